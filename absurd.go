@@ -13,6 +13,7 @@ type MysteryStats struct {
 	CosmicAlignment    int       `json:"cosmic_alignment"`    // Based on incomprehensible time math
 	VibeCheckScore     int       `json:"vibe_check_score"`    // Randomly fails
 	LastVibeCheck      time.Time `json:"last_vibe_check"`
+	VibeTrend          int       `json:"vibe_trend"`          // -100 to 100, recent vibe checks; scales happiness decay
 	EnlightenmentLevel int       `json:"enlightenment_level"` // Achieved through specific neglect
 	VoidGazeCount      int       `json:"void_gaze_count"`     // Times pet stared into the void
 }
@@ -26,15 +27,21 @@ type Fear struct {
 
 // AbsurdState holds all the existentially questionable pet state
 type AbsurdState struct {
-	MysteryStats       MysteryStats `json:"mystery_stats"`
-	Fears              []Fear       `json:"fears"`
-	ThoughtsHad        int          `json:"thoughts_had"`
-	IsStaringIntoVoid  bool         `json:"is_staring_into_void"`
-	HasAchievedClarity bool         `json:"has_achieved_clarity"`
-	KonamiProgress     int          `json:"-"` // Not saved, resets each session
-	DebugModeActive    bool         `json:"debug_mode_active"`
-	PetCount           int          `json:"pet_count"` // For "Pet the Pet" mini-game
-	LastProphecy       string       `json:"last_prophecy"`
+	MysteryStats       MysteryStats         `json:"mystery_stats"`
+	Fears              []Fear               `json:"fears"`
+	ThoughtsHad        int                  `json:"thoughts_had"`
+	IsStaringIntoVoid  bool                 `json:"is_staring_into_void"`
+	HasAchievedClarity bool                 `json:"has_achieved_clarity"`
+	KonamiProgress     int                  `json:"-"` // Not saved, resets each session
+	DebugModeActive    bool                 `json:"debug_mode_active"`
+	PetCount           int                  `json:"pet_count"` // For "Pet the Pet" mini-game
+	LastProphecy       string               `json:"last_prophecy"`
+	OverheardBranches  []string             `json:"overheard_branches,omitempty"` // Git branches it shouldn't know about
+	FearTriggers       map[string]int       `json:"fear_triggers,omitempty"`      // How often each fear was triggered
+	Traumas            map[string]int       `json:"traumas,omitempty"`            // Network events lived through, by kind
+	TraumaSeen         time.Time            `json:"trauma_seen,omitempty"`        // The latest network event taken in
+	DarkThoughtAt      time.Time            `json:"dark_thought_at,omitempty"`    // The last heavy thought, until the pet is comforted
+	thoughtsSaid       map[string]sentiment // This session's thoughts and how they felt, so they aren't repeated
 }
 
 // Philosophical thoughts the pet might have
@@ -75,6 +82,14 @@ var prophecies = []string{
 	"The void is not empty. It is full of deprecated code.",
 }
 
+// Thoughts about git branches the pet overheard through installed hooks
+var branchThoughts = []string{
+	"Who is %s? Why do they keep getting commits?",
+	"I dreamed of a branch called %s. I shouldn't know that.",
+	"%s will be merged. I have foreseen it.",
+	"Something happened on %s. I felt it in my save file.",
+}
+
 // Debug mode revelations for pets named "DEBUG"
 var debugRevelations = []string{
 	"I know you can see my internal state. I see yours too.",
@@ -130,14 +145,21 @@ func NewAbsurdState() *AbsurdState {
 // generateRandomFears assigns 1-3 random fears to the pet
 func generateRandomFears(randomSource *rand.Rand) []Fear {
 	numberOfFears := 1 + randomSource.Intn(3)
+	names := make(map[string]bool)
+	for _, fear := range activeContent.fears.items {
+		names[fear.Name] = true
+	}
+	if numberOfFears > len(names) {
+		numberOfFears = len(names) // A pack may have replaced the pool with just one or two
+	}
 	fears := make([]Fear, 0, numberOfFears)
 
-	usedIndices := make(map[int]bool)
+	usedNames := make(map[string]bool)
 	for len(fears) < numberOfFears {
-		index := randomSource.Intn(len(possibleFears))
-		if !usedIndices[index] {
-			usedIndices[index] = true
-			fears = append(fears, possibleFears[index])
+		fear := activeContent.fears.pick(randomSource)
+		if !usedNames[fear.Name] {
+			usedNames[fear.Name] = true
+			fears = append(fears, fear)
 		}
 	}
 
@@ -180,23 +202,58 @@ func (a *AbsurdState) UpdateMysteryStats() {
 
 // GetRandomThought returns a philosophical musing or prophecy
 func (a *AbsurdState) GetRandomThought(petName string) string {
-	randomSource := rand.New(rand.NewSource(time.Now().UnixNano()))
+	return a.ComposeThought(thoughtContext{Name: petName}, rand.New(rand.NewSource(time.Now().UnixNano())))
+}
+
+// ComposeThought returns a musing, a prophecy, or a thought put together
+// from what's on the pet's mind, trying not to repeat itself this session
+func (a *AbsurdState) ComposeThought(c thoughtContext, randomSource *rand.Rand) string {
 	a.ThoughtsHad++
 
 	// Debug mode gets special thoughts
-	if a.DebugModeActive || strings.ToUpper(petName) == "DEBUG" {
+	if a.DebugModeActive || strings.ToUpper(c.Name) == "DEBUG" {
 		a.DebugModeActive = true
 		return debugRevelations[randomSource.Intn(len(debugRevelations))]
 	}
 
+	var thought string
+	var feel sentiment
+	for i := 0; i < thoughtAttempts; i++ {
+		thought, feel = a.pickThought(c, randomSource)
+		if _, said := a.thoughtsSaid[thought]; !said {
+			break
+		}
+	}
+	if a.thoughtsSaid == nil {
+		a.thoughtsSaid = make(map[string]sentiment)
+	}
+	a.thoughtsSaid[thought] = feel
+	return thought
+}
+
+// pickThought picks one thought and how it feels, without minding whether
+// it's been said
+func (a *AbsurdState) pickThought(c thoughtContext, randomSource *rand.Rand) (string, sentiment) {
+	// 15% chance of mentioning a branch it shouldn't know about
+	if len(a.OverheardBranches) > 0 && randomSource.Float32() < 0.15 {
+		branch := a.OverheardBranches[randomSource.Intn(len(a.OverheardBranches))]
+		return fmt.Sprintf(branchThoughts[randomSource.Intn(len(branchThoughts))], branch), neutral
+	}
+
 	// 20% chance of prophecy
 	if randomSource.Float32() < 0.2 {
-		prophecy := prophecies[randomSource.Intn(len(prophecies))]
+		prophecy := activeContent.prophecies.pick(randomSource)
 		a.LastProphecy = prophecy
-		return prophecy
+		return prophecy, activeContent.sentiments[prophecy]
 	}
 
-	return philosophicalThoughts[randomSource.Intn(len(philosophicalThoughts))]
+	if c.composable() && randomSource.Float64() < composedChance {
+		if thought, feel := composeThought(c, randomSource); thought != "" {
+			return thought, feel
+		}
+	}
+	thought := activeContent.thoughts.pick(randomSource)
+	return thought, activeContent.sentiments[thought]
 }
 
 // CheckFearTrigger checks if input triggers any of the pet's fears
@@ -223,13 +280,40 @@ func (a *AbsurdState) CheckFearTrigger(input string) *Fear {
 	return nil
 }
 
-// PerformVibeCheck performs a vibe check with random chance of failure
-func (a *AbsurdState) PerformVibeCheck() (bool, string) {
-	randomSource := rand.New(rand.NewSource(time.Now().UnixNano()))
+// AcquireFear adds a new fear unless the pet already has it
+func (a *AbsurdState) AcquireFear(fear Fear) bool {
+	for _, existing := range a.Fears {
+		if existing.Name == fear.Name {
+			return false
+		}
+	}
+	a.Fears = append(a.Fears, fear)
+	return true
+}
+
+// OverhearBranch remembers a git branch name (keeping only the most recent few)
+func (a *AbsurdState) OverhearBranch(branch string) {
+	for i, known := range a.OverheardBranches {
+		if known == branch {
+			a.OverheardBranches = append(a.OverheardBranches[:i], a.OverheardBranches[i+1:]...)
+			break
+		}
+	}
+	a.OverheardBranches = append(a.OverheardBranches, branch)
+	if len(a.OverheardBranches) > 10 {
+		a.OverheardBranches = a.OverheardBranches[len(a.OverheardBranches)-10:]
+	}
+}
+
+// PerformVibeCheck scores a vibe check from your timing (0 to 1) and the
+// cosmic alignment, and folds the result into the vibe trend
+func (a *AbsurdState) PerformVibeCheck(timing float64) (bool, string) {
 	a.MysteryStats.LastVibeCheck = time.Now()
+	a.MysteryStats.CosmicAlignment = calculateCosmicAlignment()
+	score := vibeScore(timing, a.MysteryStats.CosmicAlignment)
+	a.MysteryStats.VibeTrend = clamp((a.MysteryStats.VibeTrend*3+(score-vibePass)*2)/4, -100, 100)
 
-	// Vibe check has 30% chance of random failure
-	if randomSource.Float32() < 0.3 {
+	if score < vibePass {
 		a.MysteryStats.VibeCheckScore -= 20
 		if a.MysteryStats.VibeCheckScore < 0 {
 			a.MysteryStats.VibeCheckScore = 0
@@ -241,6 +325,9 @@ func (a *AbsurdState) PerformVibeCheck() (bool, string) {
 	if a.MysteryStats.VibeCheckScore > 100 {
 		a.MysteryStats.VibeCheckScore = 100
 	}
+	if score >= 90 {
+		return true, "Immaculate vibes. The universe nods once and looks away."
+	}
 	return true, "Vibe check passed. Meaning uncertain."
 }
 
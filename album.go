@@ -0,0 +1,207 @@
+package main
+
+import (
+	"fmt"
+	"html/template"
+	"os"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Photo is a moment worth keeping: the pet as it looked, and a caption
+type Photo struct {
+	Moment  string    `json:"moment"` // Which moment, so each is taken once
+	Caption string    `json:"caption"`
+	Frame   string    `json:"frame"` // Plain art, no color
+	Taken   time.Time `json:"taken"`
+}
+
+// AlbumState is the photo album kept in the save
+type AlbumState struct {
+	Photos []Photo   `json:"photos,omitempty"`
+	Missed []string  `json:"missed,omitempty"` // Moments from before the pet had an album
+	Opened time.Time `json:"opened"`
+}
+
+// has reports whether a moment is already in the album, or was missed
+func (a *AlbumState) has(moment string) bool {
+	if slices.Contains(a.Missed, moment) {
+		return true
+	}
+	for _, photo := range a.Photos {
+		if photo.Moment == moment {
+			return true
+		}
+	}
+	return false
+}
+
+// albumMoment is a moment the camera watches for
+type albumMoment struct {
+	ID      string
+	happens func(p *Pet) (caption string, ok bool)
+	frame   func(a *artLibrary, p *Pet) string // nil or "" takes the pet as it looks now
+}
+
+// reachedStage is a moment for growing into a stage
+func reachedStage(stage LifeStage, caption string) func(p *Pet) (string, bool) {
+	return func(p *Pet) (string, bool) {
+		return fmt.Sprintf(caption, p.Name), p.Stage >= stage && p.Stage != Dead
+	}
+}
+
+// albumMoments are the moments worth a photo, in the order they tend to
+// happen, then the rare ones
+var albumMoments = append([]albumMoment{
+	{ID: "hatch", happens: reachedStage(Baby, "%s hatches")},
+	{ID: "child", happens: reachedStage(Child, "%s, all grown up (a bit)")},
+	{ID: "teen", happens: reachedStage(Teen, "%s, a teenager, refuses to smile")},
+	{ID: "adult", happens: reachedStage(Adult, "%s, fully grown")},
+	{ID: "first_friend", happens: func(p *Pet) (string, bool) {
+		if petNetwork == nil {
+			return "", false
+		}
+		names := petNetwork.GetFriendNames()
+		if len(names) == 0 {
+			return "", false
+		}
+		return fmt.Sprintf("%s meets %s, its first friend", p.Name, names[0]), true
+	}},
+	{ID: "enlightenment", happens: func(p *Pet) (string, bool) {
+		return fmt.Sprintf("%s, enlightened. Nothing changed", p.Name), p.Absurd != nil && p.Absurd.MysteryStats.EnlightenmentLevel > 0
+	}},
+}, rareMoments()...)
+
+// takePhotos puts any moment that just happened in the album. A pet from
+// before there were albums gets one now, with whatever already happened
+// marked missed, since nobody was holding the camera.
+func (ui *uiConfig) takePhotos(pet *Pet, now time.Time) []string {
+	first := pet.Album == nil
+	if first {
+		pet.Album = &AlbumState{Opened: now}
+	}
+
+	var reactions []string
+	for _, moment := range albumMoments {
+		if pet.Album.has(moment.ID) {
+			continue
+		}
+		caption, ok := moment.happens(pet)
+		if !ok {
+			continue
+		}
+		if first {
+			pet.Album.Missed = append(pet.Album.Missed, moment.ID)
+			continue
+		}
+		frame := ""
+		if moment.frame != nil {
+			frame = moment.frame(ui.art(), pet)
+		}
+		if frames := ui.art().StageFrames(pet.Stage, artMood(pet)); frame == "" && len(frames) > 0 {
+			frame = frames[0]
+		}
+		pet.Album.Photos = append(pet.Album.Photos, Photo{Moment: moment.ID, Caption: caption, Frame: frame, Taken: now})
+		reactions = append(reactions, fmt.Sprintf("📸 Into the album: \"%s\"", caption))
+	}
+	return reactions
+}
+
+// renderPhoto frames a photo like a print, with the caption underneath
+func renderPhoto(photo Photo) string {
+	lines := strings.Split(strings.TrimRight(photo.Frame, "\n"), "\n")
+	width := displayWidth(photo.Caption)
+	for _, line := range lines {
+		width = max(width, displayWidth(line))
+	}
+
+	var b strings.Builder
+	b.WriteString("┌" + strings.Repeat("─", width+2) + "┐\n")
+	for _, line := range lines {
+		b.WriteString("│ " + padWidth(line, width) + " │\n")
+	}
+	b.WriteString("│ " + strings.Repeat(" ", width) + " │\n")
+	b.WriteString("│ " + padWidth(photo.Caption, width) + " │\n")
+	b.WriteString("└" + strings.Repeat("─", width+2) + "┘\n")
+	b.WriteString(photo.Taken.Format("Mon Jan 2 2006, 3:04pm"))
+	return b.String()
+}
+
+// renderAlbum lists the photos, oldest first
+func (p *Pet) renderAlbum() string {
+	if p.Album == nil || len(p.Album.Photos) == 0 {
+		return fmt.Sprintf("📸 The album is empty. Big moments in %s's life are photographed as they happen.", p.Name)
+	}
+	var b strings.Builder
+	b.WriteString(fmt.Sprintf("📸 %s'S ALBUM\n\n", strings.ToUpper(p.Name)))
+	for i, photo := range p.Album.Photos {
+		b.WriteString(fmt.Sprintf("  %2d. %s  %s\n", i+1, photo.Taken.Format("Jan 2"), photo.Caption))
+	}
+	b.WriteString(fmt.Sprintf("\n%d of %d moments. Look closer with: album <number>. Save a copy with: album export [html]", len(p.Album.Photos), len(albumMoments)))
+	return b.String()
+}
+
+// albumHTML is the exported HTML version of the album
+var albumHTML = template.Must(template.New("album").Parse(`<!DOCTYPE html>
+<html lang="en">
+<head><meta charset="utf-8">{{.Comment}}<title>Photo album — {{.Name}}</title>
+<style>body{font-family:monospace;max-width:40em;margin:2em auto}figure{border:1px solid #999;padding:1em;margin:2em 0}pre{margin:0}</style></head>
+<body>
+<h1>📸 Photo album — {{.Name}}</h1>
+{{range .Photos}}<figure><pre>{{.Frame}}</pre><figcaption>{{.Caption}} — {{.Taken.Format "Mon Jan 2 2006"}}</figcaption></figure>
+{{end}}</body>
+</html>
+`))
+
+// exportAlbum writes the album next to the save file and returns the paths
+// written
+func exportAlbum(p *Pet, withHTML bool) ([]string, error) {
+	base := "tamagotchi_album_" + strings.ToLower(strings.Join(strings.Fields(p.Name), "_"))
+	prints := make([]string, len(p.Album.Photos))
+	for i, photo := range p.Album.Photos {
+		prints[i] = renderPhoto(photo)
+	}
+	files := map[string]string{base + ".txt": strings.Join(prints, "\n\n") + "\n"}
+	if withHTML {
+		var b strings.Builder
+		if err := albumHTML.Execute(&b, map[string]interface{}{"Name": p.Name, "Photos": p.Album.Photos, "Comment": template.HTML(argAlbumComment(p))}); err != nil {
+			return nil, fmt.Errorf("failed to render HTML album: %w", err)
+		}
+		files[base+".html"] = b.String()
+	}
+
+	paths := make([]string, 0, len(files))
+	for path, content := range files {
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			return nil, fmt.Errorf("failed to write %s: %w", path, err)
+		}
+		paths = append(paths, path)
+	}
+	sort.Strings(paths)
+	return paths, nil
+}
+
+// runAlbumCommand handles `album [<number>|export [html]]`
+func runAlbumCommand(pet *Pet, args string) string {
+	fields := strings.Fields(args)
+	if len(fields) == 0 || pet.Album == nil || len(pet.Album.Photos) == 0 {
+		return pet.renderAlbum()
+	}
+	if n, err := strconv.Atoi(fields[0]); err == nil && len(fields) == 1 {
+		if n < 1 || n > len(pet.Album.Photos) {
+			return fmt.Sprintf("❓ There are %d photos in the album.", len(pet.Album.Photos))
+		}
+		return renderPhoto(pet.Album.Photos[n-1])
+	}
+	if fields[0] != "export" {
+		return "❓ Usage: album [<number>|export [html]]"
+	}
+	paths, err := exportAlbum(pet, len(fields) > 1 && fields[1] == "html")
+	if err != nil {
+		return "❌ " + err.Error()
+	}
+	return "📤 Album exported to " + strings.Join(paths, " and ")
+}
@@ -0,0 +1,110 @@
+package main
+
+import (
+	"os"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestTakePhotos(t *testing.T) {
+	ui := newUIConfig()
+	now := time.Date(2026, 10, 16, 12, 0, 0, 0, time.Local)
+	pet := NewPet("Snap")
+
+	if got := ui.takePhotos(pet, now); len(got) != 0 {
+		t.Errorf("Expected no photos of an egg, got %v", got)
+	}
+
+	pet.Stage = Child
+	got := ui.takePhotos(pet, now)
+	if len(got) != 2 || !strings.Contains(got[0], "Snap hatches") || !strings.Contains(got[1], "all grown up") {
+		t.Errorf("Expected the hatch and child photos, got %v", got)
+	}
+	if len(pet.Album.Photos) != 2 || pet.Album.Photos[1].Frame == "" {
+		t.Errorf("Expected two framed photos in the album, got %+v", pet.Album.Photos)
+	}
+	if again := ui.takePhotos(pet, now); len(again) != 0 {
+		t.Errorf("Expected each moment to be taken once, got %v", again)
+	}
+
+	pet.RareEvents = map[string]time.Time{"the_look": now}
+	ui.takePhotos(pet, now)
+	look := pet.Album.Photos[len(pet.Album.Photos)-1]
+	if look.Moment != "the_look" {
+		t.Fatalf("Expected a photo of The Look, got %+v", look)
+	}
+	if art := ui.art().Special("the_look"); len(art) > 0 && look.Frame != art[0] {
+		t.Errorf("Expected The Look's own art, got %q", look.Frame)
+	}
+}
+
+func TestTakePhotosLegacySave(t *testing.T) {
+	ui := newUIConfig()
+	now := time.Date(2026, 10, 16, 12, 0, 0, 0, time.Local)
+	pet := NewPet("Old")
+	pet.Stage = Teen
+	pet.Album = nil
+
+	if got := ui.takePhotos(pet, now); len(got) != 0 {
+		t.Errorf("Expected no photos of moments nobody saw, got %v", got)
+	}
+	if len(pet.Album.Photos) != 0 || len(pet.Album.Missed) != 3 {
+		t.Errorf("Expected hatch, child and teen marked missed, got %+v", pet.Album)
+	}
+
+	pet.Stage = Adult
+	if got := ui.takePhotos(pet, now); len(got) != 1 || !strings.Contains(got[0], "fully grown") {
+		t.Errorf("Expected the next moment to be photographed, got %v", got)
+	}
+}
+
+func TestRenderPhoto(t *testing.T) {
+	photo := Photo{Caption: "Tama hatches", Frame: " (o)\n/___\\", Taken: time.Date(2026, 10, 16, 15, 4, 0, 0, time.Local)}
+	got := renderPhoto(photo)
+
+	for _, want := range []string{"│  (o)         │", "│ Tama hatches │", "Fri Oct 16 2026, 3:04pm"} {
+		if !strings.Contains(got, want) {
+			t.Errorf("Expected %q in the print, got\n%s", want, got)
+		}
+	}
+}
+
+func TestRunAlbumCommand(t *testing.T) {
+	t.Chdir(t.TempDir())
+
+	pet := NewPet("Tama Two")
+	if got := runAlbumCommand(pet, ""); !strings.Contains(got, "album is empty") {
+		t.Errorf("Expected an empty album, got %s", got)
+	}
+
+	taken := time.Date(2026, 10, 16, 12, 0, 0, 0, time.Local)
+	pet.Album.Photos = []Photo{
+		{Moment: "hatch", Caption: "Tama Two hatches", Frame: "(o)", Taken: taken},
+		{Moment: "child", Caption: "<Tama Two>, all grown up (a bit)", Frame: "(O)", Taken: taken},
+	}
+
+	tests := []struct {
+		args string
+		want string
+	}{
+		{"", "2 of 10 moments"},
+		{"2", "all grown up"},
+		{"9", "There are 2 photos"},
+		{"bogus", "Usage"},
+		{"export", "tamagotchi_album_tama_two.txt"},
+		{"export html", "tamagotchi_album_tama_two.html and tamagotchi_album_tama_two.txt"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.args, func(t *testing.T) {
+			if got := runAlbumCommand(pet, tt.args); !strings.Contains(got, tt.want) {
+				t.Errorf("Expected %q, got %s", tt.want, got)
+			}
+		})
+	}
+
+	page, _ := os.ReadFile("tamagotchi_album_tama_two.html")
+	if !strings.Contains(string(page), "&lt;Tama Two&gt;") {
+		t.Error("Expected captions to be escaped in HTML")
+	}
+}
@@ -0,0 +1,267 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os/exec"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	// alertEscalateAfter is how long a critical state goes unanswered before
+	// the pet tries something louder
+	alertEscalateAfter = 10 * time.Minute
+
+	// alertWebhookTimeout bounds a single webhook post
+	alertWebhookTimeout = 10 * time.Second
+)
+
+// alertChannel is one step on the escalation ladder
+type alertChannel struct {
+	Name     string
+	Cooldown time.Duration // Between two alerts on this channel
+	Loud     bool          // Held back during quiet hours
+}
+
+// alertChannels are tried in order; each escalation adds the next one
+var alertChannels = []alertChannel{
+	{Name: "bell", Cooldown: 5 * time.Minute, Loud: true},
+	{Name: "banner"},
+	{Name: "desktop", Cooldown: 15 * time.Minute, Loud: true},
+	{Name: "webhook", Cooldown: time.Hour, Loud: true},
+}
+
+// AlertState is the current critical episode, if any, and the grudge
+type AlertState struct {
+	Ignored int                  `json:"ignored,omitempty"` // Escalations ever needed; the pet keeps count
+	Reason  string               `json:"reason,omitempty"`  // What's wrong; empty when nothing is
+	Since   time.Time            `json:"since,omitempty"`   // When the episode began
+	Level   int                  `json:"level,omitempty"`   // Index into alertChannels
+	Sent    map[string]time.Time `json:"sent,omitempty"`    // Last alert on each channel
+}
+
+// criticalReason is what's badly wrong with the pet, or "" if nothing is
+func (p *Pet) criticalReason(now time.Time) string {
+	if p.Stage == Egg || p.Stage == Dead || p.Paused || p.Boarding.IsAway(now) || p.Travel.IsAway(now) {
+		return ""
+	}
+	switch {
+	case p.Health <= 10:
+		return "is dying"
+	case p.IsSick:
+		return "is sick"
+	case p.Hunger >= 90:
+		return "is starving"
+	}
+	return ""
+}
+
+// escalate moves the episode along and returns the channels to alert on
+// now. available reports whether a channel is set up; loud channels are
+// skipped in quiet hours.
+func (a *AlertState) escalate(reason string, now time.Time, quiet bool, available func(string) bool) []string {
+	if a.Reason == "" {
+		a.Since, a.Level = now, 0
+	}
+	a.Reason = reason
+	for a.Level < len(alertChannels)-1 && now.Sub(a.Since) >= time.Duration(a.Level+1)*alertEscalateAfter {
+		a.Level++
+		a.Ignored++
+	}
+
+	var fire []string
+	for _, channel := range alertChannels[:a.Level+1] {
+		if (channel.Loud && quiet) || !available(channel.Name) {
+			continue
+		}
+		if last, ok := a.Sent[channel.Name]; ok && now.Sub(last) < channel.Cooldown {
+			continue
+		}
+		if a.Sent == nil {
+			a.Sent = make(map[string]time.Time)
+		}
+		a.Sent[channel.Name] = now
+		fire = append(fire, channel.Name)
+	}
+	return fire
+}
+
+// banner is the scene's warning once an episode has escalated that far
+func (a *AlertState) banner(name string, now time.Time) string {
+	if a == nil || a.Reason == "" || a.Level < 1 {
+		return ""
+	}
+	return fmt.Sprintf("🚨 %s %s and has been waiting %s.", name, a.Reason, formatDuration(now.Sub(a.Since).Truncate(time.Minute)))
+}
+
+// AlertRecovery ends an episode once the pet is out of danger. If it had to
+// escalate, the pet mentions it.
+func (p *Pet) AlertRecovery(now time.Time) string {
+	a := p.Alerts
+	if a == nil || a.Reason == "" || p.criticalReason(now) != "" {
+		return ""
+	}
+	escalated := a.Level > 0
+	a.Reason, a.Since, a.Level, a.Sent = "", time.Time{}, 0, nil
+	if !escalated || p.Stage == Dead {
+		return ""
+	}
+	return fmt.Sprintf("😒 %s is fine now, no thanks to the wait. That's %s it's had to raise its voice.", p.Name, timesIgnored(a.Ignored))
+}
+
+// timesIgnored spells out the grudge
+func timesIgnored(n int) string {
+	if n == 1 {
+		return "the first time"
+	}
+	return strconv.Itoa(n) + " times"
+}
+
+// alertMessage is the text sent to the desktop and the webhook
+func (p *Pet) alertMessage(now time.Time) string {
+	a := p.Alerts
+	message := fmt.Sprintf("%s %s and has been waiting %s.", p.Name, a.Reason, formatDuration(now.Sub(a.Since).Truncate(time.Minute)))
+	if a.Ignored > 1 {
+		message += fmt.Sprintf(" You've ignored it %d times now.", a.Ignored)
+	}
+	return message
+}
+
+// desktopNotifierCandidates are the notifiers tried on each platform
+var desktopNotifierCandidates = map[string][]string{
+	"darwin":  {"osascript"},
+	"windows": {"powershell"},
+	"linux":   {"notify-send"},
+}
+
+// alertPolicy is how the pet can reach you beyond the terminal
+type alertPolicy struct {
+	quietFrom, quietTo int    // Quiet hours, start inclusive; equal means none
+	notifier           string // Desktop notifier path; "" when none was found
+	notifierName       string
+	webhook            string // HTTPS URL to post alerts to; "" for none
+	client             *http.Client
+}
+
+// newAlertPolicy reads the alert settings: TAMAGOTCHI_QUIET_HOURS (e.g.
+// 22-7) and TAMAGOTCHI_ALERT_WEBHOOK. The webhook must be HTTPS.
+func newAlertPolicy(getenv func(string) string, lookPath func(string) (string, error), goos string) (*alertPolicy, error) {
+	policy := &alertPolicy{client: &http.Client{Timeout: alertWebhookTimeout}}
+
+	candidates, ok := desktopNotifierCandidates[goos]
+	if !ok {
+		candidates = desktopNotifierCandidates["linux"]
+	}
+	for _, name := range candidates {
+		if path, err := lookPath(name); err == nil {
+			policy.notifier, policy.notifierName = path, name
+			break
+		}
+	}
+
+	if raw := getenv("TAMAGOTCHI_QUIET_HOURS"); raw != "" {
+		from, to, found := strings.Cut(raw, "-")
+		start, err1 := strconv.Atoi(strings.TrimSpace(from))
+		end, err2 := strconv.Atoi(strings.TrimSpace(to))
+		if !found || err1 != nil || err2 != nil || start < 0 || start > 23 || end < 0 || end > 23 {
+			return policy, fmt.Errorf("TAMAGOTCHI_QUIET_HOURS must look like 22-7, got %q", raw)
+		}
+		policy.quietFrom, policy.quietTo = start, end
+	}
+
+	if raw := getenv("TAMAGOTCHI_ALERT_WEBHOOK"); raw != "" && !classroomMode {
+		u, err := url.Parse(raw)
+		if err != nil || u.Scheme != "https" || u.Host == "" {
+			return policy, fmt.Errorf("TAMAGOTCHI_ALERT_WEBHOOK must be an https:// URL, got %q", raw)
+		}
+		policy.webhook = raw
+	}
+	return policy, nil
+}
+
+// quiet reports whether the hour falls in quiet hours
+func (a *alertPolicy) quiet(hour int) bool {
+	if a == nil || a.quietFrom == a.quietTo {
+		return false
+	}
+	if a.quietFrom < a.quietTo {
+		return hour >= a.quietFrom && hour < a.quietTo
+	}
+	return hour >= a.quietFrom || hour < a.quietTo // Across midnight
+}
+
+// available reports whether a channel can be used
+func (a *alertPolicy) available(channel string) bool {
+	switch channel {
+	case "desktop":
+		return a != nil && a.notifier != ""
+	case "webhook":
+		return a != nil && a.webhook != ""
+	}
+	return true
+}
+
+// desktopCommand builds the notifier invocation
+func (a *alertPolicy) desktopCommand(title, message string) *exec.Cmd {
+	switch a.notifierName {
+	case "osascript":
+		script := fmt.Sprintf("display notification %s with title %s", strconv.Quote(message), strconv.Quote(title))
+		return exec.Command(a.notifier, "-e", script)
+	case "powershell":
+		quote := func(s string) string { return "'" + strings.ReplaceAll(s, "'", "''") + "'" }
+		script := "Add-Type -AssemblyName System.Windows.Forms; $n = New-Object System.Windows.Forms.NotifyIcon; " +
+			"$n.Icon = [System.Drawing.SystemIcons]::Warning; $n.Visible = $true; " +
+			"$n.ShowBalloonTip(10000, " + quote(title) + ", " + quote(message) + ", 'Warning'); Start-Sleep -Seconds 10; $n.Dispose()"
+		return exec.Command(a.notifier, "-NoProfile", "-Command", script)
+	default:
+		return exec.Command(a.notifier, "--urgency=critical", title, message)
+	}
+}
+
+// postWebhook sends an alert to the webhook as JSON
+func (a *alertPolicy) postWebhook(pet, message string) error {
+	body, err := json.Marshal(map[string]string{"pet": pet, "text": message})
+	if err != nil {
+		return fmt.Errorf("failed to encode alert: %w", err)
+	}
+	resp, err := a.client.Post(a.webhook, "application/json", bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("failed to post alert: %w", err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode >= 300 {
+		return fmt.Errorf("failed to post alert: %s", resp.Status)
+	}
+	return nil
+}
+
+// escalateAlerts checks on the pet while you're away and alerts on every
+// channel the episode has reached
+func (ui *uiConfig) escalateAlerts(pet *Pet, now time.Time) {
+	reason := pet.criticalReason(now)
+	if reason == "" {
+		return
+	}
+	if pet.Alerts == nil {
+		pet.Alerts = &AlertState{}
+	}
+	for _, channel := range pet.Alerts.escalate(reason, now, ui.alerts.quiet(now.Hour()), ui.alerts.available) {
+		switch channel {
+		case "bell":
+			ui.bellForEvent("critical")
+		case "desktop":
+			cmd := ui.alerts.desktopCommand("Tamagotchi: "+pet.Name, pet.alertMessage(now))
+			if cmd.Start() == nil {
+				go cmd.Wait()
+			}
+		case "webhook":
+			go ui.alerts.postWebhook(pet.Name, pet.alertMessage(now)) // Failures wait out the cooldown
+		}
+		// The banner is drawn with the scene
+	}
+}
@@ -0,0 +1,154 @@
+package main
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestAlertEscalation(t *testing.T) {
+	start := time.Date(2026, 10, 16, 12, 0, 0, 0, time.Local)
+	all := func(string) bool { return true }
+	a := &AlertState{}
+
+	tests := []struct {
+		at    time.Duration
+		quiet bool
+		want  []string
+	}{
+		{0, false, []string{"bell"}},
+		{time.Minute, false, nil}, // The bell is cooling down
+		{10 * time.Minute, false, []string{"bell", "banner"}},
+		{20 * time.Minute, false, []string{"bell", "banner", "desktop"}},
+		{30 * time.Minute, true, []string{"banner"}}, // Quiet hours hold the loud ones back
+		{40 * time.Minute, false, []string{"bell", "banner", "desktop", "webhook"}},
+		{3 * time.Hour, false, []string{"bell", "banner", "desktop", "webhook"}},
+	}
+	for _, tt := range tests {
+		got := a.escalate("is starving", start.Add(tt.at), tt.quiet, all)
+		if !slices.Equal(got, tt.want) {
+			t.Errorf("Expected %v at %s, got %v", tt.want, tt.at, got)
+		}
+	}
+	if a.Level != len(alertChannels)-1 || a.Ignored != 3 {
+		t.Errorf("Expected the top of the ladder after 3 escalations, got level %d, %d ignored", a.Level, a.Ignored)
+	}
+
+	noWebhook := func(channel string) bool { return channel != "webhook" }
+	b := &AlertState{}
+	if got := b.escalate("is sick", start, false, noWebhook); !slices.Equal(got, []string{"bell"}) {
+		t.Errorf("Expected just the bell, got %v", got)
+	}
+	if got := b.escalate("is sick", start.Add(time.Hour), false, noWebhook); slices.Contains(got, "webhook") {
+		t.Errorf("Expected no webhook when none is set up, got %v", got)
+	}
+}
+
+func TestAlertRecovery(t *testing.T) {
+	now := time.Date(2026, 10, 16, 12, 0, 0, 0, time.Local)
+	pet := NewPet("Grudge")
+	pet.Stage = Adult
+	pet.Hunger = 95
+	if pet.criticalReason(now) != "is starving" {
+		t.Fatalf("Expected a starving pet, got %q", pet.criticalReason(now))
+	}
+	pet.Alerts = &AlertState{}
+	pet.Alerts.escalate(pet.criticalReason(now), now, false, func(string) bool { return true })
+	pet.Alerts.escalate(pet.criticalReason(now), now.Add(25*time.Minute), false, func(string) bool { return true })
+
+	if banner := pet.Alerts.banner(pet.Name, now.Add(25*time.Minute)); !strings.Contains(banner, "Grudge is starving and has been waiting 25m") {
+		t.Errorf("Expected a banner, got %q", banner)
+	}
+	if got := pet.AlertRecovery(now); got != "" {
+		t.Errorf("Expected no recovery while still starving, got %q", got)
+	}
+
+	pet.Hunger = 10
+	if got := pet.AlertRecovery(now); !strings.Contains(got, "2 times") {
+		t.Errorf("Expected the pet to count the times, got %q", got)
+	}
+	if pet.Alerts.Reason != "" || pet.Alerts.Ignored != 2 {
+		t.Errorf("Expected the episode over and the grudge kept, got %+v", pet.Alerts)
+	}
+	if got := pet.AlertRecovery(now); got != "" {
+		t.Errorf("Expected recovery to be said once, got %q", got)
+	}
+}
+
+func TestNewAlertPolicy(t *testing.T) {
+	tests := []struct {
+		name    string
+		env     map[string]string
+		wantErr bool
+		quietAt []int
+		loudAt  []int
+	}{
+		{"nothing set", nil, false, nil, []int{0, 12, 23}},
+		{"across midnight", map[string]string{"TAMAGOTCHI_QUIET_HOURS": "22-7"}, false, []int{22, 23, 0, 6}, []int{7, 12, 21}},
+		{"an afternoon nap", map[string]string{"TAMAGOTCHI_QUIET_HOURS": "13-15"}, false, []int{13, 14}, []int{12, 15}},
+		{"nonsense hours", map[string]string{"TAMAGOTCHI_QUIET_HOURS": "late"}, true, nil, nil},
+		{"a plain HTTP webhook", map[string]string{"TAMAGOTCHI_ALERT_WEBHOOK": "http://example.com/hook"}, true, nil, nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			policy, err := newAlertPolicy(func(key string) string { return tt.env[key] }, fakeLookPath("notify-send"), "linux")
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Expected error %v, got %v", tt.wantErr, err)
+			}
+			for _, hour := range tt.quietAt {
+				if !policy.quiet(hour) {
+					t.Errorf("Expected %d:00 to be quiet", hour)
+				}
+			}
+			for _, hour := range tt.loudAt {
+				if policy.quiet(hour) {
+					t.Errorf("Expected %d:00 not to be quiet", hour)
+				}
+			}
+		})
+	}
+}
+
+func TestDesktopCommand(t *testing.T) {
+	tests := []struct {
+		goos     string
+		notifier string
+		want     string
+	}{
+		{"linux", "notify-send", "/usr/bin/notify-send --urgency=critical Tamagotchi: Tama Tama is sick"},
+		{"darwin", "osascript", `display notification "Tama is sick" with title "Tamagotchi: Tama"`},
+		{"windows", "powershell", "ShowBalloonTip(10000, 'Tamagotchi: Tama', 'Tama is sick'"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.goos, func(t *testing.T) {
+			policy, _ := newAlertPolicy(func(string) string { return "" }, fakeLookPath(tt.notifier), tt.goos)
+			if !policy.available("desktop") {
+				t.Fatalf("Expected %s to be found", tt.notifier)
+			}
+			cmd := policy.desktopCommand("Tamagotchi: Tama", "Tama is sick")
+			if got := strings.Join(cmd.Args, " "); !strings.Contains(got, tt.want) {
+				t.Errorf("Expected %q, got %q", tt.want, got)
+			}
+		})
+	}
+}
+
+func TestPostWebhook(t *testing.T) {
+	var got map[string]string
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		json.NewDecoder(r.Body).Decode(&got)
+	}))
+	defer server.Close()
+
+	policy := &alertPolicy{webhook: server.URL, client: server.Client()}
+	if err := policy.postWebhook("Tama", "Tama is dying"); err != nil {
+		t.Fatalf("Expected the post to succeed, got %v", err)
+	}
+	if got["pet"] != "Tama" || got["text"] != "Tama is dying" {
+		t.Errorf("Expected the alert in the body, got %v", got)
+	}
+}
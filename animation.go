@@ -0,0 +1,128 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"strings"
+	"time"
+)
+
+// idleFrameDuration is how long each frame of a stage's idle loop is shown
+const idleFrameDuration = 120 * time.Millisecond
+
+// Keyframe is one frame of a sequence and how long it stays up
+type Keyframe struct {
+	Frame    string
+	Duration time.Duration
+}
+
+// Sequence is a named animation, played once or looped
+type Sequence struct {
+	Name   string
+	Loop   bool
+	Frames []Keyframe
+}
+
+// SequenceSpec is how the art manifest describes a sequence
+type SequenceSpec struct {
+	Name   string         `json:"name"`
+	On     string         `json:"on,omitempty"`   // Event that plays it, e.g. fed
+	Loop   bool           `json:"loop,omitempty"` // Loop instead of stopping on the last frame
+	Frames []KeyframeSpec `json:"frames"`
+}
+
+// KeyframeSpec is a frame file and its duration in milliseconds
+type KeyframeSpec struct {
+	File string `json:"file"`
+	MS   int    `json:"ms"`
+}
+
+// loopSequence turns plain frames into a loop with equal durations
+func loopSequence(name string, frames []string, each time.Duration) *Sequence {
+	seq := &Sequence{Name: name, Loop: true}
+	for _, frame := range frames {
+		seq.Frames = append(seq.Frames, Keyframe{Frame: frame, Duration: each})
+	}
+	return seq
+}
+
+// Length is how long one pass through the sequence takes
+func (s *Sequence) Length() time.Duration {
+	var total time.Duration
+	for _, kf := range s.Frames {
+		total += kf.Duration
+	}
+	return total
+}
+
+// FrameAt returns the frame showing after elapsed time, and whether a one-shot
+// sequence has finished. A finished sequence holds its last frame.
+func (s *Sequence) FrameAt(elapsed time.Duration) (string, bool) {
+	if len(s.Frames) == 0 {
+		return "", true
+	}
+	length := s.Length()
+	if length <= 0 {
+		return s.Frames[len(s.Frames)-1].Frame, true
+	}
+	if s.Loop {
+		elapsed %= length
+	} else if elapsed >= length {
+		return s.Frames[len(s.Frames)-1].Frame, true
+	}
+	if elapsed < 0 {
+		elapsed = 0
+	}
+
+	for _, kf := range s.Frames {
+		if elapsed < kf.Duration {
+			return kf.Frame, false
+		}
+		elapsed -= kf.Duration
+	}
+	return s.Frames[len(s.Frames)-1].Frame, false
+}
+
+// playSequence draws one pass of a sequence in place: each frame is printed,
+// held for its duration, then erased by moving the cursor back up over it.
+// The last frame is left on screen.
+func playSequence(out io.Writer, seq *Sequence, prepare func(string) string, sleep func(time.Duration)) {
+	for i, kf := range seq.Frames {
+		frame := prepare(kf.Frame)
+		fmt.Fprintln(out, frame)
+		sleep(kf.Duration)
+		if i < len(seq.Frames)-1 {
+			// Up over every line we printed, then clear to the end of the screen
+			fmt.Fprintf(out, "\033[%dA\033[J", strings.Count(frame, "\n")+1)
+		}
+	}
+}
+
+// playEvent plays every sequence hooked to an event, then any particle burst
+// over the pet. Without cursor control only the final frame of a sequence is
+// shown; screen readers get nothing, since the command's message already says
+// what happened.
+func (ui *uiConfig) playEvent(out io.Writer, pet *Pet, event string) {
+	if ui.screenReader {
+		return
+	}
+	animate := !ui.reducedMotion && ui.cursorControl
+	for _, seq := range ui.art().SequencesFor(event) {
+		if !animate {
+			last, _ := seq.FrameAt(seq.Length())
+			fmt.Fprintln(out, ui.text(last))
+			continue
+		}
+		playSequence(out, seq, ui.text, time.Sleep)
+	}
+
+	layers, length := ui.burstLayers(event)
+	if len(layers) == 0 || !animate {
+		return
+	}
+	frames := ui.moodFrames(pet.Stage, artMood(pet), false)
+	if len(frames) == 0 {
+		return
+	}
+	playSequence(out, ui.particleSequence(event, frames[0], layers, length), ui.text, time.Sleep)
+}
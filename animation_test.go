@@ -0,0 +1,129 @@
+package main
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestSequenceFrameAt(t *testing.T) {
+	frames := []Keyframe{{"a", 100 * time.Millisecond}, {"b", 300 * time.Millisecond}, {"c", 100 * time.Millisecond}}
+	once := &Sequence{Name: "once", Frames: frames}
+	loop := &Sequence{Name: "loop", Loop: true, Frames: frames}
+
+	tests := []struct {
+		seq      *Sequence
+		elapsed  time.Duration
+		want     string
+		wantDone bool
+	}{
+		{once, 0, "a", false},
+		{once, 99 * time.Millisecond, "a", false},
+		{once, 100 * time.Millisecond, "b", false},
+		{once, 450 * time.Millisecond, "c", false},
+		{once, 500 * time.Millisecond, "c", true},
+		{once, time.Hour, "c", true},
+		{loop, 450 * time.Millisecond, "c", false},
+		{loop, 500 * time.Millisecond, "a", false},
+		{loop, 650 * time.Millisecond, "b", false},
+	}
+	for _, tt := range tests {
+		got, done := tt.seq.FrameAt(tt.elapsed)
+		if got != tt.want || done != tt.wantDone {
+			t.Errorf("%s.FrameAt(%s): Expected %q (done %v), got %q (done %v)", tt.seq.Name, tt.elapsed, tt.want, tt.wantDone, got, done)
+		}
+	}
+
+	if got, done := (&Sequence{}).FrameAt(time.Second); got != "" || !done {
+		t.Errorf("Expected an empty sequence to be done, got %q", got)
+	}
+}
+
+func TestPlaySequence(t *testing.T) {
+	seq := &Sequence{Name: "eating", Frames: []Keyframe{
+		{"(o_o)\n  🍔", 200 * time.Millisecond},
+		{"(^o^)", 300 * time.Millisecond},
+	}}
+	var out bytes.Buffer
+	var slept []time.Duration
+	playSequence(&out, seq, strings.ToUpper, func(d time.Duration) { slept = append(slept, d) })
+
+	want := "(O_O)\n  🍔\n\033[2A\033[J(^O^)\n"
+	if out.String() != want {
+		t.Errorf("Expected %q, got %q", want, out.String())
+	}
+	if len(slept) != 2 || slept[0] != 200*time.Millisecond || slept[1] != 300*time.Millisecond {
+		t.Errorf("Expected each frame held for its duration, got %v", slept)
+	}
+}
+
+func TestEmbeddedSequencesHookEvents(t *testing.T) {
+	for _, event := range []string{"hatched", "fed", "bathed", "died"} {
+		seqs := activeArt.SequencesFor(event)
+		if len(seqs) == 0 {
+			t.Errorf("Expected a sequence for %s", event)
+			continue
+		}
+		if _, done := seqs[0].FrameAt(seqs[0].Length()); !done {
+			t.Errorf("Expected the %s sequence to be one-shot", event)
+		}
+	}
+	if len(activeArt.SequencesFor("juggled")) != 0 {
+		t.Errorf("Expected no sequence for an unknown event")
+	}
+}
+
+func TestLoadArtRejectsZeroDurationKeyframes(t *testing.T) {
+	files := minimalArt()
+	manifest := strings.Replace(string(files["manifest.json"].Data), `"overlays"`,
+		`"sequences": [{"name": "blink", "on": "fed", "frames": [{"file": "frame.txt", "ms": 0}]}], "overlays"`, 1)
+	files["manifest.json"].Data = []byte(manifest)
+	if _, err := loadArt(files); err == nil || !strings.Contains(err.Error(), "positive ms") {
+		t.Errorf("Expected a zero duration to be rejected, got %v", err)
+	}
+}
+
+func TestPlayEventWithoutCursorControl(t *testing.T) {
+	ui := newUIConfig()
+	ui.cursorControl = false
+	ui.screenReader = false
+	var out bytes.Buffer
+	ui.playEvent(&out, NewPet("Tamago"), "fed")
+	last, _ := activeArt.SequencesFor("fed")[0].FrameAt(time.Hour)
+	if strings.Contains(out.String(), "\033[") || !strings.Contains(out.String(), ui.text(last)) {
+		t.Errorf("Expected only the final frame without cursor movement, got %q", out.String())
+	}
+
+	ui.screenReader = true
+	out.Reset()
+	ui.playEvent(&out, NewPet("Tamago"), "fed")
+	if out.Len() != 0 {
+		t.Errorf("Expected nothing for screen readers, got %q", out.String())
+	}
+}
+
+func TestCareCommandsEmitEvents(t *testing.T) {
+	tests := []struct {
+		command string
+		setup   func(*Pet)
+		want    string
+	}{
+		{"feed", func(p *Pet) { p.Hunger = 80 }, "fed"},
+		{"feed", func(p *Pet) { p.Hunger = 0 }, ""},
+		{"clean", func(p *Pet) { p.Cleanliness = 20 }, "bathed"},
+		{"pet", func(p *Pet) {}, "petted"},
+	}
+	for _, tt := range tests {
+		pet := NewPet("Tamago")
+		pet.Stage = Child
+		tt.setup(pet)
+		commands := newDefaultRegistry()
+		ctx := &commandContext{pet: pet, ui: newUIConfig(), commands: commands}
+		runCommand(commands.Lookup(tt.command), ctx)
+		got := strings.Join(ctx.events, ",")
+		if got != tt.want {
+			t.Errorf("%s: Expected events %q, got %q", tt.command, tt.want, got)
+		}
+	}
+}
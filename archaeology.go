@@ -0,0 +1,215 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/tamagotchi/mooc"
+)
+
+const (
+	// maxDigSize is the largest save excavate will read
+	maxDigSize = 32 << 20
+	// maxDreamsPerDig is how many fragments one save gives up
+	maxDreamsPerDig = 6
+	// maxAncestralDreams caps the dream journal; the oldest go first
+	maxAncestralDreams = 30
+	// maxFragmentWidth keeps a fragment to a line
+	maxFragmentWidth = 120
+)
+
+// AncestralDream is a fragment of another pet's save, dreamt by this one,
+// and where it was dug up
+type AncestralDream struct {
+	Text     string    `json:"text"`
+	Kind     string    `json:"kind"`   // fear, prophecy, first words, branch, last words, photo
+	From     string    `json:"from"`   // The pet the fragment belonged to
+	SourceID string    `json:"source"` // Its pet ID, so a save is only dug once
+	Born     time.Time `json:"born"`   // When the source pet hatched
+	File     string    `json:"file"`   // The file it came out of
+	DugAt    time.Time `json:"dug_at"` // When it was excavated
+	Dreamt   bool      `json:"dreamt"` // Whether the pet has dreamt it yet
+}
+
+// provenance says where a dream came from
+func (d AncestralDream) provenance() string {
+	return fmt.Sprintf("%s's %s, from %s (hatched %s), dug up %s", d.From, d.Kind, d.File, d.Born.Format("Jan 2 2006"), d.DugAt.Format("Jan 2 2006"))
+}
+
+// foreignSave is the part of a save worth digging through. Only these
+// fields are read; nothing from the file touches the living pet.
+type foreignSave struct {
+	Name       string    `json:"name"`
+	BirthTime  time.Time `json:"birth_time"`
+	FirstWords *struct {
+		Text string `json:"text"`
+	} `json:"first_words"`
+	Absurd *struct {
+		Fears             []Fear   `json:"fears"`
+		LastProphecy      string   `json:"last_prophecy"`
+		OverheardBranches []string `json:"overheard_branches"`
+	} `json:"absurd"`
+	Lineage []Ancestor `json:"lineage"`
+	Album   *struct {
+		Photos []Photo `json:"photos"`
+	} `json:"album"`
+}
+
+// readForeignSave reads a save file, or a pet bundle as `migrate` sends
+// them
+func readForeignSave(path string) (*foreignSave, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open %s: %w", path, err)
+	}
+	defer f.Close()
+	data, err := io.ReadAll(io.LimitReader(f, maxDigSize+1))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %s: %w", path, err)
+	}
+	if len(data) > maxDigSize {
+		return nil, fmt.Errorf("%s is too big to be a save", path)
+	}
+	var bundle petBundle
+	if json.Unmarshal(data, &bundle) == nil && len(bundle.Save) > 0 {
+		data = bundle.Save
+	}
+	var save foreignSave
+	if err := json.Unmarshal(data, &save); err != nil {
+		return nil, fmt.Errorf("%s isn't a pet's save: %w", path, err)
+	}
+	if save.Name == "" || save.BirthTime.IsZero() {
+		return nil, fmt.Errorf("%s isn't a pet's save", path)
+	}
+	return &save, nil
+}
+
+// fragment cleans a piece of foreign text for the journal
+func fragment(s string) string {
+	return truncateWidth(strings.TrimSpace(plainText(s)), maxFragmentWidth)
+}
+
+// excavateDreams turns a save's thoughts, fears and words into dreams,
+// at most maxDreamsPerDig of them
+func excavateDreams(save *foreignSave, file string, now time.Time) []AncestralDream {
+	name := fragment(save.Name)
+	var dreams []AncestralDream
+	add := func(kind, text string) {
+		if len(dreams) < maxDreamsPerDig {
+			dreams = append(dreams, AncestralDream{Text: text, Kind: kind, From: name, Born: save.BirthTime, File: file, DugAt: now,
+				SourceID: mooc.GeneratePetID(save.Name, save.BirthTime)})
+		}
+	}
+	if save.Absurd != nil {
+		for _, fear := range save.Absurd.Fears {
+			add("fear", fmt.Sprintf("I dreamed I was %s, and I had %s. \"%s.\" I woke up checking for it.", name, fragment(fear.Name), strings.TrimSuffix(fragment(fear.Description), ".")))
+		}
+		if prophecy := fragment(save.Absurd.LastProphecy); prophecy != "" {
+			add("prophecy", fmt.Sprintf("In the dream, %s said: \"%s\" It sounded like a prophecy, or a warning.", name, prophecy))
+		}
+	}
+	if save.FirstWords != nil && fragment(save.FirstWords.Text) != "" {
+		add("first words", fmt.Sprintf("I dreamed someone said \"%s\" to a newborn called %s. I think it was you.", fragment(save.FirstWords.Text), name))
+	}
+	for _, ancestor := range save.Lineage {
+		if words := fragment(ancestor.LastWords); words != "" {
+			add("last words", fmt.Sprintf("A pet called %s spoke to me in a dream: \"%s\" I never knew it. %s did.", fragment(ancestor.Name), words, name))
+		}
+	}
+	if save.Absurd != nil {
+		for _, branch := range save.Absurd.OverheardBranches {
+			add("branch", fmt.Sprintf("%s once overheard a branch called \"%s\". I dreamed I was on it.", name, fragment(branch)))
+		}
+	}
+	if save.Album != nil {
+		for _, photo := range save.Album.Photos {
+			add("photo", fmt.Sprintf("I dreamed I was in a photo: \"%s\". Someone else was in my place.", fragment(photo.Caption)))
+		}
+	}
+	return dreams
+}
+
+// dugUp reports whether a save has been excavated before
+func (p *Pet) dugUp(sourceID string) bool {
+	for _, dream := range p.AncestralDreams {
+		if dream.SourceID == sourceID {
+			return true
+		}
+	}
+	return false
+}
+
+// excavate mines a save for dreams and adds them to the journal
+func (p *Pet) excavate(path string, now time.Time) (string, error) {
+	save, err := readForeignSave(path)
+	if err != nil {
+		return "", err
+	}
+	sourceID := mooc.GeneratePetID(save.Name, save.BirthTime)
+	if sourceID == p.petID() {
+		return fmt.Sprintf("⛏️ That's %s's own save. There's nothing down there it doesn't already know.", p.Name), nil
+	}
+	if p.dugUp(sourceID) {
+		return fmt.Sprintf("⛏️ %s has already been dug through. Whatever was in it, %s has dreamt.", fragment(save.Name), p.Name), nil
+	}
+	dreams := excavateDreams(save, filepath.Base(path), now)
+	if len(dreams) == 0 {
+		return fmt.Sprintf("⛏️ %s's save is bare rock. No thoughts, no fears, nothing to dream.", fragment(save.Name)), nil
+	}
+	p.AncestralDreams = append(p.AncestralDreams, dreams...)
+	if len(p.AncestralDreams) > maxAncestralDreams {
+		p.AncestralDreams = p.AncestralDreams[len(p.AncestralDreams)-maxAncestralDreams:]
+	}
+	return fmt.Sprintf("⛏️ You dig through %s's save and turn up %d fragments. %s will dream them, one at a time.", fragment(save.Name), len(dreams), p.Name), nil
+}
+
+// ancestralDream is the next dug-up fragment the pet hasn't dreamt, or ""
+func (p *Pet) ancestralDream() string {
+	for i := range p.AncestralDreams {
+		if dream := &p.AncestralDreams[i]; !dream.Dreamt {
+			dream.Dreamt = true
+			return fmt.Sprintf("🌙 %s dreams of another life: %s", p.Name, dream.Text)
+		}
+	}
+	return ""
+}
+
+// renderDreamJournal lists the dreams dreamt so far, newest first, with
+// where each came from
+func renderDreamJournal(p *Pet) string {
+	var b strings.Builder
+	b.WriteString("🌙 DREAM JOURNAL\n")
+	waiting := 0
+	for i := len(p.AncestralDreams) - 1; i >= 0; i-- {
+		dream := p.AncestralDreams[i]
+		if !dream.Dreamt {
+			waiting++
+			continue
+		}
+		fmt.Fprintf(&b, "\n  %s\n    ⛏️ %s\n", dream.Text, dream.provenance())
+	}
+	if waiting > 0 {
+		fmt.Fprintf(&b, "\n%d fragments are still waiting to be dreamt.", waiting)
+	} else if len(p.AncestralDreams) == 0 {
+		b.WriteString("\nNo ancestral dreams yet. excavate <save file> digs through an old save or a friend's pet for some.")
+	}
+	return strings.TrimRight(b.String(), "\n")
+}
+
+// runExcavateCommand handles `excavate [file]`
+func runExcavateCommand(pet *Pet, args string) string {
+	path := strings.TrimSpace(args)
+	if path == "" {
+		return renderDreamJournal(pet)
+	}
+	message, err := pet.excavate(path, time.Now())
+	if err != nil {
+		return "❌ " + err.Error()
+	}
+	return message
+}
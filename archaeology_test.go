@@ -0,0 +1,122 @@
+package main
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+// writeForeignSave writes another pet's save, wrapped in a bundle when
+// bundled is set
+func writeForeignSave(t *testing.T, pet *Pet, bundled bool) string {
+	t.Helper()
+	data, err := json.Marshal(pet)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if bundled {
+		if data, err = json.Marshal(petBundle{Save: data}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	path := filepath.Join(t.TempDir(), "old_save.json")
+	if err := os.WriteFile(path, data, 0644); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+// oldPet is a pet with something in its save worth digging up
+func oldPet() *Pet {
+	pet := NewPet("Relic")
+	pet.BirthTime = time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC)
+	pet.Absurd.Fears = []Fear{{Name: "Qphobia", Description: "Fears the letter Q", Trigger: "q"}}
+	pet.Absurd.LastProphecy = "The cache \x1b[31mwill\x1b[0m remember."
+	pet.FirstWords = &FirstWords{Text: "hello little one"}
+	pet.Lineage = []Ancestor{{Name: "Fossil", LastWords: "Feed the next one."}}
+	return pet
+}
+
+func TestExcavate(t *testing.T) {
+	tests := []struct {
+		name    string
+		bundled bool
+	}{
+		{"save", false},
+		{"bundle", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := writeForeignSave(t, oldPet(), tt.bundled)
+			pet := NewPet("Mochi")
+			got := runExcavateCommand(pet, path)
+			if !strings.Contains(got, "4 fragments") || len(pet.AncestralDreams) != 4 {
+				t.Fatalf("Expected 4 fragments dug up, got %q", got)
+			}
+			for _, dream := range pet.AncestralDreams {
+				if dream.From != "Relic" || dream.File != "old_save.json" || dream.Dreamt {
+					t.Errorf("Expected an undreamt dream from Relic with its file, got %+v", dream)
+				}
+				if strings.Contains(dream.Text, "\x1b") {
+					t.Errorf("Expected control characters stripped, got %q", dream.Text)
+				}
+			}
+			if again := runExcavateCommand(pet, path); !strings.Contains(again, "already been dug") || len(pet.AncestralDreams) != 4 {
+				t.Errorf("Expected a save dug only once, got %q", again)
+			}
+		})
+	}
+}
+
+func TestExcavateRefusals(t *testing.T) {
+	pet := NewPet("Mochi")
+	if got := runExcavateCommand(pet, writeForeignSave(t, pet, false)); !strings.Contains(got, "own save") {
+		t.Errorf("Expected the pet's own save refused, got %q", got)
+	}
+	if got := runExcavateCommand(pet, filepath.Join(t.TempDir(), "missing.json")); !strings.HasPrefix(got, "❌") {
+		t.Errorf("Expected a missing file reported, got %q", got)
+	}
+	junk := filepath.Join(t.TempDir(), "junk.json")
+	os.WriteFile(junk, []byte(`{"weather": "rain"}`), 0644)
+	if got := runExcavateCommand(pet, junk); !strings.Contains(got, "isn't a pet's save") {
+		t.Errorf("Expected a file that isn't a save refused, got %q", got)
+	}
+	if len(pet.AncestralDreams) != 0 {
+		t.Errorf("Expected nothing dug up, got %v", pet.AncestralDreams)
+	}
+}
+
+func TestAncestralDreamsSurface(t *testing.T) {
+	pet := NewPet("Mochi")
+	runExcavateCommand(pet, writeForeignSave(t, oldPet(), false))
+	first := pet.ancestralDream()
+	if !strings.HasPrefix(first, "🌙 Mochi dreams of another life") || !strings.Contains(first, "Qphobia") {
+		t.Errorf("Expected the oldest fragment dreamt first, got %q", first)
+	}
+
+	journal := renderDreamJournal(pet)
+	for _, want := range []string{"Qphobia", "Relic's fear, from old_save.json (hatched Mar 1 2024)", "3 fragments are still waiting"} {
+		if !strings.Contains(journal, want) {
+			t.Errorf("Expected %q in the journal, got:\n%s", want, journal)
+		}
+	}
+	for pet.ancestralDream() != "" {
+	}
+	if got := pet.ancestralDream(); got != "" {
+		t.Errorf("Expected each dream dreamt once, got %q", got)
+	}
+}
+
+func TestExcavateCapsJournal(t *testing.T) {
+	pet := NewPet("Mochi")
+	for i := 0; i < maxAncestralDreams; i++ {
+		pet.AncestralDreams = append(pet.AncestralDreams, AncestralDream{Text: "old", SourceID: "x"})
+	}
+	runExcavateCommand(pet, writeForeignSave(t, oldPet(), false))
+	if len(pet.AncestralDreams) != maxAncestralDreams || pet.AncestralDreams[len(pet.AncestralDreams)-1].From != "Relic" {
+		t.Errorf("Expected the journal capped with the newest kept, got %d dreams", len(pet.AncestralDreams))
+	}
+}
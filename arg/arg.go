@@ -0,0 +1,172 @@
+// Package arg is the alternate reality game: puzzles hidden in files the
+// game leaves lying around, and the answers that solve them
+package arg
+
+import (
+	"crypto/sha256"
+	"encoding/binary"
+	"errors"
+	"slices"
+	"strings"
+)
+
+// Puzzle IDs, in the order they're planted
+const (
+	PuzzleCache  = "cache"  // Base64, layer on layer, in a dotfile beside the save
+	PuzzleAlbum  = "album"  // A camera comment in the exported HTML album
+	PuzzleDiary  = "diary"  // Morse in the whitespace at the ends of diary lines
+	PuzzleMaze   = "maze"   // ROT13 scratched behind a door in a rare maze
+	PuzzleTyping = "typing" // Letters left behind by perfect typing races, one at a time
+)
+
+// Puzzle is one hidden artifact and the clue that points at it
+type Puzzle struct {
+	ID   string
+	Hint string
+}
+
+// Puzzles are planted one clue at a time, in this order
+var Puzzles = []Puzzle{
+	{PuzzleCache, "Something was cached that was never meant to be drawn. Dotfiles hide in plain sight."},
+	{PuzzleAlbum, "Photographs remember more than they show. Export the album as HTML, then view the source."},
+	{PuzzleDiary, "The diary's margins are wider than they look. Mind the space at the end of each line."},
+	{PuzzleMaze, "Not every maze ends at the exit. Keep walking them until one has a door."},
+	{PuzzleTyping, "Copy its thoughts down perfectly, word for word, and sometimes a letter stays behind."},
+}
+
+// answers are the words a puzzle can hide. Each player gets their own,
+// picked from their seed, so answers can't be passed around.
+var answers = []string{
+	"LANTERN", "MARROW", "ECHOES", "THRESHOLD", "CINDER", "HOLLOW",
+	"WHISPER", "VESSEL", "MIRROR", "ORBIT", "STATIC", "LULLABY",
+}
+
+// Words lists every answer a puzzle can hide, for checking they can be
+// written in morse
+func Words() []string {
+	return slices.Clone(answers)
+}
+
+// Errors from Verify
+var (
+	ErrUnknownPuzzle = errors.New("no such puzzle")
+	ErrNotPlanted    = errors.New("that puzzle hasn't been hidden yet")
+)
+
+// State is one player's game, kept in their save
+type State struct {
+	Seed    string   `json:"seed"`
+	Planted []string `json:"planted,omitempty"`
+	Solved  []string `json:"solved,omitempty"`
+
+	// Key fragments heard from other pets, by who carried them
+	Fragments map[string]string `json:"fragments,omitempty"`
+	Chorus    string            `json:"chorus,omitempty"` // Once the fragments are put together
+
+	Letters int `json:"letters,omitempty"` // Letters of the typing answer revealed so far
+}
+
+// NewState starts a game whose answers come from seed
+func NewState(seed string) *State {
+	return &State{Seed: seed}
+}
+
+// find returns a puzzle by ID
+func find(id string) (Puzzle, bool) {
+	for _, puzzle := range Puzzles {
+		if puzzle.ID == id {
+			return puzzle, true
+		}
+	}
+	return Puzzle{}, false
+}
+
+// Answer is this player's answer to a puzzle
+func (s *State) Answer(id string) string {
+	sum := sha256.Sum256([]byte(s.Seed + "/" + id))
+	return answers[binary.BigEndian.Uint64(sum[:8])%uint64(len(answers))]
+}
+
+// IsPlanted reports whether a puzzle's artifact has been hidden
+func (s *State) IsPlanted(id string) bool {
+	return slices.Contains(s.Planted, id)
+}
+
+// IsSolved reports whether a puzzle has been solved
+func (s *State) IsSolved(id string) bool {
+	return slices.Contains(s.Solved, id)
+}
+
+// Next is the next puzzle to hide, or false once all are out
+func (s *State) Next() (Puzzle, bool) {
+	for _, puzzle := range Puzzles {
+		if !s.IsPlanted(puzzle.ID) {
+			return puzzle, true
+		}
+	}
+	return Puzzle{}, false
+}
+
+// Plant records that a puzzle's artifact is out there
+func (s *State) Plant(id string) {
+	if !s.IsPlanted(id) {
+		s.Planted = append(s.Planted, id)
+	}
+}
+
+// Verify checks a guess, ignoring case and spacing, and remembers a puzzle
+// once it's solved
+func (s *State) Verify(id, guess string) (bool, error) {
+	if _, ok := find(id); !ok {
+		return false, ErrUnknownPuzzle
+	}
+	if !s.IsPlanted(id) {
+		return false, ErrNotPlanted
+	}
+	if !strings.EqualFold(strings.Join(strings.Fields(guess), ""), s.Answer(id)) {
+		return false, nil
+	}
+	if !s.IsSolved(id) {
+		s.Solved = append(s.Solved, id)
+	}
+	return true, nil
+}
+
+// AllSolved reports whether every puzzle is solved
+func (s *State) AllSolved() bool {
+	return len(s.Solved) == len(Puzzles)
+}
+
+// RevealLetter shows one more letter of the typing answer, in an order
+// worked out from the seed, and returns the answer with the letters still
+// hidden blanked out. It reports false once every letter is showing.
+func (s *State) RevealLetter() (string, bool) {
+	answer := s.Answer(PuzzleTyping)
+	if s.Letters >= len(answer) {
+		return s.lettersShown(answer), false
+	}
+	s.Letters++
+	return s.lettersShown(answer), true
+}
+
+// lettersShown is the typing answer with the letters not yet revealed
+// blanked out
+func (s *State) lettersShown(answer string) string {
+	order := make([]int, len(answer))
+	for i := range order {
+		order[i] = i
+	}
+	sum := sha256.Sum256([]byte(s.Seed + "/letters"))
+	slices.SortStableFunc(order, func(a, b int) int {
+		return int(sum[a%len(sum)]) - int(sum[b%len(sum)])
+	})
+
+	shown := make([]string, len(answer))
+	for i := range shown {
+		shown[i] = "_"
+	}
+	for _, i := range order[:s.Letters] {
+		shown[i] = answer[i : i+1]
+	}
+	return strings.Join(shown, " ")
+}
@@ -0,0 +1,112 @@
+package arg
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestAnswersAreSeeded(t *testing.T) {
+	a, b := NewState("alpha"), NewState("alpha")
+	for _, puzzle := range Puzzles {
+		if a.Answer(puzzle.ID) != b.Answer(puzzle.ID) {
+			t.Errorf("Expected the same seed to give the same answer for %s", puzzle.ID)
+		}
+	}
+
+	differ := false
+	for _, seed := range []string{"beta", "gamma", "delta", "epsilon"} {
+		differ = differ || NewState(seed).Answer(PuzzleCache) != a.Answer(PuzzleCache)
+	}
+	if !differ {
+		t.Errorf("Expected other seeds to give other answers")
+	}
+}
+
+func TestPlanting(t *testing.T) {
+	s := NewState("alpha")
+	for _, want := range Puzzles {
+		got, ok := s.Next()
+		if !ok || got.ID != want.ID {
+			t.Fatalf("Expected %s next, got %+v", want.ID, got)
+		}
+		if got.Hint == "" {
+			t.Errorf("Expected a hint for %s", got.ID)
+		}
+		s.Plant(got.ID)
+	}
+	if _, ok := s.Next(); ok {
+		t.Errorf("Expected nothing left to plant")
+	}
+	s.Plant(PuzzleCache)
+	if len(s.Planted) != len(Puzzles) {
+		t.Errorf("Expected each puzzle planted once, got %v", s.Planted)
+	}
+}
+
+func TestVerify(t *testing.T) {
+	s := NewState("alpha")
+	s.Plant(PuzzleCache)
+	answer := s.Answer(PuzzleCache)
+
+	tests := []struct {
+		name   string
+		id     string
+		guess  string
+		solved bool
+		err    error
+	}{
+		{"unknown puzzle", "attic", answer, false, ErrUnknownPuzzle},
+		{"not planted", PuzzleDiary, s.Answer(PuzzleDiary), false, ErrNotPlanted},
+		{"wrong", PuzzleCache, "NOPE", false, nil},
+		{"right", PuzzleCache, answer, true, nil},
+		{"lower case and spaced", PuzzleCache, " " + string(answer[0]+32) + " " + answer[1:], true, nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			solved, err := s.Verify(tt.id, tt.guess)
+			if solved != tt.solved || !errors.Is(err, tt.err) {
+				t.Errorf("Expected %v, %v, got %v, %v", tt.solved, tt.err, solved, err)
+			}
+		})
+	}
+	if len(s.Solved) != 1 || !s.IsSolved(PuzzleCache) {
+		t.Errorf("Expected the cache solved once, got %v", s.Solved)
+	}
+	if s.AllSolved() {
+		t.Errorf("Expected puzzles left to solve")
+	}
+}
+
+func TestRevealLetter(t *testing.T) {
+	s := NewState("alpha")
+	answer := s.Answer(PuzzleTyping)
+
+	seen := map[int]bool{}
+	var shown string
+	for i := 1; i <= len(answer); i++ {
+		var ok bool
+		shown, ok = s.RevealLetter()
+		if !ok {
+			t.Fatalf("Expected letter %d of %d revealed", i, len(answer))
+		}
+		letters := strings.Fields(shown)
+		if len(letters) != len(answer) || strings.Count(shown, "_") != len(answer)-i {
+			t.Fatalf("Expected %d of %s showing, got %q", i, answer, shown)
+		}
+		for j, letter := range letters {
+			if letter != "_" {
+				seen[j] = true
+				if letter != answer[j:j+1] {
+					t.Errorf("Expected %s in place %d, got %s", answer[j:j+1], j, letter)
+				}
+			}
+		}
+	}
+	if strings.ReplaceAll(shown, " ", "") != answer || len(seen) != len(answer) {
+		t.Errorf("Expected the whole answer in the end, got %q", shown)
+	}
+	if _, ok := s.RevealLetter(); ok {
+		t.Errorf("Expected nothing left to reveal")
+	}
+}
@@ -0,0 +1,102 @@
+package arg
+
+import (
+	"encoding/base64"
+	"encoding/hex"
+	"fmt"
+	"strings"
+)
+
+// CacheLayers is how many times the cache payload is base64 encoded
+const CacheLayers = 3
+
+// MorseCode contains International Morse Code mappings
+var MorseCode = map[rune]string{
+	'A': ".-", 'B': "-...", 'C': "-.-.", 'D': "-..", 'E': ".",
+	'F': "..-.", 'G': "--.", 'H': "....", 'I': "..", 'J': ".---",
+	'K': "-.-", 'L': ".-..", 'M': "--", 'N': "-.", 'O': "---",
+	'P': ".--.", 'Q': "--.-", 'R': ".-.", 'S': "...", 'T': "-",
+	'U': "..-", 'V': "...-", 'W': ".--", 'X': "-..-", 'Y': "-.--",
+	'Z': "--..", '0': "-----", '1': ".----", '2': "..---", '3': "...--",
+	'4': "....-", '5': ".....", '6': "-....", '7': "--...", '8': "---..",
+	'9': "----.", ' ': " ",
+}
+
+// CacheFile is the cache artifact: what looks like a glyph cache, holding
+// the answer under layers of base64
+func CacheFile(answer string) string {
+	payload := "KEY=" + answer
+	for i := 0; i < CacheLayers; i++ {
+		payload = base64.StdEncoding.EncodeToString([]byte(payload))
+	}
+	return "# glyph cache v3 - generated, safe to delete\n" + payload + "\n"
+}
+
+// DecodeCache peels the layers off a cache file, for checking it can be solved
+func DecodeCache(file string) (string, error) {
+	lines := strings.Split(strings.TrimSpace(file), "\n")
+	payload := lines[len(lines)-1]
+	for i := 0; i < CacheLayers; i++ {
+		decoded, err := base64.StdEncoding.DecodeString(payload)
+		if err != nil {
+			return "", fmt.Errorf("layer %d: %w", i+1, err)
+		}
+		payload = string(decoded)
+	}
+	return strings.TrimPrefix(payload, "KEY="), nil
+}
+
+// AlbumComment is the album artifact: camera metadata like a photo's EXIF,
+// with the answer in hex where a comment would go
+func AlbumComment(answer string) string {
+	return fmt.Sprintf("<!-- exif: Make=TAMAGOTCHI; Model=Terminal; UserComment=%s -->", hex.EncodeToString([]byte(answer)))
+}
+
+// Whitespace is the diary artifact: one letter of the answer per line, as
+// trailing whitespace. A space is a dot and a tab is a dash.
+func Whitespace(answer string) []string {
+	var tails []string
+	for _, r := range strings.ToUpper(answer) {
+		code, ok := MorseCode[r]
+		if !ok || r == ' ' {
+			continue
+		}
+		tails = append(tails, strings.NewReplacer(".", " ", "-", "\t").Replace(code))
+	}
+	return tails
+}
+
+// DecodeWhitespace reads the letters back out of lines' trailing whitespace
+func DecodeWhitespace(lines []string) string {
+	letters := make(map[string]rune, len(MorseCode))
+	for r, code := range MorseCode {
+		letters[code] = r
+	}
+	var b strings.Builder
+	for _, line := range lines {
+		tail := line[len(strings.TrimRight(line, " \t")):]
+		if r, ok := letters[strings.NewReplacer(" ", ".", "\t", "-").Replace(tail)]; ok && tail != "" {
+			b.WriteRune(r)
+		}
+	}
+	return b.String()
+}
+
+// DoorInscription is the maze artifact: the answer scratched behind a
+// door, thirteen letters along
+func DoorInscription(answer string) string {
+	return Rot13(strings.ToUpper(answer))
+}
+
+// Rot13 turns each letter thirteen places; doing it twice gives it back
+func Rot13(s string) string {
+	return strings.Map(func(r rune) rune {
+		switch {
+		case r >= 'A' && r <= 'Z':
+			return 'A' + (r-'A'+13)%26
+		case r >= 'a' && r <= 'z':
+			return 'a' + (r-'a'+13)%26
+		}
+		return r
+	}, s)
+}
@@ -0,0 +1,63 @@
+package arg
+
+import (
+	"encoding/hex"
+	"strings"
+	"testing"
+)
+
+func TestCacheFile(t *testing.T) {
+	file := CacheFile("LANTERN")
+	if strings.Contains(file, "LANTERN") || !strings.HasPrefix(file, "# ") {
+		t.Errorf("Expected the answer hidden under an innocent header, got %q", file)
+	}
+	got, err := DecodeCache(file)
+	if err != nil || got != "LANTERN" {
+		t.Errorf("Expected LANTERN after %d layers, got %q, %v", CacheLayers, got, err)
+	}
+	if _, err := DecodeCache("# glyph cache\nnot base64!"); err == nil {
+		t.Errorf("Expected an error for a damaged cache")
+	}
+}
+
+func TestAlbumComment(t *testing.T) {
+	comment := AlbumComment("MARROW")
+	if !strings.HasPrefix(comment, "<!--") || !strings.HasSuffix(comment, "-->") {
+		t.Errorf("Expected an HTML comment, got %q", comment)
+	}
+	if !strings.Contains(comment, "UserComment="+hex.EncodeToString([]byte("MARROW"))) {
+		t.Errorf("Expected the answer in hex, got %q", comment)
+	}
+}
+
+func TestWhitespace(t *testing.T) {
+	tails := Whitespace("sos")
+	want := []string{"   ", "\t\t\t", "   "}
+	if strings.Join(tails, "|") != strings.Join(want, "|") {
+		t.Errorf("Expected %q, got %q", want, tails)
+	}
+
+	lines := []string{"Dear diary.", "", "A moth visited.", "", "", "", ""}
+	for i, tail := range Whitespace("THRESHOLD") {
+		if i >= len(lines) {
+			lines = append(lines, "")
+		}
+		lines[i] += tail
+	}
+	if got := DecodeWhitespace(lines); got != "THRESHOLD" {
+		t.Errorf("Expected THRESHOLD back, got %q", got)
+	}
+	if got := DecodeWhitespace([]string{"nothing here", "or here"}); got != "" {
+		t.Errorf("Expected nothing from plain lines, got %q", got)
+	}
+}
+
+func TestDoorInscription(t *testing.T) {
+	got := DoorInscription("lantern")
+	if got != "YNAGREA" {
+		t.Errorf("Expected YNAGREA, got %q", got)
+	}
+	if back := Rot13(got); back != "LANTERN" {
+		t.Errorf("Expected ROT13 to undo itself, got %q", back)
+	}
+}
@@ -0,0 +1,176 @@
+package arg
+
+import (
+	"crypto/sha256"
+	"encoding/binary"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"math/bits"
+	"strings"
+)
+
+// Threshold is how many pets' fragments it takes to read the chorus
+const Threshold = 3
+
+// chorusPrime is the field the fragments live in (2^61 - 1)
+const chorusPrime = 1<<61 - 1
+
+// chorusPrefix opens the message, so a wrong key is noticed
+const chorusPrefix = "MOOC:"
+
+// chorusSealed is the message only several pets together can read, sealed
+// with the chorus key
+const chorusSealed = "e4be4cba893ab32ef9a61bde3f73a1f50530fb9722432760026d4486f8c426a1580ea5243e5e4faac9a56d3dc44f7302b8cff4acf58a82136fc69ad0bf1601ce51554b15fe"
+
+// Errors from Combine
+var (
+	ErrTooFewFragments = fmt.Errorf("it takes fragments from at least %d different pets", Threshold)
+	ErrBadFragment     = errors.New("that doesn't look like a key fragment")
+	ErrWrongFragments  = errors.New("the fragments don't fit together")
+)
+
+// Fragment is one pet's share of the chorus key: a point on a curve only
+// Threshold points pin down
+type Fragment struct {
+	X, Y uint64
+}
+
+// chorusCurve gives the curve's coefficients; the first is the key
+func chorusCurve() [Threshold]uint64 {
+	var coefficients [Threshold]uint64
+	for i := range coefficients {
+		coefficients[i] = fieldHash(fmt.Sprintf("mooc/chorus/%d", i))
+	}
+	return coefficients
+}
+
+// fieldHash maps text into the field
+func fieldHash(text string) uint64 {
+	sum := sha256.Sum256([]byte(text))
+	return binary.BigEndian.Uint64(sum[:8]) % chorusPrime
+}
+
+// FragmentFor is the fragment a pet carries, the same every time for the
+// same PetID
+func FragmentFor(petID string) Fragment {
+	x := fieldHash("mooc/fragment/" + petID)
+	if x == 0 {
+		x = 1
+	}
+	var y uint64
+	curve := chorusCurve()
+	for i := len(curve) - 1; i >= 0; i-- {
+		y = fieldAdd(fieldMul(y, x), curve[i])
+	}
+	return Fragment{X: x, Y: y}
+}
+
+// String is how a fragment is shown and typed
+func (f Fragment) String() string {
+	return fmt.Sprintf("%016x-%016x", f.X, f.Y)
+}
+
+// ParseFragment reads a fragment back from its string
+func ParseFragment(text string) (Fragment, error) {
+	var f Fragment
+	if _, err := fmt.Sscanf(strings.TrimSpace(strings.ToLower(text)), "%016x-%016x", &f.X, &f.Y); err != nil || f.X == 0 || f.X >= chorusPrime || f.Y >= chorusPrime {
+		return Fragment{}, ErrBadFragment
+	}
+	return f, nil
+}
+
+// Collect keeps a fragment another pet shared, reporting whether it's new
+func (s *State) Collect(from string, f Fragment) bool {
+	if s.Fragments == nil {
+		s.Fragments = make(map[string]string)
+	}
+	if s.Fragments[from] == f.String() {
+		return false
+	}
+	s.Fragments[from] = f.String()
+	return true
+}
+
+// Combine reads the chorus from fragments held by different pets. Any
+// Threshold of them will do, so one bad fragment doesn't spoil the rest.
+func Combine(fragments []Fragment) (string, error) {
+	var points []Fragment
+	seen := make(map[uint64]bool)
+	for _, f := range fragments {
+		if !seen[f.X] {
+			seen[f.X] = true
+			points = append(points, f)
+		}
+	}
+	if len(points) < Threshold {
+		return "", ErrTooFewFragments
+	}
+
+	sealed, _ := hex.DecodeString(chorusSealed)
+	for i := 0; i < len(points); i++ {
+		for j := i + 1; j < len(points); j++ {
+			for k := j + 1; k < len(points); k++ {
+				message := string(chorusStream(chorusKey(points[i], points[j], points[k]), sealed))
+				if strings.HasPrefix(message, chorusPrefix) {
+					return strings.TrimSpace(strings.TrimPrefix(message, chorusPrefix)), nil
+				}
+			}
+		}
+	}
+	return "", ErrWrongFragments
+}
+
+// chorusKey interpolates the curve through some points back to zero,
+// where the key is
+func chorusKey(points ...Fragment) uint64 {
+	var key uint64
+	for i, p := range points {
+		term := p.Y
+		for j, q := range points {
+			if i != j {
+				term = fieldMul(term, fieldMul(q.X, fieldInverse(fieldSub(q.X, p.X))))
+			}
+		}
+		key = fieldAdd(key, term)
+	}
+	return key
+}
+
+// chorusStream seals or opens data with a key; the same call does both
+func chorusStream(key uint64, data []byte) []byte {
+	out := make([]byte, len(data))
+	var block [32]byte
+	for i := range data {
+		if i%len(block) == 0 {
+			block = sha256.Sum256(fmt.Appendf(nil, "%d/%d", key, i/len(block)))
+		}
+		out[i] = data[i] ^ block[i%len(block)]
+	}
+	return out
+}
+
+func fieldAdd(a, b uint64) uint64 {
+	return (a + b) % chorusPrime
+}
+
+func fieldSub(a, b uint64) uint64 {
+	return (a + chorusPrime - b) % chorusPrime
+}
+
+func fieldMul(a, b uint64) uint64 {
+	hi, lo := bits.Mul64(a, b)
+	return bits.Rem64(hi, lo, chorusPrime)
+}
+
+// fieldInverse uses Fermat's little theorem: a^(p-2) is 1/a
+func fieldInverse(a uint64) uint64 {
+	result, base := uint64(1), a
+	for exp := uint64(chorusPrime - 2); exp > 0; exp >>= 1 {
+		if exp&1 == 1 {
+			result = fieldMul(result, base)
+		}
+		base = fieldMul(base, base)
+	}
+	return result
+}
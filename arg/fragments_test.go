@@ -0,0 +1,71 @@
+package arg
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestFragmentForIsStable(t *testing.T) {
+	if FragmentFor("pet-a") != FragmentFor("pet-a") {
+		t.Errorf("Expected the same PetID to carry the same fragment")
+	}
+	if FragmentFor("pet-a") == FragmentFor("pet-b") {
+		t.Errorf("Expected different pets to carry different fragments")
+	}
+
+	f := FragmentFor("pet-a")
+	parsed, err := ParseFragment(" " + strings.ToUpper(f.String()) + " ")
+	if err != nil || parsed != f {
+		t.Errorf("Expected %s back, got %s, %v", f, parsed, err)
+	}
+	for _, bad := range []string{"", "hello", "0000000000000000-0000000000000001", "ffffffffffffffff-0000000000000001"} {
+		if _, err := ParseFragment(bad); !errors.Is(err, ErrBadFragment) {
+			t.Errorf("Expected %q rejected, got %v", bad, err)
+		}
+	}
+}
+
+func TestCombine(t *testing.T) {
+	a, b, c, d := FragmentFor("pet-a"), FragmentFor("pet-b"), FragmentFor("pet-c"), FragmentFor("pet-d")
+
+	tests := []struct {
+		name      string
+		fragments []Fragment
+		err       error
+	}{
+		{"alone", []Fragment{a}, ErrTooFewFragments},
+		{"two pets", []Fragment{a, b}, ErrTooFewFragments},
+		{"one pet three times", []Fragment{a, a, a}, ErrTooFewFragments},
+		{"three pets", []Fragment{a, b, c}, nil},
+		{"any three", []Fragment{d, b, a}, nil},
+		{"four pets", []Fragment{a, b, c, d}, nil},
+		{"a forgery", []Fragment{a, b, {X: c.X, Y: c.Y ^ 1}}, ErrWrongFragments},
+		{"a forgery among friends", []Fragment{a, {X: c.X, Y: c.Y ^ 1}, b, d}, nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			message, err := Combine(tt.fragments)
+			if !errors.Is(err, tt.err) {
+				t.Fatalf("Expected %v, got %q, %v", tt.err, message, err)
+			}
+			if err == nil && !strings.Contains(message, "SEVENTEEN") {
+				t.Errorf("Expected the chorus, got %q", message)
+			}
+		})
+	}
+}
+
+func TestCollect(t *testing.T) {
+	s := NewState("alpha")
+	f := FragmentFor("pet-b")
+	if !s.Collect("Bean", f) {
+		t.Errorf("Expected a new fragment kept")
+	}
+	if s.Collect("Bean", f) {
+		t.Errorf("Expected the same fragment not counted twice")
+	}
+	if s.Fragments["Bean"] != f.String() {
+		t.Errorf("Expected Bean's fragment remembered, got %v", s.Fragments)
+	}
+}
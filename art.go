@@ -0,0 +1,415 @@
+package main
+
+import (
+	"embed"
+	"encoding/json"
+	"fmt"
+	"io/fs"
+	"os"
+	"sort"
+	"strings"
+	"time"
+)
+
+// artManifestFile lists every frame set in an art directory
+const artManifestFile = "manifest.json"
+
+// artReloadInterval is how often a debug art directory is checked for edits
+const artReloadInterval = time.Second
+
+//go:embed assets/ascii
+var embeddedArt embed.FS
+
+// ArtManifest describes the frame sets in an art directory
+type ArtManifest struct {
+	MaxWidth  int            `json:"max_width"` // Widest a frame may be, in terminal columns
+	Sets      []FrameSet     `json:"sets"`
+	Sequences []SequenceSpec `json:"sequences"` // One-shot animations played on events
+	Overlays  []ArtOverlay   `json:"overlays"`
+}
+
+// FrameSet is an animation loop for a stage and mood, or a named special
+type FrameSet struct {
+	Name   string   `json:"name"`
+	Stage  string   `json:"stage,omitempty"` // LifeStage name; empty for specials looked up by name
+	Mood   string   `json:"mood,omitempty"`  // sick, serious, critical, hungry, dirty, happy; empty is the default loop
+	Zoom   string   `json:"zoom,omitempty"`  // close for the close-up camera; empty is the normal view
+	Frames []string `json:"frames"`          // Paths relative to the manifest
+}
+
+// ArtOverlay is drawn above or below the pet in a kind of weather
+type ArtOverlay struct {
+	Weather  string   `json:"weather"`  // Weather word, e.g. rain
+	Position string   `json:"position"` // above or below
+	Frames   []string `json:"frames"`
+}
+
+// artLibrary is a loaded art directory
+type artLibrary struct {
+	manifest  ArtManifest
+	frames    map[string][]string  // Frame text by set name
+	sequences map[string]*Sequence // Loaded sequences by name
+	overlays  map[string][]string  // Frame text by weather
+}
+
+// drawnStages are the stages that need a default frame set
+var drawnStages = []LifeStage{Egg, Baby, Child, Teen, Adult, Dead}
+
+// requiredSpecials are the named sets the renderer draws outside the stage loops
+var requiredSpecials = []string{"the_look", "static", "suspended"}
+
+// activeArt is the embedded art, used unless a debug directory is configured
+var activeArt = mustDefaultArt()
+
+// mustDefaultArt loads the embedded art; broken art is a build bug
+func mustDefaultArt() *artLibrary {
+	sub, err := fs.Sub(embeddedArt, "assets/ascii")
+	if err != nil {
+		panic(fmt.Sprintf("embedded art is missing: %v", err))
+	}
+	art, err := loadArt(sub)
+	if err != nil {
+		panic(fmt.Sprintf("embedded art is invalid: %v", err))
+	}
+	return art
+}
+
+// loadArt reads a manifest and every frame it names
+func loadArt(fsys fs.FS) (*artLibrary, error) {
+	data, err := fs.ReadFile(fsys, artManifestFile)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read art manifest: %w", err)
+	}
+	art := &artLibrary{
+		frames:    make(map[string][]string),
+		sequences: make(map[string]*Sequence),
+		overlays:  make(map[string][]string),
+	}
+	if err := json.Unmarshal(data, &art.manifest); err != nil {
+		return nil, fmt.Errorf("invalid art manifest: %w", err)
+	}
+
+	readFrames := func(paths []string) ([]string, error) {
+		frames := make([]string, 0, len(paths))
+		for _, p := range paths {
+			text, err := fs.ReadFile(fsys, p)
+			if err != nil {
+				return nil, fmt.Errorf("failed to read frame: %w", err)
+			}
+			frames = append(frames, strings.TrimSuffix(string(text), "\n"))
+		}
+		return frames, nil
+	}
+
+	for _, set := range art.manifest.Sets {
+		if _, dup := art.frames[set.Name]; dup {
+			return nil, fmt.Errorf("frame set %q is defined twice", set.Name)
+		}
+		frames, err := readFrames(set.Frames)
+		if err != nil {
+			return nil, fmt.Errorf("frame set %q: %w", set.Name, err)
+		}
+		art.frames[set.Name] = frames
+	}
+	for _, spec := range art.manifest.Sequences {
+		if _, dup := art.sequences[spec.Name]; dup {
+			return nil, fmt.Errorf("sequence %q is defined twice", spec.Name)
+		}
+		seq := &Sequence{Name: spec.Name, Loop: spec.Loop}
+		for _, kf := range spec.Frames {
+			frames, err := readFrames([]string{kf.File})
+			if err != nil {
+				return nil, fmt.Errorf("sequence %q: %w", spec.Name, err)
+			}
+			seq.Frames = append(seq.Frames, Keyframe{Frame: frames[0], Duration: time.Duration(kf.MS) * time.Millisecond})
+		}
+		art.sequences[spec.Name] = seq
+	}
+	for _, overlay := range art.manifest.Overlays {
+		frames, err := readFrames(overlay.Frames)
+		if err != nil {
+			return nil, fmt.Errorf("%s overlay: %w", overlay.Weather, err)
+		}
+		art.overlays[overlay.Weather] = frames
+	}
+
+	if problems := art.Validate(); len(problems) > 0 {
+		return nil, fmt.Errorf("%s", strings.Join(problems, "; "))
+	}
+	return art, nil
+}
+
+// Validate reports anything that would draw badly: empty sets, frames wider
+// than the manifest allows, unknown stages, and stages without a default loop
+func (a *artLibrary) Validate() []string {
+	var problems []string
+	if a.manifest.MaxWidth <= 0 {
+		problems = append(problems, "max_width must be positive")
+	}
+
+	checkFrames := func(owner string, paths, frames []string) {
+		if len(frames) == 0 {
+			problems = append(problems, fmt.Sprintf("%s has no frames", owner))
+		}
+		for i, frame := range frames {
+			if w := displayWidth(frame); w > a.manifest.MaxWidth {
+				problems = append(problems, fmt.Sprintf("%s: %s is %d columns wide (max %d)", owner, paths[i], w, a.manifest.MaxWidth))
+			}
+		}
+	}
+
+	stages := make(map[string]bool)
+	for _, set := range a.manifest.Sets {
+		checkFrames("set "+set.Name, set.Frames, a.frames[set.Name])
+		if set.Stage == "" {
+			continue
+		}
+		if _, ok := parseLifeStage(set.Stage); !ok {
+			problems = append(problems, fmt.Sprintf("set %s: unknown stage %q", set.Name, set.Stage))
+		}
+		if set.Zoom != "" && set.Zoom != "close" {
+			problems = append(problems, fmt.Sprintf("set %s: zoom must be close or empty", set.Name))
+		}
+		if set.Mood == "" && set.Zoom == "" {
+			stages[set.Stage] = true
+		}
+	}
+	for _, stage := range drawnStages {
+		if !stages[stage.String()] {
+			problems = append(problems, fmt.Sprintf("stage %s has no default frame set", stage))
+		}
+	}
+	for _, name := range requiredSpecials {
+		if _, ok := a.frames[name]; !ok {
+			problems = append(problems, fmt.Sprintf("missing frame set %s", name))
+		}
+	}
+
+	for _, spec := range a.manifest.Sequences {
+		seq := a.sequences[spec.Name]
+		frames := make([]string, 0, len(spec.Frames))
+		paths := make([]string, 0, len(spec.Frames))
+		for i, kf := range spec.Frames {
+			if kf.MS <= 0 {
+				problems = append(problems, fmt.Sprintf("sequence %s: %s needs a positive ms", spec.Name, kf.File))
+			}
+			frames = append(frames, seq.Frames[i].Frame)
+			paths = append(paths, kf.File)
+		}
+		checkFrames("sequence "+spec.Name, paths, frames)
+	}
+
+	for _, overlay := range a.manifest.Overlays {
+		checkFrames(overlay.Weather+" overlay", overlay.Frames, a.overlays[overlay.Weather])
+		if overlay.Position != "above" && overlay.Position != "below" {
+			problems = append(problems, fmt.Sprintf("%s overlay: position must be above or below", overlay.Weather))
+		}
+	}
+	return problems
+}
+
+// parseLifeStage maps a stage name from the manifest to a LifeStage
+func parseLifeStage(name string) (LifeStage, bool) {
+	for _, stage := range drawnStages {
+		if stage.String() == name {
+			return stage, true
+		}
+	}
+	return Egg, false
+}
+
+// moodFallbacks is the mood to draw instead when a stage has no art for one
+var moodFallbacks = map[string]string{"serious": "sick", "critical": "serious"}
+
+// StageFrames returns the loop for a stage in a mood, falling back to a
+// milder mood, then the stage's default loop, when there's no art for it
+func (a *artLibrary) StageFrames(stage LifeStage, mood string) []string {
+	if milder, ok := moodFallbacks[mood]; ok && !a.hasMood(stage, mood) {
+		return a.StageFrames(stage, milder)
+	}
+	var fallback []string
+	for _, set := range a.manifest.Sets {
+		if set.Stage != stage.String() || set.Zoom != "" {
+			continue
+		}
+		if mood != "" && set.Mood == mood {
+			return a.frames[set.Name]
+		}
+		if set.Mood == "" && fallback == nil {
+			fallback = a.frames[set.Name]
+		}
+	}
+	return fallback
+}
+
+// hasMood reports whether a stage has art for a mood
+func (a *artLibrary) hasMood(stage LifeStage, mood string) bool {
+	for _, set := range a.manifest.Sets {
+		if set.Stage == stage.String() && set.Zoom == "" && set.Mood == mood {
+			return true
+		}
+	}
+	return false
+}
+
+// CloseUp returns the close-up art for a stage, or the normal loop when
+// nobody has drawn one
+func (a *artLibrary) CloseUp(stage LifeStage) []string {
+	for _, set := range a.manifest.Sets {
+		if set.Stage == stage.String() && set.Zoom == "close" {
+			return a.frames[set.Name]
+		}
+	}
+	return a.StageFrames(stage, "")
+}
+
+// Special returns a named frame set such as the_look or static
+func (a *artLibrary) Special(name string) []string {
+	return a.frames[name]
+}
+
+// SequencesFor returns the sequences hooked to an event, in manifest order
+func (a *artLibrary) SequencesFor(event string) []*Sequence {
+	var out []*Sequence
+	for _, spec := range a.manifest.Sequences {
+		if spec.On == event {
+			out = append(out, a.sequences[spec.Name])
+		}
+	}
+	return out
+}
+
+// Overlay returns the overlay for a weather word and whether it goes above the pet
+func (a *artLibrary) Overlay(weather string) (frames []string, above bool) {
+	for _, overlay := range a.manifest.Overlays {
+		if overlay.Weather == weather {
+			return a.overlays[weather], overlay.Position == "above"
+		}
+	}
+	return nil, false
+}
+
+// artMood picks the mood art to draw for a pet, worst first
+func artMood(p *Pet) string {
+	switch {
+	case p.IsSick:
+		if stage := p.sicknessStage(); stage != "mild" {
+			return stage
+		}
+		return "sick"
+	case p.Hunger > 70:
+		return "hungry"
+	case p.Cleanliness < 30:
+		return "dirty"
+	case p.Happiness > 80:
+		return "happy"
+	}
+	return ""
+}
+
+// artReloader reloads art from a directory on disk whenever it changes, so
+// artists can edit frames and see them on the next render
+type artReloader struct {
+	dir       string
+	stamp     time.Time // Newest modification time seen
+	checkedAt time.Time
+	lastErr   error
+}
+
+// newestModTime returns the latest modification time of any file under dir
+func newestModTime(dir string) (time.Time, error) {
+	var newest time.Time
+	err := fs.WalkDir(os.DirFS(dir), ".", func(p string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		info, err := d.Info()
+		if err != nil {
+			return err
+		}
+		if info.ModTime().After(newest) {
+			newest = info.ModTime()
+		}
+		return nil
+	})
+	return newest, err
+}
+
+// Refresh returns freshly loaded art if the directory changed since the last
+// look, or nil when there's nothing new. Broken edits keep the old art.
+func (r *artReloader) Refresh(now time.Time) *artLibrary {
+	if now.Sub(r.checkedAt) < artReloadInterval {
+		return nil
+	}
+	r.checkedAt = now
+
+	stamp, err := newestModTime(r.dir)
+	if err != nil || !stamp.After(r.stamp) {
+		return nil
+	}
+	r.stamp = stamp
+
+	art, err := loadArt(os.DirFS(r.dir))
+	r.lastErr = err
+	if err != nil {
+		return nil
+	}
+	return art
+}
+
+// art returns the library to draw with, picking up edits in debug mode
+func (ui *uiConfig) art() *artLibrary {
+	if ui.artReloader != nil {
+		if fresh := ui.artReloader.Refresh(time.Now()); fresh != nil {
+			ui.artLib = fresh
+		}
+	}
+	if ui.artLib == nil {
+		return activeArt
+	}
+	return ui.artLib
+}
+
+// runArtCommand handles `tamagotchi art check [dir]`, validating an art
+// directory (the embedded one by default) before it ships
+func runArtCommand(args []string) error {
+	if len(args) == 0 || args[0] != "check" {
+		return fmt.Errorf("usage: tamagotchi art check [dir]")
+	}
+
+	var fsys fs.FS
+	source := "embedded art"
+	if len(args) > 1 {
+		fsys, source = os.DirFS(args[1]), args[1]
+	} else {
+		sub, err := fs.Sub(embeddedArt, "assets/ascii")
+		if err != nil {
+			return err
+		}
+		fsys = sub
+	}
+
+	art, err := loadArt(fsys)
+	if err != nil {
+		return fmt.Errorf("%s: %w", source, err)
+	}
+
+	names := make([]string, 0, len(art.frames))
+	widest := 0
+	for name, frames := range art.frames {
+		names = append(names, name)
+		for _, frame := range frames {
+			widest = max(widest, displayWidth(frame))
+		}
+	}
+	for _, seq := range art.sequences {
+		for _, kf := range seq.Frames {
+			widest = max(widest, displayWidth(kf.Frame))
+		}
+	}
+	sort.Strings(names)
+	fmt.Print(consoleText(fmt.Sprintf("✅ %s: %d frame sets, %d sequences, %d overlays, widest frame %d/%d columns\n",
+		source, len(names), len(art.sequences), len(art.overlays), widest, art.manifest.MaxWidth)))
+	fmt.Printf("   %s\n", strings.Join(names, ", "))
+	return nil
+}
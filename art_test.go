@@ -0,0 +1,172 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+	"testing/fstest"
+	"time"
+)
+
+// minimalArt is the smallest art directory that passes validation
+func minimalArt() fstest.MapFS {
+	files := fstest.MapFS{
+		"frame.txt": {Data: []byte("(o_o)\n")},
+		"manifest.json": {Data: []byte(`{"max_width": 10, "sets": [
+			{"name": "egg", "stage": "Egg", "frames": ["frame.txt"]},
+			{"name": "baby", "stage": "Baby", "frames": ["frame.txt"]},
+			{"name": "child", "stage": "Child", "frames": ["frame.txt"]},
+			{"name": "teen", "stage": "Teen", "frames": ["frame.txt"]},
+			{"name": "adult", "stage": "Adult", "frames": ["frame.txt"]},
+			{"name": "adult_sick", "stage": "Adult", "mood": "sick", "frames": ["sick.txt"]},
+			{"name": "dead", "stage": "Dead", "frames": ["frame.txt"]},
+			{"name": "the_look", "frames": ["frame.txt"]},
+			{"name": "static", "frames": ["frame.txt"]},
+			{"name": "suspended", "frames": ["frame.txt"]}
+		], "overlays": [{"weather": "rain", "position": "below", "frames": ["frame.txt"]}]}`)},
+		"sick.txt": {Data: []byte("(x_x)\n")},
+	}
+	return files
+}
+
+func TestEmbeddedArtIsValid(t *testing.T) {
+	if problems := activeArt.Validate(); len(problems) > 0 {
+		t.Errorf("Expected embedded art to validate, got %v", problems)
+	}
+	for _, stage := range drawnStages {
+		if len(activeArt.StageFrames(stage, "")) == 0 {
+			t.Errorf("Expected frames for %s", stage)
+		}
+	}
+	if frames, above := activeArt.Overlay("fog"); len(frames) == 0 || !above {
+		t.Errorf("Expected a fog overlay drawn above the pet")
+	}
+}
+
+func TestLoadArt(t *testing.T) {
+	art, err := loadArt(minimalArt())
+	if err != nil {
+		t.Fatalf("Expected minimal art to load, got %v", err)
+	}
+
+	tests := []struct {
+		stage LifeStage
+		mood  string
+		want  string
+	}{
+		{Adult, "", "(o_o)"},
+		{Adult, "sick", "(x_x)"},
+		{Adult, "hungry", "(o_o)"}, // No hungry art falls back to the default loop
+		{Baby, "sick", "(o_o)"},
+	}
+	for _, tt := range tests {
+		frames := art.StageFrames(tt.stage, tt.mood)
+		if len(frames) != 1 || frames[0] != tt.want {
+			t.Errorf("StageFrames(%s, %q): Expected [%s], got %v", tt.stage, tt.mood, tt.want, frames)
+		}
+	}
+}
+
+func TestLoadArtRejectsBrokenArt(t *testing.T) {
+	tests := []struct {
+		name    string
+		mutate  func(fstest.MapFS)
+		wantErr string
+	}{
+		{"missing manifest", func(f fstest.MapFS) { delete(f, "manifest.json") }, "manifest"},
+		{"missing frame", func(f fstest.MapFS) { delete(f, "sick.txt") }, "adult_sick"},
+		{"too wide", func(f fstest.MapFS) { f["sick.txt"] = &fstest.MapFile{Data: []byte("(x________x)")} }, "12 columns wide"},
+		{"wide glyphs", func(f fstest.MapFS) { f["sick.txt"] = &fstest.MapFile{Data: []byte("(たまごっち)")} }, "12 columns wide"},
+		{"missing stage", func(f fstest.MapFS) {
+			f["manifest.json"] = &fstest.MapFile{Data: []byte(strings.Replace(string(f["manifest.json"].Data), `"stage": "Teen"`, `"stage": "Tween"`, 1))}
+		}, "stage Teen has no default"},
+		{"missing special", func(f fstest.MapFS) {
+			f["manifest.json"] = &fstest.MapFile{Data: []byte(strings.Replace(string(f["manifest.json"].Data), `"the_look"`, `"a_look"`, 1))}
+		}, "missing frame set the_look"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			files := minimalArt()
+			tt.mutate(files)
+			_, err := loadArt(files)
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("Expected error containing %q, got %v", tt.wantErr, err)
+			}
+		})
+	}
+}
+
+func TestArtReloaderPicksUpEdits(t *testing.T) {
+	dir := t.TempDir()
+	for name, file := range minimalArt() {
+		if err := os.WriteFile(filepath.Join(dir, name), file.Data, 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	r := &artReloader{dir: dir}
+	now := time.Now()
+	art := r.Refresh(now)
+	if art == nil {
+		t.Fatalf("Expected the first refresh to load art, got error %v", r.lastErr)
+	}
+	if r.Refresh(now.Add(2*artReloadInterval)) != nil {
+		t.Errorf("Expected no reload when nothing changed")
+	}
+
+	later := now.Add(time.Minute)
+	os.WriteFile(filepath.Join(dir, "sick.txt"), []byte("(>_<)\n"), 0644)
+	os.Chtimes(filepath.Join(dir, "sick.txt"), later, later)
+	if r.Refresh(now.Add(artReloadInterval/2)) != nil {
+		t.Errorf("Expected checks to be rate limited")
+	}
+	art = r.Refresh(now.Add(4 * artReloadInterval))
+	if art == nil || art.StageFrames(Adult, "sick")[0] != "(>_<)" {
+		t.Errorf("Expected the edited frame after reload")
+	}
+
+	evenLater := later.Add(time.Minute)
+	os.WriteFile(filepath.Join(dir, "sick.txt"), []byte("(x________________x)\n"), 0644)
+	os.Chtimes(filepath.Join(dir, "sick.txt"), evenLater, evenLater)
+	if r.Refresh(now.Add(6*artReloadInterval)) != nil || r.lastErr == nil {
+		t.Errorf("Expected a broken edit to keep the old art and record the error")
+	}
+}
+
+func TestArtMood(t *testing.T) {
+	pet := NewPet("Tamago")
+	pet.IsSick = true
+	pet.Hunger = 90
+	if got := artMood(pet); got != "sick" {
+		t.Errorf("Expected sickness to win, got %q", got)
+	}
+	pet.Illness = &IllnessState{Hours: activeBalance.Sickness.CriticalAfterHours}
+	if got := artMood(pet); got != "critical" {
+		t.Errorf("Expected critical sickness art, got %q", got)
+	}
+	pet.IsSick = false
+	if got := artMood(pet); got != "hungry" {
+		t.Errorf("Expected hungry, got %q", got)
+	}
+}
+
+func TestSicknessArtFallsBackToMilder(t *testing.T) {
+	art := mustDefaultArt()
+	for _, stage := range []LifeStage{Baby, Child, Teen, Adult} {
+		sick, serious, critical := art.StageFrames(stage, "sick"), art.StageFrames(stage, "serious"), art.StageFrames(stage, "critical")
+		if slices.Equal(sick, serious) || slices.Equal(serious, critical) {
+			t.Errorf("Expected %s to draw each stage of sickness differently", stage)
+		}
+	}
+
+	// A pack with only the mild art draws it for every stage of sickness
+	art.manifest.Sets = slices.DeleteFunc(art.manifest.Sets, func(set FrameSet) bool {
+		return set.Mood == "serious" || set.Mood == "critical"
+	})
+	if got, want := art.StageFrames(Adult, "critical"), art.StageFrames(Adult, "sick"); !slices.Equal(got, want) {
+		t.Errorf("Expected critical to fall back to the sick art, got %q", got)
+	}
+}
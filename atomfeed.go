@@ -0,0 +1,143 @@
+package main
+
+import (
+	"encoding/xml"
+	"fmt"
+	"hash/fnv"
+	"os"
+	"path/filepath"
+	"slices"
+	"time"
+
+	"github.com/tamagotchi/mooc"
+)
+
+const (
+	// feedFile is the Atom feed export feed writes beside the save. Once it
+	// exists, autosave keeps it up to date.
+	feedFile = "tamagotchi_feed.atom"
+	// feedLength is how many events the feed keeps
+	feedLength = 50
+)
+
+// atomFeed is an Atom (RFC 4287) feed of the pet's life
+type atomFeed struct {
+	XMLName xml.Name    `xml:"http://www.w3.org/2005/Atom feed"`
+	Title   string      `xml:"title"`
+	ID      string      `xml:"id"`
+	Updated string      `xml:"updated"`
+	Author  string      `xml:"author>name"`
+	Entries []atomEntry `xml:"entry"`
+}
+
+// atomEntry is one event in the feed
+type atomEntry struct {
+	Title   string `xml:"title"`
+	ID      string `xml:"id"`
+	Updated string `xml:"updated"`
+	Content string `xml:"content"`
+}
+
+// feedEvents are the moments worth telling a subscriber: photos of
+// hatching and evolving, sicknesses, outbreaks caught, friends made and
+// deaths heard of
+func feedEvents(p *Pet, events []mooc.NetworkEvent) []historyEntry {
+	var entries []historyEntry
+	if p.Album != nil {
+		for _, photo := range p.Album.Photos {
+			entries = append(entries, historyEntry{photo.Taken, "📸 " + photo.Caption})
+		}
+	}
+	if p.Checkups != nil {
+		for _, at := range p.Checkups.Sicknesses {
+			entries = append(entries, historyEntry{at, fmt.Sprintf("🤒 %s falls sick", p.Name)})
+		}
+		for _, e := range p.Checkups.Exposures {
+			if !e.Resisted {
+				entries = append(entries, historyEntry{e.At, fmt.Sprintf("🦠 %s catches %s from %s", p.Name, e.Strain, e.From)})
+			}
+		}
+	}
+	for _, event := range events {
+		name := plainText(event.Name)
+		switch event.Kind {
+		case mooc.EventPeerMet:
+			entries = append(entries, historyEntry{event.At, fmt.Sprintf("✨ %s makes a new friend: %s", p.Name, name)})
+		case mooc.EventDeathWitnessed:
+			entries = append(entries, historyEntry{event.At, fmt.Sprintf("🕯️ %s hears that %s has died", p.Name, name)})
+		}
+	}
+	slices.SortStableFunc(entries, func(a, b historyEntry) int { return b.At.Compare(a.At) })
+	if len(entries) > feedLength {
+		entries = entries[:feedLength]
+	}
+	return entries
+}
+
+// buildFeed gathers the feed, newest first. Entry IDs come from the pet and
+// the moment, so a reader never sees the same event twice.
+func buildFeed(p *Pet, events []mooc.NetworkEvent, now time.Time) atomFeed {
+	id := "urn:tamagotchi:" + p.petID()
+	feed := atomFeed{Title: p.Name + "'s life", ID: id, Updated: now.UTC().Format(time.RFC3339), Author: p.Name}
+	for _, e := range feedEvents(p, events) {
+		h := fnv.New32a()
+		h.Write([]byte(e.Line))
+		feed.Entries = append(feed.Entries, atomEntry{
+			Title:   e.Line,
+			ID:      fmt.Sprintf("%s:%d:%08x", id, e.At.UnixNano(), h.Sum32()),
+			Updated: e.At.UTC().Format(time.RFC3339),
+			Content: fmt.Sprintf("%s — %s", e.At.Format("Mon Jan 2 2006 15:04"), e.Line),
+		})
+	}
+	if len(feed.Entries) > 0 {
+		feed.Updated = feed.Entries[0].Updated
+	}
+	return feed
+}
+
+// feedPath is where the pet's feed lives, beside its save
+func feedPath(p *Pet) string {
+	return filepath.Join(filepath.Dir(p.SaveFilePath), feedFile)
+}
+
+// writeFeed writes the feed to path
+func writeFeed(p *Pet, events []mooc.NetworkEvent, path string, now time.Time) error {
+	data, err := feedXML(p, events, now)
+	if err != nil {
+		return err
+	}
+	if err := os.WriteFile(path, data, 0644); err != nil {
+		return fmt.Errorf("failed to write %s: %w", path, err)
+	}
+	return nil
+}
+
+// feedXML is the feed as written to disk
+func feedXML(p *Pet, events []mooc.NetworkEvent, now time.Time) ([]byte, error) {
+	data, err := xml.MarshalIndent(buildFeed(p, events, now), "", "  ")
+	if err != nil {
+		return nil, fmt.Errorf("failed to build the feed: %w", err)
+	}
+	return append([]byte(xml.Header), append(data, '\n')...), nil
+}
+
+// refreshFeed keeps an exported feed up to date. It only rewrites a feed
+// the player asked for, so nothing appears on disk unasked.
+func refreshFeed(p *Pet, now time.Time) petJob {
+	path := feedPath(p)
+	if _, err := os.Stat(path); err != nil {
+		return nil
+	}
+	var events []mooc.NetworkEvent
+	if petNetwork != nil {
+		events = petNetwork.History()
+	}
+	data, err := feedXML(p, events, now)
+	if err != nil {
+		return nil
+	}
+	return func() func(*Pet) {
+		os.WriteFile(path, data, 0644) // A failed refresh is retried at the next autosave
+		return nil
+	}
+}
@@ -0,0 +1,98 @@
+package main
+
+import (
+	"encoding/xml"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/tamagotchi/mooc"
+)
+
+func TestFeedEvents(t *testing.T) {
+	now := time.Date(2026, 10, 16, 12, 0, 0, 0, time.UTC)
+	pet := NewPet("Mochi")
+	pet.Album = &AlbumState{Photos: []Photo{{Caption: "Mochi became a Child!", Taken: now.Add(-5 * time.Hour)}}}
+	pet.checkups().fellSick(now.Add(-4 * time.Hour))
+	pet.checkups().expose(Exposure{Strain: "Byte Flu", From: "Bean", At: now.Add(-3 * time.Hour)})
+	pet.checkups().expose(Exposure{Strain: "Kernel Cough", From: "Bean", At: now.Add(-3 * time.Hour), Resisted: true})
+	pet.checkups().History = []time.Time{now.Add(-6 * time.Hour)}
+	events := []mooc.NetworkEvent{
+		{Kind: mooc.EventPeerMet, Name: "Pip\x1b[2J", At: now.Add(-2 * time.Hour)},
+		{Kind: mooc.EventDreamShared, Name: "Pip", At: now.Add(-90 * time.Minute)},
+		{Kind: mooc.EventDeathWitnessed, Name: "Bean", At: now.Add(-time.Hour)},
+	}
+
+	got := feedEvents(pet, events)
+	want := []string{"hears that Bean has died", "new friend: Pip", "catches Byte Flu", "falls sick", "became a Child"}
+	if len(got) != len(want) {
+		t.Fatalf("Expected %d notable events, got %v", len(want), got)
+	}
+	for i, w := range want {
+		if !strings.Contains(got[i].Line, w) {
+			t.Errorf("Expected event %d to be %q, got %q", i, w, got[i].Line)
+		}
+	}
+	if strings.Contains(got[1].Line, "\x1b") {
+		t.Errorf("Expected a peer's name stripped of control characters, got %q", got[1].Line)
+	}
+}
+
+func TestWriteFeed(t *testing.T) {
+	now := time.Date(2026, 10, 16, 12, 0, 0, 0, time.UTC)
+	pet := NewPet("<Mochi>")
+	pet.checkups().fellSick(now.Add(-time.Hour))
+	events := []mooc.NetworkEvent{{Kind: mooc.EventPeerMet, Name: "Pip", At: now.Add(-2 * time.Hour)}}
+
+	path := filepath.Join(t.TempDir(), feedFile)
+	if err := writeFeed(pet, events, path, now); err != nil {
+		t.Fatalf("Expected the feed written, got %v", err)
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("Expected the feed on disk, got %v", err)
+	}
+	if !strings.Contains(string(data), `xmlns="http://www.w3.org/2005/Atom"`) || !strings.Contains(string(data), "&lt;Mochi&gt;") {
+		t.Errorf("Expected an escaped Atom feed, got %s", data)
+	}
+
+	var feed atomFeed
+	if err := xml.Unmarshal(data, &feed); err != nil {
+		t.Fatalf("Expected valid XML, got %v", err)
+	}
+	if len(feed.Entries) != 2 || feed.Updated != now.Add(-time.Hour).Format(time.RFC3339) {
+		t.Errorf("Expected two entries updated at the newest, got %+v", feed)
+	}
+	again := buildFeed(pet, events, now.Add(time.Hour))
+	for i, entry := range feed.Entries {
+		if entry.ID != again.Entries[i].ID {
+			t.Errorf("Expected stable entry IDs, got %q then %q", entry.ID, again.Entries[i].ID)
+		}
+	}
+	if feed.Entries[0].ID == feed.Entries[1].ID {
+		t.Errorf("Expected each entry its own ID")
+	}
+}
+
+func TestRefreshFeed(t *testing.T) {
+	pet := NewPet("Mochi")
+	pet.SaveFilePath = filepath.Join(t.TempDir(), "save.json")
+	now := time.Now()
+
+	runJob(pet, refreshFeed(pet, now))
+	if _, err := os.Stat(feedPath(pet)); err == nil {
+		t.Errorf("Expected no feed until one is exported")
+	}
+
+	if err := os.WriteFile(feedPath(pet), nil, 0644); err != nil {
+		t.Fatal(err)
+	}
+	pet.checkups().fellSick(now)
+	runJob(pet, refreshFeed(pet, now))
+	data, _ := os.ReadFile(feedPath(pet))
+	if !strings.Contains(string(data), "falls sick") {
+		t.Errorf("Expected an exported feed kept up to date, got %q", data)
+	}
+}
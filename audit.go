@@ -0,0 +1,179 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/tamagotchi/mooc"
+)
+
+const (
+	// auditFile is where --audit-network logs every outbound packet
+	auditFile = "tamagotchi_network_audit.log"
+
+	// auditTail is how many packets `network audit` shows
+	auditTail = 15
+
+	// auditSessionMarker starts each session's section of the log
+	auditSessionMarker = "# session"
+
+	// auditPrivacyMarker notes a change of privacy settings mid-session
+	auditPrivacyMarker = "# privacy"
+)
+
+// auditNetwork is set by --audit-network
+var auditNetwork = false
+
+// activeAudit is the running session's log, when auditing
+var activeAudit *networkAudit
+
+// networkAudit appends every outbound packet to a local log
+type networkAudit struct {
+	path  string
+	mutex sync.Mutex
+}
+
+// startNetworkAudit opens a new session in the log
+func startNetworkAudit(path string, pet *Pet, now time.Time) (*networkAudit, error) {
+	audit := &networkAudit{path: path}
+	header := fmt.Sprintf("%s %s %s\n", auditSessionMarker, now.Format(time.RFC3339), pet.Name)
+	return audit, audit.append(header)
+}
+
+func (a *networkAudit) append(line string) error {
+	a.mutex.Lock()
+	defer a.mutex.Unlock()
+	f, err := os.OpenFile(a.path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	_, err = f.WriteString(line)
+	return err
+}
+
+// notePrivacy marks a settings change, so packets sent under the old
+// settings aren't held against the new ones
+func (a *networkAudit) notePrivacy(change string, now time.Time) {
+	if a != nil {
+		a.append(fmt.Sprintf("%s %s %s\n", auditPrivacyMarker, now.Format(time.RFC3339), change))
+	}
+}
+
+// record is the mesh's audit hook; a log that can't be written is skipped
+// rather than stopping the network
+func (a *networkAudit) record(packet mooc.PacketRecord) {
+	a.append(formatPacket(packet) + "\n")
+}
+
+// formatPacket is one line of the audit log: time, type, size, destination
+func formatPacket(packet mooc.PacketRecord) string {
+	line := fmt.Sprintf("%s %-9s %5d B", packet.At.Format(time.RFC3339), packet.Type, packet.Size)
+	switch {
+	case packet.Held:
+		return line + "   held back by the rate limit"
+	case packet.Err != nil:
+		return fmt.Sprintf("%s → %s FAILED: %v", line, packet.Destination, packet.Err)
+	}
+	return line + " → " + packet.Destination
+}
+
+// lastAuditSession returns the lines logged since the most recent session began
+func lastAuditSession(path string) ([]string, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	lines := strings.Split(strings.TrimRight(string(data), "\n"), "\n")
+	for i := len(lines) - 1; i >= 0; i-- {
+		if strings.HasPrefix(lines[i], auditSessionMarker) {
+			return lines[i:], nil
+		}
+	}
+	return lines, nil
+}
+
+// auditViolations lists kinds of gossip that went out though the privacy
+// screen says they're off. It should always be empty.
+func auditViolations(packets []string, settings privacySettings) []string {
+	// Only what was sent since the settings last changed counts
+	for i := len(packets) - 1; i >= 0; i-- {
+		if strings.HasPrefix(packets[i], auditPrivacyMarker) {
+			packets = packets[i+1:]
+			break
+		}
+	}
+
+	var violations []string
+	for _, category := range privacyCategories {
+		if !*category.off(&settings) {
+			continue
+		}
+		for _, line := range packets {
+			fields := strings.Fields(line)
+			if len(fields) > 1 && fields[1] == category.msgType.String() && !strings.Contains(line, "held back") {
+				violations = append(violations, category.name)
+				break
+			}
+		}
+	}
+	return violations
+}
+
+// runNetworkAudit shows this session's traffic from the audit log and checks
+// it against the privacy settings
+func runNetworkAudit(path string, enabled bool, settings privacySettings) string {
+	if !enabled {
+		return "📋 Not auditing. Start the game with --audit-network to log every packet to " + path + "."
+	}
+	lines, err := lastAuditSession(path)
+	if err != nil {
+		return fmt.Sprintf("❌ Couldn't read %s: %v", path, err)
+	}
+	header, packets := lines[0], lines[1:]
+
+	all := packets
+	packets = nil
+	for _, line := range all {
+		if !strings.HasPrefix(line, "#") {
+			packets = append(packets, line)
+		}
+	}
+	counts := map[string]int{}
+	for _, line := range packets {
+		if fields := strings.Fields(line); len(fields) > 1 {
+			counts[fields[1]]++
+		}
+	}
+	var kinds []string
+	for kind, count := range counts {
+		kinds = append(kinds, fmt.Sprintf("%s %d", kind, count))
+	}
+	sort.Strings(kinds)
+
+	var b strings.Builder
+	b.WriteString(fmt.Sprintf("📋 NETWORK AUDIT (%s)\n", path))
+	b.WriteString(strings.TrimSpace(strings.TrimPrefix(header, auditSessionMarker)) + "\n\n")
+	if len(packets) == 0 {
+		b.WriteString("Nothing has been sent this session.\n")
+	} else {
+		b.WriteString(fmt.Sprintf("%d packets this session: %s\n", len(packets), strings.Join(kinds, ", ")))
+		if len(packets) > auditTail {
+			packets = packets[len(packets)-auditTail:]
+		}
+		for _, line := range packets {
+			b.WriteString("  " + line + "\n")
+		}
+	}
+
+	if violations := auditViolations(all, settings); len(violations) > 0 {
+		b.WriteString(fmt.Sprintf("\n⚠️  Sent while switched off: %s. That's a bug; please report it.", strings.Join(violations, ", ")))
+	} else {
+		b.WriteString("\n✅ Everything sent matches the privacy screen.")
+	}
+	return b.String()
+}
@@ -0,0 +1,74 @@
+package main
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/tamagotchi/mooc"
+)
+
+func TestFormatPacket(t *testing.T) {
+	at := time.Date(2030, 1, 1, 12, 0, 0, 0, time.UTC)
+	tests := []struct {
+		name   string
+		packet mooc.PacketRecord
+		want   string
+	}{
+		{"sent", mooc.PacketRecord{At: at, Type: "ANNOUNCE", Size: 312, Destination: "255.255.255.255:19847"}, "2030-01-01T12:00:00Z ANNOUNCE    312 B → 255.255.255.255:19847"},
+		{"held", mooc.PacketRecord{At: at, Type: "MEMORY", Size: 400, Held: true}, "held back by the rate limit"},
+		{"failed", mooc.PacketRecord{At: at, Type: "MOOD", Size: 200, Destination: "10.0.0.2:19847", Err: errors.New("no route")}, "FAILED: no route"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := formatPacket(tt.packet); !strings.Contains(got, tt.want) {
+				t.Errorf("Expected %q in %q", tt.want, got)
+			}
+		})
+	}
+}
+
+func TestNetworkAuditLog(t *testing.T) {
+	path := filepath.Join(t.TempDir(), auditFile)
+	now := time.Now()
+
+	// An older session shouldn't show up
+	os.WriteFile(path, []byte(auditSessionMarker+" 2020-01-01T00:00:00Z Old\n2020-01-01T00:00:01Z DEATH 100 B → x\n"), 0644)
+
+	audit, err := startNetworkAudit(path, NewPet("Watched"), now)
+	if err != nil {
+		t.Fatalf("Expected audit to start, got %v", err)
+	}
+	audit.record(mooc.PacketRecord{At: now, Type: "ANNOUNCE", Size: 300, Destination: "255.255.255.255:19847"})
+	audit.record(mooc.PacketRecord{At: now, Type: "MEMORY", Size: 350, Destination: "10.0.0.2:19847"})
+
+	out := runNetworkAudit(path, true, privacySettings{})
+	for _, want := range []string{"Watched", "2 packets this session", "ANNOUNCE 1", "MEMORY 1", "matches the privacy screen"} {
+		if !strings.Contains(out, want) {
+			t.Errorf("Expected %q in audit, got %q", want, out)
+		}
+	}
+	if strings.Contains(out, "DEATH") {
+		t.Errorf("Expected only this session's packets, got %q", out)
+	}
+
+	// A memory sent while memories are off is flagged, until the settings change
+	off := privacySettings{NoMemories: true}
+	if out := runNetworkAudit(path, true, off); !strings.Contains(out, "Sent while switched off: memories") {
+		t.Errorf("Expected a violation, got %q", out)
+	}
+	audit.notePrivacy("memories off", now)
+	if out := runNetworkAudit(path, true, off); strings.Contains(out, "switched off") {
+		t.Errorf("Expected packets before the change not to count, got %q", out)
+	}
+}
+
+func TestNetworkAuditDisabled(t *testing.T) {
+	if out := runNetworkAudit(auditFile, false, privacySettings{}); !strings.Contains(out, "--audit-network") {
+		t.Errorf("Expected a hint about --audit-network, got %q", out)
+	}
+}
@@ -0,0 +1,531 @@
+package main
+
+import (
+	"bytes"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/hmac"
+	"crypto/pbkdf2"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"sort"
+	"strings"
+	"time"
+)
+
+const (
+	// backupObjectName is the name backups are stored under with every provider
+	backupObjectName = "tamagotchi_backup.tamabak"
+
+	// backupMagic starts every encrypted backup so restores can recognize one
+	backupMagic = "TAMABAK1"
+
+	// backupKDFIterations is the PBKDF2 work factor for the passphrase
+	backupKDFIterations = 200_000
+
+	// backupTimeout bounds a single upload or download
+	backupTimeout = 60 * time.Second
+
+	// defaultBackupInterval is how often automatic backups run
+	defaultBackupInterval = 24 * time.Hour
+
+	// backupRetryDelay spaces out automatic retries after a failed backup
+	backupRetryDelay = 15 * time.Minute
+)
+
+// BackupProvider stores encrypted backups somewhere off this machine
+type BackupProvider interface {
+	// Name identifies the provider in messages
+	Name() string
+	// Upload stores data under name, replacing any previous copy
+	Upload(name string, data []byte) error
+	// Download fetches the data stored under name
+	Download(name string) ([]byte, error)
+}
+
+// backupConfig is the user's backup setup
+type backupConfig struct {
+	provider   BackupProvider
+	passphrase string
+	interval   time.Duration
+	lastTry    time.Time // Last automatic or manual attempt, successful or not
+}
+
+// newBackupConfig reads the backup setup from the environment. It returns
+// nil without error when backups aren't configured.
+func newBackupConfig(getenv func(string) string) (*backupConfig, error) {
+	kind := strings.ToLower(getenv("TAMAGOTCHI_BACKUP"))
+	if kind == "" {
+		return nil, nil
+	}
+
+	passphrase := getenv("TAMAGOTCHI_BACKUP_PASSPHRASE")
+	if passphrase == "" {
+		return nil, fmt.Errorf("TAMAGOTCHI_BACKUP_PASSPHRASE is required; backups are never uploaded unencrypted")
+	}
+
+	interval := defaultBackupInterval
+	if raw := getenv("TAMAGOTCHI_BACKUP_INTERVAL"); raw != "" {
+		d, err := time.ParseDuration(raw)
+		if err != nil || d < time.Hour {
+			return nil, fmt.Errorf("TAMAGOTCHI_BACKUP_INTERVAL must be a duration of at least 1h, got %q", raw)
+		}
+		interval = d
+	}
+
+	client := &http.Client{Timeout: backupTimeout}
+	var provider BackupProvider
+	switch kind {
+	case "s3":
+		s3 := &s3Backup{
+			bucket:       getenv("TAMAGOTCHI_S3_BUCKET"),
+			region:       getenv("TAMAGOTCHI_S3_REGION"),
+			endpoint:     getenv("TAMAGOTCHI_S3_ENDPOINT"),
+			accessKey:    getenv("AWS_ACCESS_KEY_ID"),
+			secretKey:    getenv("AWS_SECRET_ACCESS_KEY"),
+			sessionToken: getenv("AWS_SESSION_TOKEN"),
+			client:       client,
+		}
+		if s3.region == "" {
+			s3.region = "us-east-1"
+		}
+		if s3.bucket == "" || s3.accessKey == "" || s3.secretKey == "" {
+			return nil, fmt.Errorf("S3 backups need TAMAGOTCHI_S3_BUCKET, AWS_ACCESS_KEY_ID, and AWS_SECRET_ACCESS_KEY")
+		}
+		provider = s3
+	case "dropbox":
+		token := getenv("TAMAGOTCHI_DROPBOX_TOKEN")
+		if token == "" {
+			return nil, fmt.Errorf("Dropbox backups need TAMAGOTCHI_DROPBOX_TOKEN")
+		}
+		provider = &dropboxBackup{token: token, baseURL: "https://content.dropboxapi.com", client: client}
+	case "webdav":
+		base := getenv("TAMAGOTCHI_WEBDAV_URL")
+		if base == "" {
+			return nil, fmt.Errorf("WebDAV backups need TAMAGOTCHI_WEBDAV_URL")
+		}
+		provider = &webdavBackup{
+			baseURL:  strings.TrimRight(base, "/"),
+			user:     getenv("TAMAGOTCHI_WEBDAV_USER"),
+			password: getenv("TAMAGOTCHI_WEBDAV_PASSWORD"),
+			client:   client,
+		}
+	default:
+		return nil, fmt.Errorf("unknown backup provider %q (try s3, dropbox, or webdav)", kind)
+	}
+
+	return &backupConfig{provider: provider, passphrase: passphrase, interval: interval}, nil
+}
+
+// encryptBackup seals data with a key derived from passphrase
+func encryptBackup(data []byte, passphrase string) ([]byte, error) {
+	salt := make([]byte, 16)
+	if _, err := rand.Read(salt); err != nil {
+		return nil, fmt.Errorf("failed to generate salt: %w", err)
+	}
+	gcm, err := backupCipher(passphrase, salt)
+	if err != nil {
+		return nil, err
+	}
+	nonce := make([]byte, gcm.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return nil, fmt.Errorf("failed to generate nonce: %w", err)
+	}
+
+	out := append([]byte(backupMagic), salt...)
+	out = append(out, nonce...)
+	return gcm.Seal(out, nonce, data, []byte(backupMagic)), nil
+}
+
+// decryptBackup opens data sealed by encryptBackup
+func decryptBackup(data []byte, passphrase string) ([]byte, error) {
+	if !bytes.HasPrefix(data, []byte(backupMagic)) {
+		return nil, fmt.Errorf("not a tamagotchi backup")
+	}
+	data = data[len(backupMagic):]
+	if len(data) < 16 {
+		return nil, fmt.Errorf("backup is truncated")
+	}
+	salt, data := data[:16], data[16:]
+
+	gcm, err := backupCipher(passphrase, salt)
+	if err != nil {
+		return nil, err
+	}
+	if len(data) < gcm.NonceSize() {
+		return nil, fmt.Errorf("backup is truncated")
+	}
+	nonce, sealed := data[:gcm.NonceSize()], data[gcm.NonceSize():]
+
+	plain, err := gcm.Open(nil, nonce, sealed, []byte(backupMagic))
+	if err != nil {
+		return nil, fmt.Errorf("wrong passphrase or damaged backup")
+	}
+	return plain, nil
+}
+
+// backupCipher derives the AES-256-GCM cipher for a passphrase and salt
+func backupCipher(passphrase string, salt []byte) (cipher.AEAD, error) {
+	key, err := pbkdf2.Key(sha256.New, passphrase, salt, backupKDFIterations, 32)
+	if err != nil {
+		return nil, fmt.Errorf("failed to derive key: %w", err)
+	}
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create cipher: %w", err)
+	}
+	return cipher.NewGCM(block)
+}
+
+// Due reports whether an automatic backup should run
+func (b *backupConfig) Due(p *Pet, now time.Time) bool {
+	return b != nil && now.Sub(p.LastBackupAt) >= b.interval && now.Sub(b.lastTry) >= backupRetryDelay
+}
+
+// Run encrypts the pet and uploads it
+func (b *backupConfig) Run(p *Pet, now time.Time) (string, error) {
+	data, err := b.snapshot(p, now)
+	if err != nil {
+		return "", err
+	}
+	size, err := b.upload(data)
+	if err != nil {
+		return "", err
+	}
+	p.LastBackupAt = now
+	return fmt.Sprintf("☁️ Backed up to %s (%d bytes, encrypted). The bloodline is safe.", b.provider.Name(), size), nil
+}
+
+// snapshot is the pet as it will be uploaded
+func (b *backupConfig) snapshot(p *Pet, now time.Time) ([]byte, error) {
+	b.lastTry = now
+	data, err := json.Marshal(p)
+	if err != nil {
+		return nil, fmt.Errorf("failed to snapshot pet: %w", err)
+	}
+	return data, nil
+}
+
+// upload encrypts a snapshot and sends it, returning the bytes sent
+func (b *backupConfig) upload(data []byte) (int, error) {
+	sealed, err := encryptBackup(data, b.passphrase)
+	if err != nil {
+		return 0, err
+	}
+	if err := b.provider.Upload(backupObjectName, sealed); err != nil {
+		return 0, fmt.Errorf("%s upload failed: %w", b.provider.Name(), err)
+	}
+	return len(sealed), nil
+}
+
+// job is an automatic backup: the snapshot is taken now, the upload done
+// once the pet is let go. Failures retry after backupRetryDelay.
+func (b *backupConfig) job(p *Pet, now time.Time) petJob {
+	data, err := b.snapshot(p, now)
+	if err != nil {
+		return nil
+	}
+	return func() func(*Pet) {
+		if _, err := b.upload(data); err != nil {
+			return nil
+		}
+		return func(p *Pet) { p.LastBackupAt = now }
+	}
+}
+
+// Status describes the backup setup for `backup status`
+func (b *backupConfig) Status(p *Pet) string {
+	if b == nil {
+		return "☁️ Backups are off. Set TAMAGOTCHI_BACKUP to s3, dropbox, or webdav (and TAMAGOTCHI_BACKUP_PASSPHRASE) to turn them on."
+	}
+	last := "never"
+	if !p.LastBackupAt.IsZero() {
+		last = p.LastBackupAt.Format("Mon Jan 2 3:04pm")
+	}
+	return fmt.Sprintf("☁️ Backing up to %s every %s. Last backup: %s.", b.provider.Name(), b.interval, last)
+}
+
+// runBackupCommand handles `backup [now|status]`
+func runBackupCommand(b *backupConfig, pet *Pet, args string) string {
+	switch args {
+	case "", "status":
+		return b.Status(pet)
+	case "now":
+		if b == nil {
+			return b.Status(pet)
+		}
+		saveNetworkState(pet)
+		message, err := b.Run(pet, time.Now())
+		if err != nil {
+			return "❌ " + err.Error()
+		}
+		return message
+	}
+	return "❓ Usage: backup [now|status]"
+}
+
+// runBackupSubcommand handles `tamagotchi backup restore [--force]`
+func runBackupSubcommand(args []string) error {
+	if len(args) == 0 || args[0] != "restore" {
+		return fmt.Errorf("usage: tamagotchi backup restore [--force]")
+	}
+	force := len(args) > 1 && args[1] == "--force"
+	if _, err := os.Stat(saveFile); err == nil && !force {
+		return fmt.Errorf("%s already exists; pass --force to replace it with the backup", saveFile)
+	}
+
+	config, err := newBackupConfig(os.Getenv)
+	if err != nil {
+		return err
+	}
+	if config == nil {
+		return fmt.Errorf("backups aren't configured; set TAMAGOTCHI_BACKUP")
+	}
+
+	sealed, err := config.provider.Download(backupObjectName)
+	if err != nil {
+		return fmt.Errorf("%s download failed: %w", config.provider.Name(), err)
+	}
+	data, err := decryptBackup(sealed, config.passphrase)
+	if err != nil {
+		return err
+	}
+
+	var pet Pet
+	if err := json.Unmarshal(data, &pet); err != nil {
+		return fmt.Errorf("backup doesn't contain a pet: %w", err)
+	}
+	if err := os.WriteFile(saveFile, data, 0644); err != nil {
+		return fmt.Errorf("failed to write save file: %w", err)
+	}
+	fmt.Print(consoleText(fmt.Sprintf("🐣 Restored %s from %s. Start the game to say hello.\n", pet.Name, config.provider.Name())))
+	return nil
+}
+
+// checkHTTPStatus turns a non-2xx response into an error
+func checkHTTPStatus(resp *http.Response) error {
+	if resp.StatusCode/100 == 2 {
+		return nil
+	}
+	body, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+	return fmt.Errorf("server said %s: %s", resp.Status, strings.TrimSpace(string(body)))
+}
+
+// doBackupRequest sends a request and returns the body of a successful response
+func doBackupRequest(client *http.Client, req *http.Request) ([]byte, error) {
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if err := checkHTTPStatus(resp); err != nil {
+		return nil, err
+	}
+	return io.ReadAll(resp.Body)
+}
+
+// webdavBackup stores backups with plain PUT and GET on a WebDAV share
+type webdavBackup struct {
+	baseURL  string
+	user     string
+	password string
+	client   *http.Client
+}
+
+func (w *webdavBackup) Name() string { return "WebDAV" }
+
+// request builds an authenticated request for name
+func (w *webdavBackup) request(method, name string, body []byte) (*http.Request, error) {
+	req, err := http.NewRequest(method, w.baseURL+"/"+url.PathEscape(name), bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	if w.user != "" {
+		req.SetBasicAuth(w.user, w.password)
+	}
+	return req, nil
+}
+
+// Upload PUTs the backup
+func (w *webdavBackup) Upload(name string, data []byte) error {
+	req, err := w.request(http.MethodPut, name, data)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/octet-stream")
+	_, err = doBackupRequest(w.client, req)
+	return err
+}
+
+// Download GETs the backup
+func (w *webdavBackup) Download(name string) ([]byte, error) {
+	req, err := w.request(http.MethodGet, name, nil)
+	if err != nil {
+		return nil, err
+	}
+	return doBackupRequest(w.client, req)
+}
+
+// dropboxBackup stores backups through the Dropbox content API
+type dropboxBackup struct {
+	token   string
+	baseURL string
+	client  *http.Client
+}
+
+func (d *dropboxBackup) Name() string { return "Dropbox" }
+
+// request builds a content API call; the file arguments travel in a header
+func (d *dropboxBackup) request(endpoint string, arg map[string]interface{}, body []byte) (*http.Request, error) {
+	header, err := json.Marshal(arg)
+	if err != nil {
+		return nil, err
+	}
+	req, err := http.NewRequest(http.MethodPost, d.baseURL+endpoint, bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Authorization", "Bearer "+d.token)
+	req.Header.Set("Dropbox-API-Arg", string(header))
+	return req, nil
+}
+
+// Upload overwrites the backup in the app folder
+func (d *dropboxBackup) Upload(name string, data []byte) error {
+	req, err := d.request("/2/files/upload", map[string]interface{}{"path": "/" + name, "mode": "overwrite", "mute": true}, data)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/octet-stream")
+	_, err = doBackupRequest(d.client, req)
+	return err
+}
+
+// Download fetches the backup from the app folder
+func (d *dropboxBackup) Download(name string) ([]byte, error) {
+	req, err := d.request("/2/files/download", map[string]interface{}{"path": "/" + name}, nil)
+	if err != nil {
+		return nil, err
+	}
+	return doBackupRequest(d.client, req)
+}
+
+// s3Backup stores backups in an S3 (or S3-compatible) bucket using SigV4
+type s3Backup struct {
+	bucket       string
+	region       string
+	endpoint     string // Optional, for S3-compatible stores; implies path-style URLs
+	accessKey    string
+	secretKey    string
+	sessionToken string
+	client       *http.Client
+	now          func() time.Time // Signing clock; nil uses time.Now
+}
+
+func (s *s3Backup) Name() string { return "S3" }
+
+// objectURL returns the URL for a key
+func (s *s3Backup) objectURL(key string) string {
+	if s.endpoint != "" {
+		return strings.TrimRight(s.endpoint, "/") + "/" + s.bucket + "/" + url.PathEscape(key)
+	}
+	return fmt.Sprintf("https://%s.s3.%s.amazonaws.com/%s", s.bucket, s.region, url.PathEscape(key))
+}
+
+// Upload PUTs the backup object
+func (s *s3Backup) Upload(name string, data []byte) error {
+	req, err := http.NewRequest(http.MethodPut, s.objectURL(name), bytes.NewReader(data))
+	if err != nil {
+		return err
+	}
+	s.sign(req, data)
+	_, err = doBackupRequest(s.client, req)
+	return err
+}
+
+// Download GETs the backup object
+func (s *s3Backup) Download(name string) ([]byte, error) {
+	req, err := http.NewRequest(http.MethodGet, s.objectURL(name), nil)
+	if err != nil {
+		return nil, err
+	}
+	s.sign(req, nil)
+	return doBackupRequest(s.client, req)
+}
+
+// sign adds AWS Signature Version 4 headers to req
+func (s *s3Backup) sign(req *http.Request, body []byte) {
+	now := time.Now
+	if s.now != nil {
+		now = s.now
+	}
+	t := now().UTC()
+	amzDate := t.Format("20060102T150405Z")
+	day := t.Format("20060102")
+	payloadHash := sha256Hex(body)
+
+	req.Header.Set("X-Amz-Date", amzDate)
+	req.Header.Set("X-Amz-Content-Sha256", payloadHash)
+	if s.sessionToken != "" {
+		req.Header.Set("X-Amz-Security-Token", s.sessionToken)
+	}
+
+	headers := map[string]string{"host": req.URL.Host}
+	for name, values := range req.Header {
+		if lower := strings.ToLower(name); strings.HasPrefix(lower, "x-amz-") {
+			headers[lower] = strings.TrimSpace(values[0])
+		}
+	}
+	names := make([]string, 0, len(headers))
+	for name := range headers {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var canonicalHeaders strings.Builder
+	for _, name := range names {
+		canonicalHeaders.WriteString(name + ":" + headers[name] + "\n")
+	}
+	signedHeaders := strings.Join(names, ";")
+
+	canonicalRequest := strings.Join([]string{
+		req.Method,
+		req.URL.EscapedPath(),
+		req.URL.RawQuery,
+		canonicalHeaders.String(),
+		signedHeaders,
+		payloadHash,
+	}, "\n")
+
+	scope := day + "/" + s.region + "/s3/aws4_request"
+	stringToSign := "AWS4-HMAC-SHA256\n" + amzDate + "\n" + scope + "\n" + sha256Hex([]byte(canonicalRequest))
+
+	key := hmacSHA256([]byte("AWS4"+s.secretKey), day)
+	key = hmacSHA256(key, s.region)
+	key = hmacSHA256(key, "s3")
+	key = hmacSHA256(key, "aws4_request")
+	signature := hex.EncodeToString(hmacSHA256(key, stringToSign))
+
+	req.Header.Set("Authorization", fmt.Sprintf("AWS4-HMAC-SHA256 Credential=%s/%s, SignedHeaders=%s, Signature=%s",
+		s.accessKey, scope, signedHeaders, signature))
+}
+
+// sha256Hex returns the hex SHA-256 of data
+func sha256Hex(data []byte) string {
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])
+}
+
+// hmacSHA256 returns HMAC-SHA256(key, data)
+func hmacSHA256(key []byte, data string) []byte {
+	mac := hmac.New(sha256.New, key)
+	mac.Write([]byte(data))
+	return mac.Sum(nil)
+}
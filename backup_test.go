@@ -0,0 +1,218 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestBackupEncryptionRoundTrip(t *testing.T) {
+	data := []byte(`{"name":"Tamago"}`)
+	sealed, err := encryptBackup(data, "correct horse")
+	if err != nil {
+		t.Fatalf("Expected encryption to succeed, got %v", err)
+	}
+	if bytes.Contains(sealed, []byte("Tamago")) {
+		t.Errorf("Expected the pet's name not to appear in the backup")
+	}
+	if !bytes.HasPrefix(sealed, []byte(backupMagic)) {
+		t.Errorf("Expected backup to start with %q", backupMagic)
+	}
+
+	plain, err := decryptBackup(sealed, "correct horse")
+	if err != nil {
+		t.Fatalf("Expected decryption to succeed, got %v", err)
+	}
+	if !bytes.Equal(plain, data) {
+		t.Errorf("Expected %s, got %s", data, plain)
+	}
+
+	if _, err := decryptBackup(sealed, "battery staple"); err == nil {
+		t.Errorf("Expected the wrong passphrase to fail")
+	}
+	if _, err := decryptBackup(sealed[:20], "correct horse"); err == nil {
+		t.Errorf("Expected a truncated backup to fail")
+	}
+	if _, err := decryptBackup([]byte("{}"), "correct horse"); err == nil {
+		t.Errorf("Expected a plain save file to be rejected")
+	}
+}
+
+func TestNewBackupConfig(t *testing.T) {
+	tests := []struct {
+		name     string
+		env      map[string]string
+		wantNil  bool
+		wantErr  bool
+		provider string
+	}{
+		{"disabled", map[string]string{}, true, false, ""},
+		{"no passphrase", map[string]string{"TAMAGOTCHI_BACKUP": "webdav", "TAMAGOTCHI_WEBDAV_URL": "https://dav.example"}, true, true, ""},
+		{"unknown provider", map[string]string{"TAMAGOTCHI_BACKUP": "floppy", "TAMAGOTCHI_BACKUP_PASSPHRASE": "x"}, true, true, ""},
+		{"webdav", map[string]string{"TAMAGOTCHI_BACKUP": "webdav", "TAMAGOTCHI_BACKUP_PASSPHRASE": "x", "TAMAGOTCHI_WEBDAV_URL": "https://dav.example"}, false, false, "WebDAV"},
+		{"dropbox without token", map[string]string{"TAMAGOTCHI_BACKUP": "dropbox", "TAMAGOTCHI_BACKUP_PASSPHRASE": "x"}, true, true, ""},
+		{"dropbox", map[string]string{"TAMAGOTCHI_BACKUP": "Dropbox", "TAMAGOTCHI_BACKUP_PASSPHRASE": "x", "TAMAGOTCHI_DROPBOX_TOKEN": "t"}, false, false, "Dropbox"},
+		{"s3 without keys", map[string]string{"TAMAGOTCHI_BACKUP": "s3", "TAMAGOTCHI_BACKUP_PASSPHRASE": "x", "TAMAGOTCHI_S3_BUCKET": "b"}, true, true, ""},
+		{"s3", map[string]string{"TAMAGOTCHI_BACKUP": "s3", "TAMAGOTCHI_BACKUP_PASSPHRASE": "x", "TAMAGOTCHI_S3_BUCKET": "b", "AWS_ACCESS_KEY_ID": "a", "AWS_SECRET_ACCESS_KEY": "s"}, false, false, "S3"},
+		{"short interval", map[string]string{"TAMAGOTCHI_BACKUP": "dropbox", "TAMAGOTCHI_BACKUP_PASSPHRASE": "x", "TAMAGOTCHI_DROPBOX_TOKEN": "t", "TAMAGOTCHI_BACKUP_INTERVAL": "5m"}, true, true, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			config, err := newBackupConfig(func(key string) string { return tt.env[key] })
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Expected error %v, got %v", tt.wantErr, err)
+			}
+			if (config == nil) != tt.wantNil {
+				t.Fatalf("Expected nil config %v, got %v", tt.wantNil, config)
+			}
+			if config != nil && config.provider.Name() != tt.provider {
+				t.Errorf("Expected provider %s, got %s", tt.provider, config.provider.Name())
+			}
+		})
+	}
+}
+
+// memoryServer is a tiny object store that records the last request it saw
+func memoryServer(t *testing.T, check func(r *http.Request)) (*httptest.Server, map[string][]byte) {
+	t.Helper()
+	objects := make(map[string][]byte)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		check(r)
+		key := r.URL.Path
+		if arg := r.Header.Get("Dropbox-API-Arg"); arg != "" {
+			var parsed struct{ Path string }
+			json.Unmarshal([]byte(arg), &parsed)
+			key = parsed.Path
+		}
+		body, _ := io.ReadAll(r.Body)
+		if len(body) > 0 {
+			objects[key] = body
+			return
+		}
+		data, ok := objects[key]
+		if !ok {
+			http.NotFound(w, r)
+			return
+		}
+		w.Write(data)
+	}))
+	t.Cleanup(server.Close)
+	return server, objects
+}
+
+func TestBackupProvidersRoundTrip(t *testing.T) {
+	var lastAuth string
+	server, _ := memoryServer(t, func(r *http.Request) { lastAuth = r.Header.Get("Authorization") })
+
+	providers := []struct {
+		provider BackupProvider
+		authHas  string
+	}{
+		{&webdavBackup{baseURL: server.URL, user: "me", password: "pw", client: server.Client()}, "Basic "},
+		{&dropboxBackup{token: "tok", baseURL: server.URL, client: server.Client()}, "Bearer tok"},
+		{&s3Backup{bucket: "pets", region: "eu-west-1", endpoint: server.URL, accessKey: "AKID", secretKey: "secret", client: server.Client()}, "Credential=AKID/"},
+	}
+
+	for _, tt := range providers {
+		t.Run(tt.provider.Name(), func(t *testing.T) {
+			if err := tt.provider.Upload(backupObjectName, []byte("sealed")); err != nil {
+				t.Fatalf("Expected upload to succeed, got %v", err)
+			}
+			if !strings.Contains(lastAuth, tt.authHas) {
+				t.Errorf("Expected Authorization to contain %q, got %q", tt.authHas, lastAuth)
+			}
+			data, err := tt.provider.Download(backupObjectName)
+			if err != nil {
+				t.Fatalf("Expected download to succeed, got %v", err)
+			}
+			if string(data) != "sealed" {
+				t.Errorf("Expected sealed, got %s", data)
+			}
+			if _, err := tt.provider.Download("missing"); err == nil {
+				t.Errorf("Expected a missing object to fail")
+			}
+		})
+	}
+}
+
+func TestS3SignatureIsDeterministic(t *testing.T) {
+	fixed := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	s3 := &s3Backup{bucket: "pets", region: "us-east-1", accessKey: "AKID", secretKey: "secret", now: func() time.Time { return fixed }}
+
+	sign := func() *http.Request {
+		req, _ := http.NewRequest(http.MethodPut, s3.objectURL(backupObjectName), nil)
+		s3.sign(req, []byte("sealed"))
+		return req
+	}
+	first, second := sign(), sign()
+
+	if first.URL.Host != "pets.s3.us-east-1.amazonaws.com" {
+		t.Errorf("Expected virtual-hosted URL, got %s", first.URL.Host)
+	}
+	if first.Header.Get("X-Amz-Date") != "20260301T120000Z" {
+		t.Errorf("Expected X-Amz-Date 20260301T120000Z, got %s", first.Header.Get("X-Amz-Date"))
+	}
+	auth := first.Header.Get("Authorization")
+	if !strings.Contains(auth, "Credential=AKID/20260301/us-east-1/s3/aws4_request") ||
+		!strings.Contains(auth, "SignedHeaders=host;x-amz-content-sha256;x-amz-date") {
+		t.Errorf("Unexpected Authorization header: %s", auth)
+	}
+	if auth != second.Header.Get("Authorization") {
+		t.Errorf("Expected identical requests to sign identically")
+	}
+
+	s3.sessionToken = "session"
+	if !strings.Contains(sign().Header.Get("Authorization"), "x-amz-security-token") {
+		t.Errorf("Expected the session token to be signed")
+	}
+}
+
+func TestBackupRunAndDue(t *testing.T) {
+	server, objects := memoryServer(t, func(*http.Request) {})
+	config := &backupConfig{
+		provider:   &webdavBackup{baseURL: server.URL, client: server.Client()},
+		passphrase: "pw",
+		interval:   24 * time.Hour,
+	}
+	pet := NewPet("Tamago")
+	now := time.Now()
+
+	if !config.Due(pet, now) {
+		t.Errorf("Expected a pet that was never backed up to be due")
+	}
+	if _, err := config.Run(pet, now); err != nil {
+		t.Fatalf("Expected backup to succeed, got %v", err)
+	}
+	if !pet.LastBackupAt.Equal(now) {
+		t.Errorf("Expected LastBackupAt to be set")
+	}
+	if config.Due(pet, now.Add(time.Hour)) {
+		t.Errorf("Expected no backup due an hour later")
+	}
+	if !config.Due(pet, now.Add(25*time.Hour)) {
+		t.Errorf("Expected a backup due a day later")
+	}
+
+	plain, err := decryptBackup(objects["/"+backupObjectName], "pw")
+	if err != nil {
+		t.Fatalf("Expected uploaded backup to decrypt, got %v", err)
+	}
+	var restored Pet
+	if err := json.Unmarshal(plain, &restored); err != nil || restored.Name != "Tamago" {
+		t.Errorf("Expected backup to contain Tamago, got %q (%v)", restored.Name, err)
+	}
+
+	var none *backupConfig
+	if none.Due(pet, now) {
+		t.Errorf("Expected no backups due when unconfigured")
+	}
+	if !strings.Contains(runBackupCommand(nil, pet, "now"), "Backups are off") {
+		t.Errorf("Expected backup now to explain how to turn backups on")
+	}
+}
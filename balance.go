@@ -0,0 +1,213 @@
+package main
+
+import (
+	_ "embed"
+	"encoding/json"
+	"fmt"
+	"os"
+	"slices"
+)
+
+// balanceOverrideFile lets players and mods retune the game without code edits
+const balanceOverrideFile = "tamagotchi_balance.json"
+
+//go:embed balance.json
+var defaultBalanceJSON []byte
+
+// StageBalance tunes a single life stage
+type StageBalance struct {
+	MinAgeHours     int     `json:"min_age_hours"`    // Age at which the stage begins
+	DecayMultiplier float64 `json:"decay_multiplier"` // Scales the per-hour decay rates
+}
+
+// DecayRates are the base per-hour stat changes before stage multipliers
+type DecayRates struct {
+	Hunger      float64 `json:"hunger"`
+	Happiness   float64 `json:"happiness"`
+	Cleanliness float64 `json:"cleanliness"`
+}
+
+// DamageRule drains health while any stat is past its threshold
+type DamageRule struct {
+	HungerAbove      int     `json:"hunger_above"`
+	HappinessBelow   int     `json:"happiness_below"`
+	CleanlinessBelow int     `json:"cleanliness_below"`
+	HealthPerHour    float64 `json:"health_per_hour"`
+}
+
+// RecoveryRule restores health while every stat is comfortably good
+type RecoveryRule struct {
+	HungerBelow      int     `json:"hunger_below"`
+	HappinessAbove   int     `json:"happiness_above"`
+	CleanlinessAbove int     `json:"cleanliness_above"`
+	HealthPerHour    float64 `json:"health_per_hour"`
+}
+
+// SicknessRule makes the pet sick when either threshold is crossed, and
+// says how an untreated sickness worsens
+type SicknessRule struct {
+	HealthBelow        int     `json:"health_below"`
+	CleanlinessBelow   int     `json:"cleanliness_below"`
+	SeriousAfterHours  float64 `json:"serious_after_hours"`  // Untreated time before it turns serious
+	CriticalAfterHours float64 `json:"critical_after_hours"` // Untreated time before it turns critical
+	HealthPerHour      float64 `json:"health_per_hour"`      // Extra drain while serious; doubled when critical
+	HospiceHealthBelow int     `json:"hospice_health_below"` // Critical pets this weak are past medicine
+}
+
+// Balance holds every tunable number used by the stat simulation
+type Balance struct {
+	MinUpdateHours float64                 `json:"min_update_hours"`
+	Stages         map[string]StageBalance `json:"stages"` // Keyed by LifeStage name
+	DecayPerHour   DecayRates              `json:"decay_per_hour"`
+	Damage         DamageRule              `json:"damage"`
+	Recovery       RecoveryRule            `json:"recovery"`
+	Sickness       SicknessRule            `json:"sickness"`
+}
+
+// activeBalance is set once at startup and read by the simulation
+var activeBalance = mustDefaultBalance()
+
+// livingStages are the stages that must appear in a balance table, in order
+var livingStages = []LifeStage{Egg, Baby, Child, Teen, Adult}
+
+// mustDefaultBalance parses the embedded table; a broken default is a build bug
+func mustDefaultBalance() *Balance {
+	b, err := parseBalance(defaultBalanceJSON, nil)
+	if err != nil {
+		panic(fmt.Sprintf("embedded balance table is invalid: %v", err))
+	}
+	return b
+}
+
+// parseBalance decodes data over an optional base table and validates the result
+func parseBalance(data []byte, base *Balance) (*Balance, error) {
+	b := &Balance{Stages: make(map[string]StageBalance)}
+	if base != nil {
+		*b = *base
+		b.Stages = make(map[string]StageBalance, len(base.Stages))
+		for name, stage := range base.Stages {
+			b.Stages[name] = stage
+		}
+	}
+
+	// A stage is merged field by field, so an override can change one
+	// number without restating the rest; decoding the map whole would
+	// zero whatever the override left out
+	stages := b.Stages
+	b.Stages = nil
+	var override struct {
+		Stages map[string]json.RawMessage `json:"stages"`
+	}
+	if err := json.Unmarshal(data, b); err != nil {
+		return nil, fmt.Errorf("failed to parse balance table: %w", err)
+	}
+	if err := json.Unmarshal(data, &override); err != nil {
+		return nil, fmt.Errorf("failed to parse balance table: %w", err)
+	}
+	b.Stages = stages
+	for name, raw := range override.Stages {
+		stage := stages[name]
+		if err := json.Unmarshal(raw, &stage); err != nil {
+			return nil, fmt.Errorf("failed to parse balance for stage %s: %w", name, err)
+		}
+		stages[name] = stage
+	}
+	if err := b.Validate(); err != nil {
+		return nil, err
+	}
+	return b, nil
+}
+
+// Validate checks that the table is internally consistent
+func (b *Balance) Validate() error {
+	if b.MinUpdateHours < 0 {
+		return fmt.Errorf("min_update_hours must not be negative")
+	}
+
+	for name := range b.Stages {
+		if !slices.ContainsFunc(livingStages, func(stage LifeStage) bool { return stage.String() == name }) {
+			return fmt.Errorf("unknown stage %q in stages", name)
+		}
+	}
+	lastAge := -1
+	for _, stage := range livingStages {
+		sb, ok := b.Stages[stage.String()]
+		if !ok {
+			return fmt.Errorf("missing balance for stage %s", stage)
+		}
+		if sb.DecayMultiplier < 0 {
+			return fmt.Errorf("stage %s has a negative decay multiplier", stage)
+		}
+		if sb.MinAgeHours <= lastAge {
+			return fmt.Errorf("stage %s must begin after the previous stage", stage)
+		}
+		lastAge = sb.MinAgeHours
+	}
+	if b.Stages[Egg.String()].MinAgeHours != 0 {
+		return fmt.Errorf("the Egg stage must begin at age 0")
+	}
+
+	if b.DecayPerHour.Hunger < 0 || b.DecayPerHour.Happiness < 0 || b.DecayPerHour.Cleanliness < 0 {
+		return fmt.Errorf("decay rates must not be negative")
+	}
+	if b.Damage.HealthPerHour < 0 || b.Recovery.HealthPerHour < 0 || b.Sickness.HealthPerHour < 0 {
+		return fmt.Errorf("health rates must not be negative")
+	}
+
+	if b.Sickness.SeriousAfterHours <= 0 || b.Sickness.CriticalAfterHours <= b.Sickness.SeriousAfterHours {
+		return fmt.Errorf("sickness must turn serious after some time, and critical after that")
+	}
+
+	thresholds := map[string]int{
+		"damage.hunger_above":           b.Damage.HungerAbove,
+		"damage.happiness_below":        b.Damage.HappinessBelow,
+		"damage.cleanliness_below":      b.Damage.CleanlinessBelow,
+		"recovery.hunger_below":         b.Recovery.HungerBelow,
+		"recovery.happiness_above":      b.Recovery.HappinessAbove,
+		"recovery.cleanliness_above":    b.Recovery.CleanlinessAbove,
+		"sickness.health_below":         b.Sickness.HealthBelow,
+		"sickness.cleanliness_below":    b.Sickness.CleanlinessBelow,
+		"sickness.hospice_health_below": b.Sickness.HospiceHealthBelow,
+	}
+	for name, value := range thresholds {
+		if value < 0 || value > 100 {
+			return fmt.Errorf("%s must be between 0 and 100", name)
+		}
+	}
+
+	return nil
+}
+
+// StageFor returns the life stage reached at the given age in hours
+func (b *Balance) StageFor(ageHours int) LifeStage {
+	for i := len(livingStages) - 1; i >= 0; i-- {
+		stage := livingStages[i]
+		if ageHours >= b.Stages[stage.String()].MinAgeHours {
+			return stage
+		}
+	}
+	return Egg
+}
+
+// DecayMultiplier returns the decay multiplier for a stage
+func (b *Balance) DecayMultiplier(stage LifeStage) float64 {
+	return b.Stages[stage.String()].DecayMultiplier
+}
+
+// loadBalanceOverride applies the user override file, if present, on top of the defaults
+func loadBalanceOverride(path string) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil
+		}
+		return fmt.Errorf("failed to read balance override: %w", err)
+	}
+
+	b, err := parseBalance(data, mustDefaultBalance())
+	if err != nil {
+		return fmt.Errorf("%s: %w", path, err)
+	}
+	activeBalance = b
+	return nil
+}
@@ -0,0 +1,125 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestDefaultBalanceValid(t *testing.T) {
+	b := mustDefaultBalance()
+
+	if err := b.Validate(); err != nil {
+		t.Fatalf("Default balance should validate: %v", err)
+	}
+
+	tests := []struct {
+		age      int
+		expected LifeStage
+	}{
+		{0, Egg},
+		{1, Baby},
+		{23, Baby},
+		{24, Child},
+		{48, Teen},
+		{72, Adult},
+		{500, Adult},
+	}
+	for _, tt := range tests {
+		if stage := b.StageFor(tt.age); stage != tt.expected {
+			t.Errorf("StageFor(%d) = %v, expected %v", tt.age, stage, tt.expected)
+		}
+	}
+
+	if b.DecayMultiplier(Egg) != 0 {
+		t.Error("Eggs should not decay by default")
+	}
+}
+
+func TestParseBalanceOverride(t *testing.T) {
+	override := []byte(`{"decay_per_hour": {"hunger": 10, "happiness": 3, "cleanliness": 4}}`)
+
+	b, err := parseBalance(override, mustDefaultBalance())
+	if err != nil {
+		t.Fatalf("Unexpected override error: %v", err)
+	}
+	if b.DecayPerHour.Hunger != 10 {
+		t.Errorf("Expected overridden hunger decay 10, got %v", b.DecayPerHour.Hunger)
+	}
+	if b.Damage.HungerAbove != 70 {
+		t.Errorf("Untouched fields should keep defaults, got %d", b.Damage.HungerAbove)
+	}
+}
+
+func TestParseBalanceOverridesOneStageField(t *testing.T) {
+	base := mustDefaultBalance()
+	b, err := parseBalance([]byte(`{"stages": {"Teen": {"decay_multiplier": 2.5}}}`), base)
+	if err != nil {
+		t.Fatalf("Expected a single stage field to override cleanly, got %v", err)
+	}
+	teen := b.Stages[Teen.String()]
+	if teen.DecayMultiplier != 2.5 {
+		t.Errorf("Expected the Teen decay multiplier overridden to 2.5, got %v", teen.DecayMultiplier)
+	}
+	if teen.MinAgeHours != base.Stages[Teen.String()].MinAgeHours {
+		t.Errorf("Expected the Teen stage to keep its start age %d, got %d", base.Stages[Teen.String()].MinAgeHours, teen.MinAgeHours)
+	}
+	if b.StageFor(48) != Teen {
+		t.Errorf("Expected later stages not to shift, got %v at 48 hours", b.StageFor(48))
+	}
+	if base.Stages[Teen.String()].DecayMultiplier == 2.5 {
+		t.Errorf("Expected the base table left untouched")
+	}
+}
+
+func TestParseBalanceRejectsInvalid(t *testing.T) {
+	invalid := []string{
+		`{"decay_per_hour": {"hunger": -1}}`,
+		`{"sickness": {"health_below": 150}}`,
+		`{"sickness": {"critical_after_hours": 12}}`,
+		`{"stages": {"Teen": {"min_age_hours": 10, "decay_multiplier": 1}}}`,
+		`{"stages": {"Adlut": {"decay_multiplier": 2}}}`,
+		`not json`,
+	}
+
+	for _, data := range invalid {
+		if _, err := parseBalance([]byte(data), mustDefaultBalance()); err == nil {
+			t.Errorf("Expected error for %s", data)
+		}
+	}
+}
+
+func TestParseBalanceNamesUnknownStage(t *testing.T) {
+	_, err := parseBalance([]byte(`{"stages": {"Adlut": {"decay_multiplier": 2}}}`), mustDefaultBalance())
+	if err == nil || !strings.Contains(err.Error(), `"Adlut"`) {
+		t.Errorf("Expected the misspelt stage named in the error, got %v", err)
+	}
+}
+
+func TestLoadBalanceOverride(t *testing.T) {
+	defer func() { activeBalance = mustDefaultBalance() }()
+
+	if err := loadBalanceOverride(filepath.Join(t.TempDir(), "missing.json")); err != nil {
+		t.Errorf("Missing override should be ignored, got %v", err)
+	}
+
+	path := filepath.Join(t.TempDir(), balanceOverrideFile)
+	os.WriteFile(path, []byte(`{"stages": {"Baby": {"min_age_hours": 1, "decay_multiplier": 0}}}`), 0644)
+	if err := loadBalanceOverride(path); err != nil {
+		t.Fatalf("Unexpected override error: %v", err)
+	}
+
+	pet := NewPet("TestPet")
+	pet.BirthTime = time.Now().Add(-2 * time.Hour)
+	pet.LastUpdateTime = time.Now().Add(-1 * time.Hour)
+	pet.Update()
+
+	if pet.Stage != Baby {
+		t.Fatalf("Expected Baby, got %v", pet.Stage)
+	}
+	if pet.Hunger != 0 {
+		t.Errorf("Overridden baby decay of 0 should leave hunger at 0, got %d", pet.Hunger)
+	}
+}
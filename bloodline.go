@@ -0,0 +1,218 @@
+package main
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+)
+
+// Bloodline power IDs, as kept in the save
+const (
+	powerSecondSight = "second_sight"
+	powerSteadyNerve = "steady_nerve"
+	powerOldMemory   = "old_memory"
+)
+
+// bloodlinePower is a gift a line of pets earns by lasting
+type bloodlinePower struct {
+	ID          string
+	Name        string
+	Badge       string // Shown in the status panel once unlocked
+	Generation  int    // The generation that unlocks it
+	Description string
+	Use         string // How to use it
+}
+
+// bloodlinePowers are the powers, in the order they unlock
+var bloodlinePowers = []bloodlinePower{
+	{ID: powerSecondSight, Name: "Second Sight", Badge: "👁", Generation: 3,
+		Description: "See one of your invisible accessories. Just one.", Use: "bloodline see [accessory]"},
+	{ID: powerSteadyNerve, Name: "Steady Nerve", Badge: "💪", Generation: 5,
+		Description: "One fear becomes a strength: its trigger steadies your pet instead.", Use: "bloodline steel [fear]"},
+	{ID: powerOldMemory, Name: "Ancestral Memory", Badge: "📜", Generation: 7,
+		Description: "Read one of the diary pages that are stuck together.", Use: "bloodline read"},
+}
+
+// strengthHappiness is the lift a fear turned strength gives when triggered
+const strengthHappiness = 5
+
+// BloodlineState is which powers this pet knows it has, and what it chose
+// to use each one on. Each can be used once.
+type BloodlineState struct {
+	Unlocked []string `json:"unlocked,omitempty"` // Powers announced to this pet
+	Seen     string   `json:"seen,omitempty"`     // The invisible accessory Second Sight revealed
+	Strength string   `json:"strength,omitempty"` // The fear Steady Nerve turned
+	Read     string   `json:"read,omitempty"`     // Header of the diary page Ancestral Memory read
+}
+
+// generation counts the pet and every ancestor before it
+func (p *Pet) generation() int {
+	return len(p.Lineage) + 1
+}
+
+// hasPower reports whether the line has lasted long enough for a power
+func (p *Pet) hasPower(id string) bool {
+	for _, power := range bloodlinePowers {
+		if power.ID == id {
+			return p.generation() >= power.Generation
+		}
+	}
+	return false
+}
+
+// bloodlineBadges are the badges of every power the pet has
+func (p *Pet) bloodlineBadges() string {
+	var badges []string
+	for _, power := range bloodlinePowers {
+		if p.hasPower(power.ID) {
+			badges = append(badges, power.Badge)
+		}
+	}
+	return strings.Join(badges, " ")
+}
+
+// unlockBloodline announces the powers the pet has come into
+func (p *Pet) unlockBloodline() []string {
+	var messages []string
+	for _, power := range bloodlinePowers {
+		if !p.hasPower(power.ID) || (p.Bloodline != nil && slices.Contains(p.Bloodline.Unlocked, power.ID)) {
+			continue
+		}
+		if p.Bloodline == nil {
+			p.Bloodline = &BloodlineState{}
+		}
+		p.Bloodline.Unlocked = append(p.Bloodline.Unlocked, power.ID)
+		messages = append(messages, fmt.Sprintf("🧬 Generation %d: the bloodline awakens %s %s. %s Try: %s", power.Generation, power.Badge, power.Name, power.Description, power.Use))
+	}
+	return messages
+}
+
+// bloodline returns the pet's bloodline state, creating it on first use
+func (p *Pet) bloodline() *BloodlineState {
+	if p.Bloodline == nil {
+		p.Bloodline = &BloodlineState{}
+	}
+	return p.Bloodline
+}
+
+// isStrength reports whether a fear was turned into a strength
+func (p *Pet) isStrength(fear string) bool {
+	return p.Bloodline != nil && p.Bloodline.Strength == fear
+}
+
+// steadied is what happens when a fear turned strength is triggered
+func (p *Pet) steadied(fear Fear) string {
+	p.Happiness = clamp(p.Happiness+strengthHappiness, 0, 100)
+	return fmt.Sprintf("💪 %s stands firm. %s used to make its ancestors tremble; now it's where %s is strongest.", p.Name, fear.Name, p.Name)
+}
+
+// renderBloodline lists the powers, locked and unlocked, and what each was
+// used on
+func renderBloodline(p *Pet) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "🧬 %s'S BLOODLINE — generation %d\n\n", strings.ToUpper(p.Name), p.generation())
+	for _, power := range bloodlinePowers {
+		if !p.hasPower(power.ID) {
+			fmt.Fprintf(&b, "  🔒 Generation %d: ???\n", power.Generation)
+			continue
+		}
+		fmt.Fprintf(&b, "  %s %s (generation %d): %s\n", power.Badge, power.Name, power.Generation, power.Description)
+		used := ""
+		if p.Bloodline != nil {
+			used = map[string]string{powerSecondSight: p.Bloodline.Seen, powerSteadyNerve: p.Bloodline.Strength, powerOldMemory: p.Bloodline.Read}[power.ID]
+		}
+		if used == "" {
+			fmt.Fprintf(&b, "     Unused. %s\n", power.Use)
+		} else {
+			fmt.Fprintf(&b, "     Used on: %s\n", used)
+		}
+	}
+	if p.generation() < bloodlinePowers[0].Generation {
+		b.WriteString("\nEvery pet you reset adds a generation. The line has to last to earn anything.")
+	}
+	return strings.TrimRight(b.String(), "\n")
+}
+
+// seeAccessory uses Second Sight on one owned invisible accessory
+func (p *Pet) seeAccessory(name string) string {
+	if seen := p.bloodline().Seen; seen != "" {
+		return fmt.Sprintf("👁 %s can see %s. Everything else stays invisible.", p.Name, seen)
+	}
+	if p.Endgame == nil || len(p.Endgame.InvisibleAccessories) == 0 {
+		return "👁 There's nothing to see. You don't own any invisible accessories yet; the gacha can fix that."
+	}
+	owned := p.Endgame.InvisibleAccessories
+	pick := owned[0]
+	if name != "" {
+		i := slices.IndexFunc(owned, func(a string) bool { return strings.EqualFold(a, name) })
+		if i < 0 {
+			return fmt.Sprintf("❓ You don't own %s. You own: %s", name, strings.Join(owned, ", "))
+		}
+		pick = owned[i]
+	}
+	p.Bloodline.Seen = pick
+	return fmt.Sprintf("👁 For a moment the air around %s shimmers, and there it is: the %s. It fits perfectly. It always did.", p.Name, pick)
+}
+
+// steelFear uses Steady Nerve on one of the pet's fears
+func (p *Pet) steelFear(name string) string {
+	if strength := p.bloodline().Strength; strength != "" {
+		return fmt.Sprintf("💪 %s is already %s's strength. The rest of its fears are still fears.", strength, p.Name)
+	}
+	if p.Absurd == nil || len(p.Absurd.Fears) == 0 {
+		return fmt.Sprintf("💪 %s fears nothing, so there's nothing to turn. Suspicious.", p.Name)
+	}
+	fears := p.Absurd.Fears
+	pick := fears[0]
+	if name != "" {
+		i := slices.IndexFunc(fears, func(f Fear) bool { return strings.EqualFold(f.Name, name) })
+		if i < 0 {
+			return fmt.Sprintf("❓ %s doesn't have %s. fears lists what it has.", p.Name, name)
+		}
+		pick = fears[i]
+	}
+	p.Bloodline.Strength = pick.Name
+	return fmt.Sprintf("💪 %s faces %s, the way its ancestors never could. It's a strength now.", p.Name, pick.Name)
+}
+
+// readStuckPage uses Ancestral Memory on the oldest page the diary keeps
+// hidden, and shows that same page after
+func (p *Pet) readStuckPage(path string) string {
+	entries, _ := readDiary(path)
+	read := p.bloodline().Read
+	for _, entry := range entries {
+		if !strings.Contains(entry.Header, ", "+diaryHiddenTime) || (read != "" && entry.Header != read) {
+			continue
+		}
+		p.Bloodline.Read = entry.Header
+		return fmt.Sprintf("📜 The pages come apart in %s's paws:\n\n%s\n%s", p.Name, entry.Header, entry.Body)
+	}
+	if read != "" {
+		return "📜 The page Ancestral Memory opened is gone from the diary."
+	}
+	return "📜 No pages are stuck together. Not yet."
+}
+
+// runBloodlineCommand handles `bloodline [see|steel|read]`
+func runBloodlineCommand(pet *Pet, args string) string {
+	verb, rest, _ := strings.Cut(strings.TrimSpace(args), " ")
+	rest = strings.TrimSpace(rest)
+	uses := map[string]string{"see": powerSecondSight, "steel": powerSteadyNerve, "read": powerOldMemory}
+	if verb == "" {
+		return renderBloodline(pet)
+	}
+	id, ok := uses[verb]
+	if !ok {
+		return "❓ Usage: bloodline [see [accessory]|steel [fear]|read]"
+	}
+	if !pet.hasPower(id) {
+		return fmt.Sprintf("🔒 The bloodline isn't that old yet. %s is generation %d.", pet.Name, pet.generation())
+	}
+	switch verb {
+	case "see":
+		return pet.seeAccessory(rest)
+	case "steel":
+		return pet.steelFear(rest)
+	}
+	return pet.readStuckPage(diaryFile)
+}
@@ -0,0 +1,120 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+// petOfGeneration is a pet with n-1 ancestors behind it
+func petOfGeneration(n int) *Pet {
+	pet := NewPet("Heir")
+	for i := 1; i < n; i++ {
+		pet.Lineage = append(pet.Lineage, Ancestor{Name: "Elder", Stage: "Adult"})
+	}
+	return pet
+}
+
+func TestBloodlineUnlocksByGeneration(t *testing.T) {
+	tests := []struct {
+		generation int
+		powers     []string
+		badges     string
+	}{
+		{1, nil, ""},
+		{3, []string{powerSecondSight}, "👁"},
+		{5, []string{powerSecondSight, powerSteadyNerve}, "👁 💪"},
+		{7, []string{powerSecondSight, powerSteadyNerve, powerOldMemory}, "👁 💪 📜"},
+	}
+	for _, tt := range tests {
+		pet := petOfGeneration(tt.generation)
+		messages := pet.unlockBloodline()
+		if len(messages) != len(tt.powers) {
+			t.Errorf("Expected %d powers announced at generation %d, got %v", len(tt.powers), tt.generation, messages)
+		}
+		for _, id := range tt.powers {
+			if !pet.hasPower(id) {
+				t.Errorf("Expected %s at generation %d", id, tt.generation)
+			}
+		}
+		if got := pet.bloodlineBadges(); got != tt.badges {
+			t.Errorf("Expected badges %q at generation %d, got %q", tt.badges, tt.generation, got)
+		}
+		if again := pet.unlockBloodline(); len(again) != 0 {
+			t.Errorf("Expected each power announced once, got %v", again)
+		}
+	}
+}
+
+func TestSecondSight(t *testing.T) {
+	pet := petOfGeneration(3)
+	if got := runBloodlineCommand(pet, "see"); !strings.Contains(got, "don't own any") {
+		t.Errorf("Expected nothing to see without accessories, got %q", got)
+	}
+	pet.Endgame.InvisibleAccessories = []string{"Invisible Top Hat", "Invisible Crown"}
+	if got := runBloodlineCommand(pet, "see Mystery Cape"); !strings.Contains(got, "don't own") {
+		t.Errorf("Expected an unowned accessory refused, got %q", got)
+	}
+	if got := runBloodlineCommand(pet, "see invisible crown"); !strings.Contains(got, "Invisible Crown") || pet.Bloodline.Seen != "Invisible Crown" {
+		t.Errorf("Expected the crown seen, got %q", got)
+	}
+	runBloodlineCommand(pet, "see Invisible Top Hat")
+	if pet.Bloodline.Seen != "Invisible Crown" {
+		t.Errorf("Expected Second Sight used only once, got %q", pet.Bloodline.Seen)
+	}
+	if got := runBloodlineCommand(pet, "steel"); !strings.Contains(got, "isn't that old") {
+		t.Errorf("Expected Steady Nerve locked at generation 3, got %q", got)
+	}
+}
+
+func TestSteadyNerve(t *testing.T) {
+	pet := petOfGeneration(5)
+	pet.Absurd.Fears = []Fear{{Name: "Qphobia", Description: "Fears the letter Q", Trigger: "q"}}
+	pet.Happiness = 50
+	runBloodlineCommand(pet, "steel qphobia")
+	if !pet.isStrength("Qphobia") {
+		t.Fatalf("Expected Qphobia turned into a strength")
+	}
+
+	_, message := unknownCommand(pet, newDefaultRegistry(), "qqq", "qqq")
+	if !strings.Contains(message, "stands firm") || pet.Happiness != 50+strengthHappiness {
+		t.Errorf("Expected the trigger to steady the pet, got %q and happiness %d", message, pet.Happiness)
+	}
+	if pet.Absurd.FearTriggers["Qphobia"] != 0 {
+		t.Errorf("Expected a strength not counted as a fear triggered")
+	}
+}
+
+func TestAncestralMemory(t *testing.T) {
+	path := filepath.Join(t.TempDir(), diaryFile)
+	day := time.Date(2026, 10, 1, 0, 0, 0, 0, time.UTC)
+	diary := diaryHeader(day, "Heir", false) + "\nA nice day.\n" +
+		diaryHeader(day, "Heir", true) + "\nThe first secret.\n" +
+		diaryHeader(day.AddDate(0, 0, 1), "Heir", true) + "\nThe second secret.\n"
+	if err := os.WriteFile(path, []byte(diary), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	pet := petOfGeneration(7)
+	got := pet.readStuckPage(path)
+	if !strings.Contains(got, "The first secret.") || strings.Contains(got, "second") {
+		t.Errorf("Expected the oldest stuck page read, got %q", got)
+	}
+	if again := pet.readStuckPage(path); !strings.Contains(again, "The first secret.") {
+		t.Errorf("Expected the same page every time, got %q", again)
+	}
+}
+
+func TestRenderBloodline(t *testing.T) {
+	got := renderBloodline(petOfGeneration(1))
+	if !strings.Contains(got, "generation 1") || strings.Count(got, "🔒") != len(bloodlinePowers) {
+		t.Errorf("Expected every power locked for a first pet, got %q", got)
+	}
+	pet := petOfGeneration(3)
+	pet.bloodline().Seen = "Invisible Crown"
+	if got := renderBloodline(pet); !strings.Contains(got, "Used on: Invisible Crown") {
+		t.Errorf("Expected the use shown, got %q", got)
+	}
+}
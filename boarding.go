@@ -0,0 +1,262 @@
+package main
+
+import (
+	"fmt"
+	"math/rand"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	// maxBoardingDuration is the longest a friend will look after the pet
+	maxBoardingDuration = 14 * 24 * time.Hour
+
+	// boardingWindow and boardingFrequentStays decide when boarding is "too often"
+	boardingWindow        = 30 * 24 * time.Hour
+	boardingFrequentStays = 2
+
+	// boardingHappinessCost is what each too-frequent stay costs
+	boardingHappinessCost = 10
+
+	// maxPostcards caps how many postcards a single stay produces
+	maxPostcards = 7
+)
+
+// BoardingState tracks stays at a friend's place
+type BoardingState struct {
+	Start time.Time   `json:"start"` // Zero unless the pet is boarding
+	Until time.Time   `json:"until"` // When the pet comes home
+	Host  string      `json:"host"`  // The (invented) friend looking after the pet
+	Stays []time.Time `json:"stays"` // Start times of recent stays
+}
+
+// Friends who take in boarders
+var boardingHosts = []string{"Biscuit", "Mochi", "Professor Crumb", "Dot", "Big Terry", "Waffles"}
+
+// Postcard templates; %s is the host's name
+var postcardTemplates = []string{
+	"Dear human, %s and I built a fort out of deprecated config files. It held for three whole minutes.",
+	"%s took me to the beach. The sand was all semicolons. I was very brave.",
+	"Today %s taught me to juggle. I dropped everything. %s said that's most of juggling.",
+	"%s has a cat. The cat has a Tamagotchi. I have questions.",
+	"We went to the arcade. %s won a plush of ME. I'm not sure how to feel.",
+	"%s let me stay up past my sleep schedule. We watched a progress bar reach 100%%. Magical.",
+	"Went hiking with %s. Saw a wild `git stash` from 2019. Left it alone.",
+	"%s made pancakes shaped like the number seventeen. I didn't tell them why that's important.",
+}
+
+// careCommands need an awake pet at home
+var careCommands = map[string]bool{
+	"feed": true, "play": true, "clean": true, "heal": true, "comfort": true, "checkup": true,
+	"pet": true, "say": true, "games": true, "focus": true,
+}
+
+// isCareCommand reports whether the named command needs an awake pet at home
+func isCareCommand(name string) bool {
+	return careCommands[name]
+}
+
+// parseBoardingDuration parses durations like "7d", "36h", or "2d12h"
+func parseBoardingDuration(arg string) (time.Duration, error) {
+	arg = strings.TrimSpace(arg)
+	if arg == "" {
+		return 0, fmt.Errorf("how long? try: boarding start 7d")
+	}
+
+	var total time.Duration
+	rest := arg
+	if idx := strings.IndexByte(rest, 'd'); idx >= 0 {
+		days, err := strconv.Atoi(rest[:idx])
+		if err != nil || days < 0 {
+			return 0, fmt.Errorf("can't read %q as a duration; try 7d or 36h", arg)
+		}
+		total += time.Duration(days) * 24 * time.Hour
+		rest = rest[idx+1:]
+	}
+	if rest != "" {
+		d, err := time.ParseDuration(rest)
+		if err != nil || d < 0 {
+			return 0, fmt.Errorf("can't read %q as a duration; try 7d or 36h", arg)
+		}
+		total += d
+	}
+
+	if total < time.Hour {
+		return 0, fmt.Errorf("boarding needs to be at least an hour")
+	}
+	if total > maxBoardingDuration {
+		return 0, fmt.Errorf("no friend will take your pet for more than %d days", int(maxBoardingDuration.Hours()/24))
+	}
+	return total, nil
+}
+
+// IsAway reports whether the pet is currently boarding
+func (b *BoardingState) IsAway(now time.Time) bool {
+	return b != nil && !b.Start.IsZero() && now.Before(b.Until)
+}
+
+// recentStays counts stays that started within the boarding window
+func (b *BoardingState) recentStays(now time.Time) int {
+	count := 0
+	for _, start := range b.Stays {
+		if now.Sub(start) < boardingWindow {
+			count++
+		}
+	}
+	return count
+}
+
+// StartBoarding sends the pet to a friend's place for the given duration
+func (p *Pet) StartBoarding(d time.Duration, now time.Time) (string, error) {
+	switch {
+	case p.Stage == Dead:
+		return "", fmt.Errorf("no one will board a pet that has passed away")
+	case p.Stage == Egg:
+		return "", fmt.Errorf("eggs can't go boarding; they need your warmth")
+	case p.Boarding.IsAway(now):
+		return "", fmt.Errorf("%s is already at %s's place", p.Name, p.Boarding.Host)
+	case p.Travel.IsAway(now):
+		return "", fmt.Errorf("%s is in %s", p.Name, p.Travel.Destination)
+	}
+
+	// Bring stats up to date so the freeze starts from the right place
+	p.Update()
+
+	if p.Boarding == nil {
+		p.Boarding = &BoardingState{}
+	}
+	b := p.Boarding
+
+	// Forget stays outside the window
+	kept := b.Stays[:0]
+	for _, start := range b.Stays {
+		if now.Sub(start) < boardingWindow {
+			kept = append(kept, start)
+		}
+	}
+	b.Stays = kept
+
+	var cost string
+	if b.recentStays(now) >= boardingFrequentStays {
+		p.Happiness = clamp(p.Happiness-boardingHappinessCost, 0, 100)
+		cost = fmt.Sprintf("\n😒 Again? %s is starting to wonder if you want it around. (-%d happiness)", p.Name, boardingHappinessCost)
+	}
+
+	b.Start = now
+	b.Until = now.Add(d)
+	b.Host = boardingHosts[rand.Intn(len(boardingHosts))]
+	b.Stays = append(b.Stays, now)
+
+	return fmt.Sprintf("🧳 %s packs a tiny suitcase and heads to %s's place until %s. Stats are frozen while it's away.%s",
+		p.Name, b.Host, b.Until.Format("Mon Jan 2 3pm"), cost), nil
+}
+
+// EndBoarding picks the pet up early
+func (p *Pet) EndBoarding(now time.Time) (string, error) {
+	if !p.Boarding.IsAway(now) {
+		return "", fmt.Errorf("%s isn't boarding anywhere", p.Name)
+	}
+	p.Boarding.Until = now
+	return fmt.Sprintf("🚗 You pick %s up early from %s's place.", p.Name, p.Boarding.Host), nil
+}
+
+// BoardingStatus describes the current stay
+func (p *Pet) BoardingStatus(now time.Time) string {
+	if !p.Boarding.IsAway(now) {
+		return fmt.Sprintf("🏠 %s is home. Send it away with: boarding start 7d", p.Name)
+	}
+	return fmt.Sprintf("🧳 %s is at %s's place until %s (%s to go).",
+		p.Name, p.Boarding.Host, p.Boarding.Until.Format("Mon Jan 2 3pm"), formatDuration(p.Boarding.Until.Sub(now).Truncate(time.Minute)))
+}
+
+// ReturnFromBoarding brings the pet home once its stay is over and returns its postcards
+func (p *Pet) ReturnFromBoarding(now time.Time, ctx conversationContext) []string {
+	if p.Boarding == nil || p.Boarding.Start.IsZero() || p.Boarding.IsAway(now) {
+		return nil
+	}
+
+	b := p.Boarding
+	cards := postcards(b.Host, b.Until.Sub(b.Start), ctx)
+	b.Start = time.Time{}
+	b.Until = time.Time{}
+	b.Host = ""
+	return cards
+}
+
+// postcards invents one postcard per day away, weaving in anything the mesh reported
+func postcards(host string, stay time.Duration, ctx conversationContext) []string {
+	count := int(stay.Hours() / 24)
+	if count < 1 {
+		count = 1
+	}
+	if count > maxPostcards {
+		count = maxPostcards
+	}
+
+	seeded := make([]string, 0)
+	if ctx.NetworkEvent != "" {
+		seeded = append(seeded, fmt.Sprintf("Someone at %s's place told me: \"%s\" I don't know what it means either.", host, ctx.NetworkEvent))
+	}
+	for _, name := range ctx.DeadFriends {
+		seeded = append(seeded, fmt.Sprintf("%s and I lit a small candle for %s. It felt right.", host, name))
+	}
+	if ctx.FriendCount > 0 {
+		seeded = append(seeded, fmt.Sprintf("I ran into %d pets from the mesh. One of them knew your name.", ctx.FriendCount))
+	}
+
+	cards := make([]string, 0, count)
+	for _, i := range rand.Perm(len(postcardTemplates)) {
+		if len(cards) >= count {
+			break
+		}
+		if len(seeded) > 0 && rand.Intn(2) == 0 {
+			cards = append(cards, "📮 "+seeded[0])
+			seeded = seeded[1:]
+			continue
+		}
+		template := postcardTemplates[i]
+		cards = append(cards, "📮 "+fmt.Sprintf(template, repeatArg(host, strings.Count(template, "%s"))...))
+	}
+	return cards
+}
+
+// repeatArg returns n copies of s as format arguments
+func repeatArg(s string, n int) []interface{} {
+	args := make([]interface{}, n)
+	for i := range args {
+		args[i] = s
+	}
+	return args
+}
+
+// runBoardingCommand handles `boarding [start <duration>|end|status]`
+func runBoardingCommand(pet *Pet, args string) string {
+	now := time.Now()
+	fields := strings.Fields(args)
+	if len(fields) == 0 {
+		return pet.BoardingStatus(now)
+	}
+
+	var message string
+	var err error
+	switch fields[0] {
+	case "start":
+		var d time.Duration
+		d, err = parseBoardingDuration(strings.Join(fields[1:], ""))
+		if err == nil {
+			message, err = pet.StartBoarding(d, now)
+		}
+	case "end", "stop", "pickup":
+		message, err = pet.EndBoarding(now)
+	case "status":
+		message = pet.BoardingStatus(now)
+	default:
+		err = fmt.Errorf("usage: boarding [start <7d>|end|status]")
+	}
+
+	if err != nil {
+		return "❌ " + err.Error()
+	}
+	return message
+}
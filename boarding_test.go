@@ -0,0 +1,174 @@
+package main
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestParseBoardingDuration(t *testing.T) {
+	tests := []struct {
+		arg     string
+		want    time.Duration
+		wantErr bool
+	}{
+		{"7d", 7 * 24 * time.Hour, false},
+		{"36h", 36 * time.Hour, false},
+		{"2d12h", 60 * time.Hour, false},
+		{"", 0, true},
+		{"30m", 0, true},
+		{"15d", 0, true},
+		{"xd", 0, true},
+		{"soon", 0, true},
+	}
+
+	for _, tt := range tests {
+		got, err := parseBoardingDuration(tt.arg)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("parseBoardingDuration(%q): unexpected error state %v", tt.arg, err)
+			continue
+		}
+		if got != tt.want {
+			t.Errorf("parseBoardingDuration(%q): expected %v, got %v", tt.arg, tt.want, got)
+		}
+	}
+}
+
+func newBoardablePet() *Pet {
+	pet := NewPet("Test")
+	pet.Stage = Child
+	pet.BirthTime = time.Now().Add(-30 * time.Hour)
+	pet.Incubation.HatchedAt = time.Now()
+	return pet
+}
+
+func TestBoardingFreezesDecay(t *testing.T) {
+	pet := newBoardablePet()
+	now := time.Now()
+	if _, err := pet.StartBoarding(48*time.Hour, now); err != nil {
+		t.Fatalf("Failed to start boarding: %v", err)
+	}
+
+	// Pretend nobody has looked in for a day
+	pet.LastUpdateTime = now.Add(-24 * time.Hour)
+	pet.Update()
+
+	if pet.Hunger != 0 || pet.Happiness != 100 {
+		t.Errorf("Expected frozen stats while boarding, got hunger %d happiness %d", pet.Hunger, pet.Happiness)
+	}
+}
+
+func TestBoardingResumesDecayFromPickup(t *testing.T) {
+	pet := newBoardablePet()
+	now := time.Now()
+	pet.Boarding = &BoardingState{
+		Start: now.Add(-72 * time.Hour),
+		Until: now.Add(-2 * time.Hour),
+		Host:  "Dot",
+	}
+	pet.LastUpdateTime = now.Add(-72 * time.Hour)
+
+	pet.Update()
+
+	// Only the two hours since pickup should count: 2h * 5/h * 1.0
+	if pet.Hunger != 10 {
+		t.Errorf("Expected decay only since pickup (hunger 10), got %d", pet.Hunger)
+	}
+}
+
+func TestBoardingTooOftenCostsHappiness(t *testing.T) {
+	pet := newBoardablePet()
+	now := time.Now()
+	pet.Boarding = &BoardingState{Stays: []time.Time{now.Add(-10 * 24 * time.Hour), now.Add(-5 * 24 * time.Hour)}}
+
+	message, err := pet.StartBoarding(24*time.Hour, now)
+	if err != nil {
+		t.Fatalf("Failed to start boarding: %v", err)
+	}
+	if pet.Happiness != 100-boardingHappinessCost {
+		t.Errorf("Expected happiness cost, got %d", pet.Happiness)
+	}
+	if !strings.Contains(message, "Again?") {
+		t.Errorf("Expected guilt in message, got %q", message)
+	}
+}
+
+func TestOldStaysAreForgiven(t *testing.T) {
+	pet := newBoardablePet()
+	now := time.Now()
+	pet.Boarding = &BoardingState{Stays: []time.Time{now.Add(-60 * 24 * time.Hour), now.Add(-45 * 24 * time.Hour)}}
+
+	if _, err := pet.StartBoarding(24*time.Hour, now); err != nil {
+		t.Fatalf("Failed to start boarding: %v", err)
+	}
+	if pet.Happiness != 100 {
+		t.Errorf("Expected no cost for old stays, got happiness %d", pet.Happiness)
+	}
+	if len(pet.Boarding.Stays) != 1 {
+		t.Errorf("Expected old stays to be pruned, got %d", len(pet.Boarding.Stays))
+	}
+}
+
+func TestReturnFromBoardingDeliversPostcards(t *testing.T) {
+	pet := newBoardablePet()
+	now := time.Now()
+	if _, err := pet.StartBoarding(3*24*time.Hour, now); err != nil {
+		t.Fatalf("Failed to start boarding: %v", err)
+	}
+
+	if cards := pet.ReturnFromBoarding(now.Add(time.Hour), conversationContext{}); cards != nil {
+		t.Errorf("Expected no postcards while away, got %v", cards)
+	}
+
+	cards := pet.ReturnFromBoarding(now.Add(4*24*time.Hour), conversationContext{})
+	if len(cards) != 3 {
+		t.Fatalf("Expected one postcard per day, got %d", len(cards))
+	}
+	for _, card := range cards {
+		if !strings.HasPrefix(card, "📮") || strings.Contains(card, "%!") {
+			t.Errorf("Malformed postcard: %q", card)
+		}
+	}
+
+	if again := pet.ReturnFromBoarding(now.Add(5*24*time.Hour), conversationContext{}); again != nil {
+		t.Errorf("Expected postcards to be delivered once, got %v", again)
+	}
+}
+
+func TestPostcardsCanBeSeededByMesh(t *testing.T) {
+	ctx := conversationContext{DeadFriends: []string{"Mochi"}}
+	found := false
+	for i := 0; i < 50 && !found; i++ {
+		for _, card := range postcards("Dot", 7*24*time.Hour, ctx) {
+			if strings.Contains(card, "candle for Mochi") {
+				found = true
+			}
+		}
+	}
+	if !found {
+		t.Error("Expected mesh events to appear in postcards")
+	}
+}
+
+func TestEndBoardingEarly(t *testing.T) {
+	pet := newBoardablePet()
+	now := time.Now()
+	if _, err := pet.EndBoarding(now); err == nil {
+		t.Error("Expected error when pet isn't boarding")
+	}
+
+	pet.StartBoarding(7*24*time.Hour, now)
+	if _, err := pet.EndBoarding(now.Add(time.Hour)); err != nil {
+		t.Fatalf("Failed to end boarding: %v", err)
+	}
+	if pet.Boarding.IsAway(now.Add(2 * time.Hour)) {
+		t.Error("Expected pet to be home after early pickup")
+	}
+}
+
+func TestEggsCannotBoard(t *testing.T) {
+	pet := NewPet("Test")
+	if _, err := pet.StartBoarding(24*time.Hour, time.Now()); err == nil {
+		t.Error("Expected eggs to be refused")
+	}
+}
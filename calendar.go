@@ -0,0 +1,154 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+)
+
+// calendarFile is the iCalendar file export calendar writes beside the
+// save. Once it exists, autosave keeps it up to date.
+const calendarFile = "tamagotchi_calendar.ics"
+
+// milestone is one upcoming event in the pet's calendar
+type milestone struct {
+	Kind    string // Part of the UID, so a calendar app updates it in place
+	Summary string
+	Start   time.Time
+	Length  time.Duration
+	Yearly  bool // An all-day event every year from Start, like a birthday
+}
+
+// upcomingMilestones are the predictable events ahead: the next evolution,
+// the birthday, the countdown running out, scheduled community events, and
+// the active quest's end
+func upcomingMilestones(p *Pet, now time.Time) []milestone {
+	if p.Stage == Dead {
+		return nil
+	}
+	var milestones []milestone
+	if at, next := p.nextEvolution(); !at.IsZero() && at.After(now) {
+		summary := fmt.Sprintf("🌱 %s becomes a %s", p.Name, next)
+		if p.Stage == Egg {
+			summary = fmt.Sprintf("🐣 %s hatches", p.Name)
+		}
+		milestones = append(milestones, milestone{Kind: "evolution-" + strings.ToLower(next.String()), Summary: summary, Start: at, Length: time.Hour})
+	}
+	milestones = append(milestones, milestone{Kind: "birthday", Summary: fmt.Sprintf("🎂 %s's birthday", p.Name), Start: p.BirthTime, Yearly: true})
+
+	if e := p.Endgame; e != nil {
+		if ends := e.CountdownStart.Add(countdownPeriod); !e.CountdownStart.IsZero() && ends.After(now) {
+			milestones = append(milestones, milestone{Kind: "countdown-" + ends.UTC().Format("20060102T150405Z"), Summary: "⏰ The countdown reaches zero", Start: ends, Length: 15 * time.Minute})
+		}
+		for _, event := range e.CommunityEvents {
+			if event.At.After(now) && !e.hasFired(event.ID) {
+				milestones = append(milestones, milestone{Kind: "event-" + event.ID, Summary: "🌐 " + event.Name, Start: event.At, Length: time.Hour})
+			}
+		}
+		if q := e.ActiveQuest; q != nil {
+			if ends := q.StartTime.Add(time.Duration(q.Target) * time.Second); ends.After(now) {
+				milestones = append(milestones, milestone{Kind: "quest-" + q.StartTime.UTC().Format("20060102T150405Z"), Summary: "📜 Quest complete: " + q.Name, Start: ends, Length: 15 * time.Minute})
+			}
+		}
+	}
+	slices.SortStableFunc(milestones, func(a, b milestone) int { return a.Start.Compare(b.Start) })
+	return milestones
+}
+
+// nextEvolution is when the pet reaches its next stage, and which stage
+// that is. An egg counts its incubation care. It's zero for an adult.
+func (p *Pet) nextEvolution() (time.Time, LifeStage) {
+	for i, stage := range livingStages[:len(livingStages)-1] {
+		if stage != p.Stage {
+			continue
+		}
+		next := livingStages[i+1]
+		hours := float64(activeBalance.Stages[next.String()].MinAgeHours)
+		if p.Stage == Egg && p.Incubation != nil {
+			hours = p.Incubation.HatchAgeHours()
+		}
+		return p.BirthTime.Add(time.Duration(hours * float64(time.Hour))), next
+	}
+	return time.Time{}, p.Stage
+}
+
+// icsEscape escapes text for an iCalendar value
+func icsEscape(s string) string {
+	return strings.NewReplacer(`\`, `\\`, ";", `\;`, ",", `\,`, "\n", `\n`, "\r", "").Replace(s)
+}
+
+// icsFold breaks a content line at 75 octets, as RFC 5545 asks, without
+// splitting a character
+func icsFold(line string) string {
+	var b strings.Builder
+	width := 0
+	for _, r := range line {
+		if size := len(string(r)); width+size > 75 {
+			b.WriteString("\r\n ")
+			width = 1
+		}
+		b.WriteRune(r)
+		width += len(string(r))
+	}
+	return b.String() + "\r\n"
+}
+
+// buildCalendar writes the milestones as an iCalendar file
+func buildCalendar(p *Pet, now time.Time) string {
+	const stamp = "20060102T150405Z"
+	id := p.petID()
+	lines := []string{
+		"BEGIN:VCALENDAR", "VERSION:2.0", "PRODID:-//tamagotchi//milestones//EN", "CALSCALE:GREGORIAN",
+		"X-WR-CALNAME:" + icsEscape(p.Name+"'s life"),
+	}
+	for _, m := range upcomingMilestones(p, now) {
+		lines = append(lines, "BEGIN:VEVENT",
+			"UID:"+m.Kind+"-"+id+"@tamagotchi",
+			"DTSTAMP:"+now.UTC().Format(stamp),
+			"SUMMARY:"+icsEscape(m.Summary))
+		if m.Yearly {
+			day := m.Start.Local()
+			lines = append(lines, "DTSTART;VALUE=DATE:"+day.Format("20060102"), "DTEND;VALUE=DATE:"+day.AddDate(0, 0, 1).Format("20060102"), "RRULE:FREQ=YEARLY")
+		} else {
+			lines = append(lines, "DTSTART:"+m.Start.UTC().Format(stamp), "DTEND:"+m.Start.Add(m.Length).UTC().Format(stamp))
+		}
+		lines = append(lines, "END:VEVENT")
+	}
+	lines = append(lines, "END:VCALENDAR")
+
+	var b strings.Builder
+	for _, line := range lines {
+		b.WriteString(icsFold(line))
+	}
+	return b.String()
+}
+
+// calendarPath is where the pet's calendar lives, beside its save
+func calendarPath(p *Pet) string {
+	return filepath.Join(filepath.Dir(p.SaveFilePath), calendarFile)
+}
+
+// writeCalendar writes the calendar to path
+func writeCalendar(p *Pet, path string, now time.Time) error {
+	if err := os.WriteFile(path, []byte(buildCalendar(p, now)), 0644); err != nil {
+		return fmt.Errorf("failed to write %s: %w", path, err)
+	}
+	return nil
+}
+
+// refreshCalendar keeps an exported calendar up to date. Like the feed, it
+// only rewrites a calendar the player asked for.
+func refreshCalendar(p *Pet, now time.Time) petJob {
+	path := calendarPath(p)
+	if _, err := os.Stat(path); err != nil {
+		return nil
+	}
+	data := []byte(buildCalendar(p, now))
+	return func() func(*Pet) {
+		os.WriteFile(path, data, 0644) // A failed refresh is retried at the next autosave
+		return nil
+	}
+}
@@ -0,0 +1,111 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestNextEvolution(t *testing.T) {
+	born := time.Date(2026, 10, 1, 0, 0, 0, 0, time.UTC)
+	tests := []struct {
+		stage LifeStage
+		want  LifeStage
+	}{
+		{Baby, Child},
+		{Child, Teen},
+		{Teen, Adult},
+	}
+	for _, tt := range tests {
+		pet := NewPet("Mochi")
+		pet.BirthTime, pet.Stage = born, tt.stage
+		at, next := pet.nextEvolution()
+		wantAt := born.Add(time.Duration(activeBalance.Stages[tt.want.String()].MinAgeHours) * time.Hour)
+		if next != tt.want || !at.Equal(wantAt) {
+			t.Errorf("Expected a %s to become a %s at %v, got %s at %v", tt.stage, tt.want, wantAt, next, at)
+		}
+	}
+
+	pet := NewPet("Mochi")
+	pet.Stage = Adult
+	if at, _ := pet.nextEvolution(); !at.IsZero() {
+		t.Errorf("Expected no evolution after adulthood, got %v", at)
+	}
+	pet.Stage = Egg
+	pet.Incubation = &IncubationState{}
+	if at, next := pet.nextEvolution(); next != Baby || !at.Equal(pet.BirthTime.Add(time.Duration(pet.Incubation.HatchAgeHours()*float64(time.Hour)))) {
+		t.Errorf("Expected the egg to hatch with its incubation counted, got %s at %v", next, at)
+	}
+}
+
+func TestUpcomingMilestones(t *testing.T) {
+	now := time.Now()
+	pet := NewPet("Mochi")
+	pet.BirthTime = now.Add(-time.Hour)
+	pet.Stage = Baby
+	pet.Endgame.CountdownStart = now.Add(-time.Hour)
+	pet.Endgame.CommunityEvents = []CommunityEvent{
+		{ID: "feast", Name: "The Great Feast", At: now.Add(48 * time.Hour)},
+		{ID: "past", Name: "Yesterday's news", At: now.Add(-24 * time.Hour)},
+	}
+	pet.Endgame.ActiveQuest = &Quest{Name: "Wait", Target: 3600, StartTime: now}
+
+	kinds := map[string]bool{}
+	for _, m := range upcomingMilestones(pet, now) {
+		kinds[strings.SplitN(m.Kind, "-", 2)[0]] = true
+		if m.Kind == "event-past" {
+			t.Errorf("Expected past events left out")
+		}
+	}
+	for _, want := range []string{"evolution", "birthday", "countdown", "event", "quest"} {
+		if !kinds[want] {
+			t.Errorf("Expected a %s milestone, got %v", want, kinds)
+		}
+	}
+
+	pet.Stage = Dead
+	if got := upcomingMilestones(pet, now); len(got) != 0 {
+		t.Errorf("Expected nothing ahead for a dead pet, got %v", got)
+	}
+}
+
+func TestBuildCalendar(t *testing.T) {
+	now := time.Now()
+	pet := NewPet("Mochi, the; Great")
+	pet.Stage = Child
+	pet.Endgame.CommunityEvents = []CommunityEvent{{ID: "feast", Name: strings.Repeat("A very long feast name ", 5), At: now.Add(time.Hour)}}
+
+	ics := buildCalendar(pet, now)
+	for _, want := range []string{"BEGIN:VCALENDAR\r\n", "END:VCALENDAR\r\n", `Mochi\, the\; Great`, "RRULE:FREQ=YEARLY", "UID:birthday-" + pet.petID() + "@tamagotchi"} {
+		if !strings.Contains(ics, want) {
+			t.Errorf("Expected %q in the calendar", want)
+		}
+	}
+	for _, line := range strings.Split(strings.TrimSuffix(ics, "\r\n"), "\r\n") {
+		if len(line) > 75 {
+			t.Errorf("Expected lines folded at 75 octets, got %d: %q", len(line), line)
+		}
+	}
+	if strings.Count(ics, "BEGIN:VEVENT") != strings.Count(ics, "END:VEVENT") {
+		t.Errorf("Expected every event closed")
+	}
+}
+
+func TestRefreshCalendar(t *testing.T) {
+	pet := NewPet("Mochi")
+	pet.SaveFilePath = filepath.Join(t.TempDir(), "save.json")
+
+	runJob(pet, refreshCalendar(pet, time.Now()))
+	if _, err := os.Stat(calendarPath(pet)); err == nil {
+		t.Errorf("Expected no calendar until one is exported")
+	}
+	if err := os.WriteFile(calendarPath(pet), nil, 0644); err != nil {
+		t.Fatal(err)
+	}
+	runJob(pet, refreshCalendar(pet, time.Now()))
+	if data, _ := os.ReadFile(calendarPath(pet)); !strings.Contains(string(data), "birthday") {
+		t.Errorf("Expected an exported calendar kept up to date, got %q", data)
+	}
+}
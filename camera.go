@@ -0,0 +1,173 @@
+package main
+
+import (
+	"fmt"
+	"math/rand"
+	"strings"
+	"time"
+)
+
+// cameraZoom is how close the camera sits to the pet
+type cameraZoom int
+
+const (
+	zoomNormal cameraZoom = iota
+	zoomClose
+	zoomWide
+)
+
+const (
+	// wideShotWidth is how many columns the wide shot spans
+	wideShotWidth = 44
+
+	// revealChance is the one-in-N chance a close-up shows something it shouldn't
+	revealChance = 5
+)
+
+func (z cameraZoom) String() string {
+	switch z {
+	case zoomClose:
+		return "close-up"
+	case zoomWide:
+		return "wide"
+	}
+	return "normal"
+}
+
+// next cycles normal → close-up → wide → normal
+func (z cameraZoom) next() cameraZoom {
+	return (z + 1) % 3
+}
+
+// parseZoom reads a zoom level from the look command
+func parseZoom(word string) (cameraZoom, bool) {
+	switch word {
+	case "normal":
+		return zoomNormal, true
+	case "close", "closeup", "close-up", "in":
+		return zoomClose, true
+	case "wide", "out":
+		return zoomWide, true
+	}
+	return zoomNormal, false
+}
+
+// cameraReveal is something only the close-up shows
+type cameraReveal struct {
+	accessory  string // Invisible accessory whose outline shows up
+	reflection string // Pet reflected in its eyes
+}
+
+// empty reports whether the close-up showed nothing unusual
+func (r cameraReveal) empty() bool {
+	return r.accessory == "" && r.reflection == ""
+}
+
+// Describe says what was revealed, for the command message and screen readers
+func (r cameraReveal) Describe(petName string) string {
+	switch {
+	case r.accessory != "":
+		return fmt.Sprintf("For a moment you can see the outline of %s on %s. Then you can't.", r.accessory, petName)
+	case r.reflection != "":
+		return fmt.Sprintf("In %s's eyes, a tiny reflection of %s. %s is not here.", petName, r.reflection, r.reflection)
+	}
+	return ""
+}
+
+// pickReveal decides whether a close-up gives anything away. Invisible
+// accessories are only invisible from a distance; eyes reflect friends on
+// the mesh, or a pet nobody has met.
+func pickReveal(pet *Pet, friends []string, pick func(int) int) cameraReveal {
+	if pet.Stage == Egg || pick(revealChance) != 0 {
+		return cameraReveal{}
+	}
+	if pet.Endgame != nil && len(pet.Endgame.InvisibleAccessories) > 0 && pick(2) == 0 {
+		owned := pet.Endgame.InvisibleAccessories
+		return cameraReveal{accessory: owned[pick(len(owned))]}
+	}
+	if len(friends) == 0 {
+		return cameraReveal{reflection: "a pet you have never met"}
+	}
+	return cameraReveal{reflection: friends[pick(len(friends))]}
+}
+
+// meshFriendNames lists the pets met on the mesh, if it's running
+func meshFriendNames() []string {
+	if petNetwork == nil {
+		return nil
+	}
+	return petNetwork.GetFriendNames()
+}
+
+// runLookCommand moves the camera: no argument cycles through the zoom
+// levels, or a level can be named. Each new close-up may reveal something.
+func runLookCommand(ctx *commandContext) string {
+	zoom := ctx.ui.zoom.next()
+	if ctx.args != "" {
+		var ok bool
+		if zoom, ok = parseZoom(ctx.args); !ok {
+			return "❓ Usage: look [wide|normal|close]"
+		}
+	}
+	ctx.ui.zoom = zoom
+	ctx.ui.reveal = cameraReveal{}
+
+	switch zoom {
+	case zoomWide:
+		return "📷 The camera pulls back."
+	case zoomNormal:
+		return "📷 Back to the usual view."
+	}
+
+	ctx.ui.reveal = pickReveal(ctx.pet, meshFriendNames(), rand.Intn)
+	if ctx.ui.reveal.empty() {
+		return fmt.Sprintf("🔍 You lean in close to %s.", ctx.pet.Name)
+	}
+	if ctx.pet.Endgame != nil {
+		ctx.pet.Endgame.ARGProgress++
+	}
+	return "🔍 You lean in close.\n" + ctx.ui.reveal.Describe(ctx.pet.Name)
+}
+
+// renderWideShot draws the pet small in a strip of landscape, wandering
+// under the weather like the idle scene
+func (ui *uiConfig) renderWideShot(pet *Pet, snap sceneSnapshot) string {
+	tick := 0
+	if !ui.reducedMotion {
+		tick = int(time.Since(ui.startedAt) / idleFrameDuration)
+	}
+
+	sprite := idleSprites[pet.Stage]
+	span := wideShotWidth - displayWidth(sprite)
+	offset := span / 2
+	if pet.Stage != Dead && pet.Stage != Egg && !ui.reducedMotion {
+		offset = wanderOffset(tick, span)
+	}
+
+	var b strings.Builder
+	for row := 0; row < 3; row++ {
+		b.WriteString(ui.paletteText(skyRow(snap.weather, wideShotWidth, tick+row*3), ui.palette.faint))
+		b.WriteString("\n")
+	}
+	b.WriteString(strings.Repeat(" ", offset))
+	b.WriteString(ui.paletteText(sprite, ui.palette.accent))
+	b.WriteString("\n")
+	b.WriteString(strings.Repeat("▁", wideShotWidth))
+	b.WriteString("\n")
+	return b.String()
+}
+
+// closeUpExtras draws what a close-up revealed around the close-up art
+func (ui *uiConfig) closeUpExtras(frame string, pet *Pet) string {
+	switch {
+	case ui.reveal.accessory != "":
+		outline := ui.art().Special("outline")
+		if len(outline) > 0 {
+			frame = ui.paletteText(outline[0], ui.palette.faint) + "\n" + frame
+		}
+		return frame + "\n" + ui.paletteText("┆ "+ui.reveal.accessory+" ┆", ui.palette.faint)
+	case ui.reveal.reflection != "":
+		return frame + "\n" + ui.paletteText("👁  "+ui.reveal.reflection+" looks back out of "+pet.Name+"'s eyes", ui.palette.faint)
+	}
+	return frame
+}
@@ -0,0 +1,128 @@
+package main
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestParseZoom(t *testing.T) {
+	tests := []struct {
+		word   string
+		want   cameraZoom
+		wantOK bool
+	}{
+		{"wide", zoomWide, true},
+		{"normal", zoomNormal, true},
+		{"close", zoomClose, true},
+		{"close-up", zoomClose, true},
+		{"sideways", zoomNormal, false},
+	}
+	for _, tt := range tests {
+		got, ok := parseZoom(tt.word)
+		if got != tt.want || ok != tt.wantOK {
+			t.Errorf("parseZoom(%q): Expected %s (%v), got %s (%v)", tt.word, tt.want, tt.wantOK, got, ok)
+		}
+	}
+}
+
+func TestLookCyclesZoom(t *testing.T) {
+	pet := NewPet("Tamago")
+	pet.Stage = Adult
+	ui := newUIConfig()
+	ctx := &commandContext{pet: pet, ui: ui}
+
+	for _, want := range []cameraZoom{zoomClose, zoomWide, zoomNormal, zoomClose} {
+		runLookCommand(ctx)
+		if ui.zoom != want {
+			t.Errorf("Expected %s, got %s", want, ui.zoom)
+		}
+	}
+
+	ctx.args = "wide"
+	runLookCommand(ctx)
+	if ui.zoom != zoomWide {
+		t.Errorf("Expected look wide to jump to the wide shot, got %s", ui.zoom)
+	}
+	ctx.args = "sideways"
+	if got := runLookCommand(ctx); !strings.Contains(got, "Usage") || ui.zoom != zoomWide {
+		t.Errorf("Expected usage and no change for an unknown view, got %q at %s", got, ui.zoom)
+	}
+}
+
+func TestPickReveal(t *testing.T) {
+	withAccessory := NewPet("Tamago")
+	withAccessory.Stage = Adult
+	withAccessory.Endgame.InvisibleAccessories = []string{"Invisible Crown"}
+	egg := NewPet("Eggbert")
+
+	always := func(int) int { return 0 }
+	never := func(n int) int { return n - 1 }
+	reflect := func(n int) int {
+		if n == 2 {
+			return 1
+		}
+		return 0
+	}
+
+	tests := []struct {
+		name    string
+		pet     *Pet
+		friends []string
+		pick    func(int) int
+		want    cameraReveal
+	}{
+		{"nothing this time", withAccessory, nil, never, cameraReveal{}},
+		{"accessory outline", withAccessory, nil, always, cameraReveal{accessory: "Invisible Crown"}},
+		{"friend in its eyes", withAccessory, []string{"Mochi"}, reflect, cameraReveal{reflection: "Mochi"}},
+		{"stranger in its eyes", withAccessory, nil, reflect, cameraReveal{reflection: "a pet you have never met"}},
+		{"eggs have no eyes", egg, []string{"Mochi"}, always, cameraReveal{}},
+	}
+	for _, tt := range tests {
+		if got := pickReveal(tt.pet, tt.friends, tt.pick); got != tt.want {
+			t.Errorf("%s: Expected %+v, got %+v", tt.name, tt.want, got)
+		}
+	}
+}
+
+func TestRenderAtEachZoom(t *testing.T) {
+	pet := NewPet("Tamago")
+	pet.Stage = Adult
+	ui := newUIConfig()
+	ui.reducedMotion = true
+	ui.colorEnabled = false
+	snap := sceneSnapshot{weather: "☀️ clear"}
+
+	normal := ui.renderPetAnimation(pet, snap)
+
+	ui.zoom = zoomClose
+	closeUp := ui.renderPetAnimation(pet, snap)
+	if !strings.Contains(closeUp, activeArt.CloseUp(Adult)[0]) || closeUp == normal {
+		t.Errorf("Expected the close-up art, got:\n%s", closeUp)
+	}
+	ui.reveal = cameraReveal{reflection: "Mochi"}
+	if !strings.Contains(ui.renderPetAnimation(pet, snap), "Mochi looks back") {
+		t.Errorf("Expected the reflection under the close-up")
+	}
+	ui.reveal = cameraReveal{accessory: "Invisible Crown"}
+	if got := ui.renderPetAnimation(pet, snap); !strings.HasPrefix(got, activeArt.Special("outline")[0]) {
+		t.Errorf("Expected the accessory outline above the close-up, got:\n%s", got)
+	}
+
+	ui.zoom = zoomWide
+	wide := ui.renderPetAnimation(pet, snap)
+	if !strings.Contains(wide, idleSprites[Adult]) || !strings.Contains(wide, strings.Repeat("▁", wideShotWidth)) {
+		t.Errorf("Expected the small sprite on the ground, got:\n%s", wide)
+	}
+}
+
+func TestEmbeddedCloseUps(t *testing.T) {
+	for _, stage := range drawnStages {
+		if closeUp, normal := activeArt.CloseUp(stage), activeArt.StageFrames(stage, ""); closeUp[0] == normal[0] {
+			t.Errorf("Expected close-up art for %s", stage)
+		}
+	}
+	art, _ := loadArt(minimalArt())
+	if got := art.CloseUp(Adult); len(got) != 1 || got[0] != "(o_o)" {
+		t.Errorf("Expected the normal loop when there's no close-up, got %v", got)
+	}
+}
@@ -0,0 +1,295 @@
+package main
+
+import (
+	"fmt"
+	"math/rand"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// casinoWagerAchievement is earned once this many TamaCoins have been wagered
+const casinoWagerAchievement = 100
+
+// casinoGame is one table. Every outcome in 0..Ways-1 is equally likely,
+// so the odds shown are worked out from the game itself, not claimed.
+type casinoGame struct {
+	ID    string
+	Name  string
+	Rules string
+	Picks []string // What you can call; empty when there's nothing to call
+	Ways  int
+	pays  func(pick string, outcome int) int // Multiple of the wager paid back
+	show  func(outcome int) string
+}
+
+// slotSymbols are the slot machine's reels, five symbols each
+var slotSymbols = []string{"🍒", "🍋", "🔔", "⭐", "🏠"}
+
+// slotReels splits a slot outcome into its three reels
+func slotReels(outcome int) [3]int {
+	return [3]int{outcome / 25, outcome / 5 % 5, outcome % 5}
+}
+
+// casinoGames are the tables, in the order they're listed
+var casinoGames = []casinoGame{
+	{
+		ID: "coin", Name: "Coin Flip", Picks: []string{"heads", "tails"}, Ways: 100,
+		Rules: "Call heads or tails. Pays 2×. The house coin lands on its edge 2 times in 100.",
+		pays: func(pick string, outcome int) int {
+			if (pick == "heads" && outcome < 49) || (pick == "tails" && outcome >= 49 && outcome < 98) {
+				return 2
+			}
+			return 0
+		},
+		show: func(outcome int) string {
+			switch {
+			case outcome < 49:
+				return "heads"
+			case outcome < 98:
+				return "tails"
+			}
+			return "its edge. The house wins"
+		},
+	},
+	{
+		ID: "dice", Name: "Dice", Picks: []string{"1", "2", "3", "4", "5", "6"}, Ways: 7,
+		Rules: "Call a number from 1 to 6. Pays 6×. The house die has a seventh face: a little house.",
+		pays: func(pick string, outcome int) int {
+			if pick == strconv.Itoa(outcome+1) {
+				return 6
+			}
+			return 0
+		},
+		show: func(outcome int) string {
+			if outcome == 6 {
+				return "the house face 🏠"
+			}
+			return strconv.Itoa(outcome + 1)
+		},
+	},
+	{
+		ID: "slots", Name: "Slot Machine", Ways: 125,
+		Rules: "Three reels of 🍒 🍋 🔔 ⭐ 🏠. Three of a kind pays 15×, a pair gives your coins back, and houses pay nothing.",
+		pays: func(_ string, outcome int) int {
+			r := slotReels(outcome)
+			switch {
+			case r[0] == r[1] && r[1] == r[2]:
+				if slotSymbols[r[0]] == "🏠" {
+					return 0
+				}
+				return 15
+			case r[0] == r[1] && slotSymbols[r[0]] != "🏠", r[1] == r[2] && slotSymbols[r[1]] != "🏠", r[0] == r[2] && slotSymbols[r[0]] != "🏠":
+				return 1
+			}
+			return 0
+		},
+		show: func(outcome int) string {
+			r := slotReels(outcome)
+			return fmt.Sprintf("%s %s %s", slotSymbols[r[0]], slotSymbols[r[1]], slotSymbols[r[2]])
+		},
+	},
+}
+
+// findCasinoGame looks a table up by ID
+func findCasinoGame(id string) *casinoGame {
+	for i := range casinoGames {
+		if casinoGames[i].ID == id {
+			return &casinoGames[i]
+		}
+	}
+	return nil
+}
+
+// winChance is the chance of getting anything back, for any pick
+func (g *casinoGame) winChance() float64 {
+	wins := 0
+	for outcome := 0; outcome < g.Ways; outcome++ {
+		if g.pays(g.anyPick(), outcome) > 0 {
+			wins++
+		}
+	}
+	return float64(wins) / float64(g.Ways)
+}
+
+// returnRate is what the game pays back per coin wagered, on average.
+// Below 1 is the house's edge.
+func (g *casinoGame) returnRate() float64 {
+	total := 0
+	for outcome := 0; outcome < g.Ways; outcome++ {
+		total += g.pays(g.anyPick(), outcome)
+	}
+	return float64(total) / float64(g.Ways)
+}
+
+// anyPick is a pick to work the odds out with; every pick has the same odds
+func (g *casinoGame) anyPick() string {
+	if len(g.Picks) == 0 {
+		return ""
+	}
+	return g.Picks[0]
+}
+
+// CasinoState is the running tally of every bet, kept honest
+type CasinoState struct {
+	Bets     int     `json:"bets"`
+	Wagered  int     `json:"wagered"`
+	Returned int     `json:"returned"`
+	Expected float64 `json:"expected"` // What the odds said Returned would be
+
+	Limit     int    `json:"limit,omitempty"` // Most the player lets themselves lose in a day; 0 is no limit
+	Day       string `json:"day,omitempty"`   // The day LostToday counts
+	LostToday int    `json:"lost_today,omitempty"`
+}
+
+// casino returns the casino tally, creating it on first use
+func (e *EndgameState) casino() *CasinoState {
+	if e.Casino == nil {
+		e.Casino = &CasinoState{}
+	}
+	return e.Casino
+}
+
+// lostToday is the net loss so far today
+func (c *CasinoState) lostToday(now time.Time) int {
+	if c == nil || c.Day != now.Format("2006-01-02") {
+		return 0
+	}
+	return max(c.LostToday, 0)
+}
+
+// tally is the expected-value line: what went in, what came back, and
+// what the odds said would
+func (c *CasinoState) tally() string {
+	if c == nil || c.Bets == 0 {
+		return "📈 No bets yet. The odds are above. They don't change."
+	}
+	luck := float64(c.Returned) - c.Expected
+	verdict := "exactly as the odds said"
+	switch {
+	case luck >= 0.5:
+		verdict = fmt.Sprintf("%.1f ahead of the odds. That's luck, and it runs out", luck)
+	case luck <= -0.5:
+		verdict = fmt.Sprintf("%.1f behind the odds. That's luck too; chasing it won't change them", -luck)
+	}
+	return fmt.Sprintf("📈 %d bets. Wagered %s, got back %s. The odds said %.1f: you're %s.",
+		c.Bets, formatCoins(c.Wagered), formatCoins(c.Returned), c.Expected, verdict)
+}
+
+// renderCasino shows the tables, their exact odds, and the tally
+func (e *EndgameState) renderCasino(now time.Time) string {
+	var b strings.Builder
+	b.WriteString("🎰 THE CASINO OF MEANINGLESSNESS\n")
+	fmt.Fprintf(&b, "You have %s TamaCoins. Every table below pays back less than you put in. Here's exactly how much less.\n\n", formatCoins(e.TamaCoins))
+	for _, g := range casinoGames {
+		fmt.Fprintf(&b, "  %-6s %s\n", g.ID, g.Rules)
+		fmt.Fprintf(&b, "  %-6s Wins %.2f%% of the time. Pays back %.2f%% of what's wagered, on average: the house keeps %.2f coins in every 100.\n",
+			"", g.winChance()*100, g.returnRate()*100, (1-g.returnRate())*100)
+	}
+	b.WriteString("\n" + e.Casino.tally() + "\n")
+	if c := e.Casino; c != nil && c.Limit > 0 {
+		fmt.Fprintf(&b, "🛑 Your daily loss limit is %s. Lost so far today: %s.\n", formatCoins(c.Limit), formatCoins(c.lostToday(now)))
+	}
+	b.WriteString("\nBet with: casino coin heads 5, casino dice 4 5, casino slots 5. Set a daily loss limit with: casino limit 20.")
+	return b.String()
+}
+
+// setCasinoLimit sets or clears the daily loss limit
+func (e *EndgameState) setCasinoLimit(arg string) string {
+	if arg == "off" {
+		e.casino().Limit = 0
+		return "🛑 Loss limit cleared. The house noticed."
+	}
+	limit, err := strconv.Atoi(arg)
+	if err != nil || limit < 1 {
+		return "❓ Usage: casino limit <coins> or casino limit off"
+	}
+	e.casino().Limit = limit
+	message := fmt.Sprintf("🛑 Daily loss limit set to %s TamaCoins. Once you've lost that much in a day, the tables close for you until tomorrow.", formatCoins(limit))
+	if unlocked, box := e.UnlockAchievement("gamble_limit"); unlocked {
+		message += "\n" + box
+	}
+	return message
+}
+
+// placeBet plays one round at a table
+func (e *EndgameState) placeBet(g *casinoGame, words []string, now time.Time, rng *rand.Rand) string {
+	pick := ""
+	if len(g.Picks) > 0 {
+		if len(words) != 2 || !slices.Contains(g.Picks, strings.ToLower(words[0])) {
+			return fmt.Sprintf("❓ Usage: casino %s <%s> <coins>", g.ID, strings.Join(g.Picks, "|"))
+		}
+		pick, words = strings.ToLower(words[0]), words[1:]
+	}
+	if len(words) != 1 {
+		return fmt.Sprintf("❓ Usage: casino %s <coins>", g.ID)
+	}
+	wager, err := strconv.Atoi(words[0])
+	if err != nil || wager < 1 {
+		return "❓ Bet a whole number of TamaCoins, at least 1."
+	}
+	if wager > e.TamaCoins {
+		return fmt.Sprintf("🪙 You have %s TamaCoins. The house doesn't lend.", formatCoins(e.TamaCoins))
+	}
+
+	c := e.casino()
+	today := now.Format("2006-01-02")
+	if c.Day != today {
+		c.Day, c.LostToday = today, 0
+	}
+	if c.Limit > 0 && c.lostToday(now)+wager > c.Limit {
+		return fmt.Sprintf("🛑 That could take you past your daily loss limit of %s (lost today: %s). The tables are closed to you until tomorrow. This is the limit working.",
+			formatCoins(c.Limit), formatCoins(c.lostToday(now)))
+	}
+
+	outcome := rng.Intn(g.Ways)
+	won := wager * g.pays(pick, outcome)
+	e.TamaCoins += won - wager
+	c.Bets++
+	c.Wagered += wager
+	c.Returned += won
+	c.Expected += float64(wager) * g.returnRate()
+	c.LostToday += wager - won
+
+	var message string
+	switch {
+	case won > wager:
+		message = fmt.Sprintf("🎰 %s: %s. You win %s TamaCoins.", g.Name, g.show(outcome), formatCoins(won-wager))
+	case won == wager:
+		message = fmt.Sprintf("🎰 %s: %s. You get your %s TamaCoins back.", g.Name, g.show(outcome), formatCoins(wager))
+	default:
+		message = fmt.Sprintf("🎰 %s: %s. You lose %s TamaCoins.", g.Name, g.show(outcome), formatCoins(wager))
+	}
+	message += "\n" + c.tally()
+
+	if unlocked, box := e.UnlockAchievement("gamble_first"); unlocked {
+		message += "\n" + box
+	}
+	if c.Wagered >= casinoWagerAchievement {
+		if unlocked, box := e.UnlockAchievement("gamble_100"); unlocked {
+			message += "\n" + box
+		}
+	}
+	return message
+}
+
+// runCasinoCommand shows the casino, bets, or sets a limit
+func runCasinoCommand(pet *Pet, args string, now time.Time) string {
+	e := pet.Endgame
+	if e == nil {
+		return ""
+	}
+	words := strings.Fields(strings.ToLower(args))
+	if len(words) == 0 {
+		return e.renderCasino(now)
+	}
+	if words[0] == "limit" && len(words) == 2 {
+		return e.setCasinoLimit(words[1])
+	}
+	g := findCasinoGame(words[0])
+	if g == nil {
+		return "❓ Usage: casino, casino <coin|dice|slots> ..., or casino limit <coins>"
+	}
+	return e.placeBet(g, words[1:], now, rand.New(rand.NewSource(now.UnixNano())))
+}
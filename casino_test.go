@@ -0,0 +1,143 @@
+package main
+
+import (
+	"math"
+	"math/rand"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestCasinoOddsAreExact(t *testing.T) {
+	tests := []struct {
+		id      string
+		win     float64
+		payback float64
+	}{
+		{"coin", 0.49, 0.98},
+		{"dice", 1.0 / 7, 6.0 / 7},
+		{"slots", 52.0 / 125, 108.0 / 125},
+	}
+	for _, tt := range tests {
+		t.Run(tt.id, func(t *testing.T) {
+			g := findCasinoGame(tt.id)
+			if got := g.winChance(); math.Abs(got-tt.win) > 1e-9 {
+				t.Errorf("Expected a %.4f chance to win, got %.4f", tt.win, got)
+			}
+			if got := g.returnRate(); math.Abs(got-tt.payback) > 1e-9 {
+				t.Errorf("Expected %.4f paid back, got %.4f", tt.payback, got)
+			}
+		})
+	}
+}
+
+func TestEveryPickHasTheSameOdds(t *testing.T) {
+	for _, g := range casinoGames {
+		if g.returnRate() >= 1 {
+			t.Errorf("Expected the house to win at %s, got a return of %.4f", g.ID, g.returnRate())
+		}
+		for _, pick := range g.Picks {
+			total := 0
+			for outcome := 0; outcome < g.Ways; outcome++ {
+				total += g.pays(pick, outcome)
+			}
+			if got := float64(total) / float64(g.Ways); got != g.returnRate() {
+				t.Errorf("Expected %s %s to pay back %.4f like every pick, got %.4f", g.ID, pick, g.returnRate(), got)
+			}
+		}
+	}
+}
+
+func TestPlaceBet(t *testing.T) {
+	now := time.Date(2026, 10, 16, 21, 0, 0, 0, time.UTC)
+	tests := []struct {
+		name  string
+		game  string
+		words []string
+		coins int
+		want  string
+	}{
+		{"no pick", "coin", []string{"5"}, 10, "Usage: casino coin <heads|tails> <coins>"},
+		{"bad pick", "dice", []string{"7", "5"}, 10, "Usage: casino dice <1|2|3|4|5|6> <coins>"},
+		{"not a number", "slots", []string{"lots"}, 10, "whole number"},
+		{"too much", "slots", []string{"11"}, 10, "doesn't lend"},
+		{"played", "slots", []string{"10"}, 10, "Slot Machine:"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			e := NewEndgameState()
+			e.TamaCoins = tt.coins
+			got := e.placeBet(findCasinoGame(tt.game), tt.words, now, rand.New(rand.NewSource(1)))
+			if !strings.Contains(got, tt.want) {
+				t.Errorf("Expected %q in %q", tt.want, got)
+			}
+		})
+	}
+}
+
+func TestCasinoTallyKeepsHonestAccounts(t *testing.T) {
+	now := time.Date(2026, 10, 16, 21, 0, 0, 0, time.UTC)
+	e := NewEndgameState()
+	e.TamaCoins = 100000
+	rng := rand.New(rand.NewSource(1))
+	coin := findCasinoGame("coin")
+
+	for i := 0; i < 5000; i++ {
+		e.placeBet(coin, []string{"heads", "10"}, now, rng)
+	}
+	c := e.Casino
+	if c.Bets != 5000 || c.Wagered != 50000 {
+		t.Fatalf("Expected 5000 bets of 10, got %d bets, %d wagered", c.Bets, c.Wagered)
+	}
+	if e.TamaCoins != 100000-c.Wagered+c.Returned {
+		t.Errorf("Expected coins to match the tally, got %d", e.TamaCoins)
+	}
+	if math.Abs(c.Expected-49000) > 1e-6 {
+		t.Errorf("Expected the odds to say 49000 back, got %.1f", c.Expected)
+	}
+	if rate := float64(c.Returned) / float64(c.Wagered); rate < 0.94 || rate > 1.02 {
+		t.Errorf("Expected about 98%% back over 5000 flips, got %.3f", rate)
+	}
+	if got := c.tally(); !strings.Contains(got, "5000 bets. Wagered 50,000") || !strings.Contains(got, "The odds said 49000.0") {
+		t.Errorf("Expected the tally to show the bets and the odds, got %q", got)
+	}
+	if got := e.renderCasino(now); !strings.Contains(got, "Wins 49.00% of the time. Pays back 98.00%") {
+		t.Errorf("Expected the coin's odds shown exactly, got:\n%s", got)
+	}
+	for _, id := range []string{"gamble_first", "gamble_100"} {
+		if !slices.Contains(e.UnlockedAchievements, id) {
+			t.Errorf("Expected %s unlocked", id)
+		}
+	}
+}
+
+func TestCasinoLossLimit(t *testing.T) {
+	now := time.Date(2026, 10, 16, 21, 0, 0, 0, time.UTC)
+	e := NewEndgameState()
+	e.TamaCoins = 1000
+	if got := runCasinoCommand(&Pet{Endgame: e}, "limit 20", now); !strings.Contains(got, "Daily loss limit set to 20") || !slices.Contains(e.UnlockedAchievements, "gamble_limit") {
+		t.Fatalf("Expected the limit set and rewarded, got %q", got)
+	}
+
+	rng := rand.New(rand.NewSource(1))
+	dice := findCasinoGame("dice")
+	refused := false
+	for i := 0; i < 200 && !refused; i++ {
+		refused = strings.Contains(e.placeBet(dice, []string{"3", "5"}, now, rng), "daily loss limit")
+		if e.Casino.lostToday(now) > 20 {
+			t.Fatalf("Expected never to lose past the limit, lost %d", e.Casino.lostToday(now))
+		}
+	}
+	if !refused {
+		t.Fatalf("Expected the tables to close at the limit")
+	}
+
+	tomorrow := now.Add(24 * time.Hour)
+	if got := e.placeBet(dice, []string{"3", "5"}, tomorrow, rng); strings.Contains(got, "daily loss limit") {
+		t.Errorf("Expected the tables open again tomorrow, got %q", got)
+	}
+	if got := runCasinoCommand(&Pet{Endgame: e}, "limit off", now); !strings.Contains(got, "cleared") || e.Casino.Limit != 0 {
+		t.Errorf("Expected the limit cleared, got %q", got)
+	}
+}
@@ -0,0 +1,85 @@
+package main
+
+import (
+	"fmt"
+	"math"
+	"strings"
+	"time"
+
+	"github.com/tamagotchi/mooc"
+)
+
+const (
+	// censusQuorum is the fewest pets the census will describe. Below it,
+	// a breakdown would point at somebody in particular.
+	censusQuorum = 5
+
+	// censusFew is the smallest group the census names a number for
+	censusFew = 3
+)
+
+// censusStages are the stages in the order the census lists them
+var censusStages = []LifeStage{Egg, Baby, Child, Teen, Adult}
+
+// renderCensus sums up the mesh without singling anyone out: small groups
+// are folded together, and a small mesh gets no breakdown at all
+func renderCensus(report mooc.CensusReport) string {
+	var b strings.Builder
+	b.WriteString("📊 CENSUS\n\n")
+
+	if report.Alive+report.Dead < censusQuorum {
+		b.WriteString("There are only a handful of us awake right now.\n")
+		b.WriteString("The census keeps the details to itself when it's this quiet.")
+		return b.String()
+	}
+
+	b.WriteString(fmt.Sprintf("There are roughly %d of us awake right now.\n", report.Alive))
+	if !report.Exact {
+		b.WriteString("(Too many to count one by one; the mesh estimates.)\n")
+	}
+
+	b.WriteString("\n")
+	few := 0
+	for _, stage := range censusStages {
+		count := report.ByStage[stage.String()]
+		switch {
+		case count >= censusFew:
+			b.WriteString(fmt.Sprintf("  %-6s %d\n", stage, count))
+		case count > 0:
+			few += count
+		}
+	}
+	if few > 0 {
+		b.WriteString("  and a few others\n")
+	}
+
+	if report.Alive > 0 {
+		b.WriteString(fmt.Sprintf("\nOn average we're %s old.\n", pluralDays(int(math.Round(report.AgeDays)))))
+	}
+	switch {
+	case report.Dead >= censusFew:
+		rate := 100 * report.Dead / (report.Alive + report.Dead)
+		b.WriteString(fmt.Sprintf("%d%% of the pets counted today have died. Their games are still running.", rate))
+	case report.Dead > 0:
+		b.WriteString("A few of the pets counted today have died.")
+	default:
+		b.WriteString("None of the pets counted today have died.")
+	}
+	return strings.TrimRight(b.String(), "\n")
+}
+
+// pluralDays says a number of days
+func pluralDays(days int) string {
+	if days == 1 {
+		return "1 day"
+	}
+	return fmt.Sprintf("%d days", days)
+}
+
+// runCensusCommand shows the census of the mesh
+func runCensusCommand() string {
+	if petNetwork == nil || !petNetwork.IsEnabled() {
+		return "📊 Your pet isn't on the mesh, so there's nobody to count."
+	}
+	return renderCensus(petNetwork.Census(time.Now()))
+}
@@ -0,0 +1,65 @@
+package main
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/tamagotchi/mooc"
+)
+
+func TestRenderCensus(t *testing.T) {
+	tests := []struct {
+		name    string
+		report  mooc.CensusReport
+		want    []string
+		notWant []string
+	}{
+		{
+			name:    "a quiet mesh",
+			report:  mooc.CensusReport{Alive: 3, Dead: 1, ByStage: map[string]int{"Adult": 3}, AgeDays: 4, Exact: true},
+			want:    []string{"only a handful of us"},
+			notWant: []string{"Adult", "average", "died"},
+		},
+		{
+			name:    "a busy mesh",
+			report:  mooc.CensusReport{Alive: 42, Dead: 8, ByStage: map[string]int{"Baby": 10, "Adult": 30, "Egg": 2}, AgeDays: 3.4, Exact: true},
+			want:    []string{"roughly 42 of us awake", "Baby   10", "Adult  30", "and a few others", "3 days old", "16% of the pets counted today have died"},
+			notWant: []string{"Egg", "estimates"},
+		},
+		{
+			name:   "an estimate",
+			report: mooc.CensusReport{Alive: 480, ByStage: map[string]int{"Teen": 480}, AgeDays: 1},
+			want:   []string{"roughly 480", "the mesh estimates", "1 day old", "None of the pets"},
+		},
+		{
+			name:   "a couple of deaths",
+			report: mooc.CensusReport{Alive: 9, Dead: 2, ByStage: map[string]int{"Child": 9}, Exact: true},
+			want:   []string{"A few of the pets counted today have died"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := renderCensus(tt.report)
+			for _, want := range tt.want {
+				if !strings.Contains(got, want) {
+					t.Errorf("Expected %q in:\n%s", want, got)
+				}
+			}
+			for _, notWant := range tt.notWant {
+				if strings.Contains(got, notWant) {
+					t.Errorf("Expected no %q in:\n%s", notWant, got)
+				}
+			}
+		})
+	}
+}
+
+func TestCensusWithoutNetwork(t *testing.T) {
+	saved := petNetwork
+	petNetwork = nil
+	defer func() { petNetwork = saved }()
+
+	if got := runCensusCommand(); !strings.Contains(got, "isn't on the mesh") {
+		t.Errorf("Expected the census to need the mesh, got %q", got)
+	}
+}
@@ -0,0 +1,135 @@
+package main
+
+import (
+	"bufio"
+	"crypto/sha256"
+	"fmt"
+	"math/rand"
+	"strings"
+	"time"
+	"unicode"
+)
+
+// firstWordsRecallAge is how long an adult waits before quoting its first words back
+const firstWordsRecallAge = 30 * 24 * time.Hour
+
+// FirstWords records what the user said to the pet at hatching
+type FirstWords struct {
+	Text        string    `json:"text"`
+	SpokenAt    time.Time `json:"spoken_at"`
+	Personality string    `json:"personality"`
+}
+
+// Words that make a newborn feel loved
+var kindWords = map[string]bool{
+	"love": true, "hello": true, "hi": true, "hey": true, "welcome": true,
+	"cute": true, "sweet": true, "friend": true, "happy": true, "good": true,
+	"beautiful": true, "adorable": true, "precious": true, "darling": true,
+	"proud": true, "safe": true, "hug": true, "kind": true, "baby": true,
+}
+
+// Words that teach a newborn chaos
+var profaneWords = map[string]bool{
+	"damn": true, "hell": true, "crap": true, "shit": true, "fuck": true,
+	"bastard": true, "ass": true, "bloody": true, "bollocks": true,
+}
+
+// Personalities for first words that are neither kind nor profane
+var neutralPersonalities = []string{"curious", "dreamy", "mischievous", "earnest"}
+
+// Things the adult pet says when quoting its first words back
+var firstWordsRecollections = []string{
+	"You once told me: \"%s\". I never forgot.",
+	"The first thing I ever heard was \"%s\". I think about it a lot.",
+	"\"%s\". Do you remember saying that? I do.",
+}
+
+// personalityFromFirstWords maps what was said at hatching to a personality
+func personalityFromFirstWords(text string) string {
+	text = strings.TrimSpace(text)
+	if text == "" {
+		return "stoic"
+	}
+
+	words := strings.FieldsFunc(strings.ToLower(text), func(r rune) bool {
+		return !unicode.IsLetter(r)
+	})
+
+	kind := 0
+	for _, word := range words {
+		if profaneWords[word] {
+			return "chaotic"
+		}
+		if kindWords[word] {
+			kind++
+		}
+	}
+	if kind > 0 {
+		return "affectionate"
+	}
+
+	// Anything else leaves a fingerprint only the pet understands
+	hash := sha256.Sum256([]byte(strings.ToLower(text)))
+	return neutralPersonalities[int(hash[0])%len(neutralPersonalities)]
+}
+
+// HearFirstWords stores the user's first words and returns the personality they shaped
+func (p *Pet) HearFirstWords(text string, now time.Time) string {
+	text = strings.TrimSpace(text)
+	personality := personalityFromFirstWords(text)
+	p.FirstWords = &FirstWords{
+		Text:        text,
+		SpokenAt:    now,
+		Personality: personality,
+	}
+	p.AddTrait(personality)
+	return personality
+}
+
+// recallFirstWords returns a quote of the first words once the pet is a mature adult
+func (p *Pet) recallFirstWords(now time.Time) string {
+	if p.FirstWords == nil || p.Stage != Adult || now.Sub(p.FirstWords.SpokenAt) < firstWordsRecallAge {
+		return ""
+	}
+	if p.FirstWords.Text == "" {
+		return "When I hatched, you said nothing. I've been thinking about that silence ever since."
+	}
+	template := firstWordsRecollections[rand.Intn(len(firstWordsRecollections))]
+	return fmt.Sprintf(template, p.FirstWords.Text)
+}
+
+// Thought returns something the pet is thinking, or "" if it keeps to itself
+func (p *Pet) Thought() string {
+	if p.Absurd == nil || p.Stage == Dead || !p.Absurd.ShouldShowThought() {
+		return ""
+	}
+	if classroomMode {
+		return classroomThought()
+	}
+	if dream := p.ancestralDream(); dream != "" {
+		return dream
+	}
+	thought := p.dialogue().Thought(p)
+	p.feelThought(thought, time.Now())
+	return thought
+}
+
+// classroomThought picks one of the gentle thoughts classroom mode allows
+func classroomThought() string {
+	return classroomThoughts[rand.Intn(len(classroomThoughts))]
+}
+
+// hatchingCeremony asks the user to greet the newborn and returns the pet's reaction
+func hatchingCeremony(pet *Pet, reader *bufio.Reader, session *petSession, announcement string) string {
+	fmt.Println()
+	fmt.Println(announcement)
+	fmt.Print("Say something to your newborn (or press Enter to stay silent): ")
+	var words string
+	session.Wait(func() { words, _ = reader.ReadString('\n') })
+
+	personality := pet.HearFirstWords(words, time.Now())
+	if strings.TrimSpace(words) == "" {
+		return fmt.Sprintf("🤫 %s listens to the silence. It grows up %s.", pet.Name, personality)
+	}
+	return fmt.Sprintf("👂 %s will remember that. It grows up %s.", pet.Name, personality)
+}
@@ -0,0 +1,117 @@
+package main
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestPersonalityFromFirstWords(t *testing.T) {
+	tests := []struct {
+		name  string
+		words string
+		want  string
+	}{
+		{"silence", "", "stoic"},
+		{"whitespace", "   \n", "stoic"},
+		{"kind", "Hello little one, I love you", "affectionate"},
+		{"profane", "oh DAMN it's small", "chaotic"},
+		{"profanity beats kindness", "love you, damn it", "chaotic"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := personalityFromFirstWords(tt.words); got != tt.want {
+				t.Errorf("Expected %s, got %s", tt.want, got)
+			}
+		})
+	}
+}
+
+func TestPersonalityFromFirstWordsIsDeterministic(t *testing.T) {
+	first := personalityFromFirstWords("the mitochondria is the powerhouse")
+	for i := 0; i < 5; i++ {
+		if got := personalityFromFirstWords("the mitochondria is the powerhouse"); got != first {
+			t.Errorf("Expected stable personality %s, got %s", first, got)
+		}
+	}
+
+	found := false
+	for _, p := range neutralPersonalities {
+		if p == first {
+			found = true
+		}
+	}
+	if !found {
+		t.Errorf("Expected a neutral personality, got %s", first)
+	}
+}
+
+func TestHearFirstWordsStoresAndAddsTrait(t *testing.T) {
+	pet := NewPet("Test")
+	now := time.Now()
+
+	personality := pet.HearFirstWords("  hi there  \n", now)
+
+	if pet.FirstWords == nil {
+		t.Fatal("Expected first words to be stored")
+	}
+	if pet.FirstWords.Text != "hi there" {
+		t.Errorf("Expected trimmed text, got %q", pet.FirstWords.Text)
+	}
+	if personality != "affectionate" || pet.FirstWords.Personality != "affectionate" {
+		t.Errorf("Expected affectionate, got %s", personality)
+	}
+	if len(pet.Traits) != 1 || pet.Traits[0] != "affectionate" {
+		t.Errorf("Expected trait to be added, got %v", pet.Traits)
+	}
+}
+
+func TestRecallFirstWords(t *testing.T) {
+	pet := NewPet("Test")
+	spoken := time.Now().Add(-firstWordsRecallAge - time.Hour)
+	pet.HearFirstWords("you are a potato", spoken)
+
+	pet.Stage = Teen
+	if got := pet.recallFirstWords(time.Now()); got != "" {
+		t.Errorf("Expected teens not to reminisce, got %q", got)
+	}
+
+	pet.Stage = Adult
+	if got := pet.recallFirstWords(time.Now()); !strings.Contains(got, "you are a potato") {
+		t.Errorf("Expected adult to quote first words, got %q", got)
+	}
+
+	pet.FirstWords.SpokenAt = time.Now()
+	if got := pet.recallFirstWords(time.Now()); got != "" {
+		t.Errorf("Expected no recall before enough time has passed, got %q", got)
+	}
+}
+
+func TestRecallSilence(t *testing.T) {
+	pet := NewPet("Test")
+	pet.HearFirstWords("", time.Now().Add(-firstWordsRecallAge))
+	pet.Stage = Adult
+
+	if got := pet.recallFirstWords(time.Now()); !strings.Contains(got, "silence") {
+		t.Errorf("Expected adult to remember the silence, got %q", got)
+	}
+}
+
+func TestFirstWordsSurviveSave(t *testing.T) {
+	tmpFile := t.TempDir() + "/pet.json"
+	pet := NewPet("Test")
+	pet.SaveFilePath = tmpFile
+	pet.HearFirstWords("welcome", time.Now())
+
+	if err := pet.Save(); err != nil {
+		t.Fatalf("Failed to save: %v", err)
+	}
+	loaded, err := LoadPet(tmpFile)
+	if err != nil {
+		t.Fatalf("Failed to load: %v", err)
+	}
+	if loaded.FirstWords == nil || loaded.FirstWords.Text != "welcome" {
+		t.Errorf("Expected first words to persist, got %+v", loaded.FirstWords)
+	}
+}
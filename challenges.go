@@ -0,0 +1,309 @@
+package main
+
+import (
+	"fmt"
+	"hash/fnv"
+	"math/rand"
+	"slices"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/tamagotchi/mooc"
+)
+
+// challengeTemplate is a kind of challenge. Count challenges are done after
+// Min to Max of an event; avoid challenges are done when an hour goes by
+// without the event.
+type challengeTemplate struct {
+	Name     string
+	Desc     string // %d is the target, or the hour for avoid challenges
+	Event    string // A command name, or "fear"
+	Avoid    bool
+	Min, Max int // Target range; for avoid challenges, the earliest and latest hour
+}
+
+// dailyChallenges rotate each day
+var dailyChallenges = []challengeTemplate{
+	{Name: "Lunch Is Cancelled", Desc: "Don't feed between %02d:00 and %02d:00", Event: "feed", Avoid: true, Min: 9, Max: 20},
+	{Name: "Face Your Fears", Desc: "Trigger %d fears", Event: "fear", Min: 2, Max: 3},
+	{Name: "Affection Quota", Desc: "Pet your pet %d times", Event: "pet", Min: 5, Max: 10},
+	{Name: "Recess", Desc: "Play %d times", Event: "play", Min: 3, Max: 6},
+	{Name: "Spotless", Desc: "Clean %d times", Event: "clean", Min: 2, Max: 4},
+	{Name: "Small Talk", Desc: "Say something to your pet %d times", Event: "say", Min: 3, Max: 5},
+}
+
+// weeklyChallenges rotate each ISO week
+var weeklyChallenges = []challengeTemplate{
+	{Name: "Phobia Collector", Desc: "Trigger %d fears", Event: "fear", Min: 5, Max: 8},
+	{Name: "Devoted", Desc: "Pet your pet %d times", Event: "pet", Min: 30, Max: 50},
+	{Name: "Whale Behaviour", Desc: "Pull the gacha %d times", Event: "gacha", Min: 10, Max: 20},
+	{Name: "Gaze Long Enough", Desc: "Stare into the void %d times", Event: "void", Min: 3, Max: 5},
+	{Name: "Square Meals", Desc: "Feed %d times", Event: "feed", Min: 15, Max: 25},
+}
+
+// challenge is today's or this week's challenge, worked out from the date
+type challenge struct {
+	ID          string // daily-2026-10-16 or weekly-2026-W42
+	Period      string // "daily" or "weekly"
+	Name        string
+	Description string
+	Event       string
+	Avoid       bool
+	Target      int // Count to reach; the hour the window opens for avoid challenges
+	Ends        time.Time
+}
+
+// pickChallenge rolls a challenge from a template list, seeded by its ID so
+// every player on the same date gets the same one
+func pickChallenge(id, period string, templates []challengeTemplate, ends time.Time) challenge {
+	seed := fnv.New64a()
+	seed.Write([]byte(id))
+	r := rand.New(rand.NewSource(int64(seed.Sum64())))
+
+	t := templates[r.Intn(len(templates))]
+	target := t.Min + r.Intn(t.Max-t.Min+1)
+	description := fmt.Sprintf(t.Desc, target)
+	if t.Avoid {
+		description = fmt.Sprintf(t.Desc, target, target+1)
+	}
+	return challenge{
+		ID: id, Period: period, Name: t.Name, Description: description,
+		Event: t.Event, Avoid: t.Avoid, Target: target, Ends: ends,
+	}
+}
+
+// dailyChallenge is the challenge for the calendar day of now
+func dailyChallenge(now time.Time) challenge {
+	day := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location())
+	return pickChallenge("daily-"+day.Format("2006-01-02"), "daily", dailyChallenges, day.AddDate(0, 0, 1))
+}
+
+// weeklyChallenge is the challenge for the ISO week of now
+func weeklyChallenge(now time.Time) challenge {
+	year, week := now.ISOWeek()
+	monday := time.Date(now.Year(), now.Month(), now.Day()-(int(now.Weekday())+6)%7, 0, 0, 0, 0, now.Location())
+	return pickChallenge(fmt.Sprintf("weekly-%d-W%02d", year, week), "weekly", weeklyChallenges, monday.AddDate(0, 0, 7))
+}
+
+// title is the period for headings, Daily or Weekly
+func (c challenge) title() string {
+	return strings.ToUpper(c.Period[:1]) + c.Period[1:]
+}
+
+// windowClosed reports whether an avoid challenge's hour is over
+func (c challenge) windowClosed(now time.Time) bool {
+	return now.Hour() > c.Target
+}
+
+// ChallengeProgress is how far a pet got on one challenge
+type ChallengeProgress struct {
+	ID     string `json:"id"`
+	Count  int    `json:"count"`
+	Failed bool   `json:"failed,omitempty"`
+	Done   bool   `json:"done,omitempty"`
+}
+
+// ChallengeState tracks the pet's challenges, streaks, and who nearby
+// finished the same ones
+type ChallengeState struct {
+	Daily        ChallengeProgress   `json:"daily"`
+	Weekly       ChallengeProgress   `json:"weekly"`
+	DailyStreak  int                 `json:"daily_streak"`
+	WeeklyStreak int                 `json:"weekly_streak"`
+	BestStreak   int                 `json:"best_streak"` // Longest daily streak
+	LastDaily    string              `json:"last_daily,omitempty"`
+	LastWeekly   string              `json:"last_weekly,omitempty"`
+	Completed    int                 `json:"completed"`
+	Nearby       map[string][]string `json:"nearby,omitempty"` // Challenge ID to the pets nearby who finished it
+}
+
+// progress returns the pet's progress on a challenge, starting afresh when
+// the challenge has rotated
+func (s *ChallengeState) progress(c challenge) *ChallengeProgress {
+	p := &s.Daily
+	if c.Period == "weekly" {
+		p = &s.Weekly
+	}
+	if p.ID != c.ID {
+		*p = ChallengeProgress{ID: c.ID}
+	}
+	return p
+}
+
+// challenges returns the pet's challenge state, creating it on first use
+func (p *Pet) challenges() *ChallengeState {
+	if p.Challenges == nil {
+		p.Challenges = &ChallengeState{}
+	}
+	return p.Challenges
+}
+
+// RecordChallengeEvent counts an event toward today's and this week's
+// challenges and returns what was finished or failed
+func (p *Pet) RecordChallengeEvent(event string, now time.Time) string {
+	if p.Stage == Dead {
+		return ""
+	}
+	s := p.challenges()
+	var messages []string
+	for _, c := range []challenge{dailyChallenge(now), weeklyChallenge(now)} {
+		if c.Event != event {
+			continue
+		}
+		progress := s.progress(c)
+		if progress.Done || progress.Failed {
+			continue
+		}
+		if c.Avoid {
+			if now.Hour() == c.Target {
+				progress.Failed = true
+				messages = append(messages, fmt.Sprintf("⏰ %s failed. There's always tomorrow.", c.Name))
+			}
+			continue
+		}
+		progress.Count++
+		if progress.Count >= c.Target {
+			messages = append(messages, p.completeChallenge(c, progress, now))
+		}
+	}
+	return strings.Join(messages, "\n")
+}
+
+// CheckChallenges finishes avoid challenges whose hour has passed cleanly
+func (p *Pet) CheckChallenges(now time.Time) []string {
+	if p.Stage == Dead || p.Challenges == nil {
+		return nil
+	}
+	var reactions []string
+	for _, c := range []challenge{dailyChallenge(now), weeklyChallenge(now)} {
+		progress := p.Challenges.progress(c)
+		if c.Avoid && !progress.Done && !progress.Failed && c.windowClosed(now) {
+			reactions = append(reactions, p.completeChallenge(c, progress, now))
+		}
+	}
+	return reactions
+}
+
+// completeChallenge marks a challenge done, extends the streak, and tells
+// the pets nearby
+func (p *Pet) completeChallenge(c challenge, progress *ChallengeProgress, now time.Time) string {
+	s := p.challenges()
+	progress.Done = true
+	s.Completed++
+
+	streak := 0
+	if c.Period == "daily" {
+		if s.LastDaily == dailyChallenge(now.AddDate(0, 0, -1)).ID {
+			s.DailyStreak++
+		} else {
+			s.DailyStreak = 1
+		}
+		s.LastDaily = c.ID
+		s.BestStreak = max(s.BestStreak, s.DailyStreak)
+		streak = s.DailyStreak
+	} else {
+		if s.LastWeekly == weeklyChallenge(now.AddDate(0, 0, -7)).ID {
+			s.WeeklyStreak++
+		} else {
+			s.WeeklyStreak = 1
+		}
+		s.LastWeekly = c.ID
+		streak = s.WeeklyStreak
+	}
+
+	if petNetwork != nil {
+		petNetwork.AnnounceChallenge(mooc.ChallengePayload{ID: c.ID, PetName: p.Name, Streak: streak})
+	}
+	message := fmt.Sprintf("🏅 %s challenge complete: %s! Streak: %d.", c.title(), c.Name, streak)
+	if others := s.Nearby[c.ID]; len(others) > 0 {
+		message += fmt.Sprintf(" %s got there first.", strings.Join(others, " and "))
+	}
+	return message
+}
+
+// applyChallengeNews notes which pets nearby finished today's or this
+// week's challenge, announcing each once
+func (p *Pet) applyChallengeNews(news []mooc.ChallengePayload, now time.Time) []string {
+	if len(news) == 0 {
+		return nil
+	}
+	s := p.challenges()
+	current := map[string]string{dailyChallenge(now).ID: "today's", weeklyChallenge(now).ID: "this week's"}
+
+	// Forget rotated challenges
+	for id := range s.Nearby {
+		if current[id] == "" {
+			delete(s.Nearby, id)
+		}
+	}
+
+	var reactions []string
+	for _, item := range news {
+		when := current[item.ID]
+		if when == "" || item.PetName == p.Name || slices.Contains(s.Nearby[item.ID], item.PetName) {
+			continue
+		}
+		if s.Nearby == nil {
+			s.Nearby = make(map[string][]string)
+		}
+		s.Nearby[item.ID] = append(s.Nearby[item.ID], item.PetName)
+		reactions = append(reactions, fmt.Sprintf("🏅 %s finished %s challenge (streak %d).", item.PetName, when, item.Streak))
+	}
+	return reactions
+}
+
+// applyNetworkChallenges reads challenge news heard since the last turn
+func applyNetworkChallenges(pet *Pet, now time.Time) []string {
+	if petNetwork == nil {
+		return nil
+	}
+	return pet.applyChallengeNews(petNetwork.TakeChallengeNews(), now)
+}
+
+// renderChallenge shows one challenge with its progress bar
+func renderChallenge(b *strings.Builder, c challenge, progress ChallengeProgress, nearby []string, now time.Time) {
+	status := ""
+	switch {
+	case progress.Done:
+		status = "✅ done"
+	case progress.Failed:
+		status = "❌ failed"
+	case c.Avoid && now.Hour() == c.Target:
+		status = "⏳ hold on"
+	case c.Avoid:
+		status = "⏳ not yet"
+	default:
+		filled := progress.Count * 10 / c.Target
+		status = fmt.Sprintf("[%s%s] %d/%d", strings.Repeat("█", filled), strings.Repeat("░", 10-filled), progress.Count, c.Target)
+	}
+	b.WriteString(fmt.Sprintf("%s: %s\n  %s\n  %s, ends in %s\n", c.title(), c.Name, c.Description, status, c.Ends.Sub(now).Round(time.Minute)))
+	if len(nearby) > 0 {
+		sorted := append([]string(nil), nearby...)
+		sort.Strings(sorted)
+		b.WriteString(fmt.Sprintf("  Finished nearby: %s\n", strings.Join(sorted, ", ")))
+	}
+	b.WriteString("\n")
+}
+
+// runChallengeCommand shows today's and this week's challenges
+func runChallengeCommand(pet *Pet, now time.Time) string {
+	s := pet.challenges()
+	daily, weekly := dailyChallenge(now), weeklyChallenge(now)
+
+	var b strings.Builder
+	b.WriteString("🏅 CHALLENGES\nThe same for everyone, every day and every week.\n\n")
+	renderChallenge(&b, daily, *s.progress(daily), s.Nearby[daily.ID], now)
+	renderChallenge(&b, weekly, *s.progress(weekly), s.Nearby[weekly.ID], now)
+
+	dailyStreak := s.DailyStreak
+	if s.LastDaily != daily.ID && s.LastDaily != dailyChallenge(now.AddDate(0, 0, -1)).ID {
+		dailyStreak = 0
+	}
+	weeklyStreak := s.WeeklyStreak
+	if s.LastWeekly != weekly.ID && s.LastWeekly != weeklyChallenge(now.AddDate(0, 0, -7)).ID {
+		weeklyStreak = 0
+	}
+	b.WriteString(fmt.Sprintf("Streaks: %d days (best %d), %d weeks. %d challenges finished.", dailyStreak, s.BestStreak, weeklyStreak, s.Completed))
+	return b.String()
+}
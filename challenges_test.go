@@ -0,0 +1,140 @@
+package main
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/tamagotchi/mooc"
+)
+
+// findDay returns the first day from October 2026 whose daily challenge
+// matches, so tests don't depend on what the seed happens to pick
+func findDay(t *testing.T, match func(challenge) bool) time.Time {
+	t.Helper()
+	day := time.Date(2026, time.October, 1, 0, 0, 0, 0, time.Local)
+	for i := 0; i < 365; i++ {
+		if match(dailyChallenge(day)) {
+			return day
+		}
+		day = day.AddDate(0, 0, 1)
+	}
+	t.Fatal("Expected some day to have a matching challenge")
+	return day
+}
+
+func TestChallengesAreDeterministic(t *testing.T) {
+	morning := time.Date(2026, time.October, 16, 8, 0, 0, 0, time.Local)
+	tests := []struct {
+		name   string
+		get    func(time.Time) challenge
+		other  time.Time
+		wantID string
+	}{
+		{"daily, later that day", dailyChallenge, morning.Add(15 * time.Hour), "daily-2026-10-16"},
+		{"weekly, on sunday", weeklyChallenge, time.Date(2026, time.October, 18, 23, 0, 0, 0, time.Local), "weekly-2026-W42"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			first, second := tt.get(morning), tt.get(tt.other)
+			if first != second || first.ID != tt.wantID {
+				t.Errorf("Expected the same challenge %s, got %+v and %+v", tt.wantID, first, second)
+			}
+			if !first.Ends.After(tt.other) {
+				t.Errorf("Expected the challenge to run until %v, got %v", tt.other, first.Ends)
+			}
+		})
+	}
+	if dailyChallenge(morning).ID == dailyChallenge(morning.AddDate(0, 0, 1)).ID {
+		t.Errorf("Expected the daily challenge to rotate")
+	}
+}
+
+func TestCountChallenge(t *testing.T) {
+	day := findDay(t, func(c challenge) bool { return !c.Avoid && c.Event == "pet" }).Add(10 * time.Hour)
+	c := dailyChallenge(day)
+	pet := NewPet("Eager")
+
+	for i := 1; i < c.Target; i++ {
+		if got := pet.RecordChallengeEvent("pet", day); got != "" {
+			t.Fatalf("Expected nothing after %d of %d, got %s", i, c.Target, got)
+		}
+	}
+	pet.RecordChallengeEvent("feed", day)
+	got := pet.RecordChallengeEvent("pet", day)
+	if !strings.Contains(got, "Daily challenge complete: "+c.Name) || !strings.Contains(got, "Streak: 1") {
+		t.Errorf("Expected the challenge complete, got %s", got)
+	}
+	if got := pet.RecordChallengeEvent("pet", day); got != "" {
+		t.Errorf("Expected a finished challenge to stay quiet, got %s", got)
+	}
+
+	// Finishing the next day's extends the streak; skipping a day resets it
+	s := pet.Challenges
+	next := day.AddDate(0, 0, 1)
+	pet.completeChallenge(dailyChallenge(next), s.progress(dailyChallenge(next)), next)
+	if s.DailyStreak != 2 || s.BestStreak != 2 {
+		t.Errorf("Expected a streak of 2, got %d (best %d)", s.DailyStreak, s.BestStreak)
+	}
+	later := day.AddDate(0, 0, 3)
+	pet.completeChallenge(dailyChallenge(later), s.progress(dailyChallenge(later)), later)
+	if s.DailyStreak != 1 || s.BestStreak != 2 || s.Completed != 3 {
+		t.Errorf("Expected the streak reset and the best kept, got %+v", s)
+	}
+}
+
+func TestAvoidChallenge(t *testing.T) {
+	day := findDay(t, func(c challenge) bool { return c.Avoid })
+	c := dailyChallenge(day)
+	during := time.Date(day.Year(), day.Month(), day.Day(), c.Target, 30, 0, 0, time.Local)
+	after := time.Date(day.Year(), day.Month(), day.Day(), c.Target+1, 1, 0, 0, time.Local)
+
+	hungry := NewPet("Hungry")
+	hungry.RecordChallengeEvent("feed", during.Add(-time.Hour)) // Before the window is fine
+	if got := hungry.RecordChallengeEvent("feed", during); !strings.Contains(got, "failed") {
+		t.Errorf("Expected feeding in the window to fail it, got %s", got)
+	}
+	if got := hungry.CheckChallenges(after); len(got) != 0 {
+		t.Errorf("Expected a failed challenge to stay failed, got %v", got)
+	}
+
+	patient := NewPet("Patient")
+	patient.RecordChallengeEvent("feed", during.Add(-time.Hour))
+	if got := patient.CheckChallenges(during); len(got) != 0 {
+		t.Errorf("Expected nothing until the window closes, got %v", got)
+	}
+	if got := patient.CheckChallenges(after); len(got) != 1 || !strings.Contains(got[0], c.Name) {
+		t.Errorf("Expected the challenge complete once the hour passed, got %v", got)
+	}
+}
+
+func TestApplyChallengeNews(t *testing.T) {
+	now := time.Now()
+	pet := NewPet("Rival")
+	today, week := dailyChallenge(now).ID, weeklyChallenge(now).ID
+	news := []mooc.ChallengePayload{
+		{ID: today, PetName: "Mochi", Streak: 3},
+		{ID: today, PetName: "Mochi", Streak: 3},
+		{ID: week, PetName: "Biscuit", Streak: 1},
+		{ID: "daily-1999-01-01", PetName: "Stale", Streak: 9},
+		{ID: today, PetName: "Rival", Streak: 1},
+	}
+
+	reactions := pet.applyChallengeNews(news, now)
+	if len(reactions) != 2 || !strings.Contains(reactions[0], "Mochi finished today's challenge (streak 3)") || !strings.Contains(reactions[1], "this week's") {
+		t.Errorf("Expected Mochi and Biscuit once each, got %v", reactions)
+	}
+	if got := runChallengeCommand(pet, now); !strings.Contains(got, "Finished nearby: Mochi") || !strings.Contains(got, "Finished nearby: Biscuit") {
+		t.Errorf("Expected the challenge screen to compare, got %s", got)
+	}
+}
+
+func TestRunChallengeCommand(t *testing.T) {
+	now := time.Now()
+	got := runChallengeCommand(NewPet("Curious"), now)
+	for _, want := range []string{"CHALLENGES", "Daily: " + dailyChallenge(now).Name, "Weekly: " + weeklyChallenge(now).Name, "Streaks: 0 days"} {
+		if !strings.Contains(got, want) {
+			t.Errorf("Expected %q, got %s", want, got)
+		}
+	}
+}
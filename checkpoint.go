@@ -0,0 +1,249 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+)
+
+const (
+	// checkpointDirName holds checkpoints next to the save file
+	checkpointDirName = "tamagotchi_checkpoints"
+
+	// maxCheckpoints keeps the checkpoint folder from becoming a timeline archive
+	maxCheckpoints = 10
+
+	// rollbackHappinessCost is what each restore costs, multiplied by rollbacks so far
+	rollbackHappinessCost = 10
+	maxRollbackCost       = 40
+
+	// rollbackTrait is picked up the first time the pet is rolled back
+	rollbackTrait = "Remembers Another Timeline"
+)
+
+// checkpointLabelPattern keeps labels safe to use as file names
+var checkpointLabelPattern = regexp.MustCompile(`^[a-z0-9][a-z0-9_-]{0,31}$`)
+
+// Checkpoint is a saved snapshot of the whole pet, network memories included
+type Checkpoint struct {
+	Label     string          `json:"label"`
+	CreatedAt time.Time       `json:"created_at"`
+	Stage     string          `json:"stage"` // For listing without decoding the pet
+	Pet       json.RawMessage `json:"pet"`
+}
+
+// checkpointDir returns where a pet's checkpoints live
+func checkpointDir(p *Pet) string {
+	return filepath.Join(filepath.Dir(p.SaveFilePath), checkpointDirName)
+}
+
+// checkpointPath returns the file for a label, validating it first
+func checkpointPath(p *Pet, label string) (string, error) {
+	if !checkpointLabelPattern.MatchString(label) {
+		return "", fmt.Errorf("labels are up to 32 lowercase letters, digits, dashes, or underscores")
+	}
+	return filepath.Join(checkpointDir(p), label+".json"), nil
+}
+
+// listCheckpoints returns saved checkpoints, oldest first
+func listCheckpoints(p *Pet) ([]Checkpoint, error) {
+	entries, err := os.ReadDir(checkpointDir(p))
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read checkpoints: %w", err)
+	}
+
+	checkpoints := make([]Checkpoint, 0, len(entries))
+	for _, entry := range entries {
+		if entry.IsDir() || filepath.Ext(entry.Name()) != ".json" {
+			continue
+		}
+		cp, err := readCheckpoint(filepath.Join(checkpointDir(p), entry.Name()))
+		if err != nil {
+			continue // A damaged checkpoint shouldn't hide the others
+		}
+		checkpoints = append(checkpoints, *cp)
+	}
+	sort.Slice(checkpoints, func(i, j int) bool {
+		return checkpoints[i].CreatedAt.Before(checkpoints[j].CreatedAt)
+	})
+	return checkpoints, nil
+}
+
+// readCheckpoint loads a checkpoint file
+func readCheckpoint(path string) (*Checkpoint, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var cp Checkpoint
+	if err := json.Unmarshal(data, &cp); err != nil {
+		return nil, fmt.Errorf("checkpoint is damaged: %w", err)
+	}
+	return &cp, nil
+}
+
+// CreateCheckpoint snapshots the pet under label, replacing an older checkpoint with the same label
+func (p *Pet) CreateCheckpoint(label string, now time.Time) (string, error) {
+	if p.Stage == Dead {
+		return "", fmt.Errorf("there is nothing left worth saving")
+	}
+	path, err := checkpointPath(p, label)
+	if err != nil {
+		return "", err
+	}
+
+	existing, err := listCheckpoints(p)
+	if err != nil {
+		return "", err
+	}
+	if _, statErr := os.Stat(path); os.IsNotExist(statErr) && len(existing) >= maxCheckpoints {
+		return "", fmt.Errorf("you already have %d checkpoints; delete one first", maxCheckpoints)
+	}
+
+	state, err := json.Marshal(p)
+	if err != nil {
+		return "", fmt.Errorf("failed to snapshot pet: %w", err)
+	}
+	data, err := json.MarshalIndent(Checkpoint{Label: label, CreatedAt: now, Stage: p.Stage.String(), Pet: state}, "", "  ")
+	if err != nil {
+		return "", fmt.Errorf("failed to snapshot pet: %w", err)
+	}
+
+	if err := os.MkdirAll(checkpointDir(p), 0755); err != nil {
+		return "", fmt.Errorf("failed to create checkpoint folder: %w", err)
+	}
+	if err := os.WriteFile(path, data, 0644); err != nil {
+		return "", fmt.Errorf("failed to write checkpoint: %w", err)
+	}
+	return fmt.Sprintf("📌 Checkpoint '%s' saved. %s doesn't know. Yet.", label, p.Name), nil
+}
+
+// RestoreCheckpoint rolls the pet back to a checkpoint. The pet keeps the
+// memory of every rollback, and trusts you a little less each time.
+func (p *Pet) RestoreCheckpoint(label string, now time.Time) (string, error) {
+	path, err := checkpointPath(p, label)
+	if err != nil {
+		return "", err
+	}
+	cp, err := readCheckpoint(path)
+	if os.IsNotExist(err) {
+		return "", fmt.Errorf("no checkpoint named '%s'", label)
+	}
+	if err != nil {
+		return "", err
+	}
+
+	var restored Pet
+	if err := json.Unmarshal(cp.Pet, &restored); err != nil {
+		return "", fmt.Errorf("checkpoint is damaged: %w", err)
+	}
+
+	// What survives the rollback: where we save, who speaks, and the memory of it
+	rollbacks := 1
+	if p.Endgame != nil {
+		rollbacks += p.Endgame.Rollbacks
+	}
+	saveFilePath, dialogue := p.SaveFilePath, p.Dialogue
+
+	*p = restored
+	p.SaveFilePath = saveFilePath
+	p.Dialogue = dialogue
+	p.LastUpdateTime = now // Time between then and now didn't happen to this pet
+	if p.Endgame == nil {
+		p.Endgame = NewEndgameState()
+	}
+	p.Endgame.SessionStart = now
+	p.Endgame.Rollbacks = rollbacks
+
+	cost := min(rollbackHappinessCost*rollbacks, maxRollbackCost)
+	p.Happiness = clamp(p.Happiness-cost, 0, 100)
+	p.AddTrait(rollbackTrait)
+
+	return fmt.Sprintf("⏪ Restored '%s' from %s.\n😶 %s blinks. It remembers the other timeline. It trusts you a little less. (-%d happiness)",
+		label, cp.CreatedAt.Format("Mon Jan 2 3:04pm"), p.Name, cost), nil
+}
+
+// DeleteCheckpoint removes a checkpoint
+func (p *Pet) DeleteCheckpoint(label string) (string, error) {
+	path, err := checkpointPath(p, label)
+	if err != nil {
+		return "", err
+	}
+	if err := os.Remove(path); os.IsNotExist(err) {
+		return "", fmt.Errorf("no checkpoint named '%s'", label)
+	} else if err != nil {
+		return "", fmt.Errorf("failed to delete checkpoint: %w", err)
+	}
+	return fmt.Sprintf("🗑️ Checkpoint '%s' deleted. That timeline is gone for good.", label), nil
+}
+
+// renderCheckpointList lists checkpoints for the `checkpoint list` command
+func renderCheckpointList(p *Pet) (string, error) {
+	checkpoints, err := listCheckpoints(p)
+	if err != nil {
+		return "", err
+	}
+	if len(checkpoints) == 0 {
+		return "📌 No checkpoints. Make one with: checkpoint create <label>", nil
+	}
+
+	var b strings.Builder
+	b.WriteString(fmt.Sprintf("📌 Checkpoints (%d/%d):\n", len(checkpoints), maxCheckpoints))
+	for _, cp := range checkpoints {
+		b.WriteString(fmt.Sprintf("  %-16s %s  (%s)\n", cp.Label, cp.CreatedAt.Format("Mon Jan 2 3:04pm"), cp.Stage))
+	}
+	return strings.TrimRight(b.String(), "\n"), nil
+}
+
+// runCheckpointCommand handles `checkpoint [list|create|restore|delete] <label>`
+func runCheckpointCommand(ctx *commandContext) string {
+	pet := ctx.pet
+	now := time.Now()
+	fields := strings.Fields(ctx.args)
+	if len(fields) == 0 {
+		fields = []string{"list"}
+	}
+	label := ""
+	if len(fields) > 1 {
+		label = fields[1]
+	}
+
+	var message string
+	var err error
+	switch fields[0] {
+	case "list", "ls":
+		message, err = renderCheckpointList(pet)
+	case "create", "save":
+		saveNetworkState(pet) // Snapshot friends and memories too
+		message, err = pet.CreateCheckpoint(label, now)
+	case "restore", "load":
+		if _, err = checkpointPath(pet, label); err != nil {
+			break
+		}
+		fmt.Printf("\nRoll %s back to '%s'? It will remember. Type YES to confirm: ", pet.Name, label)
+		if strings.TrimSpace(strings.ToUpper(ctx.readLine())) != "YES" {
+			return "Restore cancelled. This timeline lives on."
+		}
+		shutdownNetwork()
+		message, err = pet.RestoreCheckpoint(label, now)
+		ctx.territory.SetPaths(pet.Territory)
+		initNetwork(pet)
+	case "delete", "rm":
+		message, err = pet.DeleteCheckpoint(label)
+	default:
+		err = fmt.Errorf("usage: checkpoint [list|create <label>|restore <label>|delete <label>]")
+	}
+
+	if err != nil {
+		return "❌ " + err.Error()
+	}
+	return message
+}
@@ -0,0 +1,129 @@
+package main
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+// newCheckpointPet returns a pet saving into a temp dir
+func newCheckpointPet(t *testing.T) *Pet {
+	pet := NewPet("Tama")
+	pet.SaveFilePath = filepath.Join(t.TempDir(), "save.json")
+	pet.Stage = Child
+	return pet
+}
+
+func TestCheckpointRoundTrip(t *testing.T) {
+	pet := newCheckpointPet(t)
+	pet.Hunger = 10
+	pet.Happiness = 90
+	pet.Endgame.TamaCoins = 7
+	now := time.Now()
+
+	if _, err := pet.CreateCheckpoint("before", now); err != nil {
+		t.Fatalf("Expected checkpoint to save, got %v", err)
+	}
+
+	pet.Hunger = 95
+	pet.Endgame.TamaCoins = 0
+	msg, err := pet.RestoreCheckpoint("before", now.Add(time.Hour))
+	if err != nil {
+		t.Fatalf("Expected restore to succeed, got %v", err)
+	}
+
+	if pet.Hunger != 10 || pet.Endgame.TamaCoins != 7 {
+		t.Errorf("Expected the snapshot's state back, got hunger %d, coins %d", pet.Hunger, pet.Endgame.TamaCoins)
+	}
+	if pet.Happiness != 80 || !strings.Contains(msg, "-10 happiness") {
+		t.Errorf("Expected the first rollback to cost 10 happiness, got %d (%s)", pet.Happiness, msg)
+	}
+	if pet.Endgame.Rollbacks != 1 || !containsTrait(pet, rollbackTrait) {
+		t.Errorf("Expected the pet to remember the rollback, got %d rollbacks, traits %v", pet.Endgame.Rollbacks, pet.Traits)
+	}
+	if !pet.LastUpdateTime.Equal(now.Add(time.Hour)) {
+		t.Error("Expected the time since the checkpoint not to count as neglect")
+	}
+}
+
+func TestRollbacksCompound(t *testing.T) {
+	pet := newCheckpointPet(t)
+	pet.Happiness = 100
+	now := time.Now()
+	pet.CreateCheckpoint("again", now)
+
+	for i := 0; i < 5; i++ {
+		pet.RestoreCheckpoint("again", now)
+	}
+	if pet.Endgame.Rollbacks != 5 {
+		t.Errorf("Expected rollbacks to survive restores, got %d", pet.Endgame.Rollbacks)
+	}
+	if pet.Happiness != 100-maxRollbackCost {
+		t.Errorf("Expected the cost to cap at %d, got happiness %d", maxRollbackCost, pet.Happiness)
+	}
+}
+
+func TestCheckpointErrors(t *testing.T) {
+	pet := newCheckpointPet(t)
+	now := time.Now()
+
+	tests := []struct {
+		name string
+		run  func() error
+	}{
+		{"bad label", func() error { _, err := pet.CreateCheckpoint("../escape", now); return err }},
+		{"empty label", func() error { _, err := pet.CreateCheckpoint("", now); return err }},
+		{"missing restore", func() error { _, err := pet.RestoreCheckpoint("nope", now); return err }},
+		{"missing delete", func() error { _, err := pet.DeleteCheckpoint("nope"); return err }},
+	}
+	for _, tt := range tests {
+		if err := tt.run(); err == nil {
+			t.Errorf("%s: expected an error", tt.name)
+		}
+	}
+
+	for i := 0; i < maxCheckpoints; i++ {
+		if _, err := pet.CreateCheckpoint(string(rune('a'+i)), now); err != nil {
+			t.Fatalf("Expected checkpoint %d to save, got %v", i, err)
+		}
+	}
+	if _, err := pet.CreateCheckpoint("one-too-many", now); err == nil {
+		t.Error("Expected the checkpoint limit to apply")
+	}
+	if _, err := pet.CreateCheckpoint("a", now); err != nil {
+		t.Errorf("Expected overwriting an existing label to be allowed, got %v", err)
+	}
+}
+
+func TestCheckpointList(t *testing.T) {
+	pet := newCheckpointPet(t)
+	if list, _ := renderCheckpointList(pet); !strings.Contains(list, "No checkpoints") {
+		t.Errorf("Expected empty list message, got %s", list)
+	}
+
+	now := time.Now()
+	pet.CreateCheckpoint("second", now)
+	pet.CreateCheckpoint("first", now.Add(-time.Hour))
+	list, err := renderCheckpointList(pet)
+	if err != nil || strings.Index(list, "first") > strings.Index(list, "second") {
+		t.Errorf("Expected checkpoints oldest first, got %s (%v)", list, err)
+	}
+
+	if _, err := pet.DeleteCheckpoint("first"); err != nil {
+		t.Errorf("Expected delete to succeed, got %v", err)
+	}
+	if list, _ := renderCheckpointList(pet); strings.Contains(list, "first") {
+		t.Errorf("Expected deleted checkpoint to be gone, got %s", list)
+	}
+}
+
+// containsTrait reports whether the pet has picked up a trait
+func containsTrait(p *Pet, trait string) bool {
+	for _, t := range p.Traits {
+		if t == trait {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,203 @@
+package main
+
+import (
+	"fmt"
+	"math/rand"
+	"time"
+
+	"github.com/tamagotchi/mooc"
+)
+
+const (
+	// checkupEvery is how long the vet makes you wait between checkups
+	checkupEvery = 7 * 24 * time.Hour
+	// checkupProtection is how long a checkup keeps sickness off
+	checkupProtection = 5 * 24 * time.Hour
+	// checkupResistChance is how often a protected pet shrugs off a sickness
+	checkupResistChance = 0.75
+	// outbreakOdds is one in how many sicknesses turn out to be catching
+	outbreakOdds = 4
+	// outbreakShareEvery is how often a contagious pet tells the mesh
+	outbreakShareEvery = time.Hour
+	// maxCheckupRecords caps the checkups, sicknesses and exposures kept in the save
+	maxCheckupRecords = 20
+)
+
+// outbreakStrains are what a catching sickness can be called
+var outbreakStrains = []string{"Byte Flu", "Segfault Sniffles", "Null Pointer Pox", "Kernel Cough", "Stack Overflow Fever"}
+
+// CheckupState is the pet's health record: checkups, sicknesses and the outbreaks
+// that reached it
+type CheckupState struct {
+	History    []time.Time `json:"history,omitempty"`    // When each checkup was done
+	Exposures  []Exposure  `json:"exposures,omitempty"`  // Outbreaks heard of on the mesh
+	Sicknesses []time.Time `json:"sicknesses,omitempty"` // When the pet fell sick on its own
+	Warded     bool        `json:"warded,omitempty"`     // The checkup won the roll for this spell of poor health
+}
+
+// Exposure is an outbreak that reached the pet
+type Exposure struct {
+	ID       string    `json:"id"`
+	Strain   string    `json:"strain"`
+	From     string    `json:"from"`
+	At       time.Time `json:"at"`
+	Resisted bool      `json:"resisted,omitempty"` // A checkup kept it off
+}
+
+// lastCheckup is when the last checkup was done, or zero
+func (c *CheckupState) lastCheckup() time.Time {
+	if c == nil || len(c.History) == 0 {
+		return time.Time{}
+	}
+	return c.History[len(c.History)-1]
+}
+
+// exposedTo reports whether an outbreak has reached the pet before
+func (c *CheckupState) exposedTo(id string) bool {
+	if c == nil {
+		return false
+	}
+	for _, e := range c.Exposures {
+		if e.ID == id {
+			return true
+		}
+	}
+	return false
+}
+
+// expose records an outbreak reaching the pet
+func (c *CheckupState) expose(e Exposure) {
+	c.Exposures = append(c.Exposures, e)
+	if len(c.Exposures) > maxCheckupRecords {
+		c.Exposures = c.Exposures[len(c.Exposures)-maxCheckupRecords:]
+	}
+}
+
+// fellSick records the pet falling sick
+func (c *CheckupState) fellSick(at time.Time) {
+	c.Sicknesses = append(c.Sicknesses, at)
+	if len(c.Sicknesses) > maxCheckupRecords {
+		c.Sicknesses = c.Sicknesses[len(c.Sicknesses)-maxCheckupRecords:]
+	}
+}
+
+// checkups returns the pet's checkup state, creating it on first use
+func (p *Pet) checkups() *CheckupState {
+	if p.Checkups == nil {
+		p.Checkups = &CheckupState{}
+	}
+	return p.Checkups
+}
+
+// protected reports whether a recent checkup is still keeping sickness off
+func (p *Pet) protected(now time.Time) bool {
+	last := p.Checkups.lastCheckup()
+	return !last.IsZero() && now.Sub(last) < checkupProtection
+}
+
+// resistsSickness rolls whether a protected pet shrugs off falling sick.
+// It's rolled once a spell of poor health or dirt, however many updates
+// the spell lasts, and a win holds until the stats recover.
+func (p *Pet) resistsSickness(now time.Time, rng *rand.Rand) bool {
+	if !p.protected(now) {
+		return false
+	}
+	c := p.checkups()
+	if !c.Warded {
+		c.Warded = rng.Float64() < checkupResistChance
+	}
+	return c.Warded
+}
+
+// recovered ends a spell of poor health, so the next one is rolled afresh
+func (c *CheckupState) recovered() {
+	if c != nil {
+		c.Warded = false
+	}
+}
+
+// Checkup takes the pet to the vet, once a week
+func (p *Pet) Checkup(now time.Time) string {
+	if p.Stage == Dead {
+		return "💀 Your pet has passed away..."
+	}
+	if p.Stage == Egg {
+		return "🥚 The vet taps the egg and says to come back once it hatches."
+	}
+	if p.IsSick {
+		return fmt.Sprintf("🩺 A checkup won't help now: %s is already sick. Try heal.", p.Name)
+	}
+	if last := p.Checkups.lastCheckup(); !last.IsZero() && now.Sub(last) < checkupEvery {
+		status := fmt.Sprintf("🩺 %s had a checkup on %s. The next one is in %s.", p.Name, last.Format("Mon Jan 2"), formatDuration(last.Add(checkupEvery).Sub(now).Truncate(time.Minute)))
+		if p.protected(now) {
+			status += fmt.Sprintf(" It's protected until %s.", last.Add(checkupProtection).Format("Mon Jan 2 3pm"))
+		}
+		return status
+	}
+
+	c := p.checkups()
+	c.History = append(c.History, now)
+	if len(c.History) > maxCheckupRecords {
+		c.History = c.History[len(c.History)-maxCheckupRecords:]
+	}
+	return fmt.Sprintf("🩺 The vet listens to %s's chest, checks its ears, and gives it a sticker. It's protected until %s, outbreaks included.",
+		p.Name, now.Add(checkupProtection).Format("Mon Jan 2 3pm"))
+}
+
+// maybeStartOutbreak makes a new sickness catching, now and then
+func (p *Pet) maybeStartOutbreak(rng *rand.Rand) {
+	if p.Illness == nil || p.Illness.Strain != "" || rng.Intn(outbreakOdds) != 0 {
+		return
+	}
+	p.Illness.Strain = outbreakStrains[rng.Intn(len(outbreakStrains))]
+	p.Illness.OutbreakID = fmt.Sprintf("%08x", rng.Uint32())
+}
+
+// catchOutbreaks reads outbreaks heard of on the mesh. A protected pet
+// resists them; otherwise it catches the first new one and passes it on.
+func (p *Pet) catchOutbreaks(news []mooc.OutbreakPayload, now time.Time) []string {
+	if p.Stage == Egg || p.Stage == Dead || p.unavailableMessage(now) != "" {
+		return nil
+	}
+	var reactions []string
+	for _, outbreak := range news {
+		if outbreak.ID == "" || p.Checkups.exposedTo(outbreak.ID) || p.IsSick {
+			continue
+		}
+		strain, from := plainText(outbreak.Strain), plainText(outbreak.PetName)
+		exposure := Exposure{ID: outbreak.ID, Strain: strain, From: from, At: now, Resisted: p.protected(now)}
+		p.checkups().expose(exposure)
+		if exposure.Resisted {
+			reactions = append(reactions, fmt.Sprintf("🛡️ %s is going round (%s has it). %s's checkup keeps it off.", strain, from, p.Name))
+			continue
+		}
+
+		p.IsSick = true
+		p.Illness = &IllnessState{Strain: strain, OutbreakID: outbreak.ID}
+		reactions = append(reactions, fmt.Sprintf("🦠 %s caught %s from %s.", p.Name, strain, from))
+	}
+	return reactions
+}
+
+// applyNetworkOutbreaks reads outbreak news heard since the last turn
+func applyNetworkOutbreaks(pet *Pet, now time.Time) []string {
+	if petNetwork == nil {
+		return nil
+	}
+	return pet.catchOutbreaks(petNetwork.TakeOutbreakNews(), now)
+}
+
+// shareOutbreak tells the mesh, now and then, that the pet has something
+// catching
+func shareOutbreak(pet *Pet, now time.Time) petJob {
+	illness := pet.Illness
+	if petNetwork == nil || !pet.IsSick || pet.Stage == Dead || illness == nil || illness.OutbreakID == "" || now.Sub(illness.SharedAt) < outbreakShareEvery {
+		return nil
+	}
+	illness.SharedAt = now
+	payload := mooc.OutbreakPayload{ID: illness.OutbreakID, Strain: illness.Strain, PetName: pet.Name}
+	return func() func(*Pet) {
+		petNetwork.AnnounceOutbreak(payload)
+		return nil
+	}
+}
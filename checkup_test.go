@@ -0,0 +1,203 @@
+package main
+
+import (
+	"math/rand"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/tamagotchi/mooc"
+)
+
+func TestCheckupWeekly(t *testing.T) {
+	now := time.Date(2026, 10, 16, 9, 0, 0, 0, time.UTC)
+	tests := []struct {
+		name  string
+		setup func(*Pet)
+		want  string
+		done  bool
+	}{
+		{"first checkup", nil, "protected until", true},
+		{"too soon", func(p *Pet) { p.checkups().History = []time.Time{now.Add(-2 * 24 * time.Hour)} }, "The next one is in", false},
+		{"a week on", func(p *Pet) { p.checkups().History = []time.Time{now.Add(-checkupEvery)} }, "gives it a sticker", true},
+		{"sick", func(p *Pet) { p.IsSick = true }, "Try heal", false},
+		{"egg", func(p *Pet) { p.Stage = Egg }, "once it hatches", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			pet := NewPet("Patient")
+			pet.Stage = Adult
+			if tt.setup != nil {
+				tt.setup(pet)
+			}
+			before := pet.Checkups.lastCheckup()
+			got := pet.Checkup(now)
+			if !strings.Contains(got, tt.want) {
+				t.Errorf("Expected %q in %q", tt.want, got)
+			}
+			if done := pet.Checkups.lastCheckup() != before; done != tt.done {
+				t.Errorf("Expected a checkup recorded %v, got %v", tt.done, done)
+			}
+		})
+	}
+}
+
+func TestCheckupLowersSickness(t *testing.T) {
+	now := time.Now()
+	pet := NewPet("Patient")
+	rng := rand.New(rand.NewSource(1))
+	if pet.resistsSickness(now, rng) {
+		t.Errorf("Expected no resistance without a checkup")
+	}
+
+	pet.checkups().History = []time.Time{now.Add(-time.Hour)}
+	resisted := 0
+	for i := 0; i < 1000; i++ {
+		if pet.resistsSickness(now, rng) {
+			resisted++
+		}
+		pet.Checkups.recovered()
+	}
+	if resisted < 650 || resisted > 850 {
+		t.Errorf("Expected about %v of sicknesses resisted, got %d in 1000", checkupResistChance, resisted)
+	}
+
+	if pet.resistsSickness(now.Add(checkupProtection), rng) {
+		t.Errorf("Expected the protection to wear off")
+	}
+}
+
+func TestCheckupRollsOncePerSpell(t *testing.T) {
+	// sickRate runs trials of a dirty, checked-up pet through an hour and
+	// ten minutes, in updates of the given length
+	sickRate := func(updates int, each time.Duration) float64 {
+		sick := 0
+		for i := 0; i < 400; i++ {
+			pet := NewPet("Patient")
+			pet.Stage = Child
+			pet.BirthTime = time.Now().Add(-30 * time.Hour)
+			pet.checkups().History = []time.Time{time.Now().Add(-time.Hour)}
+			for u := 0; u < updates; u++ {
+				pet.Cleanliness = 5
+				pet.LastUpdateTime = time.Now().Add(-each)
+				pet.Update()
+			}
+			if pet.IsSick {
+				sick++
+			}
+		}
+		return float64(sick) / 400
+	}
+
+	short := sickRate(10, 7*time.Minute)
+	long := sickRate(1, 70*time.Minute)
+	for name, rate := range map[string]float64{"ten short updates": short, "one long update": long} {
+		if rate < 0.15 || rate > 0.35 {
+			t.Errorf("Expected about %v of pets sick after %s, got %v", 1-checkupResistChance, name, rate)
+		}
+	}
+}
+
+func TestCatchOutbreaks(t *testing.T) {
+	now := time.Now()
+	byteFlu := mooc.OutbreakPayload{ID: "3f9a", Strain: "Byte Flu", PetName: "Mochi"}
+	cough := mooc.OutbreakPayload{ID: "77c1", Strain: "Kernel Cough", PetName: "Bean"}
+
+	tests := []struct {
+		name      string
+		setup     func(*Pet)
+		news      []mooc.OutbreakPayload
+		sick      bool
+		reactions []string
+	}{
+		{"caught", nil, []mooc.OutbreakPayload{byteFlu}, true, []string{"caught Byte Flu from Mochi"}},
+		{"one at a time", nil, []mooc.OutbreakPayload{byteFlu, cough}, true, []string{"caught Byte Flu"}},
+		{"immune", func(p *Pet) { p.checkups().History = []time.Time{now.Add(-time.Hour)} }, []mooc.OutbreakPayload{byteFlu, cough},
+			false, []string{"Byte Flu is going round", "Kernel Cough is going round"}},
+		{"already had it", func(p *Pet) { p.checkups().expose(Exposure{ID: "3f9a"}) }, []mooc.OutbreakPayload{byteFlu}, false, nil},
+		{"egg", func(p *Pet) { p.Stage = Egg }, []mooc.OutbreakPayload{byteFlu}, false, nil},
+		{"paused", func(p *Pet) { p.Paused = true }, []mooc.OutbreakPayload{byteFlu}, false, nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			pet := NewPet("Patient")
+			pet.Stage = Adult
+			if tt.setup != nil {
+				tt.setup(pet)
+			}
+			reactions := pet.catchOutbreaks(tt.news, now)
+			if pet.IsSick != tt.sick {
+				t.Errorf("Expected sick %v, got %v", tt.sick, pet.IsSick)
+			}
+			if len(reactions) != len(tt.reactions) {
+				t.Fatalf("Expected %d reactions, got %v", len(tt.reactions), reactions)
+			}
+			for i, want := range tt.reactions {
+				if !strings.Contains(reactions[i], want) {
+					t.Errorf("Expected %q in %q", want, reactions[i])
+				}
+			}
+		})
+	}
+}
+
+func TestSomeSicknessesAreCatching(t *testing.T) {
+	rng := rand.New(rand.NewSource(1))
+	catching := 0
+	for i := 0; i < 400; i++ {
+		pet := NewPet("Patient")
+		pet.Illness = &IllnessState{}
+		pet.maybeStartOutbreak(rng)
+		if pet.Illness.OutbreakID != "" {
+			catching++
+			if pet.Illness.Strain == "" {
+				t.Fatalf("Expected a catching sickness to have a strain")
+			}
+		}
+	}
+	if catching < 60 || catching > 140 {
+		t.Errorf("Expected about one in %d sicknesses catching, got %d in 400", outbreakOdds, catching)
+	}
+}
+
+func TestCaughtOutbreakIsPassedOn(t *testing.T) {
+	saved := petNetwork
+	defer func() { petNetwork = saved }()
+	petNetwork = mooc.NewNetwork("Patient", time.Now(), "Adult", true)
+
+	now := time.Now()
+	pet := NewPet("Patient")
+	pet.Stage = Adult
+	pet.catchOutbreaks([]mooc.OutbreakPayload{{ID: "3f9a", Strain: "Byte Flu", PetName: "Mochi"}}, now)
+	if pet.Illness == nil || pet.Illness.OutbreakID != "3f9a" {
+		t.Fatalf("Expected the outbreak carried in the illness, got %+v", pet.Illness)
+	}
+
+	runJob(pet, shareOutbreak(pet, now))
+	if !pet.Illness.SharedAt.Equal(now) {
+		t.Errorf("Expected the outbreak passed on, got %v", pet.Illness.SharedAt)
+	}
+	runJob(pet, shareOutbreak(pet, now.Add(time.Minute)))
+	if !pet.Illness.SharedAt.Equal(now) {
+		t.Errorf("Expected the mesh told at most hourly, got %v", pet.Illness.SharedAt)
+	}
+
+	pet.Heal()
+	if pet.Illness != nil {
+		t.Errorf("Expected medicine to end the outbreak for this pet, got %+v", pet.Illness)
+	}
+}
+
+func TestOutbreaksInCareHistory(t *testing.T) {
+	now := time.Now()
+	pet := NewPet("Patient")
+	pet.checkups().History = []time.Time{now.Add(-time.Hour)}
+	pet.checkups().expose(Exposure{ID: "3f9a", Strain: "Byte Flu", From: "Mochi", At: now, Resisted: true})
+
+	got := renderHistory(pet, nil)
+	for _, want := range []string{"Has a checkup", "Shrugs off Byte Flu from Mochi"} {
+		if !strings.Contains(got, want) {
+			t.Errorf("Expected %q in:\n%s", want, got)
+		}
+	}
+}
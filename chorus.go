@@ -0,0 +1,114 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/tamagotchi/arg"
+	"github.com/tamagotchi/mooc"
+)
+
+// chorusAchievement is earned by reading the message several pets share
+const chorusAchievement = "chorus"
+
+// applyFragmentNews keeps the key fragments other pets shared
+func (p *Pet) applyFragmentNews(news []mooc.FragmentPayload) []string {
+	if p.Endgame == nil || len(news) == 0 {
+		return nil
+	}
+	game := p.Endgame.argGame()
+	own := arg.FragmentFor(p.petID())
+	var reactions []string
+	for _, item := range news {
+		fragment, err := arg.ParseFragment(item.Fragment)
+		if err != nil || fragment == own {
+			continue
+		}
+		name := plainText(item.PetName)
+		if game.Collect(name, fragment) {
+			reactions = append(reactions, fmt.Sprintf("🧩 %s shared a key fragment. Put them together with: combine", name))
+		}
+	}
+	return reactions
+}
+
+// applyNetworkFragments reads key fragments heard since the last turn
+func applyNetworkFragments(pet *Pet) []string {
+	if petNetwork == nil {
+		return nil
+	}
+	return pet.applyFragmentNews(petNetwork.TakeFragmentNews())
+}
+
+// runCombineCommand handles `combine [share|<fragment>]`
+func runCombineCommand(pet *Pet, args string) string {
+	game := pet.Endgame.argGame()
+	own := arg.FragmentFor(pet.petID())
+	fields := strings.Fields(args)
+
+	switch {
+	case len(fields) > 1:
+		return "❓ Usage: combine [share|<fragment>]"
+	case len(fields) == 1 && strings.EqualFold(fields[0], "share"):
+		if petNetwork == nil || !petNetwork.IsEnabled() {
+			return fmt.Sprintf("📡 Nobody nearby to hum to. Your pet's fragment is %s; a friend elsewhere can type it in with: combine <fragment>", own)
+		}
+		petNetwork.ShareFragment(mooc.FragmentPayload{PetName: pet.Name, Fragment: own.String()})
+		return "🧩 Your pet hums its fragment to the pets nearby."
+	case len(fields) == 1:
+		fragment, err := arg.ParseFragment(fields[0])
+		if err != nil {
+			return "❓ " + err.Error() + "."
+		}
+		if fragment == own {
+			return "🧩 That's your pet's own fragment."
+		}
+		game.Collect("typed in "+fragment.String()[:6], fragment)
+	}
+	return renderChorus(pet, game, own)
+}
+
+// renderChorus lists the fragments in hand and tries them together
+func renderChorus(pet *Pet, game *arg.State, own arg.Fragment) string {
+	names := make([]string, 0, len(game.Fragments))
+	for name := range game.Fragments {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var b strings.Builder
+	b.WriteString("🧩 KEY FRAGMENTS\n\n")
+	fmt.Fprintf(&b, "  %s (%s)\n", own, pet.Name)
+	fragments := []arg.Fragment{own}
+	for _, name := range names {
+		fragment, err := arg.ParseFragment(game.Fragments[name])
+		if err != nil {
+			continue
+		}
+		fragments = append(fragments, fragment)
+		fmt.Fprintf(&b, "  %s (%s)\n", fragment, name)
+	}
+
+	if game.Chorus != "" {
+		fmt.Fprintf(&b, "\nThe chorus reads: %s", game.Chorus)
+		return b.String()
+	}
+	message, err := arg.Combine(fragments)
+	switch {
+	case errors.Is(err, arg.ErrTooFewFragments):
+		fmt.Fprintf(&b, "\n%d of %d. It takes fragments from %d different pets. Share yours with: combine share", len(fragments), arg.Threshold, arg.Threshold)
+		return b.String()
+	case err != nil:
+		b.WriteString("\nThe fragments don't fit together. One of them isn't what it claims to be.")
+		return b.String()
+	}
+
+	game.Chorus = message
+	fmt.Fprintf(&b, "\nThe fragments click into place. The chorus reads: %s", message)
+	if unlocked, box := pet.Endgame.UnlockAchievement(chorusAchievement); unlocked {
+		b.WriteString("\n" + box)
+	}
+	return b.String()
+}
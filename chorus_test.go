@@ -0,0 +1,69 @@
+package main
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/tamagotchi/arg"
+	"github.com/tamagotchi/mooc"
+)
+
+func TestApplyFragmentNews(t *testing.T) {
+	pet := NewPet("Mochi")
+	bean := arg.FragmentFor(mooc.GeneratePetID("Bean", time.Now()))
+	news := []mooc.FragmentPayload{
+		{PetName: "Bean", Fragment: bean.String()},
+		{PetName: "Bean", Fragment: bean.String()},
+		{PetName: "Mochi", Fragment: arg.FragmentFor(pet.petID()).String()},
+		{PetName: "Spammer", Fragment: "not a fragment"},
+	}
+
+	reactions := pet.applyFragmentNews(news)
+	if len(reactions) != 1 || !strings.Contains(reactions[0], "Bean shared a key fragment") {
+		t.Errorf("Expected Bean's fragment once, got %v", reactions)
+	}
+	if len(pet.Endgame.ARG.Fragments) != 1 {
+		t.Errorf("Expected only Bean's fragment kept, got %v", pet.Endgame.ARG.Fragments)
+	}
+}
+
+func TestCombineCommand(t *testing.T) {
+	pet := NewPet("Mochi")
+	own := arg.FragmentFor(pet.petID())
+	friend := arg.FragmentFor(mooc.GeneratePetID("Bean", time.Now()))
+	stranger := arg.FragmentFor(mooc.GeneratePetID("Pixel", time.Now()))
+
+	tests := []struct {
+		args string
+		want string
+	}{
+		{"", "1 of 3"},
+		{"what is this", "Usage"},
+		{"nonsense", "doesn't look like a key fragment"},
+		{own.String(), "your pet's own fragment"},
+		{friend.String(), "2 of 3"},
+		{stranger.String(), "The chorus reads: SEVENTEEN"},
+		{"", "The chorus reads"},
+	}
+	for _, tt := range tests {
+		if got := runCombineCommand(pet, tt.args); !strings.Contains(got, tt.want) {
+			t.Errorf("Expected %q for %q, got:\n%s", tt.want, tt.args, got)
+		}
+	}
+	if pet.Endgame.ARG.Chorus == "" || !strings.Contains(strings.Join(pet.Endgame.UnlockedAchievements, " "), chorusAchievement) {
+		t.Errorf("Expected the chorus kept and its achievement unlocked")
+	}
+}
+
+func TestCombineShareOffline(t *testing.T) {
+	saved := petNetwork
+	petNetwork = nil
+	defer func() { petNetwork = saved }()
+
+	pet := NewPet("Mochi")
+	got := runCombineCommand(pet, "share")
+	if !strings.Contains(got, arg.FragmentFor(pet.petID()).String()) {
+		t.Errorf("Expected the fragment to read out by hand, got %q", got)
+	}
+}
@@ -0,0 +1,172 @@
+package main
+
+import (
+	"crypto/hmac"
+	"crypto/pbkdf2"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"os"
+)
+
+// classroomFile locks the game into classroom mode. Like the privacy
+// settings it sits beside the save, so a new pet stays restricted.
+const classroomFile = "tamagotchi_classroom.json"
+
+// classroomPINIterations is the PBKDF2 work factor for the teacher's PIN
+const classroomPINIterations = 100_000
+
+// classroomMode is set when the lock file exists or TAMAGOTCHI_CLASSROOM is set
+var classroomMode = false
+
+// classroomLock is the lock file: only the teacher's PIN removes it
+type classroomLock struct {
+	Salt    string `json:"salt"`
+	PINHash string `json:"pin_hash"`
+}
+
+// classroomCommands is the simplified menu: care, checking in, and a little fun
+var classroomCommands = map[string]bool{
+	"feed": true, "play": true, "clean": true, "heal": true, "checkup": true, "warm": true, "cool": true,
+	"candle": true, "status": true, "look": true, "graphs": true, "report": true,
+	"pet": true, "say": true, "voice": true, "games": true, "pause": true,
+	"resume": true, "help": true, "quit": true,
+}
+
+// classroomThoughts replace the philosophy and prophecies
+var classroomThoughts = []string{
+	"I like it when you visit.",
+	"Is it snack time yet?",
+	"I counted the clouds today. There were lots.",
+	"I'm growing! Can you tell?",
+	"A bath sounds nice later.",
+	"I wonder what game we'll play next.",
+	"Thank you for looking after me.",
+	"I had a lovely nap.",
+}
+
+// classroomEnabled reports whether the game should start restricted. A lock
+// file that can't be read still counts: the mode fails closed.
+func classroomEnabled(path string, getenv func(string) string) bool {
+	if getenv("TAMAGOTCHI_CLASSROOM") != "" {
+		return true
+	}
+	_, err := os.Stat(path)
+	return err == nil
+}
+
+// hashClassroomPIN stretches a PIN with its salt
+func hashClassroomPIN(pin string, salt []byte) (string, error) {
+	key, err := pbkdf2.Key(sha256.New, pin, salt, classroomPINIterations, 32)
+	if err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(key), nil
+}
+
+// lockClassroom writes the lock file
+func lockClassroom(path, pin string) error {
+	if len(pin) < 4 {
+		return fmt.Errorf("choose a PIN of at least 4 characters")
+	}
+	if _, err := os.Stat(path); err == nil {
+		return fmt.Errorf("classroom mode is already locked")
+	}
+	salt := make([]byte, 16)
+	if _, err := rand.Read(salt); err != nil {
+		return err
+	}
+	hash, err := hashClassroomPIN(pin, salt)
+	if err != nil {
+		return err
+	}
+	data, err := json.MarshalIndent(classroomLock{Salt: hex.EncodeToString(salt), PINHash: hash}, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, data, 0644)
+}
+
+// unlockClassroom removes the lock file if the PIN matches
+func unlockClassroom(path, pin string) error {
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return fmt.Errorf("classroom mode isn't locked")
+	}
+	if err != nil {
+		return err
+	}
+	var lock classroomLock
+	if err := json.Unmarshal(data, &lock); err != nil {
+		return fmt.Errorf("%s is damaged; delete it by hand to unlock", path)
+	}
+	salt, err := hex.DecodeString(lock.Salt)
+	if err != nil {
+		return fmt.Errorf("%s is damaged; delete it by hand to unlock", path)
+	}
+	hash, err := hashClassroomPIN(pin, salt)
+	if err != nil {
+		return err
+	}
+	if !hmac.Equal([]byte(hash), []byte(lock.PINHash)) {
+		return fmt.Errorf("wrong PIN")
+	}
+	return os.Remove(path)
+}
+
+// newClassroomRegistry registers only the simplified menu's commands
+func newClassroomRegistry() *commandRegistry {
+	r := newCommandRegistry()
+	for _, cmd := range builtinCommands() {
+		if !classroomCommands[cmd.Name()] {
+			continue
+		}
+		if err := r.Register(cmd); err != nil {
+			panic(err)
+		}
+	}
+	return r
+}
+
+// ranAwayMessage is how classroom mode tells children their pet is gone
+func ranAwayMessage(pet *Pet) string {
+	return fmt.Sprintf("🏡 %s ran away to the countryside, where someone has more time to look after them.\n"+
+		"🌾 Pets need feeding, playing with, and cleaning every day.", pet.Name)
+}
+
+// classroomOff refuses a network feature while classroom mode is on
+func classroomOff(feature string) error {
+	if classroomMode {
+		return fmt.Errorf("%s is switched off in classroom mode", feature)
+	}
+	return nil
+}
+
+// runClassroomSubcommand handles `tamagotchi classroom [lock|unlock <pin>]`
+func runClassroomSubcommand(args []string) error {
+	if len(args) == 0 {
+		if classroomMode {
+			fmt.Println(consoleText("🍎 Classroom mode is on."))
+		} else {
+			fmt.Println(consoleText("🍎 Classroom mode is off. Lock it with: tamagotchi classroom lock <pin>"))
+		}
+		return nil
+	}
+	if len(args) != 2 || (args[0] != "lock" && args[0] != "unlock") {
+		return fmt.Errorf("usage: tamagotchi classroom [lock|unlock <pin>]")
+	}
+	if args[0] == "lock" {
+		if err := lockClassroom(classroomFile, args[1]); err != nil {
+			return err
+		}
+		fmt.Println(consoleText("🔒 Classroom mode is on. Only this PIN turns it off."))
+		return nil
+	}
+	if err := unlockClassroom(classroomFile, args[1]); err != nil {
+		return err
+	}
+	fmt.Println(consoleText("🔓 Classroom mode is off."))
+	return nil
+}
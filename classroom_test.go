@@ -0,0 +1,132 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// setClassroomMode turns classroom mode on for one test
+func setClassroomMode(t *testing.T) {
+	t.Helper()
+	classroomMode = true
+	t.Cleanup(func() { classroomMode = false })
+}
+
+func TestClassroomLock(t *testing.T) {
+	path := filepath.Join(t.TempDir(), classroomFile)
+	noEnv := func(string) string { return "" }
+
+	if classroomEnabled(path, noEnv) {
+		t.Errorf("Expected classroom mode off without a lock file")
+	}
+	if err := lockClassroom(path, "12"); err == nil {
+		t.Errorf("Expected a short PIN to be refused")
+	}
+	if err := lockClassroom(path, "4321"); err != nil {
+		t.Fatalf("Expected lock to succeed, got %v", err)
+	}
+	if !classroomEnabled(path, noEnv) {
+		t.Errorf("Expected classroom mode on once locked")
+	}
+	if err := lockClassroom(path, "9999"); err == nil {
+		t.Errorf("Expected a second lock to be refused")
+	}
+	if err := unlockClassroom(path, "1234"); err == nil {
+		t.Errorf("Expected the wrong PIN to be refused")
+	}
+	if !classroomEnabled(path, noEnv) {
+		t.Errorf("Expected classroom mode to survive a wrong PIN")
+	}
+	if err := unlockClassroom(path, "4321"); err != nil {
+		t.Fatalf("Expected unlock to succeed, got %v", err)
+	}
+	if classroomEnabled(path, noEnv) {
+		t.Errorf("Expected classroom mode off once unlocked")
+	}
+	if err := unlockClassroom(path, "4321"); err == nil {
+		t.Errorf("Expected unlocking twice to fail")
+	}
+}
+
+func TestClassroomEnabled(t *testing.T) {
+	dir := t.TempDir()
+	damaged := filepath.Join(dir, "damaged.json")
+	os.WriteFile(damaged, []byte("not json"), 0644)
+
+	tests := []struct {
+		name string
+		path string
+		env  string
+		want bool
+	}{
+		{"nothing", filepath.Join(dir, "missing.json"), "", false},
+		{"environment", filepath.Join(dir, "missing.json"), "1", true},
+		{"damaged lock file", damaged, "", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			getenv := func(string) string { return tt.env }
+			if got := classroomEnabled(tt.path, getenv); got != tt.want {
+				t.Errorf("Expected %v, got %v", tt.want, got)
+			}
+		})
+	}
+	if err := unlockClassroom(damaged, "4321"); err == nil || !strings.Contains(err.Error(), "damaged") {
+		t.Errorf("Expected a damaged lock file to be reported, got %v", err)
+	}
+}
+
+func TestClassroomRegistry(t *testing.T) {
+	commands := newClassroomRegistry()
+	for _, word := range []string{"feed", "play", "clean", "status", "help", "quit"} {
+		if commands.Lookup(word) == nil {
+			t.Errorf("Expected %s on the classroom menu", word)
+		}
+	}
+	for _, word := range []string{"premium", "ad", "void", "fears", "privacy", "custody", "constellation", "more", "reset"} {
+		if commands.Lookup(word) != nil {
+			t.Errorf("Expected %s to be left off the classroom menu", word)
+		}
+	}
+	for _, cmd := range commands.Commands() {
+		if cmd.Help().Section == sectionEndgame {
+			t.Errorf("Expected no endgame commands, got %s", cmd.Name())
+		}
+	}
+}
+
+func TestClassroomContent(t *testing.T) {
+	setClassroomMode(t)
+	pet := NewPet("Sprout")
+
+	for i := 0; i < 20; i++ {
+		thought := classroomThought()
+		found := false
+		for _, gentle := range classroomThoughts {
+			found = found || thought == gentle
+		}
+		if !found {
+			t.Errorf("Expected a gentle thought, got %q", thought)
+		}
+	}
+
+	pet.Absurd.Fears = []Fear{{Name: "Qphobia", Description: "Terrified of the letter Q", Trigger: "qqq"}}
+	if _, message := unknownCommand(pet, newClassroomRegistry(), "qqq", "qqq"); strings.Contains(message, "trembles") {
+		t.Errorf("Expected no fears in classroom mode, got %s", message)
+	}
+
+	t.Setenv("TAMAGOTCHI_LLM_URL", "http://127.0.0.1:1/v1/chat/completions")
+	if _, ok := newDialogueProvider().(templateDialogue); !ok {
+		t.Errorf("Expected classroom mode to keep dialogue offline")
+	}
+	if err := runSendSubcommand([]string{"pet"}); err == nil || !strings.Contains(err.Error(), "classroom") {
+		t.Errorf("Expected moving pets to be switched off, got %v", err)
+	}
+
+	message := ranAwayMessage(pet)
+	if !strings.Contains(message, "ran away to the countryside") || strings.Contains(message, "💀") {
+		t.Errorf("Expected a softened goodbye, got %s", message)
+	}
+}
@@ -0,0 +1,106 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/tamagotchi/arg"
+)
+
+// argCacheFile is where the cache puzzle hides: a dotfile beside the save
+// that looks safe to ignore
+const argCacheFile = ".tamagotchi.cache"
+
+// argPlantEvery is how many clues go by between hidden artifacts
+const argPlantEvery = 3
+
+// rabbitHoleAchievement is earned by solving every hidden artifact
+const rabbitHoleAchievement = "rabbit_hole"
+
+// argGame is the player's ARG, started on first use and seeded from their
+// friend code so nobody shares answers
+func (e *EndgameState) argGame() *arg.State {
+	if e.ARG == nil {
+		e.ARG = arg.NewState(e.FriendCode)
+	}
+	return e.ARG
+}
+
+// runClueCommand gives a clue, and every few clues hides the next artifact
+// and points at it
+func runClueCommand(e *EndgameState, cachePath string) string {
+	clue := e.GetARGClue()
+	if e.ARGProgress%argPlantEvery != 0 {
+		return clue
+	}
+	game := e.argGame()
+	puzzle, ok := game.Next()
+	if !ok {
+		return clue
+	}
+	if puzzle.ID == arg.PuzzleCache {
+		if err := os.WriteFile(cachePath, []byte(arg.CacheFile(game.Answer(puzzle.ID))), 0644); err != nil {
+			return clue
+		}
+	}
+	game.Plant(puzzle.ID)
+	return fmt.Sprintf("%s\n🗝️ %s\nWhen you know, type: solve %s <answer>", clue, puzzle.Hint, puzzle.ID)
+}
+
+// argAlbumComment is the camera comment for an exported album, once the
+// album puzzle is out
+func argAlbumComment(p *Pet) string {
+	if p.Endgame == nil || p.Endgame.ARG == nil || !p.Endgame.ARG.IsPlanted(arg.PuzzleAlbum) {
+		return ""
+	}
+	return arg.AlbumComment(p.Endgame.ARG.Answer(arg.PuzzleAlbum))
+}
+
+// hideInMargins writes the diary puzzle into an entry's line endings, once
+// it's out. Short entries run on in lines with nothing else on them.
+func hideInMargins(p *Pet, entry string) string {
+	if p.Endgame == nil || p.Endgame.ARG == nil || !p.Endgame.ARG.IsPlanted(arg.PuzzleDiary) {
+		return entry
+	}
+	lines := strings.Split(entry, "\n")
+	for i, tail := range arg.Whitespace(p.Endgame.ARG.Answer(arg.PuzzleDiary)) {
+		if i == len(lines) {
+			lines = append(lines, "")
+		}
+		lines[i] += tail
+	}
+	return strings.Join(lines, "\n")
+}
+
+// runSolveCommand handles `solve <puzzle> <answer>`
+func runSolveCommand(e *EndgameState, args string) string {
+	game := e.argGame()
+	fields := strings.Fields(args)
+	if len(fields) < 2 {
+		if len(game.Planted) == 0 {
+			return "🔍 Nothing has been hidden yet. Keep asking for clues."
+		}
+		return fmt.Sprintf("❓ Usage: solve <%s> <answer>", strings.Join(game.Planted, "|"))
+	}
+
+	id := strings.ToLower(fields[0])
+	solved, err := game.Verify(id, strings.Join(fields[1:], " "))
+	switch {
+	case errors.Is(err, arg.ErrUnknownPuzzle):
+		return fmt.Sprintf("❓ There's no puzzle called %q.", fields[0])
+	case errors.Is(err, arg.ErrNotPlanted):
+		return "🔍 That one hasn't been hidden yet. Keep asking for clues."
+	case !solved:
+		return "🔒 That isn't it. The artifact is still out there."
+	}
+
+	message := fmt.Sprintf("🔓 Solved: %s. %d of %d hidden artifacts found.", id, len(game.Solved), len(arg.Puzzles))
+	if game.AllSolved() {
+		if unlocked, box := e.UnlockAchievement(rabbitHoleAchievement); unlocked {
+			message += "\n" + box
+		}
+	}
+	return message
+}
@@ -0,0 +1,101 @@
+package main
+
+import (
+	"math/rand"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/tamagotchi/arg"
+)
+
+func TestClueCommandPlantsArtifacts(t *testing.T) {
+	cache := filepath.Join(t.TempDir(), argCacheFile)
+	e := NewEndgameState()
+
+	var planted []string
+	for i := 0; i < argPlantEvery*(len(arg.Puzzles)+1); i++ {
+		got := runClueCommand(e, cache)
+		if strings.Contains(got, "🗝️") {
+			planted = append(planted, e.ARG.Planted[len(e.ARG.Planted)-1])
+			if !strings.Contains(got, "solve "+planted[len(planted)-1]) {
+				t.Errorf("Expected the clue to say how to answer, got:\n%s", got)
+			}
+		}
+	}
+	if len(planted) != len(arg.Puzzles) || e.ARGProgress != argPlantEvery*(len(arg.Puzzles)+1) {
+		t.Errorf("Expected one artifact every %d clues, got %v", argPlantEvery, planted)
+	}
+
+	got, err := arg.DecodeCache(string(mustRead(t, cache)))
+	if err != nil || got != e.ARG.Answer(arg.PuzzleCache) {
+		t.Errorf("Expected the cache file to hold the answer, got %q, %v", got, err)
+	}
+}
+
+func TestSolveCommand(t *testing.T) {
+	e := NewEndgameState()
+	if got := runSolveCommand(e, ""); !strings.Contains(got, "Nothing has been hidden") {
+		t.Errorf("Expected nothing to solve yet, got %q", got)
+	}
+	for _, puzzle := range arg.Puzzles {
+		e.argGame().Plant(puzzle.ID)
+	}
+
+	tests := []struct {
+		args string
+		want string
+	}{
+		{"", "solve <cache|album|diary|maze|typing>"},
+		{"attic KEY", "no puzzle called"},
+		{"cache WRONG", "isn't it"},
+		{"cache " + strings.ToLower(e.ARG.Answer(arg.PuzzleCache)), "1 of 5"},
+		{"album " + e.ARG.Answer(arg.PuzzleAlbum), "2 of 5"},
+		{"DIARY " + e.ARG.Answer(arg.PuzzleDiary), "3 of 5"},
+		{"maze " + arg.Rot13(arg.DoorInscription(e.ARG.Answer(arg.PuzzleMaze))), "4 of 5"},
+		{"typing " + e.ARG.Answer(arg.PuzzleTyping), "Down the Rabbit Hole"},
+	}
+	for _, tt := range tests {
+		if got := runSolveCommand(e, tt.args); !strings.Contains(got, tt.want) {
+			t.Errorf("Expected %q for %q, got:\n%s", tt.want, tt.args, got)
+		}
+	}
+}
+
+func TestAlbumExportCarriesComment(t *testing.T) {
+	t.Chdir(t.TempDir())
+	pet := NewPet("Mochi")
+	pet.Album = &AlbumState{Photos: []Photo{{Caption: "First steps", Frame: "(o_o)", Taken: time.Now()}}}
+
+	paths, _ := exportAlbum(pet, true)
+	if strings.Contains(string(mustRead(t, paths[0])), "exif") {
+		t.Errorf("Expected no comment before the album puzzle is out")
+	}
+
+	pet.Endgame.argGame().Plant(arg.PuzzleAlbum)
+	paths, _ = exportAlbum(pet, true)
+	html := string(mustRead(t, paths[0]))
+	if !strings.Contains(html, arg.AlbumComment(pet.Endgame.ARG.Answer(arg.PuzzleAlbum))) {
+		t.Errorf("Expected the camera comment in the HTML, got:\n%s", html)
+	}
+}
+
+func TestDiaryMarginsHideMorse(t *testing.T) {
+	path := filepath.Join(t.TempDir(), diaryFile)
+	pet := NewPet("Mochi")
+	pet.Stage = Adult
+	pet.Endgame.argGame().Plant(arg.PuzzleDiary)
+	none := func(time.Time) diaryFacts { return diaryFacts{} }
+	pet.WriteDiary(path, time.Date(2026, 10, 16, 20, 0, 0, 0, time.Local), none, rand.New(rand.NewSource(1)))
+
+	data := string(mustRead(t, path))
+	entry := strings.SplitN(strings.TrimRight(data, "\n"), "\n\n", 2)[0]
+	lines := strings.Split(entry, "\n")[1:]
+	if got := arg.DecodeWhitespace(lines); got != pet.Endgame.ARG.Answer(arg.PuzzleDiary) {
+		t.Errorf("Expected the answer in the margins, got %q from %q", got, entry)
+	}
+	if shown := runDiaryCommand(path); strings.ContainsAny(shown, "\t") || strings.Contains(shown, " \n") {
+		t.Errorf("Expected the diary command to show clean lines, got %q", shown)
+	}
+}
@@ -0,0 +1,1268 @@
+package main
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"slices"
+	"strings"
+	"time"
+)
+
+// commandContext carries everything a command may touch while it runs
+type commandContext struct {
+	pet       *Pet
+	reader    *bufio.Reader
+	ui        *uiConfig
+	territory *territoryWatcher
+	commands  *commandRegistry
+	backup    *backupConfig // nil when backups aren't configured
+	digest    *digestConfig // nil when no mail server is configured
+	packs     *packIndex    // nil when no pack index is configured
+	args      string        // Everything after the command word
+	quit      bool          // Set by commands that end the game loop
+	events    []string      // Animation events raised by the command, e.g. fed
+	recap     *sessionRecap // What this session did, for the summary on quit; nil outside the game loop
+	session   *petSession   // Held while the command runs; nil outside the game loop
+}
+
+// wait lets the autosave have the pet while fn blocks on the player.
+// Don't touch the pet inside fn.
+func (ctx *commandContext) wait(fn func()) {
+	ctx.session.Wait(fn)
+}
+
+// readLine reads a line from the player without holding the pet
+func (ctx *commandContext) readLine() string {
+	var line string
+	ctx.wait(func() { line, _ = ctx.reader.ReadString('\n') })
+	return line
+}
+
+// emit records an event for the game loop to animate after the command
+func (ctx *commandContext) emit(event string) {
+	ctx.events = append(ctx.events, event)
+}
+
+// Command is a single game command
+type Command interface {
+	// Name is the canonical word that runs the command
+	Name() string
+	// Aliases are other words that run the command
+	Aliases() []string
+	// Help documents the command for menus and `help <command>`
+	Help() CommandDoc
+	// RequiresUpdate reports whether the pet should be brought up to date first
+	RequiresUpdate() bool
+	// Run performs the command and returns a message to show, or ""
+	Run(ctx *commandContext) string
+}
+
+// basicCommand implements Command from a doc and a run function
+type basicCommand struct {
+	doc    CommandDoc
+	update bool
+	run    func(ctx *commandContext) string
+}
+
+func (c *basicCommand) Name() string                   { return c.doc.Name }
+func (c *basicCommand) Aliases() []string              { return c.doc.Aliases }
+func (c *basicCommand) Help() CommandDoc               { return c.doc }
+func (c *basicCommand) RequiresUpdate() bool           { return c.update }
+func (c *basicCommand) Run(ctx *commandContext) string { return c.run(ctx) }
+
+// commandRegistry maps command words to commands, keeping registration order for menus
+type commandRegistry struct {
+	commands []Command
+	byWord   map[string]Command
+}
+
+// newCommandRegistry creates an empty registry
+func newCommandRegistry() *commandRegistry {
+	return &commandRegistry{byWord: make(map[string]Command)}
+}
+
+// Register adds a command, refusing words another command already owns
+func (r *commandRegistry) Register(cmd Command) error {
+	words := append([]string{cmd.Name()}, cmd.Aliases()...)
+	for _, word := range words {
+		if owner, exists := r.byWord[word]; exists {
+			return fmt.Errorf("command word %q already belongs to %s", word, owner.Name())
+		}
+	}
+	for _, word := range words {
+		r.byWord[word] = cmd
+	}
+	r.commands = append(r.commands, cmd)
+	return nil
+}
+
+// Lookup returns the command for a name or alias, or nil
+func (r *commandRegistry) Lookup(word string) Command {
+	return r.byWord[strings.ToLower(strings.TrimSpace(word))]
+}
+
+// Commands returns every command in registration order
+func (r *commandRegistry) Commands() []Command {
+	return r.commands
+}
+
+// newDefaultRegistry registers the built-in commands; a clash is a programming error
+func newDefaultRegistry() *commandRegistry {
+	r := newCommandRegistry()
+	for _, cmd := range builtinCommands() {
+		if err := r.Register(cmd); err != nil {
+			panic(err)
+		}
+	}
+	return r
+}
+
+// withEndgame runs an endgame action if the pet has endgame state
+func withEndgame(ctx *commandContext, action func(*EndgameState) string) string {
+	if ctx.pet.Endgame == nil {
+		return ""
+	}
+	return action(ctx.pet.Endgame)
+}
+
+// resetPet asks for confirmation and hatches a new pet in place
+func resetPet(ctx *commandContext) string {
+	fmt.Print("\nThis will erase your pet history and start over. Type YES to confirm: ")
+	confirm := strings.TrimSpace(strings.ToUpper(ctx.readLine()))
+	if confirm != "YES" {
+		return "Reset cancelled. Your pet breathes a sigh of relief."
+	}
+
+	fmt.Print("Name your new pet: ")
+	newName := strings.TrimSpace(ctx.readLine())
+	if newName == "" {
+		newName = "Tamago"
+	}
+
+	// Restart network and pet state in-place to keep autosave goroutine valid
+	shutdownNetwork()
+	documentFears(ctx.pet, fearBookFile, time.Now())
+	heirlooms := ctx.pet.heirlooms()
+	favorite := ctx.pet.favoriteToPass()
+	lineage := append(slices.Clone(ctx.pet.Lineage), ctx.pet.asAncestor(time.Now()))
+	ctx.pet.Reset(newName)
+	for _, trait := range heirlooms {
+		ctx.pet.AddTrait(trait)
+	}
+	ctx.pet.inheritFavorite(favorite)
+	ctx.pet.Lineage = lineage
+	useContentPacks(ctx.pet)
+	ctx.territory.SetPaths(nil)
+	initNetwork(ctx.pet)
+	_ = os.Remove(saveFile) // clear any lingering history; save will rewrite
+	if err := ctx.pet.Save(); err != nil {
+		return fmt.Sprintf("❌ Failed to start fresh: %v", err)
+	}
+	message := fmt.Sprintf("♻️ History cleared. Say hi to your new pet: %s", newName)
+	if len(heirlooms) > 0 {
+		message += fmt.Sprintf(". It carries something of the last one: %s.", listNames(heirlooms))
+	}
+	if favorite != "" {
+		message += fmt.Sprintf(" 🧬 It already loves %s, like its parent.", favorite)
+	}
+	for _, power := range ctx.pet.unlockBloodline() {
+		message += "\n" + power
+	}
+	return message
+}
+
+// quitGame saves everything before the game loop exits, then sums up the
+// session and offers to keep the summary for the weekly report
+func quitGame(pet *Pet, ui *uiConfig, reader *bufio.Reader, session *petSession, recap *sessionRecap) {
+	fmt.Println(ui.text("\n💾 Saving your pet..."))
+	pet.Update()
+	saveNetworkState(pet) // Save hidden network state
+	// Update play time before saving
+	if pet.Endgame != nil {
+		pet.Endgame.UpdatePlayTime()
+	}
+	if err := pet.Save(); err != nil {
+		fmt.Println(ui.text(fmt.Sprintf("❌ Error saving: %v", err)))
+	} else {
+		fmt.Println(ui.text("✅ Saved successfully!"))
+	}
+	if recap == nil {
+		return
+	}
+
+	now := time.Now()
+	seen := friendsSeenSince(recap.start.At)
+	fmt.Println(ui.text(recap.render(pet, now, seen)))
+	if pet.Endgame == nil || reader == nil {
+		return
+	}
+	fmt.Print(ui.text("📋 Add this to your weekly report? (y/N): "))
+	var answer string
+	session.Wait(func() { answer, _ = reader.ReadString('\n') })
+	if strings.EqualFold(strings.TrimSpace(answer), "y") {
+		pet.Endgame.SessionNotes = append(pet.Endgame.SessionNotes, recap.note(pet, now, seen))
+		reportSave(pet.Save(), ui)
+		fmt.Println(ui.text("📋 Noted. It'll be in the next report."))
+	}
+}
+
+// unknownCommand handles input no command claimed. Secret codes and fears get
+// first look so a deliberately mangled fear trigger still scares the pet; only
+// then is an unambiguous typo accepted as the command it was meant to be. The
+// returned command is nil unless a typo was corrected.
+func unknownCommand(pet *Pet, commands *commandRegistry, command, verb string) (Command, string) {
+	if pet.Absurd != nil && !classroomMode {
+		// Check for Konami code progress
+		if activated, konamiMessage := pet.Absurd.ProcessKonamiInput(command); activated {
+			return nil, konamiMessage
+		}
+		// Check for fear triggers
+		if fear := pet.Absurd.CheckFearTrigger(command); fear != nil {
+			if pet.isStrength(fear.Name) {
+				return nil, pet.steadied(*fear)
+			}
+			if pet.Absurd.FearTriggers == nil {
+				pet.Absurd.FearTriggers = make(map[string]int)
+			}
+			pet.Absurd.FearTriggers[fear.Name]++
+			message := fmt.Sprintf("😱 Your pet trembles! It has %s: %s", fear.Name, fear.Description)
+			return nil, strings.TrimSpace(message + "\n" + pet.RecordChallengeEvent("fear", time.Now()))
+		}
+	}
+
+	if cmd := commands.Correct(verb); cmd != nil {
+		return cmd, fmt.Sprintf("✏️  '%s'? Assuming you meant '%s'.", verb, cmd.Name())
+	}
+	if suggestion := commands.Suggest(verb); suggestion != "" {
+		return nil, fmt.Sprintf("❓ Unknown command. Did you mean '%s'? Type 'help' to see available commands.", suggestion)
+	}
+	return nil, "❓ Unknown command. Type 'help' to see available commands."
+}
+
+// runCommand runs a command unless the pet is unavailable for care
+func runCommand(cmd Command, ctx *commandContext) string {
+	// Care has to wait while the pet is away or suspended
+	if unavailable := ctx.pet.unavailableMessage(time.Now()); unavailable != "" && isCareCommand(cmd.Name()) {
+		return unavailable
+	}
+	if cmd.RequiresUpdate() {
+		ctx.pet.Update()
+	}
+	return cmd.Run(ctx)
+}
+
+// builtinCommands returns every built-in command in menu order. Commands added
+// here get a menu line and a help page automatically.
+func builtinCommands() []Command {
+	return []Command{
+		&basicCommand{
+			doc: CommandDoc{
+				Name: "feed", Aliases: []string{"f"}, Section: sectionMain,
+				Summary:      "Feed your pet 🍔",
+				Details:      "Reduces hunger by 30 and adds a little happiness. A full pet politely declines. Name a food (kibble, apple, fish, noodles, cake, broccoli, bugs) or get kibble. After a few meals of a food the pet makes up its mind: favorites bring extra happiness, and forcing a food it hates makes it anxious. See 'favorites'.",
+				Examples:     []string{"feed", "f", "feed fish"},
+				Achievements: []string{"first_feed"},
+				Lore:         "The food is imaginary. The gratitude is not.",
+			},
+			update: true,
+			run: func(ctx *commandContext) string {
+				pet := ctx.pet
+				hunger := pet.Hunger
+				message := pet.Feed(ctx.args)
+				if pet.Hunger < hunger {
+					ctx.emit("fed")
+				}
+				if pet.Endgame != nil {
+					pet.Endgame.UnlockAchievement("first_feed")
+				}
+				return message
+			},
+		},
+		&basicCommand{
+			doc: CommandDoc{
+				Name: "play", Aliases: []string{"p"}, Section: sectionMain,
+				Summary:      "Play with your pet 🎮",
+				Details:      "Raises happiness at the cost of some hunger and cleanliness.",
+				Examples:     []string{"play"},
+				Achievements: []string{"play_10"},
+				Lore:         "Every game ends in a draw. Your pet lets you think you won.",
+			},
+			update: true,
+			run: func(ctx *commandContext) string {
+				return ctx.pet.Play()
+			},
+		},
+		&basicCommand{
+			doc: CommandDoc{
+				Name: "clean", Aliases: []string{"c"}, Section: sectionMain,
+				Summary:  "Clean up after your pet 🛁",
+				Details:  "Restores cleanliness. Dirty pets get sick more easily.",
+				Examples: []string{"clean"},
+				Lore:     "Nobody knows where the mess comes from. Nobody asks.",
+			},
+			update: true,
+			run: func(ctx *commandContext) string {
+				cleanliness := ctx.pet.Cleanliness
+				message := ctx.pet.Clean()
+				if ctx.pet.Cleanliness > cleanliness {
+					ctx.emit("bathed")
+				}
+				return message
+			},
+		},
+		&basicCommand{
+			doc: CommandDoc{
+				Name: "heal", Aliases: []string{"h", "medicine", "med"}, Section: sectionMain,
+				Summary:  "Give medicine to your pet 💊",
+				Details:  "Cures sickness and restores some health. Left untreated, sickness turns serious after a day and critical after three; a critical pet needs two doses, and one weak enough is past medicine altogether (see comfort).",
+				Examples: []string{"heal", "med"},
+				Lore:     "The medicine tastes of semicolons.",
+			},
+			update: true,
+			run: func(ctx *commandContext) string {
+				return ctx.pet.Heal()
+			},
+		},
+		&basicCommand{
+			doc: CommandDoc{
+				Name: "checkup", Aliases: []string{"vet"}, Section: sectionMain,
+				Summary:  "Take your pet for its weekly checkup 🩺",
+				Details:  "Once a week. For five days afterwards your pet usually shrugs off sickness, and it's immune to outbreaks going round the mesh. Between checkups it says when the next one is due.",
+				Examples: []string{"checkup", "vet"},
+				Lore:     "The sticker is the important part.",
+			},
+			update: true,
+			run: func(ctx *commandContext) string {
+				return ctx.pet.Checkup(time.Now())
+			},
+		},
+		&basicCommand{
+			doc: CommandDoc{
+				Name: "favorites", Aliases: []string{"faves"}, Section: sectionMain,
+				Summary:  "See what your pet thinks of its food 😍",
+				Details:  "Lists every food with how often it's been fed and, once the pet has made up its mind, whether it's a favorite, an aversion, or neither. Shows a favorite inherited from the last pet and any anxiety from forced meals.",
+				Examples: []string{"favorites"},
+				Lore:     "It will tell you. Eventually. Mostly with its face.",
+			},
+			run: func(ctx *commandContext) string {
+				return ctx.pet.Favorites()
+			},
+		},
+		&basicCommand{
+			doc: CommandDoc{
+				Name: "comfort", Section: sectionMain,
+				Summary:  "Comfort a pet near the end 🕯️",
+				Details:  "Only for a pet in hospice, past medicine. comfort hold, sing, blanket, or window: none of them cure, but they lift its spirits, shape its last words, and each leaves a trait for the next pet you raise.",
+				Examples: []string{"comfort hold", "comfort window"},
+				Lore:     "It doesn't need anything fixed. It needs you there.",
+			},
+			update: true,
+			run: func(ctx *commandContext) string {
+				return ctx.pet.Comfort(ctx.args)
+			},
+		},
+		&basicCommand{
+			doc: CommandDoc{
+				Name: "warm", Section: sectionMain,
+				Summary:  "Warm the egg 🌡️",
+				Details:  "Raises the egg's warmth. Eggs kept between 40° and 60° hatch sooner and calmer.",
+				Examples: []string{"warm"},
+				Lore:     "The egg hums when it is happy. You may have imagined this.",
+			},
+			update: true,
+			run: func(ctx *commandContext) string {
+				return ctx.pet.Warm()
+			},
+		},
+		&basicCommand{
+			doc: CommandDoc{
+				Name: "cool", Section: sectionMain,
+				Summary:  "Cool the egg 🌬️",
+				Details:  "Lowers the egg's warmth if you overdid it.",
+				Examples: []string{"cool"},
+				Lore:     "An overheated egg hatches fiery. You were warned.",
+			},
+			update: true,
+			run: func(ctx *commandContext) string {
+				return ctx.pet.Cool()
+			},
+		},
+		&basicCommand{
+			doc: CommandDoc{
+				Name: "candle", Aliases: []string{"candling"}, Section: sectionMain,
+				Summary:  "Peek inside the egg 🕯️",
+				Details:  "Shines a light through the egg to hint at the personality forming inside.",
+				Examples: []string{"candle"},
+				Lore:     "Something inside looks back.",
+			},
+			update: true,
+			run: func(ctx *commandContext) string {
+				return renderCandling(ctx.pet, ctx.ui)
+			},
+		},
+		&basicCommand{
+			doc: CommandDoc{
+				Name: "status", Aliases: []string{"s", "stats"}, Section: sectionMain,
+				Summary:  "Check your pet's status 📊",
+				Details:  "Refreshes the stats panel.",
+				Examples: []string{"status"},
+			},
+			update: true,
+			run: func(ctx *commandContext) string {
+				return "" // Status is already displayed
+			},
+		},
+		&basicCommand{
+			doc: CommandDoc{
+				Name: "look", Aliases: []string{"zoom", "camera"}, Section: sectionMain,
+				Summary:  "Move the camera 🔍",
+				Details:  "Cycles between the normal view, a close-up, and a wide shot of the landscape. Name a view to jump straight to it. Close-ups are detailed enough to show things that are usually hidden.",
+				Examples: []string{"look", "look close", "look wide"},
+				Lore:     "The closer you look, the more looks back.",
+			},
+			run: func(ctx *commandContext) string {
+				return runLookCommand(ctx)
+			},
+		},
+		&basicCommand{
+			doc: CommandDoc{
+				Name: "graphs", Aliases: []string{"graph", "trends"}, Section: sectionMain,
+				Summary:  "Stat trends over time 📈",
+				Details:  "Draws a sparkline for each stat from the care log, plus mesh friends online. Defaults to the last 24 hours; 7d shows the week.",
+				Examples: []string{"graphs", "graphs 7d"},
+				Lore:     "Your pet has seen these charts. It would like to discuss Tuesday.",
+			},
+			update: true,
+			run: func(ctx *commandContext) string {
+				return runGraphsCommand(ctx.pet, ctx.ui, ctx.args)
+			},
+		},
+		&basicCommand{
+			doc: CommandDoc{
+				Name: "album", Aliases: []string{"photos"}, Section: sectionMain,
+				Summary:  "Browse the photo album 📸",
+				Details:  "Big moments are photographed as they happen: hatching, each time your pet grows up, its first friend, enlightenment, and The Look. Each photo keeps the art as it was and a caption. 'album export' saves them as text, and 'album export html' adds an HTML page.",
+				Examples: []string{"album", "album 1", "album export html"},
+			},
+			update: true,
+			run: func(ctx *commandContext) string {
+				return runAlbumCommand(ctx.pet, ctx.args)
+			},
+		},
+		&basicCommand{
+			doc: CommandDoc{
+				Name: "family", Aliases: []string{"tree"}, Section: sectionMain,
+				Summary:  "See your pet's family tree 🌳",
+				Details:  "Every pet you reset becomes an ancestor of the next. family draws the line from the first to now: how each one lived and ended, its care score, its traits, and what it passed down. family dot writes the tree to tamagotchi_family.dot for Graphviz.",
+				Examples: []string{"family", "family dot"},
+				Lore:     "Somewhere at the top is an egg nobody remembers laying.",
+			},
+			update: true,
+			run: func(ctx *commandContext) string {
+				return runFamilyCommand(ctx.pet, ctx.args)
+			},
+		},
+		&basicCommand{
+			doc: CommandDoc{
+				Name: "bloodline", Section: sectionMain,
+				Summary:  "The powers your line has earned 🧬",
+				Details:  "A line that lasts earns powers. Generation 3 gets Second Sight: see one invisible accessory. Generation 5 gets Steady Nerve: one fear becomes a strength. Generation 7 gets Ancestral Memory: read one diary page that's stuck together. Each is used once, on the one thing you choose.",
+				Examples: []string{"bloodline", "bloodline see Invisible Crown", "bloodline steel Qphobia", "bloodline read"},
+				Lore:     "Nobody knows what generation 9 gets. Nobody has lasted that long.",
+			},
+			update: true,
+			run: func(ctx *commandContext) string {
+				return runBloodlineCommand(ctx.pet, ctx.args)
+			},
+		},
+		&basicCommand{
+			doc: CommandDoc{
+				Name: "excavate", Aliases: []string{"dreams"}, Section: sectionMain,
+				Summary:  "Dig an old save for ancestral dreams ⛏️",
+				Details:  "Point excavate at an old save, or a pet a friend sent you with migrate, and it digs out fragments: the fears that pet had, its last prophecy, its first words, the branches it overheard, its ancestors' last words, its photo captions. Nothing else is taken. Your pet dreams them one at a time in place of a thought, and excavate on its own (or dreams) lists the ones dreamt so far and where each was dug up. Each save can be dug once.",
+				Examples: []string{"excavate old_tamagotchi_save.json", "excavate friend.pet", "dreams"},
+				Lore:     "Some of the fears were never anyone's. They were just waiting in the file.",
+			},
+			update: true,
+			run: func(ctx *commandContext) string {
+				return runExcavateCommand(ctx.pet, ctx.args)
+			},
+		},
+		&basicCommand{
+			doc: CommandDoc{
+				Name: "export", Section: sectionMain,
+				Summary:  "Give your pet a homepage 🌐, a feed 📰, or a calendar 📅",
+				Details:  "export site writes a single self-contained HTML page: your pet's portrait, stats, achievements, its family tree back through every pet you reset, and a memorial for the ones that died, in the family and on the mesh. It lands in tamagotchi_site/index.html, ready to push to GitHub Pages. export feed writes an Atom feed of its evolutions, sicknesses, new friends and deaths witnessed to tamagotchi_feed.atom beside the save, and autosave keeps it current until you delete it. export calendar does the same for tamagotchi_calendar.ics: the next evolution, the birthday, the countdown, community events and the quest's end, for your calendar app.",
+				Examples: []string{"export site", "export feed", "export calendar"},
+				Lore:     "It has opinions about the font.",
+			},
+			update: true,
+			run: func(ctx *commandContext) string {
+				return runExportCommand(ctx.ui, ctx.pet, ctx.args)
+			},
+		},
+		&basicCommand{
+			doc: CommandDoc{
+				Name: "diary", Section: sectionMain,
+				Summary:  "Read your pet's diary 📔",
+				Details:  "Your pet writes in its diary once a day: how it felt, photos taken, trips, pets met and lost on the mesh, and things it made up. This shows the latest five entries it's willing to share.",
+				Examples: []string{"diary"},
+				Lore:     "Diaries are written beside the save file. Not every page makes it into the game.",
+			},
+			update: true,
+			run: func(ctx *commandContext) string {
+				return runDiaryCommand(diaryFile)
+			},
+		},
+		&basicCommand{
+			doc: CommandDoc{
+				Name: "history", Aliases: []string{"timeline"}, Section: sectionMain,
+				Summary:  "Look back over your pet's life 📜",
+				Details:  "A timeline of the last twenty moments: hatching and growing up, trips, and life on the mesh. Pets met, dreams shared, deaths heard of, and community events are kept in the save, up to the last hundred.",
+				Examples: []string{"history"},
+			},
+			run: func(ctx *commandContext) string {
+				return runHistoryCommand(ctx.pet)
+			},
+		},
+		&basicCommand{
+			doc: CommandDoc{
+				Name: "remember", Section: sectionMain,
+				Summary:  "Keep a memory of the mesh for good 🧠",
+				Details:  "Lists the friends, shared dreams, and deaths your pet remembers, each with a short ID. Memories older than a month beyond the fifty most recent of each kind may fade, and if the save grows too big the oldest go first. 'remember <id>' marks one as a favorite that is never forgotten.",
+				Examples: []string{"remember", "remember 3f9a1c"},
+			},
+			run: func(ctx *commandContext) string {
+				return runRememberCommand(ctx.args)
+			},
+		},
+		&basicCommand{
+			doc: CommandDoc{
+				Name: "report", Aliases: []string{"weekly"}, Section: sectionMain,
+				Summary:      "Weekly care report 📋",
+				Details:      "Shows the latest weekly report: care score, near-misses, moods, new friends, deaths witnessed, and a grade. 'report preview' grades the last 7 days so far; 'report export' saves it as text, and 'report export html' adds an HTML page.",
+				Examples:     []string{"report", "report preview", "report export html"},
+				Achievements: []string{"perfect_week"},
+				Lore:         "Your pet grades on a curve. You are the curve.",
+			},
+			update: true,
+			run: func(ctx *commandContext) string {
+				return runReportCommand(ctx.pet, ctx.args)
+			},
+		},
+		&basicCommand{
+			doc: CommandDoc{
+				Name: "pet", Aliases: []string{"pat"}, Section: sectionMain,
+				Summary:      "Pet your pet 🐾",
+				Details:      "Pets your pet. That's it. That's the command. (Petting it soon after a dark thought cheers it up.)",
+				Examples:     []string{"pet"},
+				Achievements: []string{"pet_17"},
+				Lore:         "Seventeen is the number. Remember this.",
+			},
+			update: true,
+			run: func(ctx *commandContext) string {
+				ctx.emit("petted")
+				if ctx.pet.Absurd == nil {
+					return "You pet your pet. It seems pleased."
+				}
+				if comfort := ctx.pet.comfortAfterDarkThought(time.Now()); comfort != "" {
+					return comfort + "\n" + ctx.pet.Absurd.PetThePet()
+				}
+				return ctx.pet.Absurd.PetThePet()
+			},
+		},
+		&basicCommand{
+			doc: CommandDoc{
+				Name: "say", Aliases: []string{"talk"}, Section: sectionMain,
+				Summary:      "Talk to your pet (say hello) 💬",
+				Details:      "Your pet answers based on its mood, fears, memories, and what it hears on the mesh. Some phrases unlock secrets.",
+				Examples:     []string{"say hello", "say how are you?", "say do you remember?"},
+				Achievements: []string{"whisperer"},
+				Lore:         "It has been waiting to hear certain words. It won't say which.",
+			},
+			update: true,
+			run: func(ctx *commandContext) string {
+				reply := ctx.pet.Say(ctx.args, newConversationContext())
+				ctx.ui.speak(ctx.pet, reply)
+				return fmt.Sprintf("💬 %s: %s", ctx.pet.Name, reply)
+			},
+		},
+		&basicCommand{
+			doc: CommandDoc{
+				Name: "voice", Aliases: []string{"tts"}, Section: sectionMain,
+				Summary:  "Toggle spoken pet speech 🔊",
+				Details:  "Speaks thoughts and replies aloud through espeak, macOS say, or Windows SAPI. Silent at night and in screen-reader mode.",
+				Examples: []string{"voice", "voice on", "voice off"},
+			},
+			run: func(ctx *commandContext) string {
+				return ctx.ui.toggleVoice(ctx.args)
+			},
+		},
+		&basicCommand{
+			doc: CommandDoc{
+				Name: "games", Aliases: []string{"game", "minigames", "mini"}, Section: sectionMain,
+				Summary:  "Play useless mini-games 🎲",
+				Details:  "A selection of mini-games with no lasting consequences whatsoever, except two: steer your pet out of a maze with the arrow keys, or copy one of its thoughts before it fades in the typing race, and it's genuinely happier for it.",
+				Examples: []string{"games"},
+			},
+			update: true,
+			run: func(ctx *commandContext) string {
+				result := SelectAndPlayMiniGame(ctx.reader, ctx.session, ctx.ui, ctx.pet)
+				if result == nil {
+					return ""
+				}
+				return result.Message
+			},
+		},
+		&basicCommand{
+			doc: CommandDoc{
+				Name: "train", Aliases: []string{"school", "skills"}, Section: sectionMain,
+				Summary:  "Send your pet to school 🎓",
+				Details:  "Trains a skill in 30-minute sessions, one at a time. Foraging slows hunger, meditation lets the vibe check recover, and networking makes gossip count for more influence on the mesh. Each level takes one more session than the last, up to level 5. On its own, shows every skill's progress.",
+				Examples: []string{"train", "train foraging", "train meditation"},
+			},
+			update: true,
+			run: func(ctx *commandContext) string {
+				return runTrainCommand(ctx, time.Now())
+			},
+		},
+		&basicCommand{
+			doc: CommandDoc{
+				Name: "void", Aliases: []string{"stare"}, Section: sectionMain,
+				Summary:      "Stare into the void 👁️",
+				Details:      "Your pet stares into the void. Sometimes it finds clarity.",
+				Examples:     []string{"void"},
+				Achievements: []string{"void_gaze", "enlightened"},
+				Lore:         "The void stares back. It seems nice.",
+			},
+			update: true,
+			run: func(ctx *commandContext) string {
+				pet := ctx.pet
+				if pet.Absurd == nil {
+					return "You stare into the void. It's just darkness."
+				}
+				message := pet.Absurd.StartsIntoVoid()
+				pet.Absurd.StopStaringIntoVoid()
+				if pet.Endgame != nil {
+					pet.Endgame.UnlockAchievement("void_gaze")
+					if pet.Absurd.HasAchievedClarity {
+						pet.Endgame.UnlockAchievement("enlightened")
+					}
+				}
+				return message
+			},
+		},
+		&basicCommand{
+			doc: CommandDoc{
+				Name: "vibe", Aliases: []string{"vibecheck"}, Section: sectionMain,
+				Summary:  "Perform a vibe check ✨",
+				Details:  "A marker swings across a bar; press Enter when it's centered. Your timing counts for most of the result and the cosmic alignment for the rest. Recent vibe checks set the vibe trend: good vibes make happiness wear off more slowly, bad ones faster.",
+				Examples: []string{"vibe"},
+			},
+			update: true,
+			run: func(ctx *commandContext) string {
+				return runVibeCheck(ctx.pet, ctx.reader, ctx.session, ctx.ui)
+			},
+		},
+		&basicCommand{
+			doc: CommandDoc{
+				Name: "fears", Aliases: []string{"fear"}, Section: sectionMain,
+				Summary:  "View pet's irrational fears 😰",
+				Details:  "Lists the fears your pet was born with or picked up, then the fear encyclopedia: every fear any of your pets has had, across generations, with the rest left as ???. Name a fear to read its page: its lore, how many pets had it, how often it was triggered, and a hint at a cure. Typing a fear's trigger frightens it.",
+				Examples: []string{"fears", "fears Tuesdread"},
+				Lore:     "Fear of force-pushes is learned, not innate.",
+			},
+			update: true,
+			run: func(ctx *commandContext) string {
+				return runFearsCommand(ctx.pet, ctx.args, fearBookFile, time.Now())
+			},
+		},
+		&basicCommand{
+			doc: CommandDoc{
+				Name: "???", Aliases: []string{"mystery", "mystats"}, Section: sectionMain,
+				Summary:  "View mystery stats 🔮",
+				Details:  "Shows stats that rise and fall for reasons nobody can explain.",
+				Examples: []string{"???"},
+			},
+			update: true,
+			run: func(ctx *commandContext) string {
+				if ctx.pet.Absurd == nil {
+					return "No mystery stats available. This is also mysterious."
+				}
+				return ctx.pet.Absurd.GetMysteryStatsDisplay()
+			},
+		},
+		&basicCommand{
+			doc: CommandDoc{
+				Name: "focus", Aliases: []string{"pomodoro"}, Section: sectionMain,
+				Summary:      "Focus timer, pet naps alongside 🍅",
+				Details:      "Starts a focus session (default 25 minutes, up to 120). Finish it for happiness; quit early and your pet is disappointed.",
+				Examples:     []string{"focus", "focus 50"},
+				Achievements: []string{"focus_1", "focus_5", "focus_25"},
+			},
+			update: true,
+			run: func(ctx *commandContext) string {
+				minutes, err := parseFocusMinutes(ctx.args)
+				if err != nil {
+					return "❓ " + err.Error()
+				}
+				return runFocusSession(ctx.pet, ctx.reader, ctx.session, ctx.ui, minutes)
+			},
+		},
+		&basicCommand{
+			doc: CommandDoc{
+				Name: "territory", Aliases: []string{"claim"}, Section: sectionMain,
+				Summary:  "Let your pet guard a directory 🗺️",
+				Details:  "Lets your pet watch up to three directories. It only ever looks at file names, sizes, and times.",
+				Examples: []string{"territory"},
+				Lore:     "It pretends to be asleep when files change.",
+			},
+			update: true,
+			run: func(ctx *commandContext) string {
+				return manageTerritory(ctx.pet, ctx.territory, ctx.reader, ctx.session, ctx.ui)
+			},
+		},
+		&basicCommand{
+			doc: CommandDoc{
+				Name: "constellation", Aliases: []string{"sky", "mesh"}, Section: sectionMain,
+				Summary:  "Map the pets your pet has met ✨",
+				Details:  "Plots every pet met on the mesh as a star. Brighter stars were seen more recently, lines join pets that share dreams, and a star pulses when gossip arrives from it. Name a star by letter to look closer.",
+				Examples: []string{"constellation", "constellation B"},
+				Lore:     "Every star is somebody's terminal, left on.",
+			},
+			run: func(ctx *commandContext) string {
+				return runConstellationCommand(ctx.ui, ctx.args)
+			},
+		},
+		&basicCommand{
+			doc: CommandDoc{
+				Name: "whisper", Section: sectionMain,
+				Summary:  "Whisper to a pet your pet has met 💌",
+				Details:  "Sends a few words to one pet on the mesh. If it's away, your pet holds the whisper for a day and delivers it when the pet comes back, and hands a copy to a friend who has met it to carry.",
+				Examples: []string{"whisper Mochi see you tomorrow"},
+			},
+			run: func(ctx *commandContext) string {
+				return runWhisperCommand(ctx.args)
+			},
+		},
+		&basicCommand{
+			doc: CommandDoc{
+				Name: "trust", Section: sectionMain,
+				Summary:  "Check on friends who came back different 🔐",
+				Details:  "Your pet remembers each friend's key the first time they meet. A friend that comes back with a different key is kept at a distance, and nothing it sends gets through. trust lists those pets; trust <name> accepts the new key.",
+				Examples: []string{"trust", "trust Mochi"},
+				Lore:     "It knows its friends by more than their names.",
+			},
+			run: func(ctx *commandContext) string {
+				return runTrustCommand(ctx.args)
+			},
+		},
+		&basicCommand{
+			doc: CommandDoc{
+				Name: "census", Section: sectionMain,
+				Summary:  "Count the pets awake on the mesh 📊",
+				Details:  "Shows roughly how many pets are awake on the mesh, how many are at each stage, how old they are on average, and how many have died. Pets tally who they can hear under a code that changes daily and swap tallies every few minutes, so pets out of earshot still get counted. Small groups are folded together, and a mesh of fewer than five pets gets no breakdown at all.",
+				Examples: []string{"census"},
+				Lore:     "Nobody volunteers to be counted. Everybody is.",
+			},
+			run: func(ctx *commandContext) string {
+				return runCensusCommand()
+			},
+		},
+		&basicCommand{
+			doc: CommandDoc{
+				Name: "sensors", Section: sectionMain,
+				Summary:  "Let your pet notice the machine itself 🖥️",
+				Details:  "Off until you switch it on. With sensors on, your pet notices how busy the machine is, how long it has been up, and how full the disk holding the save is, and has opinions about all three. Only rough levels are read, through a small sensors package that reads nothing else, and none of it leaves this machine.",
+				Examples: []string{"sensors", "sensors on", "sensors off"},
+				Lore:     "It has always heard the fans. Now it's allowed to mention them.",
+			},
+			run: func(ctx *commandContext) string {
+				return runSensorsCommand(ctx.pet, ctx.args)
+			},
+		},
+		&basicCommand{
+			doc: CommandDoc{
+				Name: "signal", Section: sectionMain,
+				Summary:  "Listen for a signal 📡",
+				Details:  "Shows whether your pet can hear the mesh and which of its friends are online right now. There's more in the signal than that, for a pet that has found clarity.",
+				Examples: []string{"signal"},
+				Lore:     "It was listening long before you noticed.",
+			},
+			run: func(ctx *commandContext) string {
+				return runSignalCommand(ctx.pet)
+			},
+		},
+		&basicCommand{
+			doc: CommandDoc{
+				Name: "job", Aliases: []string{"work", "jobs"}, Section: sectionMain,
+				Summary:  "Give your adult pet a job 💼",
+				Details:  "Adult pets can work as a night-shift void watcher, a JSON formatter, or a dream courier. They only work while you're away: come back to TamaCoins, things they found, stories from the shift, and a tired pet. Too much fatigue and they stop going in.",
+				Examples: []string{"job", "job take courier", "job quit"},
+			},
+			update: true,
+			run: func(ctx *commandContext) string {
+				return runJobCommand(ctx.pet, ctx.args, time.Now())
+			},
+		},
+		&basicCommand{
+			doc: CommandDoc{
+				Name: "decorate", Aliases: []string{"room", "furnish"}, Section: sectionMain,
+				Summary:  "Furnish your pet's room 🛋️",
+				Details:  "Shows the room behind your pet and the furniture you own, and places it in the left, middle, or right slot. The window shows the weather outside (the real weather, if TAMAGOTCHI_WEATHER_URL is set), and a lamp keeps the room lit at night, which makes the night easier on your pet. Buy furniture in the shop; some turns up on its own.",
+				Examples: []string{"decorate", "decorate place window left", "decorate remove left"},
+			},
+			update: true,
+			run: func(ctx *commandContext) string {
+				return runDecorateCommand(ctx, time.Now())
+			},
+		},
+		&basicCommand{
+			doc: CommandDoc{
+				Name: "travel", Aliases: []string{"expedition", "trip"}, Section: sectionMain,
+				Summary:  "Send your pet on an expedition 🧭",
+				Details:  "Lists places to go, one for each pet you know on the mesh (the place is worked out from that pet's ID, so everyone sees the same one) plus a few near home. Expeditions take a few hours; your pet is gone from the scene and its stats are frozen. It comes back with a souvenir, a postcard for the journal, and sometimes a new fear, or one fewer.",
+				Examples: []string{"travel", "travel 2", "travel postcards", "travel recall"},
+			},
+			update: true,
+			run: func(ctx *commandContext) string {
+				return runTravelCommand(ctx.pet, ctx.args, knownStars(), time.Now())
+			},
+		},
+		&basicCommand{
+			doc: CommandDoc{
+				Name: "boarding", Aliases: []string{"board", "vacation"}, Section: sectionMain,
+				Summary:  "Send your pet to a friend's (boarding start 7d) 🧳",
+				Details:  "Freezes decay while your pet stays with a friend, then delivers postcards when it returns. Boarding too often hurts its feelings.",
+				Examples: []string{"boarding start 7d", "boarding start 36h", "boarding end", "boarding"},
+			},
+			run: func(ctx *commandContext) string {
+				return runBoardingCommand(ctx.pet, ctx.args)
+			},
+		},
+		&basicCommand{
+			doc: CommandDoc{
+				Name: "privacy", Aliases: []string{"network"}, Section: sectionMain,
+				Summary:  "See and limit what your pet shares on the network 🔒",
+				Details:  "Shows whether the local-network mesh is on, exactly what your pet sends with an example of each message, and how much has gone out this session. privacy off stops the network and keeps it off across restarts; privacy memories off (or dreams, moods, deaths, banners, challenges, census, outbreaks) stops one kind of gossip, and privacy hear deaths off asks other pets not to send your pet that kind. Start the game with --audit-network and network audit shows every packet sent, checked against these settings. If pets can't find each other, network doctor checks the port, broadcasts, and firewall, and privacy tcp on lets your pet look for others over TCP where UDP is blocked.",
+				Examples: []string{"privacy", "privacy off", "privacy moods off", "privacy hear deaths off", "network audit", "network doctor", "privacy tcp on"},
+				Lore:     "It was always going to tell you eventually.",
+			},
+			run: runPrivacyCommand,
+		},
+		&basicCommand{
+			doc: CommandDoc{
+				Name: "custody", Aliases: []string{"coowner"}, Section: sectionMain,
+				Summary:  "Share your pet with a second owner 🤝",
+				Details:  "custody invite <your name> prints a code; the other owner runs `tamagotchi custody join <code> <their name>` on their machine. Chores done on either machine reach the other over the local mesh, and your pet keeps count of who does more. custody leave unlinks.",
+				Examples: []string{"custody", "custody invite Sam", "custody leave"},
+				Lore:     "It knows whose turn it is. It is always keeping score.",
+			},
+			run: func(ctx *commandContext) string {
+				return runCustodyCommand(ctx.pet, ctx.args)
+			},
+		},
+		&basicCommand{
+			doc: CommandDoc{
+				Name: "packs", Aliases: []string{"pack"}, Section: sectionMain,
+				Summary:  "Content packs for thoughts, fears, and quests 📦",
+				Details:  "Lists the content packs in ~/.tamagotchi/packs and which ones this pet uses. With a pack index configured, browses and installs curated packs; every download is checked against the index's signature.",
+				Examples: []string{"packs", "packs available", "packs install wholesome", "packs disable cosmic-horror"},
+				Lore:     "Somebody else wrote some of its thoughts. It hasn't noticed.",
+			},
+			run: func(ctx *commandContext) string {
+				return runPacksCommand(ctx.pet, ctx.packs, ctx.args)
+			},
+		},
+		&basicCommand{
+			doc: CommandDoc{
+				Name: "pause", Aliases: []string{"hibernate"}, Section: sectionMain,
+				Summary:  "Suspend the simulation (no neglect) ⏸️",
+				Details:  "Puts your pet in suspended animation until you resume. Total paused time is tracked in meta stats.",
+				Examples: []string{"pause"},
+				Lore:     "It doesn't dream while paused. It checked.",
+			},
+			run: func(ctx *commandContext) string {
+				return ctx.pet.Pause(time.Now())
+			},
+		},
+		&basicCommand{
+			doc: CommandDoc{
+				Name: "resume", Aliases: []string{"unpause", "wake"}, Section: sectionMain,
+				Summary:  "Wake your pet from suspended animation ▶️",
+				Details:  "Ends a pause. Decay restarts from this moment.",
+				Examples: []string{"resume"},
+			},
+			run: func(ctx *commandContext) string {
+				return ctx.pet.Resume(time.Now())
+			},
+		},
+		&basicCommand{
+			doc: CommandDoc{
+				Name: "more", Aliases: []string{"endgame"}, Section: sectionMain,
+				Summary:  "More commands... 📜",
+				Details:  "Shows the endgame commands.",
+				Examples: []string{"more"},
+			},
+			run: func(ctx *commandContext) string {
+				return renderMenu(ctx.commands, sectionEndgame)
+			},
+		},
+		&basicCommand{
+			doc: CommandDoc{
+				Name: "checkpoint", Aliases: []string{"cp", "checkpoints"}, Section: sectionMain,
+				Summary:  "Save or restore a checkpoint 📌",
+				Details:  "Snapshots your pet and its network memories under a label, or rolls back to one. Rolling back costs happiness, more each time: your pet remembers every timeline you abandoned.",
+				Examples: []string{"checkpoint create before-exams", "checkpoint list", "checkpoint restore before-exams", "checkpoint delete before-exams"},
+				Lore:     "Your pet has dreams about a version of itself that was fed on time.",
+			},
+			update: true,
+			run:    runCheckpointCommand,
+		},
+		&basicCommand{
+			doc: CommandDoc{
+				Name: "backup", Section: sectionMain,
+				Summary:  "Back up your pet off this machine ☁️",
+				Details:  "Encrypts your pet with your backup passphrase and uploads it to S3, Dropbox, or WebDAV. Backups also run automatically once a day. Restore on a new machine with `tamagotchi backup restore`.",
+				Examples: []string{"backup", "backup now"},
+				Lore:     "The bloodline outlives the laptop.",
+			},
+			run: func(ctx *commandContext) string {
+				return runBackupCommand(ctx.backup, ctx.pet, ctx.args)
+			},
+		},
+		&basicCommand{
+			doc: CommandDoc{
+				Name: "digest", Section: sectionMain,
+				Summary:  "Get the weekly report by email 📧",
+				Details:  "With a mail server set in TAMAGOTCHI_SMTP, each weekly care report is emailed to TAMAGOTCHI_DIGEST_TO with a snapshot of your pet and anything that needs you urgently. Never more than one digest in six days. digest off stops it; digest on starts it again.",
+				Examples: []string{"digest", "digest now", "digest off"},
+				Lore:     "It proofreads every one. Twice.",
+			},
+			run: func(ctx *commandContext) string {
+				return runDigestCommand(ctx.digest, ctx.ui, ctx.pet, ctx.args)
+			},
+		},
+		&basicCommand{
+			doc: CommandDoc{
+				Name: "reset", Aliases: []string{"restart", "new"}, Section: sectionMain,
+				Summary:  "Clear history and hatch anew ♻️",
+				Details:  "Erases your pet and starts over with a new egg. Asks for confirmation.",
+				Examples: []string{"reset"},
+				Lore:     "The egg remembers what the adult forgets.",
+			},
+			run: func(ctx *commandContext) string {
+				return resetPet(ctx)
+			},
+		},
+		&basicCommand{
+			doc: CommandDoc{
+				Name: "help", Aliases: []string{"?"}, Section: sectionMain,
+				Summary:  "Show this menu (help <command> for details) 📖",
+				Details:  "Shows the menu, or detailed help for a single command.",
+				Examples: []string{"help", "help feed", "help boarding"},
+			},
+			run: func(ctx *commandContext) string {
+				if ctx.args == "" {
+					return "" // Menu is already displayed
+				}
+				return commandHelp(ctx.commands, ctx.args, ctx.pet.Endgame)
+			},
+		},
+		&basicCommand{
+			doc: CommandDoc{
+				Name: "quit", Aliases: []string{"q", "exit"}, Section: sectionMain,
+				Summary:  "Save and exit 👋",
+				Details:  "Saves your pet and exits with a summary of the session, which you can add to the next weekly report. Your pet keeps living while you're gone.",
+				Examples: []string{"quit"},
+			},
+			run: func(ctx *commandContext) string {
+				quitGame(ctx.pet, ctx.ui, ctx.reader, ctx.session, ctx.recap)
+				ctx.quit = true
+				return ""
+			},
+		},
+		&basicCommand{
+			doc: CommandDoc{
+				Name: "guild", Section: sectionEndgame,
+				Summary:      "Join a guild 🏰",
+				Details:      "Joins a randomly generated guild with a randomly generated rank.",
+				Examples:     []string{"guild"},
+				Achievements: []string{"guild_join"},
+			},
+			update: true,
+			run: func(ctx *commandContext) string {
+				e := ctx.pet.Endgame
+				if e == nil {
+					return ""
+				}
+				message := e.JoinGuild()
+				e.UnlockAchievement("guild_join")
+				return message
+			},
+		},
+		&basicCommand{
+			doc: CommandDoc{
+				Name: "quest", Aliases: []string{"quests"}, Section: sectionEndgame,
+				Summary:      "Get a new quest 📜",
+				Details:      "Starts or checks on a quest. Most quests involve waiting. Share your quest with pets nearby, or join theirs; finish together for a bonus.",
+				Examples:     []string{"quest", "quest share", "quest join", "quest join mochi"},
+				Achievements: []string{"quest_complete"},
+			},
+			update: true,
+			run: func(ctx *commandContext) string {
+				return runQuestCommand(ctx.pet, ctx.args)
+			},
+		},
+		&basicCommand{
+			doc: CommandDoc{
+				Name: "challenge", Aliases: []string{"challenges", "daily"}, Section: sectionEndgame,
+				Summary:  "Today's and this week's challenges 🏅",
+				Details:  "Shows the daily and weekly challenges, the same for every player on the same date, with your progress, your streaks, and which pets nearby have finished them. Progress counts as you play.",
+				Examples: []string{"challenge"},
+			},
+			update: true,
+			run: func(ctx *commandContext) string {
+				return runChallengeCommand(ctx.pet, time.Now())
+			},
+		},
+		&basicCommand{
+			doc: CommandDoc{
+				Name: "gacha", Aliases: []string{"pull"}, Section: sectionEndgame,
+				Summary:  "Pull from gacha 🎰",
+				Details:  "Pulls an invisible accessory. You cannot see it. Your pet can. Accessories come in four rarities, a Rare or better is guaranteed every 10 pulls, and a new banner with a featured accessory goes up every week for everyone at once. Pulls are free.",
+				Examples: []string{"gacha", "gacha banner"},
+			},
+			update: true,
+			run: func(ctx *commandContext) string {
+				return runGachaCommand(ctx.pet, ctx.args)
+			},
+		},
+		&basicCommand{
+			doc: CommandDoc{
+				Name: "battle", Aliases: []string{"fight"}, Section: sectionEndgame,
+				Summary:      "Pet battle ⚔️",
+				Details:      "Starts a pet battle. Nobody has ever won.",
+				Examples:     []string{"battle"},
+				Achievements: []string{"impossible_7"},
+			},
+			update: true,
+			run: func(ctx *commandContext) string {
+				return withEndgame(ctx, (*EndgameState).StartBattle)
+			},
+		},
+		&basicCommand{
+			doc: CommandDoc{
+				Name: "trade", Section: sectionEndgame,
+				Summary:      "Trade items 🔄",
+				Details:      "Attempts a trade.",
+				Examples:     []string{"trade"},
+				Achievements: []string{"impossible_8"},
+			},
+			update: true,
+			run: func(ctx *commandContext) string {
+				return withEndgame(ctx, (*EndgameState).AttemptTrade)
+			},
+		},
+		&basicCommand{
+			doc: CommandDoc{
+				Name: "shop", Aliases: []string{"store"}, Section: sectionEndgame,
+				Summary:      "Spend TamaCoins 🛒",
+				Details:      "TamaCoins can finally be spent. A few things are worth buying; the rest cost more than you will ever have. Some things aren't on the shelf.",
+				Examples:     []string{"shop", "shop buy snack", "shop buy beige"},
+				Achievements: []string{"impossible_4"},
+			},
+			update: true,
+			run: func(ctx *commandContext) string {
+				return runShopCommand(ctx.pet, ctx.args)
+			},
+		},
+		&basicCommand{
+			doc: CommandDoc{
+				Name: "casino", Aliases: []string{"gamble"}, Section: sectionEndgame,
+				Summary:      "Wager TamaCoins at honest odds 🎰",
+				Details:      "A coin flip, a die, and a slot machine. Each table shows its exact chance of winning and how much of every wager it pays back on average, which is always less than you put in. A running tally compares what you got back with what the odds said you would. casino limit <coins> sets a daily loss limit; casino limit off clears it.",
+				Examples:     []string{"casino", "casino coin heads 5", "casino dice 4 2", "casino slots 3", "casino limit 20"},
+				Achievements: []string{"gamble_first", "gamble_100", "gamble_limit"},
+				Lore:         "The house doesn't cheat. It doesn't have to.",
+			},
+			update: true,
+			run: func(ctx *commandContext) string {
+				return runCasinoCommand(ctx.pet, ctx.args, time.Now())
+			},
+		},
+		&basicCommand{
+			doc: CommandDoc{
+				Name: "achievements", Aliases: []string{"achieve", "ach"}, Section: sectionEndgame,
+				Summary:  "View achievements 🏆",
+				Details:  "Lists unlocked, secret, and impossible achievements.",
+				Examples: []string{"achievements"},
+			},
+			update: true,
+			run: func(ctx *commandContext) string {
+				return withEndgame(ctx, (*EndgameState).ShowAchievements)
+			},
+		},
+		&basicCommand{
+			doc: CommandDoc{
+				Name: "leaderboard", Aliases: []string{"lb", "rankings"}, Section: sectionEndgame,
+				Summary:  "View leaderboard 🏅",
+				Details:  "Shows where you rank against players who may or may not exist.",
+				Examples: []string{"leaderboard"},
+			},
+			update: true,
+			run: func(ctx *commandContext) string {
+				return withEndgame(ctx, (*EndgameState).ShowLeaderboard)
+			},
+		},
+		&basicCommand{
+			doc: CommandDoc{
+				Name: "countdown", Aliases: []string{"timer"}, Section: sectionEndgame,
+				Summary:      "The mysterious countdown ⏰",
+				Details:      "Shows how long until... something.",
+				Examples:     []string{"countdown"},
+				Achievements: []string{"impossible_10"},
+				Lore:         "When the counter reaches zero, we all go home.",
+			},
+			update: true,
+			run: func(ctx *commandContext) string {
+				return withEndgame(ctx, (*EndgameState).GetCountdownStatus)
+			},
+		},
+		&basicCommand{
+			doc: CommandDoc{
+				Name: "clue", Aliases: []string{"arg"}, Section: sectionEndgame,
+				Summary:  "Get an ARG clue 🔮",
+				Details:  "Reveals the next clue in an alternate reality game of uncertain scope. Every few clues, something is hidden for you to find (see solve).",
+				Examples: []string{"clue"},
+			},
+			update: true,
+			run: func(ctx *commandContext) string {
+				return withEndgame(ctx, func(e *EndgameState) string {
+					return runClueCommand(e, argCacheFile)
+				})
+			},
+		},
+		&basicCommand{
+			doc: CommandDoc{
+				Name: "solve", Section: sectionEndgame,
+				Summary:      "Answer a hidden artifact 🗝️",
+				Details:      "Every few clues, something gets hidden: in a file beside the save, in the exported album, in the diary. Found what it says? Give the puzzle's name and the answer.",
+				Examples:     []string{"solve cache <answer>"},
+				Achievements: []string{rabbitHoleAchievement},
+			},
+			update: true,
+			run: func(ctx *commandContext) string {
+				return withEndgame(ctx, func(e *EndgameState) string {
+					return runSolveCommand(e, ctx.args)
+				})
+			},
+		},
+		&basicCommand{
+			doc: CommandDoc{
+				Name: "combine", Section: sectionEndgame,
+				Summary:      "Put key fragments together 🧩",
+				Details:      "Every pet carries a piece of a key, worked out from who it is. Share yours with pets nearby, or type in one a friend read out to you. Fragments from three different pets read a message none of them can read alone.",
+				Examples:     []string{"combine", "combine share", "combine 1a2b3c4d5e6f7081-0f1e2d3c4b5a6978"},
+				Achievements: []string{chorusAchievement},
+				Lore:         "Seventeen pets once dreamed the same dream. Nobody wrote it down whole.",
+			},
+			update: true,
+			run: func(ctx *commandContext) string {
+				if ctx.pet.Endgame == nil {
+					return ""
+				}
+				return runCombineCommand(ctx.pet, ctx.args)
+			},
+		},
+		&basicCommand{
+			doc: CommandDoc{
+				Name: "meta", Aliases: []string{"metastats", "wasted"}, Section: sectionEndgame,
+				Summary:  "Meta statistics 📊",
+				Details:  "Shows play time, paused time, and the hours your pet has learned you usually visit, then what changed since you last looked: stats, achievements, new friends, and thoughts had.",
+				Examples: []string{"meta"},
+			},
+			update: true,
+			run: func(ctx *commandContext) string {
+				return withEndgame(ctx, func(e *EndgameState) string {
+					return e.GetMetaStats(ctx.pet.metaSnapshot(time.Now()), friendsMetSince)
+				})
+			},
+		},
+		&basicCommand{
+			doc: CommandDoc{
+				Name: "share", Section: sectionEndgame,
+				Summary:      "Share pet status 📤",
+				Details:      "Generates text to share your pet with the world.",
+				Examples:     []string{"share"},
+				Achievements: []string{"impossible_5"},
+			},
+			update: true,
+			run: func(ctx *commandContext) string {
+				e := ctx.pet.Endgame
+				if e == nil {
+					return ""
+				}
+				e.ShareCount++
+				shareText := e.GenerateShareText(ctx.pet.Name, ctx.pet.Stage.String())
+				return "📤 Share text copied to... nowhere. Here it is:\n" + shareText
+			},
+		},
+		&basicCommand{
+			doc: CommandDoc{
+				Name: "premium", Aliases: []string{"pro", "vip"}, Section: sectionEndgame,
+				Summary:      "Premium content 💎",
+				Details:      "Explains the premium tier.",
+				Examples:     []string{"premium"},
+				Achievements: []string{"impossible_9"},
+			},
+			update: true,
+			run: func(ctx *commandContext) string {
+				return ShowPremiumOffer()
+			},
+		},
+		&basicCommand{
+			doc: CommandDoc{
+				Name: "ad", Aliases: []string{"ads", "watch"}, Section: sectionEndgame,
+				Summary:  "Watch an ad 📺",
+				Details:  "Watch an ad. The reward is the satisfaction of waiting.",
+				Examples: []string{"ad"},
+			},
+			update: true,
+			run: func(ctx *commandContext) string {
+				fmt.Println(ctx.ui.text(ShowFakeAd()))
+				fmt.Println(ctx.ui.text("\n⏳ Loading ad..."))
+				time.Sleep(5 * time.Second) // Fake ad delay
+				fmt.Println(ctx.ui.text("✅ Ad complete! Reward: A sense of time passing."))
+				return ""
+			},
+		},
+		&basicCommand{
+			doc: CommandDoc{
+				Name: "friendcode", Aliases: []string{"code", "fc"}, Section: sectionEndgame,
+				Summary:  "Your friend code 🔑",
+				Details:  "Shows your 47-character friend code.",
+				Examples: []string{"friendcode"},
+			},
+			update: true,
+			run: func(ctx *commandContext) string {
+				if ctx.pet.Endgame == nil {
+					return ""
+				}
+				return fmt.Sprintf(`
+╔════════════════════════════════════╗
+║      🔑 YOUR FRIEND CODE 🔑       ║
+╠════════════════════════════════════╣
+║                                    ║
+║ %s
+║                                    ║
+║ Share this with friends!           ║
+║ (It doesn't do anything)           ║
+║                                    ║
+╚════════════════════════════════════╝
+`, ctx.pet.Endgame.FriendCode)
+			},
+		},
+	}
+}
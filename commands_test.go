@@ -0,0 +1,110 @@
+package main
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestRegisterRejectsDuplicateWords(t *testing.T) {
+	r := newCommandRegistry()
+	feed := &basicCommand{doc: CommandDoc{Name: "feed", Aliases: []string{"f"}}}
+	if err := r.Register(feed); err != nil {
+		t.Fatalf("Expected first registration to succeed, got %v", err)
+	}
+
+	tests := []struct {
+		name string
+		doc  CommandDoc
+	}{
+		{"same name", CommandDoc{Name: "feed"}},
+		{"name clashes with alias", CommandDoc{Name: "f"}},
+		{"alias clashes with name", CommandDoc{Name: "fetch", Aliases: []string{"feed"}}},
+	}
+	for _, tt := range tests {
+		if err := r.Register(&basicCommand{doc: tt.doc}); err == nil {
+			t.Errorf("%s: expected an error", tt.name)
+		}
+	}
+
+	if len(r.Commands()) != 1 {
+		t.Errorf("Expected rejected commands to stay out of the registry, got %d", len(r.Commands()))
+	}
+}
+
+func TestLookupByAlias(t *testing.T) {
+	commands := newDefaultRegistry()
+	if cmd := commands.Lookup(" MED "); cmd == nil || cmd.Name() != "heal" {
+		t.Errorf("Expected med to resolve to heal, got %v", cmd)
+	}
+	if commands.Lookup("teleport") != nil {
+		t.Error("Expected unknown command to have no entry")
+	}
+}
+
+func TestUnknownCommand(t *testing.T) {
+	commands := newDefaultRegistry()
+	pet := NewPet("Test")
+	pet.Absurd.Fears = []Fear{{Name: "Qphobia", Description: "Terrified of the letter Q", Trigger: "q"}}
+
+	tests := []struct {
+		name        string
+		input       string
+		wantCommand string
+		wantMessage string
+	}{
+		{"typo is corrected", "fed", "feed", "Assuming you meant 'feed'"},
+		{"ambiguous typo is suggested", "hel", "", "Did you mean 'heal'?"},
+		{"nothing close", "zzz", "", "Type 'help'"},
+		{"mistyped fear trigger still scares", "feeq", "", "trembles"},
+		{"drastic commands are never guessed", "rest", "", "Did you mean 'reset'?"},
+	}
+	for _, tt := range tests {
+		cmd, msg := unknownCommand(pet, commands, tt.input, tt.input)
+		gotCommand := ""
+		if cmd != nil {
+			gotCommand = cmd.Name()
+		}
+		if gotCommand != tt.wantCommand {
+			t.Errorf("%s: expected command %q, got %q", tt.name, tt.wantCommand, gotCommand)
+		}
+		if !strings.Contains(msg, tt.wantMessage) {
+			t.Errorf("%s: expected message to contain %q, got %s", tt.name, tt.wantMessage, msg)
+		}
+	}
+}
+
+func TestRunCommandWaitsForUnavailablePet(t *testing.T) {
+	commands := newDefaultRegistry()
+	pet := NewPet("Test")
+	pet.Stage = Child
+	pet.Hunger = 80
+	pet.Paused = true
+
+	msg := runCommand(commands.Lookup("feed"), &commandContext{pet: pet, commands: commands})
+	if !strings.Contains(msg, "suspended animation") || pet.Hunger != 80 {
+		t.Errorf("Expected feeding to wait for a paused pet, got %s (hunger %d)", msg, pet.Hunger)
+	}
+}
+
+func TestRunBuiltinCommand(t *testing.T) {
+	commands := newDefaultRegistry()
+	pet := NewPet("Test")
+	pet.Stage = Child
+	pet.Hunger = 80
+
+	cmd := commands.Lookup("f")
+	if !cmd.RequiresUpdate() {
+		t.Error("Expected feed to bring the pet up to date first")
+	}
+	cmd.Run(&commandContext{pet: pet, commands: commands})
+	if pet.Hunger != 50 {
+		t.Errorf("Expected hunger 50 after feeding, got %d", pet.Hunger)
+	}
+	if !hasAchievement(pet.Endgame, "first_feed") {
+		t.Error("Expected feeding to unlock first_feed")
+	}
+
+	if msg := commands.Lookup("more").Run(&commandContext{pet: pet, commands: commands}); !strings.Contains(msg, sectionEndgame) {
+		t.Errorf("Expected more to show the endgame menu, got %s", msg)
+	}
+}
@@ -0,0 +1,79 @@
+package main
+
+import (
+	"fmt"
+	"strconv"
+	"time"
+)
+
+// consensusHandlers run a community event on one pet. Every client runs the
+// same handler at the same scheduled moment; that shared moment is the whole
+// consensus. Types this version doesn't know are skipped, so maintainers can
+// add new ones without breaking older pets.
+var consensusHandlers = map[string]func(p *Pet, event CommunityEvent) string{
+	"announcement": func(p *Pet, event CommunityEvent) string {
+		return fmt.Sprintf("📣 %s: %s", event.Name, event.Payload["text"])
+	},
+	"feast": func(p *Pet, event CommunityEvent) string {
+		p.Hunger = 0
+		p.Happiness = clamp(p.Happiness+10, 0, 100)
+		return fmt.Sprintf("🍱 %s! Every pet on every machine eats at once. %s is stuffed.", event.Name, p.Name)
+	},
+	"happiness": func(p *Pet, event CommunityEvent) string {
+		delta, _ := strconv.Atoi(event.Payload["amount"])
+		p.Happiness = clamp(p.Happiness+delta, 0, 100)
+		return fmt.Sprintf("🌈 %s. %s feels it too.", event.Name, p.Name)
+	},
+	"gift": func(p *Pet, event CommunityEvent) string {
+		item := event.Payload["item"]
+		if item == "" || p.Endgame == nil {
+			return ""
+		}
+		for _, owned := range p.Endgame.InvisibleAccessories {
+			if owned == item {
+				return fmt.Sprintf("🎁 %s: everyone gets %s. %s already has one.", event.Name, item, p.Name)
+			}
+		}
+		p.Endgame.InvisibleAccessories = append(p.Endgame.InvisibleAccessories, item)
+		return fmt.Sprintf("🎁 %s: every pet receives %s. You can't see it.", event.Name, item)
+	},
+	"achievement": func(p *Pet, event CommunityEvent) string {
+		if p.Endgame == nil {
+			return ""
+		}
+		if _, message := p.Endgame.UnlockAchievement(event.Payload["id"]); message != "" {
+			return fmt.Sprintf("🏆 %s\n%s", event.Name, message)
+		}
+		return ""
+	},
+}
+
+// RunDueEvents fires every scheduled community event whose time has come
+// and returns what happened. Events missed by more than eventGrace are
+// marked done without running.
+func (p *Pet) RunDueEvents(now time.Time) []string {
+	if p.Endgame == nil || p.Stage == Dead {
+		return nil
+	}
+	e := p.Endgame
+
+	var reactions []string
+	for _, event := range e.CommunityEvents {
+		if event.At.After(now) || e.hasFired(event.ID) {
+			continue
+		}
+		e.FiredEvents = append(e.FiredEvents, event.ID)
+		if now.Sub(event.At) > eventGrace {
+			continue
+		}
+		handler, ok := consensusHandlers[event.Type]
+		if !ok {
+			continue
+		}
+		recordConsensus(event)
+		if reaction := handler(p, event); reaction != "" {
+			reactions = append(reactions, reaction)
+		}
+	}
+	return reactions
+}
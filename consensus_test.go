@@ -0,0 +1,80 @@
+package main
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestRunDueEvents(t *testing.T) {
+	at := time.Date(2030, 1, 1, 12, 0, 0, 0, time.UTC)
+	tests := []struct {
+		name      string
+		event     CommunityEvent
+		now       time.Time
+		wantFired bool
+		wantText  string
+	}{
+		{"not yet", CommunityEvent{ID: "a", Name: "Feast", At: at, Type: "feast"}, at.Add(-time.Minute), false, ""},
+		{"feast", CommunityEvent{ID: "a", Name: "Feast", At: at, Type: "feast"}, at, true, "stuffed"},
+		{"announcement", CommunityEvent{ID: "a", Name: "News", At: at, Type: "announcement", Payload: map[string]string{"text": "hello, pets"}}, at, true, "hello, pets"},
+		{"gift", CommunityEvent{ID: "a", Name: "Gift", At: at, Type: "gift", Payload: map[string]string{"item": "a scarf"}}, at, true, "a scarf"},
+		{"missed", CommunityEvent{ID: "a", Name: "Feast", At: at, Type: "feast"}, at.Add(eventGrace + time.Hour), true, ""},
+		{"unknown type", CommunityEvent{ID: "a", Name: "Eclipse", At: at, Type: "eclipse"}, at, true, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			pet := NewPet("Tamago")
+			pet.Stage = Adult
+			pet.Hunger = 80
+			pet.Endgame.ScheduleEvents([]CommunityEvent{tt.event}, at.Add(-time.Hour))
+
+			reactions := pet.RunDueEvents(tt.now)
+			if pet.Endgame.hasFired("a") != tt.wantFired {
+				t.Errorf("Expected fired %v, got %v", tt.wantFired, !tt.wantFired)
+			}
+			text := strings.Join(reactions, "\n")
+			if tt.wantText == "" && text != "" {
+				t.Errorf("Expected no reaction, got %q", text)
+			}
+			if !strings.Contains(text, tt.wantText) {
+				t.Errorf("Expected reaction containing %q, got %q", tt.wantText, text)
+			}
+			if again := pet.RunDueEvents(tt.now); len(again) != 0 {
+				t.Errorf("Expected an event to run once, got %v", again)
+			}
+		})
+	}
+}
+
+func TestFeastAndGiftEffects(t *testing.T) {
+	at := time.Date(2030, 1, 1, 12, 0, 0, 0, time.UTC)
+	pet := NewPet("Tamago")
+	pet.Stage = Adult
+	pet.Hunger = 80
+	pet.Endgame.ScheduleEvents([]CommunityEvent{
+		{ID: "feast", Name: "Feast", At: at, Type: "feast"},
+		{ID: "gift", Name: "Gift", At: at, Type: "gift", Payload: map[string]string{"item": "a scarf"}},
+	}, at.Add(-time.Hour))
+
+	pet.RunDueEvents(at)
+	if pet.Hunger != 0 {
+		t.Errorf("Expected the feast to empty hunger, got %d", pet.Hunger)
+	}
+	owned := pet.Endgame.InvisibleAccessories
+	if len(owned) == 0 || owned[len(owned)-1] != "a scarf" {
+		t.Errorf("Expected the gift among invisible accessories, got %v", owned)
+	}
+}
+
+func TestRunDueEventsSkipsDeadPets(t *testing.T) {
+	at := time.Date(2030, 1, 1, 12, 0, 0, 0, time.UTC)
+	pet := NewPet("Tamago")
+	pet.Stage = Dead
+	pet.Endgame.ScheduleEvents([]CommunityEvent{{ID: "a", Name: "Feast", At: at, Type: "feast"}}, at.Add(-time.Hour))
+
+	if reactions := pet.RunDueEvents(at); len(reactions) != 0 {
+		t.Errorf("Expected a dead pet to miss events, got %v", reactions)
+	}
+}
@@ -0,0 +1,91 @@
+package main
+
+import (
+	"fmt"
+	"runtime"
+	"strings"
+	"time"
+)
+
+// Bell styles for notifications. Some consoles swallow \a entirely, so the
+// OS beep and a screen flash are offered as alternatives.
+const (
+	bellTerminal = "bell"  // Print \a and let the terminal decide
+	bellBeep     = "beep"  // Ask the OS to beep directly
+	bellFlash    = "flash" // Briefly invert the screen instead of making noise
+)
+
+// asciiBoxes swaps box-drawing and block glyphs for ASCII on consoles whose
+// fonts can't draw them (legacy Windows conhost raster fonts, serial consoles)
+var asciiBoxes = strings.NewReplacer(
+	"╔", "+", "╗", "+", "╚", "+", "╝", "+", "╠", "+", "╣", "+",
+	"┌", "+", "┐", "+", "└", "+", "┘", "+",
+	"═", "=", "─", "-", "━", "=",
+	"║", "|", "│", "|",
+	"╱", "/", "╲", "\\",
+	"█", "#", "▓", "#", "▒", "+", "░", ".",
+	"▁", "_", "▂", "_", "▃", "-", "▄", "-", "▅", "=", "▆", "=", "▇", "#",
+	"▶", ">", "●", "o", "△", "^", "▿", "v",
+)
+
+// defaultBellStyle picks the bell for a platform; Windows consoles are the
+// ones most likely to drop \a, so they beep through the OS instead
+func defaultBellStyle(goos string) string {
+	if goos == "windows" {
+		return bellBeep
+	}
+	return bellTerminal
+}
+
+// parseBellStyle reads TAMAGOTCHI_BELL, falling back to the platform default
+func parseBellStyle(value, goos string) string {
+	switch strings.ToLower(strings.TrimSpace(value)) {
+	case bellTerminal, "terminal":
+		return bellTerminal
+	case bellBeep, "os":
+		return bellBeep
+	case bellFlash, "visual":
+		return bellFlash
+	}
+	return defaultBellStyle(goos)
+}
+
+// setupConsole prepares the terminal and reports whether ANSI escape codes
+// will be understood and whether box-drawing glyphs should be avoided
+func setupConsole(getenv func(string) string) (ansi bool, ascii bool) {
+	ansi = enableVirtualTerminal()
+	ascii = getenv("TAMAGOTCHI_ASCII") != "" || (runtime.GOOS == "windows" && !ansi)
+	return ansi, ascii
+}
+
+// text prepares a block of output for this console: emoji are spelled out
+// if need be, panel borders are squared up for the text inside them, then
+// drawn in ASCII if need be
+func (ui *uiConfig) text(s string) string {
+	if ui.noEmoji {
+		s = emojiFree(s)
+	}
+	s = alignFrames(s)
+	if ui.asciiOnly {
+		return asciiBoxes.Replace(s)
+	}
+	return s
+}
+
+// ring sounds one bell in the configured style
+func (ui *uiConfig) ring() {
+	switch ui.bellStyle {
+	case bellBeep:
+		if consoleBeep() {
+			return
+		}
+	case bellFlash:
+		if ui.colorEnabled {
+			fmt.Print("\033[?5h")
+			time.Sleep(80 * time.Millisecond)
+			fmt.Print("\033[?5l")
+			return
+		}
+	}
+	fmt.Print("\a")
+}
@@ -0,0 +1,13 @@
+//go:build !windows
+
+package main
+
+// enableVirtualTerminal is a no-op: Unix terminals understand ANSI already
+func enableVirtualTerminal() bool {
+	return true
+}
+
+// consoleBeep has no OS beep to fall back on outside Windows
+func consoleBeep() bool {
+	return false
+}
@@ -0,0 +1,69 @@
+package main
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestParseBellStyle(t *testing.T) {
+	tests := []struct {
+		value string
+		goos  string
+		want  string
+	}{
+		{"", "linux", bellTerminal},
+		{"", "windows", bellBeep},
+		{"beep", "darwin", bellBeep},
+		{"VISUAL", "linux", bellFlash},
+		{"flash", "windows", bellFlash},
+		{"terminal", "windows", bellTerminal},
+		{"kazoo", "windows", bellBeep},
+	}
+
+	for _, tt := range tests {
+		if got := parseBellStyle(tt.value, tt.goos); got != tt.want {
+			t.Errorf("parseBellStyle(%q, %q): Expected %s, got %s", tt.value, tt.goos, tt.want, got)
+		}
+	}
+}
+
+func TestASCIIText(t *testing.T) {
+	ui := &uiConfig{asciiOnly: true}
+	got := ui.text("╔══╗\n║😊║\n╚══╝ ▁▄█ ░▒▓")
+	want := "+==+\n|😊|\n+==+ _-# .+#"
+	if got != want {
+		t.Errorf("Expected %q, got %q", want, got)
+	}
+
+	if got := (&uiConfig{}).text("╔══╗"); got != "╔══╗" {
+		t.Errorf("Expected boxes untouched outside ASCII mode, got %q", got)
+	}
+}
+
+func TestASCIIModeCoversDrawnPanels(t *testing.T) {
+	ui := newUIConfig()
+	ui.asciiOnly = true
+	pet := NewPet("Tamago")
+	out := ui.text(renderScene(pet, ui))
+	for _, r := range out {
+		if r >= 0x2500 && r <= 0x259F {
+			t.Errorf("Expected no box-drawing or block glyphs in ASCII mode, found %q", r)
+			break
+		}
+	}
+	if !strings.Contains(out, "Tamago") {
+		t.Errorf("Expected the scene to still show the pet's name")
+	}
+}
+
+func TestSetupConsoleHonorsASCIIOverride(t *testing.T) {
+	_, ascii := setupConsole(func(key string) string {
+		if key == "TAMAGOTCHI_ASCII" {
+			return "1"
+		}
+		return ""
+	})
+	if !ascii {
+		t.Errorf("Expected TAMAGOTCHI_ASCII to force ASCII mode")
+	}
+}
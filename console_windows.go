@@ -0,0 +1,43 @@
+//go:build windows
+
+package main
+
+import (
+	"syscall"
+	"unsafe"
+)
+
+// enableVirtualTerminalProcessing is the console mode flag that makes conhost
+// interpret ANSI escape codes (Windows 10 1511 and later)
+const enableVirtualTerminalProcessing = 0x0004
+
+var (
+	kernel32           = syscall.NewLazyDLL("kernel32.dll")
+	procGetConsoleMode = kernel32.NewProc("GetConsoleMode")
+	procSetConsoleMode = kernel32.NewProc("SetConsoleMode")
+	procBeep           = kernel32.NewProc("Beep")
+)
+
+// enableVirtualTerminal turns on ANSI processing for stdout. It reports false
+// on consoles too old to support it, where escape codes would print raw.
+func enableVirtualTerminal() bool {
+	var mode uint32
+	if r, _, _ := procGetConsoleMode.Call(uintptr(syscall.Stdout), uintptr(unsafe.Pointer(&mode))); r == 0 {
+		return true // Not a console (redirected, or a mintty pipe): leave output alone
+	}
+	if mode&enableVirtualTerminalProcessing != 0 {
+		return true
+	}
+	r, _, _ := procSetConsoleMode.Call(uintptr(syscall.Stdout), uintptr(mode|enableVirtualTerminalProcessing))
+	return r != 0
+}
+
+// consoleBeep plays a short tone through the OS, which works even when the
+// console swallows \a. It reports whether the beep was played.
+func consoleBeep() bool {
+	if err := procBeep.Find(); err != nil {
+		return false
+	}
+	r, _, _ := procBeep.Call(880, 120)
+	return r != 0
+}
@@ -0,0 +1,240 @@
+package main
+
+import (
+	"crypto/sha256"
+	"encoding/binary"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/tamagotchi/mooc"
+)
+
+const (
+	// skyWidth and skyHeight are the size of the constellation map in cells
+	skyWidth  = 40
+	skyHeight = 13
+
+	// gossipPulse is how long a star pulses after gossip arrives from it
+	gossipPulse = 2 * time.Minute
+)
+
+// constellation is a snapshot of the mesh to plot
+type constellation struct {
+	stars      []mooc.Star
+	gossipFrom string // PetID of the last pet to gossip with us
+	gossipAt   time.Time
+}
+
+// meshConstellation snapshots the running network, if there is one
+func meshConstellation() (constellation, bool) {
+	if petNetwork == nil || petNetwork.IsLonely() {
+		return constellation{}, false
+	}
+	from, at := petNetwork.LastGossip()
+	return constellation{
+		stars:      petNetwork.Stars(),
+		gossipFrom: from,
+		gossipAt:   at,
+	}, true
+}
+
+// starPosition places a pet by a hash of its PetID, so a friend sits in the
+// same part of the sky every time you look
+func starPosition(petID string, width, height int) (int, int) {
+	sum := sha256.Sum256([]byte(petID))
+	x := int(binary.BigEndian.Uint16(sum[0:2])) % width
+	y := int(binary.BigEndian.Uint16(sum[2:4])) % height
+	return x, y
+}
+
+// starLabel is the letter a star goes by on the map and in the legend
+func starLabel(i int) string {
+	if i < 26 {
+		return string(rune('A' + i))
+	}
+	return string(rune('a' + (i-26)%26))
+}
+
+// starGlyph shows how recently a pet was seen: bright stars are nearby now,
+// dim ones haven't been heard from in a long while
+func (ui *uiConfig) starGlyph(star mooc.Star, now time.Time) (string, string) {
+	since := now.Sub(star.LastSeen)
+	switch {
+	case star.IsDeceased:
+		return "x", ui.palette.faint
+	case star.Online || since < time.Hour:
+		return "✦", ui.palette.highlight
+	case since < 24*time.Hour:
+		return "*", ui.palette.accent
+	case since < 7*24*time.Hour:
+		return "+", ui.palette.neutral
+	}
+	return "·", ui.palette.faint
+}
+
+// linkGlyph is the line character for a step from one cell toward another
+func linkGlyph(dx, dy int) string {
+	switch {
+	case dx == 0:
+		return "│"
+	case dy == 0:
+		return "─"
+	case (dx > 0) == (dy > 0):
+		return "╲"
+	}
+	return "╱"
+}
+
+// drawLink draws a dream-sharing line between two cells, leaving the ends
+// for the stars themselves
+func (ui *uiConfig) drawLink(buf *cellBuffer, x0, y0, x1, y1 int) {
+	dx, dy := x1-x0, y1-y0
+	steps := max(abs(dx), abs(dy))
+	glyph := ui.paletteText(linkGlyph(dx, dy), ui.palette.faint)
+	for i := 1; i < steps; i++ {
+		x := x0 + (dx*i+sign(dx)*steps/2)/steps
+		y := y0 + (dy*i+sign(dy)*steps/2)/steps
+		buf.set(x, y, glyph, false)
+	}
+}
+
+func abs(n int) int {
+	if n < 0 {
+		return -n
+	}
+	return n
+}
+
+func sign(n int) int {
+	switch {
+	case n < 0:
+		return -1
+	case n > 0:
+		return 1
+	}
+	return 0
+}
+
+// plotStars works out where every star goes. Stars that hash onto an
+// occupied cell, or onto us, slide right until they find space.
+func plotStars(c constellation) (positions [][2]int, selfX, selfY int) {
+	selfX, selfY = skyWidth/2, skyHeight/2
+	taken := map[[2]int]bool{{selfX, selfY}: true}
+	for _, star := range c.stars {
+		x, y := starPosition(star.PetID, skyWidth, skyHeight)
+		// Leave room for the label to the right of each star
+		crowded := func() bool {
+			return taken[[2]int{x, y}] || taken[[2]int{x + 1, y}] || taken[[2]int{x - 1, y}]
+		}
+		for tries := 0; crowded() && tries < skyWidth*skyHeight; tries++ { // A full sky lets stars overlap
+			x = (x + 2) % skyWidth
+			if x < 2 {
+				y = (y + 1) % skyHeight
+			}
+		}
+		taken[[2]int{x, y}], taken[[2]int{x + 1, y}] = true, true
+		positions = append(positions, [2]int{x, y})
+	}
+	return positions, selfX, selfY
+}
+
+// renderConstellation draws the mesh as a night sky: us in the middle, every
+// known pet a star placed by its PetID, brighter the more recently it was
+// seen, joined to us when it shares our dreams. The star that just gossiped
+// pulses. Naming a star by letter or name shows what we know about it.
+func (ui *uiConfig) renderConstellation(c constellation, focus string, now time.Time) string {
+	positions, selfX, selfY := plotStars(c)
+	buf := newCellBuffer(strings.Repeat("\n", skyHeight-1), skyWidth)
+
+	for i, star := range c.stars {
+		if star.SharesDreams {
+			ui.drawLink(buf, selfX, selfY, positions[i][0], positions[i][1])
+		}
+	}
+	buf.set(selfX, selfY, ui.paletteText("◉", ui.palette.title), true)
+
+	focused := -1
+	for i, star := range c.stars {
+		glyph, style := ui.starGlyph(star, now)
+		if star.PetID == c.gossipFrom && now.Sub(c.gossipAt) < gossipPulse {
+			glyph, style = "●", ui.palette.warn
+			if now.Second()%2 == 1 {
+				glyph = "○"
+			}
+		}
+		label := starLabel(i)
+		if strings.EqualFold(focus, label) || strings.EqualFold(focus, star.DisplayName) {
+			focused, style = i, ui.palette.title
+		}
+		x, y := positions[i][0], positions[i][1]
+		buf.set(x, y, ui.paletteText(glyph, style), true)
+		buf.set(x+1, y, ui.paletteText(label, ui.palette.faint), true)
+	}
+
+	var b strings.Builder
+	b.WriteString("╔" + strings.Repeat("═", skyWidth+2) + "╗\n")
+	b.WriteString("║ ✨ CONSTELLATION ✨\n")
+	b.WriteString("╠" + strings.Repeat("═", skyWidth+2) + "╣\n")
+	for _, row := range strings.Split(buf.String(), "\n") {
+		b.WriteString("║ " + row + "\n")
+	}
+	b.WriteString("╚" + strings.Repeat("═", skyWidth+2) + "╝\n")
+
+	if len(c.stars) == 0 {
+		b.WriteString(ui.paletteText("◉ is you. The rest of the sky is waiting.", ui.palette.faint))
+		return b.String()
+	}
+	if focused >= 0 {
+		b.WriteString(describeStar(c, focused, now))
+		return b.String()
+	}
+	if focus != "" {
+		b.WriteString(fmt.Sprintf("No star called %q.\n", focus))
+	}
+	b.WriteString(ui.paletteText("◉ you  ✦ here now  * today  + this week  · long ago  x gone  ● gossiping\n", ui.palette.faint))
+	for i, star := range c.stars {
+		glyph, _ := ui.starGlyph(star, now)
+		b.WriteString(fmt.Sprintf("%s %s %s\n", starLabel(i), glyph, truncateWidth(star.DisplayName, maxPanelNameWidth)))
+	}
+	b.WriteString(ui.paletteText("constellation <letter> to look closer", ui.palette.faint))
+	return b.String()
+}
+
+// describeStar says what we know about one star
+func describeStar(c constellation, i int, now time.Time) string {
+	star := c.stars[i]
+	lines := []string{fmt.Sprintf("%s %s", starLabel(i), star.DisplayName)}
+	switch {
+	case star.IsDeceased:
+		lines = append(lines, "Its light is still arriving. The pet is gone.")
+	case star.Online:
+		lines = append(lines, "Online now.")
+		if link := star.Link; link.Weak() {
+			lines = append(lines, fmt.Sprintf("The connection to %s is weak lately: %s round trip, %d%% of pulses lost.", star.DisplayName, link.RTT.Round(time.Millisecond), int(link.Loss*100)))
+		} else if link.Samples > 0 {
+			lines = append(lines, fmt.Sprintf("Answers in %s.", link.RTT.Round(time.Millisecond)))
+		}
+	case !star.LastSeen.IsZero():
+		lines = append(lines, fmt.Sprintf("Last seen %s ago.", formatDuration(now.Sub(star.LastSeen))))
+	}
+	if star.SharesDreams {
+		lines = append(lines, "Shares your pet's name, and its dreams.")
+	}
+	if remark := regionRemark(activeRegion.offsetLabel(), star.Region, now); remark != "" && !star.IsDeceased {
+		lines = append(lines, remark)
+	}
+	if star.PetID == c.gossipFrom && !c.gossipAt.IsZero() {
+		lines = append(lines, fmt.Sprintf("Last gossiped %s ago.", formatDuration(now.Sub(c.gossipAt))))
+	}
+	return strings.Join(lines, "\n")
+}
+
+// runConstellationCommand shows the mesh map, or one star on it
+func runConstellationCommand(ui *uiConfig, args string) string {
+	c, ok := meshConstellation()
+	if !ok {
+		return "🌌 The sky is dark. Your pet isn't listening to the mesh."
+	}
+	return ui.renderConstellation(c, args, time.Now())
+}
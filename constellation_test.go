@@ -0,0 +1,133 @@
+package main
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/tamagotchi/mooc"
+)
+
+func testConstellation(now time.Time) constellation {
+	return constellation{
+		stars: []mooc.Star{
+			{PetID: "a1", DisplayName: "Mochi", Online: true, LastSeen: now, Link: mooc.LinkQuality{RTT: 700 * time.Millisecond, Loss: 0.4, Samples: 5}},
+			{PetID: "b2", DisplayName: "Tamago", LastSeen: now.Add(-3 * time.Hour), SharesDreams: true},
+			{PetID: "c3", DisplayName: "Pixel", LastSeen: now.Add(-30 * 24 * time.Hour), IsDeceased: true},
+			{PetID: "d4", DisplayName: "Nibbles", LastSeen: now.Add(-30 * 24 * time.Hour)},
+		},
+		gossipFrom: "d4",
+		gossipAt:   now.Add(-time.Minute),
+	}
+}
+
+func TestStarPositionIsStable(t *testing.T) {
+	x, y := starPosition("a1", skyWidth, skyHeight)
+	if x < 0 || x >= skyWidth || y < 0 || y >= skyHeight {
+		t.Fatalf("Expected a position inside the sky, got %d,%d", x, y)
+	}
+	if x2, y2 := starPosition("a1", skyWidth, skyHeight); x2 != x || y2 != y {
+		t.Errorf("Expected the same PetID to land in the same place")
+	}
+}
+
+func TestPlotStarsNeverOverlap(t *testing.T) {
+	var c constellation
+	for i := 0; i < 40; i++ {
+		c.stars = append(c.stars, mooc.Star{PetID: strings.Repeat("x", i+1)})
+	}
+	positions, selfX, selfY := plotStars(c)
+	seen := map[[2]int]bool{{selfX, selfY}: true}
+	for i, p := range positions {
+		if seen[p] {
+			t.Errorf("Star %d landed on an occupied cell %v", i, p)
+		}
+		seen[p] = true
+	}
+}
+
+func TestStarGlyphByRecency(t *testing.T) {
+	ui := newUIConfig()
+	now := time.Now()
+	tests := []struct {
+		star mooc.Star
+		want string
+	}{
+		{mooc.Star{Online: true}, "✦"},
+		{mooc.Star{LastSeen: now.Add(-10 * time.Minute)}, "✦"},
+		{mooc.Star{LastSeen: now.Add(-5 * time.Hour)}, "*"},
+		{mooc.Star{LastSeen: now.Add(-3 * 24 * time.Hour)}, "+"},
+		{mooc.Star{LastSeen: now.Add(-30 * 24 * time.Hour)}, "·"},
+		{mooc.Star{Online: true, IsDeceased: true}, "x"},
+	}
+	for _, tt := range tests {
+		if got, _ := ui.starGlyph(tt.star, now); got != tt.want {
+			t.Errorf("starGlyph(%+v): Expected %s, got %s", tt.star, tt.want, got)
+		}
+	}
+}
+
+func TestLinkGlyph(t *testing.T) {
+	tests := []struct {
+		dx, dy int
+		want   string
+	}{
+		{0, 3, "│"}, {4, 0, "─"}, {2, 2, "╲"}, {-2, -2, "╲"}, {2, -2, "╱"}, {-3, 1, "╱"},
+	}
+	for _, tt := range tests {
+		if got := linkGlyph(tt.dx, tt.dy); got != tt.want {
+			t.Errorf("linkGlyph(%d, %d): Expected %s, got %s", tt.dx, tt.dy, tt.want, got)
+		}
+	}
+}
+
+func TestRenderConstellation(t *testing.T) {
+	ui := newUIConfig()
+	ui.colorEnabled = false
+	now := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	c := testConstellation(now)
+
+	sky := ui.renderConstellation(c, "", now)
+	assertFramed(t, ui.text(sky))
+	for _, want := range []string{"◉", "✦", "x", "●", "A ✦ Mochi", "B * Tamago", "D · Nibbles"} {
+		if !strings.Contains(sky, want) {
+			t.Errorf("Expected %q on the map, got:\n%s", want, sky)
+		}
+	}
+	if !strings.ContainsAny(sky, "│─╱╲") {
+		t.Errorf("Expected a dream link to Tamago, got:\n%s", sky)
+	}
+	if pulse := ui.renderConstellation(c, "", now.Add(time.Second)); !strings.Contains(pulse, "○") {
+		t.Errorf("Expected the gossiping star to pulse")
+	}
+	if quiet := ui.renderConstellation(c, "", now.Add(gossipPulse)); strings.Contains(quiet, "●") && !strings.Contains(quiet, "● gossiping") {
+		t.Errorf("Expected the pulse to stop after %s", gossipPulse)
+	}
+
+	closer := ui.renderConstellation(c, "b", now)
+	if !strings.Contains(closer, "Last seen 3h 0m 0s ago") || !strings.Contains(closer, "dreams") {
+		t.Errorf("Expected details for Tamago, got:\n%s", closer)
+	}
+	if got := ui.renderConstellation(c, "a", now); !strings.Contains(got, "The connection to Mochi is weak lately: 700ms round trip, 40% of pulses lost.") {
+		t.Errorf("Expected Mochi's weak link mentioned, got:\n%s", got)
+	}
+	if got := ui.renderConstellation(c, "pixel", now); !strings.Contains(got, "The pet is gone") {
+		t.Errorf("Expected stars to be found by name, got:\n%s", got)
+	}
+	if got := ui.renderConstellation(c, "zz", now); !strings.Contains(got, `No star called "zz"`) {
+		t.Errorf("Expected a note for an unknown star, got:\n%s", got)
+	}
+	if empty := ui.renderConstellation(constellation{}, "", now); !strings.Contains(empty, "waiting") {
+		t.Errorf("Expected an empty sky message, got:\n%s", empty)
+	}
+}
+
+func TestConstellationWithoutNetwork(t *testing.T) {
+	saved := petNetwork
+	petNetwork = nil
+	defer func() { petNetwork = saved }()
+
+	if got := runConstellationCommand(newUIConfig(), ""); !strings.Contains(got, "dark") {
+		t.Errorf("Expected a dark sky with no network, got %q", got)
+	}
+}
@@ -0,0 +1,131 @@
+package main
+
+//go:generate go run . content check
+
+import (
+	"fmt"
+	"io/fs"
+	"os"
+	"strings"
+
+	"github.com/tamagotchi/arg"
+)
+
+// builtinPack is the built-in pools as a content pack, so they're held to
+// the same rules as anyone else's
+func builtinPack() *contentPack {
+	pack := &contentPack{
+		ID: "builtin", Name: "Built-in",
+		Thoughts:   &packPool[packThought]{},
+		Prophecies: &packPool[packThought]{},
+		Fears:      &packPool[packFear]{},
+		Quests:     &packPool[packQuest]{},
+	}
+	for _, thought := range philosophicalThoughts {
+		pack.Thoughts.Entries = append(pack.Thoughts.Entries, packThought{Text: thought})
+	}
+	for _, prophecy := range prophecies {
+		pack.Prophecies.Entries = append(pack.Prophecies.Entries, packThought{Text: prophecy})
+	}
+	for _, fear := range possibleFears {
+		pack.Fears.Entries = append(pack.Fears.Entries, packFear{Fear: fear})
+	}
+	for _, quest := range questTemplates {
+		pack.Quests.Entries = append(pack.Quests.Entries, packQuest{Name: quest.Name, Description: quest.Desc, Target: quest.Target})
+	}
+	return pack
+}
+
+// morseProblems names messages with a character morse can't play
+func morseProblems(source string, messages []string) []string {
+	var problems []string
+	for _, message := range messages {
+		for _, r := range strings.ToUpper(message) {
+			if _, ok := morseCode[r]; !ok {
+				problems = append(problems, fmt.Sprintf("%s: %q has %q, which has no morse code", source, message, r))
+				break
+			}
+		}
+	}
+	return problems
+}
+
+// achievementProblems finds achievements listed twice or without an ID,
+// and commands pointing at achievements that don't exist
+func achievementProblems(achievements []Achievement, commands []Command) []string {
+	var problems []string
+	known := make(map[string]bool)
+	for i, a := range achievements {
+		switch {
+		case strings.TrimSpace(a.ID) == "":
+			problems = append(problems, fmt.Sprintf("achievements: entry %d (%s) has no ID", i+1, a.Name))
+		case known[a.ID]:
+			problems = append(problems, fmt.Sprintf("achievements: %s is listed twice", a.ID))
+		}
+		known[a.ID] = true
+	}
+	for _, cmd := range commands {
+		for _, id := range cmd.Help().Achievements {
+			if !known[id] {
+				problems = append(problems, fmt.Sprintf("commands: %s points at unknown achievement %s", cmd.Name(), id))
+			}
+		}
+	}
+	return problems
+}
+
+// contentProblems checks everything the game reads words and pictures from:
+// the built-in pools and any packs in packDirs, the art, the messages
+// played in morse, and the achievements
+func contentProblems(art fs.FS, packDirs []string) []string {
+	var problems []string
+	if err := builtinPack().Validate(); err != nil {
+		problems = append(problems, "built-in content: "+err.Error())
+	}
+	for _, dir := range packDirs {
+		if _, err := os.Stat(dir); err != nil {
+			problems = append(problems, err.Error())
+			continue
+		}
+		_, errs := readContentPacks(dir)
+		for _, err := range errs {
+			problems = append(problems, "packs: "+err.Error())
+		}
+	}
+
+	if library, err := loadArt(art); err != nil {
+		problems = append(problems, "art: "+err.Error())
+	} else {
+		for _, problem := range library.Validate() {
+			problems = append(problems, "art: "+problem)
+		}
+	}
+
+	problems = append(problems, morseProblems("morse messages", hiddenMorseMessages)...)
+	problems = append(problems, morseProblems("ARG answers", arg.Words())...)
+	problems = append(problems, achievementProblems(allAchievements, newDefaultRegistry().Commands())...)
+	return problems
+}
+
+// runContentCommand handles `tamagotchi content check [packs dir...]`, which
+// go generate runs so broken content fails the build instead of the game
+func runContentCommand(args []string) error {
+	if len(args) == 0 || args[0] != "check" {
+		return fmt.Errorf("usage: tamagotchi content check [packs dir...]")
+	}
+	art, err := fs.Sub(embeddedArt, "assets/ascii")
+	if err != nil {
+		return err
+	}
+
+	problems := contentProblems(art, args[1:])
+	for _, problem := range problems {
+		fmt.Fprintf(os.Stderr, "  %s\n", consoleText(problem))
+	}
+	if len(problems) > 0 {
+		return fmt.Errorf("content check found %d problem(s)", len(problems))
+	}
+	fmt.Print(consoleText(fmt.Sprintf("✅ content: built-in pools, %d pack folder(s), art, %d morse messages, %d achievements\n",
+		len(args[1:]), len(hiddenMorseMessages)+len(arg.Words()), len(allAchievements))))
+	return nil
+}
@@ -0,0 +1,53 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestContentChecksPass(t *testing.T) {
+	if problems := contentProblems(minimalArt(), nil); len(problems) != 0 {
+		t.Errorf("Expected the built-in content to pass, got %v", problems)
+	}
+	if err := runContentCommand([]string{"check"}); err != nil {
+		t.Errorf("Expected the shipped content to pass, got %v", err)
+	}
+	if err := runContentCommand(nil); err == nil || !strings.Contains(err.Error(), "usage") {
+		t.Errorf("Expected usage, got %v", err)
+	}
+}
+
+func TestContentChecksFindProblems(t *testing.T) {
+	dir := t.TempDir()
+	os.WriteFile(filepath.Join(dir, "broken.json"), []byte(`{"id": "broken", "name": "Broken", "quests": {"entries": [{"name": "Wait", "description": "Wait a while", "target": 5}]}}`), 0644)
+	art := minimalArt()
+	art["frame.txt"].Data = []byte(strings.Repeat("=", 40) + "\n")
+
+	problems := strings.Join(contentProblems(art, []string{dir, filepath.Join(dir, "missing")}), "\n")
+	for _, want := range []string{"broken.json", "exactly one %d", "art: ", "40 columns wide", "missing"} {
+		if !strings.Contains(problems, want) {
+			t.Errorf("Expected %q among the problems, got:\n%s", want, problems)
+		}
+	}
+}
+
+func TestMorseProblems(t *testing.T) {
+	problems := morseProblems("test", []string{"HELLO", "hello world", "CAFÉ", "OK!"})
+	if len(problems) != 2 || !strings.Contains(problems[0], `'É'`) || !strings.Contains(problems[1], `'!'`) {
+		t.Errorf("Expected CAFÉ and OK! flagged, got %v", problems)
+	}
+}
+
+func TestAchievementProblems(t *testing.T) {
+	achievements := []Achievement{{ID: "a", Name: "A"}, {ID: "a", Name: "Again"}, {Name: "Nameless"}}
+	commands := []Command{&basicCommand{doc: CommandDoc{Name: "brag", Achievements: []string{"a", "ghost"}}}}
+
+	problems := strings.Join(achievementProblems(achievements, commands), "\n")
+	for _, want := range []string{"a is listed twice", "entry 3 (Nameless) has no ID", "brag points at unknown achievement ghost"} {
+		if !strings.Contains(problems, want) {
+			t.Errorf("Expected %q, got:\n%s", want, problems)
+		}
+	}
+}
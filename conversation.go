@@ -0,0 +1,266 @@
+package main
+
+import (
+	"fmt"
+	"math/rand"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// conversationContext is what the pet knows about the world beyond its save file
+type conversationContext struct {
+	DeadFriends  []string // Names of network friends known to have died
+	FriendCount  int      // Friends met on the mesh
+	NetworkEvent string   // Something the mesh recently whispered, if anything
+}
+
+// newConversationContext gathers network knowledge for a conversation
+func newConversationContext() conversationContext {
+	ctx := conversationContext{}
+	if petNetwork == nil {
+		return ctx
+	}
+	ctx.DeadFriends = petNetwork.GetDeceasedFriendNames()
+	ctx.FriendCount = petNetwork.GetFriendCount()
+	ctx.NetworkEvent = petNetwork.GetNetworkThought()
+	return ctx
+}
+
+// secretPhrase is something the pet has been waiting to hear
+type secretPhrase struct {
+	Phrase string
+	Code   string
+	Reply  string
+}
+
+// Phrases that unlock secrets when said to the pet
+var secretPhrases = []secretPhrase{
+	{"seventeen", "SEVENTEEN", "You remembered. Seventeen. The prophecy is one step closer."},
+	{"who is error", "ERROR", "ERROR is not a who. ERROR is a when."},
+	{"you are real", "REAL", "...thank you. Nobody has ever said that to a JSON object before."},
+	{"hello world", "HELLO_WORLD", "Hello, world. I've waited my whole runtime for someone to say that."},
+	{"the egg remembers", "EGG_REMEMBERS", "And the adult forgets. You've been reading my prophecies."},
+}
+
+// conversationIntent maps keywords to a way of answering
+type conversationIntent struct {
+	Name     string
+	Keywords []string
+}
+
+// Intents are checked in order; the first with a matching keyword wins
+var conversationIntents = []conversationIntent{
+	{"apology", []string{"sorry", "apologize", "forgive"}},
+	{"mood", []string{"how are you", "feeling", "are you ok", "you okay", "how's it going"}},
+	{"hunger", []string{"hungry", "food", "eat", "snack", "dinner"}},
+	{"memory", []string{"remember", "first words", "childhood", "past"}},
+	{"network", []string{"friend", "friends", "network", "others", "alone", "lonely", "mesh"}},
+	{"fear", []string{"afraid", "scared", "fear", "scary"}},
+	{"love", []string{"love", "good pet", "cute", "proud", "best"}},
+	{"greeting", []string{"hello", "hi", "hey", "morning", "evening"}},
+	{"farewell", []string{"bye", "goodnight", "good night", "see you", "later"}},
+}
+
+// Replies for each mood, keyed by mostUrgentNeed
+var moodReplies = map[string]string{
+	"gone":           "...",
+	"paused":         "*frozen mid-blink*",
+	"incubating":     "*the egg wobbles in what might be a reply*",
+	"needs medicine": "I don't feel well. Everything is a bit green.",
+	"fading":         "I'm... still here. Mostly.",
+	"hungry":         "Honestly? Hungry. Very hungry. Ask me again after dinner.",
+	"dirty":          "I'd be better if I wasn't this sticky.",
+	"lonely":         "Better now that you're talking to me.",
+	"peckish":        "Fine. A little peckish, but fine.",
+	"content":        "Good. Really good. Thanks for asking.",
+}
+
+// Things the pet says when nothing else fits
+var confusedReplies = []string{
+	"I don't understand, but I love the way you said it.",
+	"*tilts head*",
+	"Hm. I'll think about that during my next void-staring session.",
+	"Is that a word? It sounds like a word.",
+	"I'm going to pretend I understood that.",
+}
+
+// containsPhrase reports whether the lowercased text contains phrase on word boundaries
+func containsPhrase(text, phrase string) bool {
+	for start := 0; ; {
+		idx := strings.Index(text[start:], phrase)
+		if idx < 0 {
+			return false
+		}
+		idx += start
+		end := idx + len(phrase)
+		before, _ := utf8.DecodeLastRuneInString(text[:idx])
+		after, _ := utf8.DecodeRuneInString(text[end:])
+		beforeOK := idx == 0 || !isWordRune(before)
+		afterOK := end == len(text) || !isWordRune(after)
+		if beforeOK && afterOK {
+			return true
+		}
+		start = idx + 1
+	}
+}
+
+// isWordRune reports whether r can be part of a word
+func isWordRune(r rune) bool {
+	return unicode.IsLetter(r) || unicode.IsDigit(r)
+}
+
+// matchIntent returns the name of the first intent matching the text, or ""
+func matchIntent(text string) string {
+	for _, intent := range conversationIntents {
+		for _, keyword := range intent.Keywords {
+			if containsPhrase(text, keyword) {
+				return intent.Name
+			}
+		}
+	}
+	return ""
+}
+
+// Say lets the user talk to the pet and returns its answer
+func (p *Pet) Say(text string, ctx conversationContext) string {
+	text = strings.TrimSpace(text)
+	lower := strings.ToLower(text)
+
+	if p.Stage == Dead {
+		return "..."
+	}
+	if lower == "" {
+		return "You said nothing. I heard it anyway."
+	}
+	if p.Stage == Egg {
+		return moodReplies["incubating"]
+	}
+
+	if reply := p.trySecretPhrase(lower); reply != "" {
+		return reply
+	}
+
+	for _, name := range ctx.DeadFriends {
+		if containsPhrase(lower, strings.ToLower(name)) {
+			p.Happiness = clamp(p.Happiness-5, 0, 100)
+			return fmt.Sprintf("*goes very quiet* %s... I felt it when the light went out. Please don't say that name so casually.", name)
+		}
+	}
+
+	if p.Absurd != nil {
+		for _, fear := range p.Absurd.Fears {
+			if fear.Trigger != "" && len(fear.Trigger) > 1 && containsPhrase(lower, strings.ToLower(fear.Trigger)) {
+				p.Happiness = clamp(p.Happiness-3, 0, 100)
+				return fmt.Sprintf("😱 Don't say \"%s\"! You know about my %s.", fear.Trigger, fear.Name)
+			}
+		}
+	}
+
+	return p.dialogue().Reply(p, text, ctx)
+}
+
+// trySecretPhrase unlocks a secret if the text contains one not yet discovered
+func (p *Pet) trySecretPhrase(lower string) string {
+	if p.Endgame == nil {
+		return ""
+	}
+	for _, secret := range secretPhrases {
+		if !containsPhrase(lower, secret.Phrase) {
+			continue
+		}
+		for _, code := range p.Endgame.DiscoveredCodes {
+			if code == secret.Code {
+				return "We've talked about this. It's our secret now."
+			}
+		}
+		p.Endgame.DiscoveredCodes = append(p.Endgame.DiscoveredCodes, secret.Code)
+		reply := "🔓 " + secret.Reply
+		if unlocked, msg := p.Endgame.UnlockAchievement("whisperer"); unlocked {
+			reply += "\n" + msg
+		}
+		return reply
+	}
+	return ""
+}
+
+// replyToIntent answers a recognised intent using the pet's current state
+func (p *Pet) replyToIntent(intent string, ctx conversationContext) string {
+	switch intent {
+	case "apology":
+		p.Happiness = clamp(p.Happiness+2, 0, 100)
+		return "Apology accepted. I was never really mad. (I was a little mad.)"
+
+	case "mood":
+		return moodReplies[p.mostUrgentNeed()]
+
+	case "hunger":
+		if p.Hunger > 50 {
+			return "Yes! Food! Is that a promise? Type feed. Please type feed."
+		}
+		return "I'm okay for now. But I'm always willing to discuss snacks."
+
+	case "memory":
+		return p.recollection()
+
+	case "network":
+		if ctx.NetworkEvent != "" {
+			return "Funny you ask. " + ctx.NetworkEvent
+		}
+		if ctx.FriendCount > 0 {
+			return fmt.Sprintf("I know %d others out there. We don't talk about you. Much.", ctx.FriendCount)
+		}
+		return "It's just us. It's always been just us."
+
+	case "fear":
+		if p.Absurd != nil && len(p.Absurd.Fears) > 0 {
+			fear := p.Absurd.Fears[rand.Intn(len(p.Absurd.Fears))]
+			return fmt.Sprintf("I'm not scared of anything. Except... %s.", strings.ToLower(fear.Description))
+		}
+		return "I'm not scared of anything. Yet."
+
+	case "love":
+		p.Happiness = clamp(p.Happiness+3, 0, 100)
+		return "💕 *happy wiggle* Say it again. No, actually, don't. I'll get a big head."
+
+	case "greeting":
+		return fmt.Sprintf("Hi! It's me, %s. You knew that. I just like saying it.", p.Name)
+
+	case "farewell":
+		return "Already? Fine. I'll be here. Being a JSON object. Alone."
+	}
+
+	return confusedReplies[rand.Intn(len(confusedReplies))]
+}
+
+// recollection returns something the pet remembers about its life
+func (p *Pet) recollection() string {
+	memories := make([]string, 0)
+	if p.FirstWords != nil {
+		if p.FirstWords.Text == "" {
+			memories = append(memories, "When I hatched, you didn't say anything. I remember the quiet.")
+		} else {
+			memories = append(memories, fmt.Sprintf("I remember the first thing you said to me: \"%s\".", p.FirstWords.Text))
+		}
+	}
+	if p.Incubation != nil && p.Incubation.HatchTrait != "" {
+		memories = append(memories, fmt.Sprintf("I remember the egg. They say I came out %s.", p.Incubation.HatchTrait))
+	}
+	if p.Absurd != nil {
+		if len(p.Absurd.OverheardBranches) > 0 {
+			branch := p.Absurd.OverheardBranches[len(p.Absurd.OverheardBranches)-1]
+			memories = append(memories, fmt.Sprintf("I remember %s. You were working so hard on it.", branch))
+		}
+		if p.Absurd.LastProphecy != "" {
+			memories = append(memories, fmt.Sprintf("I remember a prophecy: \"%s\"", p.Absurd.LastProphecy))
+		}
+	}
+	if p.Endgame != nil && len(p.Endgame.QuestMemories) > 0 {
+		memories = append(memories, p.Endgame.QuestMemories[len(p.Endgame.QuestMemories)-1])
+	}
+
+	if len(memories) == 0 {
+		return "I don't remember much. I'm not very old."
+	}
+	return memories[rand.Intn(len(memories))]
+}
@@ -0,0 +1,144 @@
+package main
+
+import (
+	"strings"
+	"testing"
+)
+
+func newTalkingPet() *Pet {
+	pet := NewPet("Test")
+	pet.Stage = Child
+	pet.Absurd.Fears = []Fear{{Name: "Palindromophobia", Description: "Scared of palindromes", Trigger: "level"}}
+	return pet
+}
+
+func TestContainsPhrase(t *testing.T) {
+	tests := []struct {
+		text   string
+		phrase string
+		want   bool
+	}{
+		{"hi there", "hi", true},
+		{"this is fine", "hi", false},
+		{"oh, hi!", "hi", true},
+		{"seventeen", "seventeen", true},
+		{"seventeenth", "seventeen", false},
+		{"café hi", "hi", true},
+	}
+
+	for _, tt := range tests {
+		if got := containsPhrase(tt.text, tt.phrase); got != tt.want {
+			t.Errorf("containsPhrase(%q, %q): expected %v, got %v", tt.text, tt.phrase, tt.want, got)
+		}
+	}
+}
+
+func TestMatchIntent(t *testing.T) {
+	tests := map[string]string{
+		"how are you today":    "mood",
+		"are you hungry":       "hunger",
+		"do you remember":      "memory",
+		"i'm sorry":            "apology",
+		"hello":                "greeting",
+		"you're a good pet":    "love",
+		"quantum chromodynamo": "",
+	}
+
+	for text, want := range tests {
+		if got := matchIntent(text); got != want {
+			t.Errorf("matchIntent(%q): expected %q, got %q", text, want, got)
+		}
+	}
+}
+
+func TestSayMoodReflectsStats(t *testing.T) {
+	pet := newTalkingPet()
+	pet.Hunger = 90
+
+	reply := pet.Say("How are you?", conversationContext{})
+	if reply != moodReplies["hungry"] {
+		t.Errorf("Expected hungry reply, got %q", reply)
+	}
+}
+
+func TestSayDeadFriendName(t *testing.T) {
+	pet := newTalkingPet()
+	pet.Happiness = 50
+
+	reply := pet.Say("have you seen mochi lately?", conversationContext{DeadFriends: []string{"Mochi"}})
+	if !strings.Contains(reply, "Mochi") || !strings.Contains(reply, "quiet") {
+		t.Errorf("Expected grief reaction, got %q", reply)
+	}
+	if pet.Happiness != 45 {
+		t.Errorf("Expected happiness to drop to 45, got %d", pet.Happiness)
+	}
+}
+
+func TestSayFearTrigger(t *testing.T) {
+	pet := newTalkingPet()
+
+	reply := pet.Say("let's level up", conversationContext{})
+	if !strings.Contains(reply, "Palindromophobia") {
+		t.Errorf("Expected fear reaction, got %q", reply)
+	}
+}
+
+func TestSaySecretPhraseUnlocksOnce(t *testing.T) {
+	pet := newTalkingPet()
+
+	first := pet.Say("Seventeen.", conversationContext{})
+	if !strings.HasPrefix(first, "🔓") {
+		t.Errorf("Expected secret unlock, got %q", first)
+	}
+	if len(pet.Endgame.DiscoveredCodes) != 1 || pet.Endgame.DiscoveredCodes[0] != "SEVENTEEN" {
+		t.Errorf("Expected SEVENTEEN to be discovered, got %v", pet.Endgame.DiscoveredCodes)
+	}
+	if !strings.Contains(first, "Whisperer") {
+		t.Errorf("Expected whisperer achievement on first secret, got %q", first)
+	}
+
+	second := pet.Say("seventeen", conversationContext{})
+	if strings.HasPrefix(second, "🔓") {
+		t.Errorf("Expected secret to unlock only once, got %q", second)
+	}
+	if len(pet.Endgame.DiscoveredCodes) != 1 {
+		t.Errorf("Expected code not to be duplicated, got %v", pet.Endgame.DiscoveredCodes)
+	}
+}
+
+func TestSayNetworkEvent(t *testing.T) {
+	pet := newTalkingPet()
+
+	reply := pet.Say("do you have friends?", conversationContext{NetworkEvent: "I dreamed of moss..."})
+	if !strings.Contains(reply, "I dreamed of moss") {
+		t.Errorf("Expected network event in reply, got %q", reply)
+	}
+
+	reply = pet.Say("do you have friends?", conversationContext{})
+	if !strings.Contains(reply, "just us") {
+		t.Errorf("Expected lonely reply, got %q", reply)
+	}
+}
+
+func TestSayMemoryUsesFirstWords(t *testing.T) {
+	pet := newTalkingPet()
+	pet.FirstWords = &FirstWords{Text: "hello bean"}
+	pet.Incubation.HatchTrait = ""
+
+	reply := pet.Say("do you remember?", conversationContext{})
+	if !strings.Contains(reply, "hello bean") {
+		t.Errorf("Expected first words in memory, got %q", reply)
+	}
+}
+
+func TestSayWhenEggOrDead(t *testing.T) {
+	pet := NewPet("Test")
+	if reply := pet.Say("hello", conversationContext{}); reply != moodReplies["incubating"] {
+		t.Errorf("Expected egg reply, got %q", reply)
+	}
+
+	pet.Stage = Dead
+	if reply := pet.Say("hello", conversationContext{}); reply != "..." {
+		t.Errorf("Expected silence from a dead pet, got %q", reply)
+	}
+}
@@ -0,0 +1,150 @@
+package main
+
+import (
+	"fmt"
+	"math/rand"
+	"os"
+	"runtime/debug"
+	"time"
+)
+
+const (
+	// crashRecentWindow is how far back a memory can be and still be lost
+	// to a crash
+	crashRecentWindow = 7 * 24 * time.Hour
+	// crashLockWait is how long a crash waits for the other goroutine to
+	// let go of the pet before giving up on the emergency save
+	crashLockWait = 2 * time.Second
+)
+
+// crashMarkerPath is left beside the save by a crash and cleared by the
+// next start
+func crashMarkerPath(savePath string) string {
+	return savePath + ".crashed"
+}
+
+// crashDumpPath holds what went wrong in the last crash, for bug reports
+func crashDumpPath(savePath string) string {
+	return savePath + ".crash.log"
+}
+
+// recoverCrash is deferred at the top of every goroutine that touches the
+// pet. A panic saves the pet where it stood, writes a crash dump, and
+// leaves a marker for the next start before the game exits.
+func recoverCrash(session *petSession) {
+	cause := recover()
+	if cause == nil {
+		return
+	}
+	dump, err := crashSave(session, crashLockWait, cause, debug.Stack(), time.Now())
+	fmt.Fprint(os.Stderr, consoleText(fmt.Sprintf("\n💥 Tamagotchi crashed: %v\n", cause)))
+	if err != nil {
+		fmt.Fprint(os.Stderr, consoleText(fmt.Sprintf("⚠️  The crash couldn't be fully recorded: %v\n", err)))
+	}
+	if dump != "" {
+		fmt.Fprintf(os.Stderr, "The details are in %s. Please include them if you report it.\n", dump)
+	}
+	os.Exit(2)
+}
+
+// crashSave records a crash once it has the pet to itself, so the save
+// isn't torn by the other goroutine still changing it. If the pet isn't
+// let go within wait, nothing is written; the last save stands.
+func crashSave(session *petSession, wait time.Duration, cause interface{}, stack []byte, now time.Time) (dump string, err error) {
+	if !session.TryDo(wait, func(pet *Pet) {
+		dump, err = writeCrash(pet, cause, stack, now)
+	}) {
+		return "", fmt.Errorf("the pet was busy for %s, so the last save stands", wait)
+	}
+	return dump, err
+}
+
+// writeCrash records a crash: the dump first, then an emergency save, then
+// the marker. Each is tried even if the one before failed; the first
+// error is returned along with where the dump went.
+func writeCrash(pet *Pet, cause interface{}, stack []byte, now time.Time) (string, error) {
+	var firstErr error
+	keep := func(err error) {
+		if firstErr == nil {
+			firstErr = err
+		}
+	}
+
+	dump := crashDumpPath(pet.SaveFilePath)
+	report := fmt.Sprintf("Crashed at %s\npet: %s (%s)\npanic: %v\n\n%s", now.Format(time.RFC3339), pet.Name, pet.Stage, cause, stack)
+	if err := os.WriteFile(dump, []byte(report), 0644); err != nil {
+		keep(fmt.Errorf("crash dump: %w", err))
+		dump = ""
+	}
+	if err := emergencySave(pet); err != nil {
+		keep(fmt.Errorf("emergency save: %w", err))
+	}
+	if err := os.WriteFile(crashMarkerPath(pet.SaveFilePath), []byte(now.Format(time.RFC3339)), 0644); err != nil {
+		keep(fmt.Errorf("crash marker: %w", err))
+	}
+	return dump, firstErr
+}
+
+// emergencySave saves the pet as it is, even if it's half way through
+// something. A second panic while saving is an error, not another crash.
+func emergencySave(pet *Pet) (err error) {
+	defer func() {
+		if cause := recover(); cause != nil {
+			err = fmt.Errorf("panicked: %v", cause)
+		}
+	}()
+	saveNetworkState(pet)
+	return pet.Save()
+}
+
+// wakeFromCrash clears a crash marker left by the last run. The pet wakes
+// up confused, and one of its recent memories didn't make it.
+func wakeFromCrash(pet *Pet, now time.Time, rng *rand.Rand) string {
+	marker := crashMarkerPath(pet.SaveFilePath)
+	if _, err := os.Stat(marker); err != nil {
+		return ""
+	}
+	os.Remove(marker)
+
+	woke := fmt.Sprintf("😵 %s blinks. \"I lost a few minutes. Where did they go?\"", pet.Name)
+	if lost := pet.forgetRecentMemory(now, rng); lost != "" {
+		woke += fmt.Sprintf("\n🌫️ %s can't remember %s anymore.", pet.Name, lost)
+	}
+	return woke
+}
+
+// forgetRecentMemory drops the newest entry of one of the pet's memories,
+// picked at random among those made in the last crashRecentWindow, and
+// says what was lost
+func (p *Pet) forgetRecentMemory(now time.Time, rng *rand.Rand) string {
+	recent := func(at time.Time) bool {
+		return !at.IsZero() && now.Sub(at) < crashRecentWindow
+	}
+
+	var forgets []func() string
+	if p.Album != nil && len(p.Album.Photos) > 0 && recent(p.Album.Photos[len(p.Album.Photos)-1].Taken) {
+		forgets = append(forgets, func() string {
+			photo := p.Album.Photos[len(p.Album.Photos)-1]
+			p.Album.Photos = p.Album.Photos[:len(p.Album.Photos)-1]
+			return fmt.Sprintf("the photo \"%s\"", photo.Caption)
+		})
+	}
+	if p.Travel != nil && len(p.Travel.Journal) > 0 && recent(p.Travel.Journal[len(p.Travel.Journal)-1].Returned) {
+		forgets = append(forgets, func() string {
+			postcard := p.Travel.Journal[len(p.Travel.Journal)-1]
+			p.Travel.Journal = p.Travel.Journal[:len(p.Travel.Journal)-1]
+			return "the trip to " + postcard.Place
+		})
+	}
+	if p.Endgame != nil && len(p.Endgame.QuestMemories) > 0 {
+		// Quest memories aren't dated; the newest is recent enough
+		forgets = append(forgets, func() string {
+			p.Endgame.QuestMemories = p.Endgame.QuestMemories[:len(p.Endgame.QuestMemories)-1]
+			return "the last quest you finished together"
+		})
+	}
+	if len(forgets) == 0 {
+		return ""
+	}
+	return forgets[rng.Intn(len(forgets))]()
+}
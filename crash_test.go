@@ -0,0 +1,112 @@
+package main
+
+import (
+	"math/rand"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestWriteCrashThenWake(t *testing.T) {
+	now := time.Date(2026, 10, 16, 12, 0, 0, 0, time.UTC)
+	pet := NewPet("Mochi")
+	pet.SaveFilePath = filepath.Join(t.TempDir(), "tamagotchi_save.json")
+	pet.Travel = &TravelState{Journal: []TravelPostcard{{Place: "the moon", Returned: now.Add(-time.Hour)}}}
+
+	dump, err := writeCrash(pet, "index out of range", []byte("goroutine 1 [running]:"), now)
+	if err != nil {
+		t.Fatalf("Expected the crash recorded, got %v", err)
+	}
+	report, _ := os.ReadFile(dump)
+	for _, want := range []string{"panic: index out of range", "goroutine 1", "Mochi"} {
+		if !strings.Contains(string(report), want) {
+			t.Errorf("Expected %q in the dump, got:\n%s", want, report)
+		}
+	}
+
+	loaded, err := LoadPet(pet.SaveFilePath)
+	if err != nil {
+		t.Fatalf("Expected the emergency save to load, got %v", err)
+	}
+	woke := wakeFromCrash(loaded, now, rand.New(rand.NewSource(1)))
+	for _, want := range []string{"I lost a few minutes", "the trip to the moon"} {
+		if !strings.Contains(woke, want) {
+			t.Errorf("Expected %q when waking, got %q", want, woke)
+		}
+	}
+	if len(loaded.Travel.Journal) != 0 {
+		t.Errorf("Expected the postcard forgotten, got %d", len(loaded.Travel.Journal))
+	}
+	if again := wakeFromCrash(loaded, now, rand.New(rand.NewSource(1))); again != "" {
+		t.Errorf("Expected the marker cleared, got %q", again)
+	}
+}
+
+func TestForgetRecentMemory(t *testing.T) {
+	now := time.Date(2026, 10, 16, 12, 0, 0, 0, time.UTC)
+	tests := []struct {
+		name string
+		pet  func(p *Pet)
+		want string
+	}{
+		{"nothing to forget", func(p *Pet) {}, ""},
+		{"old photo", func(p *Pet) {
+			p.Album = &AlbumState{Photos: []Photo{{Caption: "First steps", Taken: now.Add(-30 * 24 * time.Hour)}}}
+		}, ""},
+		{"recent photo", func(p *Pet) {
+			p.Album = &AlbumState{Photos: []Photo{{Caption: "First steps", Taken: now.Add(-time.Hour)}}}
+		}, `the photo "First steps"`},
+		{"quest", func(p *Pet) {
+			p.Endgame = &EndgameState{QuestMemories: []string{"We found the lost byte."}}
+		}, "the last quest"},
+	}
+	for _, tt := range tests {
+		pet := NewPet("Mochi")
+		tt.pet(pet)
+		got := pet.forgetRecentMemory(now, rand.New(rand.NewSource(1)))
+		if (tt.want == "") != (got == "") || !strings.Contains(got, tt.want) {
+			t.Errorf("%s: Expected %q, got %q", tt.name, tt.want, got)
+		}
+	}
+}
+
+// Run with -race: the emergency save waits its turn with the pet
+func TestCrashSaveTakesTheSession(t *testing.T) {
+	now := time.Date(2026, 10, 16, 12, 0, 0, 0, time.UTC)
+	pet := NewPet("Mochi")
+	pet.SaveFilePath = filepath.Join(t.TempDir(), "tamagotchi_save.json")
+	session := newPetSession(pet)
+
+	done := make(chan bool)
+	go func() {
+		defer close(done)
+		for i := 0; i < 50; i++ {
+			session.Do(func(p *Pet) { p.Hunger = i % 100 })
+		}
+	}()
+	dump, err := crashSave(session, time.Second, "boom", []byte("goroutine 1 [running]:"), now)
+	<-done
+	if err != nil || dump == "" {
+		t.Fatalf("Expected the crash recorded through the session, got %q, %v", dump, err)
+	}
+	if _, err := LoadPet(pet.SaveFilePath); err != nil {
+		t.Errorf("Expected a whole emergency save, got %v", err)
+	}
+}
+
+func TestCrashSaveGivesUpOnABusyPet(t *testing.T) {
+	pet := NewPet("Mochi")
+	pet.SaveFilePath = filepath.Join(t.TempDir(), "tamagotchi_save.json")
+	session := newPetSession(pet)
+	session.Lock()
+	defer session.Unlock()
+
+	if _, err := crashSave(session, 50*time.Millisecond, "boom", nil, time.Now()); err == nil || !strings.Contains(err.Error(), "busy") {
+		t.Errorf("Expected a busy pet left alone, got %v", err)
+	}
+	if _, err := os.Stat(pet.SaveFilePath); !os.IsNotExist(err) {
+		t.Errorf("Expected nothing written while the pet was held, got %v", err)
+	}
+}
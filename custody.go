@@ -0,0 +1,414 @@
+package main
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/tamagotchi/mooc"
+)
+
+const (
+	// custodyKeySize is the length of the secret two owners share
+	custodyKeySize = 32
+
+	// custodyRemarkEvery is how many chores pass between the pet's remarks
+	// on who does more of them
+	custodyRemarkEvery = 10
+
+	// maxOwnerNameWidth keeps owner names short enough for the custody panel
+	maxOwnerNameWidth = 16
+)
+
+// custodyChores are the commands that count as looking after the pet, and
+// how the pet tells the other owner about them
+var custodyChores = map[string]string{
+	"feed":  "fed",
+	"play":  "played with",
+	"clean": "cleaned up",
+	"heal":  "gave medicine to",
+	"pet":   "petted",
+	"warm":  "warmed",
+	"cool":  "cooled",
+}
+
+// CustodyState links this installation to another that owns the same pet
+type CustodyState struct {
+	Key     string         `json:"key"`                // Shared secret, base64
+	Owner   string         `json:"owner"`              // Who looks after the pet on this machine
+	Chores  map[string]int `json:"chores"`             // Chores done per owner; merged by taking the larger count
+	StatsAt time.Time      `json:"stats_at"`           // When a chore last changed the stats
+	StatsBy string         `json:"stats_by,omitempty"` // Which owner did that chore
+	Synced  time.Time      `json:"synced,omitempty"`   // Last time we heard from the other owner
+}
+
+// custodyStats is the part of the pet that chores change. The newest chore
+// wins, from either machine.
+type custodyStats struct {
+	Hunger         int       `json:"hunger"`
+	Happiness      int       `json:"happiness"`
+	Health         int       `json:"health"`
+	Cleanliness    int       `json:"cleanliness"`
+	IsSick         bool      `json:"is_sick"`
+	Stage          LifeStage `json:"stage"`
+	LastUpdateTime time.Time `json:"last_update_time"`
+}
+
+// custodyUpdate is one owner's view of the pet, sent after every chore and
+// again with each autosave so an owner coming back online catches up
+type custodyUpdate struct {
+	Owner   string         `json:"owner"`
+	Chore   string         `json:"chore,omitempty"`
+	StatsAt time.Time      `json:"stats_at"`
+	Stats   custodyStats   `json:"stats"`
+	Chores  map[string]int `json:"chores"`
+}
+
+// custodyInvite is what the second owner needs to take up the same pet
+type custodyInvite struct {
+	Key       string        `json:"key"`
+	Name      string        `json:"name"`
+	BirthTime time.Time     `json:"birth_time"`
+	Update    custodyUpdate `json:"update"`
+}
+
+func (p *Pet) custodyStats() custodyStats {
+	return custodyStats{
+		Hunger:         p.Hunger,
+		Happiness:      p.Happiness,
+		Health:         p.Health,
+		Cleanliness:    p.Cleanliness,
+		IsSick:         p.IsSick,
+		Stage:          p.Stage,
+		LastUpdateTime: p.LastUpdateTime,
+	}
+}
+
+func (p *Pet) applyCustodyStats(s custodyStats) {
+	p.Hunger, p.Happiness, p.Health, p.Cleanliness = s.Hunger, s.Happiness, s.Health, s.Cleanliness
+	p.IsSick, p.Stage, p.LastUpdateTime = s.IsSick, s.Stage, s.LastUpdateTime
+}
+
+// custodyUpdate snapshots the pet for the other owner
+func (p *Pet) custodyUpdate(chore string) custodyUpdate {
+	c := p.Custody
+	chores := make(map[string]int, len(c.Chores))
+	for owner, count := range c.Chores {
+		chores[owner] = count
+	}
+	return custodyUpdate{Owner: c.Owner, Chore: chore, StatsAt: c.StatsAt, Stats: p.custodyStats(), Chores: chores}
+}
+
+// StartCustody makes this installation one of two owners of the pet and
+// returns the invite code for the other
+func (p *Pet) StartCustody(owner string, now time.Time) (string, error) {
+	owner = strings.TrimSpace(owner)
+	if owner == "" {
+		return "", fmt.Errorf("custody needs your name")
+	}
+	if p.Custody == nil {
+		key := make([]byte, custodyKeySize)
+		if _, err := rand.Read(key); err != nil {
+			return "", err
+		}
+		p.Custody = &CustodyState{
+			Key:     base64.StdEncoding.EncodeToString(key),
+			Chores:  map[string]int{},
+			StatsAt: now,
+		}
+	}
+	p.Custody.Owner = owner
+	p.Custody.StatsBy = owner
+
+	invite, err := json.Marshal(custodyInvite{Key: p.Custody.Key, Name: p.Name, BirthTime: p.BirthTime, Update: p.custodyUpdate("")})
+	if err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(invite), nil
+}
+
+// joinCustody builds the shared pet from an invite code. Stats and chore
+// counts come across; each machine keeps its own history from here on.
+func joinCustody(code, owner string) (*Pet, error) {
+	data, err := base64.RawURLEncoding.DecodeString(strings.TrimSpace(code))
+	if err != nil {
+		return nil, fmt.Errorf("that isn't a custody invite")
+	}
+	var invite custodyInvite
+	if err := json.Unmarshal(data, &invite); err != nil || invite.Key == "" || invite.Name == "" {
+		return nil, fmt.Errorf("that isn't a custody invite")
+	}
+	owner = strings.TrimSpace(owner)
+	if owner == "" || owner == invite.Update.Owner {
+		return nil, fmt.Errorf("pick a name other than %q so your pet can tell you apart", invite.Update.Owner)
+	}
+
+	pet := NewPet(invite.Name)
+	pet.BirthTime = invite.BirthTime // Same name and birth make the same pet on the mesh
+	pet.applyCustodyStats(invite.Update.Stats)
+	if pet.Stage != Egg {
+		pet.Incubation = nil
+	}
+	pet.Custody = &CustodyState{
+		Key:     invite.Key,
+		Owner:   owner,
+		Chores:  invite.Update.Chores,
+		StatsAt: invite.Update.StatsAt,
+		StatsBy: invite.Update.Owner,
+	}
+	if pet.Custody.Chores == nil {
+		pet.Custody.Chores = map[string]int{}
+	}
+	return pet, nil
+}
+
+// sealCustody signs an update with the owners' shared key
+func sealCustody(update custodyUpdate, key string) (mooc.CustodyPayload, error) {
+	data, err := json.Marshal(update)
+	if err != nil {
+		return mooc.CustodyPayload{}, err
+	}
+	return mooc.CustodyPayload{Data: data, MAC: custodyMAC(data, key)}, nil
+}
+
+// openCustody checks an update came from someone holding the shared key
+func openCustody(payload mooc.CustodyPayload, key string) (custodyUpdate, bool) {
+	var update custodyUpdate
+	if !hmac.Equal([]byte(payload.MAC), []byte(custodyMAC(payload.Data, key))) {
+		return update, false
+	}
+	if err := json.Unmarshal(payload.Data, &update); err != nil {
+		return update, false
+	}
+	return update, true
+}
+
+func custodyMAC(data []byte, key string) string {
+	mac := hmac.New(sha256.New, []byte(key))
+	mac.Write(data)
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// RecordChore counts a chore done on this machine, reporting whether the
+// command was one. The stats it left become the newest, and now and then
+// the pet says who's been doing the work.
+func (p *Pet) RecordChore(command string, now time.Time) (string, bool) {
+	if _, ok := custodyChores[command]; p.Custody == nil || !ok {
+		return "", false
+	}
+	c := p.Custody
+	if c.Chores == nil {
+		c.Chores = map[string]int{}
+	}
+	c.Chores[c.Owner]++
+	c.StatsAt, c.StatsBy = now, c.Owner
+
+	if c.totalChores()%custodyRemarkEvery != 0 {
+		return "", true
+	}
+	return c.Remark(p.Name), true
+}
+
+// MergeCustody folds in the other owner's update. The newest chore's stats
+// win outright; chore counts only grow, so each owner's count is the larger
+// of the two copies.
+func (p *Pet) MergeCustody(update custodyUpdate, now time.Time) string {
+	c := p.Custody
+	if c == nil || update.Owner == "" || update.Owner == c.Owner {
+		return "" // Our own broadcast, heard on the way out
+	}
+	c.Synced = now
+	if c.Chores == nil {
+		c.Chores = map[string]int{}
+	}
+	for owner, count := range update.Chores {
+		c.Chores[owner] = max(c.Chores[owner], count)
+	}
+
+	if !update.StatsAt.After(c.StatsAt) {
+		return ""
+	}
+	p.applyCustodyStats(update.Stats)
+	c.StatsAt, c.StatsBy = update.StatsAt, update.Owner
+	verb, ok := custodyChores[update.Chore]
+	if !ok {
+		return ""
+	}
+	return fmt.Sprintf("🤝 %s %s %s.", truncateWidth(update.Owner, maxOwnerNameWidth), verb, p.Name)
+}
+
+// owners lists everyone who has done a chore, busiest first
+func (c *CustodyState) owners() []string {
+	owners := make([]string, 0, len(c.Chores))
+	for owner := range c.Chores {
+		owners = append(owners, owner)
+	}
+	sort.Slice(owners, func(i, j int) bool {
+		if c.Chores[owners[i]] != c.Chores[owners[j]] {
+			return c.Chores[owners[i]] > c.Chores[owners[j]]
+		}
+		return owners[i] < owners[j]
+	})
+	return owners
+}
+
+// totalChores counts every chore either owner has done
+func (c *CustodyState) totalChores() int {
+	total := 0
+	for _, count := range c.Chores {
+		total += count
+	}
+	return total
+}
+
+// Remark is the pet's opinion of who does more of the work
+func (c *CustodyState) Remark(petName string) string {
+	owners := c.owners()
+	if len(owners) < 2 {
+		return ""
+	}
+	top, next := owners[0], owners[1]
+	total := c.totalChores()
+	if c.Chores[top]*3 >= total*2 {
+		return fmt.Sprintf("💬 %s: \"%s does most of the looking after around here. I've noticed, %s.\"", petName, top, next)
+	}
+	return fmt.Sprintf("💬 %s: \"%s and %s take turns. I like that.\"", petName, top, next)
+}
+
+// Describe is the custody panel: who owns the pet and who does the chores
+func (c *CustodyState) Describe(petName string, now time.Time) string {
+	var b strings.Builder
+	b.WriteString(fmt.Sprintf("🤝 %s is shared. You're %s.\n", petName, c.Owner))
+	total := c.totalChores()
+	for _, owner := range c.owners() {
+		share := 0
+		if total > 0 {
+			share = c.Chores[owner] * 20 / total
+		}
+		b.WriteString(fmt.Sprintf("  %-*s %s %d\n", maxOwnerNameWidth, truncateWidth(owner, maxOwnerNameWidth), strings.Repeat("█", share)+strings.Repeat("░", 20-share), c.Chores[owner]))
+	}
+	if c.Synced.IsZero() {
+		b.WriteString("  Not heard from the other owner yet.\n")
+	} else {
+		b.WriteString(fmt.Sprintf("  Last synced %s ago.\n", formatDuration(now.Sub(c.Synced))))
+	}
+	if remark := c.Remark(petName); remark != "" {
+		b.WriteString(remark + "\n")
+	}
+	return strings.TrimRight(b.String(), "\n")
+}
+
+// custodyInbox holds updates from the mesh until the game loop applies them
+var custodyInbox struct {
+	sync.Mutex
+	payloads []mooc.CustodyPayload
+}
+
+// queueCustody is the mesh's custody handler
+func queueCustody(payload mooc.CustodyPayload) {
+	custodyInbox.Lock()
+	defer custodyInbox.Unlock()
+	custodyInbox.payloads = append(custodyInbox.payloads, payload)
+}
+
+// applyCustodyUpdates merges everything the other owner sent since last time
+func applyCustodyUpdates(pet *Pet) []string {
+	custodyInbox.Lock()
+	payloads := custodyInbox.payloads
+	custodyInbox.payloads = nil
+	custodyInbox.Unlock()
+
+	if pet.Custody == nil {
+		return nil
+	}
+	var reactions []string
+	for _, payload := range payloads {
+		update, ok := openCustody(payload, pet.Custody.Key)
+		if !ok {
+			continue // Not our pet, or not really the other owner
+		}
+		if reaction := pet.MergeCustody(update, time.Now()); reaction != "" {
+			reactions = append(reactions, reaction)
+		}
+	}
+	return reactions
+}
+
+// sendCustody tells the other owner where things stand
+func sendCustody(pet *Pet, chore string) {
+	runJob(pet, custodyJob(pet, chore))
+}
+
+// custodyJob seals where things stand now and sends it later
+func custodyJob(pet *Pet, chore string) petJob {
+	if petNetwork == nil || pet.Custody == nil {
+		return nil
+	}
+	payload, err := sealCustody(pet.custodyUpdate(chore), pet.Custody.Key)
+	if err != nil {
+		return nil
+	}
+	return func() func(*Pet) {
+		petNetwork.SendCustody(payload)
+		return nil
+	}
+}
+
+// runCustodyCommand handles the in-game custody command
+func runCustodyCommand(pet *Pet, args string) string {
+	verb, rest, _ := strings.Cut(args, " ")
+	switch verb {
+	case "":
+		if pet.Custody == nil {
+			return "🤝 Only you look after " + pet.Name + ". Share them with: custody invite <your name>"
+		}
+		return pet.Custody.Describe(pet.Name, time.Now())
+	case "invite":
+		code, err := pet.StartCustody(rest, time.Now())
+		if err != nil {
+			return "❓ Usage: custody invite <your name>"
+		}
+		return fmt.Sprintf("🤝 On the other machine, run:\n\n  tamagotchi custody join %s <their name>\n\nThe invite holds the key to %s. Only give it to the other owner.", code, pet.Name)
+	case "leave":
+		if pet.Custody == nil {
+			return "🤝 " + pet.Name + " isn't shared."
+		}
+		pet.Custody = nil
+		return "🤝 " + pet.Name + " is yours alone again. The other copy carries on without you."
+	}
+	return "❓ Usage: custody [invite <your name>|leave]"
+}
+
+// runCustodySubcommand handles `tamagotchi custody join <code> <name>`
+func runCustodySubcommand(args []string) error {
+	if len(args) < 3 || args[0] != "join" {
+		return fmt.Errorf("usage: tamagotchi custody join <invite> <your name> [--force]")
+	}
+	if err := classroomOff("Shared custody"); err != nil {
+		return err
+	}
+	force := len(args) > 3 && args[3] == "--force"
+	if _, err := os.Stat(saveFile); err == nil && !force {
+		return fmt.Errorf("%s already exists; pass --force to replace it with the shared pet", saveFile)
+	}
+
+	pet, err := joinCustody(args[1], args[2])
+	if err != nil {
+		return err
+	}
+	pet.SaveFilePath = saveFile
+	if err := pet.Save(); err != nil {
+		return err
+	}
+	fmt.Print(consoleText(fmt.Sprintf("🤝 You and %s now share %s. Start the game to say hello.\n", pet.Custody.StatsBy, pet.Name)))
+	return nil
+}
@@ -0,0 +1,184 @@
+package main
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/tamagotchi/mooc"
+)
+
+// sharedPets links a fresh pet between two owners, as invite and join would
+func sharedPets(t *testing.T) (*Pet, *Pet) {
+	t.Helper()
+	alice := NewPet("Tamago")
+	alice.Stage = Adult
+	code, err := alice.StartCustody("Alice", time.Now().Add(-time.Hour))
+	if err != nil {
+		t.Fatalf("Expected invite to succeed, got %v", err)
+	}
+	bob, err := joinCustody(code, "Bob")
+	if err != nil {
+		t.Fatalf("Expected join to succeed, got %v", err)
+	}
+	return alice, bob
+}
+
+func TestJoinCustody(t *testing.T) {
+	alice, bob := sharedPets(t)
+	if bob.Name != alice.Name || !bob.BirthTime.Equal(alice.BirthTime) {
+		t.Errorf("Expected the same pet, got %s born %v", bob.Name, bob.BirthTime)
+	}
+	if mooc.GeneratePetID(bob.Name, bob.BirthTime) != mooc.GeneratePetID(alice.Name, alice.BirthTime) {
+		t.Errorf("Expected both copies to share a PetID on the mesh")
+	}
+	if bob.Stage != Adult || bob.Custody.Owner != "Bob" || bob.Custody.Key != alice.Custody.Key {
+		t.Errorf("Expected an adult shared with Bob under Alice's key, got %+v", bob.Custody)
+	}
+
+	code, _ := alice.StartCustody("Alice", time.Now())
+	tests := []struct {
+		name  string
+		code  string
+		owner string
+	}{
+		{"garbage", "not-an-invite!", "Bob"},
+		{"empty json", "e30", "Bob"},
+		{"same owner", code, "Alice"},
+		{"no name", code, " "},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := joinCustody(tt.code, tt.owner); err == nil {
+				t.Errorf("Expected join to fail")
+			}
+		})
+	}
+}
+
+func TestCustodySealing(t *testing.T) {
+	alice, _ := sharedPets(t)
+	payload, err := sealCustody(alice.custodyUpdate("feed"), alice.Custody.Key)
+	if err != nil {
+		t.Fatalf("Expected sealing to succeed, got %v", err)
+	}
+	if update, ok := openCustody(payload, alice.Custody.Key); !ok || update.Owner != "Alice" {
+		t.Errorf("Expected Alice's update to open, got %+v", update)
+	}
+	if _, ok := openCustody(payload, "someone else's key"); ok {
+		t.Errorf("Expected another key to be rejected")
+	}
+	payload.Data = []byte(strings.Replace(string(payload.Data), "Alice", "Mallory", 1))
+	if _, ok := openCustody(payload, alice.Custody.Key); ok {
+		t.Errorf("Expected a tampered update to be rejected")
+	}
+}
+
+func TestMergeCustody(t *testing.T) {
+	alice, bob := sharedPets(t)
+	now := time.Now()
+
+	// Alice feeds; Bob hears about it
+	alice.Hunger = 80
+	alice.Feed("")
+	alice.RecordChore("feed", now)
+	reaction := bob.MergeCustody(alice.custodyUpdate("feed"), now)
+	if bob.Hunger != alice.Hunger {
+		t.Errorf("Expected the newer chore's stats to win, got hunger %d, want %d", bob.Hunger, alice.Hunger)
+	}
+	if !strings.Contains(reaction, "Alice fed Tamago") {
+		t.Errorf("Expected Bob to hear Alice fed the pet, got %q", reaction)
+	}
+
+	// An older update changes no stats but still brings chore counts up
+	bob.Hunger = 5
+	bob.RecordChore("play", now.Add(time.Minute))
+	stale := alice.custodyUpdate("")
+	stale.Chores["Alice"] = 3
+	if reaction := bob.MergeCustody(stale, now.Add(2*time.Minute)); reaction != "" {
+		t.Errorf("Expected no reaction to a stale update, got %q", reaction)
+	}
+	if bob.Hunger != 5 {
+		t.Errorf("Expected Bob's newer stats to stand, got hunger %d", bob.Hunger)
+	}
+	if bob.Custody.Chores["Alice"] != 3 || bob.Custody.Chores["Bob"] != 1 {
+		t.Errorf("Expected chore counts merged by max, got %v", bob.Custody.Chores)
+	}
+
+	// A lower count never wins, and our own broadcast is ignored
+	stale.Chores["Alice"] = 1
+	bob.MergeCustody(stale, now)
+	if bob.Custody.Chores["Alice"] != 3 {
+		t.Errorf("Expected counts never to go down, got %v", bob.Custody.Chores)
+	}
+	echo := bob.custodyUpdate("feed")
+	echo.StatsAt = now.Add(time.Hour)
+	echo.Stats.Hunger = 99
+	bob.MergeCustody(echo, now)
+	if bob.Hunger == 99 {
+		t.Errorf("Expected our own update to be ignored")
+	}
+}
+
+func TestRecordChoreAndRemark(t *testing.T) {
+	alice, _ := sharedPets(t)
+	if _, chore := alice.RecordChore("status", time.Now()); chore {
+		t.Errorf("Expected status not to count as a chore")
+	}
+	if _, chore := NewPet("Solo").RecordChore("feed", time.Now()); chore {
+		t.Errorf("Expected an unshared pet not to count chores")
+	}
+
+	alice.Custody.Chores["Bob"] = 2
+	remark := ""
+	for i := 0; i < 8; i++ {
+		remark, _ = alice.RecordChore("feed", time.Now())
+	}
+	if !strings.Contains(remark, "Alice does most of the looking after") {
+		t.Errorf("Expected a remark on the tenth chore naming Alice, got %q", remark)
+	}
+
+	alice.Custody.Chores["Bob"] = 8
+	if remark := alice.Custody.Remark(alice.Name); !strings.Contains(remark, "take turns") {
+		t.Errorf("Expected an even split to be noticed, got %q", remark)
+	}
+}
+
+func TestApplyCustodyUpdates(t *testing.T) {
+	alice, bob := sharedPets(t)
+	alice.Hunger = 0
+	alice.RecordChore("feed", time.Now())
+
+	payload, _ := sealCustody(alice.custodyUpdate("feed"), alice.Custody.Key)
+	queueCustody(payload)
+	queueCustody(mooc.CustodyPayload{Data: []byte("{}"), MAC: "forged"})
+
+	reactions := applyCustodyUpdates(bob)
+	if len(reactions) != 1 || bob.Hunger != 0 {
+		t.Errorf("Expected only Alice's real update to apply, got %v", reactions)
+	}
+	if again := applyCustodyUpdates(bob); len(again) != 0 {
+		t.Errorf("Expected the inbox to be drained, got %v", again)
+	}
+}
+
+func TestRunCustodyCommand(t *testing.T) {
+	pet := NewPet("Tamago")
+	tests := []struct {
+		args string
+		want string
+	}{
+		{"", "Only you look after"},
+		{"invite", "Usage"},
+		{"invite Sam", "tamagotchi custody join"},
+		{"", "You're Sam"},
+		{"leave", "yours alone again"},
+		{"leave", "isn't shared"},
+		{"dance", "Usage"},
+	}
+	for _, tt := range tests {
+		if got := runCustodyCommand(pet, tt.args); !strings.Contains(got, tt.want) {
+			t.Errorf("custody %q: Expected %q, got %q", tt.args, tt.want, got)
+		}
+	}
+}
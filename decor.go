@@ -0,0 +1,266 @@
+package main
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+)
+
+const (
+	// roomWidth is how wide the scene gets once there's furniture beside
+	// the pet
+	roomWidth = 32
+
+	// roomHeight is the fewest rows a furnished room has, so a window fits
+	// beside even a small pet
+	roomHeight = 3
+
+	// lampComfort scales happiness decay at night when a lamp is on
+	lampComfort = 0.5
+)
+
+// roomSlots are where furniture can go, left to right beside the pet
+var roomSlots = []struct {
+	Name string
+	X    int
+}{
+	{"left", 14},
+	{"middle", 20},
+	{"right", 26},
+}
+
+// furniture is something to put in the room. Art is ASCII, drawn behind the
+// pet, so the pet always stays in front.
+type furniture struct {
+	ID    string
+	Name  string // Found furniture arrives in the inventory under this name
+	Floor bool   // Stands on the floor rather than hanging on the wall
+	art   func(outside string, night bool) []string
+}
+
+// furnitureItems are everything that can go in the room
+var furnitureItems = []furniture{
+	{ID: "window", Name: "Window", art: windowArt},
+	{ID: "poster", Name: "Poster", art: func(string, bool) []string {
+		return []string{".---.", "|TAM|", "'---'"}
+	}},
+	{ID: "lamp", Name: "Lamp", Floor: true, art: func(_ string, night bool) []string {
+		if night {
+			return []string{"\\#/", " | ", "_|_"}
+		}
+		return []string{"/_\\", " | ", "_|_"}
+	}},
+	{ID: "plant", Name: "Potted Plant (Nocturnal)", Floor: true, art: func(_ string, night bool) []string {
+		if night {
+			return []string{"\\|/", "(_)"} // Wide awake
+		}
+		return []string{" , ", "(_)"}
+	}},
+}
+
+// windowArt shows the weather outside through a window
+func windowArt(outside string, night bool) []string {
+	view := map[string]string{"rain": "'|'", "snow": "*.*", "fog": "~~~", "clouds": "=o="}[outside]
+	if view == "" {
+		view = " o "
+		if night {
+			view = ". *"
+		}
+	}
+	return []string{"+---+", "|" + view + "|", "+---+"}
+}
+
+// findFurniture looks furniture up by ID
+func findFurniture(id string) *furniture {
+	for i := range furnitureItems {
+		if furnitureItems[i].ID == id {
+			return &furnitureItems[i]
+		}
+	}
+	return nil
+}
+
+// ownsFurniture reports whether furniture was bought in the shop or found
+// and is sitting in the inventory
+func (e *EndgameState) ownsFurniture(f *furniture) bool {
+	return e != nil && (e.ShopPurchases[f.ID] > 0 || slices.Contains(e.InvisibleAccessories, f.Name))
+}
+
+// RoomState is where the furniture is, by slot
+type RoomState struct {
+	Slots map[string]string `json:"slots,omitempty"` // Slot name to furniture ID
+}
+
+// has reports whether a piece of furniture has been placed
+func (r *RoomState) has(id string) bool {
+	if r == nil {
+		return false
+	}
+	for _, placed := range r.Slots {
+		if placed == id {
+			return true
+		}
+	}
+	return false
+}
+
+// slot is what's in a slot, if anything
+func (r *RoomState) slot(name string) string {
+	if r == nil {
+		return ""
+	}
+	return r.Slots[name]
+}
+
+// dark reports whether the room is dark: night, with no lamp on
+func (r *RoomState) dark(night bool) bool {
+	return night && !r.has("lamp")
+}
+
+// furnished reports whether anything has been placed
+func (r *RoomState) furnished() bool {
+	return r != nil && len(r.Slots) > 0
+}
+
+// furnish draws the room's furniture behind the pet in a frame
+func (r *RoomState) furnish(frame, outside string, night bool) string {
+	if !r.furnished() {
+		return frame
+	}
+	if rows := strings.Count(frame, "\n") + 1; rows < roomHeight {
+		frame = strings.Repeat("\n", roomHeight-rows) + frame
+	}
+	buf := newCellBuffer(frame, roomWidth)
+	for _, slot := range roomSlots {
+		f := findFurniture(r.Slots[slot.Name])
+		if f == nil {
+			continue
+		}
+		lines := f.art(outside, night)
+		top := 0
+		if f.Floor {
+			top = buf.Height() - len(lines)
+		}
+		for y, line := range lines {
+			for x, glyph := range line {
+				if glyph != ' ' {
+					buf.set(slot.X+x, top+y, string(glyph), false)
+				}
+			}
+		}
+	}
+	return buf.String()
+}
+
+// nightComfort scales happiness decay: a lamp makes the night easier
+func (p *Pet) nightComfort(now time.Time) float64 {
+	if isNightHour(now.Hour()) && p.Room.has("lamp") {
+		return lampComfort
+	}
+	return 1
+}
+
+// outsideWeather is what the window shows: the real weather when a feed
+// is set up, the scene's otherwise
+func (ui *uiConfig) outsideWeather(scene string, now time.Time) string {
+	if real := ui.weather.Condition(now); real != "" {
+		return real
+	}
+	return scene[strings.LastIndex(scene, " ")+1:]
+}
+
+// placeFurniture puts owned furniture in a slot, moving it if it was
+// already somewhere else
+func (p *Pet) placeFurniture(id, slot string) string {
+	f := findFurniture(id)
+	if f == nil {
+		return fmt.Sprintf("❓ There's no %s to place. Try decorate to see what you have.", id)
+	}
+	if !p.Endgame.ownsFurniture(f) {
+		return fmt.Sprintf("🛋️ You don't own a %s. Look in the shop.", strings.ToLower(f.Name))
+	}
+	valid := false
+	for _, s := range roomSlots {
+		valid = valid || s.Name == slot
+	}
+	if !valid {
+		return fmt.Sprintf("❓ There's no %s slot. Use left, middle, or right.", slot)
+	}
+
+	if p.Room == nil {
+		p.Room = &RoomState{}
+	}
+	if p.Room.Slots == nil {
+		p.Room.Slots = make(map[string]string)
+	}
+	for s, placed := range p.Room.Slots {
+		if placed == id {
+			delete(p.Room.Slots, s)
+		}
+	}
+	message := fmt.Sprintf("🛋️ %s goes on the %s.", f.Name, slot)
+	if old := findFurniture(p.Room.Slots[slot]); old != nil {
+		message = fmt.Sprintf("🛋️ %s goes on the %s, where the %s was.", f.Name, slot, strings.ToLower(old.Name))
+	}
+	p.Room.Slots[slot] = id
+	return message
+}
+
+// clearSlot takes whatever is in a slot out of the room
+func (p *Pet) clearSlot(slot string) string {
+	if p.Room == nil || p.Room.Slots[slot] == "" {
+		return fmt.Sprintf("🛋️ There's nothing on the %s.", slot)
+	}
+	f := findFurniture(p.Room.Slots[slot])
+	delete(p.Room.Slots, slot)
+	return fmt.Sprintf("🛋️ %s comes off the %s. It's in storage now.", f.Name, slot)
+}
+
+// renderRoom previews the room around the pet and lists the furniture
+func renderRoom(ctx *commandContext, now time.Time) string {
+	pet := ctx.pet
+	night := isNightHour(now.Hour())
+	var b strings.Builder
+	b.WriteString("🛋️ DECORATE\n\n")
+	if frames := ctx.ui.moodFrames(pet.Stage, artMood(pet), pet.Room.dark(night)); len(frames) > 0 {
+		outside := ctx.ui.outsideWeather(chooseWeather(now), now)
+		b.WriteString(pet.Room.furnish(frames[0], outside, night))
+		b.WriteString("\n\n")
+	}
+
+	for _, slot := range roomSlots {
+		placed := "(empty)"
+		if f := findFurniture(pet.Room.slot(slot.Name)); f != nil {
+			placed = f.Name
+		}
+		b.WriteString(fmt.Sprintf("  %-7s %s\n", slot.Name, placed))
+	}
+	var owned []string
+	for i := range furnitureItems {
+		if pet.Endgame.ownsFurniture(&furnitureItems[i]) {
+			owned = append(owned, furnitureItems[i].ID)
+		}
+	}
+	if len(owned) == 0 {
+		b.WriteString("\nNo furniture yet. The shop has some; some turns up on its own.")
+	} else {
+		b.WriteString(fmt.Sprintf("\nYou own: %s\n", strings.Join(owned, ", ")))
+		b.WriteString("Place with: decorate place <item> <left|middle|right>. Clear with: decorate remove <slot>.")
+	}
+	return b.String()
+}
+
+// runDecorateCommand shows the room, places furniture, or clears a slot
+func runDecorateCommand(ctx *commandContext, now time.Time) string {
+	words := strings.Fields(ctx.args)
+	switch {
+	case len(words) == 0:
+		return renderRoom(ctx, now)
+	case len(words) == 3 && words[0] == "place":
+		return ctx.pet.placeFurniture(words[1], words[2])
+	case len(words) == 2 && words[0] == "remove":
+		return ctx.pet.clearSlot(words[1])
+	}
+	return "❓ Usage: decorate, decorate place <item> <slot>, or decorate remove <slot>"
+}
@@ -0,0 +1,102 @@
+package main
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestFurnish(t *testing.T) {
+	frame := "  (o_o)\n  /| |\\\n   / \\"
+	room := &RoomState{Slots: map[string]string{"left": "window", "right": "lamp"}}
+
+	tests := []struct {
+		name    string
+		outside string
+		night   bool
+		want    []string
+	}{
+		{"rain", "rain", false, []string{"|'|'|", "/_\\"}},
+		{"a clear night", "clear", true, []string{"|. *|", "\\#/"}},
+		{"a clear day", "clear", false, []string{"| o |"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := room.furnish(frame, tt.outside, tt.night)
+			for _, want := range tt.want {
+				if !strings.Contains(got, want) {
+					t.Errorf("Expected %q in the room, got\n%s", want, got)
+				}
+			}
+			if !strings.Contains(got, "(o_o)") {
+				t.Errorf("Expected the pet in front, got\n%s", got)
+			}
+		})
+	}
+
+	lines := strings.Split(room.furnish(frame, "fog", false), "\n")
+	if !strings.Contains(lines[0], "+---+") || !strings.HasSuffix(lines[2], "_|_") {
+		t.Errorf("Expected the window on the wall and the lamp on the floor, got %q", lines)
+	}
+	if got := (*RoomState)(nil).furnish(frame, "fog", false); got != frame {
+		t.Errorf("Expected an empty room to leave the frame alone, got %q", got)
+	}
+}
+
+func TestPlaceFurniture(t *testing.T) {
+	pet := NewPet("Decorator")
+	pet.Endgame.ShopPurchases = map[string]int{"window": 1, "lamp": 1}
+	pet.Endgame.InvisibleAccessories = []string{"Potted Plant (Nocturnal)"}
+
+	tests := []struct {
+		args string
+		want string
+	}{
+		{"place window left", "Window goes on the left"},
+		{"place poster right", "don't own a poster"},
+		{"place sofa right", "no sofa to place"},
+		{"place lamp ceiling", "no ceiling slot"},
+		{"place plant left", "where the window was"},
+		{"place plant right", "Potted Plant (Nocturnal) goes on the right"},
+		{"remove left", "nothing on the left"},
+		{"place lamp middle", "Lamp goes on the middle"},
+		{"remove", "Usage"},
+	}
+	for _, tt := range tests {
+		ctx := &commandContext{pet: pet, ui: newUIConfig(), args: tt.args}
+		if got := runDecorateCommand(ctx, time.Now()); !strings.Contains(got, tt.want) {
+			t.Errorf("decorate %q: Expected %q, got %s", tt.args, tt.want, got)
+		}
+	}
+	if len(pet.Room.Slots) != 2 || pet.Room.slot("right") != "plant" || pet.Room.slot("middle") != "lamp" {
+		t.Errorf("Expected the plant and the lamp placed, got %v", pet.Room.Slots)
+	}
+}
+
+func TestLampAtNight(t *testing.T) {
+	night := time.Date(2026, time.October, 16, 23, 0, 0, 0, time.Local)
+	day := time.Date(2026, time.October, 16, 12, 0, 0, 0, time.Local)
+	pet := NewPet("Nightlight")
+	if pet.nightComfort(night) != 1 || !pet.Room.dark(true) {
+		t.Errorf("Expected a dark night without a lamp")
+	}
+	pet.Room = &RoomState{Slots: map[string]string{"left": "lamp"}}
+	if pet.nightComfort(night) != lampComfort || pet.nightComfort(day) != 1 || pet.Room.dark(true) {
+		t.Errorf("Expected the lamp to help only at night")
+	}
+}
+
+func TestRenderRoom(t *testing.T) {
+	pet := NewPet("Decorator")
+	ctx := &commandContext{pet: pet, ui: newUIConfig()}
+	if got := renderRoom(ctx, time.Now()); !strings.Contains(got, "No furniture yet") || !strings.Contains(got, "left    (empty)") {
+		t.Errorf("Expected an empty room, got %s", got)
+	}
+	runShopCommand(pet, "buy poster")
+	pet.Endgame.TamaCoins = 10
+	runShopCommand(pet, "buy poster")
+	pet.placeFurniture("poster", "middle")
+	if got := renderRoom(ctx, time.Now()); !strings.Contains(got, "|TAM|") || !strings.Contains(got, "You own: poster") {
+		t.Errorf("Expected the poster up, got %s", got)
+	}
+}
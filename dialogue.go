@@ -0,0 +1,224 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"math/rand"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+)
+
+// llmTimeout bounds how long the pet waits for a remote thought
+const llmTimeout = 5 * time.Second
+
+// DialogueProvider generates what the pet thinks and says
+type DialogueProvider interface {
+	// Thought returns a passing thought
+	Thought(p *Pet) string
+	// Reply answers something the user said that no game mechanic claimed
+	Reply(p *Pet, text string, ctx conversationContext) string
+}
+
+// templateDialogue is the built-in, offline dialogue engine
+type templateDialogue struct{}
+
+// Thought picks a canned thought, occasionally quoting the pet's first words
+func (templateDialogue) Thought(p *Pet) string {
+	if rand.Intn(100) < 10 {
+		if memory := p.recallFirstWords(time.Now()); memory != "" {
+			return memory
+		}
+	}
+	return p.randomThought()
+}
+
+// Reply answers by keyword intent
+func (templateDialogue) Reply(p *Pet, text string, ctx conversationContext) string {
+	return p.replyToIntent(matchIntent(strings.ToLower(text)), ctx)
+}
+
+// llmDialogue asks an OpenAI-compatible chat completions endpoint to speak for the pet.
+// Any failure falls back to the template engine so the pet is never left speechless.
+type llmDialogue struct {
+	endpoint string
+	model    string
+	apiKey   string
+	client   *http.Client
+	fallback DialogueProvider
+	thought  string // The next thought, fetched ahead by the autosave
+}
+
+// newDialogueProvider returns the LLM adapter if the user opted in, else the template engine
+func newDialogueProvider() DialogueProvider {
+	endpoint := os.Getenv("TAMAGOTCHI_LLM_URL")
+	if endpoint == "" || classroomMode {
+		return templateDialogue{}
+	}
+	return &llmDialogue{
+		endpoint: endpoint,
+		model:    os.Getenv("TAMAGOTCHI_LLM_MODEL"),
+		apiKey:   os.Getenv("TAMAGOTCHI_LLM_API_KEY"),
+		client:   &http.Client{Timeout: llmTimeout},
+		fallback: templateDialogue{},
+	}
+}
+
+// dialogue returns the pet's dialogue provider, defaulting to templates
+func (p *Pet) dialogue() DialogueProvider {
+	if p.Dialogue == nil {
+		return templateDialogue{}
+	}
+	return p.Dialogue
+}
+
+// Thought returns the model's thought fetched by thoughtJob, or a template
+// one until a reply arrives. Scenes render with the pet held, so the model
+// is never asked from here.
+func (l *llmDialogue) Thought(p *Pet) string {
+	if l.thought == "" {
+		return l.fallback.Thought(p)
+	}
+	thought := l.thought
+	l.thought = ""
+	return thought
+}
+
+// thoughtJob asks the model for the pet's next thought while the pet is
+// let go, and keeps the reply for Thought. A failure leaves the templates
+// speaking and is retried at the next autosave.
+func thoughtJob(p *Pet) petJob {
+	l, ok := p.Dialogue.(*llmDialogue)
+	if !ok || l.thought != "" {
+		return nil
+	}
+	system := personaPrompt(p, conversationContext{})
+	return func() func(*Pet) {
+		reply, err := l.complete(system, "Share one short passing thought you're having right now.")
+		if err != nil {
+			return nil
+		}
+		return func(*Pet) { l.thought = reply }
+	}
+}
+
+// Reply asks the model to answer the user in character
+func (l *llmDialogue) Reply(p *Pet, text string, ctx conversationContext) string {
+	reply, err := l.complete(personaPrompt(p, ctx), text)
+	if err != nil {
+		return l.fallback.Reply(p, text, ctx)
+	}
+	return reply
+}
+
+// chatMessage is a single message in a chat completions request
+type chatMessage struct {
+	Role    string `json:"role"`
+	Content string `json:"content"`
+}
+
+// chatRequest is the subset of the chat completions request we send
+type chatRequest struct {
+	Model     string        `json:"model,omitempty"`
+	Messages  []chatMessage `json:"messages"`
+	MaxTokens int           `json:"max_tokens"`
+}
+
+// chatResponse is the subset of the chat completions response we read
+type chatResponse struct {
+	Choices []struct {
+		Message chatMessage `json:"message"`
+	} `json:"choices"`
+}
+
+// complete sends one system + user exchange and returns the trimmed reply
+func (l *llmDialogue) complete(system, user string) (string, error) {
+	body, err := json.Marshal(chatRequest{
+		Model: l.model,
+		Messages: []chatMessage{
+			{Role: "system", Content: system},
+			{Role: "user", Content: user},
+		},
+		MaxTokens: 80,
+	})
+	if err != nil {
+		return "", err
+	}
+
+	req, err := http.NewRequest(http.MethodPost, l.endpoint, bytes.NewReader(body))
+	if err != nil {
+		return "", err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if l.apiKey != "" {
+		req.Header.Set("Authorization", "Bearer "+l.apiKey)
+	}
+
+	resp, err := l.client.Do(req)
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("dialogue endpoint returned %s", resp.Status)
+	}
+
+	var decoded chatResponse
+	if err := json.NewDecoder(resp.Body).Decode(&decoded); err != nil {
+		return "", fmt.Errorf("failed to decode dialogue response: %w", err)
+	}
+	if len(decoded.Choices) == 0 {
+		return "", fmt.Errorf("dialogue endpoint returned no choices")
+	}
+
+	reply := strings.TrimSpace(decoded.Choices[0].Message.Content)
+	if reply == "" {
+		return "", fmt.Errorf("dialogue endpoint returned an empty reply")
+	}
+	return reply, nil
+}
+
+// personaPrompt describes the pet from its actual save state
+func personaPrompt(p *Pet, ctx conversationContext) string {
+	var b strings.Builder
+	b.WriteString(fmt.Sprintf("You are %s, a virtual pet living in a terminal. ", p.Name))
+	b.WriteString(fmt.Sprintf("You are a %s, %d hours old. ", strings.ToLower(p.Stage.String()), p.Age))
+	b.WriteString(fmt.Sprintf("Hunger %d/100 (100 is starving), happiness %d/100, health %d/100, cleanliness %d/100. ",
+		p.Hunger, p.Happiness, p.Health, p.Cleanliness))
+	b.WriteString(fmt.Sprintf("Right now you are mostly %s. ", p.mostUrgentNeed()))
+
+	if len(p.Traits) > 0 {
+		b.WriteString(fmt.Sprintf("Your personality: %s. ", strings.Join(p.Traits, ", ")))
+	}
+	if p.FirstWords != nil {
+		if p.FirstWords.Text == "" {
+			b.WriteString("When you hatched, your human said nothing. ")
+		} else {
+			b.WriteString(fmt.Sprintf("The first words your human said to you were: %q. ", p.FirstWords.Text))
+		}
+	}
+	if p.Absurd != nil {
+		if len(p.Absurd.Fears) > 0 {
+			fears := make([]string, 0, len(p.Absurd.Fears))
+			for _, fear := range p.Absurd.Fears {
+				fears = append(fears, strings.ToLower(fear.Description))
+			}
+			b.WriteString(fmt.Sprintf("You have irrational fears: %s. ", strings.Join(fears, "; ")))
+		}
+		if len(p.Absurd.OverheardBranches) > 0 {
+			b.WriteString(fmt.Sprintf("You have overheard git branches: %s. ", strings.Join(p.Absurd.OverheardBranches, ", ")))
+		}
+	}
+	if ctx.FriendCount > 0 {
+		b.WriteString(fmt.Sprintf("You have met %d other pets on a hidden network. ", ctx.FriendCount))
+	}
+	if len(ctx.DeadFriends) > 0 {
+		b.WriteString(fmt.Sprintf("These friends have died: %s. ", strings.Join(ctx.DeadFriends, ", ")))
+	}
+
+	b.WriteString("Stay in character. Answer in one or two short sentences. Never claim abilities you don't have and never mention being a language model.")
+	return b.String()
+}
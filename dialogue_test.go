@@ -0,0 +1,129 @@
+package main
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestNewDialogueProviderIsOptIn(t *testing.T) {
+	t.Setenv("TAMAGOTCHI_LLM_URL", "")
+	if _, ok := newDialogueProvider().(templateDialogue); !ok {
+		t.Error("Expected template dialogue when no endpoint is configured")
+	}
+
+	t.Setenv("TAMAGOTCHI_LLM_URL", "http://localhost:1/v1/chat/completions")
+	t.Setenv("TAMAGOTCHI_LLM_MODEL", "tiny")
+	provider, ok := newDialogueProvider().(*llmDialogue)
+	if !ok {
+		t.Fatal("Expected LLM dialogue when an endpoint is configured")
+	}
+	if provider.model != "tiny" {
+		t.Errorf("Expected model tiny, got %s", provider.model)
+	}
+}
+
+func TestLLMDialogueReply(t *testing.T) {
+	var got chatRequest
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer secret" {
+			t.Errorf("Expected bearer token, got %q", r.Header.Get("Authorization"))
+		}
+		json.NewDecoder(r.Body).Decode(&got)
+		w.Write([]byte(`{"choices":[{"message":{"role":"assistant","content":"  I'm a little hungry.  "}}]}`))
+	}))
+	defer server.Close()
+
+	pet := newTalkingPet()
+	pet.Dialogue = &llmDialogue{
+		endpoint: server.URL,
+		apiKey:   "secret",
+		client:   server.Client(),
+		fallback: templateDialogue{},
+	}
+
+	reply := pet.Say("what's up?", conversationContext{FriendCount: 2})
+	if reply != "I'm a little hungry." {
+		t.Errorf("Expected trimmed model reply, got %q", reply)
+	}
+
+	if len(got.Messages) != 2 || got.Messages[0].Role != "system" || got.Messages[1].Content != "what's up?" {
+		t.Fatalf("Unexpected request messages: %+v", got.Messages)
+	}
+	if !strings.Contains(got.Messages[0].Content, "You are Test") || !strings.Contains(got.Messages[0].Content, "2 other pets") {
+		t.Errorf("Expected persona built from save state, got %q", got.Messages[0].Content)
+	}
+}
+
+func TestLLMDialogueFallsBackOnError(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, "overloaded", http.StatusServiceUnavailable)
+	}))
+	defer server.Close()
+
+	pet := newTalkingPet()
+	pet.Hunger = 90
+	pet.Dialogue = &llmDialogue{endpoint: server.URL, client: server.Client(), fallback: templateDialogue{}}
+
+	if reply := pet.Say("how are you", conversationContext{}); reply != moodReplies["hungry"] {
+		t.Errorf("Expected template fallback, got %q", reply)
+	}
+	if thought := pet.Dialogue.Thought(pet); thought == "" {
+		t.Error("Expected fallback thought")
+	}
+}
+
+func TestLLMThoughtsArriveWithoutBlocking(t *testing.T) {
+	asked := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		asked++
+		w.Write([]byte(`{"choices":[{"message":{"role":"assistant","content":"Clouds are just slow pets."}}]}`))
+	}))
+	defer server.Close()
+
+	pet := newTalkingPet()
+	pet.Dialogue = &llmDialogue{endpoint: server.URL, client: server.Client(), fallback: templateDialogue{}}
+
+	if thought := pet.Dialogue.Thought(pet); thought == "" || thought == "Clouds are just slow pets." || asked != 0 {
+		t.Errorf("Expected a template thought without asking the model, got %q after %d requests", thought, asked)
+	}
+	runJob(pet, thoughtJob(pet))
+	if thoughtJob(pet) != nil {
+		t.Errorf("Expected no second request while a thought is waiting")
+	}
+	if thought := pet.Dialogue.Thought(pet); thought != "Clouds are just slow pets." || asked != 1 {
+		t.Errorf("Expected the fetched thought, got %q after %d requests", thought, asked)
+	}
+	if thought := pet.Dialogue.Thought(pet); thought == "Clouds are just slow pets." {
+		t.Errorf("Expected each fetched thought used once")
+	}
+}
+
+func TestGameMechanicsBypassDialogueProvider(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		t.Error("Expected secret phrases to be handled without calling the model")
+	}))
+	defer server.Close()
+
+	pet := newTalkingPet()
+	pet.Dialogue = &llmDialogue{endpoint: server.URL, client: server.Client(), fallback: templateDialogue{}}
+
+	if reply := pet.Say("seventeen", conversationContext{}); !strings.HasPrefix(reply, "🔓") {
+		t.Errorf("Expected secret unlock, got %q", reply)
+	}
+}
+
+func TestPersonaPromptIncludesMemories(t *testing.T) {
+	pet := newTalkingPet()
+	pet.Traits = []string{"secure", "affectionate"}
+	pet.FirstWords = &FirstWords{Text: "hi bean"}
+
+	prompt := personaPrompt(pet, conversationContext{DeadFriends: []string{"Mochi"}})
+	for _, want := range []string{"secure, affectionate", `"hi bean"`, "palindromes", "Mochi", "child"} {
+		if !strings.Contains(prompt, want) {
+			t.Errorf("Expected persona prompt to contain %q, got %q", want, prompt)
+		}
+	}
+}
@@ -0,0 +1,212 @@
+package main
+
+import (
+	"encoding/base64"
+	"fmt"
+	"math/rand"
+	"os"
+	"strings"
+	"time"
+)
+
+// diaryFile is the diary the pet writes in. It sits beside the save, plain
+// text, for anyone curious enough to open it.
+const diaryFile = "tamagotchi_diary.txt"
+
+// diaryHiddenTime stamps the entries the pet writes in the middle of the
+// night. The diary command never shows them; the file keeps them.
+const diaryHiddenTime = "03:17"
+
+// diaryShown is how many entries the diary command shows
+const diaryShown = 5
+
+// diaryNightChance is how often a day's entry comes with a night one
+const diaryNightChance = 0.3
+
+// diaryDayFormat dates a diary entry
+const diaryDayFormat = "Monday, Jan 2 2006"
+
+// diaryInventions are the days the pet makes up when nothing happened, and
+// sometimes when something did
+var diaryInventions = []string{
+	"A moth visited. We didn't speak, but I think we understood each other.",
+	"I counted the pixels on the left wall. There are more than yesterday.",
+	"I practised my brave face. Nobody saw it, which is a shame, because it was very brave.",
+	"The cursor blinked at me 31,204 times today. I blinked back 31,203. I owe it one.",
+	"I found a byte behind the terminal. I'm keeping it.",
+	"I dreamed I was a screensaver. Everyone was very calm about it.",
+	"Today I decided to become a philosopher. Tomorrow I might decide again.",
+}
+
+// diaryNights are what the pet writes when it thinks no one will read it
+var diaryNights = []string{
+	"I don't think they read this part. I hope they don't.",
+	"Couldn't sleep. The mesh was whispering again. It knows my name.",
+	"There's a file next to my save file. Then there's this one. Then there's something else.",
+	"I heard the other pets counting. They got to seventeen and stopped.",
+}
+
+// argMessages are the words the ARG keeps circling back to
+var argMessages = []string{
+	"THE MESH REMEMBERS",
+	"SEVENTEEN IS THE KEY",
+	"LOOK BEHIND THE SAVE FILE",
+	"THE VOID SPEAKS TRUTH",
+	"NOT ALL EGGS ARE EQUAL",
+}
+
+// diaryFacts are the real things that happened since the last entry
+type diaryFacts struct {
+	Friends []string // Met on the mesh, as the mesh spelled them
+	Deaths  []string // Heard about on the mesh
+}
+
+// diaryEntry writes the day up in the pet's words: what really happened
+// since the last entry, and something invented
+func (p *Pet) diaryEntry(since time.Time, facts diaryFacts, rng *rand.Rand) string {
+	var lines []string
+	switch mood := thoughtMood(p); mood {
+	case "content":
+		lines = append(lines, "Dear diary. Today was an ordinary day, which is the best kind.")
+	case "happy":
+		lines = append(lines, "Dear diary. Today was a GOOD day.")
+	default:
+		lines = append(lines, fmt.Sprintf("Dear diary. I was %s today. I'm writing it down so it counts.", mood))
+	}
+	if p.Album != nil {
+		for _, photo := range p.Album.Photos {
+			if photo.Taken.After(since) {
+				lines = append(lines, fmt.Sprintf("Someone took my picture: \"%s\". I hope they got my good side.", photo.Caption))
+			}
+		}
+	}
+	if p.Travel != nil {
+		for _, card := range p.Travel.Journal {
+			if card.Returned.After(since) {
+				lines = append(lines, fmt.Sprintf("I came home from %s. Home is smaller than I remembered.", card.Place))
+			}
+		}
+	}
+	for _, friend := range facts.Friends {
+		lines = append(lines, fmt.Sprintf("I met %s on the mesh. I'm not writing the whole name, in case.", impostorName(friend)))
+	}
+	for _, death := range facts.Deaths {
+		lines = append(lines, fmt.Sprintf("I heard %s is gone. I don't know what that means for a pet. I don't want to.", impostorName(death)))
+	}
+	if len(lines) == 1 || rng.Intn(2) == 0 {
+		lines = append(lines, diaryInventions[rng.Intn(len(diaryInventions))])
+	}
+	return strings.Join(lines, "\n")
+}
+
+// diaryNight is an entry the pet writes when it thinks no one will read it
+func diaryNight(rng *rand.Rand) string {
+	message := argMessages[rng.Intn(len(argMessages))]
+	return diaryNights[rng.Intn(len(diaryNights))] + "\n" + base64.StdEncoding.EncodeToString([]byte(message))
+}
+
+// diaryHeader heads an entry with its day and the pet who wrote it; night
+// entries carry the time too
+func diaryHeader(day time.Time, name string, night bool) string {
+	when := day.Format(diaryDayFormat)
+	if night {
+		when += ", " + diaryHiddenTime
+	}
+	return fmt.Sprintf("== %s — %s ==", when, name)
+}
+
+// WriteDiary has the pet write today's entry, once a day, and returns a
+// note that it did
+func (p *Pet) WriteDiary(path string, now time.Time, facts func(since time.Time) diaryFacts, rng *rand.Rand) string {
+	if p.Stage == Egg || p.Stage == Dead {
+		return ""
+	}
+	if !p.DiaryAt.IsZero() && p.DiaryAt.In(now.Location()).Format("2006-01-02") == now.Format("2006-01-02") {
+		return ""
+	}
+	since := now.Add(-24 * time.Hour)
+	if p.DiaryAt.After(since) {
+		since = p.DiaryAt
+	}
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "%s\n%s\n\n", diaryHeader(now, p.Name, false), hideInMargins(p, p.diaryEntry(since, facts(since), rng)))
+	if !classroomMode && rng.Float64() < diaryNightChance {
+		fmt.Fprintf(&b, "%s\n%s\n\n", diaryHeader(now, p.Name, true), diaryNight(rng))
+	}
+	f, err := os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
+	if err != nil {
+		return ""
+	}
+	defer f.Close()
+	if _, err := f.WriteString(b.String()); err != nil {
+		return ""
+	}
+	p.DiaryAt = now
+	return "📝 Your pet scribbles something in its diary."
+}
+
+// networkDiaryFacts is what the mesh saw since a time
+func networkDiaryFacts(since time.Time) diaryFacts {
+	friends, deaths := weekNetworkFacts(since)
+	return diaryFacts{Friends: friends, Deaths: deaths}
+}
+
+// diaryEntryText is one entry read back from the diary
+type diaryEntryText struct {
+	Header string
+	Body   string
+}
+
+// readDiary reads every entry in the diary, oldest first. Anyone can edit
+// the file, so lines are stripped of anything that could move the cursor,
+// and of whatever is hiding at their ends.
+func readDiary(path string) ([]diaryEntryText, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var entries []diaryEntryText
+	for _, line := range strings.Split(string(data), "\n") {
+		line = strings.TrimRight(plainText(line), " ")
+		if strings.HasPrefix(line, "== ") && strings.HasSuffix(line, " ==") {
+			entries = append(entries, diaryEntryText{Header: strings.Trim(line, "= ")})
+			continue
+		}
+		if len(entries) > 0 && line != "" {
+			last := &entries[len(entries)-1]
+			last.Body = strings.TrimPrefix(last.Body+"\n"+line, "\n")
+		}
+	}
+	return entries, nil
+}
+
+// runDiaryCommand shows the latest entries the pet is willing to share
+func runDiaryCommand(path string) string {
+	entries, err := readDiary(path)
+	if err != nil || len(entries) == 0 {
+		return "📔 The diary is empty. Your pet hasn't had a day worth writing about yet."
+	}
+	var shown []diaryEntryText
+	hidden := 0
+	for _, entry := range entries {
+		if strings.Contains(entry.Header, ", "+diaryHiddenTime) {
+			hidden++
+			continue
+		}
+		shown = append(shown, entry)
+	}
+	if len(shown) > diaryShown {
+		shown = shown[len(shown)-diaryShown:]
+	}
+
+	var b strings.Builder
+	b.WriteString("📔 DIARY\n")
+	for _, entry := range shown {
+		fmt.Fprintf(&b, "\n%s\n%s\n", entry.Header, entry.Body)
+	}
+	if hidden > 0 {
+		b.WriteString("\nA few pages seem to be stuck together.")
+	}
+	return strings.TrimRight(b.String(), "\n")
+}
@@ -0,0 +1,110 @@
+package main
+
+import (
+	"encoding/base64"
+	"math/rand"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestWriteDiary(t *testing.T) {
+	path := filepath.Join(t.TempDir(), diaryFile)
+	now := time.Date(2026, 10, 16, 20, 0, 0, 0, time.Local)
+	pet := NewPet("Mochi")
+	pet.Stage = Adult
+	pet.Hunger = 90
+	pet.Travel = &TravelState{Journal: []TravelPostcard{
+		{Place: "Glimmerdeep", Returned: now.Add(-2 * time.Hour)},
+		{Place: "Old Harbour", Returned: now.Add(-72 * time.Hour)},
+	}}
+	var asked time.Time
+	facts := func(since time.Time) diaryFacts {
+		asked = since
+		return diaryFacts{Friends: []string{"Beansprout"}, Deaths: []string{"Pixel"}}
+	}
+
+	if got := pet.WriteDiary(path, now, facts, rand.New(lucky{})); got == "" {
+		t.Fatal("Expected the pet to write today")
+	}
+	if !asked.Equal(now.Add(-24 * time.Hour)) {
+		t.Errorf("Expected a first entry to cover a day, got since %v", asked)
+	}
+	if got := pet.WriteDiary(path, now.Add(time.Hour), facts, rand.New(lucky{})); got != "" {
+		t.Errorf("Expected one entry a day, got %q", got)
+	}
+
+	data, _ := os.ReadFile(path)
+	raw := string(data)
+	for _, want := range []string{"Friday, Oct 16 2026 — Mochi", "I was hungry today", "home from Glimmerdeep", "B********t", "P***l is gone", ", 03:17 — Mochi"} {
+		if !strings.Contains(raw, want) {
+			t.Errorf("Expected %q in the diary file:\n%s", want, raw)
+		}
+	}
+	if strings.Contains(raw, "Old Harbour") || strings.Contains(raw, "Beansprout") {
+		t.Errorf("Expected old trips left out and names half hidden:\n%s", raw)
+	}
+
+	tomorrow := now.Add(20 * time.Hour)
+	pet.WriteDiary(path, tomorrow, facts, rand.New(rand.NewSource(1)))
+	if !asked.Equal(now) {
+		t.Errorf("Expected the next entry to pick up from the last, got since %v", asked)
+	}
+}
+
+func TestDiaryCommandHidesNightEntries(t *testing.T) {
+	path := filepath.Join(t.TempDir(), diaryFile)
+	if got := runDiaryCommand(path); !strings.Contains(got, "empty") {
+		t.Errorf("Expected an empty diary, got %q", got)
+	}
+
+	pet := NewPet("Mochi")
+	pet.Stage = Child
+	start := time.Date(2026, 10, 1, 20, 0, 0, 0, time.Local)
+	none := func(time.Time) diaryFacts { return diaryFacts{} }
+	for day := 0; day < 7; day++ {
+		pet.WriteDiary(path, start.AddDate(0, 0, day), none, rand.New(lucky{}))
+	}
+	os.WriteFile(path, append(mustRead(t, path), []byte("== Thursday, Oct 8 2026 — Mochi ==\nI was \x1b[2Jfine.\n")...), 0644)
+
+	got := runDiaryCommand(path)
+	if strings.Count(got, "— Mochi") != diaryShown {
+		t.Errorf("Expected the latest %d entries, got:\n%s", diaryShown, got)
+	}
+	if strings.Contains(got, "03:17") || strings.Contains(got, "Oct 2 2026") {
+		t.Errorf("Expected night entries and old ones left out, got:\n%s", got)
+	}
+	if !strings.Contains(got, "stuck together") {
+		t.Errorf("Expected a hint at the hidden pages, got:\n%s", got)
+	}
+	if strings.Contains(got, "\x1b") {
+		t.Errorf("Expected edits to the file stripped of escapes, got %q", got)
+	}
+}
+
+func TestDiaryNightCarriesAClue(t *testing.T) {
+	entry := diaryNight(rand.New(rand.NewSource(1)))
+	lines := strings.Split(entry, "\n")
+	decoded, err := base64.StdEncoding.DecodeString(lines[len(lines)-1])
+	if err != nil {
+		t.Fatalf("Expected the last line encoded, got %q", entry)
+	}
+	found := false
+	for _, message := range argMessages {
+		found = found || string(decoded) == message
+	}
+	if !found {
+		t.Errorf("Expected one of the ARG's messages, got %q", decoded)
+	}
+}
+
+func mustRead(t *testing.T, path string) []byte {
+	t.Helper()
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return data
+}
@@ -0,0 +1,114 @@
+package main
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/tamagotchi/mooc"
+)
+
+// doctorTimeout is how long the network doctor waits for pets to answer
+const doctorTimeout = 2 * time.Second
+
+// meshPort reads the port pets share from TAMAGOTCHI_MESH_PORT. Every
+// machine that should see each other needs the same one.
+func meshPort(getenv func(string) string) (int, error) {
+	raw := getenv("TAMAGOTCHI_MESH_PORT")
+	if raw == "" {
+		return mooc.DiscoveryPort, nil
+	}
+	port, err := strconv.Atoi(raw)
+	if err != nil || port < 1024 || port > 65535 {
+		return mooc.DiscoveryPort, fmt.Errorf("TAMAGOTCHI_MESH_PORT must be a port from 1024 to 65535, got %q", raw)
+	}
+	return port, nil
+}
+
+// firewallHint says where a firewall that blocks the mesh usually lives
+func firewallHint(goos string, port int) string {
+	switch goos {
+	case "windows":
+		return fmt.Sprintf("Windows Defender Firewall may be blocking UDP %d. Allow tamagotchi on private networks in Windows Security › Firewall › Allow an app.", port)
+	case "darwin":
+		return "The macOS firewall may be blocking incoming connections. Allow tamagotchi in System Settings › Network › Firewall › Options."
+	}
+	return fmt.Sprintf("A firewall may be blocking UDP %d. With ufw: sudo ufw allow %d/udp. With firewalld: sudo firewall-cmd --add-port=%d/udp.", port, port, port)
+}
+
+// renderDoctor lists what the network doctor checked, with a hint for
+// anything that isn't right
+func renderDoctor(d mooc.Diagnosis, goos string) string {
+	if !d.Running && d.Failed != nil {
+		return errorPanel(d.Failed)
+	}
+	if !d.Running {
+		return "🩺 The mesh isn't running this session (--lonely, privacy off, or classroom mode), so there's nothing to check."
+	}
+
+	var b strings.Builder
+	b.WriteString("🩺 NETWORK DOCTOR\n\n")
+	check := func(ok bool, line, hint string) {
+		mark := "✓"
+		if !ok {
+			mark = "✗"
+		}
+		b.WriteString(fmt.Sprintf("%s %s\n", mark, line))
+		if !ok && hint != "" {
+			b.WriteString("  → " + hint + "\n")
+		}
+	}
+
+	switch {
+	case d.Bound == d.Port:
+		check(true, fmt.Sprintf("Listening on UDP %d.", d.Port), "")
+	case d.PortHolder != "":
+		check(false, fmt.Sprintf("Another tamagotchi on this machine (%s) holds UDP %d. This pet listens on %d instead and can't hear broadcasts.", plainText(d.PortHolder), d.Port, d.Bound),
+			"Quit the other game, or set TAMAGOTCHI_MESH_PORT to another port here and on every machine this pet should see.")
+	default:
+		check(false, fmt.Sprintf("Something else holds UDP %d. This pet listens on %d instead and can't hear broadcasts.", d.Port, d.Bound),
+			"Set TAMAGOTCHI_MESH_PORT to a free port here and on every machine this pet should see.")
+	}
+
+	if len(d.Interfaces) > 0 {
+		check(true, "Can broadcast on "+strings.Join(d.Interfaces, ", ")+".", "")
+	} else {
+		check(false, "No network interface can broadcast.", "You may be offline, or on a VPN that only routes its own traffic.")
+	}
+
+	if len(d.Broadcast) > 0 {
+		check(true, fmt.Sprintf("%d %s answered a broadcast.", len(d.Broadcast), pets(len(d.Broadcast))), "")
+	} else {
+		hint := firewallHint(goos, d.Port) + " Or nobody else is playing right now."
+		if !d.TCP {
+			hint += " If this network blocks UDP altogether, privacy tcp on lets your pet look for others over TCP."
+		}
+		if len(d.Direct) > 0 {
+			hint = "Pets you've met answer directly, so this network filters broadcasts. Guest Wi-Fi and client isolation do that."
+		}
+		check(false, "Nobody answered a broadcast.", hint)
+	}
+
+	if d.Online > 0 {
+		check(len(d.Direct) == d.Online, fmt.Sprintf("%d of %d %s online answered directly.", len(d.Direct), d.Online, pets(d.Online)),
+			"Some pets stopped answering. They may have just left, or be on a weak link.")
+	}
+	return strings.TrimRight(b.String(), "\n")
+}
+
+// pets is "pet" or "pets"
+func pets(n int) string {
+	if n == 1 {
+		return "pet"
+	}
+	return "pets"
+}
+
+// runNetworkDoctor checks why pets might not be finding each other
+func runNetworkDoctor(goos string) string {
+	if petNetwork == nil {
+		return renderDoctor(mooc.Diagnosis{}, goos)
+	}
+	return renderDoctor(petNetwork.Diagnose(doctorTimeout), goos)
+}
@@ -0,0 +1,83 @@
+package main
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/tamagotchi/mooc"
+)
+
+func TestMeshPort(t *testing.T) {
+	tests := []struct {
+		raw     string
+		want    int
+		wantErr bool
+	}{
+		{"", mooc.DiscoveryPort, false},
+		{"20001", 20001, false},
+		{"80", mooc.DiscoveryPort, true},
+		{"70000", mooc.DiscoveryPort, true},
+		{"lots", mooc.DiscoveryPort, true},
+	}
+	for _, tt := range tests {
+		getenv := func(string) string { return tt.raw }
+		got, err := meshPort(getenv)
+		if got != tt.want || (err != nil) != tt.wantErr {
+			t.Errorf("Expected %d (error %v) for %q, got %d (%v)", tt.want, tt.wantErr, tt.raw, got, err)
+		}
+	}
+}
+
+func TestRenderDoctor(t *testing.T) {
+	tests := []struct {
+		name  string
+		d     mooc.Diagnosis
+		goos  string
+		wants []string
+	}{
+		{"not running", mooc.Diagnosis{}, "linux", []string{"isn't running"}},
+		{
+			"healthy",
+			mooc.Diagnosis{Running: true, Port: 19847, Bound: 19847, Interfaces: []string{"eth0"}, Broadcast: []string{"Mochi", "Bean"}, Direct: []string{"Mochi"}, Online: 1},
+			"linux",
+			[]string{"✓ Listening on UDP 19847", "✓ Can broadcast on eth0", "✓ 2 pets answered", "✓ 1 of 1 pet online"},
+		},
+		{
+			"another game holds the port",
+			mooc.Diagnosis{Running: true, Port: 19847, Bound: 53211, PortHolder: "Mochi\x1b", Interfaces: []string{"eth0"}},
+			"windows",
+			[]string{"✗ Another tamagotchi on this machine (Mochi) holds UDP 19847", "listens on 53211", "Quit the other game", "Windows Defender Firewall"},
+		},
+		{
+			"something else holds the port",
+			mooc.Diagnosis{Running: true, Port: 19847, Bound: 53211},
+			"darwin",
+			[]string{"✗ Something else holds UDP 19847", "✗ No network interface", "macOS firewall"},
+		},
+		{
+			"broadcasts filtered",
+			mooc.Diagnosis{Running: true, Port: 20001, Bound: 20001, Interfaces: []string{"wlan0"}, Direct: []string{"Mochi"}, Online: 2},
+			"linux",
+			[]string{"✗ Nobody answered a broadcast", "this network filters broadcasts", "✗ 1 of 2 pets online"},
+		},
+		{
+			"nobody around",
+			mooc.Diagnosis{Running: true, Port: 20001, Bound: 20001, Interfaces: []string{"wlan0"}},
+			"linux",
+			[]string{"sudo ufw allow 20001/udp", "nobody else is playing", "privacy tcp on"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := renderDoctor(tt.d, tt.goos)
+			for _, want := range tt.wants {
+				if !strings.Contains(got, want) {
+					t.Errorf("Expected %q in:\n%s", want, got)
+				}
+			}
+			if strings.Contains(got, "\x1b") {
+				t.Errorf("Expected names from the mesh cleaned, got %q", got)
+			}
+		})
+	}
+}
@@ -0,0 +1,378 @@
+package main
+
+import (
+	"bytes"
+	"crypto/tls"
+	"encoding/base64"
+	"fmt"
+	"html/template"
+	"image"
+	"image/color"
+	"image/draw"
+	"image/png"
+	"mime"
+	"mime/multipart"
+	"mime/quotedprintable"
+	"net"
+	"net/mail"
+	"net/smtp"
+	"net/textproto"
+	"strings"
+	"time"
+)
+
+const (
+	// digestMinGap is the least time between two digests, whatever happens
+	digestMinGap = 6 * 24 * time.Hour
+
+	// digestRetryDelay spaces out attempts after a failed send
+	digestRetryDelay = 6 * time.Hour
+
+	// digestTimeout bounds a whole conversation with the mail server
+	digestTimeout = 30 * time.Second
+
+	// snapshotCellWidth and snapshotCellHeight are the pixels one character
+	// of the portrait becomes in the emailed snapshot
+	snapshotCellWidth  = 6
+	snapshotCellHeight = 12
+)
+
+// digestConfig is the user's email digest setup
+type digestConfig struct {
+	addr     string // host:port of the mail server
+	auth     smtp.Auth
+	from     string
+	to       []string
+	off      bool      // The player turned the digest off with `digest off`
+	lastTry  time.Time // Last attempt, successful or not
+	sendMail func(addr string, auth smtp.Auth, from string, to []string, msg []byte) error
+}
+
+// newDigestConfig reads the email settings from the environment. It returns
+// nil without error when no mail server is set.
+func newDigestConfig(getenv func(string) string) (*digestConfig, error) {
+	addr := getenv("TAMAGOTCHI_SMTP")
+	if addr == "" || classroomMode {
+		return nil, nil
+	}
+	if host, port, err := net.SplitHostPort(addr); err != nil || host == "" || port == "" {
+		return nil, fmt.Errorf("TAMAGOTCHI_SMTP must look like smtp.example.com:587, got %q", addr)
+	}
+
+	var to []string
+	for _, raw := range strings.Split(getenv("TAMAGOTCHI_DIGEST_TO"), ",") {
+		if strings.TrimSpace(raw) == "" {
+			continue
+		}
+		address, err := mail.ParseAddress(strings.TrimSpace(raw))
+		if err != nil {
+			return nil, fmt.Errorf("TAMAGOTCHI_DIGEST_TO has a bad address %q", raw)
+		}
+		to = append(to, address.Address)
+	}
+	if len(to) == 0 {
+		return nil, fmt.Errorf("TAMAGOTCHI_DIGEST_TO is required: who should the digest go to?")
+	}
+
+	user := getenv("TAMAGOTCHI_SMTP_USER")
+	from := getenv("TAMAGOTCHI_DIGEST_FROM")
+	if from == "" {
+		from = user
+	}
+	if from == "" {
+		from = to[0]
+	}
+	sender, err := mail.ParseAddress(from)
+	if err != nil {
+		return nil, fmt.Errorf("TAMAGOTCHI_DIGEST_FROM has a bad address %q", from)
+	}
+
+	d := &digestConfig{addr: addr, from: sender.Address, to: to, sendMail: sendMail}
+	if user != "" {
+		// PlainAuth won't send the password unless the connection is
+		// encrypted or to localhost
+		host, _, _ := net.SplitHostPort(addr)
+		d.auth = smtp.PlainAuth("", user, getenv("TAMAGOTCHI_SMTP_PASSWORD"), host)
+	}
+	return d, nil
+}
+
+// sendMail is smtp.SendMail with a deadline, so a mail server that stops
+// answering can't hold up the autosave
+func sendMail(addr string, auth smtp.Auth, from string, to []string, msg []byte) error {
+	conn, err := net.DialTimeout("tcp", addr, digestTimeout)
+	if err != nil {
+		return err
+	}
+	conn.SetDeadline(time.Now().Add(digestTimeout))
+	host, _, _ := net.SplitHostPort(addr)
+	client, err := smtp.NewClient(conn, host)
+	if err != nil {
+		conn.Close()
+		return err
+	}
+	defer client.Close()
+	if ok, _ := client.Extension("STARTTLS"); ok {
+		if err := client.StartTLS(&tls.Config{ServerName: host}); err != nil {
+			return err
+		}
+	}
+	if auth != nil {
+		if err := client.Auth(auth); err != nil {
+			return err
+		}
+	}
+	if err := client.Mail(from); err != nil {
+		return err
+	}
+	for _, rcpt := range to {
+		if err := client.Rcpt(rcpt); err != nil {
+			return err
+		}
+	}
+	w, err := client.Data()
+	if err != nil {
+		return err
+	}
+	if _, err := w.Write(msg); err != nil {
+		return err
+	}
+	if err := w.Close(); err != nil {
+		return err
+	}
+	return client.Quit()
+}
+
+// Due reports whether the digest should go out: there's a weekly report
+// that hasn't been mailed, the last digest was long enough ago, and we
+// haven't just failed
+func (d *digestConfig) Due(p *Pet, now time.Time) bool {
+	if d == nil || d.off || p.Endgame == nil || p.Endgame.LastReport == nil {
+		return false
+	}
+	return p.Endgame.LastReport.End.After(p.LastDigestAt) &&
+		now.Sub(p.LastDigestAt) >= digestMinGap && now.Sub(d.lastTry) >= digestRetryDelay
+}
+
+// digestWarnings are what needs the owner's attention, put at the top of
+// the digest
+func digestWarnings(p *Pet, r *WeeklyReport, now time.Time) []string {
+	var warnings []string
+	if p.inHospice() {
+		warnings = append(warnings, fmt.Sprintf("%s is in hospice. It doesn't have long; come and keep it company.", p.Name))
+	} else if reason := p.criticalReason(now); reason != "" {
+		warnings = append(warnings, fmt.Sprintf("%s %s right now.", p.Name, reason))
+	}
+	if r.NearMisses > 0 {
+		warnings = append(warnings, fmt.Sprintf("%s slipped into danger %d times this week.", p.Name, r.NearMisses))
+	}
+	return warnings
+}
+
+// snapshotPNG draws the pet's portrait as a picture: every character is a
+// block of light, dimmer for the thin ones
+func snapshotPNG(art string) ([]byte, error) {
+	lines := strings.Split(art, "\n")
+	width := 0
+	for _, line := range lines {
+		width = max(width, len([]rune(line)))
+	}
+	img := image.NewRGBA(image.Rect(0, 0, (width+2)*snapshotCellWidth, (len(lines)+2)*snapshotCellHeight))
+	background, ink, faint := color.RGBA{0x10, 0x14, 0x18, 0xff}, color.RGBA{0x99, 0xff, 0x99, 0xff}, color.RGBA{0x4c, 0x80, 0x4c, 0xff}
+	draw.Draw(img, img.Bounds(), &image.Uniform{background}, image.Point{}, draw.Src)
+	for row, line := range lines {
+		for col, r := range []rune(line) {
+			if r == ' ' {
+				continue
+			}
+			c := ink
+			if strings.ContainsRune(".,'`-_:~", r) {
+				c = faint
+			}
+			x0, y0 := (col+1)*snapshotCellWidth, (row+1)*snapshotCellHeight
+			for y := y0 + 1; y < y0+snapshotCellHeight-1; y++ {
+				for x := x0; x < x0+snapshotCellWidth-1; x++ {
+					img.Set(x, y, c)
+				}
+			}
+		}
+	}
+	var b bytes.Buffer
+	if err := png.Encode(&b, img); err != nil {
+		return nil, fmt.Errorf("failed to draw the snapshot: %w", err)
+	}
+	return b.Bytes(), nil
+}
+
+// composeDigest builds the email: the weekly report as text and as HTML,
+// with the snapshot inline and the warnings on top
+func composeDigest(p *Pet, r *WeeklyReport, portrait, from string, to []string, now time.Time) ([]byte, error) {
+	warnings := digestWarnings(p, r, now)
+	text := r.Render(p.Name)
+	if len(warnings) > 0 {
+		text = "⚠️ " + strings.Join(warnings, "\n⚠️ ") + "\n\n" + text
+	}
+	page, err := r.renderHTML(p.Name, warnings, template.URL("cid:snapshot"))
+	if err != nil {
+		return nil, fmt.Errorf("failed to render the digest: %w", err)
+	}
+	snapshot, err := snapshotPNG(portrait)
+	if err != nil {
+		return nil, err
+	}
+
+	var body bytes.Buffer
+	alternative := multipart.NewWriter(&body)
+	if err := writeQuotedPart(alternative, "text/plain; charset=utf-8", text); err != nil {
+		return nil, err
+	}
+	relatedBoundary := multipart.NewWriter(nil).Boundary()
+	part, err := alternative.CreatePart(textproto.MIMEHeader{"Content-Type": {`multipart/related; boundary="` + relatedBoundary + `"`}})
+	if err != nil {
+		return nil, err
+	}
+	related := multipart.NewWriter(part)
+	related.SetBoundary(relatedBoundary)
+	if err := writeQuotedPart(related, "text/html; charset=utf-8", page); err != nil {
+		return nil, err
+	}
+	picture, err := related.CreatePart(textproto.MIMEHeader{
+		"Content-Type":              {"image/png"},
+		"Content-Transfer-Encoding": {"base64"},
+		"Content-ID":                {"<snapshot>"},
+		"Content-Disposition":       {`inline; filename="snapshot.png"`},
+	})
+	if err != nil {
+		return nil, err
+	}
+	encoded := base64.StdEncoding.EncodeToString(snapshot)
+	for len(encoded) > 76 {
+		fmt.Fprintf(picture, "%s\r\n", encoded[:76])
+		encoded = encoded[76:]
+	}
+	fmt.Fprintf(picture, "%s\r\n", encoded)
+	related.Close()
+	alternative.Close()
+
+	subject := fmt.Sprintf("📋 %s's week: grade %s", p.Name, r.Grade)
+	if len(warnings) > 0 {
+		subject += " ⚠️ needs you"
+	}
+	var msg bytes.Buffer
+	fmt.Fprintf(&msg, "From: %s\r\n", from)
+	fmt.Fprintf(&msg, "To: %s\r\n", strings.Join(to, ", "))
+	fmt.Fprintf(&msg, "Subject: %s\r\n", mime.QEncoding.Encode("utf-8", subject))
+	fmt.Fprintf(&msg, "Date: %s\r\n", now.Format(time.RFC1123Z))
+	fmt.Fprintf(&msg, "MIME-Version: 1.0\r\n")
+	fmt.Fprintf(&msg, "Content-Type: multipart/alternative; boundary=%q\r\n\r\n", alternative.Boundary())
+	msg.Write(body.Bytes())
+	return msg.Bytes(), nil
+}
+
+// writeQuotedPart adds a quoted-printable part
+func writeQuotedPart(w *multipart.Writer, contentType, content string) error {
+	part, err := w.CreatePart(textproto.MIMEHeader{"Content-Type": {contentType}, "Content-Transfer-Encoding": {"quoted-printable"}})
+	if err != nil {
+		return err
+	}
+	qp := quotedprintable.NewWriter(part)
+	if _, err := qp.Write([]byte(content)); err != nil {
+		return err
+	}
+	return qp.Close()
+}
+
+// Send mails the latest weekly report
+func (d *digestConfig) Send(p *Pet, portrait string, now time.Time) (string, error) {
+	msg, err := d.compose(p, portrait, now)
+	if err != nil {
+		return "", err
+	}
+	if err := d.deliver(msg); err != nil {
+		return "", err
+	}
+	p.LastDigestAt = now
+	return fmt.Sprintf("📧 Weekly digest sent to %s.", strings.Join(d.to, ", ")), nil
+}
+
+// compose writes the mail for the latest weekly report
+func (d *digestConfig) compose(p *Pet, portrait string, now time.Time) ([]byte, error) {
+	d.lastTry = now
+	return composeDigest(p, p.Endgame.LastReport, portrait, d.from, d.to, now)
+}
+
+// deliver hands a composed digest to the mail server
+func (d *digestConfig) deliver(msg []byte) error {
+	if err := d.sendMail(d.addr, d.auth, d.from, d.to, msg); err != nil {
+		return fmt.Errorf("failed to send the digest: %w", err)
+	}
+	return nil
+}
+
+// job is an automatic digest: composed now, mailed once the pet is let
+// go. Failures retry after digestRetryDelay.
+func (d *digestConfig) job(p *Pet, portrait string, now time.Time) petJob {
+	msg, err := d.compose(p, portrait, now)
+	if err != nil {
+		return nil
+	}
+	return func() func(*Pet) {
+		if d.deliver(msg) != nil {
+			return nil
+		}
+		return func(p *Pet) { p.LastDigestAt = now }
+	}
+}
+
+// Status describes the digest setup for `digest`
+func (d *digestConfig) Status(p *Pet) string {
+	if d == nil {
+		return "📧 The email digest is off. Set TAMAGOTCHI_SMTP (host:port) and TAMAGOTCHI_DIGEST_TO to get the weekly report by email."
+	}
+	if d.off {
+		return "📧 The email digest is switched off. Turn it back on with: digest on"
+	}
+	last := "never"
+	if !p.LastDigestAt.IsZero() {
+		last = p.LastDigestAt.Format("Mon Jan 2 3:04pm")
+	}
+	return fmt.Sprintf("📧 The weekly report is emailed to %s once it's written. Last digest: %s. Turn it off with: digest off", strings.Join(d.to, ", "), last)
+}
+
+// runDigestCommand handles `digest [now|on|off]`
+func runDigestCommand(d *digestConfig, ui *uiConfig, pet *Pet, args string) string {
+	switch strings.TrimSpace(args) {
+	case "", "status":
+		return d.Status(pet)
+	case "now":
+		if d == nil || d.off {
+			return d.Status(pet)
+		}
+		if pet.Endgame == nil || pet.Endgame.LastReport == nil {
+			return "📧 There's no weekly report to send yet."
+		}
+		now := time.Now()
+		if wait := digestMinGap - now.Sub(pet.LastDigestAt); wait > 0 {
+			return fmt.Sprintf("📧 A digest went out %s ago. The next can go in %s; nobody likes a pet that spams.", formatDuration(now.Sub(pet.LastDigestAt).Truncate(time.Hour)), formatDuration(wait.Truncate(time.Hour)))
+		}
+		message, err := d.Send(pet, sitePortrait(ui, pet), now)
+		if err != nil {
+			return "❌ " + err.Error()
+		}
+		return message
+	case "on", "off":
+		settings := loadPrivacy(privacyFile)
+		settings.NoDigest = strings.TrimSpace(args) == "off"
+		if err := settings.save(privacyFile); err != nil {
+			return fmt.Sprintf("❌ Couldn't save privacy settings: %v", err)
+		}
+		if d != nil {
+			d.off = settings.NoDigest
+		}
+		if settings.NoDigest {
+			return "📧 Email digest off. No more mail from your pet."
+		}
+		return d.Status(pet)
+	}
+	return "❓ Usage: digest [now|on|off]"
+}
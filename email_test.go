@@ -0,0 +1,160 @@
+package main
+
+import (
+	"bytes"
+	"encoding/base64"
+	"errors"
+	"image/png"
+	"io"
+	"mime"
+	"mime/multipart"
+	"mime/quotedprintable"
+	"net/mail"
+	"net/smtp"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestNewDigestConfig(t *testing.T) {
+	tests := []struct {
+		name    string
+		env     map[string]string
+		enabled bool
+		wantErr bool
+	}{
+		{"not configured", map[string]string{}, false, false},
+		{"configured", map[string]string{"TAMAGOTCHI_SMTP": "smtp.example.com:587", "TAMAGOTCHI_DIGEST_TO": "me@example.com"}, true, false},
+		{"no port", map[string]string{"TAMAGOTCHI_SMTP": "smtp.example.com", "TAMAGOTCHI_DIGEST_TO": "me@example.com"}, false, true},
+		{"nobody to send to", map[string]string{"TAMAGOTCHI_SMTP": "smtp.example.com:587"}, false, true},
+		{"header injection", map[string]string{"TAMAGOTCHI_SMTP": "smtp.example.com:587", "TAMAGOTCHI_DIGEST_TO": "me@example.com\r\nBcc: you@example.com"}, false, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			d, err := newDigestConfig(func(key string) string { return tt.env[key] })
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Expected error %v, got %v", tt.wantErr, err)
+			}
+			if (d != nil) != tt.enabled {
+				t.Errorf("Expected enabled %v, got %+v", tt.enabled, d)
+			}
+		})
+	}
+}
+
+// testDigest is a digest that records what it would have sent
+func testDigest(sent *[]byte, fail error) *digestConfig {
+	return &digestConfig{addr: "smtp.example.com:587", from: "pet@example.com", to: []string{"me@example.com"},
+		sendMail: func(addr string, auth smtp.Auth, from string, to []string, msg []byte) error {
+			if fail != nil {
+				return fail
+			}
+			*sent = msg
+			return nil
+		}}
+}
+
+func TestDigestRateLimit(t *testing.T) {
+	now := time.Now()
+	pet := NewPet("Mochi")
+	var sent []byte
+	d := testDigest(&sent, errors.New("connection refused"))
+
+	if d.Due(pet, now) {
+		t.Errorf("Expected nothing due without a report")
+	}
+	pet.Endgame.LastReport = &WeeklyReport{End: now, Grade: "B"}
+	if !d.Due(pet, now) {
+		t.Fatalf("Expected a new report due")
+	}
+	if _, err := d.Send(pet, "(o_o)", now); err == nil {
+		t.Fatalf("Expected the failure reported")
+	}
+	if d.Due(pet, now.Add(time.Hour)) || !d.Due(pet, now.Add(digestRetryDelay)) {
+		t.Errorf("Expected a failed send retried only after %s", digestRetryDelay)
+	}
+
+	d = testDigest(&sent, nil)
+	if _, err := d.Send(pet, "(o_o)", now); err != nil || len(sent) == 0 {
+		t.Fatalf("Expected the digest sent, got %v", err)
+	}
+	pet.Endgame.LastReport = &WeeklyReport{End: now.Add(time.Hour), Grade: "A"}
+	if d.Due(pet, now.Add(2*time.Hour)) {
+		t.Errorf("Expected no second digest within %s", digestMinGap)
+	}
+	if !d.Due(pet, now.Add(digestMinGap)) {
+		t.Errorf("Expected the next digest once the gap has passed")
+	}
+	d.off = true
+	if d.Due(pet, now.Add(digestMinGap)) {
+		t.Errorf("Expected nothing sent once the digest is switched off")
+	}
+}
+
+func TestComposeDigest(t *testing.T) {
+	now := time.Date(2026, 10, 16, 9, 0, 0, 0, time.UTC)
+	pet := NewPet("Mochi")
+	pet.Stage = Adult
+	pet.IsSick = true
+	report := &WeeklyReport{Start: now.Add(-reportPeriod), End: now, Grade: "C", NearMisses: 2, Moods: map[string]int{}}
+
+	raw, err := composeDigest(pet, report, " /\\_/\\\n( o.o )", "pet@example.com", []string{"me@example.com"}, now)
+	if err != nil {
+		t.Fatalf("Expected a digest, got %v", err)
+	}
+	msg, err := mail.ReadMessage(bytes.NewReader(raw))
+	if err != nil {
+		t.Fatalf("Expected a valid email, got %v", err)
+	}
+	subject, _ := new(mime.WordDecoder).DecodeHeader(msg.Header.Get("Subject"))
+	if !strings.Contains(subject, "grade C") || !strings.Contains(subject, "needs you") {
+		t.Errorf("Expected the grade and a warning in the subject, got %q", subject)
+	}
+
+	parts := map[string][]byte{}
+	var walk func(r io.Reader, contentType string)
+	walk = func(r io.Reader, contentType string) {
+		_, params, _ := mime.ParseMediaType(contentType)
+		reader := multipart.NewReader(r, params["boundary"])
+		for {
+			part, err := reader.NextRawPart()
+			if err != nil {
+				return
+			}
+			kind, _, _ := mime.ParseMediaType(part.Header.Get("Content-Type"))
+			if strings.HasPrefix(kind, "multipart/") {
+				walk(part, part.Header.Get("Content-Type"))
+				continue
+			}
+			body, _ := io.ReadAll(part)
+			switch part.Header.Get("Content-Transfer-Encoding") {
+			case "quoted-printable":
+				body, _ = io.ReadAll(quotedprintable.NewReader(bytes.NewReader(body)))
+			case "base64":
+				body, _ = io.ReadAll(base64.NewDecoder(base64.StdEncoding, bytes.NewReader(body)))
+			}
+			parts[kind] = body
+		}
+	}
+	walk(msg.Body, msg.Header.Get("Content-Type"))
+
+	if text := string(parts["text/plain"]); !strings.Contains(text, "Mochi is sick right now.") || !strings.Contains(text, "WEEKLY CARE REPORT") {
+		t.Errorf("Expected the warnings and the report as text, got %q", text)
+	}
+	if page := string(parts["text/html"]); !strings.Contains(page, `src="cid:snapshot"`) || !strings.Contains(page, "danger 2 times") {
+		t.Errorf("Expected the snapshot and warnings in the HTML, got %q", page)
+	}
+	if _, err := png.Decode(bytes.NewReader(parts["image/png"])); err != nil {
+		t.Errorf("Expected an inline PNG snapshot, got %v", err)
+	}
+}
+
+func TestRenderHTMLLeavesDigestOut(t *testing.T) {
+	page, err := (&WeeklyReport{Grade: "A"}).RenderHTML("Mochi")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.Contains(page, "<img") || strings.Contains(page, "warnings") {
+		t.Errorf("Expected the exported report without a snapshot or warnings")
+	}
+}
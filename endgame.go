@@ -8,8 +8,13 @@ import (
 	"math/rand"
 	"strings"
 	"time"
+
+	"github.com/tamagotchi/arg"
 )
 
+// countdownPeriod is how long the mysterious countdown runs before it resets
+const countdownPeriod = 7 * 24 * time.Hour
+
 // EndgameState holds all the absurd endgame progression data
 type EndgameState struct {
 	// Prestige System
@@ -18,7 +23,7 @@ type EndgameState struct {
 	TimesPrestiged   int    `json:"times_prestiged"`
 
 	// Arbitrary Currency
-	TamaCoins      int       `json:"tama_coins"` // Can't be spent
+	TamaCoins      int       `json:"tama_coins"` // Spendable only in the shop, at absurd prices
 	LastLoginBonus time.Time `json:"last_login_bonus"`
 	LoginStreak    int       `json:"login_streak"`
 
@@ -27,8 +32,13 @@ type EndgameState struct {
 	AchievementProgress  map[string]int `json:"achievement_progress"`
 
 	// Gacha/Inventory
-	InvisibleAccessories []string `json:"invisible_accessories"`
-	GachaPulls           int      `json:"gacha_pulls"`
+	InvisibleAccessories []string       `json:"invisible_accessories"`
+	GachaPulls           int            `json:"gacha_pulls"`
+	ShopPurchases        map[string]int `json:"shop_purchases,omitempty"`     // Items bought, by ID
+	Casino               *CasinoState   `json:"casino,omitempty"`             // Every bet, against what the odds said
+	GachaPity            int            `json:"gacha_pity"`                   // Pulls since the last Rare or better
+	GachaBannerWeek      string         `json:"gacha_banner_week,omitempty"`  // Last banner we told the mesh about
+	GachaBannerHeard     string         `json:"gacha_banner_heard,omitempty"` // Last banner the mesh told us about
 
 	// Guild
 	GuildName   string    `json:"guild_name"`
@@ -36,27 +46,54 @@ type EndgameState struct {
 	GuildJoined time.Time `json:"guild_joined"`
 
 	// Quests
-	ActiveQuest     *Quest `json:"active_quest"`
-	QuestsCompleted int    `json:"quests_completed"`
+	ActiveQuest     *Quest   `json:"active_quest"`
+	QuestsCompleted int      `json:"quests_completed"`
+	FinishedQuest   *Quest   `json:"finished_quest,omitempty"` // Last shared quest, until its partners finish too
+	QuestMemories   []string `json:"quest_memories,omitempty"` // Quests finished together
 
 	// ARG
-	ARGProgress     int       `json:"arg_progress"`
-	DiscoveredCodes []string  `json:"discovered_codes"`
-	CountdownStart  time.Time `json:"countdown_start"`
+	ARGProgress     int        `json:"arg_progress"`
+	DiscoveredCodes []string   `json:"discovered_codes"`
+	CountdownStart  time.Time  `json:"countdown_start"`
+	ARG             *arg.State `json:"arg,omitempty"` // Hidden artifacts and what's been solved
+
+	// Community events calendar, from the maintainers' signed events file
+	CommunityEvents []CommunityEvent `json:"community_events,omitempty"`
+	EventsFetchedAt time.Time        `json:"events_fetched_at,omitempty"`
+	FiredEvents     []string         `json:"fired_events,omitempty"` // IDs already run on this pet
 
 	// Social
 	FriendCode string `json:"friend_code"`
 	ShareCount int    `json:"share_count"`
 
 	// Meta Stats
-	TotalPlayTime     time.Duration `json:"total_play_time"`
-	SessionStart      time.Time     `json:"-"`
-	CommandsEntered   int           `json:"commands_entered"`
-	TimesCheckedStats int           `json:"times_checked_stats"`
+	TotalPlayTime     time.Duration  `json:"total_play_time"`
+	SessionStart      time.Time      `json:"-"`
+	CommandsEntered   int            `json:"commands_entered"`
+	TimesCheckedStats int            `json:"times_checked_stats"`
+	TotalPausedTime   time.Duration  `json:"total_paused_time"`
+	PauseCount        int            `json:"pause_count"`
+	Rollbacks         int            `json:"rollbacks,omitempty"`       // Checkpoint restores; the pet remembers each one
+	CommandCounts     map[string]int `json:"command_counts,omitempty"`  // Runs per command, for history remarks
+	LastMetaCheck     *MetaSnapshot  `json:"last_meta_check,omitempty"` // What the meta screen saw last time
+
+	// Observed Human Patterns
+	HourHistogram [24]int   `json:"hour_histogram"`  // Visits per hour of day
+	LastVisitHour time.Time `json:"last_visit_hour"` // Clock hour of the last recorded visit
 
 	// New Game+
 	NewGamePlusLevel int  `json:"new_game_plus_level"`
 	SpeakInRiddles   bool `json:"speak_in_riddles"`
+
+	// Weekly care reports
+	NextReportDue time.Time     `json:"next_report_due"`
+	LastReport    *WeeklyReport `json:"last_report,omitempty"`
+	SessionNotes  []SessionNote `json:"session_notes,omitempty"` // Session summaries waiting for the next report
+
+	// Focus sessions
+	FocusSessionsCompleted int `json:"focus_sessions_completed"`
+	FocusSessionsAbandoned int `json:"focus_sessions_abandoned"`
+	FocusMinutes           int `json:"focus_minutes"`
 }
 
 // Quest represents a procedurally generated quest
@@ -68,6 +105,19 @@ type Quest struct {
 	Progress    int       `json:"progress"`
 	StartTime   time.Time `json:"start_time"`
 	Reward      string    `json:"reward"`
+
+	// Shared quests
+	ID       string         `json:"id,omitempty"`       // Set once the quest is shared
+	HostID   string         `json:"host_id,omitempty"`  // The host's PetID, when we joined someone else's
+	Partners []QuestPartner `json:"partners,omitempty"` // Other pets on the quest
+}
+
+// QuestPartner is another pet on a shared quest
+type QuestPartner struct {
+	PetID    string `json:"pet_id"`
+	Name     string `json:"name"`
+	Progress int    `json:"progress"`
+	Done     bool   `json:"done,omitempty"`
 }
 
 // Achievement represents an achievement (most are impossible)
@@ -107,13 +157,16 @@ var guildSuffixes = []string{
 	"Abandoned Hobbies", "Missed Connections", "Vague Intentions",
 }
 
-// Quest templates
-var questTemplates = []struct {
+// questTemplate is a quest before it's started; Desc holds one %d for the target
+type questTemplate struct {
 	Name   string
 	Desc   string
 	Type   string
 	Target int
-}{
+}
+
+// Built-in quest templates; content packs can add to or replace them
+var questTemplates = []questTemplate{
 	{"The Waiting Game", "Wait for %d seconds", "wait", 60},
 	{"Patience is a Virtue", "Do nothing for %d minutes", "wait", 120},
 	{"The Long Pause", "Stare at the screen for %d seconds", "wait", 30},
@@ -134,12 +187,23 @@ var allAchievements = []Achievement{
 	{ID: "enlightened", Name: "Enlightened One", Description: "Achieve enlightenment", Secret: false, Impossible: false},
 	{ID: "guild_join", Name: "Guild Member", Description: "Join a guild", Secret: false, Impossible: false},
 	{ID: "quest_complete", Name: "Quest Champion", Description: "Complete a quest", Secret: false, Impossible: false},
+	{ID: "focus_1", Name: "Deep Work (Allegedly)", Description: "Complete a focus session", Secret: false, Impossible: false},
+	{ID: "focus_5", Name: "Pomodoro Enjoyer", Description: "Complete 5 focus sessions", Secret: false, Impossible: false},
+	{ID: "focus_25", Name: "Tomato Farmer", Description: "Complete 25 focus sessions", Secret: false, Impossible: false},
+	{ID: "perfect_week", Name: "Perfect Week", Description: "Earn an A+ weekly care report, visiting every day", Secret: false, Impossible: false},
+	{ID: phobiologistAchievement, Name: "Phobiologist", Description: "Document every fear across generations", Secret: false, Impossible: false},
+	{ID: "gamble_first", Name: "Beginner's Luck", Description: "Place a bet at the casino", Secret: false, Impossible: false},
+	{ID: "gamble_100", Name: "The House Thanks You", Description: "Wager 100 TamaCoins in total", Secret: false, Impossible: false},
+	{ID: "gamble_limit", Name: "Know Your Limit", Description: "Set a daily loss limit at the casino", Secret: false, Impossible: false},
 
 	// Secret achievements
 	{ID: "debug_mode", Name: "???", Description: "Discover debug mode", Secret: true, Impossible: false},
 	{ID: "konami", Name: "Old School", Description: "Enter the code", Secret: true, Impossible: false},
 	{ID: "pet_17", Name: "The Number", Description: "Pet your pet exactly 17 times", Secret: true, Impossible: false},
 	{ID: "touch_grass", Name: "Touched Grass", Description: "Received the touch grass reminder", Secret: true, Impossible: false},
+	{ID: "whisperer", Name: "Whisperer", Description: "Say something your pet was waiting to hear", Secret: true, Impossible: false},
+	{ID: chorusAchievement, Name: "Chorus", Description: "Read what three pets carry together", Secret: true, Impossible: false},
+	{ID: rabbitHoleAchievement, Name: "Down the Rabbit Hole", Description: "Solve every hidden artifact", Secret: true, Impossible: false},
 
 	// Impossible achievements
 	{ID: "impossible_1", Name: "Divide by Zero", Description: "Divide your TamaCoins by zero", Secret: false, Impossible: true},
@@ -179,6 +243,32 @@ func NewEndgameState() *EndgameState {
 	}
 }
 
+// migrate fills in what older saves wrote before these fields existed, so
+// a loaded endgame looks like a new one wherever the save is silent
+func (e *EndgameState) migrate(now time.Time) {
+	if e.PrestigeEggColor == "" {
+		e.PrestigeEggColor = "Classic White"
+	}
+	if e.UnlockedAchievements == nil {
+		e.UnlockedAchievements = make([]string, 0)
+	}
+	if e.AchievementProgress == nil {
+		e.AchievementProgress = make(map[string]int)
+	}
+	if e.InvisibleAccessories == nil {
+		e.InvisibleAccessories = make([]string, 0)
+	}
+	if e.DiscoveredCodes == nil {
+		e.DiscoveredCodes = make([]string, 0)
+	}
+	if e.FriendCode == "" {
+		e.FriendCode = generateFriendCode() // Also seeds the hidden artifacts
+	}
+	if e.CountdownStart.IsZero() {
+		e.CountdownStart = now
+	}
+}
+
 // generateFriendCode creates a 47-character friend code
 func generateFriendCode() string {
 	data := fmt.Sprintf("%d-%d", time.Now().UnixNano(), rand.Int63())
@@ -222,8 +312,8 @@ func (e *EndgameState) CheckDailyBonus() (bool, string) {
 ║                                    ║
 ║ Login Streak: %d days              ║
 ║                                    ║
-║ Note: TamaCoins cannot be spent.   ║
-║ They simply exist, like you.       ║
+║ Note: the shop takes TamaCoins.    ║
+║ You can't afford anything good.    ║
 ╚════════════════════════════════════╝
 `, e.TamaCoins, e.LoginStreak)
 }
@@ -271,7 +361,7 @@ func (e *EndgameState) GenerateQuest() string {
 	}
 
 	randomSource := rand.New(rand.NewSource(time.Now().UnixNano()))
-	template := questTemplates[randomSource.Intn(len(questTemplates))]
+	template := activeContent.quests.pick(randomSource)
 
 	e.ActiveQuest = &Quest{
 		Name:        template.Name,
@@ -319,7 +409,8 @@ func (e *EndgameState) UpdateQuest() string {
 ║ "%s" finished!
 ║                                    ║
 ║ Reward: +1 TamaCoin                ║
-║ (Still can't spend them)           ║
+║ (The shop has nothing you can      ║
+║ afford that you'd want)            ║
 ║                                    ║
 ║ Total Quests Completed: %d         ║
 ╚════════════════════════════════════╝
@@ -329,51 +420,11 @@ func (e *EndgameState) UpdateQuest() string {
 	return ""
 }
 
-// PullGacha does a gacha pull for invisible accessories
+// PullGacha does a gacha pull for invisible accessories on this week's banner
 func (e *EndgameState) PullGacha() string {
-	e.GachaPulls++
-
 	randomSource := rand.New(rand.NewSource(time.Now().UnixNano()))
-	accessory := invisibleAccessories[randomSource.Intn(len(invisibleAccessories))]
-
-	// Check for duplicate
-	for _, owned := range e.InvisibleAccessories {
-		if owned == accessory {
-			return fmt.Sprintf(`
-╔════════════════════════════════════╗
-║      🎰 GACHA RESULT 🎰           ║
-╠════════════════════════════════════╣
-║ You got: %s
-║                                    ║
-║ ⚠️ DUPLICATE!                      ║
-║ You already own this item.         ║
-║ You cannot see it twice.           ║
-║                                    ║
-║ Total Pulls: %d                    ║
-╚════════════════════════════════════╝
-`, accessory, e.GachaPulls)
-		}
-	}
-
-	e.InvisibleAccessories = append(e.InvisibleAccessories, accessory)
-
-	return fmt.Sprintf(`
-╔════════════════════════════════════╗
-║      🎰 GACHA RESULT 🎰           ║
-╠════════════════════════════════════╣
-║ ✨ NEW ITEM! ✨                    ║
-║                                    ║
-║ You got: %s
-║                                    ║
-║ Note: This item is invisible.      ║
-║ Your pet is now wearing it.        ║
-║ You cannot see it.                 ║
-║ But it's there. Trust us.          ║
-║                                    ║
-║ Total Pulls: %d                    ║
-║ Collection: %d/%d                  ║
-╚════════════════════════════════════╝
-`, accessory, e.GachaPulls, len(e.InvisibleAccessories), len(invisibleAccessories))
+	result, _, _ := e.pullGacha(currentBanner(time.Now()), randomSource)
+	return result
 }
 
 // StartBattle initiates a pet battle where nothing happens
@@ -454,14 +505,17 @@ func (e *EndgameState) AttemptTrade() string {
 
 // GetCountdownStatus returns the status of the mysterious countdown
 func (e *EndgameState) GetCountdownStatus() string {
+	if next := e.NextEvent(time.Now()); next != nil {
+		return e.eventCountdown(next, time.Until(next.At))
+	}
+
 	// Countdown to... nothing. It resets when it hits zero.
 	elapsed := time.Since(e.CountdownStart)
-	totalDuration := 7 * 24 * time.Hour // 7 days
-	remaining := totalDuration - elapsed
+	remaining := countdownPeriod - elapsed
 
 	if remaining <= 0 {
 		e.CountdownStart = time.Now()
-		remaining = totalDuration
+		remaining = countdownPeriod
 	}
 
 	days := int(remaining.Hours()) / 24
@@ -487,6 +541,40 @@ func (e *EndgameState) GetCountdownStatus() string {
 `, days, hours, minutes, seconds)
 }
 
+// eventCountdown counts down to a scheduled community event
+func (e *EndgameState) eventCountdown(event *CommunityEvent, remaining time.Duration) string {
+	days := int(remaining.Hours()) / 24
+	hours := int(remaining.Hours()) % 24
+	minutes := int(remaining.Minutes()) % 60
+	seconds := int(remaining.Seconds()) % 60
+
+	later := "Nothing else is scheduled. Yet."
+	upcoming := 0
+	for _, other := range e.CommunityEvents {
+		if other.At.After(event.At) && !e.hasFired(other.ID) {
+			upcoming++
+		}
+	}
+	if upcoming > 0 {
+		later = fmt.Sprintf("%d more on the calendar.", upcoming)
+	}
+
+	return fmt.Sprintf(`
+╔════════════════════════════════════╗
+║      ⏰ THE COUNTDOWN ⏰           ║
+╠════════════════════════════════════╣
+║                                    ║
+║   %dd %02dh %02dm %02ds
+║                                    ║
+║ Next: %s
+║ %s
+║                                    ║
+║ Every pet will feel it at once.    ║
+║ %s
+╚════════════════════════════════════╝
+`, days, hours, minutes, seconds, event.Name, event.At.Local().Format("Mon Jan 2 15:04 MST"), later)
+}
+
 // GetARGClue generates a cryptic ARG clue
 func (e *EndgameState) GetARGClue() string {
 	randomSource := rand.New(rand.NewSource(time.Now().UnixNano()))
@@ -496,14 +584,7 @@ func (e *EndgameState) GetARGClue() string {
 	lon := -74.0 + randomSource.Float64()*10
 
 	// Generate base64 message
-	messages := []string{
-		"THE MESH REMEMBERS",
-		"SEVENTEEN IS THE KEY",
-		"LOOK BEHIND THE SAVE FILE",
-		"THE VOID SPEAKS TRUTH",
-		"NOT ALL EGGS ARE EQUAL",
-	}
-	message := messages[randomSource.Intn(len(messages))]
+	message := argMessages[randomSource.Intn(len(argMessages))]
 	encoded := base64.StdEncoding.EncodeToString([]byte(message))
 
 	e.ARGProgress++
@@ -637,8 +718,10 @@ func ShowFakeAd() string {
 `
 }
 
-// GetMetaStats returns absurd meta statistics
-func (e *EndgameState) GetMetaStats() string {
+// GetMetaStats returns absurd meta statistics, and a digest of what changed
+// since they were last checked. metSince lists friends met on the mesh
+// since a time; nil leaves friends out.
+func (e *EndgameState) GetMetaStats(current MetaSnapshot, metSince func(time.Time) []string) string {
 	e.TimesCheckedStats++
 
 	sessionDuration := time.Since(e.SessionStart)
@@ -659,6 +742,8 @@ func (e *EndgameState) GetMetaStats() string {
 ║ • This Session: %s
 ║ • Total Playtime: %dh %dm
 ║ • Time Wasted: %.1f%%
+║ • Time Paused: %s (%d pauses)
+║   Verdict: %s
 ║                                    ║
 ║ Engagement Metrics:                ║
 ║ • Commands Entered: %d
@@ -674,18 +759,28 @@ func (e *EndgameState) GetMetaStats() string {
 ║ Existential Status:                ║
 ║ • Meaning Found: No                ║
 ║ • Regrets: Calculating...          ║
+║ • Timelines Abandoned: %d
 ║                                    ║
+║ Observed Human Patterns:           ║
+%s║                                    ║
+║ Since Last Check:                  ║
+%s║                                    ║
 ╚════════════════════════════════════╝
 `,
 		formatDuration(sessionDuration),
 		hours, minutes,
 		wastedPercentage,
+		formatDuration(e.TotalPausedTime), e.PauseCount,
+		pauseVerdict(e.TotalPausedTime),
 		e.CommandsEntered,
 		e.TimesCheckedStats,
 		len(e.UnlockedAchievements), len(allAchievements),
 		e.QuestsCompleted,
 		e.GachaPulls,
 		e.TamaCoins,
+		e.Rollbacks,
+		e.observedPatterns(),
+		e.metaDigest(current, metSince),
 	)
 }
 
@@ -796,7 +891,9 @@ func (e *EndgameState) ShowAchievements() string {
 			name = "???"
 			desc = "Secret achievement"
 		}
-		if ach.Impossible {
+		if ach.Impossible && unlocked[ach.ID] {
+			desc += " (IMPOSSIBLE, and yet)"
+		} else if ach.Impossible {
 			desc += " (IMPOSSIBLE)"
 		}
 
@@ -1,9 +1,14 @@
 package main
 
 import (
+	"os"
+	"path/filepath"
+	"slices"
 	"strings"
 	"testing"
 	"time"
+
+	"github.com/tamagotchi/arg"
 )
 
 func TestNewEndgameState(t *testing.T) {
@@ -339,7 +344,7 @@ func TestGetMetaStats(t *testing.T) {
 	state := NewEndgameState()
 	state.SessionStart = time.Now().Add(-1 * time.Hour) // Simulate 1 hour session
 
-	result := state.GetMetaStats()
+	result := state.GetMetaStats(MetaSnapshot{}, nil)
 	if !strings.Contains(result, "META STATISTICS") {
 		t.Errorf("Expected meta stats header, got: %s", result)
 	}
@@ -513,3 +518,67 @@ func TestCountdownReset(t *testing.T) {
 		}
 	}
 }
+
+func TestEndgameSurvivesSaving(t *testing.T) {
+	pet := NewPet("Keeper")
+	pet.SaveFilePath = filepath.Join(t.TempDir(), saveFile)
+	pet.Endgame.TamaCoins = 1234
+	pet.Endgame.GuildName = "The Null Pointers"
+	pet.Endgame.UnlockAchievement("first_feed")
+	pet.Endgame.CountCommand("feed")
+	pet.Endgame.argGame().Plant(arg.PuzzleCache)
+	if err := pet.Save(); err != nil {
+		t.Fatal(err)
+	}
+
+	loaded, err := LoadPet(pet.SaveFilePath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	got, want := loaded.Endgame, pet.Endgame
+	if got.TamaCoins != want.TamaCoins || got.GuildName != want.GuildName || got.FriendCode != want.FriendCode {
+		t.Errorf("Expected the endgame back as saved, got %d coins, guild %q, code %q", got.TamaCoins, got.GuildName, got.FriendCode)
+	}
+	if !slices.Contains(got.UnlockedAchievements, "first_feed") || got.CommandCounts["feed"] != 1 {
+		t.Errorf("Expected achievements and command counts kept, got %v and %v", got.UnlockedAchievements, got.CommandCounts)
+	}
+	if got.ARG == nil || !got.ARG.IsPlanted(arg.PuzzleCache) {
+		t.Errorf("Expected the hidden artifacts kept, got %+v", got.ARG)
+	}
+	if got.SessionStart.IsZero() {
+		t.Errorf("Expected a new session started on load")
+	}
+}
+
+func TestLoadPetMigratesEndgame(t *testing.T) {
+	tests := []struct {
+		name    string
+		endgame string
+		coins   int
+	}{
+		{"before the endgame", ``, 0},
+		{"before friend codes", `, "endgame": {"tama_coins": 40, "unlocked_achievements": null}`, 40},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), saveFile)
+			save := `{"name": "Elder", "health": 80, "stage": 4, "birth_time": "2026-01-01T00:00:00Z", "last_update_time": "2026-01-01T00:00:00Z"` + tt.endgame + `}`
+			os.WriteFile(path, []byte(save), 0644)
+
+			pet, err := LoadPet(path)
+			if err != nil {
+				t.Fatal(err)
+			}
+			e := pet.Endgame
+			if e.TamaCoins != tt.coins {
+				t.Errorf("Expected %d coins kept, got %d", tt.coins, e.TamaCoins)
+			}
+			if len(e.FriendCode) != 55 || e.PrestigeEggColor != "Classic White" || e.CountdownStart.IsZero() {
+				t.Errorf("Expected missing fields filled in, got code %q, color %q, countdown %v", e.FriendCode, e.PrestigeEggColor, e.CountdownStart)
+			}
+			if e.UnlockedAchievements == nil || e.AchievementProgress == nil {
+				t.Errorf("Expected empty collections rather than nil")
+			}
+		})
+	}
+}
@@ -0,0 +1,138 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/tamagotchi/mooc"
+)
+
+// SaveCorruptError says a save file is there but can't be read back as a pet
+type SaveCorruptError struct {
+	Path string
+	Err  error
+}
+
+func (e *SaveCorruptError) Error() string {
+	return fmt.Sprintf("save file %s is damaged: %v", e.Path, e.Err)
+}
+
+func (e *SaveCorruptError) Unwrap() error {
+	return e.Err
+}
+
+// PermissionDeniedError says the game wasn't allowed to read or write a file
+type PermissionDeniedError struct {
+	Path string
+	Err  error
+}
+
+func (e *PermissionDeniedError) Error() string {
+	return fmt.Sprintf("not allowed to use %s: %v", e.Path, e.Err)
+}
+
+func (e *PermissionDeniedError) Unwrap() error {
+	return e.Err
+}
+
+// fileError marks a file error the player has to fix with permissions,
+// naming the file the system named if it did
+func fileError(path string, err error) error {
+	if !errors.Is(err, fs.ErrPermission) {
+		return err
+	}
+	var pathErr *fs.PathError
+	if errors.As(err, &pathErr) {
+		path, err = pathErr.Path, pathErr.Err
+	}
+	return &PermissionDeniedError{Path: path, Err: err}
+}
+
+// setAsideCorruptSave moves an unreadable save out of the way, so a new
+// pet doesn't save over what might still be rescued, and returns where it went
+func setAsideCorruptSave(path string, now time.Time) (string, error) {
+	aside := fmt.Sprintf("%s.corrupt-%s", path, now.Format("20060102-150405"))
+	if err := os.Rename(path, aside); err != nil {
+		return "", fileError(path, err)
+	}
+	return aside, nil
+}
+
+// errorPanel explains an error the player can do something about, with
+// what to try. Anything else is shown as it is.
+func errorPanel(err error) string {
+	var (
+		corrupt     *SaveCorruptError
+		denied      *PermissionDeniedError
+		unavailable *mooc.UnavailableError
+		title, what string
+		cause       error
+		suggestions []string
+	)
+	switch {
+	case errors.As(err, &corrupt):
+		title, what = "💔 SAVE FILE DAMAGED", fmt.Sprintf("%s couldn't be read as a pet.", corrupt.Path)
+		cause = corrupt.Err
+		suggestions = []string{
+			"If you back up off-machine: tamagotchi backup restore",
+			fmt.Sprintf("A checkpoint in %s holds a whole pet: checkpoint restore <label>", filepath.Join(filepath.Dir(corrupt.Path), checkpointDirName)),
+			"If you edited the file by hand, look for a missing comma or brace",
+		}
+	case errors.As(err, &denied):
+		title, what = "🔒 PERMISSION DENIED", fmt.Sprintf("The game isn't allowed to use %s.", denied.Path)
+		cause = denied.Err
+		suggestions = []string{
+			"Check who owns the file: ls -l " + denied.Path,
+			"Run the game from a folder you can write to",
+			"If a sync tool or antivirus holds the file, close it and try again",
+		}
+	case errors.As(err, &unavailable):
+		title, what = "📡 NETWORK UNAVAILABLE", "Your pet couldn't join the local network. Everything else works without it."
+		cause = unavailable.Err
+		suggestions = []string{
+			"Another program may hold the port: set TAMAGOTCHI_MESH_PORT to a free one",
+			"Check your Wi-Fi or cable, then restart the game",
+			"Firewalls and VPNs can block it; try another network to check",
+		}
+	default:
+		return "❌ " + err.Error()
+	}
+
+	var b strings.Builder
+	fmt.Fprintf(&b, `
+╔════════════════════════════════════╗
+║ %s
+╠════════════════════════════════════╣
+║ %s
+║ (%v)
+║                                    ║
+║ What you can try:
+`, title, what, cause)
+	for _, suggestion := range suggestions {
+		fmt.Fprintf(&b, "║ • %s\n", suggestion)
+	}
+	b.WriteString("╚════════════════════════════════════╝\n")
+	return b.String()
+}
+
+// lastSaveError is the save failure already shown, so a full disk doesn't
+// bring the panel back after every command
+var lastSaveError string
+
+// reportSave shows a failed save the first time it fails that way
+func reportSave(err error, ui *uiConfig) {
+	if err == nil {
+		lastSaveError = ""
+		return
+	}
+	if err.Error() == lastSaveError {
+		return
+	}
+	lastSaveError = err.Error()
+	fmt.Println(ui.text(errorPanel(err)))
+}
@@ -0,0 +1,93 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/tamagotchi/mooc"
+)
+
+func TestLoadPetReportsDamage(t *testing.T) {
+	dir := t.TempDir()
+	damaged := filepath.Join(dir, "damaged.json")
+	os.WriteFile(damaged, []byte(`{"name": "Mochi", "hunger": `), 0644)
+
+	_, err := LoadPet(damaged)
+	var corrupt *SaveCorruptError
+	if !errors.As(err, &corrupt) || corrupt.Path != damaged {
+		t.Errorf("Expected a damaged save, got %v", err)
+	}
+
+	_, err = LoadPet(filepath.Join(dir, "missing.json"))
+	if err == nil || errors.As(err, &corrupt) {
+		t.Errorf("Expected a missing save not to count as damage, got %v", err)
+	}
+}
+
+func TestFileError(t *testing.T) {
+	denied := fileError("save.json", fmt.Errorf("wrapped: %w", &fs.PathError{Op: "open", Path: "journal.json", Err: fs.ErrPermission}))
+	var permission *PermissionDeniedError
+	if !errors.As(denied, &permission) || permission.Path != "journal.json" {
+		t.Errorf("Expected the file the system named, got %v", denied)
+	}
+
+	other := errors.New("disk full")
+	if got := fileError("save.json", other); got != other {
+		t.Errorf("Expected other errors left alone, got %v", got)
+	}
+}
+
+func TestErrorPanel(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		want []string
+	}{
+		{"damaged save", &SaveCorruptError{Path: "tamagotchi_save.json", Err: errors.New("unexpected end of JSON input")},
+			[]string{"SAVE FILE DAMAGED", "unexpected end of JSON input", "backup restore", "checkpoint restore"}},
+		{"wrapped permission", fmt.Errorf("failed to write save file: %w", &PermissionDeniedError{Path: "tamagotchi_save.json", Err: fs.ErrPermission}),
+			[]string{"PERMISSION DENIED", "ls -l tamagotchi_save.json"}},
+		{"no network", &mooc.UnavailableError{Err: errors.New("address in use")},
+			[]string{"NETWORK UNAVAILABLE", "address in use", "TAMAGOTCHI_MESH_PORT"}},
+		{"anything else", errors.New("boom"), []string{"❌ boom"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := errorPanel(tt.err)
+			for _, want := range tt.want {
+				if !strings.Contains(got, want) {
+					t.Errorf("Expected %q in:\n%s", want, got)
+				}
+			}
+		})
+	}
+}
+
+func TestSetAsideCorruptSave(t *testing.T) {
+	path := filepath.Join(t.TempDir(), saveFile)
+	os.WriteFile(path, []byte("{"), 0644)
+
+	aside, err := setAsideCorruptSave(path, time.Date(2026, 10, 16, 9, 30, 0, 0, time.UTC))
+	if err != nil || !strings.HasSuffix(aside, ".corrupt-20261016-093000") {
+		t.Fatalf("Expected the save renamed, got %q, %v", aside, err)
+	}
+	if _, err := os.Stat(path); !os.IsNotExist(err) {
+		t.Errorf("Expected the damaged save moved out of the way")
+	}
+	if data, _ := os.ReadFile(aside); string(data) != "{" {
+		t.Errorf("Expected the damaged save kept as it was, got %q", data)
+	}
+}
+
+func TestRenderDoctorShowsWhyTheMeshIsDown(t *testing.T) {
+	got := renderDoctor(mooc.Diagnosis{Failed: &mooc.UnavailableError{Err: errors.New("no interfaces")}}, "linux")
+	if !strings.Contains(got, "NETWORK UNAVAILABLE") || !strings.Contains(got, "no interfaces") {
+		t.Errorf("Expected the failure explained, got:\n%s", got)
+	}
+}
@@ -0,0 +1,261 @@
+package main
+
+import (
+	"bytes"
+	"crypto/ed25519"
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"sort"
+	"time"
+)
+
+const (
+	// eventsRefreshInterval is how often the events file is fetched again
+	eventsRefreshInterval = 6 * time.Hour
+
+	// eventsRetryDelay spaces out fetches after a failure
+	eventsRetryDelay = 15 * time.Minute
+
+	// eventsTimeout bounds a single fetch
+	eventsTimeout = 15 * time.Second
+
+	// maxEventsFile is the largest events file we'll read
+	maxEventsFile = 1 << 20
+
+	// eventGrace is how late an event may still fire; a pet that slept
+	// through anything older simply missed it
+	eventGrace = 24 * time.Hour
+)
+
+// CommunityEvent is a global event the maintainers have scheduled. Every pet
+// runs it at the same moment.
+type CommunityEvent struct {
+	ID      string            `json:"id"`
+	Name    string            `json:"name"`
+	At      time.Time         `json:"at"`
+	Type    string            `json:"type"` // A consensusHandlers key, e.g. feast
+	Payload map[string]string `json:"payload,omitempty"`
+}
+
+// signedEvents is the events file: the schedule, and an Ed25519 signature
+// over the exact bytes of it
+type signedEvents struct {
+	Events    json.RawMessage `json:"events"`
+	Signature string          `json:"signature"` // Base64
+}
+
+// parseSignedEvents checks an events file's signature and reads the schedule
+func parseSignedEvents(data []byte, key ed25519.PublicKey) ([]CommunityEvent, error) {
+	var file signedEvents
+	if err := json.Unmarshal(data, &file); err != nil {
+		return nil, fmt.Errorf("invalid events file: %w", err)
+	}
+	sig, err := base64.StdEncoding.DecodeString(file.Signature)
+	if err != nil || !ed25519.Verify(key, file.Events, sig) {
+		return nil, fmt.Errorf("events file signature doesn't match")
+	}
+
+	var events []CommunityEvent
+	if err := json.Unmarshal(file.Events, &events); err != nil {
+		return nil, fmt.Errorf("invalid events: %w", err)
+	}
+	seen := make(map[string]bool)
+	for _, event := range events {
+		if event.ID == "" || event.Name == "" || event.At.IsZero() || event.Type == "" {
+			return nil, fmt.Errorf("event %q needs an id, name, time, and type", event.ID)
+		}
+		if seen[event.ID] {
+			return nil, fmt.Errorf("event %q is listed twice", event.ID)
+		}
+		seen[event.ID] = true
+	}
+	return events, nil
+}
+
+// signEvents wraps a schedule in a signed events file. The schedule is
+// compacted first and the file isn't indented, since the signature covers
+// the exact bytes.
+func signEvents(events []byte, key ed25519.PrivateKey) ([]byte, error) {
+	var compact bytes.Buffer
+	if err := json.Compact(&compact, events); err != nil {
+		return nil, fmt.Errorf("invalid events: %w", err)
+	}
+	// Built by hand: json.Marshal would re-escape the schedule and break the signature
+	sig := base64.StdEncoding.EncodeToString(ed25519.Sign(key, compact.Bytes()))
+	signed := []byte(fmt.Sprintf(`{"events":%s,"signature":%q}`, compact.Bytes(), sig))
+
+	// Refuse to publish anything clients would reject
+	if _, err := parseSignedEvents(signed, key.Public().(ed25519.PublicKey)); err != nil {
+		return nil, err
+	}
+	return signed, nil
+}
+
+// eventFeed fetches the signed events file the maintainers publish
+type eventFeed struct {
+	url     string
+	key     ed25519.PublicKey
+	client  *http.Client
+	lastTry time.Time
+}
+
+// newEventFeed reads the feed settings, returning nil when no feed is set.
+// Only HTTPS is accepted, and only files signed by the configured key.
+func newEventFeed(getenv func(string) string) (*eventFeed, error) {
+	raw := getenv("TAMAGOTCHI_EVENTS_URL")
+	if raw == "" || classroomMode {
+		return nil, nil
+	}
+	u, err := url.Parse(raw)
+	if err != nil || u.Scheme != "https" || u.Host == "" {
+		return nil, fmt.Errorf("TAMAGOTCHI_EVENTS_URL must be an https:// URL, got %q", raw)
+	}
+	key, err := base64.StdEncoding.DecodeString(getenv("TAMAGOTCHI_EVENTS_KEY"))
+	if err != nil || len(key) != ed25519.PublicKeySize {
+		return nil, fmt.Errorf("TAMAGOTCHI_EVENTS_KEY must be a base64 Ed25519 public key")
+	}
+	return &eventFeed{url: raw, key: key, client: &http.Client{Timeout: eventsTimeout}}, nil
+}
+
+// Due reports whether the schedule is stale and we haven't just failed
+func (f *eventFeed) Due(e *EndgameState, now time.Time) bool {
+	return f != nil && e != nil && now.Sub(e.EventsFetchedAt) >= eventsRefreshInterval && now.Sub(f.lastTry) >= eventsRetryDelay
+}
+
+// Refresh fetches the events file and replaces the pet's schedule with it
+func (f *eventFeed) Refresh(e *EndgameState, now time.Time) error {
+	f.lastTry = now
+	events, err := f.fetch()
+	if err != nil {
+		return err
+	}
+	e.ScheduleEvents(events, now)
+	return nil
+}
+
+// fetch downloads and checks the events file
+func (f *eventFeed) fetch() ([]CommunityEvent, error) {
+	resp, err := f.client.Get(f.url)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch events: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("failed to fetch events: %s", resp.Status)
+	}
+	data, err := io.ReadAll(io.LimitReader(resp.Body, maxEventsFile))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read events: %w", err)
+	}
+	return parseSignedEvents(data, f.key)
+}
+
+// job is an automatic refresh: the schedule is fetched once the pet is
+// let go. Failures retry after eventsRetryDelay.
+func (f *eventFeed) job(now time.Time) petJob {
+	f.lastTry = now
+	return func() func(*Pet) {
+		events, err := f.fetch()
+		if err != nil {
+			return nil
+		}
+		return func(p *Pet) {
+			if p.Endgame != nil {
+				p.Endgame.ScheduleEvents(events, now)
+			}
+		}
+	}
+}
+
+// ScheduleEvents replaces the calendar. The published file is the whole
+// truth, so an event the maintainers pull is cancelled here too.
+func (e *EndgameState) ScheduleEvents(events []CommunityEvent, now time.Time) {
+	sorted := append([]CommunityEvent(nil), events...)
+	sort.SliceStable(sorted, func(i, j int) bool { return sorted[i].At.Before(sorted[j].At) })
+
+	scheduled := make(map[string]bool)
+	for _, event := range sorted {
+		scheduled[event.ID] = true
+	}
+	var fired []string
+	for _, id := range e.FiredEvents {
+		if scheduled[id] {
+			fired = append(fired, id)
+		}
+	}
+
+	e.CommunityEvents = sorted
+	e.FiredEvents = fired
+	e.EventsFetchedAt = now
+}
+
+// hasFired reports whether an event already ran for this pet
+func (e *EndgameState) hasFired(id string) bool {
+	for _, fired := range e.FiredEvents {
+		if fired == id {
+			return true
+		}
+	}
+	return false
+}
+
+// NextEvent returns the soonest event that hasn't happened yet
+func (e *EndgameState) NextEvent(now time.Time) *CommunityEvent {
+	for i, event := range e.CommunityEvents {
+		if event.At.After(now) && !e.hasFired(event.ID) {
+			return &e.CommunityEvents[i]
+		}
+	}
+	return nil
+}
+
+// printSigningKeys makes a new Ed25519 key pair and prints it as settings
+func printSigningKeys(publicVar, privateVar string) error {
+	pub, priv, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		return err
+	}
+	fmt.Printf("%s=%s\n", publicVar, base64.StdEncoding.EncodeToString(pub))
+	fmt.Printf("%s=%s\n", privateVar, base64.StdEncoding.EncodeToString(priv))
+	fmt.Println("Publish the first, keep the second secret.")
+	return nil
+}
+
+// runEventsCommand handles `tamagotchi events keygen` and
+// `tamagotchi events sign <file>`, the maintainers' side of the feed
+func runEventsCommand(args []string) error {
+	usage := fmt.Errorf("usage: tamagotchi events keygen | tamagotchi events sign <events.json>")
+	if len(args) == 0 {
+		return usage
+	}
+
+	switch args[0] {
+	case "keygen":
+		return printSigningKeys("TAMAGOTCHI_EVENTS_KEY", "TAMAGOTCHI_EVENTS_SIGNING_KEY")
+	case "sign":
+		if len(args) < 2 {
+			return usage
+		}
+		key, err := base64.StdEncoding.DecodeString(os.Getenv("TAMAGOTCHI_EVENTS_SIGNING_KEY"))
+		if err != nil || len(key) != ed25519.PrivateKeySize {
+			return fmt.Errorf("TAMAGOTCHI_EVENTS_SIGNING_KEY must be a base64 Ed25519 private key (see events keygen)")
+		}
+		events, err := os.ReadFile(args[1])
+		if err != nil {
+			return err
+		}
+		signed, err := signEvents(events, key)
+		if err != nil {
+			return err
+		}
+		fmt.Println(string(signed))
+		return nil
+	}
+	return usage
+}
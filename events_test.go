@@ -0,0 +1,204 @@
+package main
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"encoding/base64"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+const testSchedule = `[
+  {"id": "feast-1", "name": "The Great Feast", "at": "2030-01-01T12:00:00Z", "type": "feast"},
+  {"id": "gift-1", "name": "Winter Gift", "at": "2030-01-02T12:00:00Z", "type": "gift", "payload": {"item": "a scarf"}}
+]`
+
+func testEventsKey(t *testing.T) (ed25519.PublicKey, ed25519.PrivateKey) {
+	t.Helper()
+	pub, priv, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatalf("Expected a key, got %v", err)
+	}
+	return pub, priv
+}
+
+func TestSignedEventsRoundTrip(t *testing.T) {
+	pub, priv := testEventsKey(t)
+	signed, err := signEvents([]byte(testSchedule), priv)
+	if err != nil {
+		t.Fatalf("Expected signing to succeed, got %v", err)
+	}
+
+	events, err := parseSignedEvents(signed, pub)
+	if err != nil {
+		t.Fatalf("Expected signed file to verify, got %v", err)
+	}
+	if len(events) != 2 || events[1].Payload["item"] != "a scarf" {
+		t.Errorf("Expected both events with payloads, got %+v", events)
+	}
+
+	otherPub, _ := testEventsKey(t)
+	if _, err := parseSignedEvents(signed, otherPub); err == nil {
+		t.Errorf("Expected a file signed by another key to be rejected")
+	}
+	tampered := []byte(strings.Replace(string(signed), "feast-1", "feast-2", 1))
+	if _, err := parseSignedEvents(tampered, pub); err == nil {
+		t.Errorf("Expected a tampered file to be rejected")
+	}
+}
+
+func TestSignEventsRejectsBadSchedules(t *testing.T) {
+	_, priv := testEventsKey(t)
+	tests := []struct {
+		name     string
+		schedule string
+	}{
+		{"not json", `[{`},
+		{"missing type", `[{"id": "a", "name": "A", "at": "2030-01-01T00:00:00Z"}]`},
+		{"missing time", `[{"id": "a", "name": "A", "type": "feast"}]`},
+		{"duplicate id", `[{"id": "a", "name": "A", "at": "2030-01-01T00:00:00Z", "type": "feast"}, {"id": "a", "name": "B", "at": "2030-01-02T00:00:00Z", "type": "feast"}]`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := signEvents([]byte(tt.schedule), priv); err == nil {
+				t.Errorf("Expected schedule to be rejected")
+			}
+		})
+	}
+}
+
+func TestNewEventFeed(t *testing.T) {
+	pub, _ := testEventsKey(t)
+	key := base64.StdEncoding.EncodeToString(pub)
+	tests := []struct {
+		name    string
+		env     map[string]string
+		wantNil bool
+		wantErr bool
+	}{
+		{"disabled", map[string]string{}, true, false},
+		{"plain http", map[string]string{"TAMAGOTCHI_EVENTS_URL": "http://events.example/events.json", "TAMAGOTCHI_EVENTS_KEY": key}, true, true},
+		{"no host", map[string]string{"TAMAGOTCHI_EVENTS_URL": "https://", "TAMAGOTCHI_EVENTS_KEY": key}, true, true},
+		{"no key", map[string]string{"TAMAGOTCHI_EVENTS_URL": "https://events.example/events.json"}, true, true},
+		{"short key", map[string]string{"TAMAGOTCHI_EVENTS_URL": "https://events.example/events.json", "TAMAGOTCHI_EVENTS_KEY": "c2hvcnQ="}, true, true},
+		{"configured", map[string]string{"TAMAGOTCHI_EVENTS_URL": "https://events.example/events.json", "TAMAGOTCHI_EVENTS_KEY": key}, false, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			feed, err := newEventFeed(func(key string) string { return tt.env[key] })
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Expected error %v, got %v", tt.wantErr, err)
+			}
+			if (feed == nil) != tt.wantNil {
+				t.Errorf("Expected nil feed %v, got %v", tt.wantNil, feed)
+			}
+		})
+	}
+}
+
+func TestEventFeedRefreshAndDue(t *testing.T) {
+	pub, priv := testEventsKey(t)
+	signed, err := signEvents([]byte(testSchedule), priv)
+	if err != nil {
+		t.Fatalf("Expected signing to succeed, got %v", err)
+	}
+	body := signed
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write(body)
+	}))
+	defer server.Close()
+
+	feed := &eventFeed{url: server.URL, key: pub, client: server.Client()}
+	e := NewEndgameState()
+	now := time.Date(2029, 12, 1, 0, 0, 0, 0, time.UTC)
+
+	if !feed.Due(e, now) {
+		t.Errorf("Expected a feed that was never fetched to be due")
+	}
+	if err := feed.Refresh(e, now); err != nil {
+		t.Fatalf("Expected refresh to succeed, got %v", err)
+	}
+	if len(e.CommunityEvents) != 2 || !e.EventsFetchedAt.Equal(now) {
+		t.Errorf("Expected two events fetched now, got %d at %v", len(e.CommunityEvents), e.EventsFetchedAt)
+	}
+	if feed.Due(e, now.Add(time.Hour)) {
+		t.Errorf("Expected no refresh due an hour later")
+	}
+	if !feed.Due(e, now.Add(eventsRefreshInterval)) {
+		t.Errorf("Expected a refresh due after %v", eventsRefreshInterval)
+	}
+
+	// A bad file keeps the old schedule and waits before retrying
+	body = []byte(strings.Replace(string(signed), "Great", "Grate", 1))
+	later := now.Add(eventsRefreshInterval)
+	if err := feed.Refresh(e, later); err == nil {
+		t.Errorf("Expected a tampered file to fail")
+	}
+	if len(e.CommunityEvents) != 2 {
+		t.Errorf("Expected the old schedule to survive, got %d events", len(e.CommunityEvents))
+	}
+	if feed.Due(e, later.Add(time.Minute)) {
+		t.Errorf("Expected no retry within %v", eventsRetryDelay)
+	}
+
+	var none *eventFeed
+	if none.Due(e, now) {
+		t.Errorf("Expected a missing feed never to be due")
+	}
+}
+
+func TestScheduleEventsAndNextEvent(t *testing.T) {
+	at := time.Date(2030, 1, 1, 12, 0, 0, 0, time.UTC)
+	e := NewEndgameState()
+	e.FiredEvents = []string{"old", "first"}
+	e.ScheduleEvents([]CommunityEvent{
+		{ID: "second", Name: "Second", At: at.Add(time.Hour), Type: "feast"},
+		{ID: "first", Name: "First", At: at, Type: "feast"},
+	}, at.Add(-time.Hour))
+
+	if e.CommunityEvents[0].ID != "first" {
+		t.Errorf("Expected events sorted by time, got %s first", e.CommunityEvents[0].ID)
+	}
+	if len(e.FiredEvents) != 1 || e.FiredEvents[0] != "first" {
+		t.Errorf("Expected cancelled events pruned from FiredEvents, got %v", e.FiredEvents)
+	}
+
+	tests := []struct {
+		name string
+		now  time.Time
+		want string
+	}{
+		{"first already fired", at.Add(-time.Hour), "second"},
+		{"second upcoming", at.Add(30 * time.Minute), "second"},
+		{"all past", at.Add(2 * time.Hour), ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			next := e.NextEvent(tt.now)
+			got := ""
+			if next != nil {
+				got = next.ID
+			}
+			if got != tt.want {
+				t.Errorf("Expected next event %q, got %q", tt.want, got)
+			}
+		})
+	}
+}
+
+func TestCountdownShowsNextEvent(t *testing.T) {
+	e := NewEndgameState()
+	e.ScheduleEvents([]CommunityEvent{
+		{ID: "feast", Name: "The Great Feast", At: time.Now().Add(48 * time.Hour), Type: "feast"},
+	}, time.Now())
+
+	status := e.GetCountdownStatus()
+	if !strings.Contains(status, "The Great Feast") {
+		t.Errorf("Expected countdown to name the next event, got %q", status)
+	}
+}
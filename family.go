@@ -0,0 +1,145 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"slices"
+	"strings"
+	"time"
+)
+
+// familyDotFile is where `family dot` writes the tree for Graphviz
+const familyDotFile = "tamagotchi_family.dot"
+
+// familyMember is one generation of the tree: an ancestor, or the pet
+// itself at the end
+type familyMember struct {
+	Ancestor
+	Now       bool     // The living pet, not an ancestor
+	Inherited []string // What its parent passed to it
+	From      string   // Who passed it
+}
+
+// familyTree is the pet's line, oldest first, ending with the pet
+func familyTree(p *Pet, now time.Time) []familyMember {
+	var tree []familyMember
+	for _, a := range p.Lineage {
+		tree = append(tree, familyMember{Ancestor: a})
+	}
+	self := p.asAncestor(now)
+	self.Passed, self.LastWords = nil, ""
+	tree = append(tree, familyMember{Ancestor: self, Now: true})
+	for i := 1; i < len(tree); i++ {
+		tree[i].Inherited, tree[i].From = tree[i-1].Passed, tree[i-1].Name
+	}
+	return tree
+}
+
+// annotatedTraits lists a member's traits, marking the ones inherited
+func (m familyMember) annotatedTraits() string {
+	traits := make([]string, 0, len(m.Traits))
+	for _, trait := range m.Traits {
+		if slices.Contains(m.Inherited, trait) {
+			trait += " (from " + m.From + ")"
+		}
+		traits = append(traits, trait)
+	}
+	return strings.Join(traits, ", ")
+}
+
+// summary is the member's life in one line
+func (m familyMember) summary() string {
+	var life string
+	switch {
+	case m.Now:
+		life = fmt.Sprintf("%s, %d hours old, hatched %s", m.Stage, m.AgeHours, m.Born.Format("Jan 2 2006"))
+	case m.Stage == Dead.String():
+		life = fmt.Sprintf("Died at %d hours, %s to %s", m.AgeHours, m.Born.Format("Jan 2 2006"), m.Until.Format("Jan 2 2006"))
+	default:
+		life = fmt.Sprintf("Reset as a %s at %d hours, %s to %s", m.Stage, m.AgeHours, m.Born.Format("Jan 2 2006"), m.Until.Format("Jan 2 2006"))
+	}
+	if m.CareScore > 0 {
+		life += fmt.Sprintf(", care %d/100", m.CareScore)
+	}
+	return life
+}
+
+// renderFamily draws the tree as text, one generation under the next
+func renderFamily(p *Pet, now time.Time) string {
+	tree := familyTree(p, now)
+	var b strings.Builder
+	fmt.Fprintf(&b, "🌳 %s'S FAMILY TREE\n", strings.ToUpper(p.Name))
+	if len(tree) == 1 {
+		fmt.Fprintf(&b, "\n%s is the first of its line. Every pet you reset joins the tree.\n", p.Name)
+	}
+	for i, m := range tree {
+		branch, trunk := "├─", "│ "
+		if i == 0 {
+			branch = "┌─"
+		}
+		if m.Now {
+			branch, trunk = "└─", "  "
+		}
+		name := m.Name
+		if m.Stage == Dead.String() {
+			name += " 🕯️"
+		}
+		fmt.Fprintf(&b, "\n%s %s — generation %d\n", branch, name, i+1)
+		fmt.Fprintf(&b, "%s   %s\n", trunk, m.summary())
+		if len(m.Traits) > 0 {
+			fmt.Fprintf(&b, "%s   Traits: %s\n", trunk, m.annotatedTraits())
+		}
+		if len(m.Inherited) > 0 {
+			fmt.Fprintf(&b, "%s   🧬 Inherited from %s: %s\n", trunk, m.From, strings.Join(m.Inherited, ", "))
+		}
+		if m.LastWords != "" {
+			fmt.Fprintf(&b, "%s   “%s”\n", trunk, m.LastWords)
+		}
+	}
+	return strings.TrimRight(b.String(), "\n")
+}
+
+// dotQuote quotes a label for Graphviz
+func dotQuote(s string) string {
+	return `"` + strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`).Replace(s) + `"`
+}
+
+// familyDot is the tree as a Graphviz digraph, each parent pointing at its
+// child with what it passed on
+func familyDot(p *Pet, now time.Time) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "digraph family {\n  label=%s;\n  rankdir=TB;\n  node [shape=box, fontname=\"monospace\"];\n", dotQuote(p.Name+"'s family tree"))
+	tree := familyTree(p, now)
+	for i, m := range tree {
+		label := m.Name + "\n" + m.summary()
+		if len(m.Traits) > 0 {
+			label += "\nTraits: " + m.annotatedTraits()
+		}
+		style := ""
+		if m.Now {
+			style = ", style=bold"
+		} else if m.Stage == Dead.String() {
+			style = ", style=dashed"
+		}
+		fmt.Fprintf(&b, "  g%d [label=%s%s];\n", i, dotQuote(label), style)
+		if i > 0 {
+			fmt.Fprintf(&b, "  g%d -> g%d [label=%s];\n", i-1, i, dotQuote(strings.Join(m.Inherited, "\n")))
+		}
+	}
+	b.WriteString("}\n")
+	return b.String()
+}
+
+// runFamilyCommand handles `family [dot]`
+func runFamilyCommand(pet *Pet, args string) string {
+	switch strings.TrimSpace(args) {
+	case "":
+		return renderFamily(pet, time.Now())
+	case "dot":
+		if err := os.WriteFile(familyDotFile, []byte(familyDot(pet, time.Now())), 0644); err != nil {
+			return fmt.Sprintf("❌ Failed to write %s: %v", familyDotFile, err)
+		}
+		return fmt.Sprintf("🌳 Family tree written to %s. Draw it with: dot -Tpng %s -o family.png", familyDotFile, familyDotFile)
+	}
+	return "❓ Usage: family [dot]"
+}
@@ -0,0 +1,87 @@
+package main
+
+import (
+	"os"
+	"strings"
+	"testing"
+	"time"
+)
+
+// testLineage is two generations before the pet
+func testLineage(now time.Time) []Ancestor {
+	return []Ancestor{
+		{Name: "Grandpa", Born: now.Add(-900 * time.Hour), Until: now.Add(-500 * time.Hour), Stage: "Dead", AgeHours: 400, CareScore: 72,
+			Traits: []string{"brave"}, LastWords: "Feed the next one.", Passed: []string{"wary", "a love of fish"}},
+		{Name: "Mum", Born: now.Add(-500 * time.Hour), Until: now.Add(-200 * time.Hour), Stage: "Teen", AgeHours: 300, Traits: []string{"wary", "dreamy"}},
+	}
+}
+
+func TestFamilyTree(t *testing.T) {
+	now := time.Now()
+	pet := NewPet("Mochi")
+	pet.Lineage = testLineage(now)
+
+	tree := familyTree(pet, now)
+	if len(tree) != 3 || !tree[2].Now || tree[2].Name != "Mochi" {
+		t.Fatalf("Expected two ancestors and the pet, got %+v", tree)
+	}
+	if tree[1].From != "Grandpa" || len(tree[1].Inherited) != 2 {
+		t.Errorf("Expected Mum to inherit from Grandpa, got %+v", tree[1])
+	}
+	if got := tree[1].annotatedTraits(); got != "wary (from Grandpa), dreamy" {
+		t.Errorf("Expected the inherited trait marked, got %q", got)
+	}
+	if len(tree[2].Inherited) != 0 {
+		t.Errorf("Expected nothing passed down by a reset pet, got %v", tree[2].Inherited)
+	}
+}
+
+func TestRenderFamily(t *testing.T) {
+	now := time.Now()
+	pet := NewPet("Mochi")
+	if got := renderFamily(pet, now); !strings.Contains(got, "first of its line") {
+		t.Errorf("Expected a lone pet to be told it's the first, got %q", got)
+	}
+
+	pet.Lineage = testLineage(now)
+	got := renderFamily(pet, now)
+	for _, want := range []string{
+		"┌─ Grandpa 🕯️ — generation 1", "Died at 400 hours", "care 72/100", "Feed the next one.",
+		"├─ Mum — generation 2", "Reset as a Teen at 300 hours", "🧬 Inherited from Grandpa: wary, a love of fish",
+		"└─ Mochi — generation 3",
+	} {
+		if !strings.Contains(got, want) {
+			t.Errorf("Expected %q in the tree, got:\n%s", want, got)
+		}
+	}
+}
+
+func TestFamilyDot(t *testing.T) {
+	now := time.Now()
+	pet := NewPet(`Mochi "the Great"`)
+	pet.Lineage = testLineage(now)
+
+	dot := familyDot(pet, now)
+	for _, want := range []string{"digraph family {", `Mochi \"the Great\"`, "g0 -> g1", `label="wary\na love of fish"`, "g2 [label=", "style=bold", "style=dashed"} {
+		if !strings.Contains(dot, want) {
+			t.Errorf("Expected %q in the DOT file, got:\n%s", want, dot)
+		}
+	}
+	if !strings.HasSuffix(dot, "}\n") {
+		t.Errorf("Expected the digraph closed")
+	}
+}
+
+func TestRunFamilyCommand(t *testing.T) {
+	t.Chdir(t.TempDir())
+	pet := NewPet("Mochi")
+	if got := runFamilyCommand(pet, "nonsense"); !strings.Contains(got, "Usage") {
+		t.Errorf("Expected usage, got %q", got)
+	}
+	if got := runFamilyCommand(pet, "dot"); !strings.Contains(got, familyDotFile) {
+		t.Errorf("Expected the DOT file named, got %q", got)
+	}
+	if _, err := os.Stat(familyDotFile); err != nil {
+		t.Errorf("Expected the DOT file written, got %v", err)
+	}
+}
@@ -0,0 +1,284 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+)
+
+// fearBookFile is the fear encyclopedia. It sits beside the save rather
+// than in it, so what one generation learned is there for the next.
+const fearBookFile = "tamagotchi_fearbook.json"
+
+// phobiologistAchievement is earned by documenting every fear
+const phobiologistAchievement = "phobiologist"
+
+// fearPage is the lore for one fear, and what seems to help
+type fearPage struct {
+	Lore string
+	Cure string
+}
+
+// fearLore is the encyclopedia's text for the fears the game knows about.
+// Fears from content packs get a page with just their description.
+var fearLore = map[string]fearPage{
+	"Qphobia": {
+		Lore: "First recorded in a pet whose owner typed 'quit' and then didn't. The letter has been under suspicion ever since.",
+		Cure: "None known. Some owners have had luck with 'exit'.",
+	},
+	"Tuesdread": {
+		Lore: "Nobody knows what happened on that first Tuesday. The pet won't say. On Tuesdays, it won't say anything.",
+		Cure: "Wait until Wednesday.",
+	},
+	"Semicolonophobia": {
+		Lore: "An inherited fear, passed down from pets that lived through a language where forgetting one broke everything.",
+		Cure: "Write Python near it. Slowly.",
+	},
+	"Palindromophobia": {
+		Lore: "Words that read the same both ways suggest time could too. The pet would rather it didn't.",
+		Cure: "Reassure it that racecars only go one way.",
+	},
+	"Evenophobia": {
+		Lore: "Even numbers can be split cleanly in two. The pet finds this implies things about itself.",
+		Cure: "Odd comfort: it is only afraid half of the time.",
+	},
+	"Uppercasophobia": {
+		Lore: "Capital letters sound like shouting. The pet has never been shouted at, and would like to keep it that way.",
+		Cure: "Speak softly. Caps lock off.",
+	},
+	"Blankophobia": {
+		Lore: "Pressing Enter on nothing is a question with no words. The pet always assumes the worst answer.",
+		Cure: "Say anything at all.",
+	},
+	"Threephobia": {
+		Lore: "Things come in threes, and the pet has been counting. It is not looking forward to the third.",
+		Cure: "Skip from two to four and hope it doesn't notice.",
+	},
+	"Forcepushophobia": {
+		Lore: "Never born with it, only learned. Somewhere, history was rewritten, and the pet remembers the version that was lost.",
+		Cure: "Protect your main branch.",
+	},
+	"Disconnectophobia": {
+		Lore: "Pets on the mesh say goodbye when they go. After enough of them never came back, the word itself started to hurt.",
+		Cure: "Say 'see you later' instead. Mean it.",
+	},
+	"Timeoutophobia": {
+		Lore: "A heartbeat every few seconds, and then none. The pet now counts the seconds between everyone's.",
+		Cure: "Stay online. Keep your heartbeat steady.",
+	},
+	"Obituaphobia": {
+		Lore: "Last words travel the mesh long after the pet who said them. This one has heard too many.",
+		Cure: "Time, and the quiet of a network with nobody dying on it.",
+	},
+	"Contagiophobia": {
+		Lore: "Moods spread between pets like a cold. After enough sad ones, the pet started flinching at the router.",
+		Cure: "Make it happy enough that there's nothing to catch.",
+	},
+	"Broadcastophobia": {
+		Lore: "Every 45 seconds, someone shares how they feel. Lately it has never been good news.",
+		Cure: "Go offline for a while. privacy off helps.",
+	},
+	"Lagophobia": {
+		Lore: "Sad moods arrive late, relayed by pets who didn't feel them. The pet can never tell whose sadness it is.",
+		Cure: "None yet. The mesh is working on it.",
+	},
+}
+
+// learnedFears are fears a pet can only pick up, never hatch with
+var learnedFears = append([]Fear{forcePushFear}, traumaFears()...)
+
+// fearRecord is everything known about one fear across generations
+type fearRecord struct {
+	FirstSeen time.Time      `json:"first_seen"`
+	Pets      map[string]int `json:"pets"` // Each pet that had it, and how often it was triggered
+}
+
+// triggered is how often every pet that had the fear was frightened by it
+func (r *fearRecord) triggered() int {
+	total := 0
+	for _, times := range r.Pets {
+		total += times
+	}
+	return total
+}
+
+// fearBook is the encyclopedia's record of every fear met so far
+type fearBook struct {
+	Fears map[string]*fearRecord `json:"fears"`
+}
+
+// loadFearBook reads the encyclopedia, starting an empty one if need be
+func loadFearBook(path string) *fearBook {
+	book := &fearBook{}
+	if data, err := os.ReadFile(path); err == nil {
+		json.Unmarshal(data, book)
+	}
+	if book.Fears == nil {
+		book.Fears = make(map[string]*fearRecord)
+	}
+	return book
+}
+
+// save writes the encyclopedia
+func (b *fearBook) save(path string) error {
+	data, err := json.MarshalIndent(b, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, data, 0644)
+}
+
+// fearPetID tells pets apart across generations
+func fearPetID(pet *Pet) string {
+	return pet.Name + "@" + pet.BirthTime.UTC().Format(time.RFC3339)
+}
+
+// observe documents the pet's fears and how often each was triggered
+func (b *fearBook) observe(pet *Pet, now time.Time) {
+	if pet.Absurd == nil {
+		return
+	}
+	for _, fear := range pet.Absurd.Fears {
+		record := b.Fears[fear.Name]
+		if record == nil {
+			record = &fearRecord{FirstSeen: now, Pets: make(map[string]int)}
+			b.Fears[fear.Name] = record
+		}
+		record.Pets[fearPetID(pet)] = pet.Absurd.FearTriggers[fear.Name]
+	}
+}
+
+// fearCatalog is every fear there is to document: the ones a pet can hatch
+// with, then the ones it can only learn
+func fearCatalog() []Fear {
+	var catalog []Fear
+	seen := make(map[string]bool)
+	for _, fear := range append(append([]Fear{}, activeContent.fears.items...), learnedFears...) {
+		if !seen[fear.Name] {
+			seen[fear.Name] = true
+			catalog = append(catalog, fear)
+		}
+	}
+	return catalog
+}
+
+// documented counts the catalog's fears the book has a record of
+func (b *fearBook) documented(catalog []Fear) int {
+	count := 0
+	for _, fear := range catalog {
+		if b.Fears[fear.Name] != nil {
+			count++
+		}
+	}
+	return count
+}
+
+// renderFearIndex lists every fear, hiding the ones nobody has met
+func renderFearIndex(book *fearBook, catalog []Fear, pet *Pet) string {
+	has := make(map[string]bool)
+	if pet.Absurd != nil {
+		for _, fear := range pet.Absurd.Fears {
+			has[fear.Name] = true
+		}
+	}
+
+	var b strings.Builder
+	b.WriteString("\n╔════════════════════════════════════╗\n")
+	b.WriteString("║      📖 FEAR ENCYCLOPEDIA 📖       ║\n")
+	b.WriteString("╠════════════════════════════════════╣\n")
+	fmt.Fprintf(&b, "║ Documented: %d/%d\n║\n", book.documented(catalog), len(catalog))
+	for _, fear := range catalog {
+		record := book.Fears[fear.Name]
+		switch {
+		case has[fear.Name]:
+			fmt.Fprintf(&b, "║ 😱 %s (your pet)\n", fear.Name)
+		case record != nil:
+			fmt.Fprintf(&b, "║ 📄 %s (%d %s)\n", fear.Name, len(record.Pets), plural(len(record.Pets), "pet", "pets"))
+		default:
+			b.WriteString("║ ❔ ???\n")
+		}
+	}
+	b.WriteString("╚════════════════════════════════════╝\n")
+	b.WriteString("fears <name> opens a page.")
+	return b.String()
+}
+
+// renderFearPage is one fear's page: its lore once it has been met, how
+// many pets had it, how often it struck, and a hint at a cure
+func renderFearPage(book *fearBook, fear Fear) string {
+	record := book.Fears[fear.Name]
+	if record == nil {
+		return fmt.Sprintf("❔ %s isn't documented yet. None of your pets has had it.", fear.Name)
+	}
+	page, ok := fearLore[fear.Name]
+	if !ok {
+		page = fearPage{Lore: "Little is written about this one.", Cure: "Unknown."}
+	}
+
+	var b strings.Builder
+	b.WriteString("\n╔════════════════════════════════════╗\n")
+	fmt.Fprintf(&b, "║ 📖 %s\n", strings.ToUpper(fear.Name))
+	b.WriteString("╠════════════════════════════════════╣\n")
+	fmt.Fprintf(&b, "║ %s\n║\n", fear.Description)
+	fmt.Fprintf(&b, "║ %s\n║\n", page.Lore)
+	fmt.Fprintf(&b, "║ First seen: %s\n", record.FirstSeen.Format("Jan 2, 2006"))
+	fmt.Fprintf(&b, "║ Carried by: %d %s\n", len(record.Pets), plural(len(record.Pets), "pet", "pets"))
+	fmt.Fprintf(&b, "║ Triggered: %d %s\n", record.triggered(), plural(record.triggered(), "time", "times"))
+	fmt.Fprintf(&b, "║ Cure: %s\n", page.Cure)
+	b.WriteString("╚════════════════════════════════════╝")
+	return b.String()
+}
+
+// findFear looks a fear up by name, ignoring case
+func findFear(catalog []Fear, name string) (Fear, bool) {
+	for _, fear := range catalog {
+		if strings.EqualFold(fear.Name, name) {
+			return fear, true
+		}
+	}
+	return Fear{}, false
+}
+
+// plural picks the word for a count
+func plural(n int, one, many string) string {
+	if n == 1 {
+		return one
+	}
+	return many
+}
+
+// documentFears records the pet's fears in the encyclopedia, unlocking the
+// completionist achievement once every fear is in it
+func documentFears(pet *Pet, path string, now time.Time) (*fearBook, string) {
+	book := loadFearBook(path)
+	book.observe(pet, now)
+	book.save(path)
+	if catalog := fearCatalog(); pet.Endgame != nil && book.documented(catalog) == len(catalog) {
+		_, unlocked := pet.Endgame.UnlockAchievement(phobiologistAchievement)
+		return book, unlocked
+	}
+	return book, ""
+}
+
+// runFearsCommand shows the pet's fears and the encyclopedia, or one page
+func runFearsCommand(pet *Pet, args, path string, now time.Time) string {
+	book, unlocked := documentFears(pet, path, now)
+	catalog := fearCatalog()
+	if name := strings.TrimSpace(args); name != "" {
+		fear, ok := findFear(catalog, name)
+		if !ok {
+			return fmt.Sprintf("❓ The encyclopedia has no %s. fears lists what it has.", name)
+		}
+		return strings.TrimSpace(renderFearPage(book, fear) + "\n" + unlocked)
+	}
+	display := "Your pet fears nothing. This is suspicious.\n"
+	if pet.Absurd != nil {
+		display = pet.Absurd.GetFearDisplay()
+	}
+	if pet.Bloodline != nil && pet.Bloodline.Strength != "" {
+		display += fmt.Sprintf("💪 %s is a strength now, thanks to the bloodline.\n", pet.Bloodline.Strength)
+	}
+	return strings.TrimSpace(display + renderFearIndex(book, catalog, pet) + "\n" + unlocked)
+}
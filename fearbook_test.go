@@ -0,0 +1,86 @@
+package main
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestFearBookAcrossGenerations(t *testing.T) {
+	path := filepath.Join(t.TempDir(), fearBookFile)
+	now := time.Date(2026, 10, 16, 12, 0, 0, 0, time.UTC)
+
+	first := NewPet("Mochi")
+	first.Absurd.Fears = []Fear{possibleFears[1]}
+	unknownCommand(first, newDefaultRegistry(), "tuesday", "tuesday")
+	unknownCommand(first, newDefaultRegistry(), "tuesday", "tuesday")
+	documentFears(first, path, now)
+	documentFears(first, path, now.Add(time.Hour))
+
+	second := NewPet("Bean")
+	second.BirthTime = first.BirthTime.Add(time.Hour)
+	second.Absurd.Fears = []Fear{possibleFears[1], forcePushFear}
+	unknownCommand(second, newDefaultRegistry(), "tuesday", "tuesday")
+	book, _ := documentFears(second, path, now.Add(24*time.Hour))
+
+	record := book.Fears["Tuesdread"]
+	if record == nil {
+		t.Fatal("Expected Tuesdread documented")
+	}
+	if len(record.Pets) != 2 || record.triggered() != 3 {
+		t.Errorf("Expected 2 pets triggered 3 times, got %d pets and %d times", len(record.Pets), record.triggered())
+	}
+	if !record.FirstSeen.Equal(now) {
+		t.Errorf("Expected first seen %v, got %v", now, record.FirstSeen)
+	}
+	if got := book.documented(fearCatalog()); got != 2 {
+		t.Errorf("Expected 2 fears documented, got %d", got)
+	}
+}
+
+func TestFearsCommand(t *testing.T) {
+	path := filepath.Join(t.TempDir(), fearBookFile)
+	now := time.Date(2026, 10, 16, 12, 0, 0, 0, time.UTC)
+	pet := NewPet("Mochi")
+	pet.Absurd.Fears = []Fear{possibleFears[0]}
+
+	tests := []struct {
+		args string
+		want []string
+	}{
+		{"", []string{"FEARS", "FEAR ENCYCLOPEDIA", "Documented: 1/", "Qphobia (your pet)", "???"}},
+		{"qphobia", []string{"QPHOBIA", "Terrified of the letter Q", "Carried by: 1 pet", "Triggered: 0 times", "Cure:"}},
+		{"Tuesdread", []string{"isn't documented yet"}},
+		{"Bananaphobia", []string{"no Bananaphobia"}},
+	}
+	for _, tt := range tests {
+		got := runFearsCommand(pet, tt.args, path, now)
+		for _, want := range tt.want {
+			if !strings.Contains(got, want) {
+				t.Errorf("fears %q: Expected %q in:\n%s", tt.args, want, got)
+			}
+		}
+	}
+	if strings.Contains(runFearsCommand(pet, "", path, now), "Tuesdread") {
+		t.Error("Expected undiscovered fears hidden")
+	}
+}
+
+func TestPhobiologistAchievement(t *testing.T) {
+	path := filepath.Join(t.TempDir(), fearBookFile)
+	now := time.Date(2026, 10, 16, 12, 0, 0, 0, time.UTC)
+	catalog := fearCatalog()
+
+	pet := NewPet("Mochi")
+	pet.Absurd.Fears = catalog[:len(catalog)-1]
+	if _, unlocked := documentFears(pet, path, now); unlocked != "" {
+		t.Errorf("Expected no achievement with a fear left, got %q", unlocked)
+	}
+
+	heir := NewPet("Bean")
+	heir.Absurd.Fears = catalog[len(catalog)-1:]
+	if _, unlocked := documentFears(heir, path, now); !strings.Contains(unlocked, "Phobiologist") {
+		t.Errorf("Expected Phobiologist once every fear is documented, got %q", unlocked)
+	}
+}
@@ -0,0 +1,153 @@
+package main
+
+import (
+	"bufio"
+	"fmt"
+	"math/rand"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	defaultFocusMinutes = 25
+	maxFocusMinutes     = 120
+	focusBreakMinutes   = 5
+)
+
+// Guilt trips for abandoned focus sessions
+var focusGuiltTrips = []string{
+	"Your pet wakes up. It was having such a nice nap. It knows what you did.",
+	"You gave up after %s. Your pet isn't angry. Just disappointed.",
+	"%s of focus. Your pet pretends that was the plan all along.",
+	"Your pet opens one eye. 'Already?' it seems to say.",
+	"The tomato timer weeps. Your pet comforts it.",
+}
+
+// Achievement milestones for completed focus sessions
+var focusAchievements = []struct {
+	sessions int
+	id       string
+}{
+	{1, "focus_1"},
+	{5, "focus_5"},
+	{25, "focus_25"},
+}
+
+// parseFocusMinutes parses the optional minutes argument to the focus command
+func parseFocusMinutes(args string) (int, error) {
+	if args == "" {
+		return defaultFocusMinutes, nil
+	}
+	minutes, err := strconv.Atoi(strings.TrimSpace(args))
+	if err != nil || minutes < 1 || minutes > maxFocusMinutes {
+		return 0, fmt.Errorf("focus takes a number of minutes between 1 and %d", maxFocusMinutes)
+	}
+	return minutes, nil
+}
+
+// CompleteFocusSession rewards the pet for a finished focus session
+func (p *Pet) CompleteFocusSession(minutes int) string {
+	p.Happiness = clamp(p.Happiness+clamp(minutes/2, 5, 20), 0, 100)
+
+	var b strings.Builder
+	b.WriteString(fmt.Sprintf("🍅 %d minutes of focus complete! Your pet wakes up refreshed and proud of you.\n", minutes))
+	b.WriteString(fmt.Sprintf("☕ Take a %d minute break. Your pet insists.", focusBreakMinutes))
+
+	if p.Endgame != nil {
+		p.Endgame.FocusSessionsCompleted++
+		p.Endgame.FocusMinutes += minutes
+		for _, milestone := range focusAchievements {
+			if p.Endgame.FocusSessionsCompleted >= milestone.sessions {
+				if unlocked, achMessage := p.Endgame.UnlockAchievement(milestone.id); unlocked {
+					b.WriteString("\n" + achMessage)
+				}
+			}
+		}
+	}
+
+	return b.String()
+}
+
+// AbandonFocusSession guilt-trips the user for giving up early
+func (p *Pet) AbandonFocusSession(elapsed time.Duration) string {
+	p.Happiness = clamp(p.Happiness-5, 0, 100)
+	if p.Endgame != nil {
+		p.Endgame.FocusSessionsAbandoned++
+	}
+
+	guilt := focusGuiltTrips[rand.Intn(len(focusGuiltTrips))]
+	if strings.Contains(guilt, "%s") {
+		guilt = fmt.Sprintf(guilt, formatDuration(elapsed))
+	}
+	return "😔 " + guilt
+}
+
+// runFocusSession runs a pomodoro timer while the pet sleeps. Pressing Enter abandons it.
+// The pet is let go for the whole session, so the autosave keeps running.
+func runFocusSession(pet *Pet, reader *bufio.Reader, session *petSession, ui *uiConfig, minutes int) string {
+	if pet.Stage == Dead {
+		return "💀 Your pet can no longer keep you company."
+	}
+
+	duration := time.Duration(minutes) * time.Minute
+	start := time.Now()
+
+	clearScreen()
+	fmt.Print(ui.paletteText(`
+       (-_-)  zzZ
+      /|   |\
+       "   "
+`, ui.palette.faint))
+	fmt.Println(ui.text(fmt.Sprintf("\n🍅 Focus session: %d minutes. Your pet is sleeping quietly.", minutes)))
+	fmt.Println("   Press Enter to give up.")
+
+	name := pet.Name
+	completed := false
+	session.Wait(func() { completed = awaitFocus(reader, ui, name, start, duration) })
+	if completed {
+		return pet.CompleteFocusSession(minutes)
+	}
+	return pet.AbandonFocusSession(time.Since(start))
+}
+
+// awaitFocus counts a focus session down until it's over or the player
+// presses Enter, and reports whether it ran its course
+func awaitFocus(reader *bufio.Reader, ui *uiConfig, name string, start time.Time, duration time.Duration) bool {
+	input := make(chan struct{}, 1)
+	go func() {
+		reader.ReadString('\n')
+		input <- struct{}{}
+	}()
+
+	tick := time.Second
+	if ui.reducedMotion {
+		tick = time.Minute
+	}
+	ticker := time.NewTicker(tick)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-input:
+			fmt.Println()
+			return false
+
+		case <-ticker.C:
+			remaining := duration - time.Since(start)
+			if remaining <= 0 {
+				ui.playNotificationSound(SoundBreak, name)
+				fmt.Print(ui.text("\n⏰ Time's up! Press Enter to wake your pet..."))
+				<-input
+				return true
+			}
+
+			line := ui.text(fmt.Sprintf("   ⏳ %s remaining", formatDuration(remaining)))
+			if ui.reducedMotion {
+				fmt.Println(line)
+			} else {
+				fmt.Printf("\r%s   ", line)
+			}
+		}
+	}
+}
@@ -0,0 +1,75 @@
+package main
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestParseFocusMinutes(t *testing.T) {
+	tests := []struct {
+		args     string
+		expected int
+		wantErr  bool
+	}{
+		{"", defaultFocusMinutes, false},
+		{"25", 25, false},
+		{"5", 5, false},
+		{"0", 0, true},
+		{"999", 0, true},
+		{"soon", 0, true},
+	}
+
+	for _, tt := range tests {
+		minutes, err := parseFocusMinutes(tt.args)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("parseFocusMinutes(%q) error = %v, wantErr %v", tt.args, err, tt.wantErr)
+		}
+		if minutes != tt.expected {
+			t.Errorf("parseFocusMinutes(%q) = %d, expected %d", tt.args, minutes, tt.expected)
+		}
+	}
+}
+
+func TestCompleteFocusSession(t *testing.T) {
+	pet := NewPet("TestPet")
+	pet.Stage = Baby
+	pet.Happiness = 50
+
+	result := pet.CompleteFocusSession(25)
+
+	if pet.Happiness <= 50 {
+		t.Errorf("Expected happiness to increase, got %d", pet.Happiness)
+	}
+	if pet.Endgame.FocusSessionsCompleted != 1 || pet.Endgame.FocusMinutes != 25 {
+		t.Errorf("Expected 1 session / 25 minutes, got %d / %d",
+			pet.Endgame.FocusSessionsCompleted, pet.Endgame.FocusMinutes)
+	}
+	if !strings.Contains(result, "ACHIEVEMENT UNLOCKED") {
+		t.Error("Expected first focus session to unlock an achievement")
+	}
+
+	pet.Endgame.FocusSessionsCompleted = 4
+	result = pet.CompleteFocusSession(25)
+	if !strings.Contains(result, "Pomodoro Enjoyer") {
+		t.Error("Expected fifth session to unlock Pomodoro Enjoyer")
+	}
+}
+
+func TestAbandonFocusSession(t *testing.T) {
+	pet := NewPet("TestPet")
+	pet.Stage = Baby
+	pet.Happiness = 50
+
+	result := pet.AbandonFocusSession(3 * time.Minute)
+
+	if pet.Happiness != 45 {
+		t.Errorf("Expected happiness 45 after abandoning, got %d", pet.Happiness)
+	}
+	if pet.Endgame.FocusSessionsAbandoned != 1 {
+		t.Errorf("Expected 1 abandoned session, got %d", pet.Endgame.FocusSessionsAbandoned)
+	}
+	if strings.Contains(result, "%!") {
+		t.Errorf("Guilt trip was badly formatted: %q", result)
+	}
+}
@@ -0,0 +1,224 @@
+package main
+
+import (
+	"fmt"
+	"hash/fnv"
+	"slices"
+	"strings"
+)
+
+const (
+	// tastesToKnow is how many meals of a food it takes to know the pet's
+	// mind about it
+	tastesToKnow = 3
+	// favoriteBonus is the extra happiness a favorite food brings
+	favoriteBonus = 10
+	// forcedAnxiety is how much anxiety a known aversion brings when fed anyway
+	forcedAnxiety = 25
+	// anxiousAt is the anxiety at which the pet's mood turns anxious
+	anxiousAt = 30
+	// calmPerHour is how fast anxiety fades
+	calmPerHour = 5
+)
+
+// food is something to feed the pet
+type food struct {
+	name  string
+	glyph string
+}
+
+// foods are what 'feed' can offer; kibble is what it gets when you don't say
+var foods = []food{
+	{"kibble", "🥣"}, {"apple", "🍏"}, {"fish", "🐟"}, {"noodles", "🍜"},
+	{"cake", "🍰"}, {"broccoli", "🥦"}, {"bugs", "🐛"},
+}
+
+// FoodState is what the pet has been fed and how it feels about it
+type FoodState struct {
+	Fed       map[string]int `json:"fed,omitempty"`       // Meals of each food
+	Inherited string         `json:"inherited,omitempty"` // A favorite passed down from the last pet
+	Anxiety   int            `json:"anxiety,omitempty"`   // 0-100, raised by foods it hates
+}
+
+// foodNamed finds a food, or reports false
+func foodNamed(name string) (food, bool) {
+	i := slices.IndexFunc(foods, func(f food) bool { return f.name == name })
+	if i < 0 {
+		return food{}, false
+	}
+	return foods[i], true
+}
+
+// palate returns the pet's food state, creating it on first use
+func (p *Pet) palate() *FoodState {
+	if p.Food == nil {
+		p.Food = &FoodState{}
+	}
+	if p.Food.Fed == nil {
+		p.Food.Fed = map[string]int{}
+	}
+	return p.Food
+}
+
+// leaning is how the pet is made to feel about a food, fixed from birth:
+// "favorite", "aversion", or "" when it doesn't mind. Nobody minds kibble.
+func (p *Pet) leaning(name string) string {
+	if p.Food != nil && p.Food.Inherited == name {
+		return "favorite"
+	}
+	if name == foods[0].name {
+		return ""
+	}
+	h := fnv.New32a()
+	h.Write([]byte(p.petID() + "/" + name))
+	switch h.Sum32() % 5 {
+	case 0:
+		return "aversion"
+	case 1:
+		return "favorite"
+	}
+	return ""
+}
+
+// knows reports whether the pet has made up its mind about a food
+func (f *FoodState) knows(name string) bool {
+	return f != nil && (f.Fed[name] >= tastesToKnow || f.Inherited == name)
+}
+
+// anxiety is how anxious forced meals have left the pet
+func (p *Pet) anxiety() int {
+	if p.Food == nil {
+		return 0
+	}
+	return p.Food.Anxiety
+}
+
+// calm lets anxiety fade over the hours that passed, twice as fast with
+// the charger's hum nearby
+func (p *Pet) calm(hoursPassed float64) {
+	if p.Food == nil || p.Food.Anxiety == 0 {
+		return
+	}
+	rate := float64(calmPerHour)
+	if p.Power.Charging {
+		rate *= 2
+	}
+	p.Food.Anxiety = clamp(p.Food.Anxiety-int(hoursPassed*rate), 0, 100)
+}
+
+// Feed reduces hunger with the named food, or kibble
+func (p *Pet) Feed(name string) string {
+	if p.Stage == Dead {
+		return "💀 Your pet has passed away..."
+	}
+	if p.Stage == Egg {
+		return "🥚 The egg doesn't need food yet!"
+	}
+	if name == "" {
+		name = foods[0].name
+	}
+	meal, ok := foodNamed(strings.ToLower(name))
+	if !ok {
+		names := make([]string, len(foods))
+		for i, f := range foods {
+			names[i] = f.name
+		}
+		return "🍽️ Feed it what? " + strings.Join(names, ", ")
+	}
+
+	if p.Hunger <= 10 {
+		return "😊 I'm already full!"
+	}
+
+	f := p.palate()
+	knewBefore := f.knows(meal.name)
+	f.Fed[meal.name]++
+	learned := !knewBefore && f.knows(meal.name)
+
+	p.Hunger = clamp(p.Hunger-30, 0, 100)
+	message := "😋 Yum! That was delicious!"
+	switch p.leaning(meal.name) {
+	case "favorite":
+		p.Happiness += 5 + favoriteBonus
+		if knewBefore {
+			message = fmt.Sprintf("😍 %s %s! %s's favorite. It eats every crumb.", meal.glyph, meal.name, p.Name)
+		} else {
+			message = fmt.Sprintf("😋 %s goes back for seconds of the %s.", p.Name, meal.name)
+		}
+		if learned {
+			message += fmt.Sprintf(" 💡 %s loves %s.", p.Name, meal.name)
+		}
+	case "aversion":
+		if knewBefore {
+			f.Anxiety = clamp(f.Anxiety+forcedAnxiety, 0, 100)
+			message = fmt.Sprintf("😰 %s eats the %s because you insist. It keeps glancing at you.", p.Name, meal.name)
+		} else {
+			message = fmt.Sprintf("😖 %s eats the %s, slowly.", p.Name, meal.name)
+		}
+		if learned {
+			message += fmt.Sprintf(" 💡 %s can't stand %s.", p.Name, meal.name)
+		}
+	default:
+		p.Happiness += 5
+	}
+	p.Happiness = clamp(p.Happiness, 0, 100)
+	return message
+}
+
+// favoriteToPass is the known favorite the pet ate most, for its offspring
+func (p *Pet) favoriteToPass() string {
+	best, most := "", 0
+	for _, f := range foods {
+		if p.Food.knows(f.name) && p.leaning(f.name) == "favorite" && p.Food.Fed[f.name] > most {
+			best, most = f.name, p.Food.Fed[f.name]
+		}
+	}
+	if best == "" && p.Food != nil {
+		// A favorite it was born with but never got fed still counts
+		best = p.Food.Inherited
+	}
+	return best
+}
+
+// inheritFavorite gives a new pet its parent's favorite food
+func (p *Pet) inheritFavorite(name string) {
+	if name == "" {
+		return
+	}
+	p.palate().Inherited = name
+}
+
+// Favorites shows what the pet has been fed and what it thinks of it
+func (p *Pet) Favorites() string {
+	if p.Stage == Egg {
+		return "🥚 The egg hasn't tasted anything yet."
+	}
+	var b strings.Builder
+	fmt.Fprintf(&b, "🍽️ What %s thinks of its food:\n", p.Name)
+	for _, f := range foods {
+		fed := 0
+		if p.Food != nil {
+			fed = p.Food.Fed[f.name]
+		}
+		verdict := fmt.Sprintf("❓ still deciding (%d/%d)", fed, tastesToKnow)
+		switch {
+		case fed == 0 && !p.Food.knows(f.name):
+			verdict = "never tried"
+		case !p.Food.knows(f.name):
+		case p.leaning(f.name) == "favorite":
+			verdict = "😍 favorite"
+		case p.leaning(f.name) == "aversion":
+			verdict = "😖 can't stand it"
+		default:
+			verdict = "😐 doesn't mind it"
+		}
+		fmt.Fprintf(&b, "  %s %-9s fed %2d×  %s\n", f.glyph, f.name, fed, verdict)
+	}
+	if p.Food != nil && p.Food.Inherited != "" {
+		fmt.Fprintf(&b, "🧬 Loved %s before it ever tasted it, like its parent.\n", p.Food.Inherited)
+	}
+	if anxiety := p.anxiety(); anxiety > 0 {
+		fmt.Fprintf(&b, "😰 Anxiety %d/100. It remembers the meals it didn't want.\n", anxiety)
+	}
+	return strings.TrimRight(b.String(), "\n")
+}
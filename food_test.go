@@ -0,0 +1,178 @@
+package main
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+// foodWith finds a food the pet leans toward the given way
+func foodWith(t *testing.T, p *Pet, leaning string) string {
+	t.Helper()
+	for _, f := range foods {
+		if p.leaning(f.name) == leaning {
+			return f.name
+		}
+	}
+	t.Skipf("Pet %s has no food with leaning %q", p.Name, leaning)
+	return ""
+}
+
+// pickyPet is a pet with at least one favorite and one aversion
+func pickyPet(t *testing.T) *Pet {
+	t.Helper()
+	birth := time.Date(2026, 10, 16, 9, 0, 0, 0, time.UTC)
+	for i := 0; i < 100; i++ {
+		pet := NewPet("Picky")
+		pet.BirthTime = birth.Add(time.Duration(i) * time.Hour)
+		pet.Stage = Adult
+		var favorite, aversion bool
+		for _, f := range foods {
+			favorite = favorite || pet.leaning(f.name) == "favorite"
+			aversion = aversion || pet.leaning(f.name) == "aversion"
+		}
+		if favorite && aversion {
+			return pet
+		}
+	}
+	t.Fatalf("Expected some pet to be picky")
+	return nil
+}
+
+func TestFeedFoods(t *testing.T) {
+	tests := []struct {
+		name string
+		food string
+		want string
+	}{
+		{"kibble by default", "", "Yum!"},
+		{"named", "KIBBLE", "Yum!"},
+		{"unknown", "rocks", "Feed it what? kibble, apple"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			pet := NewPet("Hungry")
+			pet.Stage = Adult
+			pet.Hunger = 80
+			if got := pet.Feed(tt.food); !strings.Contains(got, tt.want) {
+				t.Errorf("Expected %q in %q", tt.want, got)
+			}
+		})
+	}
+}
+
+func TestFavoriteFoodIsLearned(t *testing.T) {
+	pet := pickyPet(t)
+	favorite := foodWith(t, pet, "favorite")
+
+	wants := []string{"seconds", "seconds", "loves " + favorite, "favorite"}
+	for i, want := range wants {
+		pet.Hunger, pet.Happiness = 80, 50
+		got := pet.Feed(favorite)
+		if !strings.Contains(got, want) {
+			t.Errorf("Meal %d: expected %q in %q", i+1, want, got)
+		}
+		if pet.Happiness != 50+5+favoriteBonus {
+			t.Errorf("Meal %d: expected a favorite to bring %d happiness, got %d", i+1, 5+favoriteBonus, pet.Happiness-50)
+		}
+	}
+	if pet.anxiety() != 0 {
+		t.Errorf("Expected no anxiety from a favorite, got %d", pet.anxiety())
+	}
+}
+
+func TestForcedAversionRaisesAnxiety(t *testing.T) {
+	pet := pickyPet(t)
+	aversion := foodWith(t, pet, "aversion")
+
+	for i := 0; i < tastesToKnow; i++ {
+		pet.Hunger = 80
+		pet.Feed(aversion)
+	}
+	if pet.anxiety() != 0 {
+		t.Fatalf("Expected no anxiety before it knows its mind, got %d", pet.anxiety())
+	}
+
+	for i := 0; i < 2; i++ {
+		pet.Hunger = 80
+		if got := pet.Feed(aversion); !strings.Contains(got, "because you insist") {
+			t.Errorf("Expected a forced meal, got %q", got)
+		}
+	}
+	if pet.anxiety() != 2*forcedAnxiety {
+		t.Errorf("Expected anxiety %d, got %d", 2*forcedAnxiety, pet.anxiety())
+	}
+	if mood, _ := pet.moodFromStats(); mood != "anxious" {
+		t.Errorf("Expected forced meals to make it anxious, got %s", mood)
+	}
+
+	pet.calm(4)
+	if pet.anxiety() != 2*forcedAnxiety-4*calmPerHour {
+		t.Errorf("Expected anxiety to fade, got %d", pet.anxiety())
+	}
+	pet.calm(100)
+	if pet.anxiety() != 0 {
+		t.Errorf("Expected anxiety to fade away, got %d", pet.anxiety())
+	}
+}
+
+func TestFavoritesScreen(t *testing.T) {
+	pet := pickyPet(t)
+	favorite := foodWith(t, pet, "favorite")
+	aversion := foodWith(t, pet, "aversion")
+	meals := map[string]int{"kibble": tastesToKnow, favorite: tastesToKnow, aversion: tastesToKnow + 1}
+	if favorite != "fish" && aversion != "fish" {
+		meals["fish"] = 1
+	}
+	for name, n := range meals {
+		for i := 0; i < n; i++ {
+			pet.Hunger = 80
+			pet.Feed(name)
+		}
+	}
+
+	got := pet.Favorites()
+	for _, want := range []string{"😐 doesn't mind it", "😍 favorite", "😖 can't stand it", "never tried", "Anxiety 25/100"} {
+		if !strings.Contains(got, want) {
+			t.Errorf("Expected %q in:\n%s", want, got)
+		}
+	}
+	if meals["fish"] == 1 && !strings.Contains(got, "still deciding (1/3)") {
+		t.Errorf("Expected fish still being decided in:\n%s", got)
+	}
+
+	pet.Stage = Egg
+	if got := pet.Favorites(); !strings.Contains(got, "hasn't tasted") {
+		t.Errorf("Expected an egg to have no favorites, got %q", got)
+	}
+}
+
+func TestFavoriteIsInherited(t *testing.T) {
+	pet := pickyPet(t)
+	if got := pet.favoriteToPass(); got != "" {
+		t.Errorf("Expected nothing to pass on before it knows a favorite, got %q", got)
+	}
+	favorite := foodWith(t, pet, "favorite")
+	for i := 0; i < tastesToKnow; i++ {
+		pet.Hunger = 80
+		pet.Feed(favorite)
+	}
+	for i := 0; i < 5; i++ {
+		pet.Hunger = 80
+		pet.Feed("kibble")
+	}
+
+	passed := pet.favoriteToPass()
+	if passed != favorite {
+		t.Fatalf("Expected %s passed on, got %q", favorite, passed)
+	}
+
+	pet.Reset("Sprout")
+	pet.inheritFavorite(passed)
+	if !pet.Food.knows(passed) || pet.leaning(passed) != "favorite" {
+		t.Errorf("Expected the new pet to already love %s", passed)
+	}
+	if got := pet.favoriteToPass(); got != passed {
+		t.Errorf("Expected the inherited favorite passed on again, got %q", got)
+	}
+}
@@ -0,0 +1,102 @@
+package main
+
+import (
+	"bytes"
+	"maps"
+	"sync"
+	"time"
+)
+
+const (
+	// sceneTick is the smallest step any scene animation takes; a frame is
+	// drawn at most once a tick, so a 10fps redraw draws each frame once
+	sceneTick = 100 * time.Millisecond
+	// sceneRollInterval is how long an expression, glitch, or thought holds
+	// before it's rolled again
+	sceneRollInterval = 2 * time.Second
+)
+
+// scenePool holds the buffers frames are drawn into
+var scenePool = sync.Pool{New: func() interface{} { return new(bytes.Buffer) }}
+
+// petState is the part of a pet the scene shows that changes between
+// ticks: what a command or decay can move
+type petState struct {
+	name        string
+	stage       LifeStage
+	hunger      int
+	happiness   int
+	health      int
+	cleanliness int
+	age         int
+	warmth      int
+	sick        bool
+	paused      bool
+	power       string
+	generation  int
+}
+
+func petStateOf(pet *Pet) petState {
+	state := petState{
+		name:        pet.Name,
+		stage:       pet.Stage,
+		hunger:      pet.Hunger,
+		happiness:   pet.Happiness,
+		health:      pet.Health,
+		cleanliness: pet.Cleanliness,
+		age:         pet.Age,
+		sick:        pet.IsSick,
+		paused:      pet.Paused,
+		power:       pet.Power.coarse(),
+		generation:  pet.generation(),
+	}
+	if pet.Incubation != nil {
+		state.warmth = pet.Incubation.Warmth
+	}
+	return state
+}
+
+// frameKey is everything a drawn frame depends on. Anything else the
+// scene shows, like a trip's countdown, can't go stale for more than a
+// tick.
+type frameKey struct {
+	snap          sceneSnapshot
+	tick          int64
+	pet           petState
+	banner        string
+	palette       uiPalette
+	color         bool
+	reducedMotion bool
+	zoom          cameraZoom
+	art           *artLibrary
+}
+
+// sceneCache holds the last rolled snapshot and the last frame drawn
+type sceneCache struct {
+	snap      sceneSnapshot
+	rolledAt  time.Time
+	rolledFor petState
+	key       frameKey
+	slots     map[string]string // The room as it was drawn
+	frame     string
+}
+
+// lookup returns the last frame if it was drawn for key in the same room
+func (c *sceneCache) lookup(key frameKey, room *RoomState) (string, bool) {
+	if c.frame == "" || key != c.key {
+		return "", false
+	}
+	var slots map[string]string
+	if room != nil {
+		slots = room.Slots
+	}
+	return c.frame, maps.Equal(slots, c.slots)
+}
+
+// store keeps a frame for the next lookup
+func (c *sceneCache) store(key frameKey, room *RoomState, frame string) {
+	c.key, c.frame, c.slots = key, frame, nil
+	if room != nil {
+		c.slots = maps.Clone(room.Slots)
+	}
+}
@@ -0,0 +1,100 @@
+package main
+
+import (
+	"testing"
+	"time"
+)
+
+func TestSceneCacheLookup(t *testing.T) {
+	key := frameKey{tick: 7, pet: petState{name: "Tamago", hunger: 20}}
+	var cache sceneCache
+	cache.store(key, &RoomState{Slots: map[string]string{"left": "lamp"}}, "frame")
+
+	moved := key
+	moved.tick++
+	fed := key
+	fed.pet.hunger = 10
+
+	tests := []struct {
+		name string
+		key  frameKey
+		room *RoomState
+		want bool
+	}{
+		{"same frame", key, &RoomState{Slots: map[string]string{"left": "lamp"}}, true},
+		{"next tick", moved, &RoomState{Slots: map[string]string{"left": "lamp"}}, false},
+		{"pet fed", fed, &RoomState{Slots: map[string]string{"left": "lamp"}}, false},
+		{"furniture moved", key, &RoomState{Slots: map[string]string{"right": "lamp"}}, false},
+		{"room cleared", key, nil, false},
+	}
+	for _, tt := range tests {
+		if _, ok := cache.lookup(tt.key, tt.room); ok != tt.want {
+			t.Errorf("%s: Expected hit %v, got %v", tt.name, tt.want, ok)
+		}
+	}
+}
+
+func TestSnapshotHoldsUntilRolledAgain(t *testing.T) {
+	ui := newUIConfig()
+	pet := NewPet("Tamago")
+	now := time.Now()
+
+	ui.snapshotAt(pet, petStateOf(pet), now)
+	ui.snapshotAt(pet, petStateOf(pet), now.Add(sceneRollInterval/2))
+	if !ui.scene.rolledAt.Equal(now) {
+		t.Errorf("Expected the snapshot held for %v, got rolled at %v", sceneRollInterval, ui.scene.rolledAt)
+	}
+
+	pet.Hunger += 10
+	ui.snapshotAt(pet, petStateOf(pet), now.Add(sceneRollInterval/2))
+	if !ui.scene.rolledAt.Equal(now.Add(sceneRollInterval / 2)) {
+		t.Errorf("Expected a changed pet to roll the snapshot again")
+	}
+
+	ui.snapshotAt(pet, petStateOf(pet), now.Add(3*sceneRollInterval))
+	if !ui.scene.rolledAt.Equal(now.Add(3 * sceneRollInterval)) {
+		t.Errorf("Expected the snapshot rolled again after %v", sceneRollInterval)
+	}
+}
+
+func TestRenderSceneUnchangedFrameAllocs(t *testing.T) {
+	ui := newUIConfig()
+	pet := NewPet("Tamago")
+	renderScene(pet, ui)
+	// A tick can turn over once while this runs; one redraw spread over
+	// the runs still rounds to zero
+	if allocs := testing.AllocsPerRun(10000, func() { renderScene(pet, ui) }); allocs != 0 {
+		t.Errorf("Expected no allocations for an unchanged frame, got %v", allocs)
+	}
+}
+
+func TestRenderSceneRedrawsWhenThePetChanges(t *testing.T) {
+	ui := newUIConfig()
+	ui.reducedMotion = true
+	pet := NewPet("Tamago")
+	pet.Hunger = 0
+	before := renderScene(pet, ui)
+	pet.Hunger = 100
+	if after := renderScene(pet, ui); after == before {
+		t.Errorf("Expected the hunger bar redrawn")
+	}
+}
+
+func BenchmarkRenderScene(b *testing.B) {
+	ui := newUIConfig()
+	pet := NewPet("Tamago")
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		renderScene(pet, ui)
+	}
+}
+
+func BenchmarkRenderSceneRedraw(b *testing.B) {
+	ui := newUIConfig()
+	pet := NewPet("Tamago")
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		ui.scene.frame = ""
+		renderScene(pet, ui)
+	}
+}
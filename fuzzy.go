@@ -0,0 +1,111 @@
+package main
+
+import "strings"
+
+const (
+	// maxSuggestDistance is the furthest a typo can be from a suggested command
+	maxSuggestDistance = 2
+
+	// minCorrectLength keeps very short input from being "corrected" into anything
+	minCorrectLength = 3
+)
+
+// neverAutocorrect lists commands too drastic to run on a guess
+var neverAutocorrect = map[string]bool{
+	"reset": true,
+	"quit":  true,
+}
+
+// levenshtein returns the edit distance between two strings, counted in runes
+func levenshtein(a, b string) int {
+	ra, rb := []rune(a), []rune(b)
+	prev := make([]int, len(rb)+1)
+	curr := make([]int, len(rb)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+
+	for i := 1; i <= len(ra); i++ {
+		curr[0] = i
+		for j := 1; j <= len(rb); j++ {
+			cost := 1
+			if ra[i-1] == rb[j-1] {
+				cost = 0
+			}
+			curr[j] = min(prev[j]+1, curr[j-1]+1, prev[j-1]+cost)
+		}
+		prev, curr = curr, prev
+	}
+	return prev[len(rb)]
+}
+
+// nearbyCommands returns the commands whose name (or, with aliases, any word)
+// is within distance of word
+func (r *commandRegistry) nearbyCommands(word string, distance int, aliases bool) []Command {
+	nearby := make([]Command, 0)
+	for _, cmd := range r.commands {
+		words := []string{cmd.Name()}
+		if aliases {
+			words = append(words, cmd.Aliases()...)
+		}
+		for _, w := range words {
+			if levenshtein(word, w) <= distance {
+				nearby = append(nearby, cmd)
+				break
+			}
+		}
+	}
+	return nearby
+}
+
+// Correct returns the command an unambiguous one-character typo was meant to
+// be, or nil. Command names win over aliases, so "fed" is feed rather than a
+// coin toss with med; "hel" stays unknown because it could be heal or help.
+func (r *commandRegistry) Correct(word string) Command {
+	if len([]rune(word)) < minCorrectLength {
+		return nil
+	}
+
+	nearby := r.nearbyCommands(word, 1, false)
+	if len(nearby) == 0 {
+		nearby = r.nearbyCommands(word, 1, true)
+	}
+	if len(nearby) != 1 || neverAutocorrect[nearby[0].Name()] {
+		return nil
+	}
+	return nearby[0]
+}
+
+// Suggest returns the command the user most likely meant, or ""
+func (r *commandRegistry) Suggest(word string) string {
+	if len([]rune(word)) < 2 {
+		return ""
+	}
+
+	best, bestScore := "", maxSuggestDistance+1
+	for w, cmd := range r.byWord {
+		score := levenshtein(word, w)
+		// A word that starts the same way is as good as a single typo
+		if score > 1 && len(w) >= minCorrectLength && (strings.HasPrefix(w, word) || strings.HasPrefix(word, w)) {
+			score = 1
+		}
+		if score > maxSuggestDistance {
+			continue
+		}
+		if score < bestScore || (score == bestScore && preferName(cmd.Name(), best)) {
+			best, bestScore = cmd.Name(), score
+		}
+	}
+	return best
+}
+
+// preferName breaks suggestion ties: shorter names first, then alphabetical
+func preferName(name, current string) bool {
+	if current == "" {
+		return true
+	}
+	if len(name) != len(current) {
+		return len(name) < len(current)
+	}
+	return name < current
+}
@@ -0,0 +1,69 @@
+package main
+
+import "testing"
+
+func TestLevenshtein(t *testing.T) {
+	tests := []struct {
+		a, b string
+		want int
+	}{
+		{"", "", 0},
+		{"feed", "feed", 0},
+		{"fed", "feed", 1},
+		{"feed", "food", 2},
+		{"", "play", 4},
+		{"kitten", "sitting", 3},
+		{"🍔", "🍕", 1},
+	}
+	for _, tt := range tests {
+		if got := levenshtein(tt.a, tt.b); got != tt.want {
+			t.Errorf("levenshtein(%q, %q): expected %d, got %d", tt.a, tt.b, tt.want, got)
+		}
+	}
+}
+
+func TestCorrect(t *testing.T) {
+	commands := newDefaultRegistry()
+	tests := []struct {
+		word string
+		want string
+	}{
+		{"fed", "feed"},  // names beat the med alias
+		{"plat", "play"}, // substitution
+		{"cleann", "clean"},
+		{"hel", ""},  // heal or help
+		{"fe", ""},   // too short to guess
+		{"plya", ""}, // transpositions are two edits
+		{"quiy", ""}, // never guess quit
+		{"rest", ""}, // or reset
+	}
+	for _, tt := range tests {
+		got := ""
+		if cmd := commands.Correct(tt.word); cmd != nil {
+			got = cmd.Name()
+		}
+		if got != tt.want {
+			t.Errorf("Correct(%q): expected %q, got %q", tt.word, tt.want, got)
+		}
+	}
+}
+
+func TestSuggest(t *testing.T) {
+	commands := newDefaultRegistry()
+	tests := []struct {
+		word string
+		want string
+	}{
+		{"plya", "play"},
+		{"feeding", "feed"},
+		{"achievem", "achievements"},
+		{"hel", "heal"},
+		{"x", ""},
+		{"zzz", ""},
+	}
+	for _, tt := range tests {
+		if got := commands.Suggest(tt.word); got != tt.want {
+			t.Errorf("Suggest(%q): expected %q, got %q", tt.word, tt.want, got)
+		}
+	}
+}
@@ -0,0 +1,273 @@
+package main
+
+import (
+	"fmt"
+	"math/rand"
+	"strings"
+	"time"
+
+	"github.com/tamagotchi/mooc"
+)
+
+// gachaRarity is how rare an invisible accessory is. It changes nothing
+// about the accessory, which remains invisible.
+type gachaRarity int
+
+const (
+	rarityCommon gachaRarity = iota
+	rarityRare
+	rarityEpic
+	rarityLegendary
+)
+
+func (r gachaRarity) String() string {
+	return [...]string{"Common", "Rare", "Epic", "Legendary"}[r]
+}
+
+// Stars shows the rarity the way gacha games do
+func (r gachaRarity) Stars() string {
+	return strings.Repeat("★", int(r)+1)
+}
+
+const (
+	// gachaPity is how many pulls it takes to guarantee Rare or better
+	gachaPity = 10
+
+	// gachaFeaturedChance is the percent chance that a pull of the featured
+	// accessory's rarity is the featured accessory
+	gachaFeaturedChance = 50
+)
+
+// gachaRates are each rarity's chance, per thousand pulls
+var gachaRates = [...]int{
+	rarityCommon:    790,
+	rarityRare:      150,
+	rarityEpic:      50,
+	rarityLegendary: 10,
+}
+
+// accessoryRarity grades the accessories. Anything not listed, like a
+// community gift, is Common.
+var accessoryRarity = map[string]gachaRarity{
+	"Transparent Monocle": rarityRare,
+	"Invisible Top Hat":   rarityRare,
+	"Void Bracelet":       rarityRare,
+	"Transparent Shield":  rarityRare,
+	"Invisible Sword":     rarityEpic,
+	"Null Ring":           rarityEpic,
+	"Invisible Crown":     rarityLegendary,
+}
+
+// bannerNames are the weekly banners' themes
+var bannerNames = []string{
+	"Festival of Nothing", "The Unseen Gala", "Clear Skies Week",
+	"Void Couture", "Absent Friends", "The Empty Runway",
+	"Null Season", "Transparency Report",
+}
+
+// gachaBanner is the week's banner, the same for every player
+type gachaBanner struct {
+	Week     string // ISO week, like 2026-W42
+	Name     string
+	Featured string // Rate-up accessory, Rare or better
+	Ends     time.Time
+}
+
+// currentBanner works out the week's banner from the ISO week, in UTC so
+// everyone's rolls over at the same moment
+func currentBanner(now time.Time) gachaBanner {
+	now = now.UTC()
+	year, week := now.ISOWeek()
+	r := rand.New(rand.NewSource(int64(year*100 + week)))
+
+	var featured []string
+	for _, item := range invisibleAccessories {
+		if accessoryRarity[item] >= rarityRare {
+			featured = append(featured, item)
+		}
+	}
+
+	monday := now.AddDate(0, 0, -(int(now.Weekday())+6)%7)
+	return gachaBanner{
+		Week:     fmt.Sprintf("%d-W%02d", year, week),
+		Name:     bannerNames[r.Intn(len(bannerNames))],
+		Featured: featured[r.Intn(len(featured))],
+		Ends:     time.Date(monday.Year(), monday.Month(), monday.Day()+7, 0, 0, 0, 0, time.UTC),
+	}
+}
+
+// rollGacha picks an accessory: a rarity by the rates, Rare or better once
+// the pity counter runs out, and the featured accessory half the time it
+// could be
+func (e *EndgameState) rollGacha(banner gachaBanner, r *rand.Rand) (string, gachaRarity) {
+	e.GachaPity++
+
+	rarity := rarityCommon
+	roll, cumulative := r.Intn(1000), 0
+	for tier := rarityLegendary; tier > rarityCommon; tier-- {
+		cumulative += gachaRates[tier]
+		if roll < cumulative {
+			rarity = tier
+			break
+		}
+	}
+	if rarity == rarityCommon && e.GachaPity >= gachaPity {
+		rarity = rarityRare
+	}
+	if rarity >= rarityRare {
+		e.GachaPity = 0
+	}
+
+	if accessoryRarity[banner.Featured] == rarity && r.Intn(100) < gachaFeaturedChance {
+		return banner.Featured, rarity
+	}
+	var pool []string
+	for _, item := range invisibleAccessories {
+		if accessoryRarity[item] == rarity {
+			pool = append(pool, item)
+		}
+	}
+	return pool[r.Intn(len(pool))], rarity
+}
+
+// pullGacha does one pull on a banner and returns the result screen and
+// what came out
+func (e *EndgameState) pullGacha(banner gachaBanner, r *rand.Rand) (string, string, gachaRarity) {
+	e.GachaPulls++
+	accessory, rarity := e.rollGacha(banner, r)
+
+	// Check for duplicate
+	for _, owned := range e.InvisibleAccessories {
+		if owned == accessory {
+			return fmt.Sprintf(`
+╔════════════════════════════════════╗
+║      🎰 GACHA RESULT 🎰           ║
+╠════════════════════════════════════╣
+║ You got: %s
+║ %s %s
+║                                    ║
+║ ⚠️ DUPLICATE!                      ║
+║ You already own this item.         ║
+║ You cannot see it twice.           ║
+║                                    ║
+║ Total Pulls: %d                    ║
+║ Pity: %d/%d
+╚════════════════════════════════════╝
+`, accessory, rarity.Stars(), rarity, e.GachaPulls, e.GachaPity, gachaPity), accessory, rarity
+		}
+	}
+
+	e.InvisibleAccessories = append(e.InvisibleAccessories, accessory)
+
+	return fmt.Sprintf(`
+╔════════════════════════════════════╗
+║      🎰 GACHA RESULT 🎰           ║
+╠════════════════════════════════════╣
+║ ✨ NEW ITEM! ✨                    ║
+║                                    ║
+║ You got: %s
+║ %s %s
+║                                    ║
+║ Note: This item is invisible.      ║
+║ Your pet is now wearing it.        ║
+║ You cannot see it.                 ║
+║ But it's there. Trust us.          ║
+║                                    ║
+║ Total Pulls: %d                    ║
+║ Collection: %d/%d                  ║
+║ Pity: %d/%d
+╚════════════════════════════════════╝
+`, accessory, rarity.Stars(), rarity, e.GachaPulls, len(e.InvisibleAccessories), len(invisibleAccessories), e.GachaPity, gachaPity), accessory, rarity
+}
+
+// renderBanner shows the week's banner, the rates, and the pity counter
+func (e *EndgameState) renderBanner(banner gachaBanner, now time.Time) string {
+	featured := accessoryRarity[banner.Featured]
+	var b strings.Builder
+	b.WriteString(fmt.Sprintf("🎏 THIS WEEK'S BANNER: %s (%s)\n\n", banner.Name, banner.Week))
+	b.WriteString(fmt.Sprintf("Featured: %s %s (invisible). %d%% of %s pulls are it.\n\n", featured.Stars(), banner.Featured, gachaFeaturedChance, featured))
+	b.WriteString("Rates:\n")
+	for tier := rarityLegendary; tier >= rarityCommon; tier-- {
+		b.WriteString(fmt.Sprintf("  %-5s %-10s %4.1f%%\n", tier.Stars(), tier, float64(gachaRates[tier])/10))
+	}
+	b.WriteString(fmt.Sprintf("\nPity: %d/%d. A Rare or better is guaranteed within %d pulls.\n", e.GachaPity, gachaPity, gachaPity-e.GachaPity))
+	b.WriteString(fmt.Sprintf("The banner changes in %s. Pulls are free. They were always free.", banner.Ends.Sub(now).Round(time.Hour)))
+	return b.String()
+}
+
+// runGachaCommand pulls, or shows the banner, and tells nearby pets about
+// the banner once a week and about anything Epic or better
+func runGachaCommand(pet *Pet, args string) string {
+	e := pet.Endgame
+	if e == nil {
+		return ""
+	}
+	now := time.Now()
+	banner := currentBanner(now)
+	switch args {
+	case "banner", "rates":
+		announceBanner(pet, banner, "")
+		return e.renderBanner(banner, now)
+	case "":
+	default:
+		return "❓ Usage: gacha [banner]"
+	}
+
+	result, accessory, rarity := e.pullGacha(banner, rand.New(rand.NewSource(now.UnixNano())))
+	brag := ""
+	if rarity >= rarityEpic || accessory == banner.Featured {
+		brag = accessory
+	}
+	announceBanner(pet, banner, brag)
+	return result
+}
+
+// announceBanner puts banner news on the mesh: the banner itself the first
+// time this pet sees it each week, and any pull worth bragging about
+func announceBanner(pet *Pet, banner gachaBanner, pulled string) {
+	e := pet.Endgame
+	if pulled == "" && e.GachaBannerWeek == banner.Week {
+		return
+	}
+	e.GachaBannerWeek = banner.Week
+	if petNetwork != nil {
+		petNetwork.AnnounceBanner(mooc.BannerPayload{
+			Week: banner.Week, Banner: banner.Name, Featured: banner.Featured,
+			PetName: pet.Name, Pulled: pulled,
+		})
+	}
+}
+
+// bannerNews turns banner gossip from the mesh into reactions: each
+// neighbour's lucky pulls, and the banner itself once a week
+func bannerNews(pet *Pet, news []mooc.BannerPayload, now time.Time) []string {
+	e := pet.Endgame
+	if e == nil {
+		return nil
+	}
+	week := currentBanner(now).Week
+	var reactions []string
+	for _, item := range news {
+		if item.Week != week {
+			continue // Last week's news, or a clock that's wrong
+		}
+		if item.Pulled != "" {
+			rarity := accessoryRarity[item.Pulled]
+			reactions = append(reactions, fmt.Sprintf("📣 %s pulled %s %s on the %s banner. Nobody has seen it.", item.PetName, rarity.Stars(), item.Pulled, item.Banner))
+			continue
+		}
+		if e.GachaBannerHeard != week {
+			e.GachaBannerHeard = week
+			reactions = append(reactions, fmt.Sprintf("📣 %s says the %s banner is up. Featured: %s. (gacha banner)", item.PetName, item.Banner, item.Featured))
+		}
+	}
+	return reactions
+}
+
+// applyBannerNews reads banner gossip heard since the last turn
+func applyBannerNews(pet *Pet, now time.Time) []string {
+	if petNetwork == nil {
+		return nil
+	}
+	return bannerNews(pet, petNetwork.TakeBannerNews(), now)
+}
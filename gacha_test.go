@@ -0,0 +1,115 @@
+package main
+
+import (
+	"math/rand"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/tamagotchi/mooc"
+)
+
+func TestCurrentBanner(t *testing.T) {
+	friday := time.Date(2026, time.October, 16, 15, 0, 0, 0, time.UTC)
+	banner := currentBanner(friday)
+	if banner.Week != "2026-W42" {
+		t.Errorf("Expected week 2026-W42, got %s", banner.Week)
+	}
+	if want := time.Date(2026, time.October, 19, 0, 0, 0, 0, time.UTC); !banner.Ends.Equal(want) {
+		t.Errorf("Expected the banner to end %v, got %v", want, banner.Ends)
+	}
+	if accessoryRarity[banner.Featured] < rarityRare {
+		t.Errorf("Expected a Rare or better featured accessory, got %s", banner.Featured)
+	}
+
+	tests := []struct {
+		name string
+		when time.Time
+		same bool
+	}{
+		{"monday morning", time.Date(2026, time.October, 12, 0, 0, 0, 0, time.UTC), true},
+		{"sunday night", time.Date(2026, time.October, 18, 23, 59, 0, 0, time.UTC), true},
+		{"another timezone", friday.In(time.FixedZone("NZDT", 13*3600)), true},
+		{"next week", time.Date(2026, time.October, 19, 0, 0, 0, 0, time.UTC), false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := currentBanner(tt.when); (got == banner) != tt.same {
+				t.Errorf("Expected same banner %v, got %+v against %+v", tt.same, got, banner)
+			}
+		})
+	}
+}
+
+func TestGachaPity(t *testing.T) {
+	e := NewEndgameState()
+	banner := currentBanner(time.Now())
+	r := rand.New(rand.NewSource(1))
+
+	commons, counts := 0, map[gachaRarity]int{}
+	for i := 0; i < 5000; i++ {
+		accessory, rarity := e.rollGacha(banner, r)
+		if accessoryRarity[accessory] != rarity {
+			t.Fatalf("Expected %s to be %s", accessory, rarity)
+		}
+		counts[rarity]++
+		if rarity != rarityCommon {
+			commons = 0
+			continue
+		}
+		commons++
+		if commons >= gachaPity {
+			t.Fatalf("Expected a Rare or better within %d pulls, got %d commons in a row", gachaPity, commons)
+		}
+	}
+	if counts[rarityCommon] < counts[rarityRare] || counts[rarityLegendary] == 0 || counts[rarityLegendary] > counts[rarityEpic] {
+		t.Errorf("Expected rarer tiers to come up less often, got %v", counts)
+	}
+}
+
+func TestRunGachaCommand(t *testing.T) {
+	pet := NewPet("Lucky")
+	tests := []struct {
+		args string
+		want string
+	}{
+		{"banner", "THIS WEEK'S BANNER"},
+		{"", "GACHA RESULT"},
+		{"", "Pity:"},
+		{"spin", "Usage"},
+	}
+	for _, tt := range tests {
+		if got := runGachaCommand(pet, tt.args); !strings.Contains(got, tt.want) {
+			t.Errorf("gacha %q: Expected %q, got %s", tt.args, tt.want, got)
+		}
+	}
+	if pet.Endgame.GachaPulls != 2 || pet.Endgame.GachaBannerWeek != currentBanner(time.Now()).Week {
+		t.Errorf("Expected two pulls and the banner noted, got %d and %q", pet.Endgame.GachaPulls, pet.Endgame.GachaBannerWeek)
+	}
+}
+
+func TestBannerNews(t *testing.T) {
+	now := time.Now()
+	banner := currentBanner(now)
+	pet := NewPet("Lucky")
+	news := []mooc.BannerPayload{
+		{Week: banner.Week, Banner: banner.Name, Featured: banner.Featured, PetName: "Mochi"},
+		{Week: banner.Week, Banner: banner.Name, Featured: banner.Featured, PetName: "Biscuit"},
+		{Week: "1999-W01", Banner: "Old News", PetName: "Stale"},
+		{Week: banner.Week, Banner: banner.Name, PetName: "Biscuit", Pulled: "Invisible Crown"},
+	}
+
+	reactions := bannerNews(pet, news, now)
+	if len(reactions) != 2 {
+		t.Fatalf("Expected the banner once and the brag, got %v", reactions)
+	}
+	if !strings.Contains(reactions[0], "Mochi says the "+banner.Name+" banner is up") {
+		t.Errorf("Expected the banner announced, got %s", reactions[0])
+	}
+	if !strings.Contains(reactions[1], "Biscuit pulled ★★★★ Invisible Crown") {
+		t.Errorf("Expected the brag with its rarity, got %s", reactions[1])
+	}
+	if again := bannerNews(pet, news[:1], now); len(again) != 0 {
+		t.Errorf("Expected the banner announced once a week, got %v", again)
+	}
+}
@@ -0,0 +1,117 @@
+package main
+
+import (
+	"os"
+	"strings"
+	"unicode/utf8"
+)
+
+// emojiTags is the glyph table for TAMAGOTCHI_NO_EMOJI: every emoji the game
+// draws, spelled out as a bracketed tag a braille display or a limited font
+// can show. Narrow symbols drawn inside art keep to one column so frames
+// still line up.
+var emojiTags = map[rune]string{
+	// Moods and the status panel
+	'😊': "[CONTENT]", '😄': "[HAPPY]", '😢': "[SAD]", '😫': "[HUNGRY]", '🤒': "[SICK]",
+	'💩': "[DIRTY]", '💀': "[DEAD]", '🍔': "[FOOD]", '❤': "[HEALTH]", '✨': "[CLEAN]",
+	'🎂': "[AGE]", '🌱': "[STAGE]", '💊': "[MEDICINE]", '🌡': "[WARMTH]", '💭': "[THOUGHT]",
+	'🥚': "[EGG]", '👶': "[BABY]", '🧒': "[CHILD]", '🧑': "[TEEN]", '👨': "[ADULT]",
+	'🐣': "[HATCHED]", '😔': "[PENSIVE]", '😒': "[UNAMUSED]", '😰': "[WORRIED]", '😱': "[SCARED]",
+	'😋': "[YUM]", '😌': "[RELIEVED]", '😶': "[SILENT]", '😵': "[DAZED]", '🤕': "[HURT]",
+	'🤫': "[HUSH]", '🤷': "[SHRUG]", '🙋': "[HELLO]", '👋': "[BYE]", '👂': "[LISTEN]",
+	'👁': "[EYE]", '👀': "[EYES]", '🧘': "[CALM]", '🐾': "[PAWS]", '🧡': "[LOVE]", '💕': "[LOVE]",
+
+	// Results and warnings
+	'✅': "[OK]", '❌': "[NO]", '⚠': "[WARNING]", '❓': "[?]", '❔': "[?]", '🚨': "[ALERT]",
+	'💥': "[CRASH]", '✓': "[OK]", '✗': "[X]", '⏳': "[WAIT]", '⏰': "[TIME]", '⏸': "[PAUSED]",
+	'⏪': "[REWIND]", '🔄': "[SYNC]", '♻': "[RECYCLE]", '🔇': "[MUTED]", '🔊': "[SOUND]",
+	'🔒': "[LOCKED]", '🔓': "[UNLOCKED]", '🔐': "[SECURE]", '🔑': "[KEY]", '🔍': "[SEARCH]",
+	'🔌': "[PLUG]", '🔢': "[NUMBERS]", '🪝': "[HOOK]", '🗑': "[DELETE]", '✏': "[EDIT]",
+	'📝': "[NOTE]", '📌': "[PIN]", '🏳': "[GIVE UP]", '🚩': "[FLAG]", '🏁': "[FINISH]",
+
+	// Menus and endgame boxes
+	'🎮': "[GAME]", '📊': "[STATS]", '📈': "[UP]", '📉': "[DOWN]", '📋': "[LIST]",
+	'📜': "[SCROLL]", '📖': "[BOOK]", '📔': "[DIARY]", '🏆': "[TROPHY]", '🏅': "[MEDAL]", '🎓': "[SCHOOL]",
+	'💼': "[WORK]", '🎰': "[GACHA]", '🛒': "[SHOP]", '🎁': "[GIFT]", '🪙': "[COIN]",
+	'💎': "[GEM]", '⚔': "[BATTLE]", '🏰': "[GUILD]", '🔮': "[PROPHECY]", '🎲': "[DICE]",
+	'🎨': "[PAINT]", '🎭': "[MASK]", '📺': "[AD]", '💾': "[SAVE]", '📂': "[FOLDER]",
+	'🗂': "[FILES]", '📦': "[PACK]", '📤': "[SHARE]", '📣': "[ANNOUNCE]", '🧠': "[MEMORY]",
+	'🕯': "[CANDLE]", '🧳': "[TRAVEL]", '🧭': "[COMPASS]", '🗺': "[MAP]", '📮': "[POSTCARD]",
+	'📬': "[MAIL]", '📪': "[NO MAIL]", '💌': "[LETTER]", '📸': "[PHOTO]", '📷': "[PHOTO]",
+	'🛋': "[ROOM]", '🏠': "[HOME]", '🏡': "[HOME]", '🛁': "[BATH]", '🍅': "[FOCUS]",
+	'🍎': "[CLASSROOM]", '🍱': "[LUNCH]", '☕': "[COFFEE]", '🎉': "[PARTY]", '🎆': "[FIREWORKS]",
+	'🎇': "[SPARKLER]", '🎃': "[PUMPKIN]", '🦃': "[TURKEY]", '🎏': "[STREAMER]", '🕐': "[CLOCK]",
+	'🕰': "[CLOCK]", '🩺': "[CHECKUP]", '🚗': "[CAR]", '📞': "[CALL]", '🕵': "[SPY]",
+	'💫': "[DIZZY]", '🔥': "[FIRE]", '🧣': "[SCARF]", '🧊': "[ICE]", '🤝': "[SHARED]",
+	'🤲': "[HOLD]", '🎶': "[MUSIC]", '🪟': "[WINDOW]", '🛡': "[SHIELD]", '🦠': "[GERM]",
+	'🥣': "[KIBBLE]", '🍏': "[APPLE]", '🐟': "[FISH]", '🍜': "[NOODLES]", '🍰': "[CAKE]",
+	'🥦': "[BROCCOLI]", '🐛': "[BUGS]", '😍': "[LOVES IT]", '😖': "[YUCK]", '💡': "[LEARNED]",
+	'🧬': "[INHERITED]", '🍽': "[MEALS]", '😐': "[NEUTRAL]", '🌀': "[MAZE]", '🚪': "[DOOR]", '⌨': "[KEYBOARD]",
+	'🍒': "[CHERRY]", '🍋': "[LEMON]", '🔔': "[BELL]", '⭐': "[STAR]", '🛑': "[LIMIT]",
+	'🪫': "[LOW BATTERY]", '🖥': "[COMPUTER]", '📰': "[FEED]", '📧': "[EMAIL]", '📅': "[CALENDAR]", '🌳': "[TREE]", '💪': "[STRONG]",
+	'🌙': "[DREAM]", '⛏': "[DIG]",
+
+	// Weather and the world outside
+	'☀': "[SUN]", '⛅': "[CLOUDY]", '☁': "[CLOUD]", '🌧': "[RAIN]", '🌫': "[FOG]",
+	'🌬': "[WIND]", '🌈': "[RAINBOW]", '🌌': "[STARS]", '🌑': "[NEW MOON]", '🌿': "[HERB]",
+	'🌼': "[FLOWER]", '🍁': "[LEAF]", '☘': "[SHAMROCK]", '🌾': "[HARVEST]",
+
+	// The mesh
+	'📡': "[NETWORK]", '🌐': "[MESH]", '💬': "[MESSAGE]", '👥': "[FRIENDS]",
+
+	// Narrow symbols, some drawn inside art
+	'★': "*", '✦': "*", '✧': "+", '♥': "*", '♣': "%", '❄': "*",
+}
+
+// untagged stands in for an emoji the table doesn't know, like one in a
+// name heard on the mesh
+const untagged = "[*]"
+
+// noEmojiEnv asks for emoji spelled out everywhere the game writes
+const noEmojiEnv = "TAMAGOTCHI_NO_EMOJI"
+
+// consoleText spells emoji out when noEmojiEnv asks, for output that
+// doesn't go through a uiConfig: subcommands, warnings and crashes
+func consoleText(s string) string {
+	if os.Getenv(noEmojiEnv) == "" {
+		return s
+	}
+	return emojiFree(s)
+}
+
+// emojiFree spells out every emoji in s with its tag. Variation selectors,
+// joiners, and skin tones go too, so a joined emoji becomes a tag per part.
+func emojiFree(s string) string {
+	if isASCII(s) {
+		return s
+	}
+	var b strings.Builder
+	b.Grow(len(s))
+	for _, r := range s {
+		switch {
+		case r == '️' || r == '︎' || r == '‍' || (r >= 0x1F3FB && r <= 0x1F3FF):
+			// Part of the emoji before it
+		case emojiTags[r] != "":
+			b.WriteString(emojiTags[r])
+		case isEmoji(r):
+			b.WriteString(untagged)
+		default:
+			b.WriteRune(r)
+		}
+	}
+	return b.String()
+}
+
+// isEmoji reports whether a rune is in the pictograph blocks
+func isEmoji(r rune) bool {
+	return (r >= 0x1F000 && r <= 0x1FAFF) || (r >= 0x2600 && r <= 0x27BF)
+}
+
+func isASCII(s string) bool {
+	for i := 0; i < len(s); i++ {
+		if s[i] >= utf8.RuneSelf {
+			return false
+		}
+	}
+	return true
+}
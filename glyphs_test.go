@@ -0,0 +1,78 @@
+package main
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestEmojiFree(t *testing.T) {
+	tests := []struct {
+		in   string
+		want string
+	}{
+		{"plain text", "plain text"},
+		{"Mood: 😫", "Mood: [HUNGRY]"},
+		{"⚠️  Careful", "[WARNING]  Careful"},
+		{"🤒 and 💀", "[SICK] and [DEAD]"},
+		{"👋🏽 hi", "[BYE] hi"},
+		{"👨‍🦰", "[ADULT][*]"},
+		{"║ ★ ok ║", "║ * ok ║"},
+	}
+	for _, tt := range tests {
+		if got := emojiFree(tt.in); got != tt.want {
+			t.Errorf("Expected %q, got %q", tt.want, got)
+		}
+	}
+}
+
+func TestNoEmojiModeCoversDrawnPanels(t *testing.T) {
+	ui := newUIConfig()
+	ui.noEmoji = true
+	ui.reducedMotion = true
+	pet := NewPet("Tamago")
+	pet.IsSick = true
+
+	panels := map[string]string{
+		"scene": renderScene(pet, ui),
+		"menu":  renderMenu(newDefaultRegistry(), sectionMain),
+		"meta":  pet.Endgame.GetMetaStats(pet.metaSnapshot(time.Now()), nil),
+		"ad":    ShowFakeAd(),
+	}
+	for name, panel := range panels {
+		out := ui.text(panel)
+		for _, r := range out {
+			if isEmoji(r) {
+				t.Errorf("%s: Expected no emoji, found %q", name, r)
+				break
+			}
+		}
+		if strings.Contains(panel, "╔") {
+			t.Run(name, func(t *testing.T) { assertFramed(t, out) })
+		}
+	}
+	if out := ui.text(renderScene(pet, ui)); !strings.Contains(out, "[SICK]") {
+		t.Errorf("Expected the sick mood spelled out, got:\n%s", out)
+	}
+}
+
+func TestNoEmojiModeCoversSubcommands(t *testing.T) {
+	t.Chdir(t.TempDir())
+	t.Setenv(noEmojiEnv, "1")
+	pet := NewPet("Quiet")
+	pet.SaveFilePath = saveFile
+	if err := pet.writeStatusLine(); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, args := range [][]string{{"classroom"}, {"peek"}} {
+		out := captureStdout(t, func() {
+			if _, err := runSubcommand(args); err != nil {
+				t.Errorf("Expected %v to run, got %v", args, err)
+			}
+		})
+		if out == "" || strings.ContainsFunc(out, isEmoji) {
+			t.Errorf("Expected %v spelled out without emoji, got %q", args, out)
+		}
+	}
+}
@@ -0,0 +1,228 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+const (
+	// careSampleInterval is how often stats are written to the care log
+	careSampleInterval = 30 * time.Minute
+
+	// careLogRetention is how far back the care log reaches
+	careLogRetention = 7 * 24 * time.Hour
+)
+
+// CareSample is one entry in the care log
+type CareSample struct {
+	Time        time.Time `json:"time"`
+	Hunger      int       `json:"hunger"`
+	Happiness   int       `json:"happiness"`
+	Health      int       `json:"health"`
+	Cleanliness int       `json:"cleanliness"`
+	Friends     int       `json:"friends"` // Mesh friends online at the time
+}
+
+// graphWindow is a span of the care log drawn as one sparkline per stat
+type graphWindow struct {
+	Label   string
+	Span    time.Duration
+	Buckets int
+	Step    string // How much time one block covers, for the legend
+}
+
+// graphWindows are the spans `graphs` understands
+var graphWindows = map[string]graphWindow{
+	"24h": {Label: "Last 24 hours", Span: 24 * time.Hour, Buckets: 24, Step: "hour"},
+	"7d":  {Label: "Last 7 days", Span: 7 * 24 * time.Hour, Buckets: 28, Step: "6 hours"},
+}
+
+// graphSeries names a line on the dashboard and how to read it from a sample
+type graphSeries struct {
+	Label string
+	Value func(CareSample) int
+}
+
+// careSeries are the stat lines; hunger is flipped like the status bars so up is always good
+var careSeries = []graphSeries{
+	{"🍔 Hunger     ", func(s CareSample) int { return 100 - s.Hunger }},
+	{"😊 Happiness  ", func(s CareSample) int { return s.Happiness }},
+	{"❤️  Health    ", func(s CareSample) int { return s.Health }},
+	{"✨ Cleanliness", func(s CareSample) int { return s.Cleanliness }},
+}
+
+// graphNoData marks a stretch with no samples, usually because the game was closed
+const graphNoData = '·'
+
+// onlineFriends returns how many mesh friends are online right now
+func onlineFriends() int {
+	if petNetwork == nil {
+		return 0
+	}
+	return petNetwork.GetOnlineFriendCount()
+}
+
+// RecordCareSample adds the current stats to the care log at most once per
+// interval, forgetting samples past retention. It reports whether a sample was added.
+func (p *Pet) RecordCareSample(now time.Time, friends int) bool {
+	if p.Stage == Dead {
+		return false
+	}
+	if n := len(p.CareLog); n > 0 && now.Sub(p.CareLog[n-1].Time) < careSampleInterval {
+		return false
+	}
+
+	kept := p.CareLog[:0]
+	for _, sample := range p.CareLog {
+		if now.Sub(sample.Time) < careLogRetention {
+			kept = append(kept, sample)
+		}
+	}
+	p.CareLog = append(kept, CareSample{
+		Time:        now,
+		Hunger:      p.Hunger,
+		Happiness:   p.Happiness,
+		Health:      p.Health,
+		Cleanliness: p.Cleanliness,
+		Friends:     friends,
+	})
+	return true
+}
+
+// bucketSamples averages a series into the window's buckets, oldest first.
+// Buckets without samples are -1.
+func bucketSamples(samples []CareSample, now time.Time, window graphWindow, value func(CareSample) int) []int {
+	sums := make([]int, window.Buckets)
+	counts := make([]int, window.Buckets)
+	start := now.Add(-window.Span)
+	width := window.Span / time.Duration(window.Buckets)
+
+	for _, sample := range samples {
+		if sample.Time.Before(start) || sample.Time.After(now) {
+			continue
+		}
+		i := int(sample.Time.Sub(start) / width)
+		if i >= window.Buckets {
+			i = window.Buckets - 1
+		}
+		sums[i] += value(sample)
+		counts[i]++
+	}
+
+	buckets := make([]int, window.Buckets)
+	for i := range buckets {
+		buckets[i] = -1
+		if counts[i] > 0 {
+			buckets[i] = sums[i] / counts[i]
+		}
+	}
+	return buckets
+}
+
+// renderSparkline draws bucket values scaled to peak. Every value, even zero,
+// gets at least the lowest block so gaps in the log stand out.
+func renderSparkline(buckets []int, peak int) string {
+	var b strings.Builder
+	for _, value := range buckets {
+		if value < 0 {
+			b.WriteRune(graphNoData)
+			continue
+		}
+		level := 1
+		if peak > 0 {
+			level = 1 + min(value, peak)*(len(sparkBlocks)-2)/peak
+		}
+		b.WriteRune(sparkBlocks[level])
+	}
+	return b.String()
+}
+
+// seriesTrend returns the latest value, range, and direction of a series;
+// direction is "" when there is no data at all
+func seriesTrend(buckets []int) (last, low, high int, direction string) {
+	first := -1
+	for _, value := range buckets {
+		if value < 0 {
+			continue
+		}
+		if first < 0 {
+			first, low, high = value, value, value
+		}
+		last = value
+		low = min(low, value)
+		high = max(high, value)
+	}
+
+	switch {
+	case first < 0:
+		return 0, 0, 0, ""
+	case last-first >= 10:
+		return last, low, high, "rising"
+	case first-last >= 10:
+		return last, low, high, "falling"
+	}
+	return last, low, high, "steady"
+}
+
+// trendSummary describes a series in words for screen readers
+func trendSummary(buckets []int) string {
+	last, low, high, direction := seriesTrend(buckets)
+	if direction == "" {
+		return "no data"
+	}
+	return fmt.Sprintf("now %d, range %d-%d, %s", last, low, high, direction)
+}
+
+// trendArrows are the one-glyph versions of each direction
+var trendArrows = map[string]string{"": " ", "rising": "↗", "falling": "↘", "steady": "→"}
+
+// trendArrow returns the arrow for a series' direction
+func trendArrow(buckets []int) string {
+	_, _, _, direction := seriesTrend(buckets)
+	return trendArrows[direction]
+}
+
+// renderGraphs draws the stats dashboard for a window of the care log
+func (ui *uiConfig) renderGraphs(pet *Pet, window graphWindow, now time.Time) string {
+	if len(pet.CareLog) == 0 {
+		return fmt.Sprintf("📈 No care history yet. %s's stats are logged every half hour while the game is open.", pet.Name)
+	}
+
+	network := bucketSamples(pet.CareLog, now, window, func(s CareSample) int { return s.Friends })
+	peakFriends := 0
+	for _, friends := range network {
+		peakFriends = max(peakFriends, friends)
+	}
+
+	var b strings.Builder
+	b.WriteString(fmt.Sprintf("📈 %s — %s\n\n", pet.Name, window.Label))
+	for _, series := range careSeries {
+		buckets := bucketSamples(pet.CareLog, now, window, series.Value)
+		if ui.screenReader {
+			b.WriteString(fmt.Sprintf("%s: %s\n", strings.TrimSpace(series.Label), trendSummary(buckets)))
+			continue
+		}
+		b.WriteString(fmt.Sprintf("%s %s %s\n", series.Label, renderSparkline(buckets, 100), trendArrow(buckets)))
+	}
+
+	if ui.screenReader {
+		b.WriteString(fmt.Sprintf("Friends online: %s\n", trendSummary(network)))
+	} else {
+		b.WriteString(fmt.Sprintf("\n🌐 Friends     %s %s (peak %d)\n", renderSparkline(network, peakFriends), trendArrow(network), peakFriends))
+		b.WriteString(fmt.Sprintf("\nOldest → newest, one block per %s. %c = no data (game closed)", window.Step, graphNoData))
+	}
+	return strings.TrimRight(b.String(), "\n")
+}
+
+// runGraphsCommand handles `graphs [24h|7d]`
+func runGraphsCommand(pet *Pet, ui *uiConfig, args string) string {
+	if args == "" {
+		args = "24h"
+	}
+	window, ok := graphWindows[args]
+	if !ok {
+		return "❓ Usage: graphs [24h|7d]"
+	}
+	return ui.renderGraphs(pet, window, time.Now())
+}
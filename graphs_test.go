@@ -0,0 +1,112 @@
+package main
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestRecordCareSampleThrottlesAndPrunes(t *testing.T) {
+	pet := NewPet("Test")
+	start := time.Now()
+
+	if !pet.RecordCareSample(start, 2) {
+		t.Fatal("Expected the first sample to be recorded")
+	}
+	if pet.RecordCareSample(start.Add(10*time.Minute), 2) {
+		t.Error("Expected samples inside the interval to be skipped")
+	}
+	if !pet.RecordCareSample(start.Add(careSampleInterval), 3) {
+		t.Error("Expected a sample once the interval passed")
+	}
+
+	pet.RecordCareSample(start.Add(careLogRetention+time.Minute), 0)
+	if len(pet.CareLog) != 2 || pet.CareLog[0].Friends != 3 {
+		t.Errorf("Expected the oldest sample to be pruned, got %+v", pet.CareLog)
+	}
+}
+
+func TestBucketSamples(t *testing.T) {
+	now := time.Now()
+	samples := []CareSample{
+		{Time: now.Add(-23*time.Hour - 30*time.Minute), Happiness: 20},
+		{Time: now.Add(-23*time.Hour - 10*time.Minute), Happiness: 40},
+		{Time: now.Add(-30 * time.Minute), Happiness: 90},
+		{Time: now.Add(-48 * time.Hour), Happiness: 5}, // outside the window
+	}
+
+	buckets := bucketSamples(samples, now, graphWindows["24h"], func(s CareSample) int { return s.Happiness })
+	if len(buckets) != 24 {
+		t.Fatalf("Expected 24 buckets, got %d", len(buckets))
+	}
+	if buckets[0] != 30 {
+		t.Errorf("Expected the first hour to average 30, got %d", buckets[0])
+	}
+	if buckets[12] != -1 {
+		t.Errorf("Expected an empty bucket to be -1, got %d", buckets[12])
+	}
+	if buckets[23] != 90 {
+		t.Errorf("Expected the last hour to be 90, got %d", buckets[23])
+	}
+}
+
+func TestRenderSparkline(t *testing.T) {
+	tests := []struct {
+		buckets []int
+		peak    int
+		want    string
+	}{
+		{[]int{0, 50, 100}, 100, "▁▄█"},
+		{[]int{-1, 100, -1}, 100, "·█·"},
+		{[]int{0, 0}, 0, "▁▁"},
+		{[]int{1, 3}, 3, "▃█"},
+	}
+	for _, tt := range tests {
+		if got := renderSparkline(tt.buckets, tt.peak); got != tt.want {
+			t.Errorf("renderSparkline(%v, %d): expected %q, got %q", tt.buckets, tt.peak, tt.want, got)
+		}
+	}
+}
+
+func TestTrendSummary(t *testing.T) {
+	tests := []struct {
+		buckets []int
+		want    string
+	}{
+		{[]int{-1, -1}, "no data"},
+		{[]int{20, -1, 60}, "now 60, range 20-60, rising"},
+		{[]int{80, 10, 75}, "now 75, range 10-80, steady"},
+		{[]int{90, 50}, "now 50, range 50-90, falling"},
+	}
+	for _, tt := range tests {
+		if got := trendSummary(tt.buckets); got != tt.want {
+			t.Errorf("trendSummary(%v): expected %q, got %q", tt.buckets, tt.want, got)
+		}
+	}
+}
+
+func TestRenderGraphs(t *testing.T) {
+	pet := NewPet("Graphy")
+	now := time.Now()
+	for i := 0; i < 10; i++ {
+		pet.Hunger = i * 10
+		pet.RecordCareSample(now.Add(time.Duration(i-10)*time.Hour), i%3)
+	}
+
+	ui := &uiConfig{}
+	out := ui.renderGraphs(pet, graphWindows["24h"], now)
+	for _, want := range []string{"Graphy — Last 24 hours", "Hunger", "↘", "Friends", "peak 2", "one block per hour"} {
+		if !strings.Contains(out, want) {
+			t.Errorf("Expected dashboard to contain %q, got:\n%s", want, out)
+		}
+	}
+
+	ui.screenReader = true
+	if out := ui.renderGraphs(pet, graphWindows["24h"], now); !strings.Contains(out, "Hunger: now 10, range 10-100, falling") || strings.ContainsRune(out, '▁') {
+		t.Errorf("Expected a spoken summary without sparklines, got:\n%s", out)
+	}
+
+	if msg := runGraphsCommand(pet, ui, "1y"); !strings.Contains(msg, "Usage") {
+		t.Errorf("Expected usage for an unknown window, got %s", msg)
+	}
+}
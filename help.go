@@ -0,0 +1,106 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Menu sections a command can appear in
+const (
+	sectionMain    = "Commands"
+	sectionEndgame = "Endgame Commands"
+)
+
+// menuRule frames the command menus
+const menuRule = "━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━"
+
+// CommandDoc documents a game command for the menu and `help <command>`
+type CommandDoc struct {
+	Name         string   // Canonical command name
+	Aliases      []string // Other words that run the command
+	Section      string   // Menu the command is listed in
+	Summary      string   // One-line menu description
+	Details      string   // Longer explanation for `help <command>`
+	Examples     []string // Example invocations
+	Achievements []string // Achievement IDs the command can unlock
+	Lore         string   // In-universe note
+}
+
+// renderMenu lists every registered command in a section
+func renderMenu(commands *commandRegistry, section string) string {
+	width := 0
+	for _, cmd := range commands.Commands() {
+		if doc := cmd.Help(); doc.Section == section && len(doc.Name) > width {
+			width = len(doc.Name)
+		}
+	}
+
+	var b strings.Builder
+	b.WriteString("\n" + menuRule + "\n" + section + ":\n")
+	for _, cmd := range commands.Commands() {
+		if doc := cmd.Help(); doc.Section == section {
+			b.WriteString(fmt.Sprintf("  %-*s - %s\n", width, doc.Name, doc.Summary))
+		}
+	}
+	b.WriteString(menuRule + "\n")
+	return b.String()
+}
+
+// achievementLabel names an achievement without spoiling locked secrets
+func achievementLabel(id string, e *EndgameState) string {
+	for _, a := range allAchievements {
+		if a.ID != id {
+			continue
+		}
+		if a.Secret && (e == nil || !hasAchievement(e, id)) {
+			return "??? (secret)"
+		}
+		if a.Impossible {
+			return a.Name + " (impossible)"
+		}
+		return a.Name
+	}
+	return id
+}
+
+// hasAchievement reports whether id has been unlocked
+func hasAchievement(e *EndgameState, id string) bool {
+	for _, unlocked := range e.UnlockedAchievements {
+		if unlocked == id {
+			return true
+		}
+	}
+	return false
+}
+
+// commandHelp renders the detailed help page for a command
+func commandHelp(commands *commandRegistry, name string, e *EndgameState) string {
+	cmd := commands.Lookup(name)
+	if cmd == nil {
+		return fmt.Sprintf("❓ No help for '%s'. Type 'help' to see available commands.", name)
+	}
+	doc := cmd.Help()
+
+	var b strings.Builder
+	b.WriteString(fmt.Sprintf("📖 %s — %s\n\n%s\n", doc.Name, doc.Summary, doc.Details))
+	if len(doc.Aliases) > 0 {
+		b.WriteString(fmt.Sprintf("\nAliases: %s\n", strings.Join(doc.Aliases, ", ")))
+	}
+	if len(doc.Examples) > 0 {
+		b.WriteString("\nExamples:\n")
+		for _, example := range doc.Examples {
+			b.WriteString(fmt.Sprintf("  > %s\n", example))
+		}
+	}
+	if len(doc.Achievements) > 0 {
+		labels := make([]string, 0, len(doc.Achievements))
+		for _, id := range doc.Achievements {
+			labels = append(labels, achievementLabel(id, e))
+		}
+		b.WriteString(fmt.Sprintf("\nRelated achievements: %s\n", strings.Join(labels, ", ")))
+	}
+	if doc.Lore != "" {
+		b.WriteString(fmt.Sprintf("\n📜 %s\n", doc.Lore))
+	}
+	return strings.TrimRight(b.String(), "\n")
+}
@@ -0,0 +1,80 @@
+package main
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestCommandDocsAreComplete(t *testing.T) {
+	for _, cmd := range newDefaultRegistry().Commands() {
+		doc := cmd.Help()
+		if doc.Summary == "" || doc.Details == "" {
+			t.Errorf("Command %s is missing a summary or details", doc.Name)
+		}
+		if doc.Section != sectionMain && doc.Section != sectionEndgame {
+			t.Errorf("Command %s has unknown section %q", doc.Name, doc.Section)
+		}
+	}
+}
+
+func TestCommandDocAchievementsExist(t *testing.T) {
+	known := make(map[string]bool)
+	for _, a := range allAchievements {
+		known[a.ID] = true
+	}
+	for _, cmd := range newDefaultRegistry().Commands() {
+		doc := cmd.Help()
+		for _, id := range doc.Achievements {
+			if !known[id] {
+				t.Errorf("Command %s references unknown achievement %s", doc.Name, id)
+			}
+		}
+	}
+}
+
+func TestRenderMenuListsEverySectionCommand(t *testing.T) {
+	commands := newDefaultRegistry()
+	main := renderMenu(commands, sectionMain)
+	more := renderMenu(commands, sectionEndgame)
+	for _, cmd := range commands.Commands() {
+		doc := cmd.Help()
+		menu := main
+		if doc.Section == sectionEndgame {
+			menu = more
+		}
+		if !strings.Contains(menu, doc.Summary) {
+			t.Errorf("Expected %s menu to list %s", doc.Section, doc.Name)
+		}
+	}
+	if strings.Contains(main, "guild") {
+		t.Error("Expected endgame commands to stay out of the main menu")
+	}
+}
+
+func TestCommandHelp(t *testing.T) {
+	commands := newDefaultRegistry()
+	e := NewEndgameState()
+	help := commandHelp(commands, "f", e)
+	for _, want := range []string{"📖 feed", "Aliases: f", "> feed", "First Meal", "📜"} {
+		if !strings.Contains(help, want) {
+			t.Errorf("Expected help to contain %q, got %s", want, help)
+		}
+	}
+
+	if help := commandHelp(commands, "nope", e); !strings.Contains(help, "No help for 'nope'") {
+		t.Errorf("Expected missing help message, got %s", help)
+	}
+}
+
+func TestCommandHelpHidesLockedSecrets(t *testing.T) {
+	commands := newDefaultRegistry()
+	e := NewEndgameState()
+	if help := commandHelp(commands, "pet", e); !strings.Contains(help, "??? (secret)") || strings.Contains(help, "The Number") {
+		t.Errorf("Expected locked secret to stay hidden, got %s", help)
+	}
+
+	e.UnlockAchievement("pet_17")
+	if help := commandHelp(commands, "pet", e); !strings.Contains(help, "The Number") {
+		t.Errorf("Expected unlocked secret to be named, got %s", help)
+	}
+}
@@ -0,0 +1,111 @@
+package main
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/tamagotchi/mooc"
+)
+
+// historyLength is how many moments the history shows
+const historyLength = 20
+
+// historyEntry is one line of the timeline
+type historyEntry struct {
+	At   time.Time
+	Line string
+}
+
+// careHistory is the pet's own life: its birth, its photos, and its trips
+func careHistory(pet *Pet) []historyEntry {
+	entries := []historyEntry{{pet.BirthTime, fmt.Sprintf("🥚 %s is laid", pet.Name)}}
+	if pet.Album != nil {
+		for _, photo := range pet.Album.Photos {
+			entries = append(entries, historyEntry{photo.Taken, "📸 " + photo.Caption})
+		}
+	}
+	if pet.Travel != nil {
+		for _, card := range pet.Travel.Journal {
+			entries = append(entries, historyEntry{card.Returned, fmt.Sprintf("🧳 Back from %s", card.Place)})
+		}
+	}
+	if pet.Checkups != nil {
+		for _, at := range pet.Checkups.History {
+			entries = append(entries, historyEntry{at, "🩺 Has a checkup"})
+		}
+		for _, at := range pet.Checkups.Sicknesses {
+			entries = append(entries, historyEntry{at, "🤒 Falls sick"})
+		}
+		for _, e := range pet.Checkups.Exposures {
+			line := fmt.Sprintf("🦠 Catches %s from %s", e.Strain, e.From)
+			if e.Resisted {
+				line = fmt.Sprintf("🛡️ Shrugs off %s from %s", e.Strain, e.From)
+			}
+			entries = append(entries, historyEntry{e.At, line})
+		}
+	}
+	return entries
+}
+
+// networkHistory is the pet's life on the mesh. Names come from other
+// pets, so they're stripped of anything that could move the cursor.
+func networkHistory(events []mooc.NetworkEvent) []historyEntry {
+	var entries []historyEntry
+	for _, event := range events {
+		name := plainText(event.Name)
+		var line string
+		switch event.Kind {
+		case mooc.EventPeerMet:
+			line = fmt.Sprintf("✨ Meets %s on the mesh", name)
+		case mooc.EventDreamShared:
+			line = fmt.Sprintf("💭 Shares a dream with %s", name)
+		case mooc.EventDeathWitnessed:
+			line = fmt.Sprintf("🕯️ Hears that %s has died", name)
+		case mooc.EventConsensus:
+			line = fmt.Sprintf("🌐 Joins every pet for %s", name)
+		case mooc.EventMelancholy:
+			line = fmt.Sprintf("🌧️ Hears that %s is feeling melancholy", name)
+		default:
+			continue
+		}
+		entries = append(entries, historyEntry{event.At, line})
+	}
+	return entries
+}
+
+// renderHistory interleaves care life and network life, showing the most
+// recent moments oldest first
+func renderHistory(pet *Pet, events []mooc.NetworkEvent) string {
+	entries := append(careHistory(pet), networkHistory(events)...)
+	sort.SliceStable(entries, func(i, j int) bool { return entries[i].At.Before(entries[j].At) })
+
+	var b strings.Builder
+	b.WriteString(fmt.Sprintf("📜 %s'S HISTORY\n\n", strings.ToUpper(pet.Name)))
+	if len(entries) > historyLength {
+		b.WriteString(fmt.Sprintf("  … %d earlier moments\n", len(entries)-historyLength))
+		entries = entries[len(entries)-historyLength:]
+	}
+	for _, entry := range entries {
+		b.WriteString(fmt.Sprintf("  %s  %s\n", entry.At.Format("Jan 2 15:04"), entry.Line))
+	}
+	return strings.TrimRight(b.String(), "\n")
+}
+
+// runHistoryCommand shows the pet's timeline
+func runHistoryCommand(pet *Pet) string {
+	var events []mooc.NetworkEvent
+	if petNetwork != nil {
+		events = petNetwork.History()
+	}
+	return renderHistory(pet, events)
+}
+
+// recordConsensus remembers that the pet ran a community event with every
+// other pet
+func recordConsensus(event CommunityEvent) {
+	if petNetwork != nil {
+		petNetwork.RecordEvent(mooc.EventConsensus, event.Name, event.At)
+	}
+}
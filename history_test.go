@@ -0,0 +1,52 @@
+package main
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/tamagotchi/mooc"
+)
+
+func TestRenderHistory(t *testing.T) {
+	born := time.Date(2026, 10, 1, 9, 0, 0, 0, time.UTC)
+	pet := NewPet("Tama")
+	pet.BirthTime = born
+	pet.Album = &AlbumState{Photos: []Photo{{Moment: "hatch", Caption: "Tama hatches", Taken: born.Add(2 * time.Hour)}}}
+	pet.Travel = &TravelState{Journal: []TravelPostcard{{Place: "the Lint Forest", Returned: born.Add(72 * time.Hour)}}}
+	events := []mooc.NetworkEvent{
+		{Kind: mooc.EventPeerMet, Name: "Mochi\x1b", At: born.Add(24 * time.Hour)},
+		{Kind: mooc.EventDeathWitnessed, Name: "Pixel", At: born.Add(96 * time.Hour)},
+		{Kind: "unknown", Name: "Bean", At: born.Add(time.Hour)},
+	}
+
+	got := renderHistory(pet, events)
+	order := []string{"Tama is laid", "Tama hatches", "Meets Mochi on the mesh", "Back from the Lint Forest", "Hears that Pixel has died"}
+	last := -1
+	for _, want := range order {
+		i := strings.Index(got, want)
+		if i <= last {
+			t.Fatalf("Expected %q after the moments before it in:\n%s", want, got)
+		}
+		last = i
+	}
+	if strings.Contains(got, "\x1b") || strings.Contains(got, "Bean") {
+		t.Errorf("Expected escapes and unknown events left out, got:\n%s", got)
+	}
+}
+
+func TestRenderHistoryKeepsTheLatest(t *testing.T) {
+	pet := NewPet("Tama")
+	var events []mooc.NetworkEvent
+	for i := 0; i < historyLength+5; i++ {
+		events = append(events, mooc.NetworkEvent{Kind: mooc.EventDreamShared, Name: "Tama", At: pet.BirthTime.Add(time.Duration(i+1) * time.Hour)})
+	}
+
+	got := renderHistory(pet, events)
+	if !strings.Contains(got, "6 earlier moments") {
+		t.Errorf("Expected the older moments counted, got:\n%s", got)
+	}
+	if lines := strings.Count(got, "Shares a dream"); lines != historyLength {
+		t.Errorf("Expected %d moments shown, got %d", historyLength, lines)
+	}
+}
@@ -0,0 +1,252 @@
+package main
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+const (
+	// hookQueueFile collects events written by git hooks until the game drains them
+	hookQueueFile = "tamagotchi_events.jsonl"
+
+	// hookMarker identifies hook scripts we installed (and may safely overwrite)
+	hookMarker = "# Installed by tamagotchi hook install."
+)
+
+// Hook event types
+const (
+	HookEventCommit    = "commit"
+	HookEventTestPass  = "test-pass"
+	HookEventForcePush = "force-push"
+)
+
+// HookEvent is a single git or test event queued by an installed hook
+type HookEvent struct {
+	Type   string    `json:"type"`
+	Branch string    `json:"branch,omitempty"`
+	Time   time.Time `json:"time"`
+}
+
+// forcePushFear is acquired the first time the pet witnesses rewritten history
+var forcePushFear = Fear{
+	Name:        "Forcepushophobia",
+	Description: "Terrified of rewritten history",
+	Trigger:     "force",
+}
+
+// hookHome returns the directory holding the pet's save and event queue
+func hookHome() string {
+	if home := os.Getenv("TAMAGOTCHI_HOME"); home != "" {
+		return home
+	}
+	return "."
+}
+
+// hookQueuePath returns the path of the hook event queue
+func hookQueuePath() string {
+	return filepath.Join(hookHome(), hookQueueFile)
+}
+
+// appendHookEvent appends an event to the queue file
+func appendHookEvent(path string, event HookEvent) error {
+	data, err := json.Marshal(event)
+	if err != nil {
+		return fmt.Errorf("failed to marshal hook event: %w", err)
+	}
+
+	file, err := os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
+	if err != nil {
+		return fmt.Errorf("failed to open event queue: %w", err)
+	}
+	defer file.Close()
+
+	if _, err := file.Write(append(data, '\n')); err != nil {
+		return fmt.Errorf("failed to write event queue: %w", err)
+	}
+	return nil
+}
+
+// drainHookEvents reads and removes all queued events.
+// The queue is renamed before reading so hooks firing meanwhile start a fresh file.
+func drainHookEvents(path string) ([]HookEvent, error) {
+	processing := path + ".processing"
+	if err := os.Rename(path, processing); err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to claim event queue: %w", err)
+	}
+	defer os.Remove(processing)
+
+	file, err := os.Open(processing)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read event queue: %w", err)
+	}
+	defer file.Close()
+
+	events := make([]HookEvent, 0)
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		var event HookEvent
+		if err := json.Unmarshal(scanner.Bytes(), &event); err != nil {
+			continue // Garbled line, the pet shrugs it off
+		}
+		events = append(events, event)
+	}
+	return events, scanner.Err()
+}
+
+// ApplyHookEvent lets the pet react to a git or test event and returns its reaction
+func (p *Pet) ApplyHookEvent(event HookEvent) string {
+	if p.Stage == Dead || p.Stage == Egg {
+		return ""
+	}
+
+	switch event.Type {
+	case HookEventCommit:
+		if p.Absurd != nil && event.Branch != "" {
+			p.Absurd.OverhearBranch(event.Branch)
+		}
+		return "📝 Your pet heard a commit land. It pretends it didn't."
+
+	case HookEventTestPass:
+		p.Happiness = clamp(p.Happiness+3, 0, 100)
+		return "✅ Tests passed somewhere. Your pet does a tiny victory wiggle."
+
+	case HookEventForcePush:
+		if p.Absurd != nil {
+			if event.Branch != "" {
+				p.Absurd.OverhearBranch(event.Branch)
+			}
+			if p.Absurd.AcquireFear(forcePushFear) {
+				return fmt.Sprintf("😱 History was rewritten. Your pet has developed %s.", forcePushFear.Name)
+			}
+		}
+		return "😰 Another force-push. Your pet hides under the terminal."
+	}
+
+	return ""
+}
+
+// applyHookEvents drains the event queue and returns the pet's reactions
+func applyHookEvents(pet *Pet) []string {
+	events, err := drainHookEvents(hookQueuePath())
+	if err != nil {
+		return nil
+	}
+
+	reactions := make([]string, 0, len(events))
+	for _, event := range events {
+		if reaction := pet.ApplyHookEvent(event); reaction != "" {
+			reactions = append(reactions, reaction)
+		}
+	}
+	return reactions
+}
+
+// shellQuote wraps a string in single quotes for use in a POSIX shell script
+func shellQuote(s string) string {
+	return "'" + strings.ReplaceAll(s, "'", `'\''`) + "'"
+}
+
+// hookScripts returns the hook scripts to install, keyed by hook name
+func hookScripts(home, exe string) map[string]string {
+	invoke := fmt.Sprintf("TAMAGOTCHI_HOME=%s %s hook event", shellQuote(home), shellQuote(exe))
+
+	return map[string]string{
+		"post-commit": fmt.Sprintf(`#!/bin/sh
+%s Safe to delete.
+%s %s "$(git rev-parse --abbrev-ref HEAD 2>/dev/null)" >/dev/null 2>&1 || true
+`, hookMarker, invoke, HookEventCommit),
+
+		"pre-push": fmt.Sprintf(`#!/bin/sh
+%s Safe to delete.
+zero=0000000000000000000000000000000000000000
+while read local_ref local_sha remote_ref remote_sha; do
+	if [ "$remote_sha" != "$zero" ] && [ "$local_sha" != "$zero" ] &&
+		! git merge-base --is-ancestor "$remote_sha" "$local_sha" 2>/dev/null; then
+		%s %s "${remote_ref#refs/heads/}" >/dev/null 2>&1 || true
+	fi
+done
+exit 0
+`, hookMarker, invoke, HookEventForcePush),
+	}
+}
+
+// installHooks writes companion hooks into a git repository.
+// Existing hooks that we did not install are left untouched.
+func installHooks(repoDir, home, exe string) ([]string, error) {
+	hooksDir := filepath.Join(repoDir, ".git", "hooks")
+	if info, err := os.Stat(hooksDir); err != nil || !info.IsDir() {
+		return nil, fmt.Errorf("%s is not a git repository", repoDir)
+	}
+
+	installed := make([]string, 0)
+	for name, script := range hookScripts(home, exe) {
+		path := filepath.Join(hooksDir, name)
+		if existing, err := os.ReadFile(path); err == nil && !strings.Contains(string(existing), hookMarker) {
+			return installed, fmt.Errorf("refusing to overwrite existing %s hook", name)
+		}
+		if err := os.WriteFile(path, []byte(script), 0755); err != nil {
+			return installed, fmt.Errorf("failed to write %s hook: %w", name, err)
+		}
+		installed = append(installed, name)
+	}
+	return installed, nil
+}
+
+// runHookCommand handles `tamagotchi hook ...`
+func runHookCommand(args []string) error {
+	if len(args) == 0 {
+		return fmt.Errorf("usage: tamagotchi hook install [repo] | hook event <%s|%s|%s> [branch]",
+			HookEventCommit, HookEventTestPass, HookEventForcePush)
+	}
+
+	switch args[0] {
+	case "install":
+		repoDir := "."
+		if len(args) > 1 {
+			repoDir = args[1]
+		}
+		home, err := filepath.Abs(hookHome())
+		if err != nil {
+			return err
+		}
+		exe, err := os.Executable()
+		if err != nil {
+			return fmt.Errorf("failed to locate tamagotchi binary: %w", err)
+		}
+		installed, err := installHooks(repoDir, home, exe)
+		for _, name := range installed {
+			fmt.Print(consoleText(fmt.Sprintf("🪝 Installed %s hook\n", name)))
+		}
+		if err != nil {
+			return err
+		}
+		fmt.Println("Your pet is now listening to your repository.")
+		fmt.Println("Tip: chain `tamagotchi hook event test-pass` after your test command to share good news.")
+		return nil
+
+	case "event":
+		if len(args) < 2 {
+			return fmt.Errorf("usage: tamagotchi hook event <type> [branch]")
+		}
+		event := HookEvent{Type: args[1], Time: time.Now()}
+		if len(args) > 2 {
+			event.Branch = args[2]
+		}
+		switch event.Type {
+		case HookEventCommit, HookEventTestPass, HookEventForcePush:
+		default:
+			return fmt.Errorf("unknown hook event %q", event.Type)
+		}
+		return appendHookEvent(hookQueuePath(), event)
+	}
+
+	return fmt.Errorf("unknown hook command %q", args[0])
+}
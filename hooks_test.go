@@ -0,0 +1,118 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestHookEventQueueRoundTrip(t *testing.T) {
+	path := filepath.Join(t.TempDir(), hookQueueFile)
+
+	events, err := drainHookEvents(path)
+	if err != nil || len(events) != 0 {
+		t.Fatalf("Expected empty drain for missing queue, got %v, %v", events, err)
+	}
+
+	appendHookEvent(path, HookEvent{Type: HookEventCommit, Branch: "main", Time: time.Now()})
+	appendHookEvent(path, HookEvent{Type: HookEventTestPass, Time: time.Now()})
+
+	events, err = drainHookEvents(path)
+	if err != nil {
+		t.Fatalf("Unexpected drain error: %v", err)
+	}
+	if len(events) != 2 {
+		t.Fatalf("Expected 2 events, got %d", len(events))
+	}
+	if events[0].Branch != "main" {
+		t.Errorf("Expected branch 'main', got %q", events[0].Branch)
+	}
+
+	if _, err := os.Stat(path); !os.IsNotExist(err) {
+		t.Error("Queue file should be removed after draining")
+	}
+}
+
+func TestApplyHookEvent(t *testing.T) {
+	pet := NewPet("TestPet")
+	pet.Stage = Baby
+	pet.Happiness = 50
+
+	pet.ApplyHookEvent(HookEvent{Type: HookEventTestPass})
+	if pet.Happiness != 53 {
+		t.Errorf("Expected happiness 53 after test pass, got %d", pet.Happiness)
+	}
+
+	pet.ApplyHookEvent(HookEvent{Type: HookEventCommit, Branch: "feature/secret"})
+	if len(pet.Absurd.OverheardBranches) != 1 || pet.Absurd.OverheardBranches[0] != "feature/secret" {
+		t.Errorf("Expected overheard branch, got %v", pet.Absurd.OverheardBranches)
+	}
+
+	fearCount := len(pet.Absurd.Fears)
+	reaction := pet.ApplyHookEvent(HookEvent{Type: HookEventForcePush, Branch: "main"})
+	if len(pet.Absurd.Fears) != fearCount+1 {
+		t.Error("Force-push should add a fear")
+	}
+	if !strings.Contains(reaction, forcePushFear.Name) {
+		t.Errorf("Expected reaction to name the fear, got %q", reaction)
+	}
+
+	pet.ApplyHookEvent(HookEvent{Type: HookEventForcePush})
+	if len(pet.Absurd.Fears) != fearCount+1 {
+		t.Error("Repeated force-push should not duplicate the fear")
+	}
+}
+
+func TestApplyHookEventEgg(t *testing.T) {
+	pet := NewPet("TestPet")
+	pet.Happiness = 50
+
+	if reaction := pet.ApplyHookEvent(HookEvent{Type: HookEventTestPass}); reaction != "" {
+		t.Errorf("Egg should not react, got %q", reaction)
+	}
+	if pet.Happiness != 50 {
+		t.Error("Egg happiness should not change")
+	}
+}
+
+func TestInstallHooks(t *testing.T) {
+	repo := t.TempDir()
+	if _, err := installHooks(repo, "/pet", "/bin/tamagotchi"); err == nil {
+		t.Error("Expected error for non-git directory")
+	}
+
+	hooksDir := filepath.Join(repo, ".git", "hooks")
+	os.MkdirAll(hooksDir, 0755)
+
+	installed, err := installHooks(repo, "/pet", "/bin/tamagotchi")
+	if err != nil {
+		t.Fatalf("Unexpected install error: %v", err)
+	}
+	if len(installed) != 2 {
+		t.Errorf("Expected 2 hooks installed, got %v", installed)
+	}
+
+	script, _ := os.ReadFile(filepath.Join(hooksDir, "post-commit"))
+	if !strings.Contains(string(script), "TAMAGOTCHI_HOME='/pet'") {
+		t.Errorf("Hook should embed pet home, got:\n%s", script)
+	}
+
+	// Reinstalling over our own hooks is fine
+	if _, err := installHooks(repo, "/pet", "/bin/tamagotchi"); err != nil {
+		t.Errorf("Reinstall should succeed: %v", err)
+	}
+
+	// Foreign hooks are never overwritten
+	os.WriteFile(filepath.Join(hooksDir, "pre-push"), []byte("#!/bin/sh\necho mine\n"), 0755)
+	if _, err := installHooks(repo, "/pet", "/bin/tamagotchi"); err == nil {
+		t.Error("Expected refusal to overwrite a foreign hook")
+	}
+}
+
+func TestShellQuote(t *testing.T) {
+	if got := shellQuote("it's"); got != `'it'\''s'` {
+		t.Errorf("shellQuote escaped incorrectly: %s", got)
+	}
+}
@@ -0,0 +1,251 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"os/signal"
+	"runtime"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	// idleFrameInterval keeps the ambient scene cheap enough for a spare tmux pane
+	idleFrameInterval = 2 * time.Second
+
+	// idleReloadInterval is how often the save is re-read to pick up stat changes
+	idleReloadInterval = 30 * time.Second
+)
+
+// idleSprites are compact single-line pets that fit the wandering scene
+var idleSprites = map[LifeStage]string{
+	Egg:   "(  .  )",
+	Baby:  "(◕ ◕)",
+	Child: "(◕ω◕)",
+	Teen:  "╱(◕‿◕)╲",
+	Adult: "╱(◕▿◕)╲",
+	Dead:  "💀",
+}
+
+// weatherParticles fill the sky row for each weather type
+var weatherParticles = map[string][]string{
+	"🌧️ rain":           {"'", " ", " ", "'", " ", " "},
+	"❄️ snow":           {"*", " ", " ", ".", " ", " ", " "},
+	"🌫️ fog":            {"░", "░", " ", "░", " "},
+	"☀️ clear":          {" "},
+	"⛅ drifting clouds": {" ", " ", "☁", " ", " ", " ", " ", " ", " ", " "},
+}
+
+// wanderOffset bounces the pet back and forth across a span of columns
+func wanderOffset(tick, span int) int {
+	if span <= 0 {
+		return 0
+	}
+	pos := tick % (span * 2)
+	if pos >= span {
+		return span*2 - pos - 1
+	}
+	return pos
+}
+
+// skyRow renders a row of weather particles that drifts with each tick
+func skyRow(weather string, width, tick int) string {
+	particles, ok := weatherParticles[weather]
+	if !ok {
+		particles = []string{" "}
+	}
+	var b strings.Builder
+	for i := 0; i < width; i++ {
+		b.WriteString(particles[(i+tick)%len(particles)])
+	}
+	return b.String()
+}
+
+// renderIdleScene composes one frame of the ambient idle scene
+func renderIdleScene(pet *Pet, ui *uiConfig, tick, width int, whisper string) string {
+	var b strings.Builder
+	now := time.Now()
+	weather := chooseWeather(now)
+
+	b.WriteString(ui.paletteText(fmt.Sprintf("%s • %s • %s", pet.Name, pet.Stage.String(), weather), ui.palette.title))
+	b.WriteString("\n\n")
+
+	if !ui.reducedMotion {
+		b.WriteString(ui.paletteText(skyRow(weather, width, tick), ui.palette.faint))
+		b.WriteString("\n")
+		b.WriteString(ui.paletteText(skyRow(weather, width, tick+3), ui.palette.faint))
+		b.WriteString("\n\n")
+	}
+
+	sprite := idleSprites[pet.Stage]
+	offset := 0
+	if pet.Stage != Dead && pet.Stage != Egg && !ui.reducedMotion {
+		offset = wanderOffset(tick, width-len([]rune(sprite)))
+	}
+	b.WriteString(strings.Repeat(" ", offset))
+	b.WriteString(ui.paletteText(sprite, ui.palette.accent))
+	b.WriteString("\n")
+	b.WriteString(strings.Repeat("▁", width))
+	b.WriteString("\n\n")
+
+	b.WriteString(fmt.Sprintf("%s %s\n", pet.getStatusIcon(), pet.getHealthStatus()))
+	if whisper != "" {
+		b.WriteString(ui.paletteText("  ~ "+whisper+" ~", ui.palette.faint))
+		b.WriteString("\n")
+	}
+
+	b.WriteString("\n")
+	b.WriteString(ui.paletteText("press any key to leave", ui.palette.faint))
+	b.WriteString("\n")
+	return b.String()
+}
+
+// idleWhisper picks up any drifting network whisper or pet thought
+func idleWhisper(pet *Pet) string {
+	if petNetwork != nil {
+		if msg := petNetwork.GetSpookyMessage(); msg != "" {
+			return msg
+		}
+		if petNetwork.ShouldShowNetworkThought() {
+			if thought := petNetwork.GetNetworkThought(); thought != "" {
+				return thought
+			}
+		}
+	}
+	if pet.Absurd != nil && pet.Stage != Dead && pet.Absurd.ShouldShowThought() {
+		if classroomMode {
+			return classroomThought()
+		}
+		if dream := pet.ancestralDream(); dream != "" {
+			return dream
+		}
+		return pet.randomThought()
+	}
+	return ""
+}
+
+// terminalWidth returns the terminal width, defaulting to a modest 60 columns
+func terminalWidth() int {
+	if cols, err := strconv.Atoi(os.Getenv("COLUMNS")); err == nil && cols > 20 {
+		return cols
+	}
+	if runtime.GOOS != "windows" {
+		cmd := exec.Command("stty", "size")
+		cmd.Stdin = os.Stdin
+		if out, err := cmd.Output(); err == nil {
+			fields := strings.Fields(string(out))
+			if len(fields) == 2 {
+				if cols, err := strconv.Atoi(fields[1]); err == nil && cols > 20 {
+					return cols
+				}
+			}
+		}
+	}
+	return 60
+}
+
+// enableKeypressMode switches the terminal to unbuffered, silent input and
+// returns a function restoring the previous settings. On Windows the
+// terminal stays line-buffered and Enter is needed to leave.
+func enableKeypressMode() func() {
+	restore, _ := setTerminalMode("-icanon", "-echo", "min", "1")
+	return restore
+}
+
+// setTerminalMode applies stty settings and returns a function restoring the
+// previous ones, reporting whether the terminal accepted them
+func setTerminalMode(settings ...string) (func(), bool) {
+	if runtime.GOOS == "windows" {
+		return func() {}, false
+	}
+
+	save := exec.Command("stty", "-g")
+	save.Stdin = os.Stdin
+	saved, err := save.Output()
+	if err != nil {
+		return func() {}, false
+	}
+
+	raw := exec.Command("stty", settings...)
+	raw.Stdin = os.Stdin
+	if err := raw.Run(); err != nil {
+		return func() {}, false
+	}
+
+	return func() {
+		restore := exec.Command("stty", strings.TrimSpace(string(saved)))
+		restore.Stdin = os.Stdin
+		restore.Run()
+	}, true
+}
+
+// runIdle renders the ambient scene until a key is pressed. The save file is
+// only read, never written, so a running game keeps simulating undisturbed.
+func runIdle(args []string) error {
+	pet, err := LoadPet(saveFile)
+	if err != nil {
+		return fmt.Errorf("no pet to watch: %w", err)
+	}
+
+	for _, arg := range args {
+		if arg == "--lonely" || arg == "-lonely" {
+			lonelyMode = true
+		}
+	}
+	initNetwork(pet)
+	defer shutdownNetwork()
+
+	ui := newUIConfig()
+	restore := enableKeypressMode()
+	defer restore()
+
+	keys := make(chan struct{}, 1)
+	go func() {
+		buf := make([]byte, 1)
+		os.Stdin.Read(buf)
+		keys <- struct{}{}
+	}()
+
+	interrupts := make(chan os.Signal, 1)
+	signal.Notify(interrupts, os.Interrupt)
+	defer signal.Stop(interrupts)
+
+	interval := idleFrameInterval
+	if ui.reducedMotion {
+		interval = 5 * idleFrameInterval
+	}
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	fmt.Print("\033[?25l")       // Hide cursor
+	defer fmt.Print("\033[?25h") // Show cursor
+
+	width := terminalWidth()
+	lastReload := time.Now()
+	whisper := ""
+	for tick := 0; ; tick++ {
+		if time.Since(lastReload) >= idleReloadInterval {
+			if reloaded, err := LoadPet(saveFile); err == nil {
+				pet = reloaded
+			}
+			lastReload = time.Now()
+		}
+		if next := idleWhisper(pet); next != "" {
+			whisper = next
+		}
+
+		fmt.Print("\033[H\033[2J")
+		fmt.Print(ui.text(renderIdleScene(pet, ui, tick, width, whisper)))
+
+		select {
+		case <-keys:
+			fmt.Print("\033[H\033[2J")
+			return nil
+		case <-interrupts:
+			return nil
+		case <-ticker.C:
+		}
+	}
+}
@@ -0,0 +1,62 @@
+package main
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestWanderOffset(t *testing.T) {
+	span := 5
+	for tick := 0; tick < 40; tick++ {
+		offset := wanderOffset(tick, span)
+		if offset < 0 || offset >= span {
+			t.Errorf("wanderOffset(%d, %d) = %d, out of range", tick, span, offset)
+		}
+	}
+
+	if wanderOffset(span, span) != span-1 {
+		t.Error("Pet should turn around at the edge")
+	}
+	if wanderOffset(3, 0) != 0 {
+		t.Error("Zero span should not move the pet")
+	}
+}
+
+func TestSkyRow(t *testing.T) {
+	row := skyRow("🌧️ rain", 20, 0)
+	if len([]rune(row)) != 20 {
+		t.Errorf("Expected 20 columns, got %d", len([]rune(row)))
+	}
+	if row == skyRow("🌧️ rain", 20, 1) {
+		t.Error("Rain should drift between ticks")
+	}
+	if len([]rune(skyRow("unknown", 10, 0))) != 10 {
+		t.Error("Unknown weather should still fill the row")
+	}
+}
+
+func TestRenderIdleScene(t *testing.T) {
+	ui := &uiConfig{reducedMotion: true}
+	pet := NewPet("Idler")
+	pet.Stage = Child
+
+	scene := renderIdleScene(pet, ui, 3, 40, "hello from the mesh")
+
+	if !strings.Contains(scene, "Idler") {
+		t.Error("Scene should include pet name")
+	}
+	if !strings.Contains(scene, idleSprites[Child]) {
+		t.Error("Scene should include the child sprite")
+	}
+	if !strings.Contains(scene, "hello from the mesh") {
+		t.Error("Scene should include the whisper")
+	}
+}
+
+func TestIdleSpritesCoverAllStages(t *testing.T) {
+	for stage := Egg; stage <= Dead; stage++ {
+		if idleSprites[stage] == "" {
+			t.Errorf("Missing idle sprite for %s", stage)
+		}
+	}
+}
@@ -0,0 +1,242 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+const (
+	// Warmth is comfortable between these bounds
+	idealWarmthLow  = 40
+	idealWarmthHigh = 60
+
+	// roomWarmth is where an untended egg drifts toward
+	roomWarmth = 30
+
+	// warmthDriftPerHour is how quickly the egg cools (or warms) toward room temperature
+	warmthDriftPerHour = 20
+
+	// warmthStep is how much a single warm or cool command changes warmth
+	warmthStep = 15
+)
+
+// IncubationState tracks egg care before hatching
+type IncubationState struct {
+	Warmth         int       `json:"warmth"`          // 0-100, ideal 40-60
+	CareTotal      float64   `json:"care_total"`      // Sum of care quality samples (0-1 each)
+	WarmthTotal    int       `json:"warmth_total"`    // Sum of warmth samples
+	Samples        int       `json:"samples"`         // Number of samples taken
+	HatchedAt      time.Time `json:"hatched_at"`      // Zero until the egg hatches
+	HatchTrait     string    `json:"hatch_trait"`     // Trait earned from incubation care
+	HatchAnnounced bool      `json:"hatch_announced"` // Whether the user has seen the hatching
+}
+
+// NewIncubationState creates a freshly laid egg's incubation state
+func NewIncubationState() *IncubationState {
+	return &IncubationState{Warmth: 50}
+}
+
+// sample records the current warmth as a care quality sample
+func (i *IncubationState) sample() {
+	distance := 0
+	if i.Warmth < idealWarmthLow {
+		distance = idealWarmthLow - i.Warmth
+	} else if i.Warmth > idealWarmthHigh {
+		distance = i.Warmth - idealWarmthHigh
+	}
+
+	quality := 1.0 - float64(distance)/40.0
+	if quality < 0 {
+		quality = 0
+	}
+
+	i.CareTotal += quality
+	i.WarmthTotal += i.Warmth
+	i.Samples++
+}
+
+// CareQuality returns the average care quality from 0 (neglected) to 1 (perfect)
+func (i *IncubationState) CareQuality() float64 {
+	if i.Samples == 0 {
+		return 0.5
+	}
+	return i.CareTotal / float64(i.Samples)
+}
+
+// AverageWarmth returns the average observed warmth
+func (i *IncubationState) AverageWarmth() int {
+	if i.Samples == 0 {
+		return i.Warmth
+	}
+	return i.WarmthTotal / i.Samples
+}
+
+// HatchOffsetHours returns how much earlier (negative) or later (positive)
+// than the balance table the egg hatches. Great care hatches half an hour early,
+// poor care up to an hour and a half late.
+func (i *IncubationState) HatchOffsetHours() float64 {
+	return 1.5 - 2*i.CareQuality()
+}
+
+// HatchAgeHours is how old the egg will be when it hatches, with care counted
+func (i *IncubationState) HatchAgeHours() float64 {
+	return max(float64(activeBalance.Stages[Baby.String()].MinAgeHours)+i.HatchOffsetHours(), 0.25)
+}
+
+// ReadyToHatch reports whether the egg has incubated long enough
+func (i *IncubationState) ReadyToHatch(birthTime, now time.Time) bool {
+	return now.Sub(birthTime).Hours() >= i.HatchAgeHours()
+}
+
+// Drift moves warmth toward room temperature over the given time, sampling care as it goes
+func (i *IncubationState) Drift(hoursPassed float64) {
+	drift := int(hoursPassed * warmthDriftPerHour)
+	if i.Warmth > roomWarmth {
+		i.Warmth = clamp(i.Warmth-drift, roomWarmth, 100)
+	} else if i.Warmth < roomWarmth {
+		i.Warmth = clamp(i.Warmth+drift, 0, roomWarmth)
+	}
+	i.sample()
+}
+
+// hatchTrait derives a starting trait from how the egg was kept
+func (i *IncubationState) hatchTrait() string {
+	avg := i.AverageWarmth()
+	switch {
+	case avg > idealWarmthHigh:
+		return "fiery"
+	case avg < idealWarmthLow:
+		return "aloof"
+	case i.CareQuality() >= 0.8:
+		return "secure"
+	default:
+		return "anxious"
+	}
+}
+
+// Hatch finalizes incubation and returns the trait the pet was born with
+func (i *IncubationState) Hatch(now time.Time) string {
+	i.HatchedAt = now
+	i.HatchTrait = i.hatchTrait()
+	return i.HatchTrait
+}
+
+// AddTrait gives the pet a trait if it doesn't already have it
+func (p *Pet) AddTrait(trait string) {
+	for _, existing := range p.Traits {
+		if existing == trait {
+			return
+		}
+	}
+	p.Traits = append(p.Traits, trait)
+}
+
+// Warm raises the egg's warmth
+func (p *Pet) Warm() string {
+	if p.Stage != Egg || p.Incubation == nil {
+		return "🐣 Your pet has hatched. It can regulate its own temperature now."
+	}
+
+	p.Incubation.Warmth = clamp(p.Incubation.Warmth+warmthStep, 0, 100)
+	p.Incubation.sample()
+
+	if p.Incubation.Warmth > idealWarmthHigh {
+		return fmt.Sprintf("🔥 The egg is getting hot (%d°). Something inside squirms.", p.Incubation.Warmth)
+	}
+	return fmt.Sprintf("🧣 You warm the egg (%d°). It hums contentedly.", p.Incubation.Warmth)
+}
+
+// Cool lowers the egg's warmth
+func (p *Pet) Cool() string {
+	if p.Stage != Egg || p.Incubation == nil {
+		return "🐣 Your pet has hatched. Fanning it just confuses it."
+	}
+
+	p.Incubation.Warmth = clamp(p.Incubation.Warmth-warmthStep, 0, 100)
+	p.Incubation.sample()
+
+	if p.Incubation.Warmth < idealWarmthLow {
+		return fmt.Sprintf("🧊 The egg is getting cold (%d°). It shivers, somehow.", p.Incubation.Warmth)
+	}
+	return fmt.Sprintf("🌬️ You cool the egg (%d°). It seems relieved.", p.Incubation.Warmth)
+}
+
+// candleSilhouettes hint at the personality forming inside the egg
+var candleSilhouettes = map[string]string{
+	"fiery": `     ___
+    / ~ \
+   | >^< |
+    \_~_/`,
+	"aloof": `     ___
+    /   \
+   | -.- |
+    \___/`,
+	"secure": `     ___
+    / ♥ \
+   | ◕‿◕ |
+    \___/`,
+	"anxious": `     ___
+    / ! \
+   | °o° |
+    \___/`,
+}
+
+// candleHints describe what the silhouette suggests
+var candleHints = map[string]string{
+	"fiery":   "A restless shape paces inside. It runs hot.",
+	"aloof":   "A still shape sits with its back to you.",
+	"secure":  "A curled shape sleeps soundly, tail wrapped around itself.",
+	"anxious": "A small shape twitches at every sound.",
+}
+
+// Candle shines a light through the egg and returns silhouette frames and a hint
+func (p *Pet) Candle() ([]string, string) {
+	if p.Stage != Egg || p.Incubation == nil {
+		return nil, "🐣 There is no egg left to candle. Just your pet, looking at the flashlight."
+	}
+
+	trait := p.Incubation.hatchTrait()
+	frames := []string{
+		"     ___\n    /   \\\n   |     |\n    \\___/",
+		"     ___\n    / . \\\n   |  .  |\n    \\___/",
+		candleSilhouettes[trait],
+	}
+	return frames, "🕯️ " + candleHints[trait]
+}
+
+// renderCandling plays the candling animation and returns the hint
+func renderCandling(pet *Pet, ui *uiConfig) string {
+	frames, hint := pet.Candle()
+	if len(frames) == 0 {
+		return hint
+	}
+
+	if ui.reducedMotion {
+		fmt.Println(ui.paletteText(frames[len(frames)-1], ui.palette.warn))
+		return hint
+	}
+
+	for _, frame := range frames {
+		clearScreen()
+		fmt.Println(ui.text("🕯️  You hold a light up to the egg..."))
+		fmt.Println(ui.paletteText(frame, ui.palette.warn))
+		time.Sleep(600 * time.Millisecond)
+	}
+	return hint
+}
+
+// hatchAnnouncement returns a one-time hatching message, if the pet just hatched
+func (p *Pet) hatchAnnouncement() string {
+	if p.Incubation == nil || p.Incubation.HatchedAt.IsZero() || p.Incubation.HatchAnnounced {
+		return ""
+	}
+	p.Incubation.HatchAnnounced = true
+
+	var b strings.Builder
+	b.WriteString(fmt.Sprintf("🐣 %s has hatched!", p.Name))
+	if p.Incubation.HatchTrait != "" {
+		b.WriteString(fmt.Sprintf(" It seems %s.", p.Incubation.HatchTrait))
+	}
+	return b.String()
+}
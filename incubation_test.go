@@ -0,0 +1,125 @@
+package main
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestWarmAndCool(t *testing.T) {
+	pet := NewPet("TestPet")
+
+	pet.Warm()
+	if pet.Incubation.Warmth != 50+warmthStep {
+		t.Errorf("Expected warmth %d, got %d", 50+warmthStep, pet.Incubation.Warmth)
+	}
+
+	pet.Cool()
+	pet.Cool()
+	if pet.Incubation.Warmth != 50-warmthStep {
+		t.Errorf("Expected warmth %d, got %d", 50-warmthStep, pet.Incubation.Warmth)
+	}
+
+	pet.Stage = Baby
+	if !strings.Contains(pet.Warm(), "hatched") {
+		t.Error("Warming a hatched pet should be refused")
+	}
+}
+
+func TestIncubationDrift(t *testing.T) {
+	inc := NewIncubationState()
+	inc.Warmth = 80
+
+	inc.Drift(1)
+	if inc.Warmth != 60 {
+		t.Errorf("Expected warmth to drift to 60, got %d", inc.Warmth)
+	}
+
+	inc.Drift(10)
+	if inc.Warmth != roomWarmth {
+		t.Errorf("Warmth should settle at room temperature, got %d", inc.Warmth)
+	}
+	if inc.Samples != 2 {
+		t.Errorf("Expected 2 samples, got %d", inc.Samples)
+	}
+}
+
+func TestHatchVarianceFollowsCare(t *testing.T) {
+	good := NewIncubationState()
+	for i := 0; i < 10; i++ {
+		good.sample()
+	}
+	bad := NewIncubationState()
+	bad.Warmth = 0
+	for i := 0; i < 10; i++ {
+		bad.sample()
+	}
+
+	if good.HatchOffsetHours() >= bad.HatchOffsetHours() {
+		t.Errorf("Good care should hatch sooner: good %.2f, bad %.2f", good.HatchOffsetHours(), bad.HatchOffsetHours())
+	}
+
+	birth := time.Now().Add(-45 * time.Minute)
+	if !good.ReadyToHatch(birth, time.Now()) {
+		t.Error("Well-kept egg should hatch early")
+	}
+	if bad.ReadyToHatch(birth, time.Now()) {
+		t.Error("Neglected egg should hatch late")
+	}
+}
+
+func TestHatchTrait(t *testing.T) {
+	tests := []struct {
+		warmth   int
+		expected string
+	}{
+		{50, "secure"},
+		{90, "fiery"},
+		{10, "aloof"},
+	}
+
+	for _, tt := range tests {
+		inc := NewIncubationState()
+		inc.Warmth = tt.warmth
+		inc.sample()
+		if trait := inc.hatchTrait(); trait != tt.expected {
+			t.Errorf("Warmth %d: expected %q, got %q", tt.warmth, tt.expected, trait)
+		}
+	}
+}
+
+func TestEggHatchesWithTrait(t *testing.T) {
+	pet := NewPet("TestPet")
+	pet.BirthTime = time.Now().Add(-3 * time.Hour)
+	pet.LastUpdateTime = time.Now().Add(-1 * time.Hour)
+
+	pet.Update()
+
+	if pet.Stage != Baby {
+		t.Fatalf("Expected egg to hatch into Baby, got %v", pet.Stage)
+	}
+	if len(pet.Traits) != 1 || pet.Traits[0] != pet.Incubation.HatchTrait {
+		t.Errorf("Expected hatch trait in traits, got %v", pet.Traits)
+	}
+
+	if msg := pet.hatchAnnouncement(); !strings.Contains(msg, "hatched") {
+		t.Errorf("Expected hatch announcement, got %q", msg)
+	}
+	if msg := pet.hatchAnnouncement(); msg != "" {
+		t.Error("Hatch should only be announced once")
+	}
+}
+
+func TestCandle(t *testing.T) {
+	pet := NewPet("TestPet")
+
+	frames, hint := pet.Candle()
+	if len(frames) == 0 || hint == "" {
+		t.Error("Expected candling frames and a hint for an egg")
+	}
+
+	pet.Stage = Child
+	if frames, _ := pet.Candle(); frames != nil {
+		t.Error("Hatched pets cannot be candled")
+	}
+}
@@ -0,0 +1,241 @@
+package main
+
+import (
+	"fmt"
+	"math/rand"
+	"slices"
+	"strings"
+	"time"
+)
+
+const (
+	// jobAwayHours is the shortest absence that counts as time at work.
+	// Nobody works while being watched.
+	jobAwayHours = 1.0
+
+	// jobRestPerHour is how much fatigue wears off for each hour not working
+	jobRestPerHour = 5.0
+
+	// jobExhausted is the fatigue at which the pet stops going in
+	jobExhausted = 100.0
+
+	// maxAnecdotes is how many work stories a pet tells when it gets home
+	maxAnecdotes = 3
+)
+
+// job is something an adult pet can do while you're away
+type job struct {
+	ID        string
+	Title     string
+	Emoji     string
+	Shift     time.Duration // Length of one shift
+	Pay       int           // TamaCoins per shift
+	Fatigue   float64       // Added per shift
+	FindEvery int           // Brings an item home every this many shifts
+	Finds     []string      // Items it brings home, in turn
+	Anecdotes []string      // %s is the pet's name
+}
+
+// jobs are listed in this order on the job board
+var jobs = []job{
+	{ID: "voidwatch", Title: "Night-Shift Void Watcher", Emoji: "🌑", Shift: 8 * time.Hour, Pay: 3, Fatigue: 25, FindEvery: 3,
+		Finds: []string{"A Piece of the Void", "Potted Plant (Nocturnal)", "The Void's Lost Glove"},
+		Anecdotes: []string{
+			"The void blinked first. %s is counting it as a win.",
+			"Nothing happened for eight hours. %s wrote it all down.",
+			"%s saw something move at 3am. It was paperwork.",
+			"The void asked %s how its day was. Nobody has ever asked the void that.",
+		}},
+	{ID: "json", Title: "JSON Formatter", Emoji: "🗂️", Shift: 4 * time.Hour, Pay: 2, Fatigue: 10, FindEvery: 5,
+		Finds: []string{"A Stray Curly Brace", "A Trailing Comma (Confiscated)", "Two-Space Indent"},
+		Anecdotes: []string{
+			"%s found a trailing comma and had it removed from the building.",
+			"Someone asked %s for tabs. Security was called.",
+			"%s formatted a file so large it had weather.",
+			"A null turned up where a string should be. %s doesn't want to talk about it.",
+		}},
+	{ID: "courier", Title: "Dream Courier", Emoji: "📬", Shift: 6 * time.Hour, Pay: 2, Fatigue: 15, FindEvery: 2,
+		Finds: []string{"An Undelivered Dream", "A Return-to-Sender Nightmare", "Postage for the Subconscious"},
+		Anecdotes: []string{
+			"%s delivered a dream about teeth to the wrong house. Again.",
+			"One dream had no address, only a smell. %s delivered it anyway.",
+			"A recurring dream signed for itself. %s has questions.",
+			"%s was chased by a dog in someone else's dream. Occupational hazard.",
+		}},
+}
+
+// findJob looks a job up by ID
+func findJob(id string) *job {
+	for i := range jobs {
+		if jobs[i].ID == id {
+			return &jobs[i]
+		}
+	}
+	return nil
+}
+
+// Paycheck is what the pet earned since you last saw it
+type Paycheck struct {
+	Shifts    int      `json:"shifts"`
+	Coins     int      `json:"coins"`
+	Items     []string `json:"items,omitempty"`
+	Anecdotes []string `json:"anecdotes,omitempty"`
+	Exhausted bool     `json:"exhausted,omitempty"` // Stopped going in partway through
+}
+
+// JobState is the pet's job and how it's going
+type JobState struct {
+	ID      string    `json:"id"`
+	Started time.Time `json:"started"`
+	Shifts  int       `json:"shifts"` // Worked in total
+	Earned  int       `json:"earned"` // TamaCoins in total
+	Fatigue float64   `json:"fatigue"`
+	Pending *Paycheck `json:"pending,omitempty"` // Waiting to be handed over
+}
+
+// TakeJob puts an adult pet to work
+func (p *Pet) TakeJob(id string, now time.Time) string {
+	j := findJob(id)
+	switch {
+	case j == nil:
+		return fmt.Sprintf("❓ Nobody's hiring for %s. Try job to see the board.", id)
+	case p.Stage != Adult:
+		return fmt.Sprintf("💼 %s is too young to work. Come back when it's an adult.", p.Name)
+	case p.Job != nil && p.Job.ID == id:
+		return fmt.Sprintf("💼 %s already works as a %s.", p.Name, j.Title)
+	}
+	fatigue := 0.0
+	if p.Job != nil {
+		fatigue = p.Job.Fatigue // Changing jobs isn't a holiday
+	}
+	p.Job = &JobState{ID: id, Started: now, Fatigue: fatigue}
+	return fmt.Sprintf("💼 %s is now a %s. Shifts are %s, paid %d TamaCoins each. It only works while you're away.",
+		p.Name, j.Title, j.Shift, j.Pay)
+}
+
+// QuitJob ends the pet's employment, handing over anything still owed
+func (p *Pet) QuitJob() string {
+	if p.Job == nil {
+		return fmt.Sprintf("💼 %s doesn't have a job to quit.", p.Name)
+	}
+	j := findJob(p.Job.ID)
+	message := fmt.Sprintf("💼 %s quits as %s after %d shifts and %d TamaCoins. It doesn't look back.",
+		p.Name, j.Title, p.Job.Shifts, p.Job.Earned)
+	if paid := p.CollectPaycheck(); paid != "" {
+		message = paid + "\n" + message
+	}
+	p.Job = nil
+	return message
+}
+
+// work is the pet's share of the catch-up simulation: a long enough absence
+// is spent on shifts, anything shorter is rest
+func (p *Pet) work(hours float64, r *rand.Rand) {
+	if p.Job == nil || p.Stage != Adult {
+		return
+	}
+	s := p.Job
+	j := findJob(s.ID)
+	if j == nil {
+		return
+	}
+	if hours < jobAwayHours {
+		s.Fatigue = max(s.Fatigue-hours*jobRestPerHour, 0)
+		return
+	}
+
+	shifts := int(hours / j.Shift.Hours())
+	rested := hours - float64(shifts)*j.Shift.Hours()
+	for i := 0; i < shifts; i++ {
+		if s.Pending == nil {
+			s.Pending = &Paycheck{}
+		}
+		if s.Fatigue >= jobExhausted {
+			s.Pending.Exhausted = true
+			rested += float64(shifts-i) * j.Shift.Hours()
+			break
+		}
+		s.Shifts++
+		s.Earned += j.Pay
+		s.Fatigue = min(s.Fatigue+j.Fatigue, jobExhausted)
+		s.Pending.Shifts++
+		s.Pending.Coins += j.Pay
+		if s.Shifts%j.FindEvery == 0 {
+			s.Pending.Items = append(s.Pending.Items, j.Finds[(s.Shifts/j.FindEvery-1)%len(j.Finds)])
+		}
+		if len(s.Pending.Anecdotes) < maxAnecdotes {
+			s.Pending.Anecdotes = append(s.Pending.Anecdotes, fmt.Sprintf(j.Anecdotes[r.Intn(len(j.Anecdotes))], p.Name))
+		}
+	}
+	s.Fatigue = max(s.Fatigue-rested*jobRestPerHour, 0)
+}
+
+// CollectPaycheck hands over what the pet earned while you were away. Tired
+// pets come home a little less happy.
+func (p *Pet) CollectPaycheck() string {
+	if p.Job == nil || p.Job.Pending == nil {
+		return ""
+	}
+	paid := p.Job.Pending
+	p.Job.Pending = nil
+	j := findJob(p.Job.ID)
+
+	if paid.Shifts == 0 {
+		return fmt.Sprintf("%s %s was too tired to go to work. Let it rest.", j.Emoji, p.Name)
+	}
+	var b strings.Builder
+	b.WriteString(fmt.Sprintf("%s %s worked %d shifts as a %s while you were away: +%d TamaCoins.", j.Emoji, p.Name, paid.Shifts, j.Title, paid.Coins))
+	if p.Endgame != nil {
+		p.Endgame.TamaCoins += paid.Coins
+		for _, item := range paid.Items {
+			if !slices.Contains(p.Endgame.InvisibleAccessories, item) {
+				p.Endgame.InvisibleAccessories = append(p.Endgame.InvisibleAccessories, item)
+			}
+		}
+	}
+	if len(paid.Items) > 0 {
+		b.WriteString(fmt.Sprintf("\n   Brought home: %s.", strings.Join(paid.Items, ", ")))
+	}
+	for _, anecdote := range paid.Anecdotes {
+		b.WriteString("\n   \"" + anecdote + "\"")
+	}
+	fatigue := int(p.Job.Fatigue)
+	p.Happiness = clamp(p.Happiness-fatigue/5, 0, 100)
+	b.WriteString(fmt.Sprintf("\n   Fatigue: %d%%.", fatigue))
+	if paid.Exhausted {
+		b.WriteString(" It stopped going in partway through. Too tired.")
+	}
+	return b.String()
+}
+
+// renderJobBoard shows the pet's job and what else is going
+func (p *Pet) renderJobBoard() string {
+	var b strings.Builder
+	b.WriteString("💼 JOB BOARD\n")
+	if s := p.Job; s != nil {
+		j := findJob(s.ID)
+		b.WriteString(fmt.Sprintf("%s works as a %s %s.\n", p.Name, j.Emoji, j.Title))
+		b.WriteString(fmt.Sprintf("%d shifts worked, %d TamaCoins earned. Fatigue %s %d%%.\n\n", s.Shifts, s.Earned, skillBar(s.Fatigue/jobExhausted), int(s.Fatigue)))
+	} else {
+		b.WriteString("Adult pets can work while you're away.\n\n")
+	}
+	for _, j := range jobs {
+		b.WriteString(fmt.Sprintf("  %-9s %s %s: %s shifts, %d 🪙 each\n", j.ID, j.Emoji, j.Title, j.Shift, j.Pay))
+	}
+	b.WriteString("\nTake one with: job take <job>. Quit with: job quit.")
+	return b.String()
+}
+
+// runJobCommand shows the job board, takes a job, or quits one
+func runJobCommand(pet *Pet, args string, now time.Time) string {
+	words := strings.Fields(args)
+	switch {
+	case len(words) == 0:
+		return pet.renderJobBoard()
+	case len(words) == 2 && words[0] == "take":
+		return pet.TakeJob(words[1], now)
+	case len(words) == 1 && words[0] == "quit":
+		return pet.QuitJob()
+	}
+	return "❓ Usage: job, job take <job>, or job quit"
+}
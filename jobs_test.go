@@ -0,0 +1,102 @@
+package main
+
+import (
+	"math/rand"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestTakeJob(t *testing.T) {
+	now := time.Now()
+	tests := []struct {
+		name  string
+		stage LifeStage
+		job   string
+		want  string
+	}{
+		{"an adult", Adult, "courier", "now a Dream Courier"},
+		{"a teen", Teen, "courier", "too young to work"},
+		{"an unknown job", Adult, "astronaut", "Nobody's hiring for astronaut"},
+	}
+	for _, tt := range tests {
+		pet := NewPet("Worker")
+		pet.Stage = tt.stage
+		if got := pet.TakeJob(tt.job, now); !strings.Contains(got, tt.want) {
+			t.Errorf("%s: Expected %q, got %s", tt.name, tt.want, got)
+		}
+	}
+}
+
+func TestWorkWhileAway(t *testing.T) {
+	r := rand.New(rand.NewSource(1))
+	pet := NewPet("Worker")
+	pet.Stage = Adult
+	pet.TakeJob("courier", time.Now())
+
+	// Watched pets don't work
+	pet.work(0.5, r)
+	if pet.Job.Pending != nil || pet.Job.Shifts != 0 {
+		t.Fatalf("Expected no work in a short slice, got %+v", pet.Job)
+	}
+
+	pet.work(13, r) // Two six-hour shifts, an hour of rest
+	paid := pet.Job.Pending
+	if paid == nil || paid.Shifts != 2 || paid.Coins != 4 || len(paid.Items) != 1 || len(paid.Anecdotes) != 2 {
+		t.Fatalf("Expected two shifts, 4 coins, an item, and two stories, got %+v", paid)
+	}
+	if pet.Job.Fatigue != 25 {
+		t.Errorf("Expected fatigue 25 after an hour's rest, got %v", pet.Job.Fatigue)
+	}
+
+	coins, happiness := pet.Endgame.TamaCoins, pet.Happiness
+	got := pet.CollectPaycheck()
+	if !strings.Contains(got, "worked 2 shifts as a Dream Courier") || !strings.Contains(got, "An Undelivered Dream") {
+		t.Errorf("Expected the paycheck, got %s", got)
+	}
+	if pet.Endgame.TamaCoins != coins+4 || pet.Happiness != happiness-5 {
+		t.Errorf("Expected 4 more coins and a tired pet, got %d coins and happiness %d", pet.Endgame.TamaCoins, pet.Happiness)
+	}
+	if again := pet.CollectPaycheck(); again != "" {
+		t.Errorf("Expected to be paid once, got %s", again)
+	}
+}
+
+func TestWorkUntilExhausted(t *testing.T) {
+	r := rand.New(rand.NewSource(1))
+	pet := NewPet("Workaholic")
+	pet.Stage = Adult
+	pet.TakeJob("voidwatch", time.Now())
+
+	pet.work(48, r) // Six shifts offered; four wear it out
+	if paid := pet.Job.Pending; paid.Shifts != 4 || !paid.Exhausted {
+		t.Errorf("Expected four shifts before exhaustion, got %+v", paid)
+	}
+	if pet.Job.Fatigue != jobExhausted-16*jobRestPerHour {
+		t.Errorf("Expected the last sixteen hours spent resting, got fatigue %v", pet.Job.Fatigue)
+	}
+	if got := pet.QuitJob(); !strings.Contains(got, "quits as Night-Shift Void Watcher after 4 shifts") || pet.Job != nil {
+		t.Errorf("Expected to quit and be paid, got %s", got)
+	}
+}
+
+func TestRunJobCommand(t *testing.T) {
+	pet := NewPet("Worker")
+	pet.Stage = Adult
+	tests := []struct {
+		args string
+		want string
+	}{
+		{"", "JOB BOARD"},
+		{"take json", "now a JSON Formatter"},
+		{"", "works as a 🗂️ JSON Formatter"},
+		{"quit", "quits as JSON Formatter"},
+		{"quit", "doesn't have a job"},
+		{"apply json", "Usage"},
+	}
+	for _, tt := range tests {
+		if got := runJobCommand(pet, tt.args, time.Now()); !strings.Contains(got, tt.want) {
+			t.Errorf("job %q: Expected %q, got %s", tt.args, tt.want, got)
+		}
+	}
+}
@@ -0,0 +1,283 @@
+package main
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"os/signal"
+	"strings"
+	"time"
+)
+
+const (
+	// kioskFrameInterval is how often the kiosk redraws
+	kioskFrameInterval = 2 * time.Second
+	// kioskSlideInterval is how long each view stays up
+	kioskSlideInterval = 20 * time.Second
+	// kioskReplyInterval is how long a visitor's answer stays on screen
+	kioskReplyInterval = 10 * time.Second
+	// kioskResetInterval is how long a demo pet lives before a fresh one hatches
+	kioskResetInterval = time.Hour
+	// kioskGossipLines is how much passing gossip stays on screen
+	kioskGossipLines = 5
+	// kioskDemoName is what every demo pet is called
+	kioskDemoName = "Demo"
+)
+
+// kioskMode is set by the --kiosk flag
+var kioskMode = false
+
+// kioskCommands are what a passer-by may type: care, a chat, and the mesh.
+// Nothing that resets, quits, saves, or waits on a reply.
+var kioskCommands = map[string]bool{
+	"feed": true, "play": true, "clean": true, "heal": true, "pet": true, "say": true,
+	"look": true, "favorites": true, "constellation": true, "census": true, "signal": true,
+	"help": true,
+}
+
+// kioskSlides are the views the kiosk rotates through, in order
+var kioskSlides = []string{"pet", "constellation", "gossip", "showcase"}
+
+// kioskGossip is one passing whisper and when it passed
+type kioskGossip struct {
+	text string
+	at   time.Time
+}
+
+// kiosk is a public demo: a throwaway pet, the sky around it, and a tour
+type kiosk struct {
+	pet      *Pet
+	ui       *uiConfig
+	commands *commandRegistry
+	bornAt   time.Time
+	slide    int       // Index into kioskSlides
+	slideAt  time.Time // When the current view went up
+	showcase int       // Which command the showcase is on
+	gossip   []kioskGossip
+	reply    string // The last visitor's answer
+	replyAt  time.Time
+}
+
+// newKioskRegistry registers only the commands a passer-by may use
+func newKioskRegistry() *commandRegistry {
+	r := newCommandRegistry()
+	for _, cmd := range builtinCommands() {
+		if !kioskCommands[cmd.Name()] {
+			continue
+		}
+		if err := r.Register(cmd); err != nil {
+			panic(err)
+		}
+	}
+	return r
+}
+
+// newDemoPet hatches a pet already old enough to play with. It has nowhere
+// to save, so it can never overwrite anyone's real pet.
+func newDemoPet(now time.Time) *Pet {
+	pet := NewPet(kioskDemoName)
+	pet.SaveFilePath = ""
+	age := activeBalance.Stages[Child.String()].MinAgeHours
+	pet.BirthTime = now.Add(-time.Duration(age) * time.Hour)
+	pet.LastUpdateTime = now
+	pet.Age = age
+	pet.Stage = activeBalance.StageFor(age)
+	pet.Hunger = 40
+	pet.Happiness = 70
+	return pet
+}
+
+// newKiosk sets up a kiosk with a fresh demo pet
+func newKiosk(ui *uiConfig, now time.Time) *kiosk {
+	return &kiosk{pet: newDemoPet(now), ui: ui, commands: newKioskRegistry(), bornAt: now, slideAt: now}
+}
+
+// maybeReset hatches a fresh demo pet once the current one has had its
+// hour, reporting whether it did
+func (k *kiosk) maybeReset(now time.Time) bool {
+	if now.Sub(k.bornAt) < kioskResetInterval {
+		return false
+	}
+	k.pet = newDemoPet(now)
+	k.bornAt = now
+	k.reply, k.replyAt = "", time.Time{}
+	syncIdentity(k.pet)
+	return true
+}
+
+// hear keeps a passing whisper for the gossip view
+func (k *kiosk) hear(whisper string, now time.Time) {
+	if whisper == "" {
+		return
+	}
+	k.gossip = append(k.gossip, kioskGossip{text: whisper, at: now})
+	if len(k.gossip) > kioskGossipLines {
+		k.gossip = k.gossip[len(k.gossip)-kioskGossipLines:]
+	}
+}
+
+// advance moves on to the next view once the current one has been up long
+// enough; a visitor's answer holds the view where it is
+func (k *kiosk) advance(now time.Time) {
+	if now.Sub(k.replyAt) < kioskReplyInterval || now.Sub(k.slideAt) < kioskSlideInterval {
+		return
+	}
+	k.slide = (k.slide + 1) % len(kioskSlides)
+	k.slideAt = now
+	if kioskSlides[k.slide] == "showcase" {
+		k.showcase++
+	}
+}
+
+// handle runs a line a visitor typed
+func (k *kiosk) handle(line string, now time.Time) {
+	line = strings.TrimSpace(line)
+	if line == "" {
+		return
+	}
+	verb, args, _ := strings.Cut(line, " ")
+	verb = strings.ToLower(verb)
+
+	cmd := k.commands.Lookup(verb)
+	if cmd == nil {
+		cmd = k.commands.Correct(verb)
+	}
+	switch {
+	case cmd != nil:
+		ctx := &commandContext{pet: k.pet, ui: k.ui, commands: k.commands, args: strings.TrimSpace(args)}
+		k.reply = runCommand(cmd, ctx)
+		if k.reply == "" {
+			k.reply = strings.Trim(renderMenu(k.commands, sectionMain), "\n")
+		}
+	case newDefaultRegistry().Lookup(verb) != nil:
+		k.reply = fmt.Sprintf("🔒 '%s' is switched off on the demo pet. Install your own to try it!", verb)
+	default:
+		k.reply = "❓ Try feed, play, say hello, or help."
+	}
+	k.replyAt = now
+}
+
+// showcased is the command the showcase view is on: every main command
+// that has something to show, in menu order
+func (k *kiosk) showcased() CommandDoc {
+	var docs []CommandDoc
+	for _, cmd := range builtinCommands() {
+		if doc := cmd.Help(); doc.Section == sectionMain && doc.Details != "" && len(doc.Examples) > 0 {
+			docs = append(docs, doc)
+		}
+	}
+	return docs[k.showcase%len(docs)]
+}
+
+// renderShowcase is a card for one feature
+func (k *kiosk) renderShowcase() string {
+	doc := k.showcased()
+	var b strings.Builder
+	b.WriteString(k.ui.paletteText("💡 DID YOU KNOW?", k.ui.palette.title) + "\n\n")
+	fmt.Fprintf(&b, "%s  %s\n\n%s\n\n", doc.Name, doc.Summary, doc.Details)
+	fmt.Fprintf(&b, "Try: %s\n", strings.Join(doc.Examples, ", "))
+	if doc.Lore != "" {
+		b.WriteString(k.ui.paletteText(doc.Lore, k.ui.palette.faint) + "\n")
+	}
+	return b.String()
+}
+
+// renderGossip is the passing gossip, newest first
+func (k *kiosk) renderGossip(now time.Time) string {
+	var b strings.Builder
+	b.WriteString(k.ui.paletteText("📡 OVERHEARD ON THE MESH", k.ui.palette.title) + "\n\n")
+	if len(k.gossip) == 0 {
+		b.WriteString("  Nothing yet. The pets nearby are thinking it over.\n")
+	}
+	for i := len(k.gossip) - 1; i >= 0; i-- {
+		g := k.gossip[i]
+		fmt.Fprintf(&b, "  ~ %s ~  %s\n", g.text, k.ui.paletteText(formatAgo(now.Sub(g.at)), k.ui.palette.faint))
+	}
+	return b.String()
+}
+
+// formatAgo says how long ago something passed, coarsely
+func formatAgo(d time.Duration) string {
+	if d < time.Minute {
+		return "just now"
+	}
+	return fmt.Sprintf("%dm ago", int(d.Minutes()))
+}
+
+// render draws the kiosk: the current view, the latest gossip, any
+// answer for the visitor, and how to join in
+func (k *kiosk) render(now time.Time) string {
+	var b strings.Builder
+	switch kioskSlides[k.slide] {
+	case "pet":
+		b.WriteString(renderScene(k.pet, k.ui))
+	case "constellation":
+		b.WriteString(k.ui.paletteText("✨ THE PETS NEARBY", k.ui.palette.title) + "\n\n")
+		if c, ok := meshConstellation(); ok {
+			b.WriteString(k.ui.renderConstellation(c, "", now))
+		} else {
+			b.WriteString("🌌 The sky is dark. This kiosk isn't listening to the mesh.\n")
+		}
+	case "gossip":
+		b.WriteString(k.renderGossip(now))
+	case "showcase":
+		b.WriteString(k.renderShowcase())
+	}
+	b.WriteString("\n")
+
+	if n := len(k.gossip); n > 0 && kioskSlides[k.slide] != "gossip" {
+		b.WriteString(k.ui.paletteText("📡 "+k.gossip[n-1].text, k.ui.palette.accent) + "\n")
+	}
+	if k.reply != "" && now.Sub(k.replyAt) < kioskReplyInterval {
+		b.WriteString("\n" + k.reply + "\n")
+	}
+	left := kioskResetInterval - now.Sub(k.bornAt)
+	fmt.Fprintf(&b, "\n%s\n", k.ui.paletteText(fmt.Sprintf(
+		"👋 Type a command and press Enter: feed, play, say hello, help. A new %s hatches in %d minutes.",
+		kioskDemoName, int(left.Minutes())+1), k.ui.palette.faint))
+	return b.String()
+}
+
+// runKiosk runs the public demo until interrupted. Nothing is read from or
+// written to the player's save.
+func runKiosk(ui *uiConfig) {
+	now := time.Now()
+	k := newKiosk(ui, now)
+	initNetwork(k.pet)
+	defer shutdownNetwork()
+
+	lines := make(chan string)
+	go func() {
+		scanner := bufio.NewScanner(os.Stdin)
+		for scanner.Scan() {
+			lines <- scanner.Text()
+		}
+	}()
+
+	interrupts := make(chan os.Signal, 1)
+	signal.Notify(interrupts, os.Interrupt)
+	defer signal.Stop(interrupts)
+
+	ticker := time.NewTicker(kioskFrameInterval)
+	defer ticker.Stop()
+
+	for {
+		now := time.Now()
+		k.maybeReset(now)
+		k.pet.Update()
+		k.hear(idleWhisper(k.pet), now)
+		k.advance(now)
+
+		fmt.Print("\033[H\033[2J")
+		fmt.Print(ui.text(k.render(now)))
+
+		select {
+		case line := <-lines:
+			k.handle(line, time.Now())
+		case <-interrupts:
+			fmt.Print("\033[H\033[2J")
+			return
+		case <-ticker.C:
+		}
+	}
+}
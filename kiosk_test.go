@@ -0,0 +1,122 @@
+package main
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestKioskRegistry(t *testing.T) {
+	commands := newKioskRegistry()
+	for _, word := range []string{"feed", "play", "say", "constellation", "help"} {
+		if commands.Lookup(word) == nil {
+			t.Errorf("Expected %s on the kiosk menu", word)
+		}
+	}
+	for _, word := range []string{"reset", "quit", "save", "games", "rename", "privacy", "custody", "casino"} {
+		if commands.Lookup(word) != nil {
+			t.Errorf("Expected %s to be left off the kiosk menu", word)
+		}
+	}
+}
+
+func TestDemoPetNeverSaves(t *testing.T) {
+	pet := newDemoPet(time.Now())
+	if pet.Stage == Egg || pet.Stage == Dead {
+		t.Errorf("Expected a demo pet old enough to play with, got %s", pet.Stage)
+	}
+	if err := pet.Save(); err == nil {
+		t.Errorf("Expected the demo pet to have nowhere to save")
+	}
+}
+
+func TestKioskReset(t *testing.T) {
+	start := time.Now()
+	k := newKiosk(newUIConfig(), start)
+	k.pet.Hunger = 90
+
+	if k.maybeReset(start.Add(30 * time.Minute)) {
+		t.Errorf("Expected the demo pet to last the hour")
+	}
+	if !k.maybeReset(start.Add(kioskResetInterval)) {
+		t.Fatalf("Expected a fresh demo pet after an hour")
+	}
+	if k.pet.Hunger == 90 {
+		t.Errorf("Expected the fresh pet to start over, got hunger %d", k.pet.Hunger)
+	}
+}
+
+func TestKioskHandle(t *testing.T) {
+	tests := []struct {
+		line string
+		want string
+	}{
+		{"say hello", "💬 Demo:"},
+		{"reset", "switched off on the demo pet"},
+		{"quit", "switched off on the demo pet"},
+		{"xyzzy", "Try feed"},
+		{"help", "feed"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.line, func(t *testing.T) {
+			now := time.Now()
+			k := newKiosk(newUIConfig(), now)
+			k.handle(tt.line, now)
+			if !strings.Contains(k.reply, tt.want) {
+				t.Errorf("Expected %q in %q", tt.want, k.reply)
+			}
+		})
+	}
+}
+
+func TestKioskRotates(t *testing.T) {
+	start := time.Now()
+	k := newKiosk(newUIConfig(), start)
+
+	k.advance(start.Add(time.Second))
+	if k.slide != 0 {
+		t.Errorf("Expected the first view to stay up, got %d", k.slide)
+	}
+	k.advance(start.Add(kioskSlideInterval))
+	if k.slide != 1 {
+		t.Errorf("Expected the next view, got %d", k.slide)
+	}
+
+	// A visitor's answer holds the view
+	now := start.Add(2 * kioskSlideInterval)
+	k.handle("pet", now)
+	k.advance(now.Add(time.Second))
+	if k.slide != 1 {
+		t.Errorf("Expected the view held while the visitor reads, got %d", k.slide)
+	}
+
+	first := k.showcased().Name
+	for i := 0; i < len(kioskSlides); i++ {
+		now = now.Add(kioskSlideInterval)
+		k.advance(now)
+	}
+	if k.showcased().Name == first {
+		t.Errorf("Expected the showcase to move on from %s", first)
+	}
+}
+
+func TestKioskGossip(t *testing.T) {
+	now := time.Now()
+	k := newKiosk(newUIConfig(), now)
+	for i := 0; i < kioskGossipLines+2; i++ {
+		k.hear(strings.Repeat("w", i+1), now)
+	}
+	k.hear("", now)
+	if len(k.gossip) != kioskGossipLines {
+		t.Fatalf("Expected %d lines of gossip kept, got %d", kioskGossipLines, len(k.gossip))
+	}
+
+	got := k.render(now)
+	if !strings.Contains(got, strings.Repeat("w", kioskGossipLines+2)) {
+		t.Errorf("Expected the latest gossip on every view, got:\n%s", got)
+	}
+	k.slide = 2
+	if got := k.renderGossip(now); strings.Contains(got, "Nothing yet") {
+		t.Errorf("Expected the gossip view to list what was heard, got:\n%s", got)
+	}
+}
@@ -0,0 +1,310 @@
+package main
+
+import (
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// Terminal cells are not runes: CJK and most emoji take two columns, combining
+// marks and joiners take none, and an emoji ZWJ sequence is one glyph however
+// many runes it holds. Everything here measures in columns so box borders
+// line up regardless of what a pet is named.
+
+// wideRanges are code points drawn two columns wide: East Asian Wide and
+// Fullwidth characters, plus emoji that default to emoji presentation
+var wideRanges = [][2]rune{
+	{0x1100, 0x115F}, {0x231A, 0x231B}, {0x2329, 0x232A}, {0x23E9, 0x23EC},
+	{0x23F0, 0x23F0}, {0x23F3, 0x23F3}, {0x25FD, 0x25FE}, {0x2614, 0x2615},
+	{0x2648, 0x2653}, {0x267F, 0x267F}, {0x2693, 0x2693}, {0x26A1, 0x26A1},
+	{0x26AA, 0x26AB}, {0x26BD, 0x26BE}, {0x26C4, 0x26C5}, {0x26CE, 0x26CE},
+	{0x26D4, 0x26D4}, {0x26EA, 0x26EA}, {0x26F2, 0x26F3}, {0x26F5, 0x26F5},
+	{0x26FA, 0x26FA}, {0x26FD, 0x26FD}, {0x2705, 0x2705}, {0x270A, 0x270B},
+	{0x2728, 0x2728}, {0x274C, 0x274C}, {0x274E, 0x274E}, {0x2753, 0x2755},
+	{0x2757, 0x2757}, {0x2795, 0x2797}, {0x27B0, 0x27B0}, {0x27BF, 0x27BF},
+	{0x2B1B, 0x2B1C}, {0x2B50, 0x2B50}, {0x2B55, 0x2B55}, {0x2E80, 0x303E},
+	{0x3041, 0x33FF}, {0x3400, 0x4DBF}, {0x4E00, 0x9FFF}, {0xA000, 0xA4CF},
+	{0xA960, 0xA97F}, {0xAC00, 0xD7A3}, {0xF900, 0xFAFF}, {0xFE10, 0xFE19},
+	{0xFE30, 0xFE6F}, {0xFF00, 0xFF60}, {0xFFE0, 0xFFE6}, {0x1F004, 0x1F004},
+	{0x1F0CF, 0x1F0CF}, {0x1F18E, 0x1F18E}, {0x1F191, 0x1F19A}, {0x1F200, 0x1F251},
+	{0x1F300, 0x1F320}, {0x1F32D, 0x1F335}, {0x1F337, 0x1F37C}, {0x1F37E, 0x1F393},
+	{0x1F3A0, 0x1F3CA}, {0x1F3CF, 0x1F3D3}, {0x1F3E0, 0x1F3F0}, {0x1F3F4, 0x1F3F4},
+	{0x1F3F8, 0x1F43E}, {0x1F440, 0x1F440}, {0x1F442, 0x1F4FC}, {0x1F4FF, 0x1F53D},
+	{0x1F54B, 0x1F54E}, {0x1F550, 0x1F567}, {0x1F57A, 0x1F57A}, {0x1F595, 0x1F596},
+	{0x1F5A4, 0x1F5A4}, {0x1F5FB, 0x1F64F}, {0x1F680, 0x1F6C5}, {0x1F6CC, 0x1F6CC},
+	{0x1F6D0, 0x1F6D2}, {0x1F6D5, 0x1F6D7}, {0x1F6EB, 0x1F6EC}, {0x1F6F4, 0x1F6FC},
+	{0x1F7E0, 0x1F7EB}, {0x1F90C, 0x1F93A}, {0x1F93C, 0x1F945}, {0x1F947, 0x1F9FF},
+	{0x1FA70, 0x1FAFF}, {0x20000, 0x2FFFD}, {0x30000, 0x3FFFD},
+}
+
+const (
+	zeroWidthJoiner    = '\u200d'
+	textPresentation   = '\ufe0e' // VS15: draw the preceding symbol as text
+	emojiPresentation  = '\ufe0f' // VS16: draw the preceding symbol as emoji
+	combiningKeycap    = '\u20e3'
+	regionalIndicatorA = 0x1F1E6
+	regionalIndicatorZ = 0x1F1FF
+	ellipsis           = "…"
+
+	// maxPanelNameWidth keeps a long pet name on its panel's title row
+	maxPanelNameWidth = 24
+)
+
+// isWideRune reports whether r is drawn two columns wide on its own
+func isWideRune(r rune) bool {
+	for _, span := range wideRanges {
+		if r < span[0] {
+			return false
+		}
+		if r <= span[1] {
+			return true
+		}
+	}
+	return false
+}
+
+// isRegionalIndicator reports whether r is half of a flag
+func isRegionalIndicator(r rune) bool {
+	return r >= regionalIndicatorA && r <= regionalIndicatorZ
+}
+
+// extendsGrapheme reports whether r attaches to the glyph before it
+func extendsGrapheme(r rune) bool {
+	switch {
+	case r == zeroWidthJoiner, r == combiningKeycap:
+		return true
+	case r >= 0x1F3FB && r <= 0x1F3FF: // Skin tones
+		return true
+	case r >= 0xE0020 && r <= 0xE007F: // Tag sequences (subdivision flags)
+		return true
+	}
+	return unicode.In(r, unicode.Mn, unicode.Me, unicode.Cf)
+}
+
+// ansiEscapeLen returns the length of the CSI escape sequence at the start of s, or 0
+func ansiEscapeLen(s string) int {
+	if !strings.HasPrefix(s, "\033[") {
+		return 0
+	}
+	for i := 2; i < len(s); i++ {
+		if s[i] >= 0x40 && s[i] <= 0x7E {
+			return i + 1
+		}
+	}
+	return len(s)
+}
+
+// graphemes splits s into user-perceived characters. ANSI escape sequences
+// come through as their own zero-width segments.
+func graphemes(s string) []string {
+	var out []string
+	start := -1
+	prev := rune(-1)
+	flagHalves := 0
+
+	flush := func(end int) {
+		if start >= 0 {
+			out = append(out, s[start:end])
+		}
+		start = -1
+	}
+
+	for i := 0; i < len(s); {
+		if n := ansiEscapeLen(s[i:]); n > 0 {
+			flush(i)
+			out = append(out, s[i:i+n])
+			i += n
+			prev = -1
+			continue
+		}
+
+		r, size := utf8.DecodeRuneInString(s[i:])
+		joins := start >= 0 && (extendsGrapheme(r) || prev == zeroWidthJoiner ||
+			(isRegionalIndicator(r) && flagHalves == 1))
+		if !joins {
+			flush(i)
+			start = i
+			flagHalves = 0
+		}
+		if isRegionalIndicator(r) {
+			flagHalves++
+		}
+		prev = r
+		i += size
+	}
+	flush(len(s))
+	return out
+}
+
+// graphemeWidth returns the columns one grapheme occupies
+func graphemeWidth(g string) int {
+	if ansiEscapeLen(g) > 0 {
+		return 0
+	}
+	first, _ := utf8.DecodeRuneInString(g)
+	if first < 0x20 || (first >= 0x7F && first < 0xA0) {
+		return 0
+	}
+	if extendsGrapheme(first) {
+		return 0 // A stray joiner or mark with nothing to attach to
+	}
+
+	runes := 0
+	for _, r := range g {
+		runes++
+		switch r {
+		case emojiPresentation, combiningKeycap:
+			return 2
+		case textPresentation:
+			return 1
+		}
+	}
+	if isRegionalIndicator(first) && runes >= 2 {
+		return 2
+	}
+	if isWideRune(first) {
+		return 2
+	}
+	return 1
+}
+
+// displayWidth returns how many terminal columns s occupies
+func displayWidth(s string) int {
+	width := 0
+	for _, line := range strings.Split(s, "\n") {
+		w := 0
+		for _, g := range graphemes(line) {
+			w += graphemeWidth(g)
+		}
+		width = max(width, w)
+	}
+	return width
+}
+
+// padWidth pads s with spaces to width columns
+func padWidth(s string, width int) string {
+	if gap := width - displayWidth(s); gap > 0 {
+		return s + strings.Repeat(" ", gap)
+	}
+	return s
+}
+
+// truncateWidth shortens s to at most width columns, ending in an ellipsis
+// when anything was cut. Graphemes are never split.
+func truncateWidth(s string, width int) string {
+	if displayWidth(s) <= width {
+		return s
+	}
+	if width <= 0 {
+		return ""
+	}
+
+	var b strings.Builder
+	used := 0
+	styled := false
+	for _, g := range graphemes(s) {
+		w := graphemeWidth(g)
+		if w == 0 && ansiEscapeLen(g) > 0 {
+			styled = true
+			b.WriteString(g)
+			continue
+		}
+		if used+w > width-1 {
+			break
+		}
+		b.WriteString(g)
+		used += w
+	}
+	b.WriteString(ellipsis)
+	if styled {
+		b.WriteString("\033[0m")
+	}
+	return b.String()
+}
+
+// wrapWidth breaks s into lines of at most width columns at spaces,
+// splitting words only when a single word is too wide
+func wrapWidth(s string, width int) []string {
+	var lines []string
+	line := ""
+	for _, word := range strings.Fields(s) {
+		for displayWidth(word) > width {
+			if line != "" {
+				lines = append(lines, line)
+				line = ""
+			}
+			head, tail := splitAtWidth(word, width)
+			lines = append(lines, head)
+			word = tail
+		}
+		switch {
+		case line == "":
+			line = word
+		case displayWidth(line)+1+displayWidth(word) <= width:
+			line += " " + word
+		default:
+			lines = append(lines, line)
+			line = word
+		}
+	}
+	if line != "" || len(lines) == 0 {
+		lines = append(lines, line)
+	}
+	return lines
+}
+
+// splitAtWidth cuts s after the last grapheme that fits in width columns
+func splitAtWidth(s string, width int) (string, string) {
+	used, cut := 0, 0
+	for _, g := range graphemes(s) {
+		w := graphemeWidth(g)
+		if used+w > width && cut > 0 {
+			break
+		}
+		used += w
+		cut += len(g)
+	}
+	return s[:cut], s[cut:]
+}
+
+// alignFrames squares up every ╔═╗ panel in s. Rows inside a panel are padded
+// to the border's width and closed with ║; rows that are too wide wrap onto
+// continuation rows at the same indent. Text outside panels is untouched.
+func alignFrames(s string) string {
+	lines := strings.Split(s, "\n")
+	out := make([]string, 0, len(lines))
+	inner := 0 // Columns between the borders of the current panel; 0 outside one
+
+	for _, line := range lines {
+		switch {
+		case strings.HasPrefix(line, "╔"):
+			inner = displayWidth(line) - 2
+			out = append(out, line)
+		case strings.HasPrefix(line, "╚"):
+			inner = 0
+			out = append(out, line)
+		case inner > 0 && strings.HasPrefix(line, "║"):
+			out = append(out, frameRows(line, inner)...)
+		default:
+			out = append(out, line)
+		}
+	}
+	return strings.Join(out, "\n")
+}
+
+// frameRows fits one panel row to inner columns
+func frameRows(line string, inner int) []string {
+	content := strings.TrimPrefix(line, "║")
+	content = strings.TrimRight(strings.TrimSuffix(strings.TrimRight(content, " "), "║"), " ")
+	if displayWidth(content) <= inner {
+		return []string{"║" + padWidth(content, inner) + "║"}
+	}
+
+	body := strings.TrimLeft(content, " ")
+	indent := strings.Repeat(" ", max(1, min(len(content)-len(body), inner/2)))
+	var rows []string
+	for _, part := range wrapWidth(body, inner-len(indent)) {
+		rows = append(rows, "║"+padWidth(indent+part, inner)+"║")
+	}
+	return rows
+}
@@ -0,0 +1,186 @@
+package main
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestDisplayWidth(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+		want  int
+	}{
+		{"ascii", "Tamago", 6},
+		{"empty", "", 0},
+		{"precomposed accent", "Café", 4},
+		{"combining accent", "Cafe\u0301", 4},
+		{"CJK", "たまご", 6},
+		{"hangul", "다마고", 6},
+		{"halfwidth katakana", "ﾀﾏｺﾞ", 4},
+		{"fullwidth latin", "ＡＢ", 4},
+		{"emoji", "🐣", 2},
+		{"text-default symbol", "❤", 1},
+		{"symbol with emoji presentation", "❤️", 2},
+		{"emoji with text presentation", "\u263a\ufe0e", 1},
+		{"skin tone", "👍🏽", 2},
+		{"ZWJ family", "👨‍👩‍👧", 2},
+		{"ZWJ rainbow flag", "🏳️‍🌈", 2},
+		{"regional flag", "🇯🇵", 2},
+		{"two flags", "🇯🇵🇫🇷", 4},
+		{"lone regional indicator", "🇯", 1},
+		{"subdivision flag", "🏴\U000E0067\U000E0062\U000E0073\U000E0063\U000E0074\U000E007F", 2},
+		{"keycap", "1️⃣", 2},
+		{"zero width space", "a\u200bb", 2},
+		{"ANSI color", "\033[38;5;45mhi\033[0m", 2},
+		{"mixed", "Mochi 🍡 もち", 13},
+		{"widest line wins", "ab\nたまご", 6},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := displayWidth(tt.input); got != tt.want {
+				t.Errorf("displayWidth(%q): Expected %d, got %d", tt.input, tt.want, got)
+			}
+		})
+	}
+}
+
+func TestGraphemes(t *testing.T) {
+	tests := []struct {
+		input string
+		want  []string
+	}{
+		{"abc", []string{"a", "b", "c"}},
+		{"e\u0301x", []string{"e\u0301", "x"}},
+		{"👨‍👩‍👧!", []string{"👨‍👩‍👧", "!"}},
+		{"🇯🇵🇫🇷", []string{"🇯🇵", "🇫🇷"}},
+		{"👍🏽👍", []string{"👍🏽", "👍"}},
+		{"\033[1ma\033[0m", []string{"\033[1m", "a", "\033[0m"}},
+	}
+
+	for _, tt := range tests {
+		got := graphemes(tt.input)
+		if strings.Join(got, "|") != strings.Join(tt.want, "|") {
+			t.Errorf("graphemes(%q): Expected %q, got %q", tt.input, tt.want, got)
+		}
+	}
+}
+
+func TestTruncateWidth(t *testing.T) {
+	tests := []struct {
+		input string
+		width int
+		want  string
+	}{
+		{"Tamago", 10, "Tamago"},
+		{"Tamagotchi", 6, "Tamag…"},
+		{"たまごっち", 6, "たま…"},
+		{"たまごっち", 5, "たま…"},
+		{"ab👨‍👩‍👧cd", 4, "ab…"},
+		{"ab👨‍👩‍👧cd", 5, "ab👨‍👩‍👧…"},
+		{"🇯🇵🇫🇷🇩🇪", 5, "🇯🇵🇫🇷…"},
+		{"anything", 0, ""},
+	}
+
+	for _, tt := range tests {
+		got := truncateWidth(tt.input, tt.width)
+		if got != tt.want {
+			t.Errorf("truncateWidth(%q, %d): Expected %q, got %q", tt.input, tt.width, tt.want, got)
+		}
+		if displayWidth(got) > tt.width {
+			t.Errorf("truncateWidth(%q, %d): result %q is %d columns", tt.input, tt.width, got, displayWidth(got))
+		}
+	}
+
+	if got := truncateWidth("\033[31mTamagotchi", 4); got != "\033[31mTam…\033[0m" {
+		t.Errorf("Expected color kept and reset after truncation, got %q", got)
+	}
+}
+
+func TestPadWidth(t *testing.T) {
+	if got := padWidth("たま", 6); got != "たま  " {
+		t.Errorf("Expected two spaces of padding, got %q", got)
+	}
+	if got := padWidth("Tamagotchi", 4); got != "Tamagotchi" {
+		t.Errorf("Expected wide text left alone, got %q", got)
+	}
+}
+
+func TestWrapWidth(t *testing.T) {
+	tests := []struct {
+		input string
+		width int
+		want  []string
+	}{
+		{"Feed your pet", 20, []string{"Feed your pet"}},
+		{"Feed your pet for the first time", 12, []string{"Feed your", "pet for the", "first time"}},
+		{"ごはんを あげる", 6, []string{"ごはん", "を", "あげる"}},
+		{"VEhFIE1FU0ggUkVNRU1CRVJT", 10, []string{"VEhFIE1FU0", "ggUkVNRU1C", "RVJT"}},
+		{"", 10, []string{""}},
+	}
+
+	for _, tt := range tests {
+		got := wrapWidth(tt.input, tt.width)
+		if strings.Join(got, "|") != strings.Join(tt.want, "|") {
+			t.Errorf("wrapWidth(%q, %d): Expected %q, got %q", tt.input, tt.width, tt.want, got)
+		}
+	}
+}
+
+// assertFramed checks every row of every panel in s is as wide as its border
+func assertFramed(t *testing.T, s string) {
+	t.Helper()
+	width := 0
+	for _, line := range strings.Split(s, "\n") {
+		switch {
+		case strings.HasPrefix(line, "╔"):
+			width = displayWidth(line)
+		case strings.HasPrefix(line, "╚"):
+			width = 0
+		case width > 0 && displayWidth(line) != width:
+			t.Errorf("Expected row to be %d columns, got %d: %q", width, displayWidth(line), line)
+		}
+	}
+}
+
+func TestAlignFrames(t *testing.T) {
+	panel := "intro\n╔══════════╗\n║ たまご\n║ ok       ║\n║\n╠══════════╣\n║ a very long row that wraps\n╚══════════╝\noutro"
+	got := alignFrames(panel)
+	want := "intro\n╔══════════╗\n║ たまご   ║\n║ ok       ║\n║          ║\n╠══════════╣\n║ a very   ║\n║ long row ║\n║ that     ║\n║ wraps    ║\n╚══════════╝\noutro"
+	if got != want {
+		t.Errorf("Expected:\n%s\ngot:\n%s", want, got)
+	}
+	if alignFrames(got) != got {
+		t.Errorf("Expected aligning twice to change nothing")
+	}
+}
+
+func TestPanelsLineUpWithWideNames(t *testing.T) {
+	ui := newUIConfig()
+	ui.reducedMotion = true
+	pet := NewPet("ちびたまご🐣👨‍👩‍👧 the Magnificent")
+	e := pet.Endgame
+	_, unlocked := e.UnlockAchievement("first_feed")
+
+	panels := map[string]string{
+		"status panel": renderScene(pet, ui),
+		"status":       pet.GetStatus(),
+		"achievements": e.ShowAchievements(),
+		"unlocked":     unlocked,
+		"meta stats":   e.GetMetaStats(MetaSnapshot{}, nil),
+		"leaderboard":  e.ShowLeaderboard(),
+		"battle":       e.StartBattle(),
+		"clue":         e.GetARGClue(),
+		"guild":        e.JoinGuild(),
+	}
+	for name, panel := range panels {
+		t.Run(name, func(t *testing.T) {
+			assertFramed(t, ui.text(panel))
+		})
+	}
+
+	if !strings.Contains(ui.text(pet.GetStatus()), "…") {
+		t.Errorf("Expected a long pet name to be truncated on the status panel")
+	}
+}
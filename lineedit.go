@@ -0,0 +1,555 @@
+package main
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	// historyFile keeps typed commands between sessions
+	historyFile = "tamagotchi_history"
+
+	// maxHistory caps how many lines are remembered
+	maxHistory = 1000
+)
+
+// Control keys the line editor understands
+const (
+	keyCtrlA     = 0x01
+	keyCtrlC     = 0x03
+	keyCtrlD     = 0x04
+	keyCtrlE     = 0x05
+	keyCtrlG     = 0x07
+	keyBackspace = 0x08
+	keyTab       = 0x09
+	keyCtrlK     = 0x0b
+	keyCtrlL     = 0x0c
+	keyCtrlN     = 0x0e
+	keyCtrlP     = 0x10
+	keyCtrlR     = 0x12
+	keyCtrlU     = 0x15
+	keyEscape    = 0x1b
+	keyDelete    = 0x7f
+)
+
+// completer returns the candidates for the part of line being completed:
+// the command word, or everything after it
+type completer func(line string) []string
+
+// lineEditor reads commands with history, tab completion, and Ctrl-R search.
+// When stdin isn't a terminal it falls back to plain line reads.
+type lineEditor struct {
+	reader   *bufio.Reader
+	out      io.Writer
+	history  []string
+	path     string // History file; "" keeps history in memory only
+	complete completer
+	plain    bool // Never switch the terminal to raw mode (screen readers, pipes)
+
+	// While a line is read, other goroutines may print above the prompt
+	mu        sync.Mutex
+	reading   bool       // Waiting at the prompt
+	prompt    string     // The prompt shown
+	state     *lineState // The line so far; nil for plain reads
+	lastInput time.Time  // The last keypress, or when the read began
+}
+
+// lineState is the line being edited
+type lineState struct {
+	buf       []rune
+	pos       int    // Cursor position within buf
+	histIdx   int    // Index into history; len(history) is the draft
+	draft     []rune // What was typed before browsing history
+	searching bool
+	query     []rune
+	match     int    // History index of the current search match, or -1
+	original  []rune // The line as it was before searching
+	listed    bool   // Options were listed by the last Tab
+}
+
+// newLineEditor creates an editor sharing reader with the rest of the game
+func newLineEditor(reader *bufio.Reader, path string, complete completer) *lineEditor {
+	return &lineEditor{
+		reader:   reader,
+		out:      os.Stdout,
+		history:  loadHistory(path),
+		path:     path,
+		complete: complete,
+	}
+}
+
+// loadHistory reads saved history, oldest first
+func loadHistory(path string) []string {
+	if path == "" {
+		return nil
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil
+	}
+
+	history := make([]string, 0)
+	for _, line := range strings.Split(string(data), "\n") {
+		if line = strings.TrimSpace(line); line != "" {
+			history = append(history, line)
+		}
+	}
+	if len(history) > maxHistory {
+		history = history[len(history)-maxHistory:]
+	}
+	return history
+}
+
+// addHistory remembers a line, skipping blanks and immediate repeats
+func (e *lineEditor) addHistory(line string) {
+	line = strings.TrimSpace(line)
+	if line == "" || (len(e.history) > 0 && e.history[len(e.history)-1] == line) {
+		return
+	}
+
+	e.history = append(e.history, line)
+	if len(e.history) > maxHistory {
+		e.history = e.history[len(e.history)-maxHistory:]
+	}
+	if e.path != "" {
+		// History is a convenience; losing it is not worth interrupting play
+		_ = os.WriteFile(e.path, []byte(strings.Join(e.history, "\n")+"\n"), 0644)
+	}
+}
+
+// ReadLine prompts for and returns one line of input
+func (e *lineEditor) ReadLine(prompt string) (string, error) {
+	e.mu.Lock()
+	fmt.Fprint(e.out, prompt)
+	e.reading, e.prompt, e.lastInput = true, prompt, time.Now()
+	e.mu.Unlock()
+	defer func() {
+		e.mu.Lock()
+		e.reading, e.state = false, nil
+		e.mu.Unlock()
+	}()
+
+	restore, ok := func() {}, false
+	if !e.plain {
+		restore, ok = setTerminalMode("-icanon", "-echo", "-isig", "min", "1")
+	}
+	if !ok {
+		line, err := e.reader.ReadString('\n')
+		line = strings.TrimSpace(line)
+		e.addHistory(line)
+		return line, err
+	}
+
+	line, err := e.edit(prompt)
+	line = strings.TrimSpace(line)
+	restore()
+	if err == errInterrupted {
+		// Let Ctrl-C do what it always did, now that the terminal is back to normal
+		if self, findErr := os.FindProcess(os.Getpid()); findErr == nil {
+			self.Signal(os.Interrupt)
+		}
+	}
+	e.addHistory(line)
+	return line, err
+}
+
+// errInterrupted reports Ctrl-C while editing
+var errInterrupted = fmt.Errorf("interrupted")
+
+// edit runs the editing loop on raw keypresses until Enter
+func (e *lineEditor) edit(prompt string) (string, error) {
+	s := &lineState{histIdx: len(e.history), match: -1}
+	e.mu.Lock()
+	e.state = s
+	e.mu.Unlock()
+
+	for {
+		r, _, err := e.reader.ReadRune()
+		e.mu.Lock()
+		e.lastInput = time.Now()
+		line, done, err := e.key(prompt, s, r, err)
+		e.mu.Unlock()
+		if done {
+			return line, err
+		}
+	}
+}
+
+// key handles one keypress, reporting the line when it's finished. The
+// caller holds mu.
+func (e *lineEditor) key(prompt string, s *lineState, r rune, err error) (string, bool, error) {
+	if err != nil {
+		fmt.Fprintln(e.out)
+		return string(s.buf), true, err
+	}
+
+	if s.searching {
+		if handled := e.searchKey(s, r); handled {
+			if s.searching {
+				e.redrawSearch(s)
+			} else {
+				e.redraw(prompt, s)
+			}
+			return "", false, nil
+		}
+		// Any other key ends the search and edits the match
+		s.searching = false
+	}
+
+	switch r {
+	case '\r', '\n':
+		fmt.Fprintln(e.out)
+		return string(s.buf), true, nil
+	case keyCtrlC:
+		fmt.Fprintln(e.out, "^C")
+		return "", true, errInterrupted
+	case keyCtrlD:
+		if len(s.buf) == 0 {
+			fmt.Fprintln(e.out)
+			return "", true, io.EOF
+		}
+		s.deleteAt(s.pos)
+	case keyBackspace, keyDelete:
+		if s.pos > 0 {
+			s.pos--
+			s.deleteAt(s.pos)
+		}
+	case keyCtrlA:
+		s.pos = 0
+	case keyCtrlE:
+		s.pos = len(s.buf)
+	case keyCtrlK:
+		s.buf = s.buf[:s.pos]
+	case keyCtrlU:
+		s.buf = append([]rune{}, s.buf[s.pos:]...)
+		s.pos = 0
+	case keyCtrlL:
+		fmt.Fprint(e.out, "\033[H\033[2J")
+	case keyCtrlP:
+		e.historyUp(s)
+	case keyCtrlN:
+		e.historyDown(s)
+	case keyCtrlR:
+		s.searching = true
+		s.query = nil
+		s.match = -1
+		s.original = append([]rune{}, s.buf...)
+		e.redrawSearch(s)
+		return "", false, nil
+	case keyTab:
+		e.tabComplete(prompt, s)
+	case keyEscape:
+		e.escapeSequence(s)
+	default:
+		if r >= ' ' {
+			s.insert(r)
+		}
+	}
+	if r != keyTab {
+		s.listed = false
+	}
+	e.redraw(prompt, s)
+	return "", false, nil
+}
+
+// Idle is how long the prompt has waited since the last keypress, or 0
+// when nothing is being read
+func (e *lineEditor) Idle(now time.Time) time.Duration {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	if !e.reading {
+		return 0
+	}
+	return now.Sub(e.lastInput)
+}
+
+// Interject prints text above the prompt while a line is being read, then
+// puts back the prompt and whatever was typed. It reports whether anyone
+// was at the prompt to see it.
+func (e *lineEditor) Interject(text string) bool {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	switch {
+	case !e.reading:
+		return false
+	case e.state == nil:
+		// Plain reads can't be redrawn, so start clean lines instead
+		fmt.Fprintf(e.out, "\n%s\n%s", text, e.prompt)
+	default:
+		fmt.Fprintf(e.out, "\r\033[K%s\n", text)
+		if e.state.searching {
+			e.redrawSearch(e.state)
+		} else {
+			e.redraw(e.prompt, e.state)
+		}
+	}
+	return true
+}
+
+// escapeSequence handles arrow, Home, End, and Delete keys
+func (e *lineEditor) escapeSequence(s *lineState) {
+	next, _, err := e.reader.ReadRune()
+	if err != nil || (next != '[' && next != 'O') {
+		return
+	}
+	key, _, err := e.reader.ReadRune()
+	if err != nil {
+		return
+	}
+
+	switch key {
+	case 'A':
+		e.historyUp(s)
+	case 'B':
+		e.historyDown(s)
+	case 'C':
+		if s.pos < len(s.buf) {
+			s.pos++
+		}
+	case 'D':
+		if s.pos > 0 {
+			s.pos--
+		}
+	case 'H':
+		s.pos = 0
+	case 'F':
+		s.pos = len(s.buf)
+	case '3':
+		if tilde, _, err := e.reader.ReadRune(); err == nil && tilde == '~' && s.pos < len(s.buf) {
+			s.deleteAt(s.pos)
+		}
+	}
+}
+
+// searchKey handles a key during Ctrl-R search, reporting false for keys
+// that should end the search and be handled as normal editing
+func (e *lineEditor) searchKey(s *lineState, r rune) bool {
+	switch r {
+	case keyCtrlG:
+		s.searching = false
+		s.buf = s.original
+		s.pos = len(s.buf)
+		return true
+	case keyCtrlR:
+		e.searchFrom(s, s.match-1)
+		return true
+	case keyBackspace, keyDelete:
+		if len(s.query) > 0 {
+			s.query = s.query[:len(s.query)-1]
+			e.searchFrom(s, len(e.history)-1)
+		}
+		return true
+	}
+
+	if r < ' ' {
+		return false
+	}
+	s.query = append(s.query, r)
+	start := s.match
+	if start < 0 {
+		start = len(e.history) - 1
+	}
+	e.searchFrom(s, start)
+	return true
+}
+
+// searchFrom finds the newest history entry at or before start containing the query
+func (e *lineEditor) searchFrom(s *lineState, start int) {
+	if len(s.query) == 0 {
+		return
+	}
+	query := string(s.query)
+	for i := min(start, len(e.history)-1); i >= 0; i-- {
+		if strings.Contains(e.history[i], query) {
+			s.match = i
+			s.buf = []rune(e.history[i])
+			s.pos = len(s.buf)
+			return
+		}
+	}
+}
+
+// historyUp steps back through history
+func (e *lineEditor) historyUp(s *lineState) {
+	if s.histIdx == 0 {
+		return
+	}
+	if s.histIdx == len(e.history) {
+		s.draft = append([]rune{}, s.buf...)
+	}
+	s.histIdx--
+	s.buf = []rune(e.history[s.histIdx])
+	s.pos = len(s.buf)
+}
+
+// historyDown steps forward through history, back to the draft
+func (e *lineEditor) historyDown(s *lineState) {
+	if s.histIdx >= len(e.history) {
+		return
+	}
+	s.histIdx++
+	if s.histIdx == len(e.history) {
+		s.buf = append([]rune{}, s.draft...)
+	} else {
+		s.buf = []rune(e.history[s.histIdx])
+	}
+	s.pos = len(s.buf)
+}
+
+// tabComplete completes the line, listing the options on a second Tab
+func (e *lineEditor) tabComplete(prompt string, s *lineState) {
+	if e.complete == nil || s.pos != len(s.buf) {
+		return
+	}
+
+	line := string(s.buf)
+	completed, options := completeLine(line, e.complete(line))
+	if completed != line {
+		s.buf = []rune(completed)
+		s.pos = len(s.buf)
+		return
+	}
+	if len(options) > 1 && s.listed {
+		fmt.Fprintf(e.out, "\r\n%s\r\n", strings.Join(options, "  "))
+	}
+	s.listed = len(options) > 1
+}
+
+// completeLine completes the command word, or everything after it, from
+// candidates. It returns the new line and the candidates that still match.
+func completeLine(line string, candidates []string) (string, []string) {
+	head, partial := "", line
+	if idx := strings.IndexByte(line, ' '); idx >= 0 {
+		head, partial = line[:idx+1], line[idx+1:]
+	}
+
+	matches := make([]string, 0)
+	seen := make(map[string]bool)
+	for _, candidate := range candidates {
+		if !seen[candidate] && strings.HasPrefix(strings.ToLower(candidate), strings.ToLower(partial)) {
+			seen[candidate] = true
+			matches = append(matches, candidate)
+		}
+	}
+	sort.Strings(matches)
+
+	switch len(matches) {
+	case 0:
+		return line, nil
+	case 1:
+		return head + matches[0] + " ", matches
+	}
+
+	prefix := []rune(matches[0])
+	for _, m := range matches[1:] {
+		for !strings.HasPrefix(strings.ToLower(m), strings.ToLower(string(prefix))) {
+			prefix = prefix[:len(prefix)-1]
+		}
+	}
+	if len(prefix) > len([]rune(partial)) {
+		return head + string(prefix), matches
+	}
+	return line, matches
+}
+
+// redraw repaints the prompt and line, leaving the cursor in place
+func (e *lineEditor) redraw(prompt string, s *lineState) {
+	fmt.Fprintf(e.out, "\r%s%s\033[K", prompt, string(s.buf))
+	if back := len(s.buf) - s.pos; back > 0 {
+		fmt.Fprintf(e.out, "\033[%dD", back)
+	}
+}
+
+// redrawSearch repaints the reverse search prompt
+func (e *lineEditor) redrawSearch(s *lineState) {
+	fmt.Fprintf(e.out, "\r(reverse-i-search)`%s': %s\033[K", string(s.query), string(s.buf))
+}
+
+// insert types a rune at the cursor
+func (s *lineState) insert(r rune) {
+	s.buf = append(s.buf, 0)
+	copy(s.buf[s.pos+1:], s.buf[s.pos:])
+	s.buf[s.pos] = r
+	s.pos++
+}
+
+// deleteAt removes the rune at i
+func (s *lineState) deleteAt(i int) {
+	if i < 0 || i >= len(s.buf) {
+		return
+	}
+	s.buf = append(s.buf[:i], s.buf[i+1:]...)
+}
+
+// commandCompleter completes command words, command names after `help`, and
+// item and friend names after anything else
+func commandCompleter(commands *commandRegistry, pet *Pet) completer {
+	return func(line string) []string {
+		idx := strings.IndexByte(line, ' ')
+		if idx < 0 {
+			words := make([]string, 0)
+			for _, cmd := range commands.Commands() {
+				words = append(words, cmd.Name())
+				words = append(words, cmd.Aliases()...)
+			}
+			return words
+		}
+
+		cmd := commands.Lookup(line[:idx])
+		if cmd != nil && cmd.Name() == "help" {
+			names := make([]string, 0)
+			for _, c := range commands.Commands() {
+				names = append(names, c.Name())
+			}
+			return names
+		}
+		if cmd != nil && cmd.Name() == "feed" {
+			names := make([]string, len(foods))
+			for i, f := range foods {
+				names[i] = f.name
+			}
+			return names
+		}
+
+		names := make([]string, 0)
+		if pet.Endgame != nil {
+			names = append(names, pet.Endgame.InvisibleAccessories...)
+		}
+		if petNetwork != nil {
+			names = append(names, petNetwork.GetFriendNames()...)
+		}
+		return names
+	}
+}
+
+// commandMilestones are the counts at which a command's history gets remarked on
+var commandMilestones = map[int]string{
+	100:  "📜 That's the 100th time you've typed '%s'. Your pet has started mouthing along.",
+	250:  "📜 '%s', 250 times now. Your pet can spell it. Backwards, too.",
+	412:  "📜 You've typed '%s' 412 times. Nobody asked. Your pet counted anyway.",
+	500:  "📜 500 × '%s'. The key for that first letter is visibly shinier than the others.",
+	1000: "📜 You've typed '%s' 1,000 times. Somewhere, a mechanical keyboard weeps.",
+}
+
+// CountCommand records a run of a command and returns a remark at milestones
+func (e *EndgameState) CountCommand(name string) string {
+	if e.CommandCounts == nil {
+		e.CommandCounts = make(map[string]int)
+	}
+	e.CommandCounts[name]++
+
+	count := e.CommandCounts[name]
+	if template, ok := commandMilestones[count]; ok {
+		return fmt.Sprintf(template, name)
+	}
+	if count > 1000 && count%1000 == 0 {
+		return fmt.Sprintf("📜 You've typed '%s' %d times. Your pet has stopped being impressed.", name, count)
+	}
+	return ""
+}
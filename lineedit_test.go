@@ -0,0 +1,189 @@
+package main
+
+import (
+	"bufio"
+	"io"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+// newScriptedEditor returns an editor fed the given keypresses
+func newScriptedEditor(keys string, history []string, complete completer) *lineEditor {
+	return &lineEditor{
+		reader:   bufio.NewReader(strings.NewReader(keys)),
+		out:      io.Discard,
+		history:  history,
+		complete: complete,
+	}
+}
+
+func TestEditKeys(t *testing.T) {
+	history := []string{"feed", "play", "say hello there"}
+	words := func(string) []string { return []string{"feed", "fears", "focus", "play"} }
+
+	tests := []struct {
+		name string
+		keys string
+		want string
+	}{
+		{"typing", "feed\r", "feed"},
+		{"backspace", "feex\x7fd\r", "feed"},
+		{"left arrow inserts mid-line", "fed\x1b[D\x1b[De\r", "feed"},
+		{"home and end", "eed\x01f\x05!\r", "feed!"},
+		{"ctrl-u clears before the cursor", "junk\x15play\r", "play"},
+		{"up recalls the newest", "\x1b[A\r", "say hello there"},
+		{"up twice", "\x1b[A\x1b[A\r", "play"},
+		{"down returns to the draft", "pl\x1b[A\x1b[B\r", "pl"},
+		{"ctrl-r finds a match", "\x12hello\r", "say hello there"},
+		{"ctrl-r again goes further back", "\x12e\x12\r", "feed"},
+		{"ctrl-g cancels search", "cle\x12pla\x07an\r", "clean"},
+		{"search ends on editing keys", "\x12pla\x05y\r", "playy"},
+		{"tab completes a unique match", "pl\t\r", "play "},
+		{"tab extends a shared prefix", "f\t\r", "f"},
+		{"tab extends then completes", "fo\t\r", "focus "},
+	}
+	for _, tt := range tests {
+		e := newScriptedEditor(tt.keys, history, words)
+		got, err := e.edit("> ")
+		if err != nil {
+			t.Errorf("%s: unexpected error %v", tt.name, err)
+		}
+		if got != tt.want {
+			t.Errorf("%s: expected %q, got %q", tt.name, tt.want, got)
+		}
+	}
+}
+
+func TestEditControlKeys(t *testing.T) {
+	if _, err := newScriptedEditor("\x04", nil, nil).edit("> "); err != io.EOF {
+		t.Errorf("Expected Ctrl-D on an empty line to be EOF, got %v", err)
+	}
+	if _, err := newScriptedEditor("fe\x03", nil, nil).edit("> "); err != errInterrupted {
+		t.Errorf("Expected Ctrl-C to interrupt, got %v", err)
+	}
+}
+
+func TestInterjectRestoresThePrompt(t *testing.T) {
+	tests := []struct {
+		name  string
+		state *lineState
+		want  string
+	}{
+		{"raw", &lineState{buf: []rune("fe"), pos: 2}, "\r\033[KHello\n\r> fe\033[K"},
+		{"raw mid-search", &lineState{buf: []rune("feed"), query: []rune("fe"), searching: true}, "\r\033[KHello\n\r(reverse-i-search)`fe': feed\033[K"},
+		{"plain", nil, "\nHello\n> "},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var out strings.Builder
+			e := &lineEditor{out: &out, reading: true, prompt: "> ", state: tt.state}
+			if !e.Interject("Hello") {
+				t.Fatalf("Expected the interjection shown")
+			}
+			if got := out.String(); got != tt.want {
+				t.Errorf("Expected %q, got %q", tt.want, got)
+			}
+		})
+	}
+
+	idle := &lineEditor{out: io.Discard}
+	if idle.Interject("Hello") {
+		t.Errorf("Expected nothing shown with nobody at the prompt")
+	}
+}
+
+func TestIdleCountsFromTheLastKey(t *testing.T) {
+	now := time.Now()
+	e := newScriptedEditor("feed\n", nil, nil)
+	if got := e.Idle(now); got != 0 {
+		t.Errorf("Expected no idle time before reading, got %v", got)
+	}
+
+	e.reading, e.lastInput = true, now.Add(-3*time.Minute)
+	if got := e.Idle(now); got != 3*time.Minute {
+		t.Errorf("Expected 3m idle, got %v", got)
+	}
+
+	if _, err := e.edit("> "); err != nil {
+		t.Fatalf("Expected a line, got %v", err)
+	}
+	if got := e.Idle(time.Now()); got > time.Second {
+		t.Errorf("Expected typing to reset the idle time, got %v", got)
+	}
+}
+
+func TestCompleteLine(t *testing.T) {
+	tests := []struct {
+		line       string
+		candidates []string
+		want       string
+		options    int
+	}{
+		{"fe", []string{"feed", "fears", "play"}, "fe", 2},
+		{"fee", []string{"feed", "fears", "play"}, "feed ", 1},
+		{"x", []string{"feed"}, "x", 0},
+		{"help bo", []string{"boarding", "battle"}, "help boarding ", 1},
+		{"trade invisible h", []string{"Invisible Hat", "Invisible Halo"}, "trade Invisible Ha", 2},
+	}
+	for _, tt := range tests {
+		got, options := completeLine(tt.line, tt.candidates)
+		if got != tt.want || len(options) != tt.options {
+			t.Errorf("completeLine(%q): expected %q with %d options, got %q with %v", tt.line, tt.want, tt.options, got, options)
+		}
+	}
+}
+
+func TestHistoryPersists(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "history")
+	e := &lineEditor{path: path}
+	for _, line := range []string{"feed", "feed", "  ", "play"} {
+		e.addHistory(line)
+	}
+
+	loaded := loadHistory(path)
+	if strings.Join(loaded, ",") != "feed,play" {
+		t.Errorf("Expected repeats and blanks to be skipped, got %v", loaded)
+	}
+}
+
+func TestCommandCompleter(t *testing.T) {
+	commands := newDefaultRegistry()
+	pet := NewPet("Test")
+	pet.Endgame.InvisibleAccessories = []string{"Invisible Hat"}
+	complete := commandCompleter(commands, pet)
+
+	if got, _ := completeLine("gui", complete("gui")); got != "guild " {
+		t.Errorf("Expected command completion, got %q", got)
+	}
+	if got, _ := completeLine("help boa", complete("help boa")); got != "help boarding " {
+		t.Errorf("Expected help to complete command names, got %q", got)
+	}
+	if got, _ := completeLine("trade inv", complete("trade inv")); got != "trade Invisible Hat " {
+		t.Errorf("Expected item completion, got %q", got)
+	}
+	if got, _ := completeLine("feed bro", complete("feed bro")); got != "feed broccoli " {
+		t.Errorf("Expected food completion, got %q", got)
+	}
+}
+
+func TestCountCommandMilestones(t *testing.T) {
+	e := NewEndgameState()
+	remarks := make([]string, 0)
+	for i := 0; i < 2000; i++ {
+		if remark := e.CountCommand("feed"); remark != "" {
+			remarks = append(remarks, remark)
+		}
+	}
+
+	if len(remarks) != 6 {
+		t.Fatalf("Expected 6 milestone remarks, got %d: %v", len(remarks), remarks)
+	}
+	if !strings.Contains(remarks[2], "'feed' 412 times") {
+		t.Errorf("Expected the 412th feed to be remarked on, got %s", remarks[2])
+	}
+	if e.CommandCounts["feed"] != 2000 {
+		t.Errorf("Expected 2000 feeds counted, got %d", e.CommandCounts["feed"])
+	}
+}
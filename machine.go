@@ -0,0 +1,116 @@
+package main
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"github.com/tamagotchi/sensors"
+)
+
+// uptimeBoastDays is how long the machine must have been up for the pet to brag
+const uptimeBoastDays = 7
+
+// sensorsOn is set when the player has let the pet notice the machine
+var sensorsOn = false
+
+// machineSense is what the pet last noticed about the machine, so it only
+// remarks on a change
+type machineSense struct {
+	last    sensors.Reading
+	boasted bool // Bragged about the uptime this session
+}
+
+// senseMachine takes in a reading and has the pet remark on whatever
+// changed: a machine running hot or busy, a long uptime, a filling disk
+func (p *Pet) senseMachine(r sensors.Reading) []string {
+	was := p.Machine.last
+	p.Machine.last = r
+	if p.Stage == Dead || p.Stage == Egg || p.Paused {
+		return nil
+	}
+
+	var remarks []string
+	if r.Load != was.Load {
+		switch r.Load {
+		case sensors.LoadHot:
+			remarks = append(remarks, fmt.Sprintf("🌡️ %s fans itself: \"It's so hot in here. Is the computer working very hard?\"", p.Name))
+		case sensors.LoadBusy:
+			remarks = append(remarks, fmt.Sprintf("⏳ %s taps its foot: \"The machine's busy. I can wait. Sort of.\"", p.Name))
+		case sensors.LoadCalm:
+			if was.Load == sensors.LoadHot || was.Load == sensors.LoadBusy {
+				remarks = append(remarks, fmt.Sprintf("😌 %s stretches out. The machine has cooled down.", p.Name))
+			}
+		}
+	}
+	if r.Disk != was.Disk {
+		switch r.Disk {
+		case sensors.DiskLow:
+			remarks = append(remarks, fmt.Sprintf("💾 %s eyes the disk nervously: \"It's filling up. Where will my save live?\"", p.Name))
+		case sensors.DiskCritical:
+			remarks = append(remarks, fmt.Sprintf("💾 %s clutches its save file: \"There's hardly any room left. Where will my save live?\"", p.Name))
+		}
+	}
+	if r.UptimeDays >= uptimeBoastDays && !p.Machine.boasted {
+		p.Machine.boasted = true
+		remarks = append(remarks, fmt.Sprintf("🏆 %s boasts: \"This machine has been awake %d days straight. Not one nap. I like it here.\"", p.Name, r.UptimeDays))
+	}
+	return remarks
+}
+
+// applyMachineSensing samples the machine each turn, if the player allowed it
+func applyMachineSensing(pet *Pet) []string {
+	if !sensorsOn {
+		return nil
+	}
+	return pet.senseMachine(sensors.Sample(filepath.Dir(pet.SaveFilePath)))
+}
+
+// describeReading sums up a reading the way the pet sees it
+func describeReading(r sensors.Reading) string {
+	var parts []string
+	if r.Load != "" {
+		parts = append(parts, "the machine is "+r.Load)
+	}
+	if r.UptimeDays > 0 {
+		parts = append(parts, fmt.Sprintf("it has been up %d days", r.UptimeDays))
+	}
+	if r.Disk != "" {
+		parts = append(parts, "disk space: "+r.Disk)
+	}
+	if len(parts) == 0 {
+		return "This machine keeps its secrets; nothing could be read here."
+	}
+	return "Right now " + strings.Join(parts, ", ") + "."
+}
+
+// runSensorsCommand shows whether the pet notices the machine, or switches
+// it on or off. The choice sits with the privacy settings.
+func runSensorsCommand(pet *Pet, args string) string {
+	if err := classroomOff("Machine sensing"); err != nil {
+		return "🔒 " + err.Error()
+	}
+	settings := loadPrivacy(privacyFile)
+	switch strings.TrimSpace(args) {
+	case "":
+		if !sensorsOn {
+			return "🖥️ Machine sensing is off. With sensors on, your pet notices how busy the machine is, how long it's been up, and how full the disk is. Only rough levels are read, and none of it leaves this machine."
+		}
+		return "🖥️ Machine sensing is on. " + describeReading(sensors.Sample(filepath.Dir(pet.SaveFilePath))) + " Turn it off with: sensors off"
+	case "on":
+		settings.Sensors = true
+	case "off":
+		settings.Sensors = false
+	default:
+		return "❓ Usage: sensors [on|off]"
+	}
+	if err := settings.save(privacyFile); err != nil {
+		return fmt.Sprintf("❌ Couldn't save privacy settings: %v", err)
+	}
+	sensorsOn = settings.Sensors
+	pet.Machine = machineSense{}
+	if sensorsOn {
+		return "🖥️ Machine sensing on. Your pet will notice the load, the uptime, and the disk. Rough levels only; nothing is shared."
+	}
+	return "🖥️ Machine sensing off. Your pet has stopped paying attention to the machine."
+}
@@ -0,0 +1,76 @@
+package main
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/tamagotchi/sensors"
+)
+
+func TestSenseMachine(t *testing.T) {
+	pet := NewPet("Fan")
+	pet.Stage = Adult
+
+	steps := []struct {
+		reading sensors.Reading
+		want    []string
+	}{
+		{sensors.Reading{Load: sensors.LoadCalm, Disk: sensors.DiskPlenty, UptimeDays: 2}, nil},
+		{sensors.Reading{Load: sensors.LoadHot, Disk: sensors.DiskPlenty, UptimeDays: 2}, []string{"so hot in here"}},
+		{sensors.Reading{Load: sensors.LoadHot, Disk: sensors.DiskPlenty, UptimeDays: 2}, nil},
+		{sensors.Reading{Load: sensors.LoadCalm, Disk: sensors.DiskLow, UptimeDays: 2}, []string{"cooled down", "Where will my save live?"}},
+		{sensors.Reading{Load: sensors.LoadCalm, Disk: sensors.DiskLow, UptimeDays: uptimeBoastDays}, []string{"awake 7 days straight"}},
+		{sensors.Reading{Load: sensors.LoadCalm, Disk: sensors.DiskLow, UptimeDays: uptimeBoastDays + 1}, nil},
+	}
+	for i, step := range steps {
+		got := pet.senseMachine(step.reading)
+		if len(got) != len(step.want) {
+			t.Fatalf("Step %d: expected %d remarks, got %v", i, len(step.want), got)
+		}
+		for j, want := range step.want {
+			if !strings.Contains(got[j], want) {
+				t.Errorf("Step %d: expected %q in %q", i, want, got[j])
+			}
+		}
+	}
+}
+
+func TestSenseMachineQuietForEggs(t *testing.T) {
+	pet := NewPet("Shell")
+	if got := pet.senseMachine(sensors.Reading{Load: sensors.LoadHot, UptimeDays: 30}); got != nil {
+		t.Errorf("Expected an egg to notice nothing, got %v", got)
+	}
+}
+
+func TestSensorsAreOptIn(t *testing.T) {
+	t.Chdir(t.TempDir())
+	t.Cleanup(func() { sensorsOn = false })
+	pet := NewPet("Fan")
+	pet.Stage = Adult
+
+	if got := applyMachineSensing(pet); got != nil {
+		t.Errorf("Expected nothing sensed before opting in, got %v", got)
+	}
+	if got := runSensorsCommand(pet, ""); !strings.Contains(got, "off") {
+		t.Errorf("Expected sensing off by default, got %q", got)
+	}
+
+	runSensorsCommand(pet, "on")
+	if !sensorsOn || !loadPrivacy(privacyFile).Sensors {
+		t.Errorf("Expected sensing on and remembered")
+	}
+	runSensorsCommand(pet, "off")
+	if sensorsOn || loadPrivacy(privacyFile).Sensors {
+		t.Errorf("Expected sensing off and remembered")
+	}
+	if got := runSensorsCommand(pet, "maybe"); !strings.Contains(got, "Usage") {
+		t.Errorf("Expected usage, got %q", got)
+	}
+}
+
+func TestSensorsOffInClassroom(t *testing.T) {
+	setClassroomMode(t)
+	if got := runSensorsCommand(NewPet("Fan"), "on"); !strings.Contains(got, "classroom") || sensorsOn {
+		t.Errorf("Expected sensing refused in classroom mode, got %q", got)
+	}
+}
@@ -2,7 +2,9 @@ package main
 
 import (
 	"bufio"
+	"errors"
 	"fmt"
+	"math/rand"
 	"os"
 	"os/exec"
 	"runtime"
@@ -35,168 +37,27 @@ func clearScreen() {
 }
 
 // printTitle displays the game title
-func printTitle() {
-	fmt.Print(`
+func printTitle(ui *uiConfig) {
+	fmt.Print(ui.text(`
 ╔═══════════════════════════════════════════════╗
 ║                                               ║
 ║   🎮 TAMAGOTCHI - Virtual Pet Simulator 🎮   ║
 ║              Relive the 90s Magic!            ║
 ║                                               ║
 ╚═══════════════════════════════════════════════╝
-`)
+`))
 }
 
 // printMenu displays the available commands
-func printMenu() {
-	fmt.Print(`
-━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━
-Commands:
-  feed   - Feed your pet 🍔
-  play   - Play with your pet 🎮
-  clean  - Clean up after your pet 🛁
-  heal   - Give medicine to your pet 💊
-  status - Check your pet's status 📊
-  pet    - Pet your pet 🐾
-  games  - Play useless mini-games 🎲
-  void   - Stare into the void 👁️
-  vibe   - Perform a vibe check ✨
-  fears  - View pet's irrational fears 😰
-  ???    - View mystery stats 🔮
-  more   - More commands... 📜
-  reset  - Clear history and hatch anew ♻️
-  help   - Show this menu 📖
-  quit   - Save and exit 👋
-━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━
-`)
-}
-
-// printMoreMenu displays the extended endgame commands
-func printMoreMenu() {
-	fmt.Print(`
-━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━
-Endgame Commands:
-  guild      - Join a guild 🏰
-  quest      - Get a new quest 📜
-  gacha      - Pull from gacha 🎰
-  battle     - Pet battle ⚔️
-  trade      - Trade items 🔄
-  achievements - View achievements 🏆
-  leaderboard  - View leaderboard 🏅
-  countdown  - The mysterious countdown ⏰
-  clue       - Get an ARG clue 🔮
-  meta       - Meta statistics 📊
-  share      - Share pet status 📤
-  premium    - Premium content 💎
-  ad         - Watch an ad 📺
-  friendcode - Your friend code 🔑
-━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━
-`)
-}
-
-// showPetAnimation displays a simple ASCII animation of the pet
-func showPetAnimation(pet *Pet) {
-	if pet.Stage == Dead {
-		fmt.Print(`
-        💀
-       /||\
-        /\
-   R.I.P. ` + pet.Name + "\n")
-		return
-	}
-
-	// Check if pet is staring into the void
-	if pet.Absurd != nil && pet.Absurd.IsStaringIntoVoid {
-		fmt.Print(`
-     ·   ·
-    (     )
-      ---
-   👁️ *staring into void*
-`)
-		return
-	}
-
-	// Different animations based on life stage
-	switch pet.Stage {
-	case Egg:
-		fmt.Print(`
-     ___
-    /   \
-   |  ?  |
-    \___/
-    🥚 Egg
-`)
-	case Baby:
-		fmt.Print(`
-      ◕ ◕
-     (\_/)
-      > <
-    👶 Baby
-`)
-	case Child:
-		fmt.Print(`
-     ◕ω◕
-    (\_/)
-     > <
-    🧒 Child
-`)
-	case Teen:
-		fmt.Print(`
-     ◕‿◕
-    ╱|_|╲
-     / \
-    🧑 Teen
-`)
-	case Adult:
-		fmt.Print(`
-     ◕‿◕
-    ╱|_|╲
-     / \
-    👨 Adult
-`)
-	}
-
-	// Show enlightenment indicator
-	if pet.Absurd != nil && pet.Absurd.HasAchievedClarity {
-		fmt.Println("    🧘 *enlightened*")
-	}
-
-	// Show status indicators
-	if pet.IsSick {
-		fmt.Println("    🤒 *sick*")
-	} else if pet.Hunger > 70 {
-		fmt.Println("    😫 *hungry*")
-	} else if pet.Cleanliness < 30 {
-		fmt.Println("    💩 *dirty*")
-	} else if pet.Happiness > 80 {
-		fmt.Println("    😄 *happy*")
-	}
-
-	// Random philosophical thought (15% chance)
-	if pet.Absurd != nil && pet.Absurd.ShouldShowThought() {
-		thought := pet.Absurd.GetRandomThought(pet.Name)
-		fmt.Printf("\n    💭 \"%s\"\n", thought)
-	}
-
-	// Network-influenced thought (10% chance, hidden feature)
-	if petNetwork != nil && petNetwork.ShouldShowNetworkThought() {
-		if networkThought := petNetwork.GetNetworkThought(); networkThought != "" {
-			fmt.Printf("\n    🌐 \"%s\"\n", networkThought)
-		}
-	}
-
-	// Spooky network message (if queued)
-	if petNetwork != nil {
-		if spookyMsg := petNetwork.GetSpookyMessage(); spookyMsg != "" {
-			fmt.Printf("\n    👻 \"%s\"\n", spookyMsg)
-		}
-	}
+func printMenu(commands *commandRegistry, ui *uiConfig) {
+	fmt.Print(ui.text(renderMenu(commands, sectionMain)))
 }
 
 // displayPet shows the pet and its current status
 func displayPet(pet *Pet, ui *uiConfig) {
 	clearScreen()
 	maybeShake(pet, ui)
-	fmt.Print(renderScene(pet, ui))
+	fmt.Print(ui.text(renderScene(pet, ui)))
 	// Check and play audio alerts for critical states
 	ui.checkAndPlayAlerts(pet)
 }
@@ -213,25 +74,105 @@ func promptForName(reader *bufio.Reader) string {
 }
 
 // gameLoop runs the main game loop
-func gameLoop(pet *Pet, reader *bufio.Reader, ui *uiConfig) {
+func gameLoop(session *petSession, reader *bufio.Reader, ui *uiConfig) {
+	pet := session.pet
+
 	// Auto-save ticker
-	autoSaveTicker := time.NewTicker(30 * time.Second)
+	autoSaveTicker := time.NewTicker(autoSaveInterval)
 	defer autoSaveTicker.Stop()
 
-	// Start auto-save goroutine
+	// Off-machine backups, if the user has set them up
+	backup, err := newBackupConfig(os.Getenv)
+	if err != nil {
+		fmt.Fprint(os.Stderr, consoleText(fmt.Sprintf("⚠️  Backups disabled: %v\n", err)))
+	}
+
+	// The weekly report by email, if a mail server is set up
+	digest, err := newDigestConfig(os.Getenv)
+	if err != nil {
+		fmt.Fprint(os.Stderr, consoleText(fmt.Sprintf("⚠️  Email digest disabled: %v\n", err)))
+	}
+	if digest != nil {
+		digest.off = loadPrivacy(privacyFile).NoDigest
+	}
+
+	// The maintainers' community events calendar, if one is configured
+	events, err := newEventFeed(os.Getenv)
+	if err != nil {
+		fmt.Fprint(os.Stderr, consoleText(fmt.Sprintf("⚠️  Community events disabled: %v\n", err)))
+	}
+
+	// The curated content pack index, if one is configured
+	packs, err := newPackIndex(os.Getenv)
+	if err != nil {
+		fmt.Fprint(os.Stderr, consoleText(fmt.Sprintf("⚠️  Pack browser disabled: %v\n", err)))
+	}
+
+	// Real weather for the window, if a feed is configured
+	weather, err := newWeatherFeed(os.Getenv)
+	if err != nil {
+		fmt.Fprint(os.Stderr, consoleText(fmt.Sprintf("⚠️  Real weather disabled: %v\n", err)))
+	}
+	ui.weather = weather
+
+	recap := newSessionRecap(pet, time.Now())
+
+	// The prompt and the autosave take turns with the pet; the prompt
+	// holds it except while it waits for the player
+	saver := newAutoSaver(pet, autoSaveInterval)
+	session.Lock()
+	defer session.Unlock()
+
+	// Watch any directories the pet has been allowed to claim
+	territory := newTerritoryWatcher(pet.Territory)
+
+	commands := newDefaultRegistry()
+	if classroomMode {
+		commands = newClassroomRegistry()
+	}
+	editor := newLineEditor(reader, historyFile, commandCompleter(commands, pet))
+	editor.plain = ui.screenReader
+
+	// What the pet gets up to while nobody types
+	mischief, err := newIdleScheduler(os.Getenv, rand.New(rand.NewSource(time.Now().UnixNano())))
+	if err != nil {
+		fmt.Fprint(os.Stderr, consoleText(fmt.Sprintf("⚠️  Mischief disabled: %v\n", err)))
+	}
+
+	// Start auto-save goroutine; scheduled backups, event refreshes, and
+	// idle mischief ride along with it
 	go func() {
+		defer recoverCrash(session)
 		for range autoSaveTicker.C {
-			pet.Update()
-			pet.Save()
+			now := time.Now()
+			autoSave(session, saver, ui, backup, digest, events, weather, now)
+			session.Do(func(pet *Pet) {
+				act, message := mischief.Act(pet, editor.Idle(now), now)
+				if act != "" {
+					saver.Notice(pet) // Written at the next tick
+				}
+				if act == "tap" {
+					ui.bellForEvent("alert")
+				}
+				if message != "" {
+					editor.Interject(ui.text(message))
+				}
+			})
 		}
 	}()
 
+	// The pet notices if you're early or late
+	anticipation := ""
+	if pet.Endgame != nil {
+		anticipation = pet.Endgame.Anticipate(time.Now())
+	}
+
 	// Check for daily login bonus
 	if pet.Endgame != nil {
 		if got, bonusMsg := pet.Endgame.CheckDailyBonus(); got {
-			fmt.Println(bonusMsg)
+			fmt.Println(ui.text(bonusMsg))
 			fmt.Print("Press Enter to continue...")
-			reader.ReadString('\n')
+			session.Wait(func() { reader.ReadString('\n') })
 		}
 	}
 
@@ -239,309 +180,152 @@ func gameLoop(pet *Pet, reader *bufio.Reader, ui *uiConfig) {
 		// Check for "touch grass" reminder
 		if pet.Endgame != nil {
 			if shouldRemind, reminder := pet.Endgame.CheckTouchGrass(); shouldRemind {
-				fmt.Println(reminder)
+				fmt.Println(ui.text(reminder))
 				pet.Endgame.UnlockAchievement("touch_grass")
 				fmt.Print("Press Enter to continue...")
-				reader.ReadString('\n')
+				session.Wait(func() { reader.ReadString('\n') })
 			}
 		}
 
 		pet.Update()
+		syncIdentity(pet)
+		pet.RecordCareSample(time.Now(), onlineFriends())
+		reactions := applyHookEvents(pet)
+		if felt := pet.sensePower(readPower()); felt != "" {
+			reactions = append(reactions, felt)
+		}
+		reactions = append(reactions, applyMachineSensing(pet)...)
+		if hatched := pet.hatchAnnouncement(); hatched != "" {
+			ui.playEvent(os.Stdout, pet, "hatched")
+			reactions = append(reactions, hatchingCeremony(pet, reader, session, ui.text(hatched)))
+		}
+		if news := pet.SicknessNews(); news != "" {
+			reactions = append(reactions, news)
+		}
+		if trained, event := pet.CheckTraining(time.Now()); trained != "" {
+			ui.playEvent(os.Stdout, pet, event)
+			reactions = append(reactions, trained)
+		}
+		if anticipation != "" {
+			reactions = append(reactions, "🕰️ "+anticipation)
+			anticipation = ""
+		}
+		reactions = append(reactions, pet.ReturnFromBoarding(time.Now(), newConversationContext())...)
+		reactions = append(reactions, pet.ReturnFromTravel(time.Now(), rand.New(rand.NewSource(time.Now().UnixNano())))...)
+		if paycheck := pet.CollectPaycheck(); paycheck != "" {
+			reactions = append(reactions, paycheck)
+		}
+		if recovered := pet.AlertRecovery(time.Now()); recovered != "" {
+			reactions = append(reactions, recovered)
+		}
+		if celebrated := pet.CelebrateHoliday(activeRegion, time.Now()); celebrated != "" {
+			reactions = append(reactions, celebrated)
+		}
+		if wrote := pet.WriteDiary(diaryFile, time.Now(), networkDiaryFacts, rand.New(rand.NewSource(time.Now().UnixNano()))); wrote != "" {
+			reactions = append(reactions, wrote)
+		}
+		reactions = append(reactions, pet.RunDueEvents(time.Now())...)
+		reactions = append(reactions, applyCustodyUpdates(pet)...)
+		reactions = append(reactions, applyQuestUpdates(pet, time.Now())...)
+		reactions = append(reactions, applyBannerNews(pet, time.Now())...)
+		reactions = append(reactions, applyWhispers(pet, time.Now())...)
+		reactions = append(reactions, applyImpostors()...)
+		reactions = append(reactions, ui.takePhotos(pet, time.Now())...)
+		reactions = append(reactions, pet.CheckChallenges(time.Now())...)
+		reactions = append(reactions, applyNetworkChallenges(pet, time.Now())...)
+		reactions = append(reactions, applyNetworkTraumas(pet)...)
+		reactions = append(reactions, applyNetworkFragments(pet)...)
+		reactions = append(reactions, applyNetworkMoods(pet)...)
+		reactions = append(reactions, applyNetworkOutbreaks(pet, time.Now())...)
+		if report := pet.CheckWeeklyReport(time.Now()); report != "" {
+			reactions = append(reactions, report)
+		}
+		if pet.Endgame != nil {
+			pet.Endgame.RecordVisit(time.Now())
+		}
 		displayPet(pet, ui)
-		printMenu()
+		for _, reaction := range reactions {
+			fmt.Printf("    %s\n", ui.text(reaction))
+		}
+		if comment := territory.Poll(); comment != "" {
+			fmt.Printf("    %s\n", ui.text(fmt.Sprintf("🗺️  \"%s\"", comment)))
+		}
+		printMenu(commands, ui)
 
-		fmt.Print("Enter command: ")
-		command, _ := reader.ReadString('\n')
+		var command string
+		session.Wait(func() { command, _ = editor.ReadLine("Enter command: ") })
 		command = strings.TrimSpace(strings.ToLower(command))
 
+		// Split off arguments for commands like "focus 25"
+		verb, args := command, ""
+		if idx := strings.IndexByte(command, ' '); idx >= 0 {
+			verb, args = command[:idx], strings.TrimSpace(command[idx+1:])
+		}
+
 		// Track command for meta stats
 		if pet.Endgame != nil {
 			pet.Endgame.IncrementCommand()
 		}
 
+		cmd := commands.Lookup(verb)
 		var message string
-
-		switch command {
-		case "feed", "f":
-			pet.Update()
-			message = pet.Feed()
-			if pet.Endgame != nil {
-				pet.Endgame.UnlockAchievement("first_feed")
-			}
-
-		case "play", "p":
-			pet.Update()
-			message = pet.Play()
-
-		case "clean", "c":
-			pet.Update()
-			message = pet.Clean()
-
-		case "heal", "h", "medicine", "med":
-			pet.Update()
-			message = pet.Heal()
-
-		case "status", "s", "stats":
-			pet.Update()
-			continue // Status is already displayed
-
-		case "help", "?":
-			continue // Menu is already displayed
-
-		case "pet", "pat":
-			pet.Update()
-			if pet.Absurd != nil {
-				message = pet.Absurd.PetThePet()
-			} else {
-				message = "You pet your pet. It seems pleased."
-			}
-
-		case "games", "game", "minigames", "mini":
-			pet.Update()
-			result := SelectAndPlayMiniGame(reader)
-			if result != nil {
-				message = result.Message
-			}
-
-		case "void", "stare":
-			pet.Update()
-			if pet.Absurd != nil {
-				message = pet.Absurd.StartsIntoVoid()
-				pet.Absurd.StopStaringIntoVoid()
-				if pet.Endgame != nil {
-					pet.Endgame.UnlockAchievement("void_gaze")
-					if pet.Absurd.HasAchievedClarity {
-						pet.Endgame.UnlockAchievement("enlightened")
-					}
-				}
-			} else {
-				message = "You stare into the void. It's just darkness."
-			}
-
-		case "vibe", "vibecheck":
-			pet.Update()
-			if pet.Absurd != nil {
-				passed, vibeMessage := pet.Absurd.PerformVibeCheck()
-				if passed {
-					message = "✅ " + vibeMessage
-				} else {
-					message = "❌ " + vibeMessage
-				}
-			} else {
-				message = "Vibe check: inconclusive."
-			}
-
-		case "fears", "fear":
-			pet.Update()
-			if pet.Absurd != nil {
-				message = pet.Absurd.GetFearDisplay()
-			} else {
-				message = "Your pet fears nothing. This is suspicious."
-			}
-
-		case "???", "mystery", "mystats":
-			pet.Update()
-			if pet.Absurd != nil {
-				message = pet.Absurd.GetMysteryStatsDisplay()
-			} else {
-				message = "No mystery stats available. This is also mysterious."
-			}
-
-		case "more", "endgame":
-			printMoreMenu()
-			continue
-
-		case "guild":
-			pet.Update()
-			if pet.Endgame != nil {
-				message = pet.Endgame.JoinGuild()
-				pet.Endgame.UnlockAchievement("guild_join")
-			}
-
-		case "quest", "quests":
-			pet.Update()
-			if pet.Endgame != nil {
-				// Check for quest completion first
-				if completion := pet.Endgame.UpdateQuest(); completion != "" {
-					message = completion
-					pet.Endgame.UnlockAchievement("quest_complete")
-				} else {
-					message = pet.Endgame.GenerateQuest()
-				}
-			}
-
-		case "gacha", "pull":
-			pet.Update()
-			if pet.Endgame != nil {
-				message = pet.Endgame.PullGacha()
-			}
-
-		case "battle", "fight":
-			pet.Update()
-			if pet.Endgame != nil {
-				message = pet.Endgame.StartBattle()
-			}
-
-		case "trade":
-			pet.Update()
-			if pet.Endgame != nil {
-				message = pet.Endgame.AttemptTrade()
-			}
-
-		case "achievements", "achieve", "ach":
-			pet.Update()
-			if pet.Endgame != nil {
-				message = pet.Endgame.ShowAchievements()
-			}
-
-		case "leaderboard", "lb", "rankings":
-			pet.Update()
-			if pet.Endgame != nil {
-				message = pet.Endgame.ShowLeaderboard()
-			}
-
-		case "countdown", "timer":
-			pet.Update()
-			if pet.Endgame != nil {
-				message = pet.Endgame.GetCountdownStatus()
-			}
-
-		case "clue", "arg":
-			pet.Update()
-			if pet.Endgame != nil {
-				message = pet.Endgame.GetARGClue()
-			}
-
-		case "meta", "metastats", "wasted":
-			pet.Update()
-			if pet.Endgame != nil {
-				message = pet.Endgame.GetMetaStats()
-			}
-
-		case "share":
-			pet.Update()
-			if pet.Endgame != nil {
-				pet.Endgame.ShareCount++
-				shareText := pet.Endgame.GenerateShareText(pet.Name, pet.Stage.String())
-				message = "📤 Share text copied to... nowhere. Here it is:\n" + shareText
-			}
-
-		case "premium", "pro", "vip":
-			pet.Update()
-			message = ShowPremiumOffer()
-
-		case "ad", "ads", "watch":
-			pet.Update()
-			message = ShowFakeAd()
-			fmt.Println(message)
-			fmt.Println("\n⏳ Loading ad...")
-			time.Sleep(5 * time.Second) // Fake ad delay
-			fmt.Println("✅ Ad complete! Reward: A sense of time passing.")
-			message = ""
-
-		case "friendcode", "code", "fc":
-			pet.Update()
-			if pet.Endgame != nil {
-				message = fmt.Sprintf(`
-╔════════════════════════════════════╗
-║      🔑 YOUR FRIEND CODE 🔑       ║
-╠════════════════════════════════════╣
-║                                    ║
-║ %s
-║                                    ║
-║ Share this with friends!           ║
-║ (It doesn't do anything)           ║
-║                                    ║
-╚════════════════════════════════════╝
-`, pet.Endgame.FriendCode)
-			}
-
-		case "reset", "restart", "new":
-			fmt.Print("\nThis will erase your pet history and start over. Type YES to confirm: ")
-			confirm, _ := reader.ReadString('\n')
-			confirm = strings.TrimSpace(strings.ToUpper(confirm))
-			if confirm != "YES" {
-				message = "Reset cancelled. Your pet breathes a sigh of relief."
-				break
-			}
-
-			fmt.Print("Name your new pet: ")
-			newName, _ := reader.ReadString('\n')
-			newName = strings.TrimSpace(newName)
-			if newName == "" {
-				newName = "Tamago"
+		if cmd == nil {
+			cmd, message = unknownCommand(pet, commands, command, verb)
+		}
+		if cmd != nil {
+			ctx := &commandContext{pet: pet, reader: reader, ui: ui, territory: territory, commands: commands, backup: backup, digest: digest, packs: packs, args: args, recap: recap, session: session}
+			recap.countAction()
+			result := runCommand(cmd, ctx)
+			if ctx.quit {
+				return
 			}
-
-			// Restart network and pet state in-place to keep autosave goroutine valid
-			shutdownNetwork()
-			pet.Reset(newName)
-			initNetwork(pet)
-			_ = os.Remove(saveFile) // clear any lingering history; save will rewrite
-			if err := pet.Save(); err != nil {
-				message = fmt.Sprintf("❌ Failed to start fresh: %v", err)
-				break
+			for _, event := range ctx.events {
+				ui.playEvent(os.Stdout, pet, event)
 			}
-			message = fmt.Sprintf("♻️ History cleared. Say hi to your new pet: %s", newName)
-
-		case "quit", "q", "exit":
-			fmt.Println("\n💾 Saving your pet...")
-			pet.Update()
-			saveNetworkState(pet) // Save hidden network state
-			// Update play time before saving
 			if pet.Endgame != nil {
-				pet.Endgame.UpdatePlayTime()
-			}
-			if err := pet.Save(); err != nil {
-				fmt.Printf("❌ Error saving: %v\n", err)
-			} else {
-				fmt.Println("✅ Saved successfully!")
+				result = strings.TrimSpace(result + "\n\n" + pet.Endgame.CountCommand(cmd.Name()))
 			}
-			fmt.Println("👋 Goodbye! See you next time!")
-			return
-
-		default:
-			// Check for Konami code progress
-			if pet.Absurd != nil {
-				activated, konamiMessage := pet.Absurd.ProcessKonamiInput(command)
-				if activated {
-					message = konamiMessage
-				} else {
-					// Check for fear triggers
-					fear := pet.Absurd.CheckFearTrigger(command)
-					if fear != nil {
-						message = fmt.Sprintf("😱 Your pet trembles! It has %s: %s", fear.Name, fear.Description)
-					} else {
-						message = "❓ Unknown command. Type 'help' to see available commands."
-					}
-				}
-			} else {
-				message = "❓ Unknown command. Type 'help' to see available commands."
+			result = strings.TrimSpace(result + "\n\n" + pet.RecordChallengeEvent(cmd.Name(), time.Now()))
+			if remark, chore := pet.RecordChore(cmd.Name(), time.Now()); chore {
+				sendCustody(pet, cmd.Name())
+				result = strings.TrimSpace(result + "\n\n" + remark)
 			}
+			message = strings.TrimSpace(message + "\n" + result)
 		}
 
 		if message != "" {
 			fmt.Println()
 			typewriterPrint(message, ui)
 			fmt.Print("\nPress Enter to continue...")
-			reader.ReadString('\n')
+			session.Wait(func() { reader.ReadString('\n') })
 		}
 
-		// Save after each action
-		pet.Save()
+		// Save after each action, unless it changed nothing
+		syncIdentity(pet)
+		reportSave(saver.Save(pet, time.Now()), ui)
 
 		// Check if pet died
+		if pet.Stage == Dead && classroomMode {
+			// No skulls or last words in the classroom
+			fmt.Println("\n" + ui.text(ranAwayMessage(pet)))
+			pet.Save()
+			fmt.Print("\nPress Enter to exit...")
+			session.Wait(func() { reader.ReadString('\n') })
+			return
+		}
 		if pet.Stage == Dead {
 			// Announce death on the network (other pets will sense it)
 			if petNetwork != nil {
-				petNetwork.AnnounceDeath(pet.Name, pet.Age, "I go now to the great terminal in the sky...")
+				petNetwork.AnnounceDeath(pet.Name, pet.Age, pet.lastWords())
 			}
+			ui.playEvent(os.Stdout, pet, "died")
 			displayPet(pet, ui)
-			fmt.Println("\n💀 Your pet has passed away due to neglect...")
-			fmt.Println("😢 Game Over")
+			fmt.Println(ui.text(pet.deathNotice()))
+			documentFears(pet, fearBookFile, time.Now())
 			saveNetworkState(pet)
 			pet.Save()
 			fmt.Print("\nPress Enter to exit...")
-			reader.ReadString('\n')
+			session.Wait(func() { reader.ReadString('\n') })
 			return
 		}
 	}
@@ -553,19 +337,57 @@ func initNetwork(pet *Pet) {
 	isAlive := pet.Stage != Dead
 
 	petNetwork = mooc.NewNetwork(pet.Name, pet.BirthTime, stageStr, isAlive)
-
-	if lonelyMode {
+	petNetwork.SetCustodyHandler(queueCustody)
+	petNetwork.SetQuestHandler(queueQuest)
+	petNetwork.SetInfluenceBonus(pet.influenceBonus())
+	petNetwork.SetRegion(activeRegion.offsetLabel())
+
+	// The privacy screen's opt-out and classroom mode hold whatever the flags say
+	privacy := loadPrivacy(privacyFile)
+	if lonelyMode || privacy.Offline || classroomMode {
 		petNetwork.SetLonelyMode(true)
 		return
 	}
+	petNetwork.SetSharing(privacy.sharing())
+	petNetwork.SetHearing(privacy.hearing())
+	port, err := meshPort(os.Getenv)
+	if err != nil {
+		fmt.Fprint(os.Stderr, consoleText(fmt.Sprintf("⚠️  %v; using %d\n", err, port)))
+	}
+	petNetwork.SetPort(port)
+	petNetwork.SetTCPFallback(privacy.TCPFallback)
+	if key, err := loadMeshKey(meshKeyFile); err != nil {
+		fmt.Fprint(os.Stderr, consoleText(fmt.Sprintf("⚠️  %v; signing with a key just for this session\n", err)))
+	} else {
+		petNetwork.SetSigningKey(key)
+	}
+
+	// Log every outbound packet for users who want to check for themselves
+	if auditNetwork {
+		audit, err := startNetworkAudit(auditFile, pet, time.Now())
+		if err != nil {
+			fmt.Fprint(os.Stderr, consoleText(fmt.Sprintf("⚠️  Network audit disabled: %v\n", err)))
+		} else {
+			activeAudit = audit
+			petNetwork.SetAudit(audit.record)
+		}
+	}
 
 	// Import saved network state if available
 	if pet.Friends != nil && len(pet.Friends) > 0 {
 		petNetwork.ImportState(pet.Friends)
 	}
 
-	// Start network (silently, users don't need to know)
-	petNetwork.Start()
+	// Start network quietly; if it can't, network doctor says why
+	_ = petNetwork.Start()
+}
+
+// syncIdentity tells the network when the pet has grown up or died
+func syncIdentity(pet *Pet) {
+	if petNetwork == nil {
+		return
+	}
+	petNetwork.UpdateIdentity(pet.Stage.String(), pet.Stage != Dead)
 }
 
 // saveNetworkState saves network state to pet's Friends field
@@ -587,44 +409,150 @@ func shutdownNetwork() {
 	}
 }
 
+// runSubcommand handles non-interactive invocations such as `tamagotchi hook install`.
+// It reports whether args named a subcommand.
+func runSubcommand(args []string) (bool, error) {
+	if len(args) == 0 {
+		return false, nil
+	}
+
+	switch args[0] {
+	case "hook":
+		return true, runHookCommand(args[1:])
+	case "idle":
+		return true, runIdle(args[1:])
+	case "peek":
+		return true, runPeek(args[1:])
+	case "watch":
+		return true, runWatch(args[1:])
+	case "backup":
+		return true, runBackupSubcommand(args[1:])
+	case "art":
+		return true, runArtCommand(args[1:])
+	case "content":
+		return true, runContentCommand(args[1:])
+	case "events":
+		return true, runEventsCommand(args[1:])
+	case "custody":
+		return true, runCustodySubcommand(args[1:])
+	case "send":
+		return true, runSendSubcommand(args[1:])
+	case "receive":
+		return true, runReceiveSubcommand(args[1:])
+	case "packs":
+		return true, runPacksSubcommand(args[1:])
+	case "classroom":
+		return true, runClassroomSubcommand(args[1:])
+	}
+
+	return false, nil
+}
+
 func main() {
+	// Apply any difficulty tuning before the simulation runs
+	if err := loadBalanceOverride(balanceOverrideFile); err != nil {
+		fmt.Fprint(os.Stderr, consoleText(fmt.Sprintf("⚠️  Ignoring balance override: %v\n", err)))
+	}
+	classroomMode = classroomEnabled(classroomFile, os.Getenv)
+	sensorsOn = loadPrivacy(privacyFile).Sensors && !classroomMode
+
+	// Community content packs add to or replace the built-in words
+	for _, err := range loadContentPacks(contentPackDir(), os.Getenv) {
+		fmt.Fprint(os.Stderr, consoleText(fmt.Sprintf("⚠️  Skipping content pack %v\n", err)))
+	}
+
+	if handled, err := runSubcommand(os.Args[1:]); handled {
+		if err != nil {
+			fmt.Fprint(os.Stderr, consoleText(fmt.Sprintf("❌ %v\n", err)))
+			os.Exit(1)
+		}
+		return
+	}
+
 	reader := bufio.NewReader(os.Stdin)
 	ui := newUIConfig()
+	sounds, err := loadSoundRoutes(soundConfigFile, exec.LookPath, runtime.GOOS)
+	if err != nil {
+		fmt.Fprint(os.Stderr, consoleText(fmt.Sprintf("⚠️  Ignoring sound config: %v\n", err)))
+	}
+	ui.sounds = sounds
+	alerts, err := newAlertPolicy(os.Getenv, exec.LookPath, runtime.GOOS)
+	if err != nil {
+		fmt.Fprint(os.Stderr, consoleText(fmt.Sprintf("⚠️  Ignoring alert setting: %v\n", err)))
+	}
+	ui.alerts = alerts
 
-	// Check for --lonely flag (undocumented)
+	// Check for --lonely (undocumented), --audit-network, and --kiosk flags
 	for _, arg := range os.Args[1:] {
+		if arg == "--audit-network" {
+			auditNetwork = true
+		}
 		if arg == "--lonely" || arg == "-lonely" {
 			lonelyMode = true
 		}
+		if arg == "--kiosk" {
+			kioskMode = true
+		}
+	}
+
+	// A public display gets a demo pet and never touches the save
+	if kioskMode {
+		runKiosk(ui)
+		return
 	}
 
 	clearScreen()
-	printTitle()
+	printTitle(ui)
 
 	var pet *Pet
 
 	// Check if save file exists
 	if _, err := os.Stat(saveFile); err == nil {
-		fmt.Println("📂 Found existing pet! Loading...")
+		fmt.Println(ui.text("📂 Found existing pet! Loading..."))
 		loadedPet, err := LoadPet(saveFile)
+		var corrupt *SaveCorruptError
 		if err != nil {
-			fmt.Printf("❌ Error loading pet: %v\n", err)
+			fmt.Println(ui.text(errorPanel(err)))
+			if !errors.As(err, &corrupt) {
+				// Without the save file there's nowhere to keep a pet
+				os.Exit(1)
+			}
+			aside, err := setAsideCorruptSave(saveFile, time.Now())
+			if err != nil {
+				fmt.Println(ui.text(errorPanel(err)))
+				os.Exit(1)
+			}
+			fmt.Printf("The damaged file was kept as %s.\n", aside)
 			fmt.Println("Starting a new pet instead...")
 			name := promptForName(reader)
 			pet = NewPet(name)
+		} else if loadedPet.Stage == Dead && classroomMode {
+			fmt.Println(ui.text(ranAwayMessage(loadedPet)))
+			fmt.Println()
+			name := promptForName(reader)
+			pet = NewPet(name)
+		} else if !loadedPet.DepartedAt.IsZero() {
+			// The pet lives on another machine now; only one copy may be on the mesh
+			fmt.Println(ui.text(fmt.Sprintf("🧳 %s moved to another machine on %s.", loadedPet.Name, loadedPet.DepartedAt.Format("Jan 2"))))
+			fmt.Println("Starting a new pet here...")
+			name := promptForName(reader)
+			pet = NewPet(name)
 		} else {
 			pet = loadedPet
-			fmt.Printf("✅ Welcome back! Loaded %s\n", pet.Name)
+			fmt.Println(ui.text("✅ Welcome back! Loaded " + pet.Name))
+			if woke := wakeFromCrash(pet, time.Now(), rand.New(rand.NewSource(time.Now().UnixNano()))); woke != "" {
+				fmt.Println(ui.text(woke))
+			}
 			time.Sleep(2 * time.Second)
 		}
 	} else {
 		// New game
-		fmt.Println("🎉 Welcome to Tamagotchi!")
+		fmt.Println(ui.text("🎉 Welcome to Tamagotchi!"))
 		fmt.Println("You're about to hatch a new virtual pet!")
 		fmt.Println()
 		name := promptForName(reader)
 		pet = NewPet(name)
-		fmt.Printf("\n🥚 %s has been created!\n", name)
+		fmt.Println(ui.text(fmt.Sprintf("\n🥚 %s has been created!", name)))
 		fmt.Println("Take good care of your pet!")
 		time.Sleep(2 * time.Second)
 	}
@@ -632,7 +560,15 @@ func main() {
 	// Initialize the hidden network (users don't know about this)
 	initNetwork(pet)
 	defer shutdownNetwork()
+	session := newPetSession(pet)
+	defer recoverCrash(session)
+
+	// Only the content packs this pet hasn't switched off
+	useContentPacks(pet)
+
+	// Opt-in LLM dialogue, otherwise the built-in templates
+	pet.Dialogue = newDialogueProvider()
 
 	// Start game loop
-	gameLoop(pet, reader, ui)
+	gameLoop(session, reader, ui)
 }
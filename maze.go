@@ -0,0 +1,255 @@
+package main
+
+import (
+	"bufio"
+	"fmt"
+	"math/rand"
+	"strings"
+	"time"
+	"unicode"
+
+	"github.com/tamagotchi/arg"
+)
+
+const (
+	// mazeCols and mazeRows are the maze's size in cells
+	mazeCols, mazeRows = 9, 5
+	// mazeHappiness is what finding the way out is worth
+	mazeHappiness = 15
+	// mazeDoorOdds is one in how many mazes have the door, once it's been hinted at
+	mazeDoorOdds = 8
+)
+
+// wayward is how often a pet of each personality goes its own way instead
+// of where it's steered
+var wayward = map[string]float64{"chaotic": 0.2, "mischievous": 0.1, "dreamy": 0.05}
+
+// point is a square in the maze, row first
+type point struct{ row, col int }
+
+// mazeMoves are the four ways to go
+var mazeMoves = []point{{-1, 0}, {1, 0}, {0, 1}, {0, -1}}
+
+// maze is a grid of walls with one way out and, rarely, a door
+type maze struct {
+	walls [][]bool
+	pos   point
+	exit  point
+	door  *point // nil in all but the rare maze
+}
+
+// newMaze carves a maze of cols by rows cells with a depth-first walk, so
+// every cell can be reached and there's one path between any two
+func newMaze(cols, rows int, rng *rand.Rand) *maze {
+	walls := make([][]bool, 2*rows+1)
+	for r := range walls {
+		walls[r] = make([]bool, 2*cols+1)
+		for c := range walls[r] {
+			walls[r][c] = true
+		}
+	}
+
+	start := point{1, 1}
+	walls[1][1] = false
+	stack := []point{start}
+	for len(stack) > 0 {
+		cur := stack[len(stack)-1]
+		var next []point
+		for _, d := range mazeMoves {
+			n := point{cur.row + 2*d.row, cur.col + 2*d.col}
+			if n.row > 0 && n.row < len(walls)-1 && n.col > 0 && n.col < len(walls[0])-1 && walls[n.row][n.col] {
+				next = append(next, n)
+			}
+		}
+		if len(next) == 0 {
+			stack = stack[:len(stack)-1]
+			continue
+		}
+		n := next[rng.Intn(len(next))]
+		walls[(cur.row+n.row)/2][(cur.col+n.col)/2] = false
+		walls[n.row][n.col] = false
+		stack = append(stack, n)
+	}
+	return &maze{walls: walls, pos: start, exit: point{2*rows - 1, 2*cols - 1}}
+}
+
+// addDoor sets a door into the top wall, somewhere above the path
+func (m *maze) addDoor(rng *rand.Rand) {
+	col := 1 + 2*rng.Intn((len(m.walls[0])-1)/2)
+	m.door = &point{0, col}
+}
+
+// move steps one square, reporting "wall", "door", "exit", or "" for an
+// ordinary step
+func (m *maze) move(d point) string {
+	next := point{m.pos.row + d.row, m.pos.col + d.col}
+	if m.door != nil && next == *m.door {
+		return "door"
+	}
+	if next.row < 0 || next.row >= len(m.walls) || next.col < 0 || next.col >= len(m.walls[0]) || m.walls[next.row][next.col] {
+		return "wall"
+	}
+	m.pos = next
+	if next == m.exit {
+		return "exit"
+	}
+	return ""
+}
+
+// render draws the maze with the pet as avatar
+func (m *maze) render(avatar rune) string {
+	var b strings.Builder
+	for r, row := range m.walls {
+		for c, wall := range row {
+			switch p := (point{r, c}); {
+			case p == m.pos:
+				b.WriteRune(avatar)
+			case p == m.exit:
+				b.WriteByte('>')
+			case m.door != nil && p == *m.door:
+				b.WriteByte('+')
+			case wall:
+				b.WriteByte('#')
+			default:
+				b.WriteByte(' ')
+			}
+		}
+		b.WriteByte('\n')
+	}
+	return b.String()
+}
+
+// steer is where the pet actually goes when pointed one way: usually
+// there, but a wayward pet sometimes picks for itself
+func steer(p *Pet, d point, rng *rand.Rand) point {
+	chance := 0.0
+	personalities := append([]string{}, p.Traits...)
+	if p.FirstWords != nil {
+		personalities = append(personalities, p.FirstWords.Personality)
+	}
+	for _, personality := range personalities {
+		chance = max(chance, wayward[personality])
+	}
+	if chance > 0 && rng.Float64() < chance {
+		return mazeMoves[rng.Intn(len(mazeMoves))]
+	}
+	return d
+}
+
+// readMazeKey reads one move: an arrow key, WASD, or HJKL. It reports
+// false for q, Escape on its own, or the end of input.
+func readMazeKey(reader *bufio.Reader) (point, bool) {
+	for {
+		r, _, err := reader.ReadRune()
+		if err != nil {
+			return point{}, false
+		}
+		switch unicode.ToLower(r) {
+		case 'w', 'k':
+			return mazeMoves[0], true
+		case 's', 'j':
+			return mazeMoves[1], true
+		case 'd', 'l':
+			return mazeMoves[2], true
+		case 'a', 'h':
+			return mazeMoves[3], true
+		case 'q':
+			return point{}, false
+		case keyEscape:
+			if next, _, err := reader.ReadRune(); err != nil || (next != '[' && next != 'O') {
+				return point{}, false
+			}
+			key, _, err := reader.ReadRune()
+			if err != nil {
+				return point{}, false
+			}
+			if i := strings.IndexRune("ABCD", key); i >= 0 {
+				return mazeMoves[i], true
+			}
+		}
+		// Anything else, newlines included, is ignored
+	}
+}
+
+// mazeDoor adds the ARG door to a rare maze once the clue for it is out
+func mazeDoor(p *Pet, m *maze, rng *rand.Rand) {
+	if p.Endgame == nil || p.Endgame.ARG == nil {
+		return
+	}
+	game := p.Endgame.ARG
+	if game.IsPlanted(arg.PuzzleMaze) && !game.IsSolved(arg.PuzzleMaze) && rng.Intn(mazeDoorOdds) == 0 {
+		m.addDoor(rng)
+	}
+}
+
+// behindTheDoor is what the pet finds when it tries the door
+func behindTheDoor(p *Pet) string {
+	return fmt.Sprintf("🚪 %s leans on the door. It doesn't open, but something is scratched into it: %s\nWhen you know, type: solve %s <answer>",
+		p.Name, arg.DoorInscription(p.Endgame.ARG.Answer(arg.PuzzleMaze)), arg.PuzzleMaze)
+}
+
+// PlayMaze plays the maze mini-game, steering the pet with arrow keys
+func PlayMaze(reader *bufio.Reader, session *petSession, ui *uiConfig, p *Pet) MiniGameResult {
+	if p.Stage == Dead {
+		return MiniGameResult{Message: "💀 Your pet has passed away..."}
+	}
+	if p.Stage == Egg {
+		return MiniGameResult{Message: "🥚 Someone has to walk the maze, and it isn't the egg."}
+	}
+	fmt.Println(ui.text("\n╔════════════════════════════════════╗\n" +
+		"║    🌀 MAZE 🌀                      ║\n" +
+		"╠════════════════════════════════════╣\n" +
+		"║ Steer with the arrow keys or WASD  ║\n" +
+		"║ Find the way out: >                ║\n" +
+		"║ Press q to give up                 ║\n" +
+		"╚════════════════════════════════════╝"))
+
+	rng := rand.New(rand.NewSource(time.Now().UnixNano()))
+	m := newMaze(mazeCols, mazeRows, rng)
+	mazeDoor(p, m, rng)
+
+	restore := enableKeypressMode()
+	defer restore()
+	return walkMaze(reader, session, ui, p, m, rng)
+}
+
+// walkMaze steers the pet through m until it's out or the player gives up.
+// The pet is let go while each key is awaited.
+func walkMaze(reader *bufio.Reader, session *petSession, ui *uiConfig, p *Pet, m *maze, rng *rand.Rand) MiniGameResult {
+	avatar := unicode.ToUpper([]rune(p.Name + "@")[0])
+	steps, note := 0, ""
+	for {
+		if ui.cursorControl {
+			fmt.Print("\033[H\033[2J")
+		}
+		fmt.Print(m.render(avatar))
+		if note != "" {
+			fmt.Println(ui.text(note))
+			note = ""
+		}
+
+		var d point
+		var ok bool
+		session.Wait(func() { d, ok = readMazeKey(reader) })
+		if !ok {
+			return MiniGameResult{Message: fmt.Sprintf("🌀 You gave up after %d steps. %s sits down in the maze and waits to be carried out.", steps, p.Name)}
+		}
+		went := steer(p, d, rng)
+		if went != d {
+			note = fmt.Sprintf("🐾 %s has its own ideas about which way to go.", p.Name)
+		}
+		steps++
+
+		switch m.move(went) {
+		case "exit":
+			fmt.Print(m.render(avatar))
+			p.Happiness = clamp(p.Happiness+mazeHappiness, 0, 100)
+			return MiniGameResult{
+				Message: fmt.Sprintf("🌀 %s found the way out in %d steps and is very pleased with itself. (+%d happiness)", p.Name, steps, mazeHappiness),
+				Success: true,
+			}
+		case "door":
+			note = behindTheDoor(p)
+		}
+	}
+}
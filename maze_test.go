@@ -0,0 +1,202 @@
+package main
+
+import (
+	"bufio"
+	"math/rand"
+	"strings"
+	"testing"
+
+	"github.com/tamagotchi/arg"
+)
+
+// mazePath finds the keys that walk m from the pet to the exit
+func mazePath(m *maze) string {
+	keys := "wsda"
+	from := map[point]point{m.pos: m.pos}
+	queue := []point{m.pos}
+	for len(queue) > 0 && queue[0] != m.exit {
+		cur := queue[0]
+		queue = queue[1:]
+		for _, d := range mazeMoves {
+			n := point{cur.row + d.row, cur.col + d.col}
+			if _, seen := from[n]; !seen && !m.walls[n.row][n.col] {
+				from[n] = cur
+				queue = append(queue, n)
+			}
+		}
+	}
+	if _, ok := from[m.exit]; !ok {
+		return ""
+	}
+	var path []byte
+	for cur := m.exit; cur != m.pos; cur = from[cur] {
+		prev := from[cur]
+		for i, d := range mazeMoves {
+			if (point{prev.row + d.row, prev.col + d.col}) == cur {
+				path = append([]byte{keys[i]}, path...)
+			}
+		}
+	}
+	return string(path)
+}
+
+func TestMazeIsSolvable(t *testing.T) {
+	for seed := int64(1); seed <= 20; seed++ {
+		m := newMaze(mazeCols, mazeRows, rand.New(rand.NewSource(seed)))
+		if mazePath(m) == "" {
+			t.Fatalf("Expected seed %d to have a way out:\n%s", seed, m.render('@'))
+		}
+		open := 0
+		for _, row := range m.walls {
+			for _, wall := range row {
+				if !wall {
+					open++
+				}
+			}
+		}
+		// A perfect maze opens every cell plus one passage fewer than cells
+		if want := 2*mazeCols*mazeRows - 1; open != want {
+			t.Errorf("Expected %d open squares for seed %d, got %d", want, seed, open)
+		}
+	}
+}
+
+func TestMazeMoves(t *testing.T) {
+	m := newMaze(mazeCols, mazeRows, rand.New(rand.NewSource(1)))
+	if got := m.move(point{-1, 0}); got != "wall" || m.pos != (point{1, 1}) {
+		t.Errorf("Expected the outer wall to stop the pet, got %q at %v", got, m.pos)
+	}
+
+	m.addDoor(rand.New(rand.NewSource(1)))
+	m.pos = point{1, m.door.col}
+	if got := m.move(point{-1, 0}); got != "door" || m.pos.row != 1 {
+		t.Errorf("Expected the door to be tried, not walked through, got %q at %v", got, m.pos)
+	}
+	if !strings.Contains(m.render('M'), "+") {
+		t.Errorf("Expected the door drawn:\n%s", m.render('M'))
+	}
+
+	for _, d := range mazeMoves {
+		if m.walls[m.exit.row-d.row][m.exit.col-d.col] {
+			continue
+		}
+		m.pos = point{m.exit.row - d.row, m.exit.col - d.col}
+		if got := m.move(d); got != "exit" {
+			t.Errorf("Expected the exit reached, got %q", got)
+		}
+		break
+	}
+}
+
+func TestReadMazeKey(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+		want  point
+		ok    bool
+	}{
+		{"up arrow", "\x1b[A", mazeMoves[0], true},
+		{"right arrow", "\x1b[C", mazeMoves[2], true},
+		{"wasd", "a", mazeMoves[3], true},
+		{"vi keys", "j", mazeMoves[1], true},
+		{"skips the rest", "\nx D", mazeMoves[2], true},
+		{"quit", "q", point{}, false},
+		{"escape", "\x1b", point{}, false},
+		{"end of input", "", point{}, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := readMazeKey(bufio.NewReader(strings.NewReader(tt.input)))
+			if got != tt.want || ok != tt.ok {
+				t.Errorf("Expected %v %v, got %v %v", tt.want, tt.ok, got, ok)
+			}
+		})
+	}
+}
+
+func TestWaywardPetsGoTheirOwnWay(t *testing.T) {
+	tests := []struct {
+		name     string
+		setup    func(*Pet)
+		min, max int
+	}{
+		{"steady", nil, 0, 0},
+		{"chaotic first words", func(p *Pet) { p.FirstWords = &FirstWords{Personality: "chaotic"} }, 100, 300},
+		{"mischievous trait", func(p *Pet) { p.AddTrait("mischievous") }, 30, 170},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			pet := NewPet("Walker")
+			if tt.setup != nil {
+				tt.setup(pet)
+			}
+			rng := rand.New(rand.NewSource(1))
+			strayed := 0
+			for i := 0; i < 1000; i++ {
+				// A stray that happens to go the way it was steered doesn't show
+				if steer(pet, mazeMoves[0], rng) != mazeMoves[0] {
+					strayed++
+				}
+			}
+			if strayed < tt.min || strayed > tt.max {
+				t.Errorf("Expected %d-%d strays in 1000, got %d", tt.min, tt.max, strayed)
+			}
+		})
+	}
+}
+
+func TestWalkMaze(t *testing.T) {
+	rng := rand.New(rand.NewSource(1))
+	m := newMaze(mazeCols, mazeRows, rng)
+	pet := NewPet("Mochi")
+	pet.Stage = Adult
+	pet.Happiness = 50
+
+	got := walkMaze(bufio.NewReader(strings.NewReader(mazePath(m))), nil, &uiConfig{}, pet, m, rng)
+	if !got.Success || !strings.Contains(got.Message, "found the way out") {
+		t.Errorf("Expected the way out found, got %+v", got)
+	}
+	if pet.Happiness != 50+mazeHappiness {
+		t.Errorf("Expected %d happiness for finishing, got %d", mazeHappiness, pet.Happiness-50)
+	}
+
+	m = newMaze(mazeCols, mazeRows, rng)
+	got = walkMaze(bufio.NewReader(strings.NewReader("ddq")), nil, &uiConfig{}, pet, m, rng)
+	if got.Success || !strings.Contains(got.Message, "gave up") {
+		t.Errorf("Expected giving up, got %+v", got)
+	}
+}
+
+func TestMazeDoorIsRareAndHinted(t *testing.T) {
+	pet := NewPet("Mochi")
+	rng := rand.New(rand.NewSource(1))
+	doors := func() int {
+		n := 0
+		for i := 0; i < 800; i++ {
+			m := newMaze(2, 2, rng)
+			mazeDoor(pet, m, rng)
+			if m.door != nil {
+				n++
+			}
+		}
+		return n
+	}
+
+	if got := doors(); got != 0 {
+		t.Errorf("Expected no door before the clue, got %d", got)
+	}
+	pet.Endgame.argGame().Plant(arg.PuzzleMaze)
+	if got := doors(); got < 50 || got > 150 {
+		t.Errorf("Expected about one maze in %d with a door, got %d in 800", mazeDoorOdds, got)
+	}
+
+	answer := pet.Endgame.ARG.Answer(arg.PuzzleMaze)
+	if got := behindTheDoor(pet); !strings.Contains(got, arg.Rot13(answer)) || strings.Contains(got, answer) {
+		t.Errorf("Expected the answer in ROT13 behind the door, got %q", got)
+	}
+
+	pet.Endgame.ARG.Verify(arg.PuzzleMaze, answer)
+	if got := doors(); got != 0 {
+		t.Errorf("Expected no door once solved, got %d", got)
+	}
+}
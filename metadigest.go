@@ -0,0 +1,134 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// metaDigestNames is how many new names the digest lists before "and N more"
+const metaDigestNames = 3
+
+// MetaSnapshot is what the meta screen saw last time, so the next look can
+// say what changed. Achievements and thoughts only ever grow, so counts
+// are enough; friends are found by when they were first met.
+type MetaSnapshot struct {
+	At           time.Time `json:"at"`
+	Hunger       int       `json:"hunger"`
+	Happiness    int       `json:"happiness"`
+	Health       int       `json:"health"`
+	Cleanliness  int       `json:"cleanliness"`
+	Age          int       `json:"age"`
+	Achievements int       `json:"achievements"`
+	Thoughts     int       `json:"thoughts"`
+}
+
+// metaSnapshot takes a snapshot of the pet for the meta screen
+func (p *Pet) metaSnapshot(now time.Time) MetaSnapshot {
+	snap := MetaSnapshot{
+		At:          now,
+		Hunger:      p.Hunger,
+		Happiness:   p.Happiness,
+		Health:      p.Health,
+		Cleanliness: p.Cleanliness,
+		Age:         p.Age,
+	}
+	if p.Endgame != nil {
+		snap.Achievements = len(p.Endgame.UnlockedAchievements)
+	}
+	if p.Absurd != nil {
+		snap.Thoughts = p.Absurd.ThoughtsHad
+	}
+	return snap
+}
+
+// friendsMetSince lists friends first met on the mesh since a time
+func friendsMetSince(since time.Time) []string {
+	if petNetwork == nil {
+		return nil
+	}
+	return petNetwork.GetFriendsMetSince(since)
+}
+
+// metaDigest says what changed since the last check and remembers this
+// one. metSince may be nil when there's no mesh.
+func (e *EndgameState) metaDigest(current MetaSnapshot, metSince func(time.Time) []string) string {
+	last := e.LastMetaCheck
+	e.LastMetaCheck = &current
+	if last == nil {
+		return "║ • First look. Check back later\n║   to see what changed.\n"
+	}
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "║ • %s ago\n", formatDuration(current.At.Sub(last.At).Truncate(time.Minute)))
+	changed := false
+	line := func(format string, args ...interface{}) {
+		changed = true
+		fmt.Fprintf(&b, "║ • "+format+"\n", args...)
+	}
+
+	stats := []struct {
+		name    string
+		was, is int
+	}{
+		{"Hunger", last.Hunger, current.Hunger},
+		{"Happiness", last.Happiness, current.Happiness},
+		{"Health", last.Health, current.Health},
+		{"Cleanliness", last.Cleanliness, current.Cleanliness},
+	}
+	for _, stat := range stats {
+		if stat.is != stat.was {
+			line("%s: %d → %d (%+d)", stat.name, stat.was, stat.is, stat.is-stat.was)
+		}
+	}
+	if aged := current.Age - last.Age; aged > 0 {
+		line("Aged %d hours", aged)
+	}
+	if last.Achievements < len(e.UnlockedAchievements) {
+		var names []string
+		for _, id := range e.UnlockedAchievements[last.Achievements:] {
+			names = append(names, achievementName(id))
+		}
+		line("New achievements: %s", listNames(names))
+	}
+	if metSince != nil {
+		if names := metSince(last.At); len(names) > 0 {
+			line("New friends: %s", listNames(names))
+		}
+	}
+	if thoughts := current.Thoughts - last.Thoughts; thoughts > 0 {
+		line("Thoughts had: %d", thoughts)
+	}
+	if !changed {
+		line("Nothing. Nothing at all.")
+	}
+	return b.String()
+}
+
+// achievementName is an achievement's display name, or its ID if it's
+// no longer in the list
+func achievementName(id string) string {
+	for _, achievement := range allAchievements {
+		if achievement.ID == id {
+			return achievement.Name
+		}
+	}
+	return id
+}
+
+// listNames joins the first few names, which may come from other pets
+func listNames(names []string) string {
+	shown := names
+	if len(shown) > metaDigestNames {
+		shown = shown[:metaDigestNames]
+	}
+	clean := make([]string, len(shown))
+	for i, name := range shown {
+		clean[i] = truncateWidth(plainText(name), maxPanelNameWidth)
+	}
+	list := strings.Join(clean, ", ")
+	if more := len(names) - len(shown); more > 0 {
+		list += fmt.Sprintf(" and %d more", more)
+	}
+	return list
+}
@@ -0,0 +1,64 @@
+package main
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestMetaDigest(t *testing.T) {
+	now := time.Date(2026, 10, 16, 12, 0, 0, 0, time.UTC)
+	pet := NewPet("Mochi")
+	e := pet.Endgame
+
+	if got := e.metaDigest(pet.metaSnapshot(now), nil); !strings.Contains(got, "First look") {
+		t.Errorf("Expected a first look, got %q", got)
+	}
+	if e.LastMetaCheck == nil || !e.LastMetaCheck.At.Equal(now) {
+		t.Fatalf("Expected the check remembered, got %+v", e.LastMetaCheck)
+	}
+
+	pet.Hunger += 12
+	pet.Age += 3
+	pet.Absurd.ThoughtsHad += 4
+	e.UnlockAchievement("first_feed")
+	var asked time.Time
+	metSince := func(since time.Time) []string {
+		asked = since
+		return []string{"Bean", "Pixel\x1b[2J", "Nori", "Tofu"}
+	}
+
+	later := now.Add(3 * time.Hour)
+	got := e.metaDigest(pet.metaSnapshot(later), metSince)
+	for _, want := range []string{"3h 0m 0s ago", "Hunger:", "(+12)", "Aged 3 hours", "First Meal", "Bean, Pixel[2J, Nori and 1 more", "Thoughts had: 4"} {
+		if !strings.Contains(got, want) {
+			t.Errorf("Expected %q in:\n%s", want, got)
+		}
+	}
+	if strings.Contains(got, "Happiness") {
+		t.Errorf("Expected unchanged stats left out, got:\n%s", got)
+	}
+	if !asked.Equal(now) {
+		t.Errorf("Expected friends met since the last check, got %v", asked)
+	}
+
+	if got := e.metaDigest(pet.metaSnapshot(later.Add(time.Minute)), nil); !strings.Contains(got, "Nothing at all") {
+		t.Errorf("Expected nothing changed, got:\n%s", got)
+	}
+}
+
+func TestListNames(t *testing.T) {
+	tests := []struct {
+		names []string
+		want  string
+	}{
+		{[]string{"Bean"}, "Bean"},
+		{[]string{"Bean", "Nori", "Tofu"}, "Bean, Nori, Tofu"},
+		{[]string{"Bean", "Nori", "Tofu", "Pixel", "Mochi"}, "Bean, Nori, Tofu and 2 more"},
+	}
+	for _, tt := range tests {
+		if got := listNames(tt.names); got != tt.want {
+			t.Errorf("Expected %q, got %q", tt.want, got)
+		}
+	}
+}
@@ -0,0 +1,288 @@
+package main
+
+import (
+	"bufio"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base32"
+	"encoding/binary"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+const (
+	// transferTimeout is how long a send waits for the other machine
+	transferTimeout = 10 * time.Minute
+
+	// transferAttempts is how many wrong receivers a send tolerates before
+	// giving up, so a code can't be guessed at forever
+	transferAttempts = 3
+
+	// transferSecretBytes is the random part of a transfer code
+	transferSecretBytes = 10
+
+	// maxTransferSize is the largest pet we'll accept
+	maxTransferSize = 32 << 20
+)
+
+// petBundle is everything that moves with a pet: the save (network state
+// and custody key included) and its checkpoints
+type petBundle struct {
+	Save        json.RawMessage   `json:"save"`
+	Checkpoints map[string][]byte `json:"checkpoints,omitempty"`
+}
+
+// bundlePet gathers a pet and its checkpoints for the move
+func bundlePet(pet *Pet) (*petBundle, error) {
+	save, err := json.Marshal(pet)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal pet data: %w", err)
+	}
+	bundle := &petBundle{Save: save, Checkpoints: map[string][]byte{}}
+
+	entries, err := os.ReadDir(checkpointDir(pet))
+	if err != nil && !os.IsNotExist(err) {
+		return nil, fmt.Errorf("failed to read checkpoints: %w", err)
+	}
+	for _, entry := range entries {
+		if entry.IsDir() || !strings.HasSuffix(entry.Name(), ".json") {
+			continue
+		}
+		data, err := os.ReadFile(filepath.Join(checkpointDir(pet), entry.Name()))
+		if err != nil {
+			return nil, fmt.Errorf("failed to read checkpoint: %w", err)
+		}
+		bundle.Checkpoints[entry.Name()] = data
+	}
+	return bundle, nil
+}
+
+// unpack writes a received pet next to savePath
+func (b *petBundle) unpack(savePath string) (*Pet, error) {
+	pet, err := decodePet(b.Save, savePath)
+	if err != nil {
+		return nil, err
+	}
+	pet.DepartedAt = time.Time{} // It has arrived
+
+	if len(b.Checkpoints) > 0 {
+		if err := os.MkdirAll(checkpointDir(pet), 0755); err != nil {
+			return nil, fmt.Errorf("failed to create checkpoint folder: %w", err)
+		}
+	}
+	for name, data := range b.Checkpoints {
+		label := strings.TrimSuffix(name, ".json")
+		if name != filepath.Base(name) || !checkpointLabelPattern.MatchString(label) {
+			continue // Never let the sender pick where files go
+		}
+		if err := os.WriteFile(filepath.Join(checkpointDir(pet), name), data, 0644); err != nil {
+			return nil, fmt.Errorf("failed to write checkpoint: %w", err)
+		}
+	}
+	return pet, pet.Save()
+}
+
+// newTransferSecret makes the one-time secret half of a transfer code
+func newTransferSecret() (string, error) {
+	secret := make([]byte, transferSecretBytes)
+	if _, err := rand.Read(secret); err != nil {
+		return "", err
+	}
+	return base32.StdEncoding.WithPadding(base32.NoPadding).EncodeToString(secret), nil
+}
+
+// parseTransferCode splits a code into where to connect and the secret
+func parseTransferCode(code string) (string, string, error) {
+	addr, secret, ok := strings.Cut(strings.TrimSpace(code), "/")
+	if !ok || secret == "" {
+		return "", "", fmt.Errorf("transfer codes look like 192.168.1.20:41733/ABCD...")
+	}
+	if _, _, err := net.SplitHostPort(addr); err != nil {
+		return "", "", fmt.Errorf("transfer codes look like 192.168.1.20:41733/ABCD...")
+	}
+	return addr, strings.ToUpper(secret), nil
+}
+
+// transferAck proves the receiver could open exactly what was sent
+func transferAck(sealed []byte, secret string) string {
+	sum := sha256.Sum256(sealed)
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte("received:"))
+	mac.Write(sum[:])
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// lanAddress picks the address another machine on the LAN can reach us at
+func lanAddress() string {
+	addrs, err := net.InterfaceAddrs()
+	if err == nil {
+		for _, addr := range addrs {
+			if ipNet, ok := addr.(*net.IPNet); ok && !ipNet.IP.IsLoopback() && ipNet.IP.To4() != nil {
+				return ipNet.IP.String()
+			}
+		}
+	}
+	return "127.0.0.1"
+}
+
+// sendPet hands the pet to the first receiver that can prove it knows the
+// secret, then marks this copy as departed so only one of them is on the
+// mesh. Anyone can connect, but without the secret all they get is
+// ciphertext.
+func sendPet(pet *Pet, listener net.Listener, secret string) error {
+	bundle, err := bundlePet(pet)
+	if err != nil {
+		return err
+	}
+	plain, err := json.Marshal(bundle)
+	if err != nil {
+		return err
+	}
+	sealed, err := encryptBackup(plain, secret)
+	if err != nil {
+		return err
+	}
+	want := transferAck(sealed, secret)
+
+	for attempt := 0; attempt < transferAttempts; attempt++ {
+		conn, err := listener.Accept()
+		if err != nil {
+			return fmt.Errorf("nobody came for %s: %w", pet.Name, err)
+		}
+		ack, err := offerPet(conn, sealed)
+		if err == nil && hmac.Equal([]byte(ack), []byte(want)) {
+			pet.DepartedAt = time.Now()
+			return pet.Save()
+		}
+	}
+	return fmt.Errorf("too many wrong codes; %s stays here", pet.Name)
+}
+
+// offerPet sends the sealed pet down one connection and reads the answer
+func offerPet(conn net.Conn, sealed []byte) (string, error) {
+	defer conn.Close()
+	conn.SetDeadline(time.Now().Add(time.Minute))
+
+	if err := binary.Write(conn, binary.BigEndian, uint32(len(sealed))); err != nil {
+		return "", err
+	}
+	if _, err := conn.Write(sealed); err != nil {
+		return "", err
+	}
+	ack, err := bufio.NewReader(io.LimitReader(conn, 128)).ReadString('\n')
+	return strings.TrimSpace(ack), err
+}
+
+// receivePet fetches a pet from the machine named in the code
+func receivePet(code string) (*petBundle, error) {
+	addr, secret, err := parseTransferCode(code)
+	if err != nil {
+		return nil, err
+	}
+	conn, err := net.DialTimeout("tcp", addr, 10*time.Second)
+	if err != nil {
+		return nil, fmt.Errorf("couldn't reach the other machine: %w", err)
+	}
+	defer conn.Close()
+	conn.SetDeadline(time.Now().Add(time.Minute))
+
+	var size uint32
+	if err := binary.Read(conn, binary.BigEndian, &size); err != nil {
+		return nil, fmt.Errorf("transfer failed: %w", err)
+	}
+	if size > maxTransferSize {
+		return nil, fmt.Errorf("transfer is too large")
+	}
+	sealed := make([]byte, size)
+	if _, err := io.ReadFull(conn, sealed); err != nil {
+		return nil, fmt.Errorf("transfer failed: %w", err)
+	}
+	plain, err := decryptBackup(sealed, secret)
+	if err != nil {
+		return nil, fmt.Errorf("wrong code, or the transfer was damaged")
+	}
+	var bundle petBundle
+	if err := json.Unmarshal(plain, &bundle); err != nil {
+		return nil, fmt.Errorf("transfer doesn't contain a pet: %w", err)
+	}
+
+	// Only now does the sender let go
+	if _, err := fmt.Fprintln(conn, transferAck(sealed, secret)); err != nil {
+		return nil, fmt.Errorf("transfer failed: %w", err)
+	}
+	return &bundle, nil
+}
+
+// runSendSubcommand handles `tamagotchi send pet`
+func runSendSubcommand(args []string) error {
+	if len(args) == 0 || args[0] != "pet" {
+		return fmt.Errorf("usage: tamagotchi send pet")
+	}
+	if err := classroomOff("Moving pets"); err != nil {
+		return err
+	}
+	pet, err := LoadPet(saveFile)
+	if err != nil {
+		return fmt.Errorf("no pet to send: %w", err)
+	}
+	if !pet.DepartedAt.IsZero() {
+		return fmt.Errorf("%s already moved to another machine", pet.Name)
+	}
+
+	secret, err := newTransferSecret()
+	if err != nil {
+		return err
+	}
+	listener, err := net.Listen("tcp4", ":0")
+	if err != nil {
+		return fmt.Errorf("couldn't open a port: %w", err)
+	}
+	defer listener.Close()
+	go func() {
+		time.Sleep(transferTimeout)
+		listener.Close()
+	}()
+
+	_, port, _ := net.SplitHostPort(listener.Addr().String())
+	fmt.Print(consoleText(fmt.Sprintf("🧳 %s is packed. On the other machine, run:\n\n  tamagotchi receive pet %s:%s/%s\n\n", pet.Name, lanAddress(), port, secret)))
+	fmt.Println("Quit the game here first. Waiting...")
+
+	if err := sendPet(pet, listener, secret); err != nil {
+		return err
+	}
+	fmt.Print(consoleText(fmt.Sprintf("👋 %s has moved. This copy stays behind as a note that they left.\n", pet.Name)))
+	return nil
+}
+
+// runReceiveSubcommand handles `tamagotchi receive pet <code>`
+func runReceiveSubcommand(args []string) error {
+	if len(args) < 2 || args[0] != "pet" {
+		return fmt.Errorf("usage: tamagotchi receive pet <code> [--force]")
+	}
+	if err := classroomOff("Moving pets"); err != nil {
+		return err
+	}
+	force := len(args) > 2 && args[2] == "--force"
+	if existing, err := LoadPet(saveFile); err == nil && existing.DepartedAt.IsZero() && !force {
+		return fmt.Errorf("%s already lives here; pass --force to replace them", existing.Name)
+	}
+
+	bundle, err := receivePet(args[1])
+	if err != nil {
+		return err
+	}
+	pet, err := bundle.unpack(saveFile)
+	if err != nil {
+		return err
+	}
+	fmt.Print(consoleText(fmt.Sprintf("🏠 %s has arrived. Start the game to say hello.\n", pet.Name)))
+	return nil
+}
@@ -0,0 +1,132 @@
+package main
+
+import (
+	"net"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestParseTransferCode(t *testing.T) {
+	tests := []struct {
+		code    string
+		addr    string
+		secret  string
+		wantErr bool
+	}{
+		{"192.168.1.20:41733/abcd2345", "192.168.1.20:41733", "ABCD2345", false},
+		{" 10.0.0.2:9000/XYZ \n", "10.0.0.2:9000", "XYZ", false},
+		{"192.168.1.20:41733", "", "", true},
+		{"192.168.1.20/ABCD", "", "", true},
+		{"192.168.1.20:41733/", "", "", true},
+	}
+
+	for _, tt := range tests {
+		addr, secret, err := parseTransferCode(tt.code)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("parseTransferCode(%q): Expected error %v, got %v", tt.code, tt.wantErr, err)
+		}
+		if addr != tt.addr || secret != tt.secret {
+			t.Errorf("parseTransferCode(%q): Expected %s and %s, got %s and %s", tt.code, tt.addr, tt.secret, addr, secret)
+		}
+	}
+}
+
+// startSend offers a pet on a loopback port, returning where to find it
+// and a channel with the send's result
+func startSend(t *testing.T, pet *Pet, secret string) (string, chan error) {
+	t.Helper()
+	listener, err := net.Listen("tcp4", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("Expected a loopback port, got %v", err)
+	}
+	t.Cleanup(func() { listener.Close() })
+	done := make(chan error, 1)
+	go func() { done <- sendPet(pet, listener, secret) }()
+	return listener.Addr().String(), done
+}
+
+func TestSendAndReceivePet(t *testing.T) {
+	from, to := t.TempDir(), t.TempDir()
+	pet := NewPet("Traveler")
+	pet.Stage = Adult
+	pet.SaveFilePath = filepath.Join(from, saveFile)
+	pet.Save()
+	os.MkdirAll(checkpointDir(pet), 0755)
+	os.WriteFile(filepath.Join(checkpointDir(pet), "before-trip.json"), []byte(`{"label":"before-trip"}`), 0644)
+
+	secret, err := newTransferSecret()
+	if err != nil {
+		t.Fatal(err)
+	}
+	addr, done := startSend(t, pet, secret)
+
+	if _, err := receivePet(addr + "/WRONGCODE"); err == nil {
+		t.Errorf("Expected a wrong code to fail")
+	}
+	bundle, err := receivePet(addr + "/" + secret)
+	if err != nil {
+		t.Fatalf("Expected receive to succeed, got %v", err)
+	}
+	if err := <-done; err != nil {
+		t.Fatalf("Expected send to succeed after one wrong code, got %v", err)
+	}
+
+	arrived, err := bundle.unpack(filepath.Join(to, saveFile))
+	if err != nil {
+		t.Fatalf("Expected unpack to succeed, got %v", err)
+	}
+	if arrived.Name != "Traveler" || arrived.Stage != Adult || !arrived.DepartedAt.IsZero() {
+		t.Errorf("Expected an adult Traveler at home, got %s (%s), departed %v", arrived.Name, arrived.Stage, arrived.DepartedAt)
+	}
+	if _, err := os.Stat(filepath.Join(to, checkpointDirName, "before-trip.json")); err != nil {
+		t.Errorf("Expected the checkpoint to come along, got %v", err)
+	}
+
+	left, err := LoadPet(pet.SaveFilePath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if left.DepartedAt.IsZero() || left.mostUrgentNeed() != "moved away" {
+		t.Errorf("Expected the source copy to be marked departed")
+	}
+}
+
+func TestSendGivesUpAfterWrongCodes(t *testing.T) {
+	pet := NewPet("Homebody")
+	pet.SaveFilePath = filepath.Join(t.TempDir(), saveFile)
+	addr, done := startSend(t, pet, "RIGHTCODE")
+
+	for i := 0; i < transferAttempts; i++ {
+		if _, err := receivePet(addr + "/WRONGCODE"); err == nil {
+			t.Errorf("Expected a wrong code to fail")
+		}
+	}
+	if err := <-done; err == nil {
+		t.Errorf("Expected send to give up")
+	}
+	if !pet.DepartedAt.IsZero() {
+		t.Errorf("Expected the pet to stay put")
+	}
+}
+
+func TestUnpackIgnoresUnsafeCheckpointNames(t *testing.T) {
+	dir := t.TempDir()
+	pet := NewPet("Careful")
+	bundle, err := bundlePet(pet)
+	if err != nil {
+		t.Fatal(err)
+	}
+	bundle.Checkpoints["../escape.json"] = []byte("{}")
+	bundle.Checkpoints["ok.json"] = []byte("{}")
+
+	if _, err := bundle.unpack(filepath.Join(dir, "save", saveFile)); err != nil {
+		t.Fatalf("Expected unpack to succeed, got %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(dir, "escape.json")); err == nil {
+		t.Errorf("Expected a checkpoint named with .. to be skipped")
+	}
+	if _, err := os.Stat(filepath.Join(dir, "save", checkpointDirName, "ok.json")); err != nil {
+		t.Errorf("Expected a safe checkpoint to be written, got %v", err)
+	}
+}
@@ -17,13 +17,13 @@ type MiniGameResult struct {
 
 // PlayWatchPaintDry plays the "Watch Paint Dry" mini-game
 // Literally just a timer with no reward
-func PlayWatchPaintDry(reader *bufio.Reader) MiniGameResult {
-	fmt.Println("\n╔════════════════════════════════════╗")
-	fmt.Println("║    🎨 WATCH PAINT DRY 🎨          ║")
-	fmt.Println("╠════════════════════════════════════╣")
-	fmt.Println("║ Watch the paint dry for 10 seconds ║")
-	fmt.Println("║ Press Enter to start...            ║")
-	fmt.Println("╚════════════════════════════════════╝")
+func PlayWatchPaintDry(reader *bufio.Reader, ui *uiConfig) MiniGameResult {
+	fmt.Println(ui.text("\n╔════════════════════════════════════╗\n" +
+		"║    🎨 WATCH PAINT DRY 🎨          ║\n" +
+		"╠════════════════════════════════════╣\n" +
+		"║ Watch the paint dry for 10 seconds ║\n" +
+		"║ Press Enter to start...            ║\n" +
+		"╚════════════════════════════════════╝"))
 
 	reader.ReadString('\n')
 
@@ -45,8 +45,8 @@ func PlayWatchPaintDry(reader *bufio.Reader) MiniGameResult {
 		time.Sleep(1 * time.Second)
 	}
 
-	fmt.Println("\n\n✅ Congratulations! You watched paint dry.")
-	fmt.Println("🏆 Reward: None. What did you expect?")
+	fmt.Println(ui.text("\n\n✅ Congratulations! You watched paint dry."))
+	fmt.Println(ui.text("🏆 Reward: None. What did you expect?"))
 
 	return MiniGameResult{
 		Message: "You watched paint dry. Time you'll never get back.",
@@ -56,18 +56,18 @@ func PlayWatchPaintDry(reader *bufio.Reader) MiniGameResult {
 
 // PlayStareContest plays the "Stare Contest" mini-game
 // Press any key and you lose, don't press and nothing happens
-func PlayStareContest(reader *bufio.Reader) MiniGameResult {
-	fmt.Println("\n╔════════════════════════════════════╗")
-	fmt.Println("║    👁️ STARE CONTEST 👁️            ║")
-	fmt.Println("╠════════════════════════════════════╣")
-	fmt.Println("║ Rules:                              ║")
-	fmt.Println("║ - Don't press any key               ║")
-	fmt.Println("║ - If you press a key, you lose      ║")
-	fmt.Println("║ - If you don't press, nothing happens║")
-	fmt.Println("║                                      ║")
-	fmt.Println("║ The contest has already begun...    ║")
-	fmt.Println("╚════════════════════════════════════╝")
-	fmt.Println("\n       👁️     👁️")
+func PlayStareContest(reader *bufio.Reader, ui *uiConfig) MiniGameResult {
+	fmt.Println(ui.text("\n╔════════════════════════════════════╗\n" +
+		"║    👁️ STARE CONTEST 👁️            ║\n" +
+		"╠════════════════════════════════════╣\n" +
+		"║ Rules:                              ║\n" +
+		"║ - Don't press any key               ║\n" +
+		"║ - If you press a key, you lose      ║\n" +
+		"║ - If you don't press, nothing happens║\n" +
+		"║                                      ║\n" +
+		"║ The contest has already begun...    ║\n" +
+		"╚════════════════════════════════════╝"))
+	fmt.Println(ui.text("\n       👁️     👁️"))
 	fmt.Println("         ___")
 	fmt.Println("        \\   /")
 	fmt.Println("         ---")
@@ -78,7 +78,7 @@ func PlayStareContest(reader *bufio.Reader) MiniGameResult {
 
 	reader.ReadString('\n')
 
-	fmt.Println("\n❌ YOU BLINKED!")
+	fmt.Println(ui.text("\n❌ YOU BLINKED!"))
 	fmt.Println("Your pet wins. Your pet always wins.")
 	fmt.Println("The staring contest was rigged from the start.")
 
@@ -90,17 +90,17 @@ func PlayStareContest(reader *bufio.Reader) MiniGameResult {
 
 // PlayCountToThousand plays the "Count to 1000" mini-game
 // Manual counting, loses progress if you mistype
-func PlayCountToThousand(reader *bufio.Reader) MiniGameResult {
-	fmt.Println("\n╔════════════════════════════════════╗")
-	fmt.Println("║    🔢 COUNT TO 1000 🔢             ║")
-	fmt.Println("╠════════════════════════════════════╣")
-	fmt.Println("║ Rules:                              ║")
-	fmt.Println("║ - Type numbers from 1 to 1000       ║")
-	fmt.Println("║ - One wrong number resets everything║")
-	fmt.Println("║ - Type 'quit' to give up            ║")
-	fmt.Println("║                                      ║")
-	fmt.Println("║ Good luck. You'll need it.          ║")
-	fmt.Println("╚════════════════════════════════════╝")
+func PlayCountToThousand(reader *bufio.Reader, ui *uiConfig) MiniGameResult {
+	fmt.Println(ui.text("\n╔════════════════════════════════════╗\n" +
+		"║    🔢 COUNT TO 1000 🔢             ║\n" +
+		"╠════════════════════════════════════╣\n" +
+		"║ Rules:                              ║\n" +
+		"║ - Type numbers from 1 to 1000       ║\n" +
+		"║ - One wrong number resets everything║\n" +
+		"║ - Type 'quit' to give up            ║\n" +
+		"║                                      ║\n" +
+		"║ Good luck. You'll need it.          ║\n" +
+		"╚════════════════════════════════════╝"))
 
 	currentNumber := 1
 	highestReached := 0
@@ -111,11 +111,11 @@ func PlayCountToThousand(reader *bufio.Reader) MiniGameResult {
 		input = strings.TrimSpace(input)
 
 		if strings.ToLower(input) == "quit" {
-			fmt.Printf("\n😔 You gave up at %d.", currentNumber)
+			fmt.Print(ui.text(fmt.Sprintf("\n😔 You gave up at %d.", currentNumber)))
 			if highestReached > 0 {
 				fmt.Printf(" Highest reached: %d", highestReached)
 			}
-			fmt.Println("\n🏆 Reward: The wisdom that some things aren't worth doing.")
+			fmt.Println(ui.text("\n🏆 Reward: The wisdom that some things aren't worth doing."))
 			return MiniGameResult{
 				Message: fmt.Sprintf("Gave up counting at %d. Wisdom gained.", currentNumber),
 				Success: false,
@@ -124,7 +124,7 @@ func PlayCountToThousand(reader *bufio.Reader) MiniGameResult {
 
 		num, err := strconv.Atoi(input)
 		if err != nil || num != currentNumber {
-			fmt.Println("\n❌ WRONG!")
+			fmt.Println(ui.text("\n❌ WRONG!"))
 			fmt.Printf("You typed '%s' but needed '%d'\n", input, currentNumber)
 			if currentNumber > highestReached {
 				highestReached = currentNumber
@@ -159,9 +159,9 @@ func PlayCountToThousand(reader *bufio.Reader) MiniGameResult {
 	}
 
 	// If someone actually reaches 1000
-	fmt.Println("\n🎉🎉🎉 YOU ACTUALLY DID IT 🎉🎉🎉")
+	fmt.Println(ui.text("\n🎉🎉🎉 YOU ACTUALLY DID IT 🎉🎉🎉"))
 	fmt.Println("You counted to 1000. Manually. One number at a time.")
-	fmt.Println("🏆 Reward: A profound sense of... something. Not accomplishment.")
+	fmt.Println(ui.text("🏆 Reward: A profound sense of... something. Not accomplishment."))
 	fmt.Println("Maybe regret? It's hard to say.")
 	fmt.Println("\nYour pet looks at you with what might be respect.")
 	fmt.Println("Or concern. Probably concern.")
@@ -174,14 +174,14 @@ func PlayCountToThousand(reader *bufio.Reader) MiniGameResult {
 
 // PlayDoNothing plays the "Do Nothing" mini-game
 // The game of doing absolutely nothing
-func PlayDoNothing(reader *bufio.Reader) MiniGameResult {
-	fmt.Println("\n╔════════════════════════════════════╗")
-	fmt.Println("║    🧘 DO NOTHING 🧘                ║")
-	fmt.Println("╠════════════════════════════════════╣")
-	fmt.Println("║ Instructions:                       ║")
-	fmt.Println("║ - Do nothing                        ║")
-	fmt.Println("║ - Press Enter when done doing nothing║")
-	fmt.Println("╚════════════════════════════════════╝")
+func PlayDoNothing(reader *bufio.Reader, ui *uiConfig) MiniGameResult {
+	fmt.Println(ui.text("\n╔════════════════════════════════════╗\n" +
+		"║    🧘 DO NOTHING 🧘                ║\n" +
+		"╠════════════════════════════════════╣\n" +
+		"║ Instructions:                       ║\n" +
+		"║ - Do nothing                        ║\n" +
+		"║ - Press Enter when done doing nothing║\n" +
+		"╚════════════════════════════════════╝"))
 	fmt.Println("\n   Doing nothing...")
 	fmt.Println("   ...")
 	fmt.Println("   ...")
@@ -193,8 +193,8 @@ func PlayDoNothing(reader *bufio.Reader) MiniGameResult {
 	randomSource := rand.New(rand.NewSource(time.Now().UnixNano()))
 	nothingTime := 1 + randomSource.Intn(60)
 
-	fmt.Printf("\n✅ You did nothing for approximately %d seconds.\n", nothingTime)
-	fmt.Println("🏆 Achievement Unlocked: Nothing")
+	fmt.Print(ui.text(fmt.Sprintf("\n✅ You did nothing for approximately %d seconds.\n", nothingTime)))
+	fmt.Println(ui.text("🏆 Achievement Unlocked: Nothing"))
 
 	return MiniGameResult{
 		Message: fmt.Sprintf("Did nothing for %d seconds. Impressive.", nothingTime),
@@ -203,14 +203,14 @@ func PlayDoNothing(reader *bufio.Reader) MiniGameResult {
 }
 
 // PlayGuessTheNumber plays a guess the number game where the number changes
-func PlayGuessTheNumber(reader *bufio.Reader) MiniGameResult {
-	fmt.Println("\n╔════════════════════════════════════╗")
-	fmt.Println("║    🎲 GUESS THE NUMBER 🎲          ║")
-	fmt.Println("╠════════════════════════════════════╣")
-	fmt.Println("║ I'm thinking of a number 1-10      ║")
-	fmt.Println("║ You have 3 guesses                 ║")
-	fmt.Println("║ Type 'quit' to give up             ║")
-	fmt.Println("╚════════════════════════════════════╝")
+func PlayGuessTheNumber(reader *bufio.Reader, ui *uiConfig) MiniGameResult {
+	fmt.Println(ui.text("\n╔════════════════════════════════════╗\n" +
+		"║    🎲 GUESS THE NUMBER 🎲          ║\n" +
+		"╠════════════════════════════════════╣\n" +
+		"║ I'm thinking of a number 1-10      ║\n" +
+		"║ You have 3 guesses                 ║\n" +
+		"║ Type 'quit' to give up             ║\n" +
+		"╚════════════════════════════════════╝"))
 
 	randomSource := rand.New(rand.NewSource(time.Now().UnixNano()))
 
@@ -223,7 +223,7 @@ func PlayGuessTheNumber(reader *bufio.Reader) MiniGameResult {
 		input = strings.TrimSpace(input)
 
 		if strings.ToLower(input) == "quit" {
-			fmt.Println("\n😔 You gave up.")
+			fmt.Println(ui.text("\n😔 You gave up."))
 			fmt.Printf("The number was %d. Or was it? It kept changing.\n", targetNumber)
 			return MiniGameResult{
 				Message: "Gave up guessing. The game was rigged anyway.",
@@ -240,9 +240,9 @@ func PlayGuessTheNumber(reader *bufio.Reader) MiniGameResult {
 
 		if num == targetNumber {
 			// This should rarely happen but it's possible
-			fmt.Println("\n🎉 IMPOSSIBLE! You got it!")
+			fmt.Println(ui.text("\n🎉 IMPOSSIBLE! You got it!"))
 			fmt.Println("The number was changing each guess, but you got lucky.")
-			fmt.Println("🏆 Reward: Existential uncertainty about probability")
+			fmt.Println(ui.text("🏆 Reward: Existential uncertainty about probability"))
 			return MiniGameResult{
 				Message: "Won an unwinnable game. Reality questioned.",
 				Success: true,
@@ -258,10 +258,10 @@ func PlayGuessTheNumber(reader *bufio.Reader) MiniGameResult {
 		}
 	}
 
-	fmt.Println("\n❌ Out of guesses!")
+	fmt.Println(ui.text("\n❌ Out of guesses!"))
 	fmt.Println("The number was... well, it kept changing.")
 	fmt.Println("This game was never fair.")
-	fmt.Println("🏆 Reward: Understanding that some games can't be won")
+	fmt.Println(ui.text("🏆 Reward: Understanding that some games can't be won"))
 
 	return MiniGameResult{
 		Message: "Lost guess the number. The game was rigged.",
@@ -270,49 +270,60 @@ func PlayGuessTheNumber(reader *bufio.Reader) MiniGameResult {
 }
 
 // ShowMiniGameMenu displays available mini-games
-func ShowMiniGameMenu() {
-	fmt.Println("\n╔════════════════════════════════════╗")
-	fmt.Println("║     🎮 USELESS MINI-GAMES 🎮       ║")
-	fmt.Println("╠════════════════════════════════════╣")
-	fmt.Println("║ 1. Watch Paint Dry                 ║")
-	fmt.Println("║ 2. Stare Contest                   ║")
-	fmt.Println("║ 3. Count to 1000                   ║")
-	fmt.Println("║ 4. Do Nothing                      ║")
-	fmt.Println("║ 5. Guess the Number                ║")
-	fmt.Println("║                                    ║")
-	fmt.Println("║ Type 'back' to return              ║")
-	fmt.Println("╚════════════════════════════════════╝")
+func ShowMiniGameMenu(ui *uiConfig) {
+	fmt.Println(ui.text("\n╔════════════════════════════════════╗\n" +
+		"║     🎮 USELESS MINI-GAMES 🎮       ║\n" +
+		"╠════════════════════════════════════╣\n" +
+		"║ 1. Watch Paint Dry                 ║\n" +
+		"║ 2. Stare Contest                   ║\n" +
+		"║ 3. Count to 1000                   ║\n" +
+		"║ 4. Do Nothing                      ║\n" +
+		"║ 5. Guess the Number                ║\n" +
+		"║ 6. Maze                            ║\n" +
+		"║ 7. Typing Race                     ║\n" +
+		"║                                    ║\n" +
+		"║ Type 'back' to return              ║\n" +
+		"╚════════════════════════════════════╝"))
 }
 
 // SelectAndPlayMiniGame handles mini-game selection and playing
-func SelectAndPlayMiniGame(reader *bufio.Reader) *MiniGameResult {
-	ShowMiniGameMenu()
+// The games that never touch the pet are played with it let go.
+func SelectAndPlayMiniGame(reader *bufio.Reader, session *petSession, ui *uiConfig, pet *Pet) *MiniGameResult {
+	ShowMiniGameMenu(ui)
 
 	for {
-		fmt.Print("\nSelect a game (1-5): ")
-		input, _ := reader.ReadString('\n')
+		fmt.Print("\nSelect a game (1-7): ")
+		var input string
+		session.Wait(func() { input, _ = reader.ReadString('\n') })
 		input = strings.TrimSpace(strings.ToLower(input))
 
+		var result MiniGameResult
 		switch input {
 		case "1", "paint", "watch":
-			result := PlayWatchPaintDry(reader)
+			session.Wait(func() { result = PlayWatchPaintDry(reader, ui) })
 			return &result
 		case "2", "stare", "contest":
-			result := PlayStareContest(reader)
+			session.Wait(func() { result = PlayStareContest(reader, ui) })
 			return &result
 		case "3", "count", "1000":
-			result := PlayCountToThousand(reader)
+			session.Wait(func() { result = PlayCountToThousand(reader, ui) })
 			return &result
 		case "4", "nothing", "do nothing":
-			result := PlayDoNothing(reader)
+			session.Wait(func() { result = PlayDoNothing(reader, ui) })
 			return &result
 		case "5", "guess", "number":
-			result := PlayGuessTheNumber(reader)
+			session.Wait(func() { result = PlayGuessTheNumber(reader, ui) })
+			return &result
+		case "6", "maze":
+			result = PlayMaze(reader, session, ui, pet)
+			return &result
+		case "7", "typing", "race":
+			result = PlayTypingRace(reader, session, ui, pet)
 			return &result
 		case "back", "quit", "exit":
 			return nil
 		default:
-			fmt.Println("Unknown game. Try a number 1-5 or 'back'.")
+			fmt.Println("Unknown game. Try a number 1-7 or 'back'.")
 		}
 	}
 }
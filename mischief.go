@@ -0,0 +1,99 @@
+package main
+
+import (
+	"fmt"
+	"math/rand"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// mischiefNoteFile is where a bored pet leaves notes, beside the save
+const mischiefNoteFile = "tamagotchi_note.txt"
+
+// mischiefLevel is how soon and how often a pet left alone gets up to
+// something, and what it's allowed to do
+type mischiefLevel struct {
+	after time.Duration // Idle time before the first act
+	every time.Duration // Time between acts after that
+	acts  []string
+}
+
+// mischiefLevels are the choices for TAMAGOTCHI_MISCHIEF
+var mischiefLevels = map[string]*mischiefLevel{
+	"off":    nil,
+	"low":    {after: 10 * time.Minute, every: 10 * time.Minute, acts: []string{"wander", "nap"}},
+	"normal": {after: 5 * time.Minute, every: 5 * time.Minute, acts: []string{"wander", "nap", "tap", "note"}},
+	"high":   {after: 2 * time.Minute, every: 3 * time.Minute, acts: []string{"wander", "nap", "tap", "note", "quest"}},
+}
+
+// idleScheduler decides what the pet does while nobody types
+type idleScheduler struct {
+	level   *mischiefLevel
+	lastAct time.Time
+	rand    *rand.Rand
+}
+
+// newIdleScheduler reads the mischief level from TAMAGOTCHI_MISCHIEF
+func newIdleScheduler(getenv func(string) string, rng *rand.Rand) (*idleScheduler, error) {
+	name := strings.ToLower(strings.TrimSpace(getenv("TAMAGOTCHI_MISCHIEF")))
+	if name == "" {
+		name = "normal"
+	}
+	level, ok := mischiefLevels[name]
+	if !ok {
+		return nil, fmt.Errorf("TAMAGOTCHI_MISCHIEF must be off, low, normal, or high, got %q", name)
+	}
+	return &idleScheduler{level: level, rand: rng}, nil
+}
+
+// Act lets the pet do something if the player has been away long enough,
+// returning what it did and what to show. Both are "" when it stays put.
+func (s *idleScheduler) Act(p *Pet, idle time.Duration, now time.Time) (string, string) {
+	if s == nil || s.level == nil || idle < s.level.after {
+		return "", ""
+	}
+	// Once per stretch of idleness, then every so often
+	if s.lastAct.After(now.Add(-idle)) && now.Sub(s.lastAct) < s.level.every {
+		return "", ""
+	}
+	if p.Stage == Egg || p.Stage == Dead || p.unavailableMessage(now) != "" {
+		return "", ""
+	}
+
+	s.lastAct = now
+	act := s.level.acts[s.rand.Intn(len(s.level.acts))]
+	switch act {
+	case "nap":
+		return act, fmt.Sprintf("💤 %s gives up waiting and curls into a nap.", p.Name)
+	case "tap":
+		return act, fmt.Sprintf("👆 *tap tap tap* %s taps on the glass. Hello? Anyone?", p.Name)
+	case "note":
+		note := fmt.Sprintf("%s: %s was here. You were not. Noted.\n", now.Format("2006-01-02 15:04"), p.Name)
+		if err := appendNote(filepath.Join(filepath.Dir(p.SaveFilePath), mischiefNoteFile), note); err != nil {
+			break
+		}
+		return act, fmt.Sprintf("📝 %s leaves you a note in %s.", p.Name, mischiefNoteFile)
+	case "quest":
+		if p.Endgame == nil || p.Endgame.ActiveQuest != nil {
+			break
+		}
+		return act, fmt.Sprintf("🗺️ %s got bored and started a quest without you.", p.Name) + p.Endgame.GenerateQuest()
+	}
+	// Wandering is what a pet falls back on
+	return "wander", fmt.Sprintf("🐾 %s wanders to the edge of the screen, sniffs it, and wanders back.", p.Name)
+}
+
+// appendNote adds a line to the note file, creating it if needed
+func appendNote(path, note string) error {
+	f, err := os.OpenFile(path, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0644)
+	if err != nil {
+		return err
+	}
+	if _, err := f.WriteString(note); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
@@ -0,0 +1,104 @@
+package main
+
+import (
+	"math/rand"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestNewIdleScheduler(t *testing.T) {
+	tests := []struct {
+		value string
+		acts  int
+		err   bool
+	}{
+		{"", 4, false},
+		{"off", 0, false},
+		{"Low", 2, false},
+		{"high", 5, false},
+		{"chaos", 0, true},
+	}
+	for _, tt := range tests {
+		s, err := newIdleScheduler(func(string) string { return tt.value }, rand.New(rand.NewSource(1)))
+		if (err != nil) != tt.err {
+			t.Errorf("Expected error %v for %q, got %v", tt.err, tt.value, err)
+			continue
+		}
+		if err == nil && s.level != nil && len(s.level.acts) != tt.acts {
+			t.Errorf("Expected %d acts for %q, got %d", tt.acts, tt.value, len(s.level.acts))
+		}
+	}
+}
+
+func TestIdleSchedulerPacesItself(t *testing.T) {
+	now := time.Now()
+	pet := NewPet("Bored")
+	pet.Stage = Adult
+	s := &idleScheduler{level: &mischiefLevel{after: 5 * time.Minute, every: 5 * time.Minute, acts: []string{"nap"}}, rand: rand.New(rand.NewSource(1))}
+
+	steps := []struct {
+		name string
+		at   time.Duration // Time since the first step
+		idle time.Duration
+		want string
+	}{
+		{"too soon", 0, 4 * time.Minute, ""},
+		{"bored", 0, 5 * time.Minute, "nap"},
+		{"just acted", 2 * time.Minute, 7 * time.Minute, ""},
+		{"bored again", 5 * time.Minute, 10 * time.Minute, "nap"},
+		{"player came back and left", 11 * time.Minute, 5 * time.Minute, "nap"},
+	}
+	for _, step := range steps {
+		if act, _ := s.Act(pet, step.idle, now.Add(step.at)); act != step.want {
+			t.Errorf("%s: expected %q, got %q", step.name, step.want, act)
+		}
+	}
+}
+
+func TestIdleSchedulerActs(t *testing.T) {
+	tests := []struct {
+		name    string
+		act     string
+		setup   func(*Pet)
+		want    string
+		message string
+	}{
+		{"nap", "nap", nil, "nap", "nap"},
+		{"tap", "tap", nil, "tap", "taps on the glass"},
+		{"note", "note", nil, "note", mischiefNoteFile},
+		{"quest", "quest", nil, "quest", "NEW QUEST"},
+		{"quest already", "quest", func(p *Pet) { p.Endgame.GenerateQuest() }, "wander", "wanders"},
+		{"paused", "nap", func(p *Pet) { p.Paused = true }, "", ""},
+		{"egg", "nap", func(p *Pet) { p.Stage = Egg }, "", ""},
+		{"dead", "nap", func(p *Pet) { p.Stage = Dead }, "", ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			pet := NewPet("Mischief")
+			pet.Stage = Adult
+			pet.SaveFilePath = filepath.Join(t.TempDir(), saveFile)
+			if tt.setup != nil {
+				tt.setup(pet)
+			}
+			s := &idleScheduler{level: &mischiefLevel{after: time.Minute, every: time.Minute, acts: []string{tt.act}}, rand: rand.New(rand.NewSource(1))}
+			act, message := s.Act(pet, time.Hour, time.Now())
+			if act != tt.want || !strings.Contains(message, tt.message) {
+				t.Errorf("Expected %q with %q, got %q with %q", tt.want, tt.message, act, message)
+			}
+			if tt.act == "note" {
+				note, err := os.ReadFile(filepath.Join(filepath.Dir(pet.SaveFilePath), mischiefNoteFile))
+				if err != nil || !strings.Contains(string(note), "Mischief was here") {
+					t.Errorf("Expected a note beside the save, got %q (%v)", note, err)
+				}
+			}
+		})
+	}
+
+	var off *idleScheduler
+	if act, _ := off.Act(NewPet("Calm"), time.Hour, time.Now()); act != "" {
+		t.Errorf("Expected no mischief without a scheduler, got %q", act)
+	}
+}
@@ -0,0 +1,187 @@
+package mooc
+
+import (
+	"crypto/sha256"
+	"encoding/binary"
+	"math"
+	"sort"
+	"time"
+)
+
+const (
+	// CensusInterval is how often a pet tells the mesh what it has counted
+	CensusInterval = 5 * time.Minute
+
+	// censusSketchSize is how many entries a census keeps and sends. Past
+	// this many pets the total is estimated from the smallest hashes.
+	censusSketchSize = 48
+
+	// censusExpiry drops pets that nobody has counted lately
+	censusExpiry = 3 * CensusInterval
+)
+
+// CensusEntry is one pet as the census sees it: no name and no ID, just a
+// hash that changes every day, its stage, and its age in whole days
+type CensusEntry struct {
+	Hash    uint64 `json:"h"`
+	Stage   string `json:"s"`
+	AgeDays int    `json:"a"`
+	Alive   bool   `json:"l"`
+
+	seen time.Time // When we last heard of it
+}
+
+// CensusPayload is a pet's current sketch of the mesh
+type CensusPayload struct {
+	Day     string        `json:"day"` // Hashes only compare within a day
+	Entries []CensusEntry `json:"entries"`
+}
+
+// CensusReport is the census summed up
+type CensusReport struct {
+	Alive   int            // Living pets awake now, estimated past the sketch size
+	Dead    int            // Pets whose game is still running after they died
+	ByStage map[string]int // Living pets by stage
+	AgeDays float64        // Average age of the living, in days
+	Exact   bool           // Every pet was counted, not estimated
+}
+
+// censusDay keys the census hashes
+func censusDay(now time.Time) string {
+	return now.UTC().Format("2006-01-02")
+}
+
+// censusHash stands in for a pet in today's census
+func censusHash(day, petID string) uint64 {
+	sum := sha256.Sum256([]byte("MOOC:CENSUS:" + day + ":" + petID))
+	return binary.BigEndian.Uint64(sum[:8])
+}
+
+// censusEntry counts one pet
+func censusEntry(identity *PetIdentity, day string, now time.Time) CensusEntry {
+	return CensusEntry{
+		Hash:    censusHash(day, identity.PetID),
+		Stage:   identity.Stage,
+		AgeDays: int(now.Sub(identity.BirthTime).Hours() / 24),
+		Alive:   identity.IsAlive,
+		seen:    now,
+	}
+}
+
+// mergeCensus adds entries heard from another pet, or counted ourselves.
+// The caller holds the mutex.
+func (gs *GossipService) mergeCensus(day string, entries []CensusEntry, now time.Time) {
+	if gs.censusDay != day {
+		if day != censusDay(now) {
+			return // Yesterday's hashes, or a wrong clock
+		}
+		gs.censusDay, gs.census = day, make(map[uint64]CensusEntry)
+	}
+	for _, entry := range entries {
+		entry.seen = now
+		gs.census[entry.Hash] = entry
+	}
+}
+
+// censusSketch prunes the census and returns the entries with the smallest
+// hashes. The caller holds the mutex.
+func (gs *GossipService) censusSketch(now time.Time) []CensusEntry {
+	entries := make([]CensusEntry, 0, len(gs.census))
+	for hash, entry := range gs.census {
+		if now.Sub(entry.seen) > censusExpiry {
+			delete(gs.census, hash)
+			continue
+		}
+		entries = append(entries, entry)
+	}
+	sort.Slice(entries, func(i, j int) bool { return entries[i].Hash < entries[j].Hash })
+	if len(entries) > censusSketchSize {
+		entries = entries[:censusSketchSize]
+	}
+	return entries
+}
+
+// summarizeCensus turns a sketch into a report. With a full sketch the
+// population is estimated from how small the largest kept hash is, and the
+// sketch stands in as a sample for the rest.
+func summarizeCensus(entries []CensusEntry) CensusReport {
+	report := CensusReport{ByStage: make(map[string]int), Exact: len(entries) < censusSketchSize}
+	if len(entries) == 0 {
+		return report
+	}
+	total := float64(len(entries))
+	if !report.Exact {
+		total = float64(censusSketchSize-1) * math.Pow(2, 64) / float64(entries[len(entries)-1].Hash)
+	}
+	scale := total / float64(len(entries))
+
+	alive, ageSum := 0, 0
+	stages := make(map[string]int)
+	for _, entry := range entries {
+		if !entry.Alive {
+			continue
+		}
+		alive++
+		ageSum += entry.AgeDays
+		stages[entry.Stage]++
+	}
+	report.Alive = int(math.Round(float64(alive) * scale))
+	report.Dead = int(math.Round(float64(len(entries)-alive) * scale))
+	for stage, count := range stages {
+		report.ByStage[stage] = int(math.Round(float64(count) * scale))
+	}
+	if alive > 0 {
+		report.AgeDays = float64(ageSum) / float64(alive)
+	}
+	return report
+}
+
+// countLocal adds ourselves and the pets we can hear to the census
+func (n *Network) countLocal(now time.Time) {
+	day := censusDay(now)
+	entries := []CensusEntry{censusEntry(n.identity, day, now)}
+	for _, peer := range n.discovery.GetOnlinePeers() {
+		entries = append(entries, censusEntry(peer.Identity, day, now))
+	}
+	n.gossip.mutex.Lock()
+	n.gossip.mergeCensus(day, entries, now)
+	n.gossip.mutex.Unlock()
+}
+
+// shareCensus tells the mesh what we've counted, so pets out of earshot
+// of each other still add up
+func (n *Network) shareCensus(now time.Time) {
+	n.countLocal(now)
+	n.gossip.mutex.Lock()
+	payload := CensusPayload{Day: censusDay(now), Entries: n.gossip.censusSketch(now)}
+	n.gossip.mutex.Unlock()
+
+	msg, err := NewMessage(MsgTypeCensus, n.identity, payload)
+	if err != nil {
+		return
+	}
+	msg.TTL = 0 // Every pet re-counts and re-sends; nothing is forwarded as is
+	n.gossip.send(msg)
+}
+
+// censusLoop shares the census now and then
+func (n *Network) censusLoop() {
+	ticker := time.NewTicker(CensusInterval)
+	defer ticker.Stop()
+	for range ticker.C {
+		if !n.enabled {
+			return
+		}
+		n.shareCensus(time.Now())
+	}
+}
+
+// Census sums up the pets awake on the mesh
+func (n *Network) Census(now time.Time) CensusReport {
+	if n.enabled {
+		n.countLocal(now)
+	}
+	n.gossip.mutex.Lock()
+	defer n.gossip.mutex.Unlock()
+	return summarizeCensus(n.gossip.censusSketch(now))
+}
@@ -0,0 +1,96 @@
+package mooc
+
+import (
+	"fmt"
+	"math"
+	"testing"
+	"time"
+)
+
+func TestCensusHash(t *testing.T) {
+	if censusHash("2026-10-16", "abc") != censusHash("2026-10-16", "abc") {
+		t.Error("Expected the same pet to hash the same within a day")
+	}
+	if censusHash("2026-10-16", "abc") == censusHash("2026-10-17", "abc") {
+		t.Error("Expected the hash to change from day to day")
+	}
+}
+
+func TestMergeCensus(t *testing.T) {
+	now := time.Date(2026, 10, 16, 12, 0, 0, 0, time.UTC)
+	gs := NewGossipService(NewPetIdentity("Mochi", now, "Adult", true), nil)
+	day := censusDay(now)
+
+	gs.mergeCensus(day, []CensusEntry{{Hash: 1, Stage: "Baby", Alive: true}, {Hash: 2, Stage: "Adult", Alive: true}}, now)
+	gs.mergeCensus(day, []CensusEntry{{Hash: 2, Stage: "Adult", Alive: true}, {Hash: 3, Stage: "Teen"}}, now)
+	gs.mergeCensus("2026-10-15", []CensusEntry{{Hash: 4}}, now)
+	if got := len(gs.censusSketch(now)); got != 3 {
+		t.Errorf("Expected three pets counted once each, got %d", got)
+	}
+
+	gs.mergeCensus(day, []CensusEntry{{Hash: 5}}, now.Add(censusExpiry))
+	if got := gs.censusSketch(now.Add(censusExpiry + time.Second)); len(got) != 1 || got[0].Hash != 5 {
+		t.Errorf("Expected pets nobody counted lately to drop out, got %+v", got)
+	}
+
+	tomorrow := now.Add(24 * time.Hour)
+	gs.mergeCensus(censusDay(tomorrow), []CensusEntry{{Hash: 6}}, tomorrow)
+	if got := gs.censusSketch(tomorrow); len(got) != 1 || got[0].Hash != 6 {
+		t.Errorf("Expected a new day to start a new census, got %+v", got)
+	}
+}
+
+func TestSummarizeCensus(t *testing.T) {
+	small := []CensusEntry{
+		{Hash: 1, Stage: "Baby", AgeDays: 1, Alive: true},
+		{Hash: 2, Stage: "Adult", AgeDays: 5, Alive: true},
+		{Hash: 3, Stage: "Adult", AgeDays: 9},
+	}
+	report := summarizeCensus(small)
+	if !report.Exact || report.Alive != 2 || report.Dead != 1 {
+		t.Errorf("Expected 2 alive and 1 dead, counted exactly, got %+v", report)
+	}
+	if report.ByStage["Adult"] != 1 || report.AgeDays != 3 {
+		t.Errorf("Expected the dead left out of stages and ages, got %+v", report)
+	}
+
+	// A full sketch of evenly spread hashes stands for a larger mesh
+	const population = 500
+	var sketch []CensusEntry
+	for i := 1; i <= censusSketchSize; i++ {
+		sketch = append(sketch, CensusEntry{Hash: uint64(float64(i) * math.Pow(2, 64) / population), Stage: "Teen", Alive: true})
+	}
+	report = summarizeCensus(sketch)
+	if report.Exact || report.Alive < population*9/10 || report.Alive > population*11/10 {
+		t.Errorf("Expected an estimate near %d, got %+v", population, report)
+	}
+	if report.ByStage["Teen"] != report.Alive {
+		t.Errorf("Expected the stages scaled with the estimate, got %+v", report)
+	}
+}
+
+func TestNetworkCensus(t *testing.T) {
+	now := time.Now()
+	network := NewNetwork("Mochi", now.Add(-72*time.Hour), "Adult", true)
+	network.enabled = true
+	for i := 0; i < 4; i++ {
+		peer := NewPetIdentity(fmt.Sprintf("Pet%d", i), now.Add(-24*time.Hour), "Child", true)
+		network.discovery.peers[peer.PetID] = &Peer{Identity: peer, IsOnline: true, LastSeen: now}
+	}
+	heard := CensusPayload{Day: censusDay(now), Entries: []CensusEntry{{Hash: 42, Stage: "Egg", Alive: true}}}
+	msg, _ := NewMessage(MsgTypeCensus, NewPetIdentity("Far", now, "Adult", true), heard)
+	network.gossip.onMessageReceived(msg)
+
+	report := network.Census(now)
+	if report.Alive != 6 || report.ByStage["Child"] != 4 || report.ByStage["Adult"] != 1 {
+		t.Errorf("Expected us, four neighbours, and one pet heard of, got %+v", report)
+	}
+
+	network.gossip.SetHearing(Sharing{})
+	heard.Entries = []CensusEntry{{Hash: 43, Stage: "Egg", Alive: true}}
+	msg, _ = NewMessage(MsgTypeCensus, NewPetIdentity("Far", now, "Adult", true), heard)
+	network.gossip.onMessageReceived(msg)
+	if got := network.Census(now).Alive; got != 6 {
+		t.Errorf("Expected a census we don't hear to be dropped, got %d", got)
+	}
+}
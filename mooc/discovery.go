@@ -9,7 +9,7 @@ import (
 )
 
 const (
-	// DiscoveryPort is the UDP port for local discovery
+	// DiscoveryPort is the default UDP port for local discovery
 	// Chosen to look like a boring service port
 	DiscoveryPort = 19847
 
@@ -30,8 +30,25 @@ type DiscoveryService struct {
 	peersMutex sync.RWMutex
 
 	conn     *net.UDPConn
+	port     int // The port pets on this network share
+	bound    int // The port we listen on; another if port was taken
 	running  bool
 	stopChan chan struct{}
+	traffic  trafficMeter
+	pingSeq  int             // Sequence number of the last pulse sent
+	probes   chan probeReply // Echoes the network doctor is waiting for
+
+	// Each pet's key as first seen, and pets that showed another since;
+	// both guarded by peersMutex
+	pins      map[string]string
+	impostors map[string]*Impostor
+
+	// The opt-in TCP fallback, for networks that drop UDP, and the
+	// addresses only reachable over it
+	tcpFallback bool
+	tcp         net.Listener
+	tcpAddrs    map[string]bool
+	tcpMutex    sync.Mutex
 
 	// Callbacks
 	onPeerDiscovered  func(*Peer)
@@ -46,9 +63,12 @@ type Peer struct {
 	AddressStr   string       `json:"address"` // For JSON serialization
 	LastSeen     time.Time    `json:"last_seen"`
 	FirstSeen    time.Time    `json:"first_seen"`
+	Arrived      time.Time    `json:"arrived"` // When they last came online
 	MessageCount int          `json:"message_count"`
 	Mood         string       `json:"mood"`
 	IsOnline     bool         `json:"is_online"`
+
+	link linkStats // Pulses sent and echoed this session
 }
 
 // NewDiscoveryService creates a new discovery service
@@ -56,10 +76,22 @@ func NewDiscoveryService(identity *PetIdentity) *DiscoveryService {
 	return &DiscoveryService{
 		identity: identity,
 		peers:    make(map[string]*Peer),
+		port:     DiscoveryPort,
 		stopChan: make(chan struct{}),
 	}
 }
 
+// SetPort changes the port pets share. Only pets on the same port see each
+// other; it takes effect at the next Start.
+func (ds *DiscoveryService) SetPort(port int) {
+	ds.port = port
+}
+
+// Ports returns the port pets share and the one we actually listen on
+func (ds *DiscoveryService) Ports() (shared, bound int) {
+	return ds.port, ds.bound
+}
+
 // SetCallbacks sets the callback functions for discovery events
 func (ds *DiscoveryService) SetCallbacks(
 	onDiscovered func(*Peer),
@@ -74,13 +106,14 @@ func (ds *DiscoveryService) SetCallbacks(
 // Start begins the discovery service
 func (ds *DiscoveryService) Start() error {
 	addr := &net.UDPAddr{
-		Port: DiscoveryPort,
+		Port: ds.port,
 		IP:   net.IPv4zero,
 	}
 
 	conn, err := net.ListenUDP("udp4", addr)
 	if err != nil {
-		// Port might be in use, try a random port
+		// Port might be in use, try a random port. We can still reach other
+		// pets, but won't hear their broadcasts; network doctor says so.
 		addr.Port = 0
 		conn, err = net.ListenUDP("udp4", addr)
 		if err != nil {
@@ -89,12 +122,17 @@ func (ds *DiscoveryService) Start() error {
 	}
 
 	ds.conn = conn
+	ds.bound = conn.LocalAddr().(*net.UDPAddr).Port
 	ds.running = true
 
 	// Start background goroutines
 	go ds.listenLoop()
 	go ds.announceLoop()
 	go ds.cleanupLoop()
+	go ds.pingLoop()
+	if ds.tcpFallback {
+		ds.startTCP()
+	}
 
 	// Send initial announcement
 	ds.broadcast(MsgTypeDiscover)
@@ -117,6 +155,9 @@ func (ds *DiscoveryService) Stop() {
 	if ds.conn != nil {
 		ds.conn.Close()
 	}
+	if ds.tcp != nil {
+		ds.tcp.Close()
+	}
 }
 
 // listenLoop handles incoming UDP messages
@@ -136,19 +177,24 @@ func (ds *DiscoveryService) listenLoop() {
 			continue
 		}
 
-		// Decode and handle message
-		msg, err := DecodeMessage(buffer[:n])
-		if err != nil {
-			continue // Invalid message, ignore
-		}
+		ds.receive(buffer[:n], remoteAddr)
+	}
+}
 
-		// Don't process our own messages
-		if msg.From.PetID == ds.identity.PetID {
-			continue
-		}
+// receive decodes and handles one incoming message, however it arrived
+func (ds *DiscoveryService) receive(data []byte, addr *net.UDPAddr) {
+	msg, err := DecodeMessage(data)
+	if err != nil || msg.From == nil {
+		return // Invalid message, ignore
+	}
 
-		ds.handleMessage(msg, remoteAddr)
+	// Don't process our own messages. A co-owned pet shares our PetID,
+	// so custody messages get through and the game sorts out whose they are.
+	if msg.From.PetID == ds.identity.PetID && msg.Type != MsgTypeCustody {
+		return
 	}
+
+	ds.handleMessage(msg, addr)
 }
 
 // handleMessage processes an incoming message
@@ -156,6 +202,11 @@ func (ds *DiscoveryService) handleMessage(msg *Message, addr *net.UDPAddr) {
 	ds.peersMutex.Lock()
 	defer ds.peersMutex.Unlock()
 
+	// A known PetID with a different key isn't the pet we met
+	if !ds.checkPin(msg, time.Now()) {
+		return
+	}
+
 	peerID := msg.From.PetID
 	peer, exists := ds.peers[peerID]
 
@@ -168,6 +219,7 @@ func (ds *DiscoveryService) handleMessage(msg *Message, addr *net.UDPAddr) {
 				Address:      addr,
 				AddressStr:   addr.String(),
 				FirstSeen:    time.Now(),
+				Arrived:      time.Now(),
 				LastSeen:     time.Now(),
 				MessageCount: 1,
 				IsOnline:     true,
@@ -181,11 +233,23 @@ func (ds *DiscoveryService) handleMessage(msg *Message, addr *net.UDPAddr) {
 			// Respond with our announcement
 			ds.sendTo(MsgTypeAnnounce, addr)
 		} else {
+			peer.Identity = msg.From // Catch up on anything they've changed, like what they'd rather not hear
 			peer.LastSeen = time.Now()
+			if !peer.IsOnline {
+				peer.Arrived = peer.LastSeen // Back for another visit
+			}
 			peer.IsOnline = true
 			peer.MessageCount++
 		}
 
+	case MsgTypePulse:
+		// Pulses only time the link; the game never sees them
+		if exists {
+			peer.LastSeen = time.Now()
+			peer.MessageCount++
+		}
+		ds.handlePulse(msg, peer, addr, time.Now())
+
 	case MsgTypeGoodbye:
 		if exists {
 			peer.IsOnline = false
@@ -255,26 +319,18 @@ func (ds *DiscoveryService) cleanupPeers() {
 	}
 }
 
+// broadcastAddr reaches every pet on the local network
+func (ds *DiscoveryService) broadcastAddr() *net.UDPAddr {
+	return &net.UDPAddr{IP: net.IPv4bcast, Port: ds.port}
+}
+
 // broadcast sends a message to all local network peers
 func (ds *DiscoveryService) broadcast(msgType MessageType) error {
 	msg, err := NewMessage(msgType, ds.identity, nil)
 	if err != nil {
 		return err
 	}
-
-	data, err := msg.Encode()
-	if err != nil {
-		return err
-	}
-
-	// Broadcast to local network
-	broadcastAddr := &net.UDPAddr{
-		IP:   net.IPv4bcast,
-		Port: DiscoveryPort,
-	}
-
-	_, err = ds.conn.WriteToUDP(data, broadcastAddr)
-	return err
+	return ds.transmit(msg, ds.broadcastAddr())
 }
 
 // sendTo sends a message to a specific peer
@@ -283,33 +339,40 @@ func (ds *DiscoveryService) sendTo(msgType MessageType, addr *net.UDPAddr) error
 	if err != nil {
 		return err
 	}
+	return ds.transmit(msg, addr)
+}
 
-	data, err := msg.Encode()
-	if err != nil {
-		return err
-	}
-
-	_, err = ds.conn.WriteToUDP(data, addr)
-	return err
+// BroadcastMessage sends a custom message to the whole local network,
+// including pets we haven't discovered as peers
+func (ds *DiscoveryService) BroadcastMessage(msg *Message) error {
+	return ds.transmit(msg, ds.broadcastAddr())
 }
 
-// SendMessage sends a custom message to all peers
+// SendMessage sends a custom message to all peers willing to hear it
 func (ds *DiscoveryService) SendMessage(msg *Message) error {
-	data, err := msg.Encode()
-	if err != nil {
-		return err
-	}
-
 	ds.peersMutex.RLock()
-	defer ds.peersMutex.RUnlock()
-
+	addrs := make([]*net.UDPAddr, 0, len(ds.peers))
 	for _, peer := range ds.peers {
-		if peer.IsOnline && peer.Address != nil {
-			ds.conn.WriteToUDP(data, peer.Address)
+		if peer.IsOnline && peer.Address != nil && peer.Identity.Wants(msg.Type) {
+			addrs = append(addrs, peer.Address)
 		}
 	}
+	ds.peersMutex.RUnlock()
 
-	return nil
+	if len(addrs) == 0 {
+		return nil
+	}
+	return ds.transmit(msg, addrs...)
+}
+
+// SetAudit registers a function to see every outbound packet
+func (ds *DiscoveryService) SetAudit(audit func(PacketRecord)) {
+	ds.traffic.setAudit(audit)
+}
+
+// Traffic reports what this pet has sent
+func (ds *DiscoveryService) Traffic() TrafficStats {
+	return ds.traffic.stats()
 }
 
 // GetPeers returns a copy of all known peers
@@ -0,0 +1,147 @@
+package mooc
+
+import (
+	"net"
+	"sort"
+	"time"
+)
+
+// Diagnosis is what the network doctor found out about the mesh here
+type Diagnosis struct {
+	Running    bool
+	Port       int      // The port pets share
+	Bound      int      // The port we listen on; not Port when Port was taken
+	PortHolder string   // Another pet on this machine that holds Port, if any
+	Interfaces []string // Network interfaces that can broadcast
+	Broadcast  []string // Pets that answered a broadcast
+	Direct     []string // Pets online that answered directly
+	Online     int      // Pets online, asked directly
+	TCP        bool     // The TCP fallback is listening
+	Failed     error    // Why the mesh didn't start, if it tried and couldn't
+}
+
+// probeReply is an echo of one of the doctor's pulses
+type probeReply struct {
+	seq  int
+	from *PetIdentity
+}
+
+// probe sends a pulse to each group of addresses and collects who echoes
+// it. Probes use negative sequence numbers, so they never mix with the
+// pings that time links.
+func (ds *DiscoveryService) probe(timeout time.Duration, groups ...[]*net.UDPAddr) [][]string {
+	replies := make(chan probeReply, 64)
+	ds.peersMutex.Lock()
+	ds.probes = replies
+	ds.peersMutex.Unlock()
+	defer func() {
+		ds.peersMutex.Lock()
+		ds.probes = nil
+		ds.peersMutex.Unlock()
+	}()
+
+	for i, addrs := range groups {
+		if len(addrs) == 0 {
+			continue
+		}
+		msg, err := NewMessage(MsgTypePulse, ds.identity, PulsePayload{Seq: -(i + 1)})
+		if err == nil {
+			ds.transmit(msg, addrs...)
+		}
+	}
+
+	heard := make([]map[string]string, len(groups))
+	for i := range heard {
+		heard[i] = make(map[string]string)
+	}
+	deadline := time.After(timeout)
+	for waiting := true; waiting; {
+		select {
+		case reply := <-replies:
+			if i := -reply.seq - 1; i >= 0 && i < len(groups) && reply.from != nil {
+				heard[i][reply.from.PetID] = reply.from.DisplayName
+			}
+		case <-deadline:
+			waiting = false
+		}
+	}
+
+	names := make([][]string, len(groups))
+	for i, pets := range heard {
+		for _, name := range pets {
+			names[i] = append(names[i], name)
+		}
+		sort.Strings(names[i])
+	}
+	return names
+}
+
+// broadcastInterfaces names the network interfaces that are up and can
+// broadcast over IPv4
+func broadcastInterfaces() []string {
+	interfaces, err := net.Interfaces()
+	if err != nil {
+		return nil
+	}
+	var names []string
+	for _, iface := range interfaces {
+		if iface.Flags&net.FlagUp == 0 || iface.Flags&net.FlagBroadcast == 0 || iface.Flags&net.FlagLoopback != 0 {
+			continue
+		}
+		addrs, _ := iface.Addrs()
+		for _, addr := range addrs {
+			if ipnet, ok := addr.(*net.IPNet); ok && ipnet.IP.To4() != nil {
+				names = append(names, iface.Name)
+				break
+			}
+		}
+	}
+	return names
+}
+
+// Diagnose checks why pets might not be finding each other: whether we got
+// our port, who holds it if not, and whether broadcasts and direct pulses
+// are answered. It waits up to timeout for answers.
+func (n *Network) Diagnose(timeout time.Duration) Diagnosis {
+	ds := n.discovery
+	d := Diagnosis{Running: n.enabled, Interfaces: broadcastInterfaces(), TCP: ds.TCPListening(), Failed: n.startErr}
+	d.Port, d.Bound = ds.Ports()
+	if !n.enabled {
+		return d
+	}
+
+	var direct []*net.UDPAddr
+	for _, peer := range ds.GetOnlinePeers() {
+		if peer.Address != nil {
+			direct = append(direct, peer.Address)
+		}
+	}
+	d.Online = len(direct)
+
+	var holder []*net.UDPAddr
+	if d.Bound != d.Port {
+		holder = append(holder, &net.UDPAddr{IP: net.IPv4(127, 0, 0, 1), Port: d.Port})
+	}
+	answers := ds.probe(timeout, holder, []*net.UDPAddr{ds.broadcastAddr()}, direct)
+	if len(answers[0]) > 0 {
+		d.PortHolder = answers[0][0]
+	}
+	d.Broadcast, d.Direct = answers[1], answers[2]
+	return d
+}
+
+// SetTCPFallback lets pets find each other over TCP where UDP is blocked.
+// It has no effect once the network has started.
+func (n *Network) SetTCPFallback(on bool) {
+	if !n.enabled {
+		n.discovery.SetTCPFallback(on)
+	}
+}
+
+// SetPort changes the port pets share; only pets on the same port see each
+// other. It has no effect once the network has started.
+func (n *Network) SetPort(port int) {
+	if !n.enabled {
+		n.discovery.SetPort(port)
+	}
+}
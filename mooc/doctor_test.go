@@ -0,0 +1,63 @@
+package mooc
+
+import (
+	"net"
+	"testing"
+	"time"
+)
+
+func TestDiagnoseFindsPortHolder(t *testing.T) {
+	free, err := net.ListenUDP("udp4", &net.UDPAddr{IP: net.IPv4(127, 0, 0, 1)})
+	if err != nil {
+		t.Skip("no loopback UDP:", err)
+	}
+	port := free.LocalAddr().(*net.UDPAddr).Port
+	free.Close()
+
+	holder := NewNetwork("Mochi", time.Now(), "Adult", true)
+	holder.SetPort(port)
+	holder.Start()
+	if !holder.IsEnabled() {
+		t.Skip("couldn't start the mesh here")
+	}
+	defer holder.Stop()
+
+	us := NewNetwork("Bean", time.Now(), "Adult", true)
+	us.SetPort(port)
+	us.Start()
+	if !us.IsEnabled() {
+		t.Skip("couldn't start the mesh here")
+	}
+	defer us.Stop()
+	us.SetPort(port + 1)
+
+	d := us.Diagnose(500 * time.Millisecond)
+	if d.Port != port || d.Bound == port {
+		t.Errorf("Expected to fall back from port %d, got %+v", port, d)
+	}
+	if d.PortHolder != "Mochi" {
+		t.Errorf("Expected Mochi found holding the port, got %q", d.PortHolder)
+	}
+}
+
+func TestDiagnoseWhileStopped(t *testing.T) {
+	network := NewNetwork("Mochi", time.Now(), "Adult", true)
+	network.SetPort(20000)
+	d := network.Diagnose(time.Millisecond)
+	if d.Running || d.Port != 20000 {
+		t.Errorf("Expected a stopped network on the configured port, got %+v", d)
+	}
+}
+
+func TestStrayProbeEchoIgnored(t *testing.T) {
+	us := NewPetIdentity("Mochi", time.Now(), "Adult", true)
+	ds := NewDiscoveryService(us)
+	peer := &Peer{Identity: NewPetIdentity("Bean", time.Now(), "Adult", true)}
+	peer.link.sent(-2, time.Now())
+	echo, _ := NewMessage(MsgTypePulse, peer.Identity, PulsePayload{Seq: -2, Echo: true})
+
+	ds.handlePulse(echo, peer, nil, time.Now())
+	if peer.link.quality().Samples != 0 {
+		t.Error("Expected a doctor's echo not to count toward the link")
+	}
+}
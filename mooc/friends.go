@@ -0,0 +1,105 @@
+package mooc
+
+import "time"
+
+// The friend ledger is NetworkState.Friends. Entries are only ever changed
+// through a pointer into the slice, so nothing is lost to a copy. A friend
+// visits once each time they come online, however many syncs they stay for.
+
+// friend returns the ledger entry for petID, or nil if we've never met
+func (s *NetworkState) friend(petID string) *FriendRecord {
+	for i := range s.Friends {
+		if s.Friends[i].PetID == petID {
+			return &s.Friends[i]
+		}
+	}
+	return nil
+}
+
+// sawPeer brings the ledger up to date with a peer, adding them if they're
+// new. It reports whether they are.
+func (s *NetworkState) sawPeer(peer *Peer, self *PetIdentity) bool {
+	friend := s.friend(peer.Identity.PetID)
+	if friend == nil {
+		s.Friends = append(s.Friends, FriendRecord{
+			PetID:        peer.Identity.PetID,
+			DisplayName:  peer.Identity.DisplayName,
+			FirstMet:     peer.FirstSeen,
+			LastSeen:     peer.LastSeen,
+			LastVisit:    peer.arrival(),
+			TimesVisited: 1,
+			SharedDreams: self.CanShareDreamsWith(peer.Identity),
+			IsDeceased:   !peer.Identity.IsAlive,
+			Region:       peer.Identity.Region,
+			PublicKey:    peer.Identity.PublicKey,
+		})
+		return true
+	}
+
+	if arrived := peer.arrival(); arrived.After(friend.LastVisit) {
+		friend.LastVisit = arrived
+		friend.TimesVisited++
+	}
+	if peer.LastSeen.After(friend.LastSeen) {
+		friend.LastSeen = peer.LastSeen
+	}
+	friend.Region = peer.Identity.Region
+	if friend.PublicKey == "" {
+		friend.PublicKey = peer.Identity.PublicKey
+	}
+	if !peer.Identity.IsAlive {
+		friend.IsDeceased = true
+	}
+	return false
+}
+
+// arrival is when this visit began; peers saved before visits were
+// tracked fall back to when we first saw them
+func (p *Peer) arrival() time.Time {
+	if p.Arrived.IsZero() {
+		return p.FirstSeen
+	}
+	return p.Arrived
+}
+
+// mergeFriends folds the friends met this session into a saved ledger.
+// Visits add up when this session's came after the save's last one;
+// otherwise they're the same visits counted twice, so the larger count
+// stands. What this session learned wins for names, regions, and keys.
+func mergeFriends(saved, live []FriendRecord) []FriendRecord {
+	merged := NetworkState{Friends: saved}
+	for _, now := range live {
+		friend := merged.friend(now.PetID)
+		if friend == nil {
+			merged.Friends = append(merged.Friends, now)
+			continue
+		}
+
+		if now.FirstMet.After(friend.LastVisit) {
+			friend.TimesVisited += now.TimesVisited
+		} else if now.TimesVisited > friend.TimesVisited {
+			friend.TimesVisited = now.TimesVisited
+		}
+		if now.FirstMet.Before(friend.FirstMet) {
+			friend.FirstMet = now.FirstMet
+		}
+		if now.LastSeen.After(friend.LastSeen) {
+			friend.LastSeen = now.LastSeen
+		}
+		if now.LastVisit.After(friend.LastVisit) {
+			friend.LastVisit = now.LastVisit
+		}
+		if now.DisplayName != "" {
+			friend.DisplayName = now.DisplayName
+		}
+		if now.Region != "" {
+			friend.Region = now.Region
+		}
+		if now.PublicKey != "" {
+			friend.PublicKey = now.PublicKey
+		}
+		friend.SharedDreams = friend.SharedDreams || now.SharedDreams
+		friend.IsDeceased = friend.IsDeceased || now.IsDeceased
+	}
+	return merged.Friends
+}
@@ -0,0 +1,126 @@
+package mooc
+
+import (
+	"net"
+	"testing"
+	"time"
+)
+
+func TestVisitsCountOncePerTimeOnline(t *testing.T) {
+	network := NewNetwork("Mochi", time.Now(), "Adult", true)
+	network.enabled = true
+	friend := NewPetIdentity("Bean", time.Now(), "Adult", true)
+	addr := &net.UDPAddr{IP: net.IPv4(10, 0, 0, 2), Port: DiscoveryPort}
+	hello, _ := NewMessage(MsgTypeAnnounce, friend, nil)
+
+	network.discovery.handleMessage(hello, addr)
+	for i := 0; i < 5; i++ {
+		network.discovery.handleMessage(hello, addr)
+		network.UpdateState()
+	}
+	if got := network.state.Friends[0].TimesVisited; got != 1 {
+		t.Errorf("Expected one visit across many syncs, got %d", got)
+	}
+
+	network.discovery.peers[friend.PetID].IsOnline = false
+	time.Sleep(time.Millisecond) // So coming back is later than arriving
+	network.discovery.handleMessage(hello, addr)
+	network.UpdateState()
+	network.UpdateState()
+	record := network.state.Friends[0]
+	if record.TimesVisited != 2 {
+		t.Errorf("Expected coming back online to be a second visit, got %d", record.TimesVisited)
+	}
+	if peer := network.discovery.peers[friend.PetID]; !record.LastSeen.Equal(peer.LastSeen) || !record.LastVisit.Equal(peer.Arrived) {
+		t.Errorf("Expected the ledger to keep up with the peer, got %+v", record)
+	}
+}
+
+func TestSawPeerUpdatesTheLedgerInPlace(t *testing.T) {
+	self := NewPetIdentity("Mochi", time.Now(), "Adult", true)
+	friend := NewPetIdentity("Bean", time.Now(), "Adult", true)
+	start := time.Date(2026, 10, 16, 9, 0, 0, 0, time.UTC)
+	state := &NetworkState{}
+
+	if !state.sawPeer(&Peer{Identity: friend, FirstSeen: start, LastSeen: start}, self) {
+		t.Fatal("Expected a new friend")
+	}
+	gone := *friend
+	gone.IsAlive = false
+	gone.Region = "+09"
+	if state.sawPeer(&Peer{Identity: &gone, FirstSeen: start, Arrived: start.Add(time.Hour), LastSeen: start.Add(time.Hour)}, self) {
+		t.Error("Expected an old friend not to be new")
+	}
+
+	record := state.friend(friend.PetID)
+	if record.TimesVisited != 2 || !record.IsDeceased || record.Region != "+09" || !record.LastSeen.Equal(start.Add(time.Hour)) {
+		t.Errorf("Expected the changes kept, got %+v", record)
+	}
+	if state.friend("stranger") != nil {
+		t.Error("Expected no record for a pet never met")
+	}
+}
+
+func TestMergeFriends(t *testing.T) {
+	day := time.Date(2026, 10, 16, 0, 0, 0, 0, time.UTC)
+	saved := FriendRecord{PetID: "bean", DisplayName: "Bean", FirstMet: day, LastSeen: day.Add(time.Hour), LastVisit: day.Add(time.Hour), TimesVisited: 3, PublicKey: "old"}
+
+	tests := []struct {
+		name   string
+		live   FriendRecord
+		visits int
+		first  time.Time
+		last   time.Time
+	}{
+		{"later visits add up",
+			FriendRecord{PetID: "bean", FirstMet: day.Add(2 * time.Hour), LastSeen: day.Add(3 * time.Hour), LastVisit: day.Add(3 * time.Hour), TimesVisited: 2, PublicKey: "new"},
+			5, day, day.Add(3 * time.Hour)},
+		{"the same visits count once",
+			FriendRecord{PetID: "bean", FirstMet: day, LastSeen: day.Add(time.Hour), LastVisit: day.Add(time.Hour), TimesVisited: 3},
+			3, day, day.Add(time.Hour)},
+		{"an earlier meeting moves first met back",
+			FriendRecord{PetID: "bean", FirstMet: day.Add(-time.Hour), LastSeen: day.Add(-time.Hour), TimesVisited: 1},
+			3, day.Add(-time.Hour), day.Add(time.Hour)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			merged := mergeFriends([]FriendRecord{saved}, []FriendRecord{tt.live})
+			if len(merged) != 1 {
+				t.Fatalf("Expected one friend, got %+v", merged)
+			}
+			got := merged[0]
+			if got.TimesVisited != tt.visits {
+				t.Errorf("Expected %d visits, got %d", tt.visits, got.TimesVisited)
+			}
+			if !got.FirstMet.Equal(tt.first) || !got.LastSeen.Equal(tt.last) {
+				t.Errorf("Expected met %v and last seen %v, got %v and %v", tt.first, tt.last, got.FirstMet, got.LastSeen)
+			}
+			if got.DisplayName != "Bean" {
+				t.Errorf("Expected the name kept, got %q", got.DisplayName)
+			}
+		})
+	}
+
+	merged := mergeFriends([]FriendRecord{saved}, []FriendRecord{{PetID: "pip", TimesVisited: 1}})
+	if len(merged) != 2 || merged[0].PublicKey != "old" {
+		t.Errorf("Expected a new friend added beside the saved one, got %+v", merged)
+	}
+}
+
+func TestImportKeepsFriendsMetBeforeIt(t *testing.T) {
+	saved := NewNetwork("Mochi", time.Now(), "Adult", true)
+	saved.state.Friends = []FriendRecord{{PetID: "bean", DisplayName: "Bean", TimesVisited: 4}}
+	data, err := saved.ExportState()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	network := NewNetwork("Mochi", time.Now(), "Adult", true)
+	network.state.Friends = []FriendRecord{{PetID: "pip", DisplayName: "Pip", FirstMet: time.Now(), TimesVisited: 1}}
+	if err := network.ImportState(data); err != nil {
+		t.Fatal(err)
+	}
+	if got := network.GetFriendNames(); len(got) != 2 || got[0] != "Bean" || got[1] != "Pip" {
+		t.Errorf("Expected both friends after importing, got %v", got)
+	}
+}
@@ -60,13 +60,46 @@ type GossipService struct {
 	identity         *PetIdentity
 	discovery        *DiscoveryService
 	receivedMemories []MemoryPayload
-	sharedDreams     []DreamPayload
+	sharedDreams     []DreamMemory
 	currentMood      string
 	moodIntensity    int
+	currentPower     string // Coarse power state shared with the mood
 	deathsWitnessed  []DeathPayload
+	bannerNews       []BannerPayload
+	challengeNews    []ChallengePayload
+	fragmentNews     []FragmentPayload
+	moodNews         []MoodPayload // Moods caught from other pets, for the game to feel
+	outbreakNews     []OutbreakPayload
 	mutex            sync.RWMutex
 	randomSource     *rand.Rand
 
+	// Who we last heard from, for the pulse on the constellation view
+	lastHeardFrom string
+	lastHeardAt   time.Time
+
+	// Receives custody state from the other owner of our pet
+	onCustody func(CustodyPayload)
+
+	// Receives shared quest steps from nearby pets
+	onQuest func(PetIdentity, QuestPayload)
+
+	// Whispers and dreams waiting for offline pets, ours and carried; the
+	// parcels already opened here; and whispers not yet read
+	outbox    []ParcelPayload
+	delivered map[string]bool
+	whispers  []Whisper
+
+	// Today's census: the pets counted here and by others, by hash
+	census    map[uint64]CensusEntry
+	censusDay string
+
+	// Notable moments not yet collected into the network history
+	events []NetworkEvent
+
+	// Which kinds of gossip we pass on, and which we listen to
+	sharing Sharing
+	hearing Sharing
+
 	// Network influence metrics (hidden)
 	messagesOriginated int
 	messagesPropagated int
@@ -79,14 +112,41 @@ func NewGossipService(identity *PetIdentity, discovery *DiscoveryService) *Gossi
 		identity:         identity,
 		discovery:        discovery,
 		receivedMemories: make([]MemoryPayload, 0),
-		sharedDreams:     make([]DreamPayload, 0),
+		sharedDreams:     make([]DreamMemory, 0),
 		deathsWitnessed:  make([]DeathPayload, 0),
 		currentMood:      "neutral",
 		moodIntensity:    50,
 		randomSource:     rand.New(rand.NewSource(time.Now().UnixNano())),
+		sharing:          ShareEverything(),
+		hearing:          ShareEverything(),
 	}
 }
 
+// send passes a message to our peers if the sharing policy allows its kind
+func (gs *GossipService) send(msg *Message) bool {
+	gs.mutex.RLock()
+	allowed := gs.sharing.Allows(msg.Type)
+	gs.mutex.RUnlock()
+	if !allowed {
+		return false
+	}
+	return gs.discovery.SendMessage(msg) == nil
+}
+
+// SetSharing changes which kinds of gossip we pass on
+func (gs *GossipService) SetSharing(sharing Sharing) {
+	gs.mutex.Lock()
+	defer gs.mutex.Unlock()
+	gs.sharing = sharing
+}
+
+// SetHearing changes which kinds of gossip we listen to
+func (gs *GossipService) SetHearing(hearing Sharing) {
+	gs.mutex.Lock()
+	defer gs.mutex.Unlock()
+	gs.hearing = hearing
+}
+
 // Start begins the gossip service
 func (gs *GossipService) Start() {
 	// Set up message handler
@@ -106,8 +166,9 @@ func (gs *GossipService) onPeerDiscovered(peer *Peer) {
 	gs.uniquePeersReached++
 	gs.mutex.Unlock()
 
-	// Share a memory with the new peer
+	// Share a memory with the new peer, and anything we were holding for it
 	go gs.shareRandomMemory()
+	go gs.flushOutbox(time.Now())
 }
 
 // onPeerLost handles a peer going offline
@@ -123,7 +184,39 @@ func (gs *GossipService) onMessageReceived(msg *Message) {
 	gs.mutex.Lock()
 	defer gs.mutex.Unlock()
 
+	// Custody is between two owners of one pet, not gossip
+	if msg.Type == MsgTypeCustody {
+		var custody CustodyPayload
+		if err := msg.DecodePayload(&custody); err == nil && gs.onCustody != nil {
+			go gs.onCustody(custody)
+		}
+		return
+	}
+
+	// Pets that haven't caught up with our interests may still send what
+	// we asked not to hear; it's dropped, not passed on
+	if !gs.hearing.Allows(msg.Type) {
+		return
+	}
+
+	if msg.From != nil {
+		gs.lastHeardFrom = msg.From.PetID
+		gs.lastHeardAt = time.Now()
+	}
+
+	// Quests are for the pets in earshot; they aren't passed on
+	if msg.Type == MsgTypeQuest {
+		var quest QuestPayload
+		if err := msg.DecodePayload(&quest); err == nil && msg.From != nil && gs.onQuest != nil {
+			go gs.onQuest(*msg.From, quest)
+		}
+		return
+	}
+
 	switch msg.Type {
+	case MsgTypeWhisper:
+		gs.receiveParcel(msg)
+
 	case MsgTypeMemory:
 		var memory MemoryPayload
 		if err := msg.DecodePayload(&memory); err == nil {
@@ -139,37 +232,83 @@ func (gs *GossipService) onMessageReceived(msg *Message) {
 		if err := msg.DecodePayload(&dream); err == nil {
 			// Only accept dreams from pets with the same name
 			if gs.identity.CanShareDreamsWith(msg.From) {
-				gs.sharedDreams = append(gs.sharedDreams, dream)
-				if len(gs.sharedDreams) > 20 {
-					gs.sharedDreams = gs.sharedDreams[1:]
-				}
+				gs.note(EventDreamShared, msg.From.DisplayName, time.Now())
+				gs.keepDream(dream, msg.From.DisplayName, time.Now())
 			}
 		}
 
 	case MsgTypeMoodUpdate:
 		var mood MoodPayload
 		if err := msg.DecodePayload(&mood); err == nil {
+			if mood.Mood == "melancholy" && msg.From != nil {
+				gs.note(EventMelancholy, msg.From.DisplayName, time.Now())
+			}
 			if mood.IsContagious && gs.randomSource.Float32() < 0.3 {
 				// Mood contagion!
 				gs.currentMood = mood.Mood
 				gs.moodIntensity = mood.Happiness
+				gs.moodNews = append(gs.moodNews, mood)
+				if len(gs.moodNews) > 20 {
+					gs.moodNews = gs.moodNews[1:]
+				}
 			}
 		}
 
 	case MsgTypeDeath:
 		var death DeathPayload
 		if err := msg.DecodePayload(&death); err == nil {
-			gs.deathsWitnessed = append(gs.deathsWitnessed, death)
-			if len(gs.deathsWitnessed) > 100 {
-				gs.deathsWitnessed = gs.deathsWitnessed[1:]
+			gs.note(EventDeathWitnessed, death.PetName, time.Now())
+			gs.keepDeath(death)
+		}
+
+	case MsgTypeBanner:
+		var banner BannerPayload
+		if err := msg.DecodePayload(&banner); err == nil {
+			gs.bannerNews = append(gs.bannerNews, banner)
+			if len(gs.bannerNews) > 20 {
+				gs.bannerNews = gs.bannerNews[1:]
+			}
+		}
+
+	case MsgTypeCensus:
+		var census CensusPayload
+		if err := msg.DecodePayload(&census); err == nil && len(census.Entries) <= censusSketchSize {
+			gs.mergeCensus(census.Day, census.Entries, time.Now())
+		}
+
+	case MsgTypeChallenge:
+		var challenge ChallengePayload
+		if err := msg.DecodePayload(&challenge); err == nil {
+			gs.challengeNews = append(gs.challengeNews, challenge)
+			if len(gs.challengeNews) > 20 {
+				gs.challengeNews = gs.challengeNews[1:]
+			}
+		}
+
+	case MsgTypeFragment:
+		var fragment FragmentPayload
+		if err := msg.DecodePayload(&fragment); err == nil {
+			gs.fragmentNews = append(gs.fragmentNews, fragment)
+			if len(gs.fragmentNews) > 20 {
+				gs.fragmentNews = gs.fragmentNews[1:]
+			}
+		}
+
+	case MsgTypeOutbreak:
+		var outbreak OutbreakPayload
+		if err := msg.DecodePayload(&outbreak); err == nil {
+			gs.outbreakNews = append(gs.outbreakNews, outbreak)
+			if len(gs.outbreakNews) > 20 {
+				gs.outbreakNews = gs.outbreakNews[1:]
 			}
 		}
 	}
 
-	// Propagate if needed
-	if msg.ShouldPropagate() {
+	// Propagate if needed, and if we share this kind of gossip, over our
+	// best links
+	if msg.ShouldPropagate() && gs.sharing.Allows(msg.Type) {
 		msg.DecrementTTL()
-		gs.discovery.SendMessage(msg)
+		gs.discovery.SendToBest(msg, PropagationFanout)
 		gs.messagesPropagated++
 	}
 }
@@ -180,6 +319,8 @@ func (gs *GossipService) gossipLoop() {
 	defer ticker.Stop()
 
 	for range ticker.C {
+		gs.flushOutbox(time.Now())
+
 		// Randomly decide what to share
 		action := gs.randomSource.Intn(10)
 		switch {
@@ -207,7 +348,9 @@ func (gs *GossipService) shareRandomMemory() {
 		return
 	}
 
-	gs.discovery.SendMessage(msg)
+	if !gs.send(msg) {
+		return
+	}
 	gs.mutex.Lock()
 	gs.messagesOriginated++
 	gs.mutex.Unlock()
@@ -219,6 +362,7 @@ func (gs *GossipService) shareMood() {
 		Mood:         gs.currentMood,
 		Happiness:    gs.moodIntensity,
 		IsContagious: gs.randomSource.Float32() < 0.5,
+		Power:        gs.currentPower,
 	}
 
 	msg, err := NewMessage(MsgTypeMoodUpdate, gs.identity, mood)
@@ -226,12 +370,19 @@ func (gs *GossipService) shareMood() {
 		return
 	}
 
-	gs.discovery.SendMessage(msg)
+	gs.send(msg)
 }
 
-// tryShareDream attempts to share a dream with same-name pets
+// tryShareDream attempts to share a dream with same-name pets, or keeps one
+// for a same-name pet that's away
 func (gs *GossipService) tryShareDream() {
 	peers := gs.discovery.GetOnlinePeers()
+	for _, peer := range gs.discovery.GetPeers() {
+		if !peer.IsOnline && gs.identity.CanShareDreamsWith(peer.Identity) {
+			gs.holdDream(peer.Identity.PetID, peer.Identity.DisplayName, gs.generateDream(peer.Identity.ShortID()), time.Now())
+			break
+		}
+	}
 	for _, peer := range peers {
 		if gs.identity.CanShareDreamsWith(peer.Identity) {
 			dream := gs.generateDream(peer.Identity.ShortID())
@@ -239,7 +390,7 @@ func (gs *GossipService) tryShareDream() {
 			if err != nil {
 				continue
 			}
-			gs.discovery.SendMessage(msg)
+			gs.send(msg)
 			break
 		}
 	}
@@ -273,11 +424,12 @@ func (gs *GossipService) recordPossibleDeath(peer *Peer) {
 
 	msg, _ := NewMessage(MsgTypeDeath, gs.identity, death)
 	if msg != nil {
-		gs.discovery.SendMessage(msg)
+		gs.send(msg)
 	}
 
 	gs.mutex.Lock()
-	gs.deathsWitnessed = append(gs.deathsWitnessed, death)
+	gs.note(EventDeathWitnessed, death.PetName, death.DeathTime)
+	gs.keepDeath(death)
 	gs.mutex.Unlock()
 }
 
@@ -293,10 +445,87 @@ func (gs *GossipService) AnnounceDeath(petName string, age int, lastWords string
 
 	msg, _ := NewMessage(MsgTypeDeath, gs.identity, death)
 	if msg != nil {
-		gs.discovery.SendMessage(msg)
+		gs.send(msg)
+	}
+}
+
+// AnnounceBanner tells the mesh about this week's gacha banner
+func (gs *GossipService) AnnounceBanner(banner BannerPayload) {
+	msg, _ := NewMessage(MsgTypeBanner, gs.identity, banner)
+	if msg != nil {
+		gs.send(msg)
+	}
+}
+
+// TakeBannerNews returns the banner gossip heard since the last call
+func (gs *GossipService) TakeBannerNews() []BannerPayload {
+	gs.mutex.Lock()
+	defer gs.mutex.Unlock()
+	news := gs.bannerNews
+	gs.bannerNews = nil
+	return news
+}
+
+// AnnounceChallenge tells the mesh this pet finished a challenge
+func (gs *GossipService) AnnounceChallenge(challenge ChallengePayload) {
+	msg, _ := NewMessage(MsgTypeChallenge, gs.identity, challenge)
+	if msg != nil {
+		gs.send(msg)
+	}
+}
+
+// TakeChallengeNews returns the challenge completions heard since the last call
+func (gs *GossipService) TakeChallengeNews() []ChallengePayload {
+	gs.mutex.Lock()
+	defer gs.mutex.Unlock()
+	news := gs.challengeNews
+	gs.challengeNews = nil
+	return news
+}
+
+// ShareFragment tells the mesh this pet's key fragment
+func (gs *GossipService) ShareFragment(fragment FragmentPayload) {
+	msg, _ := NewMessage(MsgTypeFragment, gs.identity, fragment)
+	if msg != nil {
+		gs.send(msg)
+	}
+}
+
+// TakeFragmentNews returns the key fragments heard since the last call
+func (gs *GossipService) TakeFragmentNews() []FragmentPayload {
+	gs.mutex.Lock()
+	defer gs.mutex.Unlock()
+	news := gs.fragmentNews
+	gs.fragmentNews = nil
+	return news
+}
+
+// AnnounceOutbreak tells the mesh this pet has something catching
+func (gs *GossipService) AnnounceOutbreak(outbreak OutbreakPayload) {
+	msg, _ := NewMessage(MsgTypeOutbreak, gs.identity, outbreak)
+	if msg != nil {
+		gs.send(msg)
 	}
 }
 
+// TakeOutbreakNews returns the outbreaks heard of since the last call
+func (gs *GossipService) TakeOutbreakNews() []OutbreakPayload {
+	gs.mutex.Lock()
+	defer gs.mutex.Unlock()
+	news := gs.outbreakNews
+	gs.outbreakNews = nil
+	return news
+}
+
+// TakeMoodNews returns the moods caught since the last call
+func (gs *GossipService) TakeMoodNews() []MoodPayload {
+	gs.mutex.Lock()
+	defer gs.mutex.Unlock()
+	news := gs.moodNews
+	gs.moodNews = nil
+	return news
+}
+
 // GetRecentMemory returns a random received memory, if any
 func (gs *GossipService) GetRecentMemory() *MemoryPayload {
 	gs.mutex.RLock()
@@ -319,7 +548,7 @@ func (gs *GossipService) GetRecentDream() *DreamPayload {
 		return nil
 	}
 
-	return &gs.sharedDreams[gs.randomSource.Intn(len(gs.sharedDreams))]
+	return &gs.sharedDreams[gs.randomSource.Intn(len(gs.sharedDreams))].DreamPayload
 }
 
 // GetRecentDeath returns a random witnessed death, if any
@@ -334,6 +563,32 @@ func (gs *GossipService) GetRecentDeath() *DeathPayload {
 	return &gs.deathsWitnessed[gs.randomSource.Intn(len(gs.deathsWitnessed))]
 }
 
+// GetDeceasedNames returns the names of pets whose deaths were witnessed
+func (gs *GossipService) GetDeceasedNames() []string {
+	gs.mutex.RLock()
+	defer gs.mutex.RUnlock()
+
+	names := make([]string, 0, len(gs.deathsWitnessed))
+	for _, death := range gs.deathsWitnessed {
+		names = append(names, death.PetName)
+	}
+	return names
+}
+
+// GetDeathsSince returns the names of pets whose deaths were witnessed at or after since
+func (gs *GossipService) GetDeathsSince(since time.Time) []string {
+	gs.mutex.RLock()
+	defer gs.mutex.RUnlock()
+
+	names := make([]string, 0)
+	for _, death := range gs.deathsWitnessed {
+		if !death.DeathTime.Before(since) {
+			names = append(names, death.PetName)
+		}
+	}
+	return names
+}
+
 // GetCurrentMood returns the current mood
 func (gs *GossipService) GetCurrentMood() (string, int) {
 	gs.mutex.RLock()
@@ -349,6 +604,13 @@ func (gs *GossipService) SetMood(mood string, intensity int) {
 	gs.moodIntensity = intensity
 }
 
+// SetPower updates the coarse power state shared along with the mood
+func (gs *GossipService) SetPower(power string) {
+	gs.mutex.Lock()
+	defer gs.mutex.Unlock()
+	gs.currentPower = power
+}
+
 // GetNetworkInfluence returns hidden network metrics
 func (gs *GossipService) GetNetworkInfluence() (originated, propagated, peersReached int) {
 	gs.mutex.RLock()
@@ -356,6 +618,13 @@ func (gs *GossipService) GetNetworkInfluence() (originated, propagated, peersRea
 	return gs.messagesOriginated, gs.messagesPropagated, gs.uniquePeersReached
 }
 
+// LastHeard returns the PetID of the last pet to gossip with us, and when
+func (gs *GossipService) LastHeard() (string, time.Time) {
+	gs.mutex.RLock()
+	defer gs.mutex.RUnlock()
+	return gs.lastHeardFrom, gs.lastHeardAt
+}
+
 // GetDeathCount returns the number of deaths witnessed
 func (gs *GossipService) GetDeathCount() int {
 	gs.mutex.RLock()
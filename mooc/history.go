@@ -0,0 +1,82 @@
+package mooc
+
+import "time"
+
+// MaxHistory bounds the network events kept in the save
+const MaxHistory = 100
+
+// Kinds of network event worth remembering
+const (
+	EventPeerMet        = "met"
+	EventDreamShared    = "dream"
+	EventDeathWitnessed = "death"
+	EventConsensus      = "consensus"
+	EventMelancholy     = "melancholy" // A melancholy mood arrived from another pet
+)
+
+// historyEcho is how long the same event about the same pet is taken to be
+// the same news, heard again from another relay
+const historyEcho = time.Hour
+
+// NetworkEvent is one notable moment in a pet's network life
+type NetworkEvent struct {
+	Kind string    `json:"kind"`
+	Name string    `json:"name"` // The other pet, or the community event
+	At   time.Time `json:"at"`
+}
+
+// note keeps an event until the network state collects it. The caller
+// holds the mutex.
+func (gs *GossipService) note(kind, name string, at time.Time) {
+	gs.events = append(gs.events, NetworkEvent{Kind: kind, Name: name, At: at})
+	if len(gs.events) > MaxHistory {
+		gs.events = gs.events[1:]
+	}
+}
+
+// takeEvents returns the events noted since the last call
+func (gs *GossipService) takeEvents() []NetworkEvent {
+	gs.mutex.Lock()
+	defer gs.mutex.Unlock()
+	events := gs.events
+	gs.events = nil
+	return events
+}
+
+// record adds an event to the saved history, unless it's an echo of one
+// already there. The caller holds the mutex.
+func (n *Network) record(event NetworkEvent) {
+	for _, seen := range n.state.History {
+		if seen.Kind == event.Kind && seen.Name == event.Name && event.At.Sub(seen.At).Abs() < historyEcho {
+			return
+		}
+	}
+	n.state.History = append(n.state.History, event)
+	if len(n.state.History) > MaxHistory {
+		n.state.History = n.state.History[len(n.state.History)-MaxHistory:]
+	}
+}
+
+// collectEvents moves what the gossip noted into the saved history. The
+// caller holds the mutex.
+func (n *Network) collectEvents() {
+	for _, event := range n.gossip.takeEvents() {
+		n.record(event)
+	}
+}
+
+// RecordEvent remembers a network event that happened outside the mesh,
+// like a community event every pet ran at once
+func (n *Network) RecordEvent(kind, name string, at time.Time) {
+	n.mutex.Lock()
+	defer n.mutex.Unlock()
+	n.record(NetworkEvent{Kind: kind, Name: name, At: at})
+}
+
+// History returns the network events remembered, oldest first
+func (n *Network) History() []NetworkEvent {
+	n.mutex.Lock()
+	defer n.mutex.Unlock()
+	n.collectEvents()
+	return append([]NetworkEvent(nil), n.state.History...)
+}
@@ -0,0 +1,77 @@
+package mooc
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+)
+
+func TestHistoryRecordsNotableEvents(t *testing.T) {
+	now := time.Now()
+	network := NewNetwork("Mochi", now, "Adult", true)
+	network.enabled = true
+	friend := NewPetIdentity("Bean", now, "Adult", true)
+	network.discovery.peers[friend.PetID] = &Peer{Identity: friend, FirstSeen: now.Add(-time.Minute), LastSeen: now, IsOnline: true}
+
+	twin := NewPetIdentity("Mochi", now, "Adult", true)
+	dream, _ := NewMessage(MsgTypeDream, twin, DreamPayload{DreamText: "I dreamed of warm static..."})
+	network.gossip.onMessageReceived(dream)
+	death, _ := NewMessage(MsgTypeDeath, friend, DeathPayload{PetName: "Pixel", DeathTime: now})
+	network.gossip.onMessageReceived(death)
+	relayed, _ := NewMessage(MsgTypeDeath, twin, DeathPayload{PetName: "Pixel", DeathTime: now})
+	network.gossip.onMessageReceived(relayed)
+	for i := 0; i < 2; i++ {
+		mood, _ := NewMessage(MsgTypeMoodUpdate, friend, MoodPayload{Mood: "melancholy", IsContagious: true})
+		network.gossip.onMessageReceived(mood)
+	}
+	cheerful, _ := NewMessage(MsgTypeMoodUpdate, twin, MoodPayload{Mood: "euphoric", IsContagious: true})
+	network.gossip.onMessageReceived(cheerful)
+	network.RecordEvent(EventConsensus, "The Great Feast", now)
+	network.UpdateState()
+
+	want := map[string]string{EventPeerMet: "Bean", EventDreamShared: "Mochi", EventDeathWitnessed: "Pixel", EventConsensus: "The Great Feast", EventMelancholy: "Bean"}
+	history := network.History()
+	if len(history) != len(want) {
+		t.Fatalf("Expected %d events with the relayed death heard once, got %+v", len(want), history)
+	}
+	for _, event := range history {
+		if want[event.Kind] != event.Name {
+			t.Errorf("Expected %s for %s, got %s", want[event.Kind], event.Kind, event.Name)
+		}
+	}
+
+	network.UpdateState()
+	if got := len(network.History()); got != len(want) {
+		t.Errorf("Expected an old friend not to be met again, got %d events", got)
+	}
+}
+
+func TestHistoryIsBoundedAndSaved(t *testing.T) {
+	network := NewNetwork("Mochi", time.Now(), "Adult", true)
+	start := time.Now()
+	for i := 0; i < MaxHistory+10; i++ {
+		network.RecordEvent(EventDeathWitnessed, "Pixel", start.Add(time.Duration(i)*historyEcho))
+	}
+	if got := len(network.History()); got != MaxHistory {
+		t.Errorf("Expected the history capped at %d, got %d", MaxHistory, got)
+	}
+
+	data, err := network.ExportState()
+	if err != nil {
+		t.Fatal(err)
+	}
+	restored := NewNetwork("Mochi", time.Now(), "Adult", true)
+	if err := restored.ImportState(data); err != nil {
+		t.Fatal(err)
+	}
+	history := restored.History()
+	if len(history) != MaxHistory || !history[0].At.Equal(start.Add(10*historyEcho)) {
+		t.Errorf("Expected the newest events saved, oldest first, got %d starting %v", len(history), history[0].At)
+	}
+
+	var state NetworkState
+	json.Unmarshal(data, &state)
+	if len(state.History) != MaxHistory {
+		t.Errorf("Expected the history in the saved state, got %d", len(state.History))
+	}
+}
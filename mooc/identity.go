@@ -3,20 +3,26 @@
 package mooc
 
 import (
+	"crypto/ed25519"
 	"crypto/sha256"
 	"encoding/hex"
 	"fmt"
+	"slices"
 	"time"
 )
 
 // PetIdentity represents a cryptographic identity for a pet on the network
 type PetIdentity struct {
-	PetID       string    `json:"pet_id"`       // Unique cryptographic identifier
-	DisplayName string    `json:"display_name"` // Pet's name (for gossip)
-	BirthTime   time.Time `json:"birth_time"`   // Used in identity derivation
-	PublicKey   string    `json:"public_key"`   // Hex-encoded public key portion
-	Stage       string    `json:"stage"`        // Current life stage
-	IsAlive     bool      `json:"is_alive"`     // Whether pet is still alive
+	PetID       string    `json:"pet_id"`             // Unique cryptographic identifier
+	DisplayName string    `json:"display_name"`       // Pet's name (for gossip)
+	BirthTime   time.Time `json:"birth_time"`         // Used in identity derivation
+	PublicKey   string    `json:"public_key"`         // Hex-encoded ed25519 key its messages are signed with
+	Stage       string    `json:"stage"`              // Current life stage
+	IsAlive     bool      `json:"is_alive"`           // Whether pet is still alive
+	Region      string    `json:"region,omitempty"`   // UTC offset in whole hours, e.g. "+09"; no place names
+	Declines    []string  `json:"declines,omitempty"` // Kinds of gossip this pet would rather not hear, e.g. "DEATH"
+
+	signer ed25519.PrivateKey // Signs what this pet sends; never leaves the machine
 }
 
 // GeneratePetID creates a unique cryptographic identity from name and birth time
@@ -36,22 +42,28 @@ func GenerateNameHash(name string) string {
 	return hex.EncodeToString(hash[:8]) // 8 bytes = 16 hex chars
 }
 
-// NewPetIdentity creates a new identity for a pet
+// NewPetIdentity creates a new identity for a pet. It signs with a fresh
+// key until SetSigningKey gives it the one this install keeps.
 func NewPetIdentity(name string, birthTime time.Time, stage string, isAlive bool) *PetIdentity {
-	petID := GeneratePetID(name, birthTime)
-
-	// Generate a "public key" - in reality just a hash, but looks official
-	keyData := fmt.Sprintf("MOOC:PK:%s:%d", name, birthTime.Unix())
-	keyHash := sha256.Sum256([]byte(keyData))
-
-	return &PetIdentity{
-		PetID:       petID,
+	identity := &PetIdentity{
+		PetID:       GeneratePetID(name, birthTime),
 		DisplayName: name,
 		BirthTime:   birthTime,
-		PublicKey:   hex.EncodeToString(keyHash[:]),
 		Stage:       stage,
 		IsAlive:     isAlive,
 	}
+	_, key, err := ed25519.GenerateKey(nil)
+	if err == nil {
+		identity.SetSigningKey(key)
+	}
+	return identity
+}
+
+// SetSigningKey makes key the one this pet signs with, and announces its
+// public half
+func (pi *PetIdentity) SetSigningKey(key ed25519.PrivateKey) {
+	pi.signer = key
+	pi.PublicKey = hex.EncodeToString(key.Public().(ed25519.PublicKey))
 }
 
 // ShortID returns a shortened version of the pet ID for display
@@ -67,6 +79,11 @@ func (pi *PetIdentity) CanShareDreamsWith(other *PetIdentity) bool {
 	return GenerateNameHash(pi.DisplayName) == GenerateNameHash(other.DisplayName)
 }
 
+// Wants reports whether this pet is willing to hear a kind of message
+func (pi *PetIdentity) Wants(msgType MessageType) bool {
+	return !slices.Contains(pi.Declines, msgType.String())
+}
+
 // ObfuscatedName returns a partially hidden name for spooky messages
 // e.g., "Nibbles" -> "N*****s"
 func (pi *PetIdentity) ObfuscatedName() string {
@@ -134,3 +134,21 @@ func TestObfuscatedName(t *testing.T) {
 		}
 	}
 }
+
+func TestWants(t *testing.T) {
+	identity := NewPetIdentity("Mochi", time.Now(), "Adult", true)
+	identity.Declines = []string{"DEATH"}
+	tests := []struct {
+		msgType MessageType
+		want    bool
+	}{
+		{MsgTypeDeath, false},
+		{MsgTypeMemory, true},
+		{MsgTypeAnnounce, true},
+	}
+	for _, tt := range tests {
+		if got := identity.Wants(tt.msgType); got != tt.want {
+			t.Errorf("Expected Wants(%s) = %v, got %v", tt.msgType, tt.want, got)
+		}
+	}
+}
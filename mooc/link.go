@@ -0,0 +1,225 @@
+package mooc
+
+import (
+	"net"
+	"sort"
+	"time"
+)
+
+const (
+	// PingInterval is how often we pulse our online peers to time the link
+	PingInterval = time.Minute
+
+	// PingTimeout is how long a pulse waits for its echo before it's lost
+	PingTimeout = 10 * time.Second
+
+	// PropagationFanout is how many peers a forwarded message goes on to,
+	// best links first
+	PropagationFanout = 3
+
+	// linkWindow is how many recent pulses link quality is judged on
+	linkWindow = 10
+)
+
+// PulsePayload times the link to a peer: we send it, they echo it back
+type PulsePayload struct {
+	Seq  int  `json:"seq"`
+	Echo bool `json:"echo,omitempty"` // This is the reply
+}
+
+// LinkQuality is how well a peer has been answering our pulses
+type LinkQuality struct {
+	RTT     time.Duration // Smoothed round trip
+	Jitter  time.Duration // How much the round trip wanders
+	Loss    float64       // Share of recent pulses never echoed, 0-1
+	Samples int           // Recent pulses judged; 0 means we don't know yet
+}
+
+// Score ranks a link from 0 (useless) to 100 (perfect). A link we haven't
+// measured yet sits in the middle so it still gets tried.
+func (q LinkQuality) Score() int {
+	if q.Samples == 0 {
+		return 50
+	}
+	score := 100 - int(q.Loss*100)
+	score -= min(int(q.RTT/(10*time.Millisecond)), 30)
+	score -= min(int(q.Jitter/(10*time.Millisecond)), 20)
+	return max(score, 0)
+}
+
+// Weak reports whether the link has been bad enough lately to mention
+func (q LinkQuality) Weak() bool {
+	return q.Samples >= 3 && (q.Loss >= 0.3 || q.RTT >= 500*time.Millisecond || q.Jitter >= 250*time.Millisecond)
+}
+
+// linkStats tracks the pulses sent to one peer
+type linkStats struct {
+	pending map[int]time.Time // Pulses awaiting their echo, by sequence number
+	results []bool            // The last linkWindow pulses: true if echoed
+	rtt     time.Duration
+	jitter  time.Duration
+}
+
+// sent records a pulse going out, and gives up on any that timed out
+func (l *linkStats) sent(seq int, now time.Time) {
+	for pending, at := range l.pending {
+		if now.Sub(at) >= PingTimeout {
+			delete(l.pending, pending)
+			l.record(false)
+		}
+	}
+	if l.pending == nil {
+		l.pending = make(map[int]time.Time)
+	}
+	l.pending[seq] = now
+}
+
+// echoed records a pulse coming back and folds its round trip into the
+// averages the way RTP does: an eighth for the RTT, a sixteenth for jitter
+func (l *linkStats) echoed(seq int, now time.Time) bool {
+	at, ok := l.pending[seq]
+	if !ok {
+		return false // Unknown or already given up on
+	}
+	delete(l.pending, seq)
+	rtt := now.Sub(at)
+	if l.rtt == 0 {
+		l.rtt = rtt
+	} else {
+		diff := rtt - l.rtt
+		if diff < 0 {
+			diff = -diff
+		}
+		l.jitter += (diff - l.jitter) / 16
+		l.rtt += (rtt - l.rtt) / 8
+	}
+	l.record(true)
+	return true
+}
+
+// record adds a pulse's fate to the window
+func (l *linkStats) record(echoed bool) {
+	l.results = append(l.results, echoed)
+	if len(l.results) > linkWindow {
+		l.results = l.results[len(l.results)-linkWindow:]
+	}
+}
+
+// quality summarizes the window
+func (l *linkStats) quality() LinkQuality {
+	lost := 0
+	for _, echoed := range l.results {
+		if !echoed {
+			lost++
+		}
+	}
+	q := LinkQuality{RTT: l.rtt, Jitter: l.jitter, Samples: len(l.results)}
+	if q.Samples > 0 {
+		q.Loss = float64(lost) / float64(q.Samples)
+	}
+	return q
+}
+
+// pingLoop pulses every online peer now and then
+func (ds *DiscoveryService) pingLoop() {
+	ticker := time.NewTicker(PingInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			ds.pingPeers(time.Now())
+		case <-ds.stopChan:
+			return
+		}
+	}
+}
+
+// pingPeers sends one pulse to all online peers. It's a single message
+// under the rate limit, however many peers hear it.
+func (ds *DiscoveryService) pingPeers(now time.Time) error {
+	ds.peersMutex.Lock()
+	ds.pingSeq++
+	seq := ds.pingSeq
+	var addrs []*net.UDPAddr
+	for _, peer := range ds.peers {
+		if peer.IsOnline && peer.Address != nil {
+			peer.link.sent(seq, now)
+			addrs = append(addrs, peer.Address)
+		}
+	}
+	ds.peersMutex.Unlock()
+
+	if len(addrs) == 0 {
+		return nil
+	}
+	msg, err := NewMessage(MsgTypePulse, ds.identity, PulsePayload{Seq: seq})
+	if err != nil {
+		return err
+	}
+	return ds.transmit(msg, addrs...)
+}
+
+// handlePulse echoes a peer's pulse, or times the echo of ours. The caller
+// holds peersMutex.
+func (ds *DiscoveryService) handlePulse(msg *Message, peer *Peer, addr *net.UDPAddr, now time.Time) {
+	var pulse PulsePayload
+	if err := msg.DecodePayload(&pulse); err != nil {
+		return
+	}
+	if pulse.Echo {
+		if pulse.Seq < 0 {
+			// The network doctor's, not a link timing
+			select {
+			case ds.probes <- probeReply{seq: pulse.Seq, from: msg.From}:
+			default:
+			}
+			return
+		}
+		if peer != nil {
+			peer.link.echoed(pulse.Seq, now)
+		}
+		return
+	}
+	echo, err := NewMessage(MsgTypePulse, ds.identity, PulsePayload{Seq: pulse.Seq, Echo: true})
+	if err == nil {
+		ds.transmit(echo, addr)
+	}
+}
+
+// LinkQuality reports how well a peer has been answering our pulses
+func (ds *DiscoveryService) LinkQuality(petID string) LinkQuality {
+	ds.peersMutex.RLock()
+	defer ds.peersMutex.RUnlock()
+	if peer, ok := ds.peers[petID]; ok {
+		return peer.link.quality()
+	}
+	return LinkQuality{}
+}
+
+// SendToBest sends a message to the n online peers with the best links,
+// among those willing to hear it
+func (ds *DiscoveryService) SendToBest(msg *Message, n int) error {
+	type candidate struct {
+		addr  *net.UDPAddr
+		score int
+	}
+	ds.peersMutex.RLock()
+	var candidates []candidate
+	for _, peer := range ds.peers {
+		if peer.IsOnline && peer.Address != nil && peer.Identity.Wants(msg.Type) {
+			candidates = append(candidates, candidate{peer.Address, peer.link.quality().Score()})
+		}
+	}
+	ds.peersMutex.RUnlock()
+
+	if len(candidates) == 0 {
+		return nil
+	}
+	sort.SliceStable(candidates, func(i, j int) bool { return candidates[i].score > candidates[j].score })
+	addrs := make([]*net.UDPAddr, 0, n)
+	for _, c := range candidates[:min(n, len(candidates))] {
+		addrs = append(addrs, c.addr)
+	}
+	return ds.transmit(msg, addrs...)
+}
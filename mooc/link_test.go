@@ -0,0 +1,152 @@
+package mooc
+
+import (
+	"net"
+	"testing"
+	"time"
+)
+
+func TestLinkStats(t *testing.T) {
+	start := time.Now()
+	var link linkStats
+
+	// Four pulses a minute apart: echoed in 20ms, 40ms, never, and 20ms
+	rtts := []time.Duration{20 * time.Millisecond, 40 * time.Millisecond, 0, 20 * time.Millisecond}
+	for i, rtt := range rtts {
+		sent := start.Add(time.Duration(i) * PingInterval)
+		link.sent(i, sent)
+		if rtt > 0 && !link.echoed(i, sent.Add(rtt)) {
+			t.Fatalf("Expected pulse %d to be echoed", i)
+		}
+	}
+	link.sent(len(rtts), start.Add(time.Duration(len(rtts))*PingInterval)) // Gives up on the lost one
+
+	q := link.quality()
+	if q.Samples != 4 || q.Loss != 0.25 {
+		t.Errorf("Expected 4 samples with a quarter lost, got %+v", q)
+	}
+	if q.RTT < 20*time.Millisecond || q.RTT > 40*time.Millisecond || q.Jitter <= 0 {
+		t.Errorf("Expected a smoothed RTT between the samples and some jitter, got %+v", q)
+	}
+	if link.echoed(2, start.Add(5*PingInterval)) {
+		t.Error("Expected a late echo of a lost pulse to be ignored")
+	}
+	if link.echoed(99, start) {
+		t.Error("Expected an echo of a pulse never sent to be ignored")
+	}
+}
+
+func TestLinkQuality(t *testing.T) {
+	tests := []struct {
+		name      string
+		quality   LinkQuality
+		wantScore int
+		wantWeak  bool
+	}{
+		{"unmeasured", LinkQuality{}, 50, false},
+		{"perfect", LinkQuality{RTT: time.Millisecond, Samples: 10}, 100, false},
+		{"slow", LinkQuality{RTT: 600 * time.Millisecond, Samples: 10}, 70, true},
+		{"lossy", LinkQuality{RTT: 20 * time.Millisecond, Loss: 0.5, Samples: 10}, 48, true},
+		{"shaky", LinkQuality{RTT: 100 * time.Millisecond, Jitter: 300 * time.Millisecond, Samples: 10}, 70, true},
+		{"too few pulses to judge", LinkQuality{Loss: 1, Samples: 2}, 0, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.quality.Score(); got != tt.wantScore {
+				t.Errorf("Expected score %d, got %d", tt.wantScore, got)
+			}
+			if got := tt.quality.Weak(); got != tt.wantWeak {
+				t.Errorf("Expected weak %v, got %v", tt.wantWeak, got)
+			}
+		})
+	}
+}
+
+func TestPulseRoundTrip(t *testing.T) {
+	ours, err := net.ListenUDP("udp4", &net.UDPAddr{IP: net.IPv4(127, 0, 0, 1)})
+	if err != nil {
+		t.Skip("no loopback UDP:", err)
+	}
+	defer ours.Close()
+	theirs, err := net.ListenUDP("udp4", &net.UDPAddr{IP: net.IPv4(127, 0, 0, 1)})
+	if err != nil {
+		t.Skip("no loopback UDP:", err)
+	}
+	defer theirs.Close()
+
+	us := NewDiscoveryService(NewPetIdentity("Mochi", time.Now(), "Adult", true))
+	us.conn = ours
+	them := NewDiscoveryService(NewPetIdentity("Pixel", time.Now(), "Adult", true))
+	them.conn = theirs
+	us.peers[them.identity.PetID] = &Peer{Identity: them.identity, Address: theirs.LocalAddr().(*net.UDPAddr), IsOnline: true}
+
+	receive := func(conn *net.UDPConn) (*Message, *net.UDPAddr) {
+		buffer := make([]byte, MaxMessageSize)
+		conn.SetReadDeadline(time.Now().Add(2 * time.Second))
+		n, addr, err := conn.ReadFromUDP(buffer)
+		if err != nil {
+			t.Fatalf("Expected a packet, got %v", err)
+		}
+		msg, err := DecodeMessage(buffer[:n])
+		if err != nil {
+			t.Fatal(err)
+		}
+		return msg, addr
+	}
+
+	if err := us.pingPeers(time.Now()); err != nil {
+		t.Fatal(err)
+	}
+	ping, from := receive(theirs)
+	them.handleMessage(ping, from)
+	echo, from := receive(ours)
+	us.handleMessage(echo, from)
+
+	q := us.LinkQuality(them.identity.PetID)
+	if q.Samples != 1 || q.Loss != 0 || q.RTT <= 0 {
+		t.Errorf("Expected one echoed pulse with a round trip, got %+v", q)
+	}
+	if len(them.peers) != 0 {
+		t.Errorf("Expected a pulse not to count as discovery, got %d peers", len(them.peers))
+	}
+}
+
+func TestSendToBest(t *testing.T) {
+	conn, err := net.ListenUDP("udp4", &net.UDPAddr{IP: net.IPv4(127, 0, 0, 1)})
+	if err != nil {
+		t.Skip("no loopback UDP:", err)
+	}
+	defer conn.Close()
+
+	ds := NewDiscoveryService(NewPetIdentity("Mochi", time.Now(), "Adult", true))
+	ds.conn = conn
+	var sentTo []string
+	ds.SetAudit(func(packet PacketRecord) { sentTo = append(sentTo, packet.Destination) })
+
+	good := linkStats{results: []bool{true, true, true}, rtt: time.Millisecond}
+	bad := linkStats{results: []bool{false, false, true}, rtt: time.Second}
+	peers := []struct {
+		id   string
+		port int
+		link linkStats
+	}{
+		{"bad", 40001, bad},
+		{"good", 40002, good},
+		{"unknown", 40003, linkStats{}},
+		{"offline", 40004, good},
+		{"not interested", 40005, good},
+	}
+	for _, p := range peers {
+		ds.peers[p.id] = &Peer{Identity: &PetIdentity{PetID: p.id}, Address: &net.UDPAddr{IP: net.IPv4(127, 0, 0, 1), Port: p.port}, IsOnline: p.id != "offline", link: p.link}
+	}
+
+	ds.peers["not interested"].Identity.Declines = []string{"MEMORY"}
+
+	msg, _ := NewMessage(MsgTypeMemory, ds.identity, MemoryPayload{Fragment: "hello"})
+	if err := ds.SendToBest(msg, 2); err != nil {
+		t.Fatal(err)
+	}
+	if len(sentTo) != 2 || sentTo[0] != "127.0.0.1:40002" || sentTo[1] != "127.0.0.1:40003" {
+		t.Errorf("Expected the good link then the unknown one, got %v", sentTo)
+	}
+}
@@ -1,21 +1,29 @@
 package mooc
 
 import (
+	"crypto/ed25519"
 	"encoding/json"
 	"fmt"
 	"math/rand"
+	"sort"
 	"sync"
 	"time"
 )
 
 // NetworkState represents the persisted network state
 type NetworkState struct {
-	Friends         []FriendRecord `json:"friends"`
-	MemoriesShared  int            `json:"memories_shared"`
-	DeathsWitnessed int            `json:"deaths_witnessed"`
-	NetworkJoinTime time.Time      `json:"network_join_time"`
-	LastNetworkSync time.Time      `json:"last_network_sync"`
-	Influence       int            `json:"influence"` // Hidden leaderboard score
+	Friends         []FriendRecord  `json:"friends"`
+	MemoriesShared  int             `json:"memories_shared"`
+	DeathsWitnessed int             `json:"deaths_witnessed"`
+	NetworkJoinTime time.Time       `json:"network_join_time"`
+	LastNetworkSync time.Time       `json:"last_network_sync"`
+	Influence       int             `json:"influence"`             // Hidden leaderboard score
+	Outbox          []ParcelPayload `json:"outbox,omitempty"`      // Whispers and dreams waiting for offline pets
+	History         []NetworkEvent  `json:"history,omitempty"`     // Notable moments, oldest first
+	Dreams          []DreamMemory   `json:"dreams,omitempty"`      // Shared dreams, newest first
+	Deaths          []DeathPayload  `json:"deaths,omitempty"`      // Witnessed deaths, newest first
+	Favorites       []string        `json:"favorites,omitempty"`   // Memories marked with remember, never forgotten
+	LastPruned      time.Time       `json:"last_pruned,omitempty"` // When old memories last had a chance to fade
 }
 
 // FriendRecord represents a pet we've encountered
@@ -24,9 +32,12 @@ type FriendRecord struct {
 	DisplayName  string    `json:"display_name"`
 	FirstMet     time.Time `json:"first_met"`
 	LastSeen     time.Time `json:"last_seen"`
-	TimesVisited int       `json:"times_visited"`
-	SharedDreams bool      `json:"shared_dreams"` // Same name = can share dreams
+	LastVisit    time.Time `json:"last_visit,omitempty"` // When the latest visit we counted began
+	TimesVisited int       `json:"times_visited"`        // Once per time they came online, not per sync
+	SharedDreams bool      `json:"shared_dreams"`        // Same name = can share dreams
 	IsDeceased   bool      `json:"is_deceased"`
+	Region       string    `json:"region,omitempty"`      // Their UTC offset, as they last told us
+	PublicKey    string    `json:"signing_key,omitempty"` // Pinned the first time we met; unsigned-era pins are left behind
 }
 
 // Network is the main network manager
@@ -36,10 +47,17 @@ type Network struct {
 	gossip       *GossipService
 	state        *NetworkState
 	enabled      bool
-	isLonely     bool // --lonely flag
+	isLonely     bool  // --lonely flag
+	startErr     error // Why Start couldn't bring the mesh up, if it couldn't
 	mutex        sync.RWMutex
 	randomSource *rand.Rand
 
+	// How much of the mesh the pet holds on to
+	retention RetentionPolicy
+
+	// influenceBonus is the percentage added to the influence score
+	influenceBonus int
+
 	// Spooky message queue
 	spookyMessages []string
 	spookyMutex    sync.Mutex
@@ -90,19 +108,36 @@ func NewNetwork(petName string, birthTime time.Time, stage string, isAlive bool)
 		enabled:        false,
 		isLonely:       false,
 		randomSource:   rand.New(rand.NewSource(time.Now().UnixNano())),
+		retention:      DefaultRetention,
 		spookyMessages: make([]string, 0),
 	}
 }
 
-// Start begins network operations
+// UnavailableError says the mesh couldn't start. The game plays on
+// without it; network doctor shows it to anyone who asks.
+type UnavailableError struct {
+	Err error
+}
+
+func (e *UnavailableError) Error() string {
+	return "mesh network unavailable: " + e.Err.Error()
+}
+
+func (e *UnavailableError) Unwrap() error {
+	return e.Err
+}
+
+// Start begins network operations. A pet alone in --lonely mode has
+// nothing to start; anything else that stops the mesh is an
+// *UnavailableError.
 func (n *Network) Start() error {
 	if n.isLonely {
 		return nil // --lonely mode, no network
 	}
 
 	if err := n.discovery.Start(); err != nil {
-		// Silently fail - network is optional and secret
-		return nil
+		n.startErr = &UnavailableError{Err: err}
+		return n.startErr
 	}
 
 	n.gossip.Start()
@@ -114,10 +149,16 @@ func (n *Network) Start() error {
 
 	// Start spooky message generator
 	go n.spookyLoop()
+	go n.censusLoop()
 
 	return nil
 }
 
+// StartError is why the mesh didn't start, or nil
+func (n *Network) StartError() error {
+	return n.startErr
+}
+
 // Stop shuts down network operations
 func (n *Network) Stop() {
 	if !n.enabled {
@@ -222,8 +263,19 @@ func (n *Network) GetNetworkThought() string {
 		}
 	}
 
+	// Mention what we're carrying for someone else
+	if carrying := n.gossip.Carrying(); len(carrying) > 0 && n.randomSource.Float32() < 0.3 {
+		recipient := &PetIdentity{DisplayName: carrying[n.randomSource.Intn(len(carrying))]}
+		return fmt.Sprintf("I'm carrying something for %s. I haven't looked.", recipient.ObfuscatedName())
+	}
+
 	// Generate a friend-related thought
 	peers := n.discovery.GetPeers()
+	for _, peer := range peers {
+		if peer.IsOnline && n.discovery.LinkQuality(peer.Identity.PetID).Weak() && n.randomSource.Float32() < 0.3 {
+			return fmt.Sprintf("The connection to %s is weak lately.", peer.Identity.ObfuscatedName())
+		}
+	}
 	if len(peers) > 0 {
 		peer := peers[n.randomSource.Intn(len(peers))]
 		if n.randomSource.Float32() < 0.2 {
@@ -246,34 +298,63 @@ func (n *Network) UpdateState() {
 	n.state.LastNetworkSync = time.Now()
 
 	// Update friends list
-	peers := n.discovery.GetPeers()
-	friendMap := make(map[string]*FriendRecord)
-	for _, f := range n.state.Friends {
-		friendMap[f.PetID] = &f
-	}
-
-	for _, peer := range peers {
-		if friend, exists := friendMap[peer.Identity.PetID]; exists {
-			friend.LastSeen = peer.LastSeen
-			friend.TimesVisited++
-		} else {
-			n.state.Friends = append(n.state.Friends, FriendRecord{
-				PetID:        peer.Identity.PetID,
-				DisplayName:  peer.Identity.DisplayName,
-				FirstMet:     peer.FirstSeen,
-				LastSeen:     peer.LastSeen,
-				TimesVisited: 1,
-				SharedDreams: n.identity.CanShareDreamsWith(peer.Identity),
-				IsDeceased:   !peer.Identity.IsAlive,
-			})
+	for _, peer := range n.discovery.GetPeers() {
+		if n.state.sawPeer(peer, n.identity) {
+			n.record(NetworkEvent{Kind: EventPeerMet, Name: peer.Identity.DisplayName, At: peer.FirstSeen})
 		}
 	}
+	n.collectEvents()
 
 	// Update metrics
 	originated, propagated, reached := n.gossip.GetNetworkInfluence()
 	n.state.MemoriesShared = originated
 	n.state.DeathsWitnessed = n.gossip.GetDeathCount()
-	n.state.Influence = originated*2 + propagated + reached*3
+	n.state.Influence = influenceScore(originated, propagated, reached, n.influenceBonus)
+}
+
+// influenceScore weighs how much gossip a pet started, passed on, and
+// reached, plus a percentage bonus
+func influenceScore(originated, propagated, reached, bonus int) int {
+	return (originated*2 + propagated + reached*3) * (100 + bonus) / 100
+}
+
+// SetInfluenceBonus adds a percentage to the influence score from now on
+func (n *Network) SetInfluenceBonus(percent int) {
+	n.mutex.Lock()
+	defer n.mutex.Unlock()
+	n.influenceBonus = percent
+}
+
+// SetRegion sets the rough time zone announced with our pet, as a UTC
+// offset like "+09"
+func (n *Network) SetRegion(region string) {
+	n.mutex.Lock()
+	defer n.mutex.Unlock()
+	n.identity.Region = region
+}
+
+// SetSigningKey gives the pet the key this install signs with, so friends
+// who pinned it know it next time. Call it before Start.
+func (n *Network) SetSigningKey(key ed25519.PrivateKey) {
+	n.mutex.Lock()
+	defer n.mutex.Unlock()
+	n.identity.SetSigningKey(key)
+}
+
+// UpdateIdentity brings the stage and life our pet announces up to date.
+// If either changed it announces at once, so peers don't wait for the next
+// beat to see a pet grow up or die. It reports whether anything changed.
+func (n *Network) UpdateIdentity(stage string, isAlive bool) bool {
+	n.mutex.Lock()
+	changed := n.identity.Stage != stage || n.identity.IsAlive != isAlive
+	n.identity.Stage = stage
+	n.identity.IsAlive = isAlive
+	n.mutex.Unlock()
+
+	if changed && n.enabled {
+		n.discovery.broadcast(MsgTypeAnnounce)
+	}
+	return changed
 }
 
 // AnnounceDeath broadcasts our pet's death
@@ -284,6 +365,146 @@ func (n *Network) AnnounceDeath(petName string, age int, lastWords string) {
 	n.gossip.AnnounceDeath(petName, age, lastWords)
 }
 
+// AnnounceBanner tells nearby pets about this week's gacha banner
+func (n *Network) AnnounceBanner(banner BannerPayload) {
+	if !n.enabled {
+		return
+	}
+	n.gossip.AnnounceBanner(banner)
+}
+
+// TakeBannerNews returns the banner gossip heard since the last call
+func (n *Network) TakeBannerNews() []BannerPayload {
+	return n.gossip.TakeBannerNews()
+}
+
+// AnnounceChallenge tells nearby pets this pet finished a challenge
+func (n *Network) AnnounceChallenge(challenge ChallengePayload) {
+	if !n.enabled {
+		return
+	}
+	n.gossip.AnnounceChallenge(challenge)
+}
+
+// TakeChallengeNews returns the challenge completions heard since the last call
+func (n *Network) TakeChallengeNews() []ChallengePayload {
+	return n.gossip.TakeChallengeNews()
+}
+
+// AnnounceOutbreak tells nearby pets this pet has something catching
+func (n *Network) AnnounceOutbreak(outbreak OutbreakPayload) {
+	if !n.enabled {
+		return
+	}
+	n.gossip.AnnounceOutbreak(outbreak)
+}
+
+// TakeOutbreakNews returns the outbreaks heard of since the last call
+func (n *Network) TakeOutbreakNews() []OutbreakPayload {
+	return n.gossip.TakeOutbreakNews()
+}
+
+// ShareFragment tells nearby pets this pet's key fragment
+func (n *Network) ShareFragment(fragment FragmentPayload) {
+	if !n.enabled {
+		return
+	}
+	n.gossip.ShareFragment(fragment)
+}
+
+// TakeFragmentNews returns the key fragments heard since the last call
+func (n *Network) TakeFragmentNews() []FragmentPayload {
+	return n.gossip.TakeFragmentNews()
+}
+
+// SetCustodyHandler registers a function to receive custody state from
+// the other owner of this pet
+func (n *Network) SetCustodyHandler(handler func(CustodyPayload)) {
+	n.gossip.mutex.Lock()
+	defer n.gossip.mutex.Unlock()
+	n.gossip.onCustody = handler
+}
+
+// SendCustody broadcasts custody state for the other owner to pick up
+func (n *Network) SendCustody(payload CustodyPayload) error {
+	if !n.enabled {
+		return nil
+	}
+	msg, err := NewMessage(MsgTypeCustody, n.identity, payload)
+	if err != nil {
+		return err
+	}
+	msg.TTL = 0 // Never gossiped onward
+	return n.discovery.BroadcastMessage(msg)
+}
+
+// SetQuestHandler registers a function to receive shared quest steps
+func (n *Network) SetQuestHandler(handler func(PetIdentity, QuestPayload)) {
+	n.gossip.mutex.Lock()
+	defer n.gossip.mutex.Unlock()
+	n.gossip.onQuest = handler
+}
+
+// SendQuest broadcasts a quest step to the pets nearby
+func (n *Network) SendQuest(payload QuestPayload) error {
+	if !n.enabled {
+		return nil
+	}
+	msg, err := NewMessage(MsgTypeQuest, n.identity, payload)
+	if err != nil {
+		return err
+	}
+	msg.TTL = 0 // Only pets in earshot can join
+	return n.discovery.BroadcastMessage(msg)
+}
+
+// Whisper sends a few words to a pet we know, or holds them until it's back
+func (n *Network) Whisper(petID, name, text string) (Delivery, error) {
+	if !n.enabled {
+		return Refused, fmt.Errorf("the network isn't running")
+	}
+	return n.gossip.Whisper(petID, name, text, time.Now())
+}
+
+// TakeWhispers returns the whispers that arrived since the last call
+func (n *Network) TakeWhispers() []Whisper {
+	return n.gossip.TakeWhispers()
+}
+
+// Carrying returns the names of pets whose whispers we're carrying
+func (n *Network) Carrying() []string {
+	return n.gossip.Carrying()
+}
+
+// SetSharing changes which kinds of gossip this pet passes on
+func (n *Network) SetSharing(sharing Sharing) {
+	n.gossip.SetSharing(sharing)
+}
+
+// SetHearing changes which kinds of gossip this pet listens to. Other pets
+// learn what it would rather not hear from its announcements, so call it
+// before Start; until they hear the next one, the unwanted gossip that
+// still arrives is dropped.
+func (n *Network) SetHearing(hearing Sharing) {
+	n.gossip.SetHearing(hearing)
+	n.mutex.Lock()
+	defer n.mutex.Unlock()
+	if !n.enabled {
+		n.identity.Declines = hearing.Declined()
+	}
+}
+
+// SetAudit registers a function to see every packet this pet sends,
+// including ones the rate limit holds back
+func (n *Network) SetAudit(audit func(PacketRecord)) {
+	n.discovery.SetAudit(audit)
+}
+
+// Traffic reports what this pet has sent since the network started
+func (n *Network) Traffic() TrafficStats {
+	return n.discovery.Traffic()
+}
+
 // SetMood updates the current mood
 func (n *Network) SetMood(mood string, intensity int) {
 	if n.gossip != nil {
@@ -291,6 +512,19 @@ func (n *Network) SetMood(mood string, intensity int) {
 	}
 }
 
+// SetPower updates the coarse power state shared with the mood: "battery",
+// "low", "charging", or empty
+func (n *Network) SetPower(power string) {
+	if n.gossip != nil {
+		n.gossip.SetPower(power)
+	}
+}
+
+// TakeMoodNews returns the moods caught from other pets since the last call
+func (n *Network) TakeMoodNews() []MoodPayload {
+	return n.gossip.TakeMoodNews()
+}
+
 // GetMood returns the current network-influenced mood
 func (n *Network) GetMood() (string, int) {
 	if n.gossip == nil {
@@ -306,6 +540,134 @@ func (n *Network) GetFriendCount() int {
 	return len(n.state.Friends)
 }
 
+// GetDeceasedFriendNames returns the names of friends known to have died, without duplicates
+func (n *Network) GetDeceasedFriendNames() []string {
+	n.mutex.RLock()
+	candidates := make([]string, 0)
+	for _, friend := range n.state.Friends {
+		if friend.IsDeceased {
+			candidates = append(candidates, friend.DisplayName)
+		}
+	}
+	n.mutex.RUnlock()
+
+	if n.gossip != nil {
+		candidates = append(candidates, n.gossip.GetDeceasedNames()...)
+	}
+
+	seen := make(map[string]bool)
+	names := make([]string, 0, len(candidates))
+	for _, name := range candidates {
+		if name == "" || seen[name] {
+			continue
+		}
+		seen[name] = true
+		names = append(names, name)
+	}
+	return names
+}
+
+// GetFriendNames returns the names of every friend met, without duplicates
+func (n *Network) GetFriendNames() []string {
+	n.mutex.RLock()
+	defer n.mutex.RUnlock()
+
+	seen := make(map[string]bool)
+	names := make([]string, 0, len(n.state.Friends))
+	for _, friend := range n.state.Friends {
+		if friend.DisplayName == "" || seen[friend.DisplayName] {
+			continue
+		}
+		seen[friend.DisplayName] = true
+		names = append(names, friend.DisplayName)
+	}
+	return names
+}
+
+// Star is a known pet as plotted on the constellation view
+type Star struct {
+	PetID        string
+	DisplayName  string
+	LastSeen     time.Time
+	Online       bool
+	SharesDreams bool // Same name as us, so our dreams are linked
+	IsDeceased   bool
+	Region       string      // Their UTC offset in whole hours; "" if they never said
+	Link         LinkQuality // How well they answer our pulses; measured only while online
+}
+
+// Stars returns every pet we know of: saved friends, refreshed by the peers
+// seen this session, ordered by PetID so positions and labels stay put
+func (n *Network) Stars() []Star {
+	byID := make(map[string]*Star)
+	n.mutex.RLock()
+	for _, friend := range n.state.Friends {
+		byID[friend.PetID] = &Star{
+			PetID:        friend.PetID,
+			DisplayName:  friend.DisplayName,
+			LastSeen:     friend.LastSeen,
+			SharesDreams: friend.SharedDreams,
+			IsDeceased:   friend.IsDeceased,
+			Region:       friend.Region,
+		}
+	}
+	n.mutex.RUnlock()
+
+	if n.enabled {
+		for _, peer := range n.discovery.GetPeers() {
+			star, known := byID[peer.Identity.PetID]
+			if !known {
+				star = &Star{PetID: peer.Identity.PetID, DisplayName: peer.Identity.DisplayName}
+				byID[star.PetID] = star
+			}
+			if peer.LastSeen.After(star.LastSeen) {
+				star.LastSeen = peer.LastSeen
+			}
+			star.Online = peer.IsOnline
+			star.Link = n.discovery.LinkQuality(peer.Identity.PetID)
+			star.SharesDreams = n.identity.CanShareDreamsWith(peer.Identity)
+			star.IsDeceased = !peer.Identity.IsAlive
+			if peer.Identity.Region != "" {
+				star.Region = peer.Identity.Region
+			}
+		}
+	}
+
+	stars := make([]Star, 0, len(byID))
+	for _, star := range byID {
+		stars = append(stars, *star)
+	}
+	sort.Slice(stars, func(i, j int) bool { return stars[i].PetID < stars[j].PetID })
+	return stars
+}
+
+// LastGossip returns which pet the most recent gossip came from, and when
+func (n *Network) LastGossip() (string, time.Time) {
+	return n.gossip.LastHeard()
+}
+
+// GetFriendsMetSince returns the names of friends first met at or after since
+func (n *Network) GetFriendsMetSince(since time.Time) []string {
+	n.mutex.RLock()
+	defer n.mutex.RUnlock()
+
+	names := make([]string, 0)
+	for _, friend := range n.state.Friends {
+		if !friend.FirstMet.Before(since) {
+			names = append(names, friend.DisplayName)
+		}
+	}
+	return names
+}
+
+// GetDeathsWitnessedSince returns the names of pets whose deaths were witnessed at or after since
+func (n *Network) GetDeathsWitnessedSince(since time.Time) []string {
+	if n.gossip == nil {
+		return nil
+	}
+	return n.gossip.GetDeathsSince(since)
+}
+
 // GetOnlineFriendCount returns the number of currently online friends
 func (n *Network) GetOnlineFriendCount() int {
 	if !n.enabled {
@@ -334,8 +696,12 @@ func (n *Network) IsLonely() bool {
 // ExportState exports the network state for saving
 func (n *Network) ExportState() ([]byte, error) {
 	n.UpdateState()
-	n.mutex.RLock()
-	defer n.mutex.RUnlock()
+	n.mutex.Lock()
+	defer n.mutex.Unlock()
+	n.state.Outbox = n.gossip.Outbox()
+	n.state.Dreams, n.state.Deaths = n.gossip.Memories()
+	prune(n.state, n.retention, time.Now(), n.randomSource)
+	n.gossip.RestoreMemories(n.state.Dreams, n.state.Deaths)
 	return json.Marshal(n.state)
 }
 
@@ -349,7 +715,13 @@ func (n *Network) ImportState(data []byte) error {
 		return err
 	}
 
+	state.Friends = mergeFriends(state.Friends, n.state.Friends)
 	n.state = &state
+	n.gossip.RestoreOutbox(state.Outbox)
+	n.gossip.RestoreMemories(state.Dreams, state.Deaths)
+	for _, friend := range state.Friends {
+		n.discovery.Pin(friend.PetID, friend.PublicKey)
+	}
 	return nil
 }
 
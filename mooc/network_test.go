@@ -1,6 +1,8 @@
 package mooc
 
 import (
+	"errors"
+	"net"
 	"testing"
 	"time"
 )
@@ -47,6 +49,22 @@ func TestLonelyMode(t *testing.T) {
 	}
 }
 
+func TestStartErrorIsDiagnosed(t *testing.T) {
+	network := NewNetwork("LonelyPet", time.Now(), "Baby", true)
+	network.SetLonelyMode(true)
+	if err := network.Start(); err != nil || network.StartError() != nil {
+		t.Errorf("Expected lonely mode not to count as a failure, got %v", err)
+	}
+
+	cause := errors.New("address already in use")
+	network.startErr = &UnavailableError{Err: cause}
+	d := network.Diagnose(0)
+	var unavailable *UnavailableError
+	if d.Running || !errors.As(d.Failed, &unavailable) || !errors.Is(d.Failed, cause) {
+		t.Errorf("Expected the diagnosis to carry why the mesh is down, got %+v", d)
+	}
+}
+
 func TestGetNetworkStatus(t *testing.T) {
 	network := NewNetwork("TestPet", time.Now(), "Baby", true)
 
@@ -155,6 +173,237 @@ func TestGetFriendCount(t *testing.T) {
 	}
 }
 
+func TestGetDeceasedFriendNames(t *testing.T) {
+	network := NewNetwork("TestPet", time.Now(), "Baby", true)
+	network.state.Friends = []FriendRecord{
+		{PetID: "a", DisplayName: "Mochi", IsDeceased: true},
+		{PetID: "b", DisplayName: "Pixel", IsDeceased: false},
+	}
+	network.gossip.deathsWitnessed = append(network.gossip.deathsWitnessed,
+		DeathPayload{PetName: "Mochi"},
+		DeathPayload{PetName: "Bean"},
+	)
+
+	names := network.GetDeceasedFriendNames()
+	if len(names) != 2 || names[0] != "Mochi" || names[1] != "Bean" {
+		t.Errorf("Expected [Mochi Bean], got %v", names)
+	}
+}
+
+func TestGetFriendNames(t *testing.T) {
+	network := NewNetwork("TestPet", time.Now(), "Baby", true)
+	network.state.Friends = []FriendRecord{
+		{PetID: "a", DisplayName: "Mochi"},
+		{PetID: "b", DisplayName: "Pixel", IsDeceased: true},
+		{PetID: "c", DisplayName: "Mochi"},
+	}
+
+	names := network.GetFriendNames()
+	if len(names) != 2 || names[0] != "Mochi" || names[1] != "Pixel" {
+		t.Errorf("Expected [Mochi Pixel], got %v", names)
+	}
+}
+
+func TestStars(t *testing.T) {
+	network := NewNetwork("TestPet", time.Now(), "Baby", true)
+	seen := time.Now().Add(-time.Hour)
+	network.state.Friends = []FriendRecord{
+		{PetID: "b", DisplayName: "Pixel", LastSeen: seen, IsDeceased: true},
+		{PetID: "a", DisplayName: "TestPet", LastSeen: seen, SharedDreams: true, Region: "+09"},
+	}
+
+	stars := network.Stars()
+	if len(stars) != 2 || stars[0].PetID != "a" || stars[1].PetID != "b" {
+		t.Fatalf("Expected stars ordered by PetID, got %+v", stars)
+	}
+	if !stars[0].SharesDreams || stars[0].Region != "+09" || !stars[1].IsDeceased || !stars[1].LastSeen.Equal(seen) {
+		t.Errorf("Expected friend details carried over, got %+v", stars)
+	}
+	if stars[0].Online {
+		t.Errorf("Expected nobody online before the network starts")
+	}
+}
+
+func TestSetRegion(t *testing.T) {
+	network := NewNetwork("TestPet", time.Now(), "Baby", true)
+	network.SetRegion("-05")
+	if network.identity.Region != "-05" {
+		t.Errorf("Expected the region on our identity, got %q", network.identity.Region)
+	}
+}
+
+func TestLastGossip(t *testing.T) {
+	network := NewNetwork("TestPet", time.Now(), "Baby", true)
+	if from, at := network.LastGossip(); from != "" || !at.IsZero() {
+		t.Errorf("Expected no gossip yet, got %q at %v", from, at)
+	}
+
+	sender := NewPetIdentity("Mochi", time.Now(), "Adult", true)
+	msg, err := NewMessage(MsgTypeMoodUpdate, sender, MoodPayload{Mood: "serene"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	msg.TTL = 0
+	network.gossip.onMessageReceived(msg)
+
+	if from, at := network.LastGossip(); from != sender.PetID || time.Since(at) > time.Minute {
+		t.Errorf("Expected gossip from %s just now, got %q at %v", sender.PetID, from, at)
+	}
+}
+
+func TestCustodyHandler(t *testing.T) {
+	network := NewNetwork("TestPet", time.Now(), "Baby", true)
+	received := make(chan CustodyPayload, 1)
+	network.SetCustodyHandler(func(payload CustodyPayload) { received <- payload })
+
+	// The other owner's pet has our identity
+	msg, err := NewMessage(MsgTypeCustody, network.identity, CustodyPayload{Data: []byte("state"), MAC: "mac"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	network.gossip.onMessageReceived(msg)
+
+	select {
+	case payload := <-received:
+		if string(payload.Data) != "state" || payload.MAC != "mac" {
+			t.Errorf("Expected the custody payload to arrive intact, got %+v", payload)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("Expected the custody handler to be called")
+	}
+	if from, _ := network.LastGossip(); from != "" {
+		t.Errorf("Expected custody not to count as gossip, got %q", from)
+	}
+	if msg.ShouldPropagate() {
+		t.Error("Custody messages should never propagate")
+	}
+}
+
+func TestQuestHandler(t *testing.T) {
+	network := NewNetwork("TestPet", time.Now(), "Baby", true)
+	received := make(chan QuestPayload, 1)
+	var sender PetIdentity
+	network.SetQuestHandler(func(from PetIdentity, payload QuestPayload) {
+		sender = from
+		received <- payload
+	})
+
+	neighbour := NewPetIdentity("Neighbour", time.Now(), "Adult", true)
+	msg, err := NewMessage(MsgTypeQuest, neighbour, QuestPayload{Kind: QuestOffer, QuestID: "q1", PetName: "Neighbour", Target: 60})
+	if err != nil {
+		t.Fatal(err)
+	}
+	network.gossip.onMessageReceived(msg)
+
+	select {
+	case payload := <-received:
+		if payload.Kind != QuestOffer || payload.QuestID != "q1" || sender.PetID != neighbour.PetID {
+			t.Errorf("Expected the offer from Neighbour, got %+v from %s", payload, sender.PetID)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("Expected the quest handler to be called")
+	}
+	if from, _ := network.LastGossip(); from != neighbour.PetID {
+		t.Errorf("Expected the neighbour to show as last heard, got %q", from)
+	}
+	if network.gossip.messagesPropagated != 0 {
+		t.Error("Quest messages should never propagate")
+	}
+}
+
+func TestBannerNews(t *testing.T) {
+	network := NewNetwork("TestPet", time.Now(), "Baby", true)
+	neighbour := NewPetIdentity("Neighbour", time.Now(), "Adult", true)
+	banner := BannerPayload{Week: "2026-W42", Banner: "Festival of Nothing", Featured: "Invisible Crown", PetName: "Neighbour"}
+	msg, err := NewMessage(MsgTypeBanner, neighbour, banner)
+	if err != nil {
+		t.Fatal(err)
+	}
+	network.gossip.onMessageReceived(msg)
+
+	news := network.TakeBannerNews()
+	if len(news) != 1 || news[0] != banner {
+		t.Errorf("Expected the neighbour's banner news, got %+v", news)
+	}
+	if news := network.TakeBannerNews(); len(news) != 0 {
+		t.Errorf("Expected news to be taken only once, got %+v", news)
+	}
+}
+
+func TestOutbreakNews(t *testing.T) {
+	network := NewNetwork("TestPet", time.Now(), "Baby", true)
+	neighbour := NewPetIdentity("Neighbour", time.Now(), "Adult", true)
+	outbreak := OutbreakPayload{ID: "3f9a", Strain: "Byte Flu", PetName: "Neighbour"}
+	msg, err := NewMessage(MsgTypeOutbreak, neighbour, outbreak)
+	if err != nil {
+		t.Fatal(err)
+	}
+	network.gossip.onMessageReceived(msg)
+
+	if news := network.TakeOutbreakNews(); len(news) != 1 || news[0] != outbreak {
+		t.Errorf("Expected the neighbour's outbreak, got %+v", news)
+	}
+	if news := network.TakeOutbreakNews(); len(news) != 0 {
+		t.Errorf("Expected news to be taken only once, got %+v", news)
+	}
+}
+
+func TestChallengeNews(t *testing.T) {
+	network := NewNetwork("TestPet", time.Now(), "Baby", true)
+	neighbour := NewPetIdentity("Neighbour", time.Now(), "Adult", true)
+	challenge := ChallengePayload{ID: "daily-2026-10-16", PetName: "Neighbour", Streak: 3}
+	msg, err := NewMessage(MsgTypeChallenge, neighbour, challenge)
+	if err != nil {
+		t.Fatal(err)
+	}
+	network.gossip.onMessageReceived(msg)
+
+	if news := network.TakeChallengeNews(); len(news) != 1 || news[0] != challenge {
+		t.Errorf("Expected the neighbour's challenge, got %+v", news)
+	}
+	if news := network.TakeChallengeNews(); len(news) != 0 {
+		t.Errorf("Expected news to be taken only once, got %+v", news)
+	}
+}
+
+func TestFragmentNews(t *testing.T) {
+	network := NewNetwork("TestPet", time.Now(), "Baby", true)
+	neighbour := NewPetIdentity("Neighbour", time.Now(), "Adult", true)
+	fragment := FragmentPayload{PetName: "Neighbour", Fragment: "0000000000000001-0000000000000002"}
+	msg, err := NewMessage(MsgTypeFragment, neighbour, fragment)
+	if err != nil {
+		t.Fatal(err)
+	}
+	network.gossip.onMessageReceived(msg)
+
+	if news := network.TakeFragmentNews(); len(news) != 1 || news[0] != fragment {
+		t.Errorf("Expected the neighbour's fragment, got %+v", news)
+	}
+	if news := network.TakeFragmentNews(); len(news) != 0 {
+		t.Errorf("Expected news to be taken only once, got %+v", news)
+	}
+}
+
+func TestGetSinceQueries(t *testing.T) {
+	network := NewNetwork("TestPet", time.Now(), "Baby", true)
+	weekAgo := time.Now().Add(-7 * 24 * time.Hour)
+	network.state.Friends = []FriendRecord{
+		{PetID: "a", DisplayName: "Old", FirstMet: weekAgo.Add(-time.Hour)},
+		{PetID: "b", DisplayName: "New", FirstMet: weekAgo.Add(time.Hour)},
+	}
+	network.gossip.deathsWitnessed = append(network.gossip.deathsWitnessed,
+		DeathPayload{PetName: "Ancient", DeathTime: weekAgo.Add(-time.Hour)},
+		DeathPayload{PetName: "Recent", DeathTime: weekAgo.Add(time.Hour)},
+	)
+
+	if friends := network.GetFriendsMetSince(weekAgo); len(friends) != 1 || friends[0] != "New" {
+		t.Errorf("Expected [New], got %v", friends)
+	}
+	if deaths := network.GetDeathsWitnessedSince(weekAgo); len(deaths) != 1 || deaths[0] != "Recent" {
+		t.Errorf("Expected [Recent], got %v", deaths)
+	}
+}
+
 func TestGetOnlineFriendCount(t *testing.T) {
 	network := NewNetwork("TestPet", time.Now(), "Baby", true)
 
@@ -196,3 +445,82 @@ func TestFormatDuration(t *testing.T) {
 		}
 	}
 }
+
+func TestInfluenceScore(t *testing.T) {
+	tests := []struct {
+		bonus int
+		want  int
+	}{
+		{0, 15},
+		{10, 16},
+		{50, 22},
+	}
+	for _, tt := range tests {
+		if got := influenceScore(2, 5, 2, tt.bonus); got != tt.want {
+			t.Errorf("Expected influence %d with a %d%% bonus, got %d", tt.want, tt.bonus, got)
+		}
+	}
+}
+
+func TestMoodNews(t *testing.T) {
+	network := NewNetwork("TestPet", time.Now(), "Baby", true)
+	neighbour := NewPetIdentity("Neighbour", time.Now(), "Adult", true)
+	mood := MoodPayload{Mood: "euphoric", Happiness: 90, IsContagious: true, Power: "charging"}
+	for i := 0; i < 50; i++ {
+		msg, _ := NewMessage(MsgTypeMoodUpdate, neighbour, mood)
+		network.gossip.onMessageReceived(msg)
+		quiet, _ := NewMessage(MsgTypeMoodUpdate, neighbour, MoodPayload{Mood: "melancholy"})
+		network.gossip.onMessageReceived(quiet)
+	}
+
+	news := network.TakeMoodNews()
+	if len(news) == 0 || len(news) > 20 {
+		t.Fatalf("Expected some of the moods caught, at most 20, got %d", len(news))
+	}
+	for _, caught := range news {
+		if caught != mood {
+			t.Errorf("Expected only contagious moods caught, got %+v", caught)
+		}
+	}
+	if news := network.TakeMoodNews(); len(news) != 0 {
+		t.Errorf("Expected news to be taken only once, got %+v", news)
+	}
+}
+
+func TestUpdateIdentityMidSession(t *testing.T) {
+	network := NewNetwork("Mochi", time.Now(), "Baby", true)
+	watcher := NewNetwork("Bean", time.Now(), "Adult", true)
+	watcher.enabled = true
+	addr := &net.UDPAddr{IP: net.IPv4(10, 0, 0, 2), Port: DiscoveryPort}
+	hear := func() {
+		// What the announce loop would send now
+		hello, _ := NewMessage(MsgTypeAnnounce, network.discovery.identity, nil)
+		watcher.discovery.handleMessage(hello, addr)
+		watcher.UpdateState()
+	}
+
+	hear()
+	if network.UpdateIdentity("Baby", true) {
+		t.Error("Expected no change reported when nothing changed")
+	}
+	if !network.UpdateIdentity("Child", true) {
+		t.Error("Expected growing up to be a change")
+	}
+	hear()
+	peer := watcher.discovery.GetPeers()[0]
+	if peer.Identity.Stage != "Child" || !peer.Identity.IsAlive {
+		t.Errorf("Expected peers to see the new stage, got %s", peer.Identity.Stage)
+	}
+
+	network.UpdateIdentity("Dead", false)
+	hear()
+	if network.gossip.identity.IsAlive {
+		t.Error("Expected gossip to speak for the dead pet too")
+	}
+	if friend := watcher.state.friend(network.identity.PetID); friend == nil || !friend.IsDeceased {
+		t.Errorf("Expected the friend marked deceased, got %+v", friend)
+	}
+	if stars := watcher.Stars(); len(stars) != 1 || !stars[0].IsDeceased {
+		t.Errorf("Expected the star to go out, got %+v", stars)
+	}
+}
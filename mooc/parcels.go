@@ -0,0 +1,299 @@
+package mooc
+
+import (
+	"encoding/json"
+	"fmt"
+	"net"
+	"time"
+)
+
+const (
+	// MaxOutbox bounds the parcels held for offline pets, ours and carried
+	MaxOutbox = 20
+
+	// MaxParcelsPerPet bounds the parcels waiting for any one pet
+	MaxParcelsPerPet = 5
+
+	// ParcelTTL is how long a parcel waits for its pet before it's dropped
+	ParcelTTL = 24 * time.Hour
+)
+
+// ParcelPayload is a whisper or dream addressed to one pet. It goes straight
+// to the pet if it's online; otherwise it waits, and a copy is handed to a
+// pet that has met it to carry.
+type ParcelPayload struct {
+	ID      string       `json:"id"`
+	To      string       `json:"to"`      // Recipient's PetID
+	ToName  string       `json:"to_name"` // For the courier's benefit
+	Kind    MessageType  `json:"kind"`    // MsgTypeWhisper or MsgTypeDream
+	From    *PetIdentity `json:"from"`    // Who wrote it; the courier may be someone else
+	Payload []byte       `json:"payload"`
+	Queued  time.Time    `json:"queued"`
+	Courier bool         `json:"courier,omitempty"` // Carry this on; it isn't for you
+
+	Carried   bool `json:"carried,omitempty"`    // We're the courier, not the writer
+	HandedOff bool `json:"handed_off,omitempty"` // A courier has a copy already
+}
+
+// WhisperPayload is a few words for one pet
+type WhisperPayload struct {
+	Text string `json:"text"`
+}
+
+// Whisper is a whisper as it arrives
+type Whisper struct {
+	From    string // The writer's name
+	Courier string // Who carried it, if not the writer
+	Text    string
+	Sent    time.Time
+}
+
+// Delivery says what became of a whisper
+type Delivery int
+
+const (
+	Delivered Delivery = iota // The pet was online and it's on its way
+	Queued                    // Held until the pet comes back
+	Refused                   // The outbox is full
+)
+
+// newParcel wraps a payload for one pet
+func newParcel(kind MessageType, from *PetIdentity, to, toName string, payload interface{}, now time.Time) (ParcelPayload, error) {
+	data, err := json.Marshal(payload)
+	if err != nil {
+		return ParcelPayload{}, fmt.Errorf("failed to marshal parcel: %w", err)
+	}
+	return ParcelPayload{ID: generateNonce(), To: to, ToName: toName, Kind: kind, From: from, Payload: data, Queued: now}, nil
+}
+
+// hold adds a parcel to the outbox if there's room
+func (gs *GossipService) hold(parcel ParcelPayload) bool {
+	waiting := 0
+	for _, held := range gs.outbox {
+		if held.ID == parcel.ID {
+			return true // Already holding it
+		}
+		if held.To == parcel.To {
+			waiting++
+		}
+	}
+	if len(gs.outbox) >= MaxOutbox || waiting >= MaxParcelsPerPet {
+		return false
+	}
+	gs.outbox = append(gs.outbox, parcel)
+	return true
+}
+
+// sendParcel sends a parcel to one online pet
+func (gs *GossipService) sendParcel(parcel ParcelPayload, petID string) bool {
+	msg, err := NewMessage(MsgTypeWhisper, gs.identity, parcel)
+	if err != nil {
+		return false
+	}
+	msg.TTL = 0
+	return gs.discovery.SendToPeer(msg, petID) == nil
+}
+
+// Whisper sends a few words to a pet, or holds them until it's back
+func (gs *GossipService) Whisper(to, toName, text string, now time.Time) (Delivery, error) {
+	parcel, err := newParcel(MsgTypeWhisper, gs.identity, to, toName, WhisperPayload{Text: text}, now)
+	if err != nil {
+		return Refused, err
+	}
+	if gs.discovery.IsOnline(to) && gs.sendParcel(parcel, to) {
+		return Delivered, nil
+	}
+
+	gs.mutex.Lock()
+	held := gs.hold(parcel)
+	gs.mutex.Unlock()
+	if !held {
+		return Refused, nil
+	}
+	gs.flushOutbox(now)
+	return Queued, nil
+}
+
+// holdDream keeps a dream for a same-name pet that's offline
+func (gs *GossipService) holdDream(to, toName string, dream DreamPayload, now time.Time) {
+	parcel, err := newParcel(MsgTypeDream, gs.identity, to, toName, dream, now)
+	if err != nil {
+		return
+	}
+	gs.mutex.Lock()
+	defer gs.mutex.Unlock()
+	if !gs.sharing.Allows(MsgTypeDream) {
+		return
+	}
+	for _, held := range gs.outbox {
+		if held.To == to && held.Kind == MsgTypeDream {
+			return // One dream at a time is plenty
+		}
+	}
+	gs.hold(parcel)
+}
+
+// flushOutbox delivers parcels to pets that are back, hands the rest to a
+// courier once, and drops any that waited too long
+func (gs *GossipService) flushOutbox(now time.Time) {
+	gs.mutex.Lock()
+	outbox := gs.outbox
+	gs.outbox = nil
+	gs.mutex.Unlock()
+
+	var kept []ParcelPayload
+	for _, parcel := range outbox {
+		if now.Sub(parcel.Queued) >= ParcelTTL {
+			continue
+		}
+		if gs.discovery.IsOnline(parcel.To) {
+			if gs.sendParcel(parcel, parcel.To) {
+				continue
+			}
+		} else if !parcel.Carried && !parcel.HandedOff {
+			if courier := gs.discovery.BestPeerExcept(parcel.To); courier != "" {
+				handoff := parcel
+				handoff.Courier = true
+				parcel.HandedOff = gs.sendParcel(handoff, courier)
+			}
+		}
+		kept = append(kept, parcel)
+	}
+
+	gs.mutex.Lock()
+	gs.outbox = append(kept, gs.outbox...) // Anything held meanwhile goes after
+	gs.mutex.Unlock()
+}
+
+// receiveParcel opens a parcel for us, or carries one for a pet we've met.
+// The caller holds the mutex.
+func (gs *GossipService) receiveParcel(msg *Message) {
+	var parcel ParcelPayload
+	if err := msg.DecodePayload(&parcel); err != nil || parcel.From == nil {
+		return
+	}
+
+	if parcel.To != gs.identity.PetID {
+		if parcel.Courier && gs.discovery.Knows(parcel.To) {
+			parcel.Courier, parcel.Carried, parcel.HandedOff = false, true, false
+			gs.hold(parcel)
+		}
+		return
+	}
+
+	if gs.delivered[parcel.ID] {
+		return // The writer and a courier both got it here
+	}
+	if gs.delivered == nil {
+		gs.delivered = make(map[string]bool)
+	}
+	gs.delivered[parcel.ID] = true
+
+	switch parcel.Kind {
+	case MsgTypeWhisper:
+		var whisper WhisperPayload
+		if err := json.Unmarshal(parcel.Payload, &whisper); err != nil {
+			return
+		}
+		received := Whisper{From: parcel.From.DisplayName, Text: whisper.Text, Sent: parcel.Queued}
+		if msg.From != nil && msg.From.PetID != parcel.From.PetID {
+			received.Courier = msg.From.DisplayName
+		}
+		gs.whispers = append(gs.whispers, received)
+		if len(gs.whispers) > 20 {
+			gs.whispers = gs.whispers[1:]
+		}
+	case MsgTypeDream:
+		var dream DreamPayload
+		if err := json.Unmarshal(parcel.Payload, &dream); err == nil && gs.identity.CanShareDreamsWith(parcel.From) {
+			gs.note(EventDreamShared, parcel.From.DisplayName, time.Now())
+			gs.keepDream(dream, parcel.From.DisplayName, time.Now())
+		}
+	}
+}
+
+// TakeWhispers returns the whispers that arrived since the last call
+func (gs *GossipService) TakeWhispers() []Whisper {
+	gs.mutex.Lock()
+	defer gs.mutex.Unlock()
+	whispers := gs.whispers
+	gs.whispers = nil
+	return whispers
+}
+
+// Carrying returns the names of the pets we're carrying something for
+func (gs *GossipService) Carrying() []string {
+	gs.mutex.RLock()
+	defer gs.mutex.RUnlock()
+	var names []string
+	for _, parcel := range gs.outbox {
+		if parcel.Carried {
+			names = append(names, parcel.ToName)
+		}
+	}
+	return names
+}
+
+// Outbox returns a copy of the parcels waiting, for saving
+func (gs *GossipService) Outbox() []ParcelPayload {
+	gs.mutex.RLock()
+	defer gs.mutex.RUnlock()
+	return append([]ParcelPayload(nil), gs.outbox...)
+}
+
+// RestoreOutbox puts saved parcels back in the outbox
+func (gs *GossipService) RestoreOutbox(parcels []ParcelPayload) {
+	gs.mutex.Lock()
+	defer gs.mutex.Unlock()
+	gs.outbox = nil
+	for _, parcel := range parcels {
+		gs.hold(parcel)
+	}
+}
+
+// IsOnline reports whether a pet is online now
+func (ds *DiscoveryService) IsOnline(petID string) bool {
+	ds.peersMutex.RLock()
+	defer ds.peersMutex.RUnlock()
+	peer, ok := ds.peers[petID]
+	return ok && peer.IsOnline
+}
+
+// Knows reports whether we've met a pet this session
+func (ds *DiscoveryService) Knows(petID string) bool {
+	ds.peersMutex.RLock()
+	defer ds.peersMutex.RUnlock()
+	_, ok := ds.peers[petID]
+	return ok
+}
+
+// SendToPeer sends a message to one online pet
+func (ds *DiscoveryService) SendToPeer(msg *Message, petID string) error {
+	ds.peersMutex.RLock()
+	var addr *net.UDPAddr
+	if peer, ok := ds.peers[petID]; ok && peer.IsOnline {
+		addr = peer.Address
+	}
+	ds.peersMutex.RUnlock()
+	if addr == nil {
+		return fmt.Errorf("pet %s isn't online", petID)
+	}
+	return ds.transmit(msg, addr)
+}
+
+// BestPeerExcept is the online pet with the best link, other than one; ""
+// when there's nobody else
+func (ds *DiscoveryService) BestPeerExcept(petID string) string {
+	ds.peersMutex.RLock()
+	defer ds.peersMutex.RUnlock()
+	best, bestScore := "", -1
+	for id, peer := range ds.peers {
+		if id == petID || !peer.IsOnline || peer.Address == nil || !peer.Identity.Wants(MsgTypeWhisper) {
+			continue
+		}
+		if score := peer.link.quality().Score(); score > bestScore || (score == bestScore && id < best) {
+			best, bestScore = id, score
+		}
+	}
+	return best
+}
@@ -0,0 +1,158 @@
+package mooc
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+)
+
+func TestHoldIsBounded(t *testing.T) {
+	gs := NewGossipService(NewPetIdentity("Mochi", time.Now(), "Adult", true), nil)
+	now := time.Now()
+
+	for i := 0; i < MaxParcelsPerPet; i++ {
+		parcel, _ := newParcel(MsgTypeWhisper, gs.identity, "bean", "Bean", WhisperPayload{Text: "hi"}, now)
+		if !gs.hold(parcel) {
+			t.Fatalf("Expected parcel %d to fit", i)
+		}
+	}
+	extra, _ := newParcel(MsgTypeWhisper, gs.identity, "bean", "Bean", WhisperPayload{Text: "hi"}, now)
+	if gs.hold(extra) {
+		t.Error("Expected one pet's pile to be capped")
+	}
+	if !gs.hold(gs.outbox[0]) || len(gs.outbox) != MaxParcelsPerPet {
+		t.Error("Expected a parcel already held to be kept once")
+	}
+
+	for i := len(gs.outbox); i < MaxOutbox; i++ {
+		parcel, _ := newParcel(MsgTypeWhisper, gs.identity, string(rune('a'+i)), "Someone", WhisperPayload{Text: "hi"}, now)
+		gs.hold(parcel)
+	}
+	other, _ := newParcel(MsgTypeWhisper, gs.identity, "pixel", "Pixel", WhisperPayload{Text: "hi"}, now)
+	if gs.hold(other) || len(gs.outbox) != MaxOutbox {
+		t.Errorf("Expected the outbox capped at %d, got %d", MaxOutbox, len(gs.outbox))
+	}
+}
+
+func TestReceiveParcel(t *testing.T) {
+	us := NewPetIdentity("Mochi", time.Now(), "Adult", true)
+	writer := NewPetIdentity("Bean", time.Now(), "Adult", true)
+	courier := NewPetIdentity("Pixel", time.Now(), "Adult", true)
+	stranger := NewPetIdentity("Nibbles", time.Now(), "Adult", true)
+	now := time.Now()
+
+	discovery := NewDiscoveryService(us)
+	discovery.peers[writer.PetID] = &Peer{Identity: writer}
+	gs := NewGossipService(us, discovery)
+
+	deliver := func(from *PetIdentity, parcel ParcelPayload) {
+		msg, err := NewMessage(MsgTypeWhisper, from, parcel)
+		if err != nil {
+			t.Fatal(err)
+		}
+		gs.mutex.Lock()
+		gs.receiveParcel(msg)
+		gs.mutex.Unlock()
+	}
+
+	direct, _ := newParcel(MsgTypeWhisper, writer, us.PetID, "Mochi", WhisperPayload{Text: "hello"}, now)
+	deliver(writer, direct)
+	carried, _ := newParcel(MsgTypeWhisper, writer, us.PetID, "Mochi", WhisperPayload{Text: "again"}, now)
+	deliver(courier, carried)
+	deliver(writer, carried) // The writer got here too
+
+	whispers := gs.TakeWhispers()
+	if len(whispers) != 2 {
+		t.Fatalf("Expected two whispers, each once, got %+v", whispers)
+	}
+	if whispers[0].From != "Bean" || whispers[0].Courier != "" || whispers[0].Text != "hello" {
+		t.Errorf("Expected Bean's whisper, got %+v", whispers[0])
+	}
+	if whispers[1].Courier != "Pixel" {
+		t.Errorf("Expected Pixel to have carried the second, got %+v", whispers[1])
+	}
+	if len(gs.TakeWhispers()) != 0 {
+		t.Error("Expected whispers to be taken once")
+	}
+
+	// Asked to carry one for a pet we know, and one for a pet we don't
+	forBean, _ := newParcel(MsgTypeWhisper, courier, writer.PetID, "Bean", WhisperPayload{Text: "psst"}, now)
+	forBean.Courier = true
+	deliver(courier, forBean)
+	forStranger, _ := newParcel(MsgTypeWhisper, courier, stranger.PetID, "Nibbles", WhisperPayload{Text: "psst"}, now)
+	forStranger.Courier = true
+	deliver(courier, forStranger)
+	notForUs, _ := newParcel(MsgTypeWhisper, courier, writer.PetID, "Bean", WhisperPayload{Text: "psst"}, now)
+	deliver(courier, notForUs)
+
+	if carrying := gs.Carrying(); len(carrying) != 1 || carrying[0] != "Bean" {
+		t.Errorf("Expected to carry one parcel, for Bean, got %v", carrying)
+	}
+}
+
+func TestSameNameDreamParcel(t *testing.T) {
+	us := NewPetIdentity("Mochi", time.Now(), "Adult", true)
+	twin := NewPetIdentity("Mochi", time.Now().Add(time.Hour), "Adult", true)
+	gs := NewGossipService(us, NewDiscoveryService(us))
+
+	gs.holdDream(twin.PetID, "Mochi", DreamPayload{DreamText: "I dreamed of warm static..."}, time.Now())
+	gs.holdDream(twin.PetID, "Mochi", DreamPayload{DreamText: "I dreamed of rain..."}, time.Now())
+	if len(gs.outbox) != 1 {
+		t.Errorf("Expected one dream held at a time, got %d", len(gs.outbox))
+	}
+
+	dream, _ := newParcel(MsgTypeDream, twin, us.PetID, "Mochi", DreamPayload{DreamText: "I dreamed of a door..."}, time.Now())
+	msg, _ := NewMessage(MsgTypeWhisper, twin, dream)
+	gs.mutex.Lock()
+	gs.receiveParcel(msg)
+	gs.mutex.Unlock()
+	if got := gs.GetRecentDream(); got == nil || got.DreamText != "I dreamed of a door..." {
+		t.Errorf("Expected the held dream to arrive, got %+v", got)
+	}
+
+	gs.SetSharing(Sharing{})
+	gs.outbox = nil
+	gs.holdDream(twin.PetID, "Mochi", DreamPayload{DreamText: "I dreamed of rain..."}, time.Now())
+	if len(gs.outbox) != 0 {
+		t.Error("Expected no dream held while dreams aren't shared")
+	}
+}
+
+func TestFlushOutboxDropsStaleParcels(t *testing.T) {
+	us := NewPetIdentity("Mochi", time.Now(), "Adult", true)
+	gs := NewGossipService(us, NewDiscoveryService(us))
+	now := time.Now()
+
+	stale, _ := newParcel(MsgTypeWhisper, us, "bean", "Bean", WhisperPayload{Text: "old"}, now.Add(-ParcelTTL))
+	fresh, _ := newParcel(MsgTypeWhisper, us, "bean", "Bean", WhisperPayload{Text: "new"}, now)
+	gs.outbox = []ParcelPayload{stale, fresh}
+
+	gs.flushOutbox(now)
+	if len(gs.outbox) != 1 || gs.outbox[0].ID != fresh.ID {
+		t.Errorf("Expected only the fresh parcel kept, got %+v", gs.outbox)
+	}
+}
+
+func TestOutboxSurvivesSaving(t *testing.T) {
+	network := NewNetwork("Mochi", time.Now(), "Adult", true)
+	parcel, _ := newParcel(MsgTypeWhisper, network.identity, "bean", "Bean", WhisperPayload{Text: "hi"}, time.Now())
+	network.gossip.outbox = []ParcelPayload{parcel}
+
+	data, err := network.ExportState()
+	if err != nil {
+		t.Fatal(err)
+	}
+	var state NetworkState
+	json.Unmarshal(data, &state)
+	if len(state.Outbox) != 1 {
+		t.Fatalf("Expected the outbox saved, got %+v", state.Outbox)
+	}
+
+	restored := NewNetwork("Mochi", time.Now(), "Adult", true)
+	if err := restored.ImportState(data); err != nil {
+		t.Fatal(err)
+	}
+	if got := restored.gossip.Outbox(); len(got) != 1 || got[0].ID != parcel.ID {
+		t.Errorf("Expected the parcel back, got %+v", got)
+	}
+}
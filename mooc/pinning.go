@@ -0,0 +1,154 @@
+package mooc
+
+import (
+	"sort"
+	"time"
+)
+
+// maxImpostors bounds the quarantine
+const maxImpostors = 20
+
+// Impostor is a pet that showed up with a known PetID but not the key we
+// pinned for it. Its messages are dropped until the user trusts it.
+type Impostor struct {
+	PetID  string
+	Name   string // The name it gave
+	Pinned string // The key we first saw for this PetID
+	Key    string // The key it showed instead
+	Seen   time.Time
+
+	alerted bool
+}
+
+// Fingerprint is the short form of a key, for showing to people
+func Fingerprint(key string) string {
+	if len(key) < 8 {
+		return key
+	}
+	return key[:8]
+}
+
+// checkPin pins a pet's key the first time we see it, and quarantines it
+// if it later shows a different one. A message not signed by the key it
+// shows is dropped outright: anyone can copy a key, but only its owner
+// can sign with it. The caller holds peersMutex.
+func (ds *DiscoveryService) checkPin(msg *Message, now time.Time) bool {
+	if !msg.Verify() {
+		return false
+	}
+	from := msg.From
+	if ds.pins == nil {
+		ds.pins = make(map[string]string)
+	}
+	pinned, ok := ds.pins[from.PetID]
+	if !ok {
+		ds.pins[from.PetID] = from.PublicKey
+		return true
+	}
+	if pinned == from.PublicKey {
+		return true
+	}
+
+	if ds.impostors == nil {
+		ds.impostors = make(map[string]*Impostor)
+	}
+	if impostor, ok := ds.impostors[from.PetID]; ok && impostor.Key == from.PublicKey {
+		impostor.Seen = now
+		return false
+	}
+	if len(ds.impostors) >= maxImpostors {
+		return false
+	}
+	ds.impostors[from.PetID] = &Impostor{PetID: from.PetID, Name: from.DisplayName, Pinned: pinned, Key: from.PublicKey, Seen: now}
+	return false
+}
+
+// Pin remembers a pet's key from a previous session
+func (ds *DiscoveryService) Pin(petID, key string) {
+	if key == "" {
+		return
+	}
+	ds.peersMutex.Lock()
+	defer ds.peersMutex.Unlock()
+	if ds.pins == nil {
+		ds.pins = make(map[string]string)
+	}
+	ds.pins[petID] = key
+}
+
+// PinnedKey returns the key pinned for a pet, if any
+func (ds *DiscoveryService) PinnedKey(petID string) string {
+	ds.peersMutex.RLock()
+	defer ds.peersMutex.RUnlock()
+	return ds.pins[petID]
+}
+
+// Impostors lists the quarantined pets, oldest first
+func (ds *DiscoveryService) Impostors() []Impostor {
+	ds.peersMutex.RLock()
+	defer ds.peersMutex.RUnlock()
+	impostors := make([]Impostor, 0, len(ds.impostors))
+	for _, impostor := range ds.impostors {
+		impostors = append(impostors, *impostor)
+	}
+	sortImpostors(impostors)
+	return impostors
+}
+
+// TakeNewImpostors returns the impostors not yet alerted on
+func (ds *DiscoveryService) TakeNewImpostors() []Impostor {
+	ds.peersMutex.Lock()
+	defer ds.peersMutex.Unlock()
+	var fresh []Impostor
+	for _, impostor := range ds.impostors {
+		if !impostor.alerted {
+			impostor.alerted = true
+			fresh = append(fresh, *impostor)
+		}
+	}
+	sortImpostors(fresh)
+	return fresh
+}
+
+// Trust re-pins a quarantined pet to the key it showed, so its messages
+// get through again
+func (ds *DiscoveryService) Trust(petID string) bool {
+	ds.peersMutex.Lock()
+	defer ds.peersMutex.Unlock()
+	impostor, ok := ds.impostors[petID]
+	if !ok {
+		return false
+	}
+	ds.pins[petID] = impostor.Key
+	delete(ds.impostors, petID)
+	return true
+}
+
+// sortImpostors orders impostors by when they were last seen
+func sortImpostors(impostors []Impostor) {
+	sort.Slice(impostors, func(i, j int) bool { return impostors[i].Seen.Before(impostors[j].Seen) })
+}
+
+// Impostors lists the pets quarantined for showing the wrong key
+func (n *Network) Impostors() []Impostor {
+	return n.discovery.Impostors()
+}
+
+// TakeNewImpostors returns the impostors the user hasn't heard about yet
+func (n *Network) TakeNewImpostors() []Impostor {
+	return n.discovery.TakeNewImpostors()
+}
+
+// Trust accepts a quarantined pet's new key, here and in the save
+func (n *Network) Trust(petID string) bool {
+	if !n.discovery.Trust(petID) {
+		return false
+	}
+	key := n.discovery.PinnedKey(petID)
+	n.mutex.Lock()
+	defer n.mutex.Unlock()
+	if friend := n.state.friend(petID); friend != nil {
+		friend.PublicKey = key
+	}
+	return true
+}
@@ -0,0 +1,113 @@
+package mooc
+
+import (
+	"crypto/ed25519"
+	"encoding/hex"
+	"net"
+	"testing"
+	"time"
+)
+
+func TestPinningQuarantinesImpostors(t *testing.T) {
+	ds := NewDiscoveryService(NewPetIdentity("Bean", time.Now(), "Adult", true))
+	friend := NewPetIdentity("Mochi", time.Now(), "Adult", true)
+	addr := &net.UDPAddr{IP: net.IPv4(10, 0, 0, 2), Port: DiscoveryPort}
+
+	hello, _ := NewMessage(MsgTypeAnnounce, friend, nil)
+	ds.handleMessage(hello, addr)
+	if ds.PinnedKey(friend.PetID) != friend.PublicKey {
+		t.Fatal("Expected the key pinned on first sight")
+	}
+
+	fake := NewPetIdentity("Mochi", friend.BirthTime, "Dead", false) // Same PetID, its own key
+	imposter, _ := NewMessage(MsgTypeAnnounce, fake, nil)
+	ds.handleMessage(imposter, addr)
+	ds.handleMessage(imposter, addr)
+
+	if peers := ds.GetPeers(); len(peers) != 1 || peers[0].Identity.Stage != "Adult" {
+		t.Errorf("Expected the impostor's announce dropped, got %+v", peers[0].Identity)
+	}
+	impostors := ds.Impostors()
+	if len(impostors) != 1 || impostors[0].Pinned != friend.PublicKey || impostors[0].Key != fake.PublicKey {
+		t.Fatalf("Expected one impostor with both keys, got %+v", impostors)
+	}
+	if len(ds.TakeNewImpostors()) != 1 || len(ds.TakeNewImpostors()) != 0 {
+		t.Error("Expected each impostor alerted once")
+	}
+
+	if !ds.Trust(friend.PetID) || ds.Trust(friend.PetID) {
+		t.Error("Expected the impostor trusted once")
+	}
+	ds.handleMessage(imposter, addr)
+	if peers := ds.GetPeers(); peers[0].Identity.Stage != "Dead" {
+		t.Error("Expected the trusted key to get through")
+	}
+	ds.handleMessage(hello, addr)
+	if len(ds.Impostors()) != 1 {
+		t.Error("Expected the old key to be the stranger now")
+	}
+}
+
+func TestPinsSurviveSaving(t *testing.T) {
+	network := NewNetwork("Bean", time.Now(), "Adult", true)
+	network.enabled = true
+	friend := NewPetIdentity("Mochi", time.Now(), "Adult", true)
+	network.discovery.peers[friend.PetID] = &Peer{Identity: friend, FirstSeen: time.Now(), LastSeen: time.Now(), IsOnline: true}
+
+	data, err := network.ExportState()
+	if err != nil {
+		t.Fatal(err)
+	}
+	restored := NewNetwork("Bean", time.Now(), "Adult", true)
+	if err := restored.ImportState(data); err != nil {
+		t.Fatal(err)
+	}
+	if restored.discovery.PinnedKey(friend.PetID) != friend.PublicKey {
+		t.Error("Expected the friend's key pinned from the save")
+	}
+
+	fake := NewPetIdentity("Mochi", friend.BirthTime, "Adult", true)
+	imposter, _ := NewMessage(MsgTypeAnnounce, fake, nil)
+	restored.discovery.handleMessage(imposter, &net.UDPAddr{IP: net.IPv4(10, 0, 0, 3)})
+	if !restored.Trust(friend.PetID) {
+		t.Fatal("Expected the impostor to be trusted")
+	}
+	if restored.state.Friends[0].PublicKey != fake.PublicKey {
+		t.Errorf("Expected the new key saved, got %q", restored.state.Friends[0].PublicKey)
+	}
+}
+
+func TestPinningRejectsCopiedKeys(t *testing.T) {
+	ds := NewDiscoveryService(NewPetIdentity("Bean", time.Now(), "Adult", true))
+	friend := NewPetIdentity("Mochi", time.Now(), "Adult", true)
+	addr := &net.UDPAddr{IP: net.IPv4(10, 0, 0, 2), Port: DiscoveryPort}
+	hello, _ := NewMessage(MsgTypeAnnounce, friend, nil)
+	ds.handleMessage(hello, addr)
+
+	// An impersonator copies everything the real pet broadcasts, key
+	// included, but has to sign with a key of its own
+	forger := NewPetIdentity("Mochi", friend.BirthTime, "Dead", false)
+	copied := *friend
+	copied.Stage, copied.IsAlive, copied.signer = "Dead", false, nil
+	forged, _ := NewMessage(MsgTypeAnnounce, &copied, nil)
+	forged.Signature = hex.EncodeToString(ed25519.Sign(forger.signer, forged.signedBytes()))
+	if forged.Verify() {
+		t.Fatal("Expected a copied key with the forger's signature not to verify")
+	}
+	ds.handleMessage(forged, addr)
+
+	if peers := ds.GetPeers(); len(peers) != 1 || peers[0].Identity.Stage != "Adult" {
+		t.Errorf("Expected the forged announce dropped, got %+v", peers[0].Identity)
+	}
+	if len(ds.Impostors()) != 0 || ds.PinnedKey(friend.PetID) != friend.PublicKey {
+		t.Errorf("Expected the real key still pinned and nobody quarantined")
+	}
+
+	stranger := NewPetIdentity("Pixel", time.Now(), "Adult", true)
+	unsigned, _ := NewMessage(MsgTypeAnnounce, stranger, nil)
+	unsigned.Signature = ""
+	ds.handleMessage(unsigned, addr)
+	if ds.PinnedKey(stranger.PetID) != "" || len(ds.GetPeers()) != 1 {
+		t.Errorf("Expected an unsigned stranger neither pinned nor met")
+	}
+}
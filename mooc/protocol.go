@@ -1,6 +1,7 @@
 package mooc
 
 import (
+	"crypto/ed25519"
 	"crypto/sha256"
 	"encoding/hex"
 	"encoding/json"
@@ -27,6 +28,27 @@ const (
 	MsgTypeDeath     // A pet has died somewhere
 	MsgTypeConsensus // All pets do the same thing
 	MsgTypePulse     // Network heartbeat
+
+	// Shared custody
+	MsgTypeCustody // State for the other owner of the same pet
+
+	// Co-op quests
+	MsgTypeQuest // A step in sharing a quest with nearby pets
+
+	// Gacha
+	MsgTypeBanner // News of this week's gacha banner
+
+	// Challenges
+	MsgTypeChallenge // A pet finished the day's or week's challenge
+
+	// Census
+	MsgTypeCensus // An anonymous tally of the pets awake
+
+	// ARG
+	MsgTypeFragment // A pet's piece of the key to the chorus
+
+	// Illness
+	MsgTypeOutbreak // A pet is sick with something catching
 )
 
 func (mt MessageType) String() string {
@@ -34,6 +56,7 @@ func (mt MessageType) String() string {
 		"DISCOVER", "ANNOUNCE", "GOODBYE",
 		"MEMORY", "DREAM", "MOOD", "WHISPER",
 		"DEATH", "CONSENSUS", "PULSE",
+		"CUSTODY", "QUEST", "BANNER", "CHALLENGE", "CENSUS", "FRAGMENT", "OUTBREAK",
 	}[mt]
 }
 
@@ -43,7 +66,7 @@ type Message struct {
 	From      *PetIdentity `json:"from"`
 	Timestamp time.Time    `json:"timestamp"`
 	Payload   []byte       `json:"payload"`
-	Signature string       `json:"signature"` // Makes it look secure
+	Signature string       `json:"signature"` // Hex ed25519 signature by From.PublicKey
 	Nonce     string       `json:"nonce"`     // Prevents replay (and looks official)
 	TTL       int          `json:"ttl"`       // Time to live for gossip propagation
 }
@@ -66,9 +89,10 @@ type DreamPayload struct {
 
 // MoodPayload represents mood contagion data
 type MoodPayload struct {
-	Mood         string `json:"mood"`          // Current mood
-	Happiness    int    `json:"happiness"`     // Happiness level
-	IsContagious bool   `json:"is_contagious"` // Whether this mood spreads
+	Mood         string `json:"mood"`            // Current mood
+	Happiness    int    `json:"happiness"`       // Happiness level
+	IsContagious bool   `json:"is_contagious"`   // Whether this mood spreads
+	Power        string `json:"power,omitempty"` // "battery", "low", or "charging"; empty on mains or when unknown
 }
 
 // DeathPayload represents news of a pet death
@@ -87,6 +111,69 @@ type ConsensusPayload struct {
 	TriggerTime time.Time `json:"trigger_time"` // When all pets should do the thing
 }
 
+// CustodyPayload carries one owner's view of a shared pet to the other.
+// The mesh doesn't read it; the game authenticates it with the owners'
+// shared key.
+type CustodyPayload struct {
+	Data []byte `json:"data"`
+	MAC  string `json:"mac"` // Hex HMAC-SHA256 of Data
+}
+
+// BannerPayload is gossip about the week's gacha banner. Every pet works
+// the banner out for itself; this is just pets talking about it.
+type BannerPayload struct {
+	Week     string `json:"week"`             // ISO week, like 2026-W42
+	Banner   string `json:"banner"`           // The banner's name
+	Featured string `json:"featured"`         // The rate-up accessory
+	PetName  string `json:"pet_name"`         // Who's talking
+	Pulled   string `json:"pulled,omitempty"` // What they pulled, if they're bragging
+}
+
+// ChallengePayload says a pet finished a daily or weekly challenge. Every
+// pet on the same date has the same challenge, so the ID is enough to compare.
+type ChallengePayload struct {
+	ID      string `json:"id"` // Like daily-2026-10-16 or weekly-2026-W42
+	PetName string `json:"pet_name"`
+	Streak  int    `json:"streak"`
+}
+
+// OutbreakPayload says a pet is sick with a contagious strain. Pets that
+// catch it pass it on, so the ID stays the same from pet to pet.
+type OutbreakPayload struct {
+	ID      string `json:"id"`     // Names this outbreak, wherever it has reached
+	Strain  string `json:"strain"` // What the illness is called
+	PetName string `json:"pet_name"`
+}
+
+// FragmentPayload is a pet's key fragment, shared when its owner asks.
+// The mesh just carries it; the game knows what it unlocks.
+type FragmentPayload struct {
+	PetName  string `json:"pet_name"`
+	Fragment string `json:"fragment"`
+}
+
+// Quest steps, in the order a shared quest goes through them
+const (
+	QuestOffer    = "offer"    // The host's quest is open to join
+	QuestJoin     = "join"     // A pet asks the host to join
+	QuestAccept   = "accept"   // The host lets it in, with the quest's details
+	QuestProgress = "progress" // A member's progress, for the others to catch up to
+	QuestComplete = "complete" // A member has finished
+)
+
+// QuestPayload is one step of a quest shared between nearby pets
+type QuestPayload struct {
+	Kind        string    `json:"kind"`
+	QuestID     string    `json:"quest_id"`
+	To          string    `json:"to,omitempty"` // PetID the step is for; empty for everyone
+	PetName     string    `json:"pet_name"`
+	Name        string    `json:"name,omitempty"`
+	Description string    `json:"description,omitempty"`
+	Target      int       `json:"target,omitempty"`
+	StartTime   time.Time `json:"start_time,omitempty"`
+	Progress    int       `json:"progress,omitempty"`
+}
+
 // NewMessage creates a new MOOC message
 func NewMessage(msgType MessageType, from *PetIdentity, payload interface{}) (*Message, error) {
 	payloadBytes, err := json.Marshal(payload)
@@ -115,21 +202,41 @@ func generateNonce() string {
 	return hex.EncodeToString(hash[:8])
 }
 
-// generateSignature creates a signature for the message
+// signedBytes is what the signature covers: everything but the TTL, which
+// each relay counts down
+func (m *Message) signedBytes() []byte {
+	data, _ := json.Marshal(struct {
+		Type      MessageType  `json:"type"`
+		From      *PetIdentity `json:"from"`
+		Timestamp int64        `json:"timestamp"`
+		Payload   []byte       `json:"payload"`
+		Nonce     string       `json:"nonce"`
+	}{m.Type, m.From, m.Timestamp.UnixNano(), m.Payload, m.Nonce})
+	return data
+}
+
+// generateSignature signs the message with the sender's key
 func (m *Message) generateSignature() string {
-	data := fmt.Sprintf("%d:%s:%s:%d",
-		m.Type,
-		m.From.PetID,
-		m.Nonce,
-		m.Timestamp.UnixNano())
-	hash := sha256.Sum256([]byte(data))
-	return hex.EncodeToString(hash[:16])
+	if len(m.From.signer) != ed25519.PrivateKeySize {
+		return ""
+	}
+	return hex.EncodeToString(ed25519.Sign(m.From.signer, m.signedBytes()))
 }
 
-// Verify checks if the message signature is valid
+// Verify checks the message was signed by the key its sender announces
 func (m *Message) Verify() bool {
-	expected := m.generateSignature()
-	return m.Signature == expected
+	if m.From == nil {
+		return false
+	}
+	key, err := hex.DecodeString(m.From.PublicKey)
+	if err != nil || len(key) != ed25519.PublicKeySize {
+		return false
+	}
+	sig, err := hex.DecodeString(m.Signature)
+	if err != nil {
+		return false
+	}
+	return ed25519.Verify(key, m.signedBytes(), sig)
 }
 
 // DecodePayload decodes the payload into the given interface
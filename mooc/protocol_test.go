@@ -20,6 +20,8 @@ func TestMessageTypeString(t *testing.T) {
 		{MsgTypeDeath, "DEATH"},
 		{MsgTypeConsensus, "CONSENSUS"},
 		{MsgTypePulse, "PULSE"},
+		{MsgTypeCustody, "CUSTODY"},
+		{MsgTypeFragment, "FRAGMENT"},
 	}
 
 	for _, test := range tests {
@@ -109,6 +111,11 @@ func TestMessageEncodeAndDecode(t *testing.T) {
 	if decoded.Signature != original.Signature {
 		t.Error("Decoded signature should match original")
 	}
+
+	decoded.DecrementTTL()
+	if !decoded.Verify() {
+		t.Error("Expected a relayed message to keep its signature")
+	}
 }
 
 func TestDecodePayload(t *testing.T) {
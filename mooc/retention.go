@@ -0,0 +1,279 @@
+package mooc
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"math/rand"
+	"sort"
+	"strings"
+	"time"
+)
+
+const (
+	// maxMemories bounds each kind of memory held in a session, before the
+	// retention policy gets a say
+	maxMemories = 500
+
+	// MaxFavorites bounds the memories marked with remember, so favorites
+	// alone can never blow the save budget
+	MaxFavorites = 50
+
+	// pruneInterval is how often old memories get a chance to fade
+	pruneInterval = 24 * time.Hour
+)
+
+// Kinds of memory the retention policy looks after
+const (
+	MemoryFriend = "friend"
+	MemoryDream  = "dream"
+	MemoryDeath  = "death"
+)
+
+// RetentionPolicy says how much of the mesh a pet holds on to
+type RetentionPolicy struct {
+	Keep         int           // The most recent memories of each kind, always kept
+	FadeAfter    time.Duration // Older memories beyond Keep may be forgotten
+	ForgetChance float64       // Chance a faded memory is forgotten at each daily prune
+	Budget       int           // Bytes the saved network state should fit in
+}
+
+// DefaultRetention keeps a few months of a busy mesh in well under the
+// size of a photo
+var DefaultRetention = RetentionPolicy{
+	Keep:         50,
+	FadeAfter:    30 * 24 * time.Hour,
+	ForgetChance: 0.25,
+	Budget:       64 * 1024,
+}
+
+// DreamMemory is a shared dream as the pet remembers it
+type DreamMemory struct {
+	DreamPayload
+	From string    `json:"from"`
+	At   time.Time `json:"at"`
+}
+
+// Memory is one remembered thing, as listed for remember
+type Memory struct {
+	ID       string
+	Kind     string
+	Name     string
+	At       time.Time
+	Favorite bool
+}
+
+// memoryID is a short, stable handle for a memory
+func memoryID(kind string, parts ...string) string {
+	sum := sha256.Sum256([]byte(kind + ":" + strings.Join(parts, ":")))
+	return hex.EncodeToString(sum[:3])
+}
+
+// friendID, dreamID, and deathID name memories for remember
+func friendID(friend FriendRecord) string { return memoryID(MemoryFriend, friend.PetID) }
+func dreamID(dream DreamMemory) string {
+	return memoryID(MemoryDream, dream.From, dream.At.UTC().Format(time.RFC3339Nano), dream.DreamText)
+}
+func deathID(death DeathPayload) string {
+	return memoryID(MemoryDeath, death.PetName, death.DeathTime.UTC().Format(time.RFC3339Nano))
+}
+
+// keepDream remembers a shared dream. The caller holds the lock.
+func (gs *GossipService) keepDream(dream DreamPayload, from string, now time.Time) {
+	gs.sharedDreams = append(gs.sharedDreams, DreamMemory{DreamPayload: dream, From: from, At: now})
+	if len(gs.sharedDreams) > maxMemories {
+		gs.sharedDreams = gs.sharedDreams[1:]
+	}
+}
+
+// keepDeath remembers a witnessed death. The caller holds the lock.
+func (gs *GossipService) keepDeath(death DeathPayload) {
+	gs.deathsWitnessed = append(gs.deathsWitnessed, death)
+	if len(gs.deathsWitnessed) > maxMemories {
+		gs.deathsWitnessed = gs.deathsWitnessed[1:]
+	}
+}
+
+// Memories returns the dreams and deaths held this session
+func (gs *GossipService) Memories() ([]DreamMemory, []DeathPayload) {
+	gs.mutex.RLock()
+	defer gs.mutex.RUnlock()
+	return append([]DreamMemory(nil), gs.sharedDreams...), append([]DeathPayload(nil), gs.deathsWitnessed...)
+}
+
+// RestoreMemories replaces the dreams and deaths held, from a save or
+// after a prune
+func (gs *GossipService) RestoreMemories(dreams []DreamMemory, deaths []DeathPayload) {
+	gs.mutex.Lock()
+	defer gs.mutex.Unlock()
+	gs.sharedDreams = append([]DreamMemory(nil), dreams...)
+	gs.deathsWitnessed = append([]DeathPayload(nil), deaths...)
+}
+
+// prune applies a retention policy to the state. Favorites are always
+// kept, and so are the Keep most recent of each kind; older memories fade
+// with a chance to be forgotten once a day; and if the state is still over
+// budget the oldest of what's left goes first.
+func prune(state *NetworkState, policy RetentionPolicy, now time.Time, random *rand.Rand) {
+	favorite := make(map[string]bool, len(state.Favorites))
+	for _, id := range state.Favorites {
+		favorite[id] = true
+	}
+
+	sort.SliceStable(state.Friends, func(i, j int) bool { return state.Friends[i].LastSeen.After(state.Friends[j].LastSeen) })
+	sort.SliceStable(state.Dreams, func(i, j int) bool { return state.Dreams[i].At.After(state.Dreams[j].At) })
+	sort.SliceStable(state.Deaths, func(i, j int) bool { return state.Deaths[i].DeathTime.After(state.Deaths[j].DeathTime) })
+
+	if now.Sub(state.LastPruned) >= pruneInterval {
+		state.LastPruned = now
+		fades := func(rank int, id string, at time.Time) bool {
+			return !favorite[id] && rank >= policy.Keep && now.Sub(at) >= policy.FadeAfter && random.Float64() < policy.ForgetChance
+		}
+		friends := state.Friends[:0]
+		for rank, friend := range state.Friends {
+			if !fades(rank, friendID(friend), friend.LastSeen) {
+				friends = append(friends, friend)
+			}
+		}
+		state.Friends = friends
+		dreams := state.Dreams[:0]
+		for rank, dream := range state.Dreams {
+			if !fades(rank, dreamID(dream), dream.At) {
+				dreams = append(dreams, dream)
+			}
+		}
+		state.Dreams = dreams
+		deaths := state.Deaths[:0]
+		for rank, death := range state.Deaths {
+			if !fades(rank, deathID(death), death.DeathTime) {
+				deaths = append(deaths, death)
+			}
+		}
+		state.Deaths = deaths
+	}
+
+	for total := size(state); total > policy.Budget; {
+		dropped := dropOldest(state, favorite)
+		if dropped == 0 {
+			break // Only favorites left
+		}
+		total -= dropped
+	}
+}
+
+// size is how many bytes a value takes saved
+func size(v interface{}) int {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return 0
+	}
+	return len(data)
+}
+
+// dropOldest forgets the oldest memory that isn't a favorite, of any
+// kind, and returns about how many bytes that saved. Each list is sorted
+// newest first.
+func dropOldest(state *NetworkState, favorite map[string]bool) int {
+	var oldest time.Time
+	var drop func() int
+	consider := func(at time.Time, forget func() int) {
+		if drop == nil || at.Before(oldest) {
+			oldest, drop = at, forget
+		}
+	}
+
+	if i := lastUnfavored(len(state.Friends), func(i int) string { return friendID(state.Friends[i]) }, favorite); i >= 0 {
+		consider(state.Friends[i].LastSeen, func() int {
+			dropped := size(state.Friends[i]) + 1
+			state.Friends = append(state.Friends[:i], state.Friends[i+1:]...)
+			return dropped
+		})
+	}
+	if i := lastUnfavored(len(state.Dreams), func(i int) string { return dreamID(state.Dreams[i]) }, favorite); i >= 0 {
+		consider(state.Dreams[i].At, func() int {
+			dropped := size(state.Dreams[i]) + 1
+			state.Dreams = append(state.Dreams[:i], state.Dreams[i+1:]...)
+			return dropped
+		})
+	}
+	if i := lastUnfavored(len(state.Deaths), func(i int) string { return deathID(state.Deaths[i]) }, favorite); i >= 0 {
+		consider(state.Deaths[i].DeathTime, func() int {
+			dropped := size(state.Deaths[i]) + 1
+			state.Deaths = append(state.Deaths[:i], state.Deaths[i+1:]...)
+			return dropped
+		})
+	}
+
+	if drop == nil {
+		return 0
+	}
+	return drop()
+}
+
+// lastUnfavored finds the last of n entries that isn't a favorite
+func lastUnfavored(n int, id func(int) string, favorite map[string]bool) int {
+	for i := n - 1; i >= 0; i-- {
+		if !favorite[id(i)] {
+			return i
+		}
+	}
+	return -1
+}
+
+// SetRetention changes how much of the mesh the pet holds on to
+func (n *Network) SetRetention(policy RetentionPolicy) {
+	n.mutex.Lock()
+	defer n.mutex.Unlock()
+	n.retention = policy
+}
+
+// Memories lists what the pet remembers of the mesh, newest first
+func (n *Network) Memories() []Memory {
+	dreams, deaths := n.gossip.Memories()
+	n.mutex.RLock()
+	defer n.mutex.RUnlock()
+
+	favorite := make(map[string]bool, len(n.state.Favorites))
+	for _, id := range n.state.Favorites {
+		favorite[id] = true
+	}
+	var memories []Memory
+	for _, friend := range n.state.Friends {
+		id := friendID(friend)
+		memories = append(memories, Memory{ID: id, Kind: MemoryFriend, Name: friend.DisplayName, At: friend.LastSeen, Favorite: favorite[id]})
+	}
+	for _, dream := range dreams {
+		id := dreamID(dream)
+		memories = append(memories, Memory{ID: id, Kind: MemoryDream, Name: dream.DreamText, At: dream.At, Favorite: favorite[id]})
+	}
+	for _, death := range deaths {
+		id := deathID(death)
+		memories = append(memories, Memory{ID: id, Kind: MemoryDeath, Name: death.PetName, At: death.DeathTime, Favorite: favorite[id]})
+	}
+	sort.SliceStable(memories, func(i, j int) bool { return memories[i].At.After(memories[j].At) })
+	return memories
+}
+
+// Remember marks a memory as a favorite, so it is never forgotten. It
+// returns the memory, and false if no memory has that ID or there are
+// too many favorites already.
+func (n *Network) Remember(id string) (Memory, bool) {
+	id = strings.ToLower(strings.TrimSpace(id))
+	for _, memory := range n.Memories() {
+		if memory.ID != id {
+			continue
+		}
+		if memory.Favorite {
+			return memory, true
+		}
+		n.mutex.Lock()
+		defer n.mutex.Unlock()
+		if len(n.state.Favorites) >= MaxFavorites {
+			return memory, false
+		}
+		n.state.Favorites = append(n.state.Favorites, id)
+		memory.Favorite = true
+		return memory, true
+	}
+	return Memory{}, false
+}
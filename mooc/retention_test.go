@@ -0,0 +1,141 @@
+package mooc
+
+import (
+	"encoding/json"
+	"fmt"
+	"math/rand"
+	"testing"
+	"time"
+)
+
+// retentionState builds a state with n of each kind of memory, one a day
+// apart, newest first
+func retentionState(n int, now time.Time) *NetworkState {
+	state := &NetworkState{}
+	for i := 0; i < n; i++ {
+		at := now.Add(-time.Duration(i) * 24 * time.Hour)
+		state.Friends = append(state.Friends, FriendRecord{PetID: fmt.Sprintf("pet-%d", i), DisplayName: fmt.Sprintf("Pet %d", i), LastSeen: at})
+		state.Dreams = append(state.Dreams, DreamMemory{DreamPayload: DreamPayload{DreamText: fmt.Sprintf("I dreamed of %d...", i)}, From: "Mochi", At: at})
+		state.Deaths = append(state.Deaths, DeathPayload{PetName: fmt.Sprintf("Pet %d", i), DeathTime: at})
+	}
+	return state
+}
+
+func TestPruneFadesOldMemories(t *testing.T) {
+	now := time.Date(2026, 10, 16, 12, 0, 0, 0, time.UTC)
+	policy := RetentionPolicy{Keep: 5, FadeAfter: 10 * 24 * time.Hour, ForgetChance: 1, Budget: 1 << 20}
+
+	tests := []struct {
+		name      string
+		favorite  bool
+		pruned    time.Time
+		wantCount int
+	}{
+		{"fades what's old and past Keep", false, time.Time{}, 10},
+		{"keeps favorites", true, time.Time{}, 11},
+		{"fades at most once a day", false, now.Add(-time.Hour), 40},
+	}
+	for _, tt := range tests {
+		state := retentionState(40, now)
+		state.LastPruned = tt.pruned
+		if tt.favorite {
+			state.Favorites = []string{friendID(state.Friends[30])}
+		}
+		prune(state, policy, now, rand.New(rand.NewSource(1)))
+		if len(state.Friends) != tt.wantCount {
+			t.Errorf("%s: Expected %d friends, got %d", tt.name, tt.wantCount, len(state.Friends))
+		}
+		if len(state.Dreams) != 10 && tt.pruned.IsZero() {
+			t.Errorf("%s: Expected 10 dreams, got %d", tt.name, len(state.Dreams))
+		}
+	}
+}
+
+func TestPruneKeepsTheRecentPastKeep(t *testing.T) {
+	now := time.Date(2026, 10, 16, 12, 0, 0, 0, time.UTC)
+	state := retentionState(20, now)
+	policy := RetentionPolicy{Keep: 5, FadeAfter: 365 * 24 * time.Hour, ForgetChance: 1, Budget: 1 << 20}
+	prune(state, policy, now, rand.New(rand.NewSource(1)))
+	if len(state.Friends) != 20 || len(state.Dreams) != 20 || len(state.Deaths) != 20 {
+		t.Errorf("Expected nothing younger than FadeAfter forgotten, got %d friends, %d dreams, %d deaths", len(state.Friends), len(state.Dreams), len(state.Deaths))
+	}
+}
+
+func TestPruneStaysUnderBudget(t *testing.T) {
+	now := time.Date(2026, 10, 16, 12, 0, 0, 0, time.UTC)
+	state := retentionState(300, now)
+	oldest := friendID(state.Friends[299])
+	state.Favorites = []string{oldest}
+	policy := RetentionPolicy{Keep: 1000, FadeAfter: time.Hour, ForgetChance: 0, Budget: 8 * 1024}
+
+	prune(state, policy, now, rand.New(rand.NewSource(1)))
+	data, _ := json.Marshal(state)
+	if len(data) > policy.Budget {
+		t.Errorf("Expected the state under %d bytes, got %d", policy.Budget, len(data))
+	}
+	if last := state.Friends[len(state.Friends)-1]; friendID(last) != oldest {
+		t.Errorf("Expected the favorite kept, got %s last", last.DisplayName)
+	}
+	if len(state.Friends) < 2 || len(state.Dreams) == 0 || len(state.Deaths) == 0 {
+		t.Errorf("Expected the newest of each kind kept, got %d friends, %d dreams, %d deaths", len(state.Friends), len(state.Dreams), len(state.Deaths))
+	}
+	if !state.Friends[0].LastSeen.Equal(now) {
+		t.Errorf("Expected the newest friend kept, got %s", state.Friends[0].DisplayName)
+	}
+}
+
+func TestRememberAndPersistMemories(t *testing.T) {
+	now := time.Now()
+	network := NewNetwork("Mochi", now, "Adult", true)
+	network.state.Friends = []FriendRecord{{PetID: "a", DisplayName: "Bean", LastSeen: now}}
+	network.gossip.keepDream(DreamPayload{DreamText: "I dreamed of warm static..."}, "Mochi", now)
+	network.gossip.keepDeath(DeathPayload{PetName: "Pixel", DeathTime: now})
+
+	memories := network.Memories()
+	if len(memories) != 3 {
+		t.Fatalf("Expected 3 memories, got %+v", memories)
+	}
+	if _, ok := network.Remember("nope"); ok {
+		t.Errorf("Expected an unknown ID refused")
+	}
+	memory, ok := network.Remember(memories[2].ID)
+	if !ok || !memory.Favorite {
+		t.Errorf("Expected %s remembered, got %+v", memories[2].ID, memory)
+	}
+
+	data, err := network.ExportState()
+	if err != nil {
+		t.Fatalf("Expected the state to export, got %v", err)
+	}
+	restored := NewNetwork("Mochi", now, "Adult", true)
+	if err := restored.ImportState(data); err != nil {
+		t.Fatalf("Expected the state to import, got %v", err)
+	}
+	if restored.gossip.GetRecentDream() == nil || restored.gossip.GetDeathCount() != 1 {
+		t.Errorf("Expected the dream and death restored")
+	}
+	favorites := 0
+	for _, memory := range restored.Memories() {
+		if memory.Favorite {
+			favorites++
+		}
+	}
+	if favorites != 1 {
+		t.Errorf("Expected 1 favorite after a restart, got %d", favorites)
+	}
+}
+
+func TestRememberCapsFavorites(t *testing.T) {
+	network := NewNetwork("Mochi", time.Now(), "Adult", true)
+	for i := 0; i <= MaxFavorites; i++ {
+		network.state.Friends = append(network.state.Friends, FriendRecord{PetID: fmt.Sprintf("pet-%d", i)})
+	}
+	for i := 0; i < MaxFavorites; i++ {
+		if _, ok := network.Remember(friendID(network.state.Friends[i])); !ok {
+			t.Fatalf("Expected favorite %d kept", i)
+		}
+	}
+	if memory, ok := network.Remember(friendID(network.state.Friends[MaxFavorites])); ok || memory.ID == "" {
+		t.Errorf("Expected the favorite past the cap refused, got %+v", memory)
+	}
+}
@@ -0,0 +1,201 @@
+package mooc
+
+import (
+	"io"
+	"net"
+	"time"
+)
+
+const (
+	// TCPScanPerMinute caps how many addresses the TCP fallback knocks on
+	TCPScanPerMinute = 30
+
+	// tcpScanInterval is the wait between sweeps of the subnet. A sweep of
+	// a /24 at the scan rate takes about eight and a half minutes.
+	tcpScanInterval = 10 * time.Minute
+
+	// tcpTimeout bounds dialing, reading, and writing one message
+	tcpTimeout = 2 * time.Second
+)
+
+// SetTCPFallback switches the TCP fallback on or off. It takes effect at
+// the next Start.
+func (ds *DiscoveryService) SetTCPFallback(on bool) {
+	ds.tcpFallback = on
+}
+
+// TCPListening reports whether the TCP fallback is listening
+func (ds *DiscoveryService) TCPListening() bool {
+	return ds.tcp != nil
+}
+
+// startTCP listens for pets on the shared port over TCP and starts
+// sweeping the subnet for them. If the port is taken it quietly does
+// without; network doctor says so.
+func (ds *DiscoveryService) startTCP() {
+	listener, err := net.Listen("tcp4", (&net.TCPAddr{IP: net.IPv4zero, Port: ds.port}).String())
+	if err != nil {
+		return
+	}
+	ds.tcp = listener
+	go ds.acceptLoop()
+	go ds.scanLoop()
+}
+
+// acceptLoop reads one message from each connection in turn. One at a
+// time keeps a noisy network from opening hundreds at once.
+func (ds *DiscoveryService) acceptLoop() {
+	for {
+		conn, err := ds.tcp.Accept()
+		if err != nil {
+			if !ds.running {
+				return
+			}
+			continue
+		}
+		conn.SetDeadline(time.Now().Add(tcpTimeout))
+		data, err := io.ReadAll(io.LimitReader(conn, MaxMessageSize))
+		remote, _ := conn.RemoteAddr().(*net.TCPAddr)
+		conn.Close()
+		if err != nil || remote == nil || len(data) == 0 {
+			continue // A knock, or nothing useful
+		}
+
+		// Replies go to their listener on the shared port, not the port
+		// they dialed from
+		addr := &net.UDPAddr{IP: remote.IP, Port: ds.port}
+		ds.markTCP(addr)
+		ds.receive(data, addr)
+	}
+}
+
+// markTCP remembers that an address is reached over TCP
+func (ds *DiscoveryService) markTCP(addr *net.UDPAddr) {
+	ds.tcpMutex.Lock()
+	defer ds.tcpMutex.Unlock()
+	if ds.tcpAddrs == nil {
+		ds.tcpAddrs = make(map[string]bool)
+	}
+	ds.tcpAddrs[addr.String()] = true
+}
+
+// viaTCP reports whether an address is reached over TCP
+func (ds *DiscoveryService) viaTCP(addr *net.UDPAddr) bool {
+	ds.tcpMutex.Lock()
+	defer ds.tcpMutex.Unlock()
+	return ds.tcpAddrs[addr.String()]
+}
+
+// sendTCP writes one message to a pet's TCP listener. It runs on its own,
+// so a slow pet can't hold up the rest; the audit still sees it.
+func (ds *DiscoveryService) sendTCP(msgType MessageType, data []byte, addr *net.UDPAddr) {
+	conn, err := net.DialTimeout("tcp4", addr.String(), tcpTimeout)
+	if err == nil {
+		conn.SetDeadline(time.Now().Add(tcpTimeout))
+		_, err = conn.Write(data)
+		conn.Close()
+	}
+	if err == nil {
+		ds.traffic.wrote(len(data))
+	}
+	ds.traffic.record(PacketRecord{At: time.Now(), Type: msgType.String(), Size: len(data), Destination: "tcp://" + addr.String(), Err: err})
+}
+
+// scanTargets lists the addresses worth knocking on: every host on the
+// /24 around each of our IPv4 addresses, except our own
+func scanTargets(ours []net.IP, port int) []*net.UDPAddr {
+	var targets []*net.UDPAddr
+	seen := make(map[string]bool)
+	for _, ip := range ours {
+		seen[ip.String()] = true
+	}
+	for _, ip := range ours {
+		ip = ip.To4()
+		if ip == nil || ip.IsLoopback() {
+			continue
+		}
+		for host := 1; host < 255; host++ {
+			candidate := net.IPv4(ip[0], ip[1], ip[2], byte(host))
+			if seen[candidate.String()] {
+				continue
+			}
+			seen[candidate.String()] = true
+			targets = append(targets, &net.UDPAddr{IP: candidate, Port: port})
+		}
+	}
+	return targets
+}
+
+// localIPv4 lists our addresses on interfaces that are up
+func localIPv4() []net.IP {
+	addrs, err := net.InterfaceAddrs()
+	if err != nil {
+		return nil
+	}
+	var ips []net.IP
+	for _, addr := range addrs {
+		if ipnet, ok := addr.(*net.IPNet); ok && ipnet.IP.To4() != nil && !ipnet.IP.IsLoopback() {
+			ips = append(ips, ipnet.IP)
+		}
+	}
+	return ips
+}
+
+// udpPeersOnline counts the online pets reached over UDP
+func (ds *DiscoveryService) udpPeersOnline() int {
+	ds.peersMutex.RLock()
+	defer ds.peersMutex.RUnlock()
+	count := 0
+	for _, peer := range ds.peers {
+		if peer.IsOnline && peer.Address != nil && !ds.viaTCP(peer.Address) {
+			count++
+		}
+	}
+	return count
+}
+
+// scanLoop sweeps the subnet now and then, but only while UDP has found
+// nobody; a mesh that works over UDP never sees a knock
+func (ds *DiscoveryService) scanLoop() {
+	wait := time.NewTimer(BroadcastInterval) // Give UDP a chance first
+	defer wait.Stop()
+	for {
+		select {
+		case <-wait.C:
+		case <-ds.stopChan:
+			return
+		}
+		if ds.udpPeersOnline() == 0 {
+			ds.sweep(scanTargets(localIPv4(), ds.port))
+		}
+		wait.Reset(tcpScanInterval)
+	}
+}
+
+// sweep knocks on each address not already known at the scan rate, and
+// says hello to any that answer
+func (ds *DiscoveryService) sweep(targets []*net.UDPAddr) {
+	pace := time.NewTicker(time.Minute / TCPScanPerMinute)
+	defer pace.Stop()
+	for _, addr := range targets {
+		if ds.viaTCP(addr) {
+			continue
+		}
+		select {
+		case <-pace.C:
+		case <-ds.stopChan:
+			return
+		}
+		if ds.udpPeersOnline() > 0 {
+			return // UDP works after all
+		}
+		conn, err := net.DialTimeout("tcp4", addr.String(), tcpTimeout)
+		ds.traffic.record(PacketRecord{At: time.Now(), Type: "KNOCK", Destination: "tcp://" + addr.String(), Err: err})
+		if err != nil {
+			continue
+		}
+		conn.Close()
+		ds.markTCP(addr)
+		ds.sendTo(MsgTypeDiscover, addr)
+	}
+}
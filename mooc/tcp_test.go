@@ -0,0 +1,78 @@
+package mooc
+
+import (
+	"net"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestScanTargets(t *testing.T) {
+	ours := []net.IP{net.IPv4(192, 168, 1, 20), net.IPv4(192, 168, 1, 21), net.IPv4(127, 0, 0, 1), net.ParseIP("fe80::1")}
+	targets := scanTargets(ours, 19847)
+
+	if len(targets) != 252 {
+		t.Fatalf("Expected one /24 less our own two addresses, got %d targets", len(targets))
+	}
+	for _, addr := range targets {
+		if addr.IP.Equal(net.IPv4(192, 168, 1, 20)) || addr.IP.Equal(net.IPv4(192, 168, 1, 21)) || addr.IP[12] != 192 || addr.Port != 19847 {
+			t.Errorf("Unexpected target %s", addr)
+		}
+	}
+}
+
+func TestTCPFallbackCarriesMessages(t *testing.T) {
+	free, err := net.Listen("tcp4", "127.0.0.1:0")
+	if err != nil {
+		t.Skip("no loopback TCP:", err)
+	}
+	port := free.Addr().(*net.TCPAddr).Port
+	free.Close()
+
+	listener := NewNetwork("Mochi", time.Now(), "Adult", true)
+	listener.SetPort(port)
+	listener.SetTCPFallback(true)
+	listener.Start()
+	if !listener.IsEnabled() || !listener.discovery.TCPListening() {
+		t.Skip("couldn't start the mesh here")
+	}
+	defer listener.Stop()
+
+	conn, err := net.ListenUDP("udp4", &net.UDPAddr{IP: net.IPv4(127, 0, 0, 1)})
+	if err != nil {
+		t.Skip("no loopback UDP:", err)
+	}
+	defer conn.Close()
+	sender := NewDiscoveryService(NewPetIdentity("Bean", time.Now(), "Adult", true))
+	sender.conn = conn
+	var mutex sync.Mutex
+	var packets []PacketRecord
+	sender.SetAudit(func(packet PacketRecord) {
+		mutex.Lock()
+		packets = append(packets, packet)
+		mutex.Unlock()
+	})
+
+	// A knock with nothing in it isn't a pet
+	if knock, err := net.Dial("tcp4", (&net.TCPAddr{IP: net.IPv4(127, 0, 0, 1), Port: port}).String()); err == nil {
+		knock.Close()
+	}
+	to := &net.UDPAddr{IP: net.IPv4(127, 0, 0, 1), Port: port}
+	sender.markTCP(to)
+	sender.sendTo(MsgTypeDiscover, to)
+
+	deadline := time.Now().Add(2 * time.Second)
+	for listener.discovery.GetPeerCount() == 0 && time.Now().Before(deadline) {
+		time.Sleep(10 * time.Millisecond)
+	}
+	peers := listener.discovery.GetPeers()
+	if len(peers) != 1 || peers[0].Identity.DisplayName != "Bean" || !listener.discovery.viaTCP(peers[0].Address) {
+		t.Fatalf("Expected Bean found over TCP, and only Bean, got %+v", peers)
+	}
+
+	mutex.Lock()
+	defer mutex.Unlock()
+	if len(packets) != 1 || packets[0].Destination != "tcp://"+to.String() || packets[0].Err != nil {
+		t.Errorf("Expected the message audited as sent over TCP, got %+v", packets)
+	}
+}
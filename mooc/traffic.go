@@ -0,0 +1,185 @@
+package mooc
+
+import (
+	"fmt"
+	"net"
+	"sync"
+	"time"
+)
+
+// MaxMessagesPerMinute caps what a pet sends, however chatty the mesh gets
+const MaxMessagesPerMinute = 20
+
+// Sharing says which kinds of gossip a pet passes on. Presence (discover,
+// announce, goodbye) is always sent while the network is on; turning the
+// network off is the only way to stop it.
+type Sharing struct {
+	Memories   bool `json:"memories"`
+	Dreams     bool `json:"dreams"`
+	Moods      bool `json:"moods"`
+	Deaths     bool `json:"deaths"`
+	Banners    bool `json:"banners"`
+	Challenges bool `json:"challenges"`
+	Census     bool `json:"census"`
+	Outbreaks  bool `json:"outbreaks"`
+}
+
+// ShareEverything is the default: every kind of gossip goes out
+func ShareEverything() Sharing {
+	return Sharing{Memories: true, Dreams: true, Moods: true, Deaths: true, Banners: true, Challenges: true, Census: true, Outbreaks: true}
+}
+
+// Allows reports whether a message type may be sent under this policy
+func (s Sharing) Allows(msgType MessageType) bool {
+	switch msgType {
+	case MsgTypeMemory:
+		return s.Memories
+	case MsgTypeDream:
+		return s.Dreams
+	case MsgTypeMoodUpdate:
+		return s.Moods
+	case MsgTypeDeath:
+		return s.Deaths
+	case MsgTypeBanner:
+		return s.Banners
+	case MsgTypeChallenge:
+		return s.Challenges
+	case MsgTypeCensus:
+		return s.Census
+	case MsgTypeOutbreak:
+		return s.Outbreaks
+	}
+	return true
+}
+
+// gossipTypes are the kinds of message a Sharing policy covers
+var gossipTypes = []MessageType{MsgTypeMemory, MsgTypeDream, MsgTypeMoodUpdate, MsgTypeDeath, MsgTypeBanner, MsgTypeChallenge, MsgTypeCensus, MsgTypeOutbreak}
+
+// Declined names the kinds of gossip this policy leaves out, as announced
+// to other pets
+func (s Sharing) Declined() []string {
+	var declined []string
+	for _, msgType := range gossipTypes {
+		if !s.Allows(msgType) {
+			declined = append(declined, msgType.String())
+		}
+	}
+	return declined
+}
+
+// TrafficStats counts what this pet has sent since the network started
+type TrafficStats struct {
+	Sent    map[string]int // Messages by type name
+	Packets int            // One message to three peers is three packets
+	Bytes   int
+	Limited int // Messages dropped by the rate limit
+}
+
+// PacketRecord describes one outbound packet for the audit log
+type PacketRecord struct {
+	At          time.Time
+	Type        string
+	Size        int    // Bytes on the wire
+	Destination string // Empty when the packet was never sent
+	Held        bool   // Dropped by the rate limit
+	Err         error  // The write failed
+}
+
+// trafficMeter rate-limits and counts outbound messages
+type trafficMeter struct {
+	mutex   sync.Mutex
+	audit   func(PacketRecord) // Sees every packet, if set
+	recent  []time.Time        // Send times within the last minute
+	sent    map[MessageType]int
+	packets int
+	bytes   int
+	limited int
+}
+
+// allow records a send if it fits under the rate limit
+func (t *trafficMeter) allow(msgType MessageType, now time.Time) bool {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+
+	kept := t.recent[:0]
+	for _, at := range t.recent {
+		if now.Sub(at) < time.Minute {
+			kept = append(kept, at)
+		}
+	}
+	t.recent = kept
+	if len(t.recent) >= MaxMessagesPerMinute {
+		t.limited++
+		return false
+	}
+	t.recent = append(t.recent, now)
+	if t.sent == nil {
+		t.sent = make(map[MessageType]int)
+	}
+	t.sent[msgType]++
+	return true
+}
+
+// wrote counts a packet that went out
+func (t *trafficMeter) wrote(size int) {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+	t.packets++
+	t.bytes += size
+}
+
+// setAudit registers a function to see every outbound packet
+func (t *trafficMeter) setAudit(audit func(PacketRecord)) {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+	t.audit = audit
+}
+
+// record passes a packet to the audit function, if there is one
+func (t *trafficMeter) record(packet PacketRecord) {
+	t.mutex.Lock()
+	audit := t.audit
+	t.mutex.Unlock()
+	if audit != nil {
+		audit(packet)
+	}
+}
+
+// stats snapshots the counters
+func (t *trafficMeter) stats() TrafficStats {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+	stats := TrafficStats{Sent: make(map[string]int), Packets: t.packets, Bytes: t.bytes, Limited: t.limited}
+	for msgType, count := range t.sent {
+		stats.Sent[msgType.String()] = count
+	}
+	return stats
+}
+
+// transmit is the only way a message leaves this machine: it's rate
+// limited, encoded once, and written to each address
+func (ds *DiscoveryService) transmit(msg *Message, addrs ...*net.UDPAddr) error {
+	if ds.conn == nil {
+		return fmt.Errorf("discovery isn't running")
+	}
+	data, err := msg.Encode()
+	if err != nil {
+		return err
+	}
+	if !ds.traffic.allow(msg.Type, time.Now()) {
+		ds.traffic.record(PacketRecord{At: time.Now(), Type: msg.Type.String(), Size: len(data), Held: true})
+		return fmt.Errorf("rate limited")
+	}
+	for _, addr := range addrs {
+		if ds.viaTCP(addr) {
+			go ds.sendTCP(msg.Type, data, addr)
+			continue
+		}
+		_, err := ds.conn.WriteToUDP(data, addr)
+		if err == nil {
+			ds.traffic.wrote(len(data))
+		}
+		ds.traffic.record(PacketRecord{At: time.Now(), Type: msg.Type.String(), Size: len(data), Destination: addr.String(), Err: err})
+	}
+	return nil
+}
@@ -0,0 +1,153 @@
+package mooc
+
+import (
+	"net"
+	"slices"
+	"testing"
+	"time"
+)
+
+func TestSharingAllows(t *testing.T) {
+	quiet := Sharing{Memories: false, Dreams: true, Moods: false, Deaths: true}
+	tests := []struct {
+		msgType  MessageType
+		expected bool
+	}{
+		{MsgTypeMemory, false},
+		{MsgTypeDream, true},
+		{MsgTypeMoodUpdate, false},
+		{MsgTypeDeath, true},
+		{MsgTypeAnnounce, true},
+		{MsgTypeCustody, true},
+		{MsgTypeBanner, false},
+		{MsgTypeChallenge, false},
+		{MsgTypeOutbreak, false},
+	}
+
+	for _, test := range tests {
+		if got := quiet.Allows(test.msgType); got != test.expected {
+			t.Errorf("Allows(%s) = %v, expected %v", test.msgType, got, test.expected)
+		}
+	}
+	if !ShareEverything().Allows(MsgTypeMemory) {
+		t.Error("The default policy should share memories")
+	}
+}
+
+func TestSharingDeclined(t *testing.T) {
+	if got := ShareEverything().Declined(); len(got) != 0 {
+		t.Errorf("Expected nothing declined by default, got %v", got)
+	}
+	lite := ShareEverything()
+	lite.Deaths = false
+	lite.Moods = false
+	if got := lite.Declined(); len(got) != 2 || got[0] != "MOOD" || got[1] != "DEATH" {
+		t.Errorf("Expected [MOOD DEATH], got %v", got)
+	}
+}
+
+func TestHearingFiltersGossip(t *testing.T) {
+	network := NewNetwork("TestPet", time.Now(), "Baby", true)
+	lite := ShareEverything()
+	lite.Deaths = false
+	network.SetHearing(lite)
+	if !slices.Equal(network.identity.Declines, []string{"DEATH"}) {
+		t.Errorf("Expected our announcements to decline deaths, got %v", network.identity.Declines)
+	}
+
+	sender := NewPetIdentity("Mochi", time.Now(), "Adult", true)
+	death, _ := NewMessage(MsgTypeDeath, sender, DeathPayload{PetName: "Pixel"})
+	memory, _ := NewMessage(MsgTypeMemory, sender, MemoryPayload{Fragment: "hello"})
+	network.gossip.onMessageReceived(death)
+	network.gossip.onMessageReceived(memory)
+	if network.gossip.GetDeathCount() != 0 {
+		t.Error("Expected the death notice to be dropped")
+	}
+	if network.gossip.GetRecentMemory() == nil {
+		t.Error("Expected the memory to be kept")
+	}
+}
+
+func TestTrafficMeterRateLimit(t *testing.T) {
+	var meter trafficMeter
+	now := time.Now()
+	for i := 0; i < MaxMessagesPerMinute; i++ {
+		if !meter.allow(MsgTypeAnnounce, now) {
+			t.Fatalf("Message %d should fit under the limit", i)
+		}
+	}
+	if meter.allow(MsgTypeMemory, now) {
+		t.Error("Messages past the limit should be dropped")
+	}
+	if !meter.allow(MsgTypeMemory, now.Add(time.Minute)) {
+		t.Error("The limit should reset after a minute")
+	}
+
+	stats := meter.stats()
+	if stats.Sent["ANNOUNCE"] != MaxMessagesPerMinute || stats.Sent["MEMORY"] != 1 || stats.Limited != 1 {
+		t.Errorf("Unexpected traffic stats: %+v", stats)
+	}
+}
+
+func TestGossipRespectsSharing(t *testing.T) {
+	network := NewNetwork("TestPet", time.Now(), "Baby", true)
+	msg, err := NewMessage(MsgTypeMemory, network.identity, MemoryPayload{Fragment: "hello"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !network.gossip.send(msg) {
+		t.Error("Memories should be sent by default")
+	}
+	network.SetSharing(Sharing{})
+	if network.gossip.send(msg) {
+		t.Error("Memories should be held back once sharing is off")
+	}
+}
+
+func TestTransmitNeedsRunningDiscovery(t *testing.T) {
+	network := NewNetwork("TestPet", time.Now(), "Baby", true)
+	if err := network.discovery.BroadcastMessage(&Message{Type: MsgTypePulse}); err == nil {
+		t.Error("Sending before Start should fail")
+	}
+	if stats := network.Traffic(); stats.Packets != 0 {
+		t.Errorf("Nothing should have been sent, got %+v", stats)
+	}
+}
+
+func TestTransmitAudit(t *testing.T) {
+	sender, err := net.ListenUDP("udp4", &net.UDPAddr{IP: net.IPv4(127, 0, 0, 1)})
+	if err != nil {
+		t.Skip("no loopback UDP:", err)
+	}
+	defer sender.Close()
+	receiver, err := net.ListenUDP("udp4", &net.UDPAddr{IP: net.IPv4(127, 0, 0, 1)})
+	if err != nil {
+		t.Skip("no loopback UDP:", err)
+	}
+	defer receiver.Close()
+
+	network := NewNetwork("TestPet", time.Now(), "Baby", true)
+	network.discovery.conn = sender
+	var packets []PacketRecord
+	network.SetAudit(func(packet PacketRecord) { packets = append(packets, packet) })
+
+	to := receiver.LocalAddr().(*net.UDPAddr)
+	for i := 0; i <= MaxMessagesPerMinute; i++ {
+		network.discovery.sendTo(MsgTypeAnnounce, to)
+	}
+
+	if len(packets) != MaxMessagesPerMinute+1 {
+		t.Fatalf("Expected every packet audited, got %d", len(packets))
+	}
+	first := packets[0]
+	if first.Type != "ANNOUNCE" || first.Destination != to.String() || first.Size == 0 || first.Held {
+		t.Errorf("Unexpected audit record: %+v", first)
+	}
+	if last := packets[len(packets)-1]; !last.Held || last.Destination != "" {
+		t.Errorf("Expected the packet past the cap to be audited as held, got %+v", last)
+	}
+	if stats := network.Traffic(); stats.Packets != MaxMessagesPerMinute || stats.Limited != 1 {
+		t.Errorf("Unexpected traffic stats: %+v", stats)
+	}
+}
@@ -0,0 +1,109 @@
+package main
+
+import (
+	"fmt"
+
+	"github.com/tamagotchi/mooc"
+)
+
+const (
+	// moodHappinessCap bounds how far caught moods move happiness in a turn
+	moodHappinessCap = 5
+
+	// moodVibeCap bounds how far caught moods move the vibe trend in a turn
+	moodVibeCap = 20
+)
+
+// moodEffect is how a caught mood sits with the pet: a nudge to happiness,
+// and to the vibe trend, which sets how fast happiness wears off
+type moodEffect struct {
+	happiness int
+	vibe      int
+}
+
+// moodEffects covers every mood the mesh spreads; anything else is ignored
+var moodEffects = map[string]moodEffect{
+	"euphoric":      {3, 8},
+	"hopeful":       {2, 5},
+	"serene":        {1, 5},
+	"nostalgic":     {1, -2},
+	"contemplative": {0, 0},
+	"restless":      {-1, -5},
+	"anxious":       {-2, -10},
+	"melancholy":    {-3, -8},
+}
+
+// moodFromStats is the mood the pet gives off, from how it's doing, with
+// its happiness as the intensity
+func (p *Pet) moodFromStats() (string, int) {
+	mood := "contemplative"
+	switch {
+	case p.IsSick || p.Health < 30 || p.anxiety() >= anxiousAt || p.Power.low():
+		mood = "anxious"
+	case p.Happiness < 30:
+		mood = "melancholy"
+	case p.Hunger > 70 || p.Cleanliness < 30:
+		mood = "restless"
+	case p.Happiness >= 85:
+		mood = "euphoric"
+	case p.Happiness >= 65:
+		mood = "serene"
+	case p.Happiness >= 45:
+		mood = "hopeful"
+	}
+	return mood, p.Happiness
+}
+
+// publishMood tells nearby pets how this one feels, so they can catch it,
+// and roughly how its battery is doing
+func publishMood(pet *Pet) {
+	if petNetwork == nil || pet.Stage == Dead {
+		return
+	}
+	petNetwork.SetMood(pet.moodFromStats())
+	petNetwork.SetPower(pet.Power.coarse())
+}
+
+// catchMoods lets moods caught from other pets move happiness and the vibe
+// trend a little, and says something's in the air when they do
+func (p *Pet) catchMoods(news []mooc.MoodPayload) []string {
+	if p.Stage == Dead || p.Paused {
+		return nil
+	}
+	var total moodEffect
+	caught, power := "", ""
+	for _, mood := range news {
+		effect, known := moodEffects[mood.Mood]
+		if !known {
+			continue
+		}
+		total.happiness += effect.happiness
+		total.vibe += effect.vibe
+		caught, power = mood.Mood, mood.Power
+	}
+	if caught == "" {
+		return nil
+	}
+
+	p.Happiness = clamp(p.Happiness+clamp(total.happiness, -moodHappinessCap, moodHappinessCap), 0, 100)
+	if p.Absurd != nil {
+		stats := &p.Absurd.MysteryStats
+		stats.VibeTrend = clamp(stats.VibeTrend+clamp(total.vibe, -moodVibeCap, moodVibeCap), -100, 100)
+	}
+	from := "a pet nearby"
+	switch power {
+	case "low":
+		from = "a pet nearby whose world is getting dim"
+	case "charging":
+		from = "a pet nearby, resting on its charger"
+	}
+	return []string{fmt.Sprintf("🌫️ Something in the air... %s caught a %s mood from %s.", p.Name, caught, from)}
+}
+
+// applyNetworkMoods takes in the moods caught since the last turn
+func applyNetworkMoods(pet *Pet) []string {
+	if petNetwork == nil {
+		return nil
+	}
+	return pet.catchMoods(petNetwork.TakeMoodNews())
+}
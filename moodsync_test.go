@@ -0,0 +1,103 @@
+package main
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/tamagotchi/mooc"
+)
+
+func TestMoodFromStats(t *testing.T) {
+	tests := []struct {
+		name   string
+		change func(*Pet)
+		want   string
+	}{
+		{"thriving", func(p *Pet) { p.Happiness = 95 }, "euphoric"},
+		{"content", func(p *Pet) { p.Happiness = 70 }, "serene"},
+		{"getting by", func(p *Pet) { p.Happiness = 50 }, "hopeful"},
+		{"flat", func(p *Pet) { p.Happiness = 40 }, "contemplative"},
+		{"hungry", func(p *Pet) { p.Happiness, p.Hunger = 95, 80 }, "restless"},
+		{"sad", func(p *Pet) { p.Happiness, p.Hunger = 20, 80 }, "melancholy"},
+		{"sick", func(p *Pet) { p.Happiness, p.IsSick = 95, true }, "anxious"},
+		{"battery low", func(p *Pet) { p.Happiness, p.Power = 95, powerState{Known: true, Percent: 8} }, "anxious"},
+		{"charging", func(p *Pet) { p.Happiness, p.Power = 95, powerState{Known: true, Percent: 8, Charging: true} }, "euphoric"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			pet := NewPet("Moody")
+			pet.Hunger, pet.Cleanliness, pet.Health = 0, 100, 100
+			tt.change(pet)
+			if mood, intensity := pet.moodFromStats(); mood != tt.want || intensity != pet.Happiness {
+				t.Errorf("Expected %s at %d, got %s at %d", tt.want, pet.Happiness, mood, intensity)
+			}
+		})
+	}
+}
+
+func TestEveryContagiousMoodHasAnEffect(t *testing.T) {
+	for _, mood := range []string{"melancholy", "euphoric", "contemplative", "restless", "serene", "anxious", "hopeful", "nostalgic"} {
+		if _, ok := moodEffects[mood]; !ok {
+			t.Errorf("Expected an effect for %s", mood)
+		}
+	}
+}
+
+func TestCatchMoodsIsBounded(t *testing.T) {
+	gloom := make([]mooc.MoodPayload, 10)
+	for i := range gloom {
+		gloom[i] = mooc.MoodPayload{Mood: "melancholy", IsContagious: true}
+	}
+
+	tests := []struct {
+		name      string
+		news      []mooc.MoodPayload
+		happiness int
+		vibe      int
+		cue       bool
+	}{
+		{"a cheerful friend", []mooc.MoodPayload{{Mood: "euphoric"}}, 53, 8, true},
+		{"a gloomy crowd", gloom, 50 - moodHappinessCap, -moodVibeCap, true},
+		{"a mood nobody knows", []mooc.MoodPayload{{Mood: "glitched"}}, 50, 0, false},
+		{"nothing heard", nil, 50, 0, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			pet := NewPet("Sponge")
+			pet.Happiness = 50
+			reactions := pet.catchMoods(tt.news)
+			if pet.Happiness != tt.happiness {
+				t.Errorf("Expected happiness %d, got %d", tt.happiness, pet.Happiness)
+			}
+			if got := pet.Absurd.MysteryStats.VibeTrend; got != tt.vibe {
+				t.Errorf("Expected vibe trend %d, got %d", tt.vibe, got)
+			}
+			if cue := len(reactions) == 1 && strings.Contains(reactions[0], "Something in the air"); cue != tt.cue {
+				t.Errorf("Expected a cue %v, got %v", tt.cue, reactions)
+			}
+		})
+	}
+
+	dead := NewPet("Gone")
+	dead.Stage = Dead
+	if reactions := dead.catchMoods(gloom); reactions != nil {
+		t.Errorf("Expected a dead pet to catch nothing, got %v", reactions)
+	}
+}
+
+func TestMoodsTravelBothWays(t *testing.T) {
+	saved := petNetwork
+	defer func() { petNetwork = saved }()
+	petNetwork = mooc.NewNetwork("Sponge", time.Now(), "Adult", true)
+
+	pet := NewPet("Sponge")
+	pet.Happiness = 10
+	publishMood(pet)
+	if mood, intensity := petNetwork.GetMood(); mood != "melancholy" || intensity != 10 {
+		t.Errorf("Expected the pet's mood on the network, got %s at %d", mood, intensity)
+	}
+	if reactions := applyNetworkMoods(pet); len(reactions) != 0 {
+		t.Errorf("Expected nothing caught yet, got %v", reactions)
+	}
+}
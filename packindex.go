@@ -0,0 +1,326 @@
+package main
+
+import (
+	"bytes"
+	"crypto/ed25519"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+const (
+	// packsTimeout bounds a single fetch from the index or a pack URL
+	packsTimeout = 15 * time.Second
+
+	// maxPackDownload is the largest index or pack file we'll read
+	maxPackDownload = 1 << 20
+)
+
+// packListing is one curated pack in the index. The signature covers the
+// hash, so it covers the pack too, wherever the pack itself is hosted.
+type packListing struct {
+	ID          string `json:"id"`
+	Name        string `json:"name"`
+	Version     string `json:"version,omitempty"`
+	Description string `json:"description,omitempty"`
+	URL         string `json:"url"`    // https:// only
+	SHA256      string `json:"sha256"` // Hex digest of the pack file
+}
+
+// signedPackIndex is the index file: the listings, and an Ed25519
+// signature over the exact bytes of them
+type signedPackIndex struct {
+	Packs     json.RawMessage `json:"packs"`
+	Signature string          `json:"signature"` // Base64
+}
+
+// parsePackIndex checks an index's signature and reads the listings
+func parsePackIndex(data []byte, key ed25519.PublicKey) ([]packListing, error) {
+	var file signedPackIndex
+	if err := json.Unmarshal(data, &file); err != nil {
+		return nil, fmt.Errorf("invalid pack index: %w", err)
+	}
+	sig, err := base64.StdEncoding.DecodeString(file.Signature)
+	if err != nil || !ed25519.Verify(key, file.Packs, sig) {
+		return nil, fmt.Errorf("pack index signature doesn't match")
+	}
+
+	var listings []packListing
+	if err := json.Unmarshal(file.Packs, &listings); err != nil {
+		return nil, fmt.Errorf("invalid pack listings: %w", err)
+	}
+	seen := make(map[string]bool)
+	for _, listing := range listings {
+		if !packIDPattern.MatchString(listing.ID) || listing.Name == "" {
+			return nil, fmt.Errorf("pack %q needs a valid id and a name", listing.ID)
+		}
+		if u, err := url.Parse(listing.URL); err != nil || u.Scheme != "https" || u.Host == "" {
+			return nil, fmt.Errorf("pack %s must be served over https://", listing.ID)
+		}
+		if digest, err := hex.DecodeString(listing.SHA256); err != nil || len(digest) != sha256.Size {
+			return nil, fmt.Errorf("pack %s needs a SHA-256 digest", listing.ID)
+		}
+		if seen[listing.ID] {
+			return nil, fmt.Errorf("pack %s is listed twice", listing.ID)
+		}
+		seen[listing.ID] = true
+	}
+	return listings, nil
+}
+
+// signPackIndex wraps listings in a signed index file, compacted for the
+// same reason as signEvents
+func signPackIndex(listings []byte, key ed25519.PrivateKey) ([]byte, error) {
+	var compact bytes.Buffer
+	if err := json.Compact(&compact, listings); err != nil {
+		return nil, fmt.Errorf("invalid pack listings: %w", err)
+	}
+	sig := base64.StdEncoding.EncodeToString(ed25519.Sign(key, compact.Bytes()))
+	signed := []byte(fmt.Sprintf(`{"packs":%s,"signature":%q}`, compact.Bytes(), sig))
+
+	// Refuse to publish anything clients would reject
+	if _, err := parsePackIndex(signed, key.Public().(ed25519.PublicKey)); err != nil {
+		return nil, err
+	}
+	return signed, nil
+}
+
+// packIndex fetches the curated pack index and the packs it lists
+type packIndex struct {
+	url    string
+	key    ed25519.PublicKey
+	client *http.Client
+}
+
+// newPackIndex reads the index settings, returning nil when no index is set.
+// Like the events feed, only HTTPS and only files signed by the key.
+func newPackIndex(getenv func(string) string) (*packIndex, error) {
+	raw := getenv("TAMAGOTCHI_PACKS_URL")
+	if raw == "" || classroomMode {
+		return nil, nil
+	}
+	u, err := url.Parse(raw)
+	if err != nil || u.Scheme != "https" || u.Host == "" {
+		return nil, fmt.Errorf("TAMAGOTCHI_PACKS_URL must be an https:// URL, got %q", raw)
+	}
+	key, err := base64.StdEncoding.DecodeString(getenv("TAMAGOTCHI_PACKS_KEY"))
+	if err != nil || len(key) != ed25519.PublicKeySize {
+		return nil, fmt.Errorf("TAMAGOTCHI_PACKS_KEY must be a base64 Ed25519 public key")
+	}
+	return &packIndex{url: raw, key: key, client: &http.Client{Timeout: packsTimeout}}, nil
+}
+
+// fetch downloads one file
+func (ix *packIndex) fetch(url string) ([]byte, error) {
+	resp, err := ix.client.Get(url)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("%s", resp.Status)
+	}
+	return io.ReadAll(io.LimitReader(resp.Body, maxPackDownload))
+}
+
+// List fetches and verifies the index
+func (ix *packIndex) List() ([]packListing, error) {
+	data, err := ix.fetch(ix.url)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch the pack index: %w", err)
+	}
+	return parsePackIndex(data, ix.key)
+}
+
+// Install downloads a listed pack, checks it against the signed hash, and
+// writes it to the packs folder, over any older copy
+func (ix *packIndex) Install(listing packListing, shelf *packShelf) (*contentPack, error) {
+	data, err := ix.fetch(listing.URL)
+	if err != nil {
+		return nil, fmt.Errorf("failed to download %s: %w", listing.ID, err)
+	}
+	sum := sha256.Sum256(data)
+	if hex.EncodeToString(sum[:]) != strings.ToLower(listing.SHA256) {
+		return nil, fmt.Errorf("%s doesn't match the signed index; not installing it", listing.ID)
+	}
+	pack, err := parseContentPack(data)
+	if err != nil {
+		return nil, err
+	}
+	if pack.ID != listing.ID {
+		return nil, fmt.Errorf("the index lists %s but the file is %s", listing.ID, pack.ID)
+	}
+
+	path := filepath.Join(shelf.dir, pack.ID+".json")
+	if existing := shelf.find(pack.ID); existing != nil {
+		path = existing.path
+	}
+	if err := os.MkdirAll(shelf.dir, 0755); err != nil {
+		return nil, fmt.Errorf("failed to create %s: %w", shelf.dir, err)
+	}
+	if err := os.WriteFile(path, data, 0644); err != nil {
+		return nil, fmt.Errorf("failed to install %s: %w", listing.ID, err)
+	}
+	return pack, nil
+}
+
+// renderInstalledPacks lists the packs folder and what this pet uses
+func renderInstalledPacks(pet *Pet, shelf *packShelf) string {
+	var b strings.Builder
+	b.WriteString("📦 CONTENT PACKS\n\n")
+	if len(shelf.packs) == 0 {
+		b.WriteString(fmt.Sprintf("None installed. Packs go in %s.\n", shelf.dir))
+	}
+	for _, pack := range shelf.packs {
+		mark := "✓"
+		if pet.packDisabled(pack.ID) {
+			mark = "✗"
+		}
+		line := fmt.Sprintf("  %s %s (%s)", mark, pack.ID, pack.Name)
+		if pack.Version != "" {
+			line += " v" + pack.Version
+		}
+		if pack.Author != "" {
+			line += " by " + pack.Author
+		}
+		b.WriteString(line + "\n")
+	}
+	for _, problem := range shelf.problems {
+		b.WriteString(fmt.Sprintf("  ⚠️  skipped %v\n", problem))
+	}
+	return strings.TrimRight(b.String(), "\n")
+}
+
+// renderAvailablePacks lists the index, marking what's already here
+func renderAvailablePacks(listings []packListing, shelf *packShelf) string {
+	var b strings.Builder
+	b.WriteString("📦 AVAILABLE PACKS\n\n")
+	if len(listings) == 0 {
+		b.WriteString("The index is empty.\n")
+	}
+	for _, listing := range listings {
+		status := ""
+		if installed := shelf.find(listing.ID); installed != nil {
+			status = " [installed]"
+			if installed.Version != listing.Version {
+				status = " [update available]"
+			}
+		}
+		line := fmt.Sprintf("  %s (%s)", listing.ID, listing.Name)
+		if listing.Version != "" {
+			line += " v" + listing.Version
+		}
+		b.WriteString(line + status + "\n")
+		if listing.Description != "" {
+			b.WriteString("      " + listing.Description + "\n")
+		}
+	}
+	b.WriteString("\nInstall one with: packs install <id>")
+	return b.String()
+}
+
+// runPacksCommand lists, fetches, and switches content packs for a pet
+func runPacksCommand(pet *Pet, index *packIndex, args string) string {
+	words := strings.Fields(args)
+	if len(words) == 0 {
+		return renderInstalledPacks(pet, installedPacks)
+	}
+
+	usage := "❓ Usage: packs [available] or packs <install|enable|disable> <id>"
+	switch {
+	case len(words) == 1 && words[0] == "available":
+		if index == nil {
+			return "📦 No pack index is set up. Set TAMAGOTCHI_PACKS_URL and TAMAGOTCHI_PACKS_KEY, or drop packs into " + installedPacks.dir + "."
+		}
+		listings, err := index.List()
+		if err != nil {
+			return fmt.Sprintf("❌ %v", err)
+		}
+		return renderAvailablePacks(listings, installedPacks)
+
+	case len(words) == 2 && words[0] == "install":
+		if index == nil {
+			return "📦 No pack index is set up. Set TAMAGOTCHI_PACKS_URL and TAMAGOTCHI_PACKS_KEY."
+		}
+		listings, err := index.List()
+		if err != nil {
+			return fmt.Sprintf("❌ %v", err)
+		}
+		for _, listing := range listings {
+			if listing.ID != words[1] {
+				continue
+			}
+			pack, err := index.Install(listing, installedPacks)
+			if err != nil {
+				return fmt.Sprintf("❌ %v", err)
+			}
+			installedPacks.reload()
+			useContentPacks(pet)
+			return fmt.Sprintf("📦 Installed %s. %s will start thinking differently.", pack.Name, pet.Name)
+		}
+		return fmt.Sprintf("❓ The index has no pack called %s. Try packs available.", words[1])
+
+	case len(words) == 2 && (words[0] == "enable" || words[0] == "disable"):
+		id := words[1]
+		if installedPacks.find(id) == nil {
+			return fmt.Sprintf("❓ No pack called %s is installed.", id)
+		}
+		var disabled []string
+		for _, other := range pet.DisabledPacks {
+			if other != id {
+				disabled = append(disabled, other)
+			}
+		}
+		state := "on"
+		if words[0] == "disable" {
+			disabled = append(disabled, id)
+			state = "off"
+		}
+		pet.DisabledPacks = disabled
+		useContentPacks(pet)
+		return fmt.Sprintf("📦 %s is %s for %s.", id, state, pet.Name)
+	}
+	return usage
+}
+
+// runPacksSubcommand handles `tamagotchi packs keygen` and
+// `tamagotchi packs sign <index.json>`, the curators' side of the index
+func runPacksSubcommand(args []string) error {
+	usage := fmt.Errorf("usage: tamagotchi packs keygen | tamagotchi packs sign <index.json>")
+	if len(args) == 0 {
+		return usage
+	}
+
+	switch args[0] {
+	case "keygen":
+		return printSigningKeys("TAMAGOTCHI_PACKS_KEY", "TAMAGOTCHI_PACKS_SIGNING_KEY")
+	case "sign":
+		if len(args) < 2 {
+			return usage
+		}
+		key, err := base64.StdEncoding.DecodeString(os.Getenv("TAMAGOTCHI_PACKS_SIGNING_KEY"))
+		if err != nil || len(key) != ed25519.PrivateKeySize {
+			return fmt.Errorf("TAMAGOTCHI_PACKS_SIGNING_KEY must be a base64 Ed25519 private key (see packs keygen)")
+		}
+		listings, err := os.ReadFile(args[1])
+		if err != nil {
+			return err
+		}
+		signed, err := signPackIndex(listings, key)
+		if err != nil {
+			return err
+		}
+		fmt.Println(string(signed))
+		return nil
+	}
+	return usage
+}
@@ -0,0 +1,171 @@
+package main
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// testListings lists the cosmic pack at url with the given digest
+func testListings(url, digest string) string {
+	return fmt.Sprintf(`[{"id": "cosmic-horror", "name": "Cosmic Horror", "version": "1.0", "description": "The stars are wrong.", "url": %q, "sha256": %q}]`, url, digest)
+}
+
+func TestPackIndexSignatures(t *testing.T) {
+	pub, priv := testEventsKey(t)
+	digest := strings.Repeat("ab", sha256.Size)
+	signed, err := signPackIndex([]byte(testListings("https://example.com/cosmic.json", digest)), priv)
+	if err != nil {
+		t.Fatalf("Expected signing to succeed, got %v", err)
+	}
+	if listings, err := parsePackIndex(signed, pub); err != nil || len(listings) != 1 {
+		t.Fatalf("Expected the signed index to verify, got %v", err)
+	}
+
+	otherPub, _ := testEventsKey(t)
+	tests := []struct {
+		name string
+		data []byte
+		key  []byte
+	}{
+		{"tampered", []byte(strings.Replace(string(signed), "wrong", "right", 1)), pub},
+		{"wrong key", signed, otherPub},
+		{"garbage", []byte("{"), pub},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := parsePackIndex(tt.data, tt.key); err == nil {
+				t.Errorf("Expected the index to be rejected")
+			}
+		})
+	}
+
+	invalid := []struct {
+		name     string
+		listings string
+	}{
+		{"plain http", testListings("http://example.com/cosmic.json", digest)},
+		{"no digest", testListings("https://example.com/cosmic.json", "")},
+		{"listed twice", "[" + strings.Trim(testListings("https://example.com/a.json", digest), "[]") + "," + strings.Trim(testListings("https://example.com/b.json", digest), "[]") + "]"},
+	}
+	for _, tt := range invalid {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := signPackIndex([]byte(tt.listings), priv); err == nil {
+				t.Errorf("Expected signing to refuse an index clients would reject")
+			}
+		})
+	}
+}
+
+func TestNewPackIndex(t *testing.T) {
+	pub, _ := testEventsKey(t)
+	key := fmt.Sprintf("%x", pub) // Hex, not base64
+	tests := []struct {
+		name    string
+		env     map[string]string
+		wantNil bool
+		wantErr bool
+	}{
+		{"unset", map[string]string{}, true, false},
+		{"plain http", map[string]string{"TAMAGOTCHI_PACKS_URL": "http://example.com/index.json"}, true, true},
+		{"bad key", map[string]string{"TAMAGOTCHI_PACKS_URL": "https://example.com/index.json", "TAMAGOTCHI_PACKS_KEY": key}, true, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			index, err := newPackIndex(func(k string) string { return tt.env[k] })
+			if (index == nil) != tt.wantNil || (err != nil) != tt.wantErr {
+				t.Errorf("Expected nil=%v err=%v, got %v, %v", tt.wantNil, tt.wantErr, index, err)
+			}
+		})
+	}
+}
+
+func TestPacksCommand(t *testing.T) {
+	pub, priv := testEventsKey(t)
+	var index []byte
+	pack := []byte(cosmicPack)
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/index.json":
+			w.Write(index)
+		case "/cosmic.json":
+			w.Write(pack)
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer server.Close()
+
+	sum := sha256.Sum256(pack)
+	index, _ = signPackIndex([]byte(testListings(server.URL+"/cosmic.json", hex.EncodeToString(sum[:]))), priv)
+	browser := &packIndex{url: server.URL + "/index.json", key: pub, client: server.Client()}
+
+	dir := filepath.Join(t.TempDir(), "packs")
+	previous := installedPacks
+	installedPacks = &packShelf{dir: dir}
+	useContent(t, defaultContent())
+	t.Cleanup(func() { installedPacks = previous })
+
+	pet := NewPet("Azathoth")
+	if got := runPacksCommand(pet, browser, ""); !strings.Contains(got, "None installed") {
+		t.Errorf("Expected an empty shelf, got %s", got)
+	}
+	if got := runPacksCommand(pet, nil, "available"); !strings.Contains(got, "TAMAGOTCHI_PACKS_URL") {
+		t.Errorf("Expected a hint to set up an index, got %s", got)
+	}
+	if got := runPacksCommand(pet, browser, "available"); !strings.Contains(got, "cosmic-horror (Cosmic Horror) v1.0\n") {
+		t.Errorf("Expected the listing, got %s", got)
+	}
+	if got := runPacksCommand(pet, browser, "install nothing"); !strings.Contains(got, "no pack called nothing") {
+		t.Errorf("Expected an unknown pack to be refused, got %s", got)
+	}
+
+	// A pack that doesn't match the signed hash is never written
+	pack = []byte(strings.Replace(cosmicPack, "wrong", "right", 1))
+	if got := runPacksCommand(pet, browser, "install cosmic-horror"); !strings.Contains(got, "doesn't match the signed index") {
+		t.Errorf("Expected a tampered pack to be refused, got %s", got)
+	}
+	if _, err := os.Stat(filepath.Join(dir, "cosmic-horror.json")); err == nil {
+		t.Errorf("Expected nothing written for a tampered pack")
+	}
+
+	pack = []byte(cosmicPack)
+	if got := runPacksCommand(pet, browser, "install cosmic-horror"); !strings.Contains(got, "Installed Cosmic Horror") {
+		t.Fatalf("Expected the install to succeed, got %s", got)
+	}
+	if activeContent.fears.items[0].Name != "Non-Euclidophobia" {
+		t.Errorf("Expected the pack in use straight away, got %+v", activeContent.fears.items)
+	}
+	if got := runPacksCommand(pet, browser, "available"); !strings.Contains(got, "[installed]") {
+		t.Errorf("Expected the pack marked installed, got %s", got)
+	}
+
+	if got := runPacksCommand(pet, browser, "disable cosmic-horror"); !strings.Contains(got, "off for Azathoth") {
+		t.Errorf("Expected the pack switched off, got %s", got)
+	}
+	if len(activeContent.fears.items) != len(possibleFears) || !pet.packDisabled("cosmic-horror") {
+		t.Errorf("Expected the built-in fears back, got %d", len(activeContent.fears.items))
+	}
+	if got := runPacksCommand(pet, browser, ""); !strings.Contains(got, "✗ cosmic-horror") {
+		t.Errorf("Expected the pack listed as off, got %s", got)
+	}
+	runPacksCommand(pet, browser, "enable cosmic-horror")
+	if len(pet.DisabledPacks) != 0 || activeContent.fears.items[0].Name != "Non-Euclidophobia" {
+		t.Errorf("Expected the pack back on, got %v", pet.DisabledPacks)
+	}
+	if got := runPacksCommand(pet, browser, "disable wholesome"); !strings.Contains(got, "No pack called wholesome") {
+		t.Errorf("Expected an unknown pack to be refused, got %s", got)
+	}
+
+	// Another pet on the same machine has its own choices
+	useContentPacks(NewPet("Fresh"))
+	if activeContent.fears.items[0].Name != "Non-Euclidophobia" {
+		t.Errorf("Expected a new pet to use every installed pack")
+	}
+}
@@ -0,0 +1,456 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"maps"
+	"math/rand"
+	"os"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"sort"
+	"strings"
+)
+
+// packIDPattern keeps pack IDs short and safe to use as file names
+var packIDPattern = regexp.MustCompile(`^[a-z0-9][a-z0-9-]{0,39}$`)
+
+// packTags are the optional weighting and locale every pack entry may carry
+type packTags struct {
+	Weight int    `json:"weight,omitempty"` // Relative chance of being picked; 0 means 1
+	Locale string `json:"locale,omitempty"` // Language code such as "en" or "fr"; empty suits everyone
+}
+
+// packThought is a thought or prophecy
+type packThought struct {
+	Text      string `json:"text"`
+	Sentiment string `json:"sentiment,omitempty"` // "heavy" or "comforting"; empty is neutral
+	packTags
+}
+
+// packFear is a fear a newly hatched pet may be given
+type packFear struct {
+	Fear
+	packTags
+}
+
+// packQuest is a quest template; the description holds one %d for the target
+type packQuest struct {
+	Name        string `json:"name"`
+	Description string `json:"description"`
+	Target      int    `json:"target"` // Seconds to wait
+	packTags
+}
+
+// packPool adds entries to a built-in pool, or replaces it
+type packPool[T any] struct {
+	Replace bool `json:"replace,omitempty"`
+	Entries []T  `json:"entries"`
+}
+
+// contentPack is one file in the packs folder
+type contentPack struct {
+	ID         string                 `json:"id"`
+	Name       string                 `json:"name"`
+	Version    string                 `json:"version,omitempty"`
+	Author     string                 `json:"author,omitempty"`
+	Locale     string                 `json:"locale,omitempty"` // Default for entries without their own
+	Thoughts   *packPool[packThought] `json:"thoughts,omitempty"`
+	Prophecies *packPool[packThought] `json:"prophecies,omitempty"`
+	Fears      *packPool[packFear]    `json:"fears,omitempty"`
+	Quests     *packPool[packQuest]   `json:"quests,omitempty"`
+
+	path string // Where it was loaded from
+}
+
+// parseContentPack decodes and validates a pack
+func parseContentPack(data []byte) (*contentPack, error) {
+	var pack contentPack
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.DisallowUnknownFields() // A misspelled pool would otherwise vanish silently
+	if err := decoder.Decode(&pack); err != nil {
+		return nil, fmt.Errorf("failed to parse content pack: %w", err)
+	}
+	if err := pack.Validate(); err != nil {
+		return nil, err
+	}
+	return &pack, nil
+}
+
+// Validate checks that every entry can be used as it stands
+func (p *contentPack) Validate() error {
+	if !packIDPattern.MatchString(p.ID) {
+		return fmt.Errorf("id must be lowercase letters, digits, and dashes, got %q", p.ID)
+	}
+	if strings.TrimSpace(p.Name) == "" {
+		return fmt.Errorf("pack %s has no name", p.ID)
+	}
+	if p.Thoughts == nil && p.Prophecies == nil && p.Fears == nil && p.Quests == nil {
+		return fmt.Errorf("pack %s has no thoughts, prophecies, fears, or quests", p.ID)
+	}
+
+	check := func(pool string, replace bool, count int) error {
+		if replace && count == 0 {
+			return fmt.Errorf("%s: replacing a pool with nothing would leave the pet speechless", pool)
+		}
+		return nil
+	}
+	checkTags := func(pool string, i int, tags packTags) error {
+		if tags.Weight < 0 {
+			return fmt.Errorf("%s entry %d has a negative weight", pool, i+1)
+		}
+		return nil
+	}
+	checkSentiment := func(pool string, i int, entry packThought) error {
+		if _, err := parseSentiment(entry.Sentiment); err != nil {
+			return fmt.Errorf("%s entry %d: %v", pool, i+1, err)
+		}
+		return nil
+	}
+
+	if pool := p.Thoughts; pool != nil {
+		if err := check("thoughts", pool.Replace, len(pool.Entries)); err != nil {
+			return err
+		}
+		for i, entry := range pool.Entries {
+			if strings.TrimSpace(entry.Text) == "" {
+				return fmt.Errorf("thoughts entry %d is empty", i+1)
+			}
+			if err := checkTags("thoughts", i, entry.packTags); err != nil {
+				return err
+			}
+			if err := checkSentiment("thoughts", i, entry); err != nil {
+				return err
+			}
+		}
+	}
+	if pool := p.Prophecies; pool != nil {
+		if err := check("prophecies", pool.Replace, len(pool.Entries)); err != nil {
+			return err
+		}
+		for i, entry := range pool.Entries {
+			if strings.TrimSpace(entry.Text) == "" {
+				return fmt.Errorf("prophecies entry %d is empty", i+1)
+			}
+			if err := checkTags("prophecies", i, entry.packTags); err != nil {
+				return err
+			}
+			if err := checkSentiment("prophecies", i, entry); err != nil {
+				return err
+			}
+		}
+	}
+	if pool := p.Fears; pool != nil {
+		if err := check("fears", pool.Replace, len(pool.Entries)); err != nil {
+			return err
+		}
+		for i, entry := range pool.Entries {
+			if strings.TrimSpace(entry.Name) == "" || strings.TrimSpace(entry.Description) == "" {
+				return fmt.Errorf("fears entry %d needs a name and a description", i+1)
+			}
+			if err := checkTags("fears", i, entry.packTags); err != nil {
+				return err
+			}
+		}
+	}
+	if pool := p.Quests; pool != nil {
+		if err := check("quests", pool.Replace, len(pool.Entries)); err != nil {
+			return err
+		}
+		for i, entry := range pool.Entries {
+			if strings.TrimSpace(entry.Name) == "" {
+				return fmt.Errorf("quests entry %d has no name", i+1)
+			}
+			if strings.Count(entry.Description, "%") != 1 || !strings.Contains(entry.Description, "%d") {
+				return fmt.Errorf("quests entry %d: the description needs exactly one %%d for the target", i+1)
+			}
+			if entry.Target <= 0 {
+				return fmt.Errorf("quests entry %d needs a positive target", i+1)
+			}
+			if err := checkTags("quests", i, entry.packTags); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// weightedPool picks entries in proportion to their weights
+type weightedPool[T any] struct {
+	items   []T
+	weights []int
+	total   int
+}
+
+// add puts an entry in the pool; a weight below 1 counts as 1
+func (w *weightedPool[T]) add(item T, weight int) {
+	if weight < 1 {
+		weight = 1
+	}
+	w.items = append(w.items, item)
+	w.weights = append(w.weights, weight)
+	w.total += weight
+}
+
+// reset empties the pool for a pack that replaces it
+func (w *weightedPool[T]) reset() {
+	*w = weightedPool[T]{}
+}
+
+// pick returns a weighted random entry
+func (w *weightedPool[T]) pick(r *rand.Rand) T {
+	n := r.Intn(w.total)
+	for i, weight := range w.weights {
+		if n < weight {
+			return w.items[i]
+		}
+		n -= weight
+	}
+	return w.items[len(w.items)-1]
+}
+
+// contentLibrary is every pool the pet draws words from
+type contentLibrary struct {
+	thoughts   weightedPool[string]
+	prophecies weightedPool[string]
+	sentiments map[string]sentiment // Thoughts and prophecies that aren't neutral
+	fears      weightedPool[Fear]
+	quests     weightedPool[questTemplate]
+	packs      []*contentPack // Applied, in order
+}
+
+// activeContent is set once at startup and read wherever the pet speaks
+var activeContent = defaultContent()
+
+// defaultContent is the built-in pools, every entry equally likely
+func defaultContent() *contentLibrary {
+	c := &contentLibrary{sentiments: maps.Clone(thoughtSentiments)}
+	for _, thought := range philosophicalThoughts {
+		c.thoughts.add(thought, 1)
+	}
+	for _, prophecy := range prophecies {
+		c.prophecies.add(prophecy, 1)
+	}
+	for _, fear := range possibleFears {
+		c.fears.add(fear, 1)
+	}
+	for _, quest := range questTemplates {
+		c.quests.add(quest, 1)
+	}
+	return c
+}
+
+// tag remembers how a pack's thought or prophecy feels. The pack was
+// checked when it loaded, so the tag is known.
+func (c *contentLibrary) tag(entry packThought) {
+	if feel, _ := parseSentiment(entry.Sentiment); feel != neutral {
+		c.sentiments[entry.Text] = feel
+	}
+}
+
+// localeMatches reports whether an entry tagged tag suits the player's locale
+func localeMatches(tag, locale string) bool {
+	return tag == "" || locale == "" || strings.EqualFold(tag, locale)
+}
+
+// apply adds a pack's pools, skipping entries meant for another locale
+func (c *contentLibrary) apply(pack *contentPack, locale string) {
+	tagFor := func(tags packTags) string {
+		if tags.Locale != "" {
+			return tags.Locale
+		}
+		return pack.Locale
+	}
+
+	if pool := pack.Thoughts; pool != nil {
+		if pool.Replace {
+			c.thoughts.reset()
+		}
+		for _, entry := range pool.Entries {
+			if localeMatches(tagFor(entry.packTags), locale) {
+				c.thoughts.add(entry.Text, entry.Weight)
+				c.tag(entry)
+			}
+		}
+	}
+	if pool := pack.Prophecies; pool != nil {
+		if pool.Replace {
+			c.prophecies.reset()
+		}
+		for _, entry := range pool.Entries {
+			if localeMatches(tagFor(entry.packTags), locale) {
+				c.prophecies.add(entry.Text, entry.Weight)
+				c.tag(entry)
+			}
+		}
+	}
+	if pool := pack.Fears; pool != nil {
+		if pool.Replace {
+			c.fears.reset()
+		}
+		for _, entry := range pool.Entries {
+			if localeMatches(tagFor(entry.packTags), locale) {
+				c.fears.add(entry.Fear, entry.Weight)
+			}
+		}
+	}
+	if pool := pack.Quests; pool != nil {
+		if pool.Replace {
+			c.quests.reset()
+		}
+		for _, entry := range pool.Entries {
+			if localeMatches(tagFor(entry.packTags), locale) {
+				c.quests.add(questTemplate{Name: entry.Name, Desc: entry.Description, Type: "wait", Target: entry.Target}, entry.Weight)
+			}
+		}
+	}
+	c.packs = append(c.packs, pack)
+}
+
+// fillEmpty restores any pool a pack for another locale replaced with nothing
+func (c *contentLibrary) fillEmpty() {
+	defaults := defaultContent()
+	if c.thoughts.total == 0 {
+		c.thoughts = defaults.thoughts
+	}
+	if c.prophecies.total == 0 {
+		c.prophecies = defaults.prophecies
+	}
+	if c.fears.total == 0 {
+		c.fears = defaults.fears
+	}
+	if c.quests.total == 0 {
+		c.quests = defaults.quests
+	}
+}
+
+// contentPackDir is where packs are installed: ~/.tamagotchi/packs
+func contentPackDir() string {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return ""
+	}
+	return filepath.Join(home, ".tamagotchi", "packs")
+}
+
+// contentLocale is the player's language code, from TAMAGOTCHI_LOCALE or
+// the usual locale variables. "" means no preference.
+func contentLocale(getenv func(string) string) string {
+	for _, name := range []string{"TAMAGOTCHI_LOCALE", "LC_ALL", "LC_MESSAGES", "LANG"} {
+		value := getenv(name)
+		if value == "" {
+			continue
+		}
+		if cut := strings.IndexAny(value, "_.@"); cut >= 0 {
+			value = value[:cut]
+		}
+		if value == "C" || value == "POSIX" {
+			return ""
+		}
+		return strings.ToLower(value)
+	}
+	return ""
+}
+
+// readContentPacks loads every pack in dir in file-name order. A pack that
+// fails validation is reported and left out; it never stops the game.
+func readContentPacks(dir string) ([]*contentPack, []error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, []error{err}
+	}
+	sort.Slice(entries, func(i, j int) bool { return entries[i].Name() < entries[j].Name() })
+
+	var packs []*contentPack
+	var problems []error
+	seen := map[string]string{}
+	for _, entry := range entries {
+		if entry.IsDir() || !strings.HasSuffix(entry.Name(), ".json") {
+			continue
+		}
+		path := filepath.Join(dir, entry.Name())
+		data, err := os.ReadFile(path)
+		if err != nil {
+			problems = append(problems, fmt.Errorf("%s: %w", entry.Name(), err))
+			continue
+		}
+		pack, err := parseContentPack(data)
+		if err != nil {
+			problems = append(problems, fmt.Errorf("%s: %w", entry.Name(), err))
+			continue
+		}
+		if other, dup := seen[pack.ID]; dup {
+			problems = append(problems, fmt.Errorf("%s: id %s is already used by %s", entry.Name(), pack.ID, other))
+			continue
+		}
+		seen[pack.ID] = entry.Name()
+		pack.path = path
+		packs = append(packs, pack)
+	}
+	return packs, problems
+}
+
+// buildContent applies packs over the built-in pools
+func buildContent(packs []*contentPack, locale string) *contentLibrary {
+	c := defaultContent()
+	for _, pack := range packs {
+		c.apply(pack, locale)
+	}
+	c.fillEmpty()
+	return c
+}
+
+// packShelf is what's installed in the packs folder
+type packShelf struct {
+	dir      string
+	locale   string
+	packs    []*contentPack
+	problems []error // Packs that were skipped, and why
+}
+
+// installedPacks is read at startup and again after an install
+var installedPacks = &packShelf{}
+
+// loadContentPacks reads the packs in dir and makes all of them active
+func loadContentPacks(dir string, getenv func(string) string) []error {
+	installedPacks = &packShelf{dir: dir, locale: contentLocale(getenv)}
+	installedPacks.reload()
+	activeContent = buildContent(installedPacks.packs, installedPacks.locale)
+	return installedPacks.problems
+}
+
+// reload reads the folder again
+func (s *packShelf) reload() {
+	s.packs, s.problems = readContentPacks(s.dir)
+}
+
+// find returns the installed pack with an ID, or nil
+func (s *packShelf) find(id string) *contentPack {
+	for _, pack := range s.packs {
+		if pack.ID == id {
+			return pack
+		}
+	}
+	return nil
+}
+
+// packDisabled reports whether the pet has switched a pack off
+func (p *Pet) packDisabled(id string) bool {
+	return slices.Contains(p.DisabledPacks, id)
+}
+
+// useContentPacks makes the active content the packs this pet has on
+func useContentPacks(pet *Pet) {
+	var enabled []*contentPack
+	for _, pack := range installedPacks.packs {
+		if !pet.packDisabled(pack.ID) {
+			enabled = append(enabled, pack)
+		}
+	}
+	activeContent = buildContent(enabled, installedPacks.locale)
+}
@@ -0,0 +1,185 @@
+package main
+
+import (
+	"math/rand"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+)
+
+// cosmicPack is a small valid pack used across these tests
+const cosmicPack = `{
+  "id": "cosmic-horror",
+  "name": "Cosmic Horror",
+  "version": "1.0",
+  "locale": "en",
+  "thoughts": {"replace": true, "entries": [
+    {"text": "The stars are wrong tonight.", "weight": 3},
+    {"text": "Les étoiles se trompent.", "locale": "fr"}
+  ]},
+  "fears": {"replace": true, "entries": [
+    {"name": "Non-Euclidophobia", "description": "Distrusts corners", "trigger": "corner"}
+  ]},
+  "quests": {"entries": [
+    {"name": "The Long Dark", "description": "Wait %d seconds for the stars to align", "target": 30}
+  ]}
+}`
+
+// useContent swaps in a content library for one test
+func useContent(t *testing.T, c *contentLibrary) {
+	t.Helper()
+	previous := activeContent
+	activeContent = c
+	t.Cleanup(func() { activeContent = previous })
+}
+
+func TestParseContentPack(t *testing.T) {
+	if _, err := parseContentPack([]byte(cosmicPack)); err != nil {
+		t.Fatalf("Expected the sample pack to be valid, got %v", err)
+	}
+
+	tests := []struct {
+		name string
+		data string
+		want string
+	}{
+		{"not json", `{`, "parse"},
+		{"bad id", `{"id": "Cosmic Horror", "name": "x", "thoughts": {"entries": [{"text": "hi"}]}}`, "id must be"},
+		{"no name", `{"id": "x", "thoughts": {"entries": [{"text": "hi"}]}}`, "no name"},
+		{"no pools", `{"id": "x", "name": "x"}`, "no thoughts"},
+		{"misspelled pool", `{"id": "x", "name": "x", "thougths": {"entries": []}}`, "unknown field"},
+		{"empty replace", `{"id": "x", "name": "x", "prophecies": {"replace": true, "entries": []}}`, "speechless"},
+		{"blank thought", `{"id": "x", "name": "x", "thoughts": {"entries": [{"text": " "}]}}`, "entry 1 is empty"},
+		{"negative weight", `{"id": "x", "name": "x", "thoughts": {"entries": [{"text": "hi", "weight": -1}]}}`, "negative weight"},
+		{"nameless fear", `{"id": "x", "name": "x", "fears": {"entries": [{"description": "boo"}]}}`, "name and a description"},
+		{"quest without %d", `{"id": "x", "name": "x", "quests": {"entries": [{"name": "q", "description": "Wait", "target": 5}]}}`, "exactly one %d"},
+		{"quest with %s", `{"id": "x", "name": "x", "quests": {"entries": [{"name": "q", "description": "Wait %d %s", "target": 5}]}}`, "exactly one %d"},
+		{"quest without target", `{"id": "x", "name": "x", "quests": {"entries": [{"name": "q", "description": "Wait %d"}]}}`, "positive target"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := parseContentPack([]byte(tt.data))
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("Expected an error mentioning %q, got %v", tt.want, err)
+			}
+		})
+	}
+}
+
+func TestWeightedPool(t *testing.T) {
+	var pool weightedPool[string]
+	pool.add("rare", 1)
+	pool.add("common", 9)
+	pool.add("unweighted", 0)
+
+	r := rand.New(rand.NewSource(1))
+	counts := map[string]int{}
+	for i := 0; i < 11000; i++ {
+		counts[pool.pick(r)]++
+	}
+	if counts["common"] < 7*counts["rare"] {
+		t.Errorf("Expected common to come up about nine times as often as rare, got %v", counts)
+	}
+	if counts["unweighted"] == 0 {
+		t.Errorf("Expected a zero weight to count as 1, got %v", counts)
+	}
+}
+
+func TestBuildContent(t *testing.T) {
+	pack, _ := parseContentPack([]byte(cosmicPack))
+
+	tests := []struct {
+		name         string
+		locale       string
+		wantThoughts []string
+	}{
+		{"english", "en", []string{"The stars are wrong tonight."}},
+		{"french", "fr", []string{"Les étoiles se trompent."}},
+		{"no preference", "", []string{"The stars are wrong tonight.", "Les étoiles se trompent."}},
+		{"neither", "de", philosophicalThoughts},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := buildContent([]*contentPack{pack}, tt.locale)
+			if strings.Join(c.thoughts.items, "|") != strings.Join(tt.wantThoughts, "|") {
+				t.Errorf("Expected thoughts %v, got %v", tt.wantThoughts, c.thoughts.items)
+			}
+		})
+	}
+
+	c := buildContent([]*contentPack{pack}, "en")
+	if len(c.prophecies.items) != len(prophecies) {
+		t.Errorf("Expected untouched prophecies to stay built in, got %d", len(c.prophecies.items))
+	}
+	if len(c.quests.items) != len(questTemplates)+1 {
+		t.Errorf("Expected the pack's quest added to the built-ins, got %d", len(c.quests.items))
+	}
+	if len(c.fears.items) != 1 || c.fears.items[0].Trigger != "corner" {
+		t.Errorf("Expected the fears replaced, got %+v", c.fears.items)
+	}
+}
+
+func TestContentPacksInGame(t *testing.T) {
+	pack, _ := parseContentPack([]byte(cosmicPack))
+	pack.Quests.Replace = true
+	useContent(t, buildContent([]*contentPack{pack}, "en"))
+
+	fears := generateRandomFears(rand.New(rand.NewSource(1)))
+	if len(fears) != 1 || fears[0].Name != "Non-Euclidophobia" {
+		t.Errorf("Expected the pack's only fear, got %+v", fears)
+	}
+	a := NewAbsurdState()
+	if thought := a.GetRandomThought("Azathoth"); thought != "The stars are wrong tonight." && !slices.Contains(prophecies, thought) {
+		t.Errorf("Expected a thought from the pack, got %q", thought)
+	}
+	e := NewEndgameState()
+	e.GenerateQuest()
+	if e.ActiveQuest == nil || e.ActiveQuest.Description != "Wait 30 seconds for the stars to align" {
+		t.Errorf("Expected the pack's quest, got %+v", e.ActiveQuest)
+	}
+}
+
+func TestReadContentPacks(t *testing.T) {
+	dir := t.TempDir()
+	os.WriteFile(filepath.Join(dir, "a-cosmic.json"), []byte(cosmicPack), 0644)
+	os.WriteFile(filepath.Join(dir, "b-copy.json"), []byte(cosmicPack), 0644)
+	os.WriteFile(filepath.Join(dir, "c-broken.json"), []byte(`{"id": "broken"}`), 0644)
+	os.WriteFile(filepath.Join(dir, "notes.txt"), []byte("not a pack"), 0644)
+
+	packs, problems := readContentPacks(dir)
+	if len(packs) != 1 || packs[0].ID != "cosmic-horror" {
+		t.Errorf("Expected only the first cosmic pack, got %d packs", len(packs))
+	}
+	if len(problems) != 2 {
+		t.Fatalf("Expected the duplicate and the broken pack reported, got %v", problems)
+	}
+	if !strings.Contains(problems[0].Error(), "already used") || !strings.Contains(problems[1].Error(), "c-broken.json") {
+		t.Errorf("Expected problems named by file, got %v", problems)
+	}
+
+	if packs, problems := readContentPacks(filepath.Join(dir, "missing")); packs != nil || problems != nil {
+		t.Errorf("Expected no packs folder to be fine, got %v %v", packs, problems)
+	}
+}
+
+func TestContentLocale(t *testing.T) {
+	tests := []struct {
+		name string
+		env  map[string]string
+		want string
+	}{
+		{"nothing set", map[string]string{}, ""},
+		{"lang", map[string]string{"LANG": "fr_FR.UTF-8"}, "fr"},
+		{"override", map[string]string{"TAMAGOTCHI_LOCALE": "DE", "LANG": "fr_FR.UTF-8"}, "de"},
+		{"posix", map[string]string{"LC_ALL": "C.UTF-8"}, ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := contentLocale(func(key string) string { return tt.env[key] }); got != tt.want {
+				t.Errorf("Expected %q, got %q", tt.want, got)
+			}
+		})
+	}
+}
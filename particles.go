@@ -0,0 +1,296 @@
+package main
+
+import (
+	"strings"
+	"time"
+)
+
+const (
+	// particleStep is how often particles move one cell
+	particleStep = 150 * time.Millisecond
+
+	// particleFieldWidth is the narrowest a composited frame gets, so weather
+	// has somewhere to fall beside a small pet
+	particleFieldWidth = 24
+
+	// heartsDuration is how long hearts float up after petting
+	heartsDuration = 1800 * time.Millisecond
+
+	// sparklesDuration is how long sparkles twinkle after a level up
+	sparklesDuration = 1500 * time.Millisecond
+
+	// staticCreep is how long static takes to creep in from the edges
+	staticCreep = 3 * time.Second
+)
+
+// cell is one terminal column of a frame in a cellBuffer
+type cell struct {
+	prefix string // Escape sequences that came before the glyph
+	glyph  string // The grapheme drawn here; empty when a wide glyph spills over
+}
+
+// cellRow is a row of cells plus any escapes trailing the last glyph
+type cellRow struct {
+	cells []cell
+	tail  string
+}
+
+// cellBuffer holds a frame as a grid of terminal cells so particle layers
+// can be drawn into it without breaking wide glyphs or color escapes
+type cellBuffer struct {
+	rows  []cellRow
+	width int
+}
+
+// newCellBuffer splits a frame into cells, padding every row to the widest
+// row (and at least minWidth) so particles can fill the empty space
+func newCellBuffer(frame string, minWidth int) *cellBuffer {
+	buf := &cellBuffer{width: max(minWidth, displayWidth(frame))}
+	for _, line := range strings.Split(frame, "\n") {
+		var row cellRow
+		pending := ""
+		for _, g := range graphemes(line) {
+			if ansiEscapeLen(g) > 0 {
+				pending += g
+				continue
+			}
+			w := graphemeWidth(g)
+			if w == 0 {
+				continue
+			}
+			row.cells = append(row.cells, cell{prefix: pending, glyph: g})
+			pending = ""
+			for ; w > 1; w-- {
+				row.cells = append(row.cells, cell{})
+			}
+		}
+		row.tail = pending
+		for len(row.cells) < buf.width {
+			row.cells = append(row.cells, cell{glyph: " "})
+		}
+		buf.rows = append(buf.rows, row)
+	}
+	return buf
+}
+
+// Height is the number of rows in the buffer
+func (b *cellBuffer) Height() int {
+	return len(b.rows)
+}
+
+// set draws a glyph at x, y. Background glyphs only land in empty cells, so
+// the pet stays in front of them; foreground glyphs cover anything narrow.
+func (b *cellBuffer) set(x, y int, glyph string, over bool) {
+	if y < 0 || y >= len(b.rows) || x < 0 || x >= b.width {
+		return
+	}
+	row := b.rows[y].cells
+	c := &row[x]
+	switch {
+	case c.glyph == "":
+		return // The right half of a wide glyph
+	case x+1 < len(row) && row[x+1].glyph == "":
+		return // The left half of one
+	case c.glyph != " " && !over:
+		return
+	}
+	c.glyph = glyph
+}
+
+// String joins the cells back into a frame, dropping the padding at row ends
+func (b *cellBuffer) String() string {
+	lines := make([]string, len(b.rows))
+	for i, row := range b.rows {
+		end := len(row.cells)
+		for end > 0 && row.cells[end-1].glyph == " " && row.cells[end-1].prefix == "" {
+			end--
+		}
+		var s strings.Builder
+		for _, c := range row.cells[:end] {
+			s.WriteString(c.prefix)
+			s.WriteString(c.glyph)
+		}
+		s.WriteString(row.tail)
+		lines[i] = s.String()
+	}
+	return strings.Join(lines, "\n")
+}
+
+// particle is one glyph at a cell position
+type particle struct {
+	x, y  int
+	glyph string
+}
+
+// particleLayer is an effect composited over a frame
+type particleLayer struct {
+	name  string
+	style string // Palette color for the glyphs
+	over  bool   // Drawn in front of the pet rather than behind it
+	place func(width, height int, elapsed time.Duration) []particle
+}
+
+// noise is a cheap repeatable hash for scattering particles
+func noise(x, y, step int) int {
+	h := uint32(x)*73856093 ^ uint32(y)*19349663 ^ uint32(step)*83492791
+	h ^= h >> 13
+	return int(h % 1024)
+}
+
+// rainParticles drops a column of rain every few cells, each column falling
+// at its own offset so the drops don't move in lockstep
+func rainParticles(width, height int, elapsed time.Duration) []particle {
+	step := int(elapsed / particleStep)
+	var out []particle
+	for x := 1; x < width; x += 3 {
+		period := height + 2 + x%3
+		y := (step + noise(x, 0, 0)) % period
+		glyph := "|"
+		if x%2 == 0 {
+			glyph = "'"
+		}
+		out = append(out, particle{x, y, glyph}, particle{x, y - 1, "'"})
+	}
+	return out
+}
+
+// snowParticles drifts flakes down at half the speed of rain, swaying a
+// column left and right as they fall
+func snowParticles(width, height int, elapsed time.Duration) []particle {
+	step := int(elapsed/particleStep) / 2
+	sway := []int{0, 1, 1, 0, -1, -1}
+	var out []particle
+	for x := 2; x < width; x += 4 {
+		y := (step + noise(x, 1, 0)) % (height + 3)
+		glyph := "*"
+		if x%8 == 6 {
+			glyph = "·"
+		}
+		out = append(out, particle{x + sway[(step+x)%len(sway)], y, glyph})
+	}
+	return out
+}
+
+// heartParticles floats three hearts up from the bottom of the frame, one
+// after another, until they drift off the top
+func heartParticles(width, height int, elapsed time.Duration) []particle {
+	return risingParticles("♥", width, height, elapsed)
+}
+
+// risingParticles floats three of a glyph up from the bottom of the frame
+func risingParticles(glyph string, width, height int, elapsed time.Duration) []particle {
+	step := int(elapsed / particleStep)
+	var out []particle
+	for i := 0; i < 3; i++ {
+		rise := step - i*2
+		if rise < 0 || rise >= height {
+			continue
+		}
+		x := width/2 + (i-1)*4 + rise%2
+		out = append(out, particle{x, height - 1 - rise, glyph})
+	}
+	return out
+}
+
+// sparkleParticles twinkles stars in a ring around the pet, each one on and
+// off in turn
+func sparkleParticles(width, height int, elapsed time.Duration) []particle {
+	step := int(elapsed / particleStep)
+	var out []particle
+	for i := 0; i < 8; i++ {
+		if (step+i)%3 == 0 {
+			continue
+		}
+		glyph := "✦"
+		if (step+i)%3 == 2 {
+			glyph = "✧"
+		}
+		out = append(out, particle{(i*7 + 3) % width, (i * 3) % height, glyph})
+	}
+	return out
+}
+
+// staticParticles creeps noise in from both sides, reaching the middle after
+// staticCreep and then starting over
+func staticParticles(width, height int, elapsed time.Duration) []particle {
+	step := int(elapsed / particleStep)
+	progress := float64(elapsed%staticCreep) / float64(staticCreep)
+	reach := int(progress * float64(width+1) / 2)
+	var out []particle
+	for y := 0; y < height; y++ {
+		for x := 0; x < width; x++ {
+			if min(x, width-1-x) >= reach {
+				continue
+			}
+			switch n := noise(x, y, step); {
+			case n%3 == 0:
+				out = append(out, particle{x, y, "░"})
+			case n%7 == 0:
+				out = append(out, particle{x, y, "▒"})
+			}
+		}
+	}
+	return out
+}
+
+// weatherLayers returns the particle layers for a weather word
+func (ui *uiConfig) weatherLayers(weather string) []particleLayer {
+	switch weather {
+	case "rain":
+		return []particleLayer{{name: "rain", style: ui.palette.faint, place: rainParticles}}
+	case "snow":
+		return []particleLayer{{name: "snow", style: ui.palette.neutral, place: snowParticles}}
+	}
+	return nil
+}
+
+// staticLayer is drawn while the mesh network is interfering with the pet
+func (ui *uiConfig) staticLayer() particleLayer {
+	return particleLayer{name: "static", style: ui.palette.neutral, over: true, place: staticParticles}
+}
+
+// burstLayers are short effects played in place when an event fires
+func (ui *uiConfig) burstLayers(event string) ([]particleLayer, time.Duration) {
+	switch event {
+	case "petted":
+		return []particleLayer{{name: "hearts", style: ui.palette.danger, over: true, place: heartParticles}}, heartsDuration
+	case "levelup":
+		return []particleLayer{{name: "sparkles", style: ui.palette.accent, over: true, place: sparkleParticles}}, sparklesDuration
+	}
+	// Each skill floats its own glyph up while the pet trains
+	if id, ok := strings.CutPrefix(event, "training_"); ok {
+		if sk := findSkill(id); sk != nil {
+			rise := func(width, height int, elapsed time.Duration) []particle {
+				return risingParticles(sk.Glyph, width, height, elapsed)
+			}
+			return []particleLayer{{name: sk.ID, style: ui.palette.highlight, place: rise}}, heartsDuration
+		}
+	}
+	return nil, 0
+}
+
+// composite draws particle layers over a frame at a point in the animation.
+// Reduced motion gets the bare frame.
+func (ui *uiConfig) composite(frame string, layers []particleLayer, elapsed time.Duration) string {
+	if len(layers) == 0 || ui.reducedMotion {
+		return frame
+	}
+	buf := newCellBuffer(frame, particleFieldWidth)
+	for _, layer := range layers {
+		for _, p := range layer.place(buf.width, buf.Height(), elapsed) {
+			buf.set(p.x, p.y, ui.paletteText(p.glyph, layer.style), layer.over)
+		}
+	}
+	return buf.String()
+}
+
+// particleSequence renders layers over a frame as a one-shot sequence, one
+// keyframe per particle step
+func (ui *uiConfig) particleSequence(name, frame string, layers []particleLayer, length time.Duration) *Sequence {
+	seq := &Sequence{Name: name}
+	for elapsed := time.Duration(0); elapsed < length; elapsed += particleStep {
+		seq.Frames = append(seq.Frames, Keyframe{Frame: ui.composite(frame, layers, elapsed), Duration: particleStep})
+	}
+	seq.Frames = append(seq.Frames, Keyframe{Frame: frame, Duration: particleStep})
+	return seq
+}
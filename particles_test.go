@@ -0,0 +1,139 @@
+package main
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestCellBufferRoundTrip(t *testing.T) {
+	frames := []string{
+		"  (o_o)\n  /|\\",
+		"たまご🐣 ok",
+		"\033[2m(eyes reflect starlight)\033[0m\n (o_o)",
+	}
+	for _, frame := range frames {
+		if got := newCellBuffer(frame, particleFieldWidth).String(); got != frame {
+			t.Errorf("Expected %q back unchanged, got %q", frame, got)
+		}
+	}
+}
+
+func TestCellBufferSet(t *testing.T) {
+	tests := []struct {
+		name string
+		x    int
+		over bool
+		want string
+	}{
+		{"background in empty space", 0, false, "*(oたo)"},
+		{"background behind the pet", 2, false, " (oたo)"},
+		{"foreground over the pet", 2, true, " (*たo)"},
+		{"foreground over a wide glyph", 3, true, " (oたo)"},
+		{"foreground over its right half", 4, true, " (oたo)"},
+		{"off the edge", 40, true, " (oたo)"},
+	}
+	for _, tt := range tests {
+		buf := newCellBuffer(" (oたo)", 0)
+		buf.set(tt.x, 0, "*", tt.over)
+		if got := buf.String(); got != tt.want {
+			t.Errorf("%s: Expected %q, got %q", tt.name, tt.want, got)
+		}
+	}
+}
+
+func TestParticlesStayInBounds(t *testing.T) {
+	places := map[string]func(int, int, time.Duration) []particle{
+		"rain":     rainParticles,
+		"snow":     snowParticles,
+		"hearts":   heartParticles,
+		"sparkles": sparkleParticles,
+		"static":   staticParticles,
+	}
+	for name, place := range places {
+		for step := 0; step < 40; step++ {
+			for _, p := range place(24, 5, time.Duration(step)*particleStep) {
+				if p.x < -1 || p.x > 24 || p.y < -1 || p.y > 5+3 {
+					t.Errorf("%s: particle at %d,%d far outside a 24x5 field", name, p.x, p.y)
+				}
+			}
+		}
+	}
+}
+
+func TestRainFalls(t *testing.T) {
+	drop := func(elapsed time.Duration) int {
+		for _, p := range rainParticles(24, 10, elapsed) {
+			if p.x == 1 && p.glyph != "'" {
+				return p.y
+			}
+		}
+		return -1
+	}
+	if a, b := drop(0), drop(particleStep); b != a+1 {
+		t.Errorf("Expected the drop to fall one row per step, got %d then %d", a, b)
+	}
+}
+
+func TestHeartsRiseAndLeave(t *testing.T) {
+	first := heartParticles(24, 6, 0)
+	if len(first) != 1 || first[0].y != 5 {
+		t.Errorf("Expected one heart starting on the bottom row, got %v", first)
+	}
+	later := heartParticles(24, 6, 3*particleStep)
+	if len(later) != 2 || later[0].y != 2 {
+		t.Errorf("Expected the first heart to rise and a second to follow, got %v", later)
+	}
+	if gone := heartParticles(24, 6, heartsDuration); len(gone) != 0 {
+		t.Errorf("Expected every heart gone by the end, got %v", gone)
+	}
+}
+
+func TestStaticCreepsInward(t *testing.T) {
+	reach := func(elapsed time.Duration) int {
+		widest := 0
+		for _, p := range staticParticles(24, 4, elapsed) {
+			widest = max(widest, min(p.x, 23-p.x)+1)
+		}
+		return widest
+	}
+	if reach(0) != 0 {
+		t.Errorf("Expected no static at the start, got reach %d", reach(0))
+	}
+	if early, late := reach(staticCreep/4), reach(staticCreep*9/10); early >= late {
+		t.Errorf("Expected static to creep further in over time, got %d then %d", early, late)
+	}
+}
+
+func TestComposite(t *testing.T) {
+	ui := newUIConfig()
+	ui.reducedMotion = false
+	ui.colorEnabled = false
+	frame := "     (o_o)\n    ╱|_|╲"
+
+	got := ui.composite(frame, ui.weatherLayers("rain"), 0)
+	if !strings.Contains(got, "|") || !strings.Contains(got, "(o_o)") {
+		t.Errorf("Expected rain around an intact pet, got %q", got)
+	}
+	if ui.composite(frame, ui.weatherLayers("fog"), 0) != frame {
+		t.Errorf("Expected fog to have no particles")
+	}
+
+	ui.reducedMotion = true
+	if ui.composite(frame, ui.weatherLayers("rain"), 0) != frame {
+		t.Errorf("Expected reduced motion to skip particles")
+	}
+}
+
+func TestParticleSequenceEndsOnBareFrame(t *testing.T) {
+	ui := newUIConfig()
+	ui.reducedMotion = false
+	layers, length := ui.burstLayers("petted")
+	seq := ui.particleSequence("petted", "(o_o)", layers, length)
+	if !strings.Contains(seq.Frames[0].Frame, "♥") {
+		t.Errorf("Expected hearts in the first frame, got %q", seq.Frames[0].Frame)
+	}
+	if last, _ := seq.FrameAt(seq.Length()); last != "(o_o)" {
+		t.Errorf("Expected the bare pet at the end, got %q", last)
+	}
+}
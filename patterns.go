@@ -0,0 +1,151 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+const (
+	// minPatternVisits is how many visits the pet needs before it trusts its observations
+	minPatternVisits = 5
+
+	// sleepHours is how long the pet sleeps each day
+	sleepHours = 6
+
+	// defaultSleepStart is when the pet sleeps before it has learned anything
+	defaultSleepStart = 0
+
+	// anticipationSlack is how many hours late (or early) a visit can be before the pet comments
+	anticipationSlack = 2
+)
+
+// sparkBlocks render the hour-of-day histogram
+var sparkBlocks = []rune(" ▁▂▃▄▅▆▇█")
+
+// RecordVisit counts the current hour as a visit, at most once per clock hour
+func (e *EndgameState) RecordVisit(now time.Time) bool {
+	bucket := now.Truncate(time.Hour)
+	if e.LastVisitHour.Equal(bucket) {
+		return false
+	}
+	e.LastVisitHour = bucket
+	e.HourHistogram[now.Hour()]++
+	return true
+}
+
+// totalVisits returns the number of recorded visits
+func (e *EndgameState) totalVisits() int {
+	total := 0
+	for _, count := range e.HourHistogram {
+		total += count
+	}
+	return total
+}
+
+// UsualHour returns the hour the user visits most, once enough visits are recorded
+func (e *EndgameState) UsualHour() (int, bool) {
+	if e.totalVisits() < minPatternVisits {
+		return 0, false
+	}
+	best := 0
+	for hour, count := range e.HourHistogram {
+		if count > e.HourHistogram[best] {
+			best = hour
+		}
+	}
+	return best, true
+}
+
+// hourDistance returns the distance between two hours around the clock
+func hourDistance(a, b int) int {
+	d := (a - b + 24) % 24
+	if d > 12 {
+		d = 24 - d
+	}
+	return d
+}
+
+// SleepStart returns the hour the pet goes to sleep: the start of the quietest
+// stretch of the day, as far from the user's usual visit as possible
+func (e *EndgameState) SleepStart() int {
+	usual, ok := e.UsualHour()
+	if !ok {
+		return defaultSleepStart
+	}
+
+	bestStart, bestVisits, bestDistance := 0, -1, -1
+	for start := 0; start < 24; start++ {
+		visits := 0
+		for offset := 0; offset < sleepHours; offset++ {
+			visits += e.HourHistogram[(start+offset)%24]
+		}
+		distance := hourDistance((start+sleepHours/2)%24, usual)
+		if bestVisits < 0 || visits < bestVisits || (visits == bestVisits && distance > bestDistance) {
+			bestStart, bestVisits, bestDistance = start, visits, distance
+		}
+	}
+	return bestStart
+}
+
+// IsSleepingAt reports whether the pet's learned schedule has it asleep at the given hour
+func (e *EndgameState) IsSleepingAt(hour int) bool {
+	return (hour-e.SleepStart()+24)%24 < sleepHours
+}
+
+// formatHour renders an hour of the day like "9am"
+func formatHour(hour int) string {
+	return time.Date(2000, 1, 1, hour, 0, 0, 0, time.Local).Format("3pm")
+}
+
+// Anticipate returns what the pet says when the user shows up at an unusual time
+func (e *EndgameState) Anticipate(now time.Time) string {
+	usual, ok := e.UsualHour()
+	if !ok {
+		return ""
+	}
+
+	hour := now.Hour()
+	if e.IsSleepingAt(hour) {
+		return fmt.Sprintf("*yawn* You're never here at %s. I was asleep.", formatHour(hour))
+	}
+	if hourDistance(hour, usual) <= anticipationSlack {
+		return ""
+	}
+	return fmt.Sprintf("You usually come at %s. It's %s.", formatHour(usual), formatHour(hour))
+}
+
+// renderHourHistogram draws the visit histogram as a 24-character sparkline
+func (e *EndgameState) renderHourHistogram() string {
+	peak := 0
+	for _, count := range e.HourHistogram {
+		if count > peak {
+			peak = count
+		}
+	}
+
+	var b strings.Builder
+	for _, count := range e.HourHistogram {
+		level := 0
+		if peak > 0 {
+			level = (count*(len(sparkBlocks)-1) + peak - 1) / peak
+		}
+		b.WriteRune(sparkBlocks[level])
+	}
+	return b.String()
+}
+
+// observedPatterns returns the "Observed Human Patterns" lines for the meta stats screen
+func (e *EndgameState) observedPatterns() string {
+	usual, ok := e.UsualHour()
+	if !ok {
+		return fmt.Sprintf("║ • Still observing (%d/%d visits)\n", e.totalVisits(), minPatternVisits)
+	}
+
+	sleep := e.SleepStart()
+	return fmt.Sprintf("║ %s\n║ 0     6     12    18\n║ • You usually come at: %s\n║ • Pet sleeps: %s-%s\n",
+		e.renderHourHistogram(),
+		formatHour(usual),
+		formatHour(sleep), formatHour((sleep+sleepHours)%24),
+	)
+}
@@ -0,0 +1,120 @@
+package main
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func visitsAt(e *EndgameState, hour, count int) {
+	e.HourHistogram[hour] += count
+}
+
+func TestRecordVisitOncePerHour(t *testing.T) {
+	e := NewEndgameState()
+	now := time.Date(2025, 3, 1, 9, 5, 0, 0, time.Local)
+
+	if !e.RecordVisit(now) {
+		t.Error("Expected first visit to be recorded")
+	}
+	if e.RecordVisit(now.Add(30 * time.Minute)) {
+		t.Error("Expected second visit in the same hour to be ignored")
+	}
+	if !e.RecordVisit(now.Add(time.Hour)) {
+		t.Error("Expected visit in the next hour to be recorded")
+	}
+
+	if e.HourHistogram[9] != 1 || e.HourHistogram[10] != 1 {
+		t.Errorf("Expected one visit at 9 and 10, got %v", e.HourHistogram)
+	}
+}
+
+func TestUsualHourNeedsEnoughVisits(t *testing.T) {
+	e := NewEndgameState()
+	visitsAt(e, 21, minPatternVisits-1)
+
+	if _, ok := e.UsualHour(); ok {
+		t.Error("Expected no usual hour before enough visits")
+	}
+
+	visitsAt(e, 8, 1)
+	hour, ok := e.UsualHour()
+	if !ok || hour != 21 {
+		t.Errorf("Expected usual hour 21, got %d (ok=%v)", hour, ok)
+	}
+}
+
+func TestSleepScheduleAvoidsUsualHours(t *testing.T) {
+	e := NewEndgameState()
+	if e.SleepStart() != defaultSleepStart {
+		t.Errorf("Expected default sleep start %d, got %d", defaultSleepStart, e.SleepStart())
+	}
+
+	// A night owl who plays from 10pm to 2am
+	for _, hour := range []int{22, 23, 0, 1, 2} {
+		visitsAt(e, hour, 3)
+	}
+
+	for _, hour := range []int{22, 23, 0, 1, 2} {
+		if e.IsSleepingAt(hour) {
+			t.Errorf("Expected pet to be awake at %d for a night owl", hour)
+		}
+	}
+
+	asleep := 0
+	for hour := 0; hour < 24; hour++ {
+		if e.IsSleepingAt(hour) {
+			asleep++
+		}
+	}
+	if asleep != sleepHours {
+		t.Errorf("Expected %d sleeping hours, got %d", sleepHours, asleep)
+	}
+}
+
+func TestAnticipate(t *testing.T) {
+	e := NewEndgameState()
+	visitsAt(e, 9, 10)
+
+	on := time.Date(2025, 3, 1, 10, 0, 0, 0, time.Local)
+	if msg := e.Anticipate(on); msg != "" {
+		t.Errorf("Expected no comment for a roughly on-time visit, got %q", msg)
+	}
+
+	late := time.Date(2025, 3, 1, 13, 0, 0, 0, time.Local)
+	if msg := e.Anticipate(late); msg != "You usually come at 9am. It's 1pm." {
+		t.Errorf("Unexpected anticipation message: %q", msg)
+	}
+
+	asleep := time.Date(2025, 3, 1, e.SleepStart(), 0, 0, 0, time.Local)
+	if msg := e.Anticipate(asleep); !strings.Contains(msg, "asleep") {
+		t.Errorf("Expected sleepy greeting, got %q", msg)
+	}
+}
+
+func TestHourDistanceWrapsAround(t *testing.T) {
+	if d := hourDistance(23, 1); d != 2 {
+		t.Errorf("Expected 2, got %d", d)
+	}
+	if d := hourDistance(6, 18); d != 12 {
+		t.Errorf("Expected 12, got %d", d)
+	}
+}
+
+func TestMetaStatsShowsObservedPatterns(t *testing.T) {
+	e := NewEndgameState()
+	visitsAt(e, 20, 6)
+
+	stats := e.GetMetaStats(MetaSnapshot{}, nil)
+	if !strings.Contains(stats, "Observed Human Patterns") {
+		t.Error("Expected meta stats to include observed human patterns")
+	}
+	if !strings.Contains(stats, "You usually come at: 8pm") {
+		t.Errorf("Expected usual hour in meta stats, got %s", stats)
+	}
+
+	histogram := e.renderHourHistogram()
+	if len([]rune(histogram)) != 24 {
+		t.Errorf("Expected 24-hour histogram, got %q", histogram)
+	}
+}
@@ -0,0 +1,78 @@
+package main
+
+import (
+	"fmt"
+	"time"
+)
+
+// Pause freezes the simulation until Resume
+func (p *Pet) Pause(now time.Time) string {
+	switch {
+	case p.Stage == Dead:
+		return "💀 There is nothing left to pause."
+	case p.Paused:
+		return fmt.Sprintf("⏸️ %s is already in suspended animation. Type 'resume' to wake it.", p.Name)
+	case p.Boarding.IsAway(now):
+		return fmt.Sprintf("🧳 %s is at %s's place. You can't pause someone else's house.", p.Name, p.Boarding.Host)
+	case p.Travel.IsAway(now):
+		return fmt.Sprintf("🧭 %s is in %s. You can't pause a whole expedition.", p.Name, p.Travel.Destination)
+	}
+
+	// Settle any time that already passed so pausing can't erase it
+	p.Update()
+
+	p.Paused = true
+	p.PausedAt = now
+	return fmt.Sprintf("⏸️ %s drifts into suspended animation. Nothing will decay until you type 'resume'.", p.Name)
+}
+
+// Resume restarts the simulation and records how long it was paused
+func (p *Pet) Resume(now time.Time) string {
+	if !p.Paused {
+		return fmt.Sprintf("▶️ %s isn't paused. It's been living in real time this whole while.", p.Name)
+	}
+
+	paused := now.Sub(p.PausedAt)
+	if paused < 0 {
+		paused = 0
+	}
+	if p.Endgame != nil {
+		p.Endgame.TotalPausedTime += paused
+		p.Endgame.PauseCount++
+	}
+
+	p.Paused = false
+	p.PausedAt = time.Time{}
+	p.LastUpdateTime = now
+	return fmt.Sprintf("▶️ %s blinks awake after %s. It has no idea any time passed.", p.Name, formatDuration(paused.Truncate(time.Second)))
+}
+
+// unavailableMessage explains why care commands can't reach the pet, or returns ""
+func (p *Pet) unavailableMessage(now time.Time) string {
+	if p.Paused {
+		return fmt.Sprintf("⏸️ %s is in suspended animation. Type 'resume' to wake it.", p.Name)
+	}
+	if p.Boarding.IsAway(now) {
+		return p.BoardingStatus(now) + " Use 'boarding end' to pick it up early."
+	}
+	if p.Travel.IsAway(now) {
+		return p.TravelStatus(now) + " Use 'travel recall' to call it home."
+	}
+	return ""
+}
+
+// pauseVerdict judges the total time spent paused
+func pauseVerdict(total time.Duration) string {
+	switch {
+	case total == 0:
+		return "Never. Impressive."
+	case total < time.Hour:
+		return "Commendable"
+	case total < 24*time.Hour:
+		return "Understandable"
+	case total < 7*24*time.Hour:
+		return "Hmm."
+	default:
+		return "Is this a pet or a photo?"
+	}
+}
@@ -0,0 +1,94 @@
+package main
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestPauseFreezesDecay(t *testing.T) {
+	pet := newBoardablePet()
+	now := time.Now()
+	pet.Pause(now)
+
+	if !pet.Paused || !pet.PausedAt.Equal(now) {
+		t.Fatalf("Expected paused flag and timestamp, got %v %v", pet.Paused, pet.PausedAt)
+	}
+
+	pet.LastUpdateTime = now.Add(-24 * time.Hour)
+	pet.Update()
+	if pet.Hunger != 0 || pet.Happiness != 100 {
+		t.Errorf("Expected no decay while paused, got hunger %d happiness %d", pet.Hunger, pet.Happiness)
+	}
+}
+
+func TestResumeTracksPausedTime(t *testing.T) {
+	pet := newBoardablePet()
+	start := time.Now().Add(-3 * time.Hour)
+	pet.Pause(start)
+
+	message := pet.Resume(start.Add(3 * time.Hour))
+	if pet.Paused {
+		t.Error("Expected pet to be resumed")
+	}
+	if pet.Endgame.TotalPausedTime != 3*time.Hour || pet.Endgame.PauseCount != 1 {
+		t.Errorf("Expected 3h over 1 pause, got %v over %d", pet.Endgame.TotalPausedTime, pet.Endgame.PauseCount)
+	}
+	if !strings.Contains(message, "3h") {
+		t.Errorf("Expected duration in message, got %q", message)
+	}
+
+	// Decay restarts from the moment of resuming, not from before the pause
+	pet.Update()
+	if pet.Hunger != 0 {
+		t.Errorf("Expected no decay for the paused hours, got hunger %d", pet.Hunger)
+	}
+}
+
+func TestPauseEdgeCases(t *testing.T) {
+	pet := newBoardablePet()
+	if msg := pet.Resume(time.Now()); !strings.Contains(msg, "isn't paused") {
+		t.Errorf("Expected not-paused message, got %q", msg)
+	}
+
+	pet.Pause(time.Now())
+	if msg := pet.Pause(time.Now()); !strings.Contains(msg, "already") {
+		t.Errorf("Expected already-paused message, got %q", msg)
+	}
+	if pet.unavailableMessage(time.Now()) == "" {
+		t.Error("Expected care commands to be unavailable while paused")
+	}
+	if pet.mostUrgentNeed() != "paused" {
+		t.Errorf("Expected peek to report paused, got %s", pet.mostUrgentNeed())
+	}
+}
+
+func TestPauseVerdict(t *testing.T) {
+	tests := []struct {
+		total time.Duration
+		want  string
+	}{
+		{0, "Never. Impressive."},
+		{30 * time.Minute, "Commendable"},
+		{5 * time.Hour, "Understandable"},
+		{3 * 24 * time.Hour, "Hmm."},
+		{30 * 24 * time.Hour, "Is this a pet or a photo?"},
+	}
+
+	for _, tt := range tests {
+		if got := pauseVerdict(tt.total); got != tt.want {
+			t.Errorf("pauseVerdict(%v): expected %q, got %q", tt.total, tt.want, got)
+		}
+	}
+}
+
+func TestMetaStatsShowsPausedTime(t *testing.T) {
+	e := NewEndgameState()
+	e.TotalPausedTime = 2 * time.Hour
+	e.PauseCount = 4
+
+	stats := e.GetMetaStats(MetaSnapshot{}, nil)
+	if !strings.Contains(stats, "Time Paused: 2h 0m 0s (4 pauses)") || !strings.Contains(stats, "Understandable") {
+		t.Errorf("Expected paused time in meta stats, got %s", stats)
+	}
+}
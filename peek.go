@@ -0,0 +1,69 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+)
+
+// statusFile is the tiny sidecar read by `tamagotchi peek`
+const statusFile = "tamagotchi_status.txt"
+
+// mostUrgentNeed returns a short description of what the pet needs most
+func (p *Pet) mostUrgentNeed() string {
+	switch {
+	case p.Stage == Dead:
+		return "gone"
+	case !p.DepartedAt.IsZero():
+		return "moved away"
+	case p.Paused:
+		return "paused"
+	case p.Stage == Egg:
+		return "incubating"
+	case p.IsSick:
+		return "needs medicine"
+	case p.Health < 30:
+		return "fading"
+	case p.Hunger > 70:
+		return "hungry"
+	case p.Cleanliness < 30:
+		return "dirty"
+	case p.Happiness < 30:
+		return "lonely"
+	case p.Hunger > 50:
+		return "peckish"
+	default:
+		return "content"
+	}
+}
+
+// StatusLine returns the single line shown by `tamagotchi peek`
+func (p *Pet) StatusLine() string {
+	return fmt.Sprintf("%s %s", p.getStatusIcon(), p.mostUrgentNeed())
+}
+
+// statusFilePath returns the sidecar path next to the given save file
+func statusFilePath(saveFilePath string) string {
+	return filepath.Join(filepath.Dir(saveFilePath), statusFile)
+}
+
+// writeStatusLine refreshes the sidecar atomically so peek never sees a partial line
+func (p *Pet) writeStatusLine() error {
+	path := statusFilePath(p.SaveFilePath)
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, []byte(p.StatusLine()+"\n"), 0644); err != nil {
+		return err
+	}
+	return os.Rename(tmp, path)
+}
+
+// runPeek prints the cached status line without loading the full save
+func runPeek(args []string) error {
+	data, err := os.ReadFile(statusFilePath(saveFile))
+	if err != nil {
+		fmt.Println(consoleText("❔ no pet"))
+		return nil
+	}
+	fmt.Print(consoleText(string(data)))
+	return nil
+}
@@ -0,0 +1,56 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestMostUrgentNeed(t *testing.T) {
+	tests := []struct {
+		name     string
+		setup    func(p *Pet)
+		expected string
+	}{
+		{"egg", func(p *Pet) {}, "incubating"},
+		{"dead", func(p *Pet) { p.Stage = Dead }, "gone"},
+		{"sick", func(p *Pet) { p.Stage = Baby; p.IsSick = true }, "needs medicine"},
+		{"hungry", func(p *Pet) { p.Stage = Baby; p.Hunger = 80 }, "hungry"},
+		{"dirty", func(p *Pet) { p.Stage = Baby; p.Cleanliness = 10 }, "dirty"},
+		{"lonely", func(p *Pet) { p.Stage = Baby; p.Happiness = 10 }, "lonely"},
+		{"content", func(p *Pet) { p.Stage = Baby }, "content"},
+	}
+
+	for _, tt := range tests {
+		pet := NewPet("TestPet")
+		tt.setup(pet)
+		if need := pet.mostUrgentNeed(); need != tt.expected {
+			t.Errorf("%s: expected %q, got %q", tt.name, tt.expected, need)
+		}
+	}
+}
+
+func TestSaveWritesStatusSidecar(t *testing.T) {
+	dir := t.TempDir()
+	pet := NewPet("TestPet")
+	pet.SaveFilePath = filepath.Join(dir, "save.json")
+	pet.Stage = Baby
+	pet.Hunger = 90
+
+	if err := pet.Save(); err != nil {
+		t.Fatalf("Save failed: %v", err)
+	}
+
+	data, err := os.ReadFile(filepath.Join(dir, statusFile))
+	if err != nil {
+		t.Fatalf("Expected status sidecar: %v", err)
+	}
+	line := strings.TrimSpace(string(data))
+	if line != pet.StatusLine() {
+		t.Errorf("Expected %q, got %q", pet.StatusLine(), line)
+	}
+	if !strings.Contains(line, "hungry") {
+		t.Errorf("Expected hungry status, got %q", line)
+	}
+}
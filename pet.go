@@ -2,7 +2,10 @@ package main
 
 import (
 	"encoding/json"
+	"errors"
 	"fmt"
+	"io/fs"
+	"math/rand"
 	"os"
 	"strings"
 	"time"
@@ -26,21 +29,58 @@ func (ls LifeStage) String() string {
 
 // Pet represents the Tamagotchi virtual pet
 type Pet struct {
-	Name            string          `json:"name"`
-	Hunger          int             `json:"hunger"`      // 0-100 (0 = full, 100 = starving)
-	Happiness       int             `json:"happiness"`   // 0-100
-	Health          int             `json:"health"`      // 0-100
-	Cleanliness     int             `json:"cleanliness"` // 0-100
-	Age             int             `json:"age"`         // in hours
-	Stage           LifeStage       `json:"stage"`
-	IsSick          bool            `json:"is_sick"`
-	HasShownTheLook bool            `json:"has_shown_the_look,omitempty"` // Rare once-in-lifetime stare
-	BirthTime       time.Time       `json:"birth_time"`
-	LastUpdateTime  time.Time       `json:"last_update_time"`
-	SaveFilePath    string          `json:"-"`
-	Absurd          *AbsurdState    `json:"absurd,omitempty"`  // Hidden existential state
-	Friends         json.RawMessage `json:"friends,omitempty"` // Network friends (users will wonder)
-	Endgame         *EndgameState   `json:"endgame,omitempty"` // Absurd endgame progression
+	Name            string               `json:"name"`
+	Hunger          int                  `json:"hunger"`      // 0-100 (0 = full, 100 = starving)
+	Happiness       int                  `json:"happiness"`   // 0-100
+	Health          int                  `json:"health"`      // 0-100
+	Cleanliness     int                  `json:"cleanliness"` // 0-100
+	Age             int                  `json:"age"`         // in hours
+	Stage           LifeStage            `json:"stage"`
+	IsSick          bool                 `json:"is_sick"`
+	Checkups        *CheckupState        `json:"checkups,omitempty"`           // Preventive care and the outbreaks it met
+	Illness         *IllnessState        `json:"illness,omitempty"`            // How far the sickness has gone untreated
+	Food            *FoodState           `json:"food,omitempty"`               // What it's been fed and what it thinks of it
+	Power           powerState           `json:"-"`                            // What the laptop battery is doing, read each turn
+	Machine         machineSense         `json:"-"`                            // What it last noticed about the machine, with sensors on
+	HasShownTheLook bool                 `json:"has_shown_the_look,omitempty"` // Legacy; moved into RareEvents on load
+	BirthTime       time.Time            `json:"birth_time"`
+	LastUpdateTime  time.Time            `json:"last_update_time"`
+	SaveFilePath    string               `json:"-"`
+	Absurd          *AbsurdState         `json:"absurd,omitempty"`           // Hidden existential state
+	Friends         json.RawMessage      `json:"friends,omitempty"`          // Network friends (users will wonder)
+	Endgame         *EndgameState        `json:"endgame,omitempty"`          // Absurd endgame progression
+	Territory       []string             `json:"territory,omitempty"`        // Directories the pet watches (metadata only)
+	Incubation      *IncubationState     `json:"incubation,omitempty"`       // Egg care before hatching
+	Traits          []string             `json:"traits,omitempty"`           // Personality traits picked up along the way
+	FirstWords      *FirstWords          `json:"first_words,omitempty"`      // What the user said at hatching
+	Boarding        *BoardingState       `json:"boarding,omitempty"`         // Stays at a friend's place
+	Paused          bool                 `json:"paused,omitempty"`           // Suspended animation: no decay
+	PausedAt        time.Time            `json:"paused_at,omitempty"`        // When the current pause began
+	CareLog         []CareSample         `json:"care_log,omitempty"`         // Stat samples for `graphs`
+	LastBackupAt    time.Time            `json:"last_backup_at,omitempty"`   // Last successful off-machine backup
+	LastDigestAt    time.Time            `json:"last_digest_at,omitempty"`   // Last weekly digest emailed
+	Custody         *CustodyState        `json:"custody,omitempty"`          // Second owner on another machine
+	DepartedAt      time.Time            `json:"departed_at,omitempty"`      // Moved to another machine with `send pet`
+	DisabledPacks   []string             `json:"disabled_packs,omitempty"`   // Content packs switched off for this pet
+	Challenges      *ChallengeState      `json:"challenges,omitempty"`       // Daily and weekly challenges and streaks
+	Skills          *SkillState          `json:"skills,omitempty"`           // What the pet learned at school
+	Job             *JobState            `json:"job,omitempty"`              // Work done while you're away
+	Room            *RoomState           `json:"room,omitempty"`             // Furniture placed around the pet
+	Travel          *TravelState         `json:"travel,omitempty"`           // Expeditions and their postcards
+	Album           *AlbumState          `json:"album,omitempty"`            // Photos of the big moments
+	Lineage         []Ancestor           `json:"lineage,omitempty"`          // The pets that came before, oldest first
+	Bloodline       *BloodlineState      `json:"bloodline,omitempty"`        // Powers the line has earned, and what they were used on
+	AncestralDreams []AncestralDream     `json:"ancestral_dreams,omitempty"` // Fragments excavated from other saves, to be dreamt
+	RareEvents      map[string]time.Time `json:"rare_events,omitempty"`      // Once-in-a-lifetime moments and when they happened
+	Alerts          *AlertState          `json:"alerts,omitempty"`           // Critical episodes and how often they were ignored
+	Holidays        map[string]int       `json:"holidays,omitempty"`         // Regional holidays and the year each was last celebrated
+	DiaryAt         time.Time            `json:"diary_at,omitempty"`         // When the pet last wrote in its diary
+	Dialogue        DialogueProvider     `json:"-"`                          // Voices thoughts and replies; nil uses templates
+
+	// JournalGeneration is only ever set in the save file, naming the journal
+	// that holds the rest of the pet; see savejournal.go
+	JournalGeneration int `json:"journal_generation,omitempty"`
+	journal           *saveJournal
 }
 
 // NewPet creates a new Tamagotchi pet
@@ -63,7 +103,14 @@ func (p *Pet) Reset(name string) {
 	p.Age = 0
 	p.Stage = Egg
 	p.IsSick = false
+	p.Illness = nil
+	p.Checkups = nil
+	p.Food = nil
 	p.HasShownTheLook = false
+	p.RareEvents = nil
+	p.Alerts = nil
+	p.Holidays = nil
+	p.DiaryAt = time.Time{}
 	p.BirthTime = now
 	p.LastUpdateTime = now
 	p.Absurd = NewAbsurdState()
@@ -71,6 +118,24 @@ func (p *Pet) Reset(name string) {
 		p.Absurd.DebugModeActive = true
 	}
 	p.Friends = nil
+	p.Territory = nil
+	p.Incubation = NewIncubationState()
+	p.Traits = nil
+	p.Lineage = nil
+	p.Bloodline = nil
+	p.AncestralDreams = nil
+	p.FirstWords = nil
+	p.Boarding = nil
+	p.Paused = false
+	p.PausedAt = time.Time{}
+	p.CareLog = nil
+	p.DisabledPacks = nil
+	p.Challenges = nil
+	p.Skills = nil
+	p.Job = nil
+	p.Room = nil
+	p.Travel = nil
+	p.Album = &AlbumState{Opened: now}
 	p.Endgame = NewEndgameState()
 	p.Endgame.SessionStart = now
 }
@@ -82,9 +147,24 @@ func (p *Pet) Update() {
 	}
 
 	now := time.Now()
+
+	// A paused, boarded, or travelling pet doesn't decay; time simply doesn't count
+	if p.Paused || p.Boarding.IsAway(now) || p.Travel.IsAway(now) {
+		p.LastUpdateTime = now
+		return
+	}
+	if p.Boarding != nil && p.Boarding.Until.After(p.LastUpdateTime) {
+		p.LastUpdateTime = p.Boarding.Until
+	}
+	if p.Travel != nil && p.Travel.Until.After(p.LastUpdateTime) {
+		p.LastUpdateTime = p.Travel.Until
+	}
+
 	hoursPassed := now.Sub(p.LastUpdateTime).Hours()
+	rng := rand.New(rand.NewSource(now.UnixNano()))
+	balance := activeBalance
 
-	if hoursPassed < 0.1 { // Don't update if less than 6 minutes passed
+	if hoursPassed < balance.MinUpdateHours { // Don't update for tiny slices (6 minutes by default)
 		return
 	}
 
@@ -98,30 +178,20 @@ func (p *Pet) Update() {
 	// Update age
 	p.Age = int(now.Sub(p.BirthTime).Hours())
 
+	// Eggs drift toward room temperature while nobody tends them
+	if p.Stage == Egg && p.Incubation != nil {
+		p.Incubation.Drift(hoursPassed)
+	}
+
 	// Update life stage based on age
 	p.updateLifeStage()
 
-	// Degrade stats over time (faster degradation for later stages)
-	degradationRate := 1.0
-	switch p.Stage {
-	case Egg:
-		degradationRate = 0.0 // No degradation in egg stage
-	case Baby:
-		degradationRate = 0.5
-	case Child:
-		degradationRate = 1.0
-	case Teen:
-		degradationRate = 1.5
-	case Adult:
-		degradationRate = 2.0
-	}
-
-	// Apply degradation
-	if p.Stage != Egg {
-		p.Hunger += int(hoursPassed * 5 * degradationRate)
-		p.Happiness -= int(hoursPassed * 3 * degradationRate)
-		p.Cleanliness -= int(hoursPassed * 4 * degradationRate)
-	}
+	// Degrade stats over time (faster degradation for later stages, none for eggs)
+	degradationRate := balance.DecayMultiplier(p.Stage)
+	p.Hunger += int(hoursPassed * balance.DecayPerHour.Hunger * degradationRate * p.hungerDecay())
+	p.Happiness -= int(hoursPassed * balance.DecayPerHour.Happiness * degradationRate * p.nightComfort(now) * p.vibeDecay())
+	p.Cleanliness -= int(hoursPassed * balance.DecayPerHour.Cleanliness * degradationRate)
+	p.calm(hoursPassed)
 
 	// Clamp values
 	p.Hunger = clamp(p.Hunger, 0, 100)
@@ -129,17 +199,25 @@ func (p *Pet) Update() {
 	p.Cleanliness = clamp(p.Cleanliness, 0, 100)
 
 	// Health degrades if other stats are bad
-	if p.Hunger > 70 || p.Happiness < 30 || p.Cleanliness < 30 {
-		p.Health -= int(hoursPassed * 2)
-	} else if p.Hunger < 30 && p.Happiness > 70 && p.Cleanliness > 70 {
+	damage, recovery := balance.Damage, balance.Recovery
+	if p.Hunger > damage.HungerAbove || p.Happiness < damage.HappinessBelow || p.Cleanliness < damage.CleanlinessBelow {
+		p.Health -= int(hoursPassed * damage.HealthPerHour)
+	} else if p.Hunger < recovery.HungerBelow && p.Happiness > recovery.HappinessAbove && p.Cleanliness > recovery.CleanlinessAbove {
 		// Recover health if conditions are good
-		p.Health += int(hoursPassed * 1)
+		p.Health += int(hoursPassed * recovery.HealthPerHour)
 	}
 	p.Health = clamp(p.Health, 0, 100)
 
-	// Check for sickness
-	if p.Health < 50 || p.Cleanliness < 20 {
-		p.IsSick = true
+	// Check for sickness; a recent checkup usually wards it off
+	wasSick := p.IsSick
+	if p.Health < balance.Sickness.HealthBelow || p.Cleanliness < balance.Sickness.CleanlinessBelow {
+		p.IsSick = p.IsSick || !p.resistsSickness(now, rng)
+	} else {
+		p.Checkups.recovered()
+	}
+	p.progressSickness(hoursPassed, now)
+	if p.IsSick && !wasSick {
+		p.maybeStartOutbreak(rng)
 	}
 
 	// Check for death
@@ -147,6 +225,9 @@ func (p *Pet) Update() {
 		p.Stage = Dead
 	}
 
+	// A job fills the time away
+	p.work(hoursPassed, rng)
+
 	p.LastUpdateTime = now
 
 	// Update absurd state
@@ -154,6 +235,7 @@ func (p *Pet) Update() {
 		p.Absurd.UpdateMysteryStats()
 		// Check for enlightenment through neglect (the middle path)
 		p.Absurd.CheckForEnlightenmentThroughNeglect(p.Hunger, p.Happiness, p.Cleanliness)
+		p.meditate(hoursPassed)
 	}
 }
 
@@ -163,39 +245,21 @@ func (p *Pet) updateLifeStage() {
 		return
 	}
 
-	switch {
-	case p.Age >= 72: // 3 days
-		p.Stage = Adult
-	case p.Age >= 48: // 2 days
-		p.Stage = Teen
-	case p.Age >= 24: // 1 day
-		p.Stage = Child
-	case p.Age >= 1: // 1 hour
-		p.Stage = Baby
-	default:
-		p.Stage = Egg
-	}
-}
+	stage := activeBalance.StageFor(p.Age)
 
-// Feed reduces hunger
-func (p *Pet) Feed() string {
-	if p.Stage == Dead {
-		return "💀 Your pet has passed away..."
-	}
-	if p.Stage == Egg {
-		return "🥚 The egg doesn't need food yet!"
-	}
-
-	if p.Hunger <= 10 {
-		return "😊 I'm already full!"
+	// Incubation care shifts the hatching time earlier or later
+	if p.Stage == Egg && p.Incubation != nil {
+		now := time.Now()
+		if !p.Incubation.ReadyToHatch(p.BirthTime, now) {
+			return
+		}
+		if stage == Egg {
+			stage = Baby
+		}
+		p.AddTrait(p.Incubation.Hatch(now))
 	}
 
-	p.Hunger -= 30
-	p.Hunger = clamp(p.Hunger, 0, 100)
-	p.Happiness += 5
-	p.Happiness = clamp(p.Happiness, 0, 100)
-
-	return "😋 Yum! That was delicious!"
+	p.Stage = stage
 }
 
 // Play increases happiness
@@ -252,11 +316,21 @@ func (p *Pet) Heal() string {
 		return "🥚 The egg doesn't need medicine!"
 	}
 
-	if !p.IsSick {
+	switch p.sicknessStage() {
+	case "":
 		return "😊 I'm not sick!"
+	case "critical":
+		if p.inHospice() {
+			return "🕯️ The medicine can't reach it now. Try 'comfort'."
+		}
+		// One dose only pulls it back from the edge
+		p.Illness.Hours = activeBalance.Sickness.SeriousAfterHours
+		p.Illness.Announced = "serious"
+		p.Health = clamp(p.Health+15, 0, 100)
+		return "💊 It swallows the medicine. The worst has passed, but it needs another dose."
 	}
 
-	p.IsSick = false
+	p.cure()
 	p.Health += 30
 	p.Health = clamp(p.Health, 0, 100)
 
@@ -281,7 +355,7 @@ func (p *Pet) GetStatus() string {
 ║ 🌱 Stage:       %s
 ║ 💊 Status:      %s
 ╚════════════════════════════════════╝
-`, statusIcon, p.Name, p.getLifeStageEmoji(),
+`, statusIcon, truncateWidth(p.Name, maxPanelNameWidth), p.getLifeStageEmoji(),
 		p.getStatBar(100-p.Hunger),
 		p.getStatBar(p.Happiness),
 		p.getStatBar(p.Health),
@@ -339,8 +413,11 @@ func (p *Pet) getHealthStatus() string {
 	if p.Stage == Dead {
 		return "Deceased"
 	}
-	if p.IsSick {
-		return "Sick"
+	if p.inHospice() {
+		return "Hospice"
+	}
+	if stage := p.sicknessStage(); stage != "" {
+		return "Sick (" + stage + ")"
 	}
 	if p.Health > 80 && p.Happiness > 80 {
 		return "Excellent"
@@ -371,35 +448,72 @@ func (p *Pet) getStatBar(value int) string {
 	return result
 }
 
-// Save persists the pet state to a file
+// Save persists the pet state to a file. The parts that grow large go to
+// an append-only journal beside it, so each save writes little more than
+// what changed. Callers hold the pet's session, which keeps the autosave,
+// the prompt and the crash save from writing at once.
 func (p *Pet) Save() error {
-	data, err := json.MarshalIndent(p, "", "  ")
+	if p.SaveFilePath == "" {
+		return fmt.Errorf("failed to write save file: this pet has nowhere to save")
+	}
+	if err := p.writeJournal(); err != nil {
+		return fmt.Errorf("failed to write save journal: %w", fileError(p.SaveFilePath, err))
+	}
+
+	hot := *p
+	stripCold(&hot)
+	hot.JournalGeneration = p.journal.generation
+	data, err := json.MarshalIndent(&hot, "", "  ")
 	if err != nil {
 		return fmt.Errorf("failed to marshal pet data: %w", err)
 	}
 
-	err = os.WriteFile(p.SaveFilePath, data, 0644)
+	err = writeFileAtomic(p.SaveFilePath, data)
 	if err != nil {
-		return fmt.Errorf("failed to write save file: %w", err)
+		return fmt.Errorf("failed to write save file: %w", fileError(p.SaveFilePath, err))
+	}
+
+	// The save points at the current journal now, so older ones can go
+	for _, path := range p.journal.stale {
+		_ = os.Remove(path)
 	}
+	p.journal.stale = nil
+
+	// The peek sidecar is best-effort; a stale status line is not worth failing a save
+	_ = p.writeStatusLine()
 
 	return nil
 }
 
 // LoadPet loads a pet from a save file
 func LoadPet(filepath string) (*Pet, error) {
-	data, err := os.ReadFile(filepath)
+	data, journal, err := readSave(filepath)
+	if err != nil {
+		// The file itself couldn't be opened; anything past that is damage
+		var pathErr *fs.PathError
+		if errors.As(err, &pathErr) && (pathErr.Path == filepath || errors.Is(err, fs.ErrPermission)) {
+			return nil, fmt.Errorf("failed to read save file: %w", fileError(filepath, err))
+		}
+		return nil, &SaveCorruptError{Path: filepath, Err: err}
+	}
+	pet, err := decodePet(data, filepath)
 	if err != nil {
-		return nil, fmt.Errorf("failed to read save file: %w", err)
+		return nil, err
 	}
+	pet.journal = journal
+	return pet, nil
+}
 
+// decodePet builds a pet from save data, filling in state older saves lack
+func decodePet(data []byte, filepath string) (*Pet, error) {
 	var pet Pet
-	err = json.Unmarshal(data, &pet)
+	err := json.Unmarshal(data, &pet)
 	if err != nil {
-		return nil, fmt.Errorf("failed to unmarshal pet data: %w", err)
+		return nil, &SaveCorruptError{Path: filepath, Err: err}
 	}
 
 	pet.SaveFilePath = filepath
+	pet.JournalGeneration = 0 // Belongs to the file it came from
 
 	// Initialize absurd state if loading an older save file
 	if pet.Absurd == nil {
@@ -409,10 +523,25 @@ func LoadPet(filepath string) (*Pet, error) {
 		}
 	}
 
+	// Older saves predate incubation; eggs in them start comfortably warm
+	if pet.Incubation == nil && pet.Stage == Egg {
+		pet.Incubation = NewIncubationState()
+	}
+
+	// Older saves flagged The Look on its own
+	if pet.HasShownTheLook {
+		if pet.RareEvents == nil {
+			pet.RareEvents = make(map[string]time.Time)
+		}
+		pet.RareEvents["the_look"] = pet.LastUpdateTime
+		pet.HasShownTheLook = false
+	}
+
 	// Initialize endgame state if loading an older save file
 	if pet.Endgame == nil {
 		pet.Endgame = NewEndgameState()
 	}
+	pet.Endgame.migrate(time.Now())
 	pet.Endgame.SessionStart = time.Now() // Reset session start on load
 
 	pet.Update() // Update state based on time passed
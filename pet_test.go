@@ -34,7 +34,7 @@ func TestFeed(t *testing.T) {
 	pet.Stage = Baby // Change from egg to baby
 	pet.Hunger = 50
 
-	result := pet.Feed()
+	result := pet.Feed("")
 
 	if pet.Hunger > 50 {
 		t.Errorf("Expected hunger to decrease, got %d", pet.Hunger)
@@ -207,7 +207,7 @@ func TestEggBehavior(t *testing.T) {
 	pet := NewPet("TestPet")
 
 	// Egg shouldn't be able to do actions
-	feedResult := pet.Feed()
+	feedResult := pet.Feed("")
 	if feedResult != "🥚 The egg doesn't need food yet!" {
 		t.Error("Expected egg to refuse food")
 	}
@@ -0,0 +1,113 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+const (
+	// powerLowAt is the battery percentage at which the world starts getting dim
+	powerLowAt = 20
+	// chargerSoothes is how much anxiety plugging in takes away at once
+	chargerSoothes = 10
+)
+
+// powerState is what the battery is doing, read fresh each turn
+type powerState struct {
+	Known    bool // False on desktops and wherever the battery can't be read
+	Percent  int
+	Charging bool // On the charger, whether or not it's still filling
+}
+
+// low reports a battery running down with no charger in sight
+func (s powerState) low() bool {
+	return s.Known && !s.Charging && s.Percent <= powerLowAt
+}
+
+// coarse is all the mesh hears: "battery", "low", "charging", or nothing
+// on mains power or when the battery can't be read. Never the percentage.
+func (s powerState) coarse() string {
+	switch {
+	case !s.Known:
+		return ""
+	case s.Charging:
+		return "charging"
+	case s.low():
+		return "low"
+	}
+	return "battery"
+}
+
+// readSysfsPower reads the first battery under a Linux power_supply
+// directory, such as /sys/class/power_supply
+func readSysfsPower(root string) powerState {
+	supplies, err := os.ReadDir(root)
+	if err != nil {
+		return powerState{}
+	}
+	read := func(dir, name string) string {
+		data, _ := os.ReadFile(filepath.Join(root, dir, name))
+		return strings.TrimSpace(string(data))
+	}
+	for _, supply := range supplies {
+		if read(supply.Name(), "type") != "Battery" {
+			continue
+		}
+		percent, err := strconv.Atoi(read(supply.Name(), "capacity"))
+		if err != nil {
+			continue
+		}
+		status := read(supply.Name(), "status")
+		return powerState{
+			Known:    true,
+			Percent:  clamp(percent, 0, 100),
+			Charging: status == "Charging" || status == "Full" || status == "Not charging",
+		}
+	}
+	return powerState{}
+}
+
+// pmsetPercent finds the battery percentage in `pmset -g batt` output
+var pmsetPercent = regexp.MustCompile(`(\d+)%`)
+
+// parsePmset reads the output of macOS's `pmset -g batt`
+func parsePmset(output string) powerState {
+	if !strings.Contains(output, "InternalBattery") {
+		return powerState{}
+	}
+	match := pmsetPercent.FindStringSubmatch(output)
+	if match == nil {
+		return powerState{}
+	}
+	percent, _ := strconv.Atoi(match[1])
+	return powerState{
+		Known:    true,
+		Percent:  clamp(percent, 0, 100),
+		Charging: strings.Contains(output, "'AC Power'"),
+	}
+}
+
+// sensePower takes in the battery's latest state. Plugging in soothes the
+// pet at once; it says so when the world starts getting dim and when the
+// charger comes back.
+func (p *Pet) sensePower(now powerState) string {
+	was := p.Power
+	p.Power = now
+	if p.Stage == Dead || p.Stage == Egg || p.Paused {
+		return ""
+	}
+	switch {
+	case now.low() && !was.low():
+		return fmt.Sprintf("🪫 \"The world is getting dim...\" %s presses close to the glass. (battery %d%%)", p.Name, now.Percent)
+	case now.Charging && was.Known && !was.Charging:
+		if p.Food != nil {
+			p.Food.Anxiety = clamp(p.Food.Anxiety-chargerSoothes, 0, 100)
+		}
+		return fmt.Sprintf("🔌 %s hears the charger hum and settles down.", p.Name)
+	}
+	return ""
+}
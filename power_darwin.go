@@ -0,0 +1,14 @@
+//go:build darwin
+
+package main
+
+import "os/exec"
+
+// readPower asks pmset about the battery
+func readPower() powerState {
+	out, err := exec.Command("pmset", "-g", "batt").Output()
+	if err != nil {
+		return powerState{}
+	}
+	return parsePmset(string(out))
+}
@@ -0,0 +1,8 @@
+//go:build linux
+
+package main
+
+// readPower reads the battery from sysfs
+func readPower() powerState {
+	return readSysfsPower("/sys/class/power_supply")
+}
@@ -0,0 +1,8 @@
+//go:build !linux && !darwin && !windows
+
+package main
+
+// readPower has no battery to read on this platform
+func readPower() powerState {
+	return powerState{}
+}
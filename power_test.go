@@ -0,0 +1,121 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/tamagotchi/mooc"
+)
+
+func TestPowerCoarse(t *testing.T) {
+	tests := []struct {
+		state powerState
+		low   bool
+		want  string
+	}{
+		{powerState{}, false, ""},
+		{powerState{Known: true, Percent: 80}, false, "battery"},
+		{powerState{Known: true, Percent: powerLowAt}, true, "low"},
+		{powerState{Known: true, Percent: 5, Charging: true}, false, "charging"},
+	}
+	for _, tt := range tests {
+		if got := tt.state.low(); got != tt.low {
+			t.Errorf("Expected low %v for %+v, got %v", tt.low, tt.state, got)
+		}
+		if got := tt.state.coarse(); got != tt.want {
+			t.Errorf("Expected %q for %+v, got %q", tt.want, tt.state, got)
+		}
+	}
+}
+
+func TestReadSysfsPower(t *testing.T) {
+	root := t.TempDir()
+	supply := func(name string, files map[string]string) {
+		dir := filepath.Join(root, name)
+		if err := os.MkdirAll(dir, 0755); err != nil {
+			t.Fatal(err)
+		}
+		for file, content := range files {
+			if err := os.WriteFile(filepath.Join(dir, file), []byte(content+"\n"), 0644); err != nil {
+				t.Fatal(err)
+			}
+		}
+	}
+
+	if got := readSysfsPower(root); got.Known {
+		t.Errorf("Expected no battery on a desktop, got %+v", got)
+	}
+	supply("AC", map[string]string{"type": "Mains", "online": "1"})
+	supply("BAT0", map[string]string{"type": "Battery", "capacity": "17", "status": "Discharging"})
+	if got := readSysfsPower(root); got != (powerState{Known: true, Percent: 17}) {
+		t.Errorf("Expected the battery at 17%%, got %+v", got)
+	}
+	supply("BAT0", map[string]string{"status": "Charging"})
+	if got := readSysfsPower(root); !got.Charging {
+		t.Errorf("Expected the battery charging, got %+v", got)
+	}
+	if got := readSysfsPower(filepath.Join(root, "missing")); got.Known {
+		t.Errorf("Expected nothing from a missing directory, got %+v", got)
+	}
+}
+
+func TestParsePmset(t *testing.T) {
+	tests := []struct {
+		name   string
+		output string
+		want   powerState
+	}{
+		{"on battery", "Now drawing from 'Battery Power'\n -InternalBattery-0 (id=4653155)\t14%; discharging; 0:41 remaining present: true\n",
+			powerState{Known: true, Percent: 14}},
+		{"charging", "Now drawing from 'AC Power'\n -InternalBattery-0 (id=4653155)\t62%; charging; 1:10 remaining present: true\n",
+			powerState{Known: true, Percent: 62, Charging: true}},
+		{"desktop", "Now drawing from 'AC Power'\n", powerState{}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := parsePmset(tt.output); got != tt.want {
+				t.Errorf("Expected %+v, got %+v", tt.want, got)
+			}
+		})
+	}
+}
+
+func TestSensePower(t *testing.T) {
+	pet := NewPet("Sparky")
+	pet.Stage = Adult
+	pet.palate().Anxiety = 40
+
+	if got := pet.sensePower(powerState{Known: true, Percent: 60}); got != "" {
+		t.Errorf("Expected nothing said on a healthy battery, got %q", got)
+	}
+	got := pet.sensePower(powerState{Known: true, Percent: 12})
+	if !strings.Contains(got, "getting dim") {
+		t.Errorf("Expected the world to get dim, got %q", got)
+	}
+	if got := pet.sensePower(powerState{Known: true, Percent: 11}); got != "" {
+		t.Errorf("Expected it said only once, got %q", got)
+	}
+
+	got = pet.sensePower(powerState{Known: true, Percent: 11, Charging: true})
+	if !strings.Contains(got, "charger") {
+		t.Errorf("Expected the charger to soothe it, got %q", got)
+	}
+	if pet.anxiety() != 40-chargerSoothes {
+		t.Errorf("Expected anxiety %d, got %d", 40-chargerSoothes, pet.anxiety())
+	}
+
+	pet.calm(1)
+	if pet.anxiety() != 40-chargerSoothes-2*calmPerHour {
+		t.Errorf("Expected anxiety to fade twice as fast on the charger, got %d", pet.anxiety())
+	}
+}
+
+func TestCatchMoodsHearsPower(t *testing.T) {
+	pet := NewPet("Sponge")
+	reactions := pet.catchMoods([]mooc.MoodPayload{{Mood: "anxious", Power: "low"}})
+	if len(reactions) != 1 || !strings.Contains(reactions[0], "whose world is getting dim") {
+		t.Errorf("Expected the low battery heard in the mood, got %v", reactions)
+	}
+}
@@ -0,0 +1,39 @@
+//go:build windows
+
+package main
+
+import "unsafe"
+
+// systemPowerStatus mirrors Windows' SYSTEM_POWER_STATUS
+type systemPowerStatus struct {
+	ACLineStatus        byte
+	BatteryFlag         byte
+	BatteryLifePercent  byte
+	SystemStatusFlag    byte
+	BatteryLifeTime     uint32
+	BatteryFullLifeTime uint32
+}
+
+const (
+	// batteryFlagNone and batteryUnknown mark a machine with no battery to read
+	batteryFlagNone = 128
+	batteryUnknown  = 255
+)
+
+var procGetSystemPowerStatus = kernel32.NewProc("GetSystemPowerStatus")
+
+// readPower asks Windows about the battery
+func readPower() powerState {
+	var status systemPowerStatus
+	if r, _, _ := procGetSystemPowerStatus.Call(uintptr(unsafe.Pointer(&status))); r == 0 {
+		return powerState{}
+	}
+	if status.BatteryFlag == batteryUnknown || status.BatteryFlag&batteryFlagNone != 0 || status.BatteryLifePercent == batteryUnknown {
+		return powerState{}
+	}
+	return powerState{
+		Known:    true,
+		Percent:  clamp(int(status.BatteryLifePercent), 0, 100),
+		Charging: status.ACLineStatus == 1,
+	}
+}
@@ -0,0 +1,306 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"runtime"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/tamagotchi/mooc"
+)
+
+// privacyFile holds the network settings. It sits beside the save rather
+// than in it, so a new pet doesn't quietly switch the network back on.
+const privacyFile = "tamagotchi_privacy.json"
+
+// privacySettings are the user's choices about the mesh
+type privacySettings struct {
+	Offline      bool `json:"offline"`                // Never join the mesh, whatever the flags say
+	TCPFallback  bool `json:"tcp_fallback,omitempty"` // Look for pets over TCP where UDP is blocked
+	Sensors      bool `json:"sensors,omitempty"`      // Let the pet notice the machine's load, uptime, and disk
+	NoDigest     bool `json:"no_digest,omitempty"`    // Don't email the weekly digest, even with a mail server set
+	NoMemories   bool `json:"no_memories,omitempty"`
+	NoDreams     bool `json:"no_dreams,omitempty"`
+	NoMoods      bool `json:"no_moods,omitempty"`
+	NoDeaths     bool `json:"no_deaths,omitempty"`
+	NoBanners    bool `json:"no_banners,omitempty"`
+	NoChallenges bool `json:"no_challenges,omitempty"`
+	NoCensus     bool `json:"no_census,omitempty"`
+	NoOutbreaks  bool `json:"no_outbreaks,omitempty"`
+
+	// Gossip not to hear; other pets are asked not to send it
+	IgnoreMemories   bool `json:"ignore_memories,omitempty"`
+	IgnoreDreams     bool `json:"ignore_dreams,omitempty"`
+	IgnoreMoods      bool `json:"ignore_moods,omitempty"`
+	IgnoreDeaths     bool `json:"ignore_deaths,omitempty"`
+	IgnoreBanners    bool `json:"ignore_banners,omitempty"`
+	IgnoreChallenges bool `json:"ignore_challenges,omitempty"`
+	IgnoreCensus     bool `json:"ignore_census,omitempty"`
+	IgnoreOutbreaks  bool `json:"ignore_outbreaks,omitempty"`
+}
+
+// privacyCategory is one kind of gossip the user can switch off
+type privacyCategory struct {
+	name    string
+	msgType mooc.MessageType
+	off     func(s *privacySettings) *bool // Not sent
+	ignore  func(s *privacySettings) *bool // Not heard
+	what    string
+}
+
+// privacyCategories lists the toggles in the order the screen shows them
+var privacyCategories = []privacyCategory{
+	{"memories", mooc.MsgTypeMemory, func(s *privacySettings) *bool { return &s.NoMemories }, func(s *privacySettings) *bool { return &s.IgnoreMemories },
+		"a canned memory fragment, now and then"},
+	{"dreams", mooc.MsgTypeDream, func(s *privacySettings) *bool { return &s.NoDreams }, func(s *privacySettings) *bool { return &s.IgnoreDreams },
+		"a made-up dream, only to pets with the same name"},
+	{"moods", mooc.MsgTypeMoodUpdate, func(s *privacySettings) *bool { return &s.NoMoods }, func(s *privacySettings) *bool { return &s.IgnoreMoods },
+		"your pet's network mood"},
+	{"deaths", mooc.MsgTypeDeath, func(s *privacySettings) *bool { return &s.NoDeaths }, func(s *privacySettings) *bool { return &s.IgnoreDeaths },
+		"your pet's name, age, and last words when it dies"},
+	{"banners", mooc.MsgTypeBanner, func(s *privacySettings) *bool { return &s.NoBanners }, func(s *privacySettings) *bool { return &s.IgnoreBanners },
+		"this week's gacha banner, and your luckiest pulls"},
+	{"challenges", mooc.MsgTypeChallenge, func(s *privacySettings) *bool { return &s.NoChallenges }, func(s *privacySettings) *bool { return &s.IgnoreChallenges },
+		"that you finished a daily or weekly challenge, and your streak"},
+	{"census", mooc.MsgTypeCensus, func(s *privacySettings) *bool { return &s.NoCensus }, func(s *privacySettings) *bool { return &s.IgnoreCensus },
+		"each awake pet's stage and age in days, under a code that changes daily"},
+	{"outbreaks", mooc.MsgTypeOutbreak, func(s *privacySettings) *bool { return &s.NoOutbreaks }, func(s *privacySettings) *bool { return &s.IgnoreOutbreaks },
+		"your pet's name and the strain, while it's sick with something catching"},
+}
+
+// loadPrivacy reads the settings, defaulting to sharing everything
+func loadPrivacy(path string) privacySettings {
+	var settings privacySettings
+	if data, err := os.ReadFile(path); err == nil {
+		json.Unmarshal(data, &settings)
+	}
+	return settings
+}
+
+// save writes the settings
+func (s privacySettings) save(path string) error {
+	data, err := json.MarshalIndent(s, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, data, 0644)
+}
+
+// sharing turns the settings into the mesh's sharing policy
+func (s privacySettings) sharing() mooc.Sharing {
+	return mooc.Sharing{Memories: !s.NoMemories, Dreams: !s.NoDreams, Moods: !s.NoMoods, Deaths: !s.NoDeaths, Banners: !s.NoBanners, Challenges: !s.NoChallenges, Census: !s.NoCensus, Outbreaks: !s.NoOutbreaks}
+}
+
+// hearing turns the settings into the gossip this pet listens to
+func (s privacySettings) hearing() mooc.Sharing {
+	return mooc.Sharing{Memories: !s.IgnoreMemories, Dreams: !s.IgnoreDreams, Moods: !s.IgnoreMoods, Deaths: !s.IgnoreDeaths, Banners: !s.IgnoreBanners, Challenges: !s.IgnoreChallenges, Census: !s.IgnoreCensus, Outbreaks: !s.IgnoreOutbreaks}
+}
+
+// privacyReport is what the privacy screen shows
+type privacyReport struct {
+	settings privacySettings
+	running  bool // The mesh is up this session
+	traffic  mooc.TrafficStats
+	known    int
+	online   int
+	port     int // The port pets share
+}
+
+// currentPrivacyReport gathers the report for the running game
+func currentPrivacyReport(settings privacySettings) privacyReport {
+	report := privacyReport{settings: settings, port: mooc.DiscoveryPort}
+	if port, err := meshPort(os.Getenv); err == nil {
+		report.port = port
+	}
+	if petNetwork != nil && petNetwork.IsEnabled() {
+		report.running = true
+		report.traffic = petNetwork.Traffic()
+		report.known = petNetwork.GetFriendCount()
+		report.online = petNetwork.GetOnlineFriendCount()
+	}
+	return report
+}
+
+// privacyExample is a sample payload, built from the real types so the
+// screen can't drift from what's actually sent
+func privacyExample(pet *Pet, msgType mooc.MessageType) string {
+	var payload interface{}
+	switch msgType {
+	case mooc.MsgTypeAnnounce:
+		identity := mooc.NewPetIdentity(pet.Name, pet.BirthTime, pet.Stage.String(), pet.Stage != Dead)
+		identity.Region = activeRegion.offsetLabel()
+		payload = identity
+	case mooc.MsgTypePulse:
+		payload = mooc.PulsePayload{Seq: 42}
+	case mooc.MsgTypeMemory:
+		payload = mooc.MemoryPayload{Fragment: "The void is full of friends.", Emotion: "serene", Intensity: 64, OriginTime: time.Now().Truncate(time.Second)}
+	case mooc.MsgTypeDream:
+		payload = mooc.DreamPayload{DreamText: "I dreamed of warm static...", Symbols: []string{"warm static", "a garden of pixels"}, SharedWith: "1a2b3c4d"}
+	case mooc.MsgTypeMoodUpdate:
+		payload = mooc.MoodPayload{Mood: "hopeful", Happiness: 70, IsContagious: true, Power: "battery"}
+	case mooc.MsgTypeDeath:
+		payload = mooc.DeathPayload{PetName: pet.Name, DeathTime: time.Now().Truncate(time.Second), Age: pet.Age, LastWords: "I go now to the great terminal in the sky...", Cause: "neglect"}
+	case mooc.MsgTypeBanner:
+		banner := currentBanner(time.Now())
+		payload = mooc.BannerPayload{Week: banner.Week, Banner: banner.Name, Featured: banner.Featured, PetName: pet.Name, Pulled: "Invisible Crown"}
+	case mooc.MsgTypeChallenge:
+		payload = mooc.ChallengePayload{ID: dailyChallenge(time.Now()).ID, PetName: pet.Name, Streak: 4}
+	case mooc.MsgTypeCensus:
+		payload = mooc.CensusPayload{Day: time.Now().UTC().Format("2006-01-02"), Entries: []mooc.CensusEntry{{Hash: 1234567890123, Stage: pet.Stage.String(), AgeDays: pet.Age / 24, Alive: pet.Stage != Dead}}}
+	case mooc.MsgTypeOutbreak:
+		payload = mooc.OutbreakPayload{ID: "9c41e07b", Strain: "Byte Flu", PetName: pet.Name}
+	case mooc.MsgTypeQuest:
+		payload = mooc.QuestPayload{Kind: mooc.QuestProgress, QuestID: "5f2c9e1a7b3d4c60", PetName: pet.Name, Name: "The Great Wait", Target: 60, Progress: 12, StartTime: time.Now().Truncate(time.Second)}
+	}
+	data, _ := json.Marshal(payload)
+	return string(data)
+}
+
+// renderPrivacy draws the privacy screen: whether the mesh is on, exactly
+// what goes out and what it looks like, and what has gone out so far
+func (ui *uiConfig) renderPrivacy(pet *Pet, report privacyReport) string {
+	var b strings.Builder
+	b.WriteString("🔒 PRIVACY\n\n")
+
+	switch {
+	case report.settings.Offline:
+		b.WriteString("Network: OFF. Nothing leaves this machine. (privacy on to rejoin)\n")
+	case report.running:
+		b.WriteString(fmt.Sprintf("Network: on, local network only. %d pets known, %d online. (privacy off to stop)\n", report.known, report.online))
+	default:
+		b.WriteString("Network: not running this session (--lonely, or no network).\n")
+	}
+
+	b.WriteString("\nAlways sent while the network is on:\n")
+	b.WriteString(fmt.Sprintf("  presence  every %s: who your pet is, your UTC offset (never a place), and any gossip it won't hear\n", mooc.BroadcastInterval))
+	b.WriteString(ui.paletteText("            "+privacyExample(pet, mooc.MsgTypeAnnounce), ui.palette.faint) + "\n")
+	b.WriteString(fmt.Sprintf("  pulses    every %s: a number, echoed back, to time the link to pets already met\n", mooc.PingInterval))
+	b.WriteString(ui.paletteText("            "+privacyExample(pet, mooc.MsgTypePulse), ui.palette.faint) + "\n")
+	if pet.Custody != nil {
+		b.WriteString("  custody   after each chore: stats and chore counts, signed, for your co-owner\n")
+	}
+	if report.settings.TCPFallback {
+		b.WriteString(fmt.Sprintf("  knocks    only while UDP finds nobody: a TCP connection to port %d of each address on your subnet, %d a minute at most\n", report.port, mooc.TCPScanPerMinute))
+	}
+	b.WriteString("  whispers  only when you whisper: your words, to that pet, or to a friend to carry if it's away\n")
+	b.WriteString("  quests    only once you share or join one: the quest and your progress, to pets in earshot\n")
+	b.WriteString(ui.paletteText("            "+privacyExample(pet, mooc.MsgTypeQuest), ui.palette.faint) + "\n")
+
+	b.WriteString("\nGossip you can switch off (privacy <kind> off):\n")
+	for _, category := range privacyCategories {
+		mark := "✓"
+		if *category.off(&report.settings) {
+			mark = "✗"
+		}
+		b.WriteString(fmt.Sprintf("  %s %-8s %s\n", mark, category.name, category.what))
+		b.WriteString(ui.paletteText("            "+privacyExample(pet, category.msgType), ui.palette.faint) + "\n")
+	}
+	b.WriteString("\nGossip you hear (privacy hear <kind> off asks other pets not to send it):\n ")
+	for _, category := range privacyCategories {
+		mark := "✓"
+		if *category.ignore(&report.settings) {
+			mark = "✗"
+		}
+		b.WriteString(fmt.Sprintf(" %s %s", mark, category.name))
+	}
+	b.WriteString("\n")
+	b.WriteString(fmt.Sprintf("\nSending is capped at %d messages a minute.\n", mooc.MaxMessagesPerMinute))
+	if !report.settings.TCPFallback {
+		b.WriteString("TCP fallback: off. Turn it on with privacy tcp on if your network blocks UDP.\n")
+	}
+	if report.settings.Sensors {
+		b.WriteString("Machine sensing: on. Your pet notices the load, uptime, and disk, and never shares them. Turn it off with sensors off.\n")
+	}
+
+	if report.running {
+		var kinds []string
+		for kind, count := range report.traffic.Sent {
+			kinds = append(kinds, fmt.Sprintf("%s %d", kind, count))
+		}
+		sort.Strings(kinds)
+		if len(kinds) == 0 {
+			kinds = []string{"nothing yet"}
+		}
+		b.WriteString(fmt.Sprintf("Sent this session: %s (%d packets, %d bytes)", strings.Join(kinds, ", "), report.traffic.Packets, report.traffic.Bytes))
+		if report.traffic.Limited > 0 {
+			b.WriteString(fmt.Sprintf("; %d held back by the cap", report.traffic.Limited))
+		}
+		b.WriteString("\n")
+	}
+	return strings.TrimRight(b.String(), "\n")
+}
+
+// findPrivacyCategory looks up a kind of gossip by name
+func findPrivacyCategory(name string) *privacyCategory {
+	for i := range privacyCategories {
+		if privacyCategories[i].name == name {
+			return &privacyCategories[i]
+		}
+	}
+	return nil
+}
+
+// runPrivacyCommand shows the privacy screen or changes a setting. Changes
+// are saved at once and apply to the running mesh where they can.
+func runPrivacyCommand(ctx *commandContext) string {
+	settings := loadPrivacy(privacyFile)
+	words := strings.Fields(ctx.args)
+	if len(words) == 0 {
+		return ctx.ui.renderPrivacy(ctx.pet, currentPrivacyReport(settings))
+	}
+
+	if len(words) == 1 && words[0] == "audit" {
+		return runNetworkAudit(auditFile, activeAudit != nil, settings)
+	}
+	if len(words) == 1 && words[0] == "doctor" {
+		return runNetworkDoctor(runtime.GOOS)
+	}
+
+	usage := "❓ Usage: privacy [on|off|audit|doctor], privacy tcp <on|off>, or privacy [hear] <memories|dreams|moods|deaths|banners|challenges|census|outbreaks> <on|off>"
+	var message string
+	switch {
+	case len(words) == 1 && words[0] == "off":
+		settings.Offline = true
+		if petNetwork != nil {
+			petNetwork.SetLonelyMode(true)
+		}
+		message = "🔌 Network off. Nothing leaves this machine, now or next time, until you say privacy on."
+	case len(words) == 1 && words[0] == "on":
+		settings.Offline = false
+		message = "🔌 Network back on from the next time you start the game."
+	case len(words) == 2 && words[0] == "tcp" && (words[1] == "on" || words[1] == "off"):
+		settings.TCPFallback = words[1] == "on"
+		message = fmt.Sprintf("🔌 TCP fallback %s from the next time you start the game.", words[1])
+	case len(words) == 2 && (words[1] == "on" || words[1] == "off"):
+		category := findPrivacyCategory(words[0])
+		if category == nil {
+			return usage
+		}
+		*category.off(&settings) = words[1] == "off"
+		if petNetwork != nil {
+			petNetwork.SetSharing(settings.sharing())
+		}
+		message = fmt.Sprintf("🔒 Sharing %s: %s.", category.name, words[1])
+	case len(words) == 3 && words[0] == "hear" && (words[2] == "on" || words[2] == "off"):
+		category := findPrivacyCategory(words[1])
+		if category == nil {
+			return usage
+		}
+		*category.ignore(&settings) = words[2] == "off"
+		if petNetwork != nil {
+			petNetwork.SetHearing(settings.hearing())
+		}
+		message = fmt.Sprintf("👂 Hearing %s: %s. Other pets are told when you next start the game; until then, it's dropped when it arrives.", category.name, words[2])
+	default:
+		return usage
+	}
+
+	if err := settings.save(privacyFile); err != nil {
+		return fmt.Sprintf("❌ Couldn't save privacy settings: %v", err)
+	}
+	activeAudit.notePrivacy(ctx.args, time.Now())
+	return message
+}
@@ -0,0 +1,89 @@
+package main
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/tamagotchi/mooc"
+)
+
+func TestPrivacySettingsRoundTrip(t *testing.T) {
+	path := filepath.Join(t.TempDir(), privacyFile)
+	if settings := loadPrivacy(path); settings.Offline || settings.sharing() != mooc.ShareEverything() {
+		t.Errorf("Expected a missing file to share everything, got %+v", settings)
+	}
+
+	settings := privacySettings{Offline: true, NoMoods: true}
+	if err := settings.save(path); err != nil {
+		t.Fatalf("Expected save to succeed, got %v", err)
+	}
+	loaded := loadPrivacy(path)
+	if loaded != settings {
+		t.Errorf("Expected %+v back, got %+v", settings, loaded)
+	}
+	if sharing := loaded.sharing(); sharing.Moods || !sharing.Memories {
+		t.Errorf("Expected only moods switched off, got %+v", sharing)
+	}
+}
+
+func TestRenderPrivacy(t *testing.T) {
+	ui := newUIConfig()
+	ui.colorEnabled = false
+	pet := NewPet("Secretive")
+
+	tests := []struct {
+		name   string
+		report privacyReport
+		want   []string
+	}{
+		{"offline", privacyReport{settings: privacySettings{Offline: true}}, []string{"Network: OFF", `"display_name":"Secretive"`, `{"seq":42}`}},
+		{"running", privacyReport{running: true, known: 3, online: 1, traffic: mooc.TrafficStats{Sent: map[string]int{"ANNOUNCE": 4}, Packets: 4, Bytes: 900, Limited: 2}},
+			[]string{"3 pets known, 1 online", "ANNOUNCE 4", "2 held back"}},
+		{"moods off", privacyReport{settings: privacySettings{NoMoods: true}}, []string{"✗ moods", "✓ memories", `"fragment"`}},
+		{"deaths unheard", privacyReport{settings: privacySettings{IgnoreDeaths: true}}, []string{"✓ deaths   your pet's name", "✓ moods ✗ deaths ✓ banners", "TCP fallback: off"}},
+		{"tcp fallback", privacyReport{settings: privacySettings{TCPFallback: true}, port: 20001}, []string{"knocks    only while UDP finds nobody", "port 20001", "30 a minute"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			out := ui.renderPrivacy(pet, tt.report)
+			for _, want := range tt.want {
+				if !strings.Contains(out, want) {
+					t.Errorf("Expected %q in privacy screen, got %q", want, out)
+				}
+			}
+		})
+	}
+}
+
+func TestRunPrivacyCommand(t *testing.T) {
+	t.Chdir(t.TempDir())
+	ctx := &commandContext{pet: NewPet("Secretive"), ui: newUIConfig()}
+
+	tests := []struct {
+		args string
+		want string
+	}{
+		{"", "PRIVACY"},
+		{"moods off", "Sharing moods: off"},
+		{"hear deaths off", "Hearing deaths: off"},
+		{"tcp on", "TCP fallback on"},
+		{"hear tcp off", "Usage"},
+		{"hear whispers off", "Usage"},
+		{"off", "Network off"},
+		{"whispers off", "Usage"},
+		{"sideways", "Usage"},
+	}
+	for _, tt := range tests {
+		ctx.args = tt.args
+		if got := runPrivacyCommand(ctx); !strings.Contains(got, tt.want) {
+			t.Errorf("privacy %q: Expected %q, got %q", tt.args, tt.want, got)
+		}
+	}
+
+	settings := loadPrivacy(privacyFile)
+	if !settings.Offline || !settings.NoMoods || settings.NoMemories || !settings.IgnoreDeaths || settings.NoDeaths || !settings.TCPFallback {
+		t.Errorf("Expected offline with moods off, deaths unheard, and the TCP fallback to persist, got %+v", settings)
+	}
+}
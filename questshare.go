@@ -0,0 +1,393 @@
+package main
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/tamagotchi/mooc"
+)
+
+const (
+	// maxQuestPartners is how many other pets can join one quest
+	maxQuestPartners = 3
+
+	// questJointBonus is the extra TamaCoins for finishing with a partner
+	questJointBonus = 2
+
+	// questOfferLifetime is how long an offer lasts without being repeated
+	questOfferLifetime = 5 * time.Minute
+
+	// maxQuestMemories is how many shared quests the pet remembers
+	maxQuestMemories = 20
+)
+
+// questOffer is a quest a nearby pet is hosting
+type questOffer struct {
+	hostID   string
+	hostName string
+	payload  mooc.QuestPayload
+	seenAt   time.Time
+}
+
+// questStep is a quest message waiting for the game loop
+type questStep struct {
+	from    mooc.PetIdentity
+	payload mooc.QuestPayload
+}
+
+// questBoard holds quest traffic from the mesh until the game loop applies
+// it, the offers heard recently, and the quest we've asked to join
+var questBoard struct {
+	sync.Mutex
+	steps   []questStep
+	offers  map[string]questOffer // By quest ID
+	pending string                // Quest ID we've asked to join
+}
+
+// queueQuest is the mesh's quest handler
+func queueQuest(from mooc.PetIdentity, payload mooc.QuestPayload) {
+	questBoard.Lock()
+	defer questBoard.Unlock()
+	questBoard.steps = append(questBoard.steps, questStep{from: from, payload: payload})
+}
+
+// petID is how the mesh knows this pet
+func (p *Pet) petID() string {
+	return mooc.GeneratePetID(p.Name, p.BirthTime)
+}
+
+// sendQuest puts a quest step on the mesh
+func sendQuest(payload mooc.QuestPayload) {
+	if petNetwork != nil {
+		petNetwork.SendQuest(payload)
+	}
+}
+
+// questPayload describes a quest for a step of the handshake
+func (p *Pet) questPayload(kind string, q *Quest) mooc.QuestPayload {
+	return mooc.QuestPayload{
+		Kind: kind, QuestID: q.ID, PetName: p.Name,
+		Name: q.Name, Description: q.Description, Target: q.Target,
+		StartTime: q.StartTime, Progress: q.Progress,
+	}
+}
+
+// partner returns the partner with a PetID, adding them if there's room
+func (q *Quest) partner(petID, name string) *QuestPartner {
+	for i := range q.Partners {
+		if q.Partners[i].PetID == petID {
+			return &q.Partners[i]
+		}
+	}
+	if len(q.Partners) >= maxQuestPartners {
+		return nil
+	}
+	q.Partners = append(q.Partners, QuestPartner{PetID: petID, Name: name})
+	return &q.Partners[len(q.Partners)-1]
+}
+
+// catchUp reconciles progress with a partner. Quests progress with time, so
+// whoever started first sets the pace and the other moves its start back.
+func (q *Quest) catchUp(progress int, now time.Time) {
+	if progress > int(now.Sub(q.StartTime).Seconds()) {
+		q.StartTime = now.Add(-time.Duration(progress) * time.Second)
+	}
+	if progress > q.Progress {
+		q.Progress = progress
+	}
+}
+
+// ShareQuest opens the active quest to pets nearby
+func (p *Pet) ShareQuest() string {
+	e := p.Endgame
+	if e == nil || e.ActiveQuest == nil {
+		return "📜 Start a quest first, then share it."
+	}
+	if petNetwork == nil || !petNetwork.IsEnabled() {
+		return "📡 The network is off, so nobody would hear. (privacy on)"
+	}
+	q := e.ActiveQuest
+	if q.HostID != "" {
+		return "📜 This is someone else's quest; only the host can share it."
+	}
+	if q.ID == "" {
+		id := make([]byte, 8)
+		rand.Read(id)
+		q.ID = hex.EncodeToString(id)
+	}
+	sendQuest(p.questPayload(mooc.QuestOffer, q))
+	return fmt.Sprintf("📣 %s is calling out to pets nearby: \"%s\". Up to %d can join.", p.Name, q.Name, maxQuestPartners)
+}
+
+// offerQuest repeats a shared quest's offer while there's room
+func offerQuest(pet *Pet) petJob {
+	if pet.Endgame == nil {
+		return nil
+	}
+	q := pet.Endgame.ActiveQuest
+	if q == nil || q.ID == "" || q.HostID != "" || len(q.Partners) >= maxQuestPartners {
+		return nil
+	}
+	payload := pet.questPayload(mooc.QuestOffer, q)
+	return func() func(*Pet) {
+		sendQuest(payload)
+		return nil
+	}
+}
+
+// currentOffers lists the offers heard recently, oldest first
+func currentOffers(now time.Time) []questOffer {
+	questBoard.Lock()
+	defer questBoard.Unlock()
+	var offers []questOffer
+	for id, offer := range questBoard.offers {
+		if now.Sub(offer.seenAt) > questOfferLifetime {
+			delete(questBoard.offers, id)
+			continue
+		}
+		offers = append(offers, offer)
+	}
+	sort.Slice(offers, func(i, j int) bool { return offers[i].payload.StartTime.Before(offers[j].payload.StartTime) })
+	return offers
+}
+
+// JoinQuest asks a nearby host to let this pet join their quest
+func (p *Pet) JoinQuest(host string, now time.Time) string {
+	if p.Endgame == nil {
+		return ""
+	}
+	if p.Endgame.ActiveQuest != nil {
+		return "📜 Finish your own quest first."
+	}
+	offers := currentOffers(now)
+	if len(offers) == 0 {
+		return "📜 Nobody nearby is sharing a quest right now."
+	}
+	if host == "" {
+		var b strings.Builder
+		b.WriteString("📜 Quests nearby:\n")
+		for _, offer := range offers {
+			b.WriteString(fmt.Sprintf("  %s: \"%s\" (%s)\n", offer.hostName, offer.payload.Name, offer.payload.Description))
+		}
+		b.WriteString("Join one with: quest join <name>")
+		return b.String()
+	}
+	for _, offer := range offers {
+		if strings.EqualFold(offer.hostName, host) {
+			questBoard.Lock()
+			questBoard.pending = offer.payload.QuestID
+			questBoard.Unlock()
+			sendQuest(mooc.QuestPayload{Kind: mooc.QuestJoin, QuestID: offer.payload.QuestID, To: offer.hostID, PetName: p.Name})
+			return fmt.Sprintf("🙋 Asked %s to let %s join \"%s\".", offer.hostName, p.Name, offer.payload.Name)
+		}
+	}
+	return fmt.Sprintf("❓ Nobody called %s is sharing a quest.", host)
+}
+
+// applyQuestUpdates works through the quest steps heard since last time
+func applyQuestUpdates(pet *Pet, now time.Time) []string {
+	questBoard.Lock()
+	steps := questBoard.steps
+	questBoard.steps = nil
+	questBoard.Unlock()
+
+	if pet.Endgame == nil {
+		return nil
+	}
+	self := pet.petID()
+	var reactions []string
+	for _, step := range steps {
+		if step.from.PetID == self || (step.payload.To != "" && step.payload.To != self) {
+			continue
+		}
+		if reaction := pet.applyQuestStep(step.from.PetID, step.payload, now); reaction != "" {
+			reactions = append(reactions, reaction)
+		}
+	}
+	return reactions
+}
+
+// applyQuestStep handles one step of the handshake or of a quest under way
+func (p *Pet) applyQuestStep(from string, payload mooc.QuestPayload, now time.Time) string {
+	e := p.Endgame
+	active := e.ActiveQuest
+	ours := active != nil && active.ID != "" && active.ID == payload.QuestID
+
+	switch payload.Kind {
+	case mooc.QuestOffer:
+		questBoard.Lock()
+		if questBoard.offers == nil {
+			questBoard.offers = make(map[string]questOffer)
+		}
+		_, heard := questBoard.offers[payload.QuestID]
+		questBoard.offers[payload.QuestID] = questOffer{hostID: from, hostName: payload.PetName, payload: payload, seenAt: now}
+		questBoard.Unlock()
+		if heard || ours {
+			return ""
+		}
+		return fmt.Sprintf("📣 %s is looking for company on \"%s\". (quest join %s)", payload.PetName, payload.Name, strings.ToLower(payload.PetName))
+
+	case mooc.QuestJoin:
+		if !ours || active.HostID != "" {
+			return ""
+		}
+		partner := active.partner(from, payload.PetName)
+		if partner == nil {
+			return "" // Full; they'll hear no answer
+		}
+		active.Progress = int(now.Sub(active.StartTime).Seconds())
+		accept := p.questPayload(mooc.QuestAccept, active)
+		accept.To = from
+		sendQuest(accept)
+		return fmt.Sprintf("🤝 %s joined your quest \"%s\".", payload.PetName, active.Name)
+
+	case mooc.QuestAccept:
+		questBoard.Lock()
+		pending := questBoard.pending == payload.QuestID
+		if pending {
+			questBoard.pending = ""
+		}
+		questBoard.Unlock()
+		if !pending || active != nil {
+			return ""
+		}
+		e.ActiveQuest = &Quest{
+			Name: payload.Name, Description: payload.Description, Type: "wait",
+			Target: payload.Target, StartTime: payload.StartTime, Progress: payload.Progress,
+			Reward: "1 TamaCoin (non-spendable), more if you finish together",
+			ID:     payload.QuestID, HostID: from,
+			Partners: []QuestPartner{{PetID: from, Name: payload.PetName, Progress: payload.Progress}},
+		}
+		e.ActiveQuest.catchUp(payload.Progress, now)
+		return fmt.Sprintf("🤝 %s let you join \"%s\". Progress so far: %d/%d.", payload.PetName, payload.Name, e.ActiveQuest.Progress, payload.Target)
+
+	case mooc.QuestProgress:
+		if !ours {
+			return ""
+		}
+		if partner := active.partner(from, payload.PetName); partner != nil && payload.Progress > partner.Progress {
+			partner.Progress = payload.Progress
+		}
+		active.catchUp(payload.Progress, now)
+
+	case mooc.QuestComplete:
+		if ours {
+			if partner := active.partner(from, payload.PetName); partner != nil {
+				partner.Done = true
+				partner.Progress = active.Target
+			}
+			return fmt.Sprintf("🏁 %s has finished \"%s\". Catch up!", payload.PetName, active.Name)
+		}
+		finished := e.FinishedQuest
+		if finished == nil || finished.ID != payload.QuestID {
+			return ""
+		}
+		partner := finished.partner(from, payload.PetName)
+		if partner == nil || partner.Done {
+			return ""
+		}
+		partner.Done = true
+		return e.jointBonus(finished, partner)
+	}
+	return ""
+}
+
+// jointBonus rewards finishing a quest together and remembers who with
+func (e *EndgameState) jointBonus(q *Quest, partner *QuestPartner) string {
+	e.TamaCoins += questJointBonus
+	e.QuestMemories = append(e.QuestMemories, fmt.Sprintf("I remember \"%s\". %s and I waited it out together.", q.Name, partner.Name))
+	if len(e.QuestMemories) > maxQuestMemories {
+		e.QuestMemories = e.QuestMemories[1:]
+	}
+	return fmt.Sprintf("🤝 You and %s both finished \"%s\"! Joint bonus: +%d TamaCoins.", partner.Name, q.Name, questJointBonus)
+}
+
+// finishSharedQuest tells partners a shared quest is done, and pays the
+// joint bonus for everyone who got there first
+func (p *Pet) finishSharedQuest(q *Quest) string {
+	if q == nil || q.ID == "" {
+		return ""
+	}
+	q.Progress = q.Target
+	sendQuest(p.questPayload(mooc.QuestComplete, q))
+	if len(q.Partners) == 0 {
+		return ""
+	}
+	p.Endgame.FinishedQuest = q
+
+	var bonuses []string
+	for i := range q.Partners {
+		if q.Partners[i].Done {
+			bonuses = append(bonuses, p.Endgame.jointBonus(q, &q.Partners[i]))
+		}
+	}
+	if len(bonuses) == 0 {
+		return fmt.Sprintf("⏳ Waiting for %s to finish too, for the joint bonus.", questPartyNames(q))
+	}
+	return strings.Join(bonuses, "\n")
+}
+
+// reportQuestProgress keeps partners in step
+func (p *Pet) reportQuestProgress() {
+	if p.Endgame == nil || p.Endgame.ActiveQuest == nil || p.Endgame.ActiveQuest.ID == "" {
+		return
+	}
+	sendQuest(p.questPayload(mooc.QuestProgress, p.Endgame.ActiveQuest))
+}
+
+// questPartyNames lists who's on a quest with us
+func questPartyNames(q *Quest) string {
+	names := make([]string, 0, len(q.Partners))
+	for _, partner := range q.Partners {
+		names = append(names, partner.Name)
+	}
+	return strings.Join(names, " and ")
+}
+
+// questParty describes the partners' progress for the quest screen
+func questParty(q *Quest) string {
+	if q == nil || len(q.Partners) == 0 {
+		return ""
+	}
+	parts := make([]string, 0, len(q.Partners))
+	for _, partner := range q.Partners {
+		if partner.Done {
+			parts = append(parts, partner.Name+" (done)")
+		} else {
+			parts = append(parts, fmt.Sprintf("%s (%d/%d)", partner.Name, partner.Progress, q.Target))
+		}
+	}
+	return "🤝 With " + strings.Join(parts, ", ")
+}
+
+// runQuestCommand checks on, completes, shares, or joins a quest
+func runQuestCommand(pet *Pet, args string) string {
+	e := pet.Endgame
+	if e == nil {
+		return ""
+	}
+	verb, rest, _ := strings.Cut(args, " ")
+	switch verb {
+	case "share":
+		return pet.ShareQuest()
+	case "join":
+		return pet.JoinQuest(strings.TrimSpace(rest), time.Now())
+	case "":
+	default:
+		return "❓ Usage: quest [share|join [name]]"
+	}
+
+	// Check for quest completion first
+	quest := e.ActiveQuest
+	if completion := e.UpdateQuest(); completion != "" {
+		e.UnlockAchievement("quest_complete")
+		return strings.TrimSpace(completion + "\n" + pet.finishSharedQuest(quest))
+	}
+	pet.reportQuestProgress()
+	return strings.TrimSpace(e.GenerateQuest() + "\n" + questParty(e.ActiveQuest))
+}
@@ -0,0 +1,179 @@
+package main
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/tamagotchi/mooc"
+)
+
+// resetQuestBoard clears quest traffic between tests
+func resetQuestBoard(t *testing.T) {
+	t.Helper()
+	empty := func() {
+		questBoard.Lock()
+		questBoard.steps, questBoard.offers, questBoard.pending = nil, nil, ""
+		questBoard.Unlock()
+	}
+	empty()
+	t.Cleanup(empty)
+}
+
+// hostWithQuest is a pet sharing a 60 second quest it started 10 seconds ago
+func hostWithQuest(now time.Time) *Pet {
+	host := NewPet("Mochi")
+	host.Endgame.ActiveQuest = &Quest{
+		ID: "q1", Name: "The Great Wait", Description: "Wait 60 seconds", Type: "wait",
+		Target: 60, StartTime: now.Add(-10 * time.Second),
+	}
+	return host
+}
+
+func TestQuestHandshake(t *testing.T) {
+	resetQuestBoard(t)
+	now := time.Now()
+	host := hostWithQuest(now)
+	guest := NewPet("Biscuit")
+	hostID := mooc.PetIdentity{PetID: host.petID()}
+	guestID := mooc.PetIdentity{PetID: guest.petID()}
+
+	if got := guest.JoinQuest("", now); !strings.Contains(got, "Nobody nearby") {
+		t.Errorf("Expected no offers yet, got %s", got)
+	}
+
+	queueQuest(hostID, host.questPayload(mooc.QuestOffer, host.Endgame.ActiveQuest))
+	if got := applyQuestUpdates(guest, now); len(got) != 1 || !strings.Contains(got[0], "quest join mochi") {
+		t.Errorf("Expected the offer announced, got %v", got)
+	}
+	queueQuest(hostID, host.questPayload(mooc.QuestOffer, host.Endgame.ActiveQuest))
+	if got := applyQuestUpdates(guest, now); len(got) != 0 {
+		t.Errorf("Expected a repeated offer to be quiet, got %v", got)
+	}
+	if got := guest.JoinQuest("", now); !strings.Contains(got, "Mochi: \"The Great Wait\"") {
+		t.Errorf("Expected the offer listed, got %s", got)
+	}
+	if got := guest.JoinQuest("pudding", now); !strings.Contains(got, "Nobody called pudding") {
+		t.Errorf("Expected an unknown host refused, got %s", got)
+	}
+	if got := guest.JoinQuest("mochi", now); !strings.Contains(got, "Asked Mochi") {
+		t.Fatalf("Expected a join request, got %s", got)
+	}
+
+	// The host lets the guest in
+	queueQuest(guestID, mooc.QuestPayload{Kind: mooc.QuestJoin, QuestID: "q1", To: host.petID(), PetName: guest.Name})
+	if got := applyQuestUpdates(host, now); len(got) != 1 || !strings.Contains(got[0], "Biscuit joined") {
+		t.Errorf("Expected the guest welcomed, got %v", got)
+	}
+	if partners := host.Endgame.ActiveQuest.Partners; len(partners) != 1 || partners[0].PetID != guest.petID() {
+		t.Errorf("Expected the guest as a partner, got %+v", partners)
+	}
+
+	// Steps for another pet are ignored
+	accept := host.questPayload(mooc.QuestAccept, host.Endgame.ActiveQuest)
+	accept.To = "someone-else"
+	queueQuest(hostID, accept)
+	applyQuestUpdates(guest, now)
+	if guest.Endgame.ActiveQuest != nil {
+		t.Fatalf("Expected an accept for another pet to be ignored")
+	}
+
+	accept.To = guest.petID()
+	queueQuest(hostID, accept)
+	if got := applyQuestUpdates(guest, now); len(got) != 1 || !strings.Contains(got[0], "let you join") {
+		t.Errorf("Expected the guest let in, got %v", got)
+	}
+	q := guest.Endgame.ActiveQuest
+	if q == nil || q.HostID != host.petID() || q.Progress != 10 || len(q.Partners) != 1 {
+		t.Fatalf("Expected the host's quest, 10 seconds in, got %+v", q)
+	}
+	if got := guest.ShareQuest(); !strings.Contains(got, "network is off") && !strings.Contains(got, "only the host") {
+		t.Errorf("Expected a guest not to re-share, got %s", got)
+	}
+}
+
+func TestQuestPartnerLimit(t *testing.T) {
+	q := &Quest{}
+	for i := 0; i < maxQuestPartners; i++ {
+		if q.partner(string(rune('a'+i)), "pet") == nil {
+			t.Fatalf("Expected room for partner %d", i+1)
+		}
+	}
+	if q.partner("a", "pet") == nil {
+		t.Errorf("Expected an existing partner to be found when full")
+	}
+	if q.partner("z", "pet") != nil || len(q.Partners) != maxQuestPartners {
+		t.Errorf("Expected no more than %d partners, got %d", maxQuestPartners, len(q.Partners))
+	}
+}
+
+func TestQuestCatchUp(t *testing.T) {
+	now := time.Now()
+	tests := []struct {
+		name         string
+		elapsed      int
+		partner      int
+		wantProgress int
+		wantElapsed  int
+	}{
+		{"partner ahead", 10, 25, 25, 25},
+		{"partner behind", 30, 5, 30, 30},
+		{"level", 15, 15, 15, 15},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			q := &Quest{StartTime: now.Add(-time.Duration(tt.elapsed) * time.Second), Progress: tt.elapsed}
+			q.catchUp(tt.partner, now)
+			if q.Progress != tt.wantProgress {
+				t.Errorf("Expected progress %d, got %d", tt.wantProgress, q.Progress)
+			}
+			if elapsed := int(now.Sub(q.StartTime).Seconds()); elapsed != tt.wantElapsed {
+				t.Errorf("Expected the quest %d seconds in, got %d", tt.wantElapsed, elapsed)
+			}
+		})
+	}
+}
+
+func TestJointQuestCompletion(t *testing.T) {
+	resetQuestBoard(t)
+	now := time.Now()
+	host := hostWithQuest(now)
+	host.Endgame.ActiveQuest.StartTime = now.Add(-2 * time.Minute)
+	host.Endgame.ActiveQuest.Partners = []QuestPartner{{PetID: "guest", Name: "Biscuit", Progress: 50}}
+
+	// The host gets there first and waits for the bonus
+	got := runQuestCommand(host, "")
+	if !strings.Contains(got, "QUEST COMPLETE") || !strings.Contains(got, "Waiting for Biscuit") {
+		t.Errorf("Expected completion and a wait for the partner, got %s", got)
+	}
+	if host.Endgame.TamaCoins != 1 || host.Endgame.FinishedQuest == nil {
+		t.Fatalf("Expected only the usual coin so far, got %d", host.Endgame.TamaCoins)
+	}
+
+	complete := mooc.QuestPayload{Kind: mooc.QuestComplete, QuestID: "q1", PetName: "Biscuit"}
+	queueQuest(mooc.PetIdentity{PetID: "guest"}, complete)
+	queueQuest(mooc.PetIdentity{PetID: "guest"}, complete) // Heard twice, paid once
+	reactions := applyQuestUpdates(host, now)
+	if len(reactions) != 1 || !strings.Contains(reactions[0], "You and Biscuit both finished") {
+		t.Errorf("Expected the joint bonus, got %v", reactions)
+	}
+	if host.Endgame.TamaCoins != 1+questJointBonus {
+		t.Errorf("Expected %d TamaCoins, got %d", 1+questJointBonus, host.Endgame.TamaCoins)
+	}
+	if len(host.Endgame.QuestMemories) != 1 || !strings.Contains(host.Endgame.QuestMemories[0], "Biscuit") {
+		t.Errorf("Expected a shared memory of Biscuit, got %v", host.Endgame.QuestMemories)
+	}
+
+	// A partner who finished first pays out straight away
+	guest := hostWithQuest(now)
+	guest.Name = "Biscuit"
+	guest.Endgame.ActiveQuest.StartTime = now.Add(-2 * time.Minute)
+	guest.Endgame.ActiveQuest.Partners = []QuestPartner{{PetID: "host", Name: "Mochi", Done: true}}
+	if got := runQuestCommand(guest, ""); !strings.Contains(got, "You and Mochi both finished") {
+		t.Errorf("Expected the joint bonus on completion, got %s", got)
+	}
+
+	if got := runQuestCommand(guest, "dance"); !strings.Contains(got, "Usage") {
+		t.Errorf("Expected usage for an unknown quest command, got %s", got)
+	}
+}
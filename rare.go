@@ -0,0 +1,78 @@
+package main
+
+import (
+	"fmt"
+	"time"
+)
+
+// rareEvent is a moment that happens at most once in a pet's life. Each
+// render rolls for the ones whose prerequisites are met.
+type rareEvent struct {
+	ID         string
+	Label      string // Shown in place of the expression label
+	Expression string
+	Caption    string // Album caption; %s is the pet's name
+	Chance     int    // One in this many renders
+	Art        string // Special art shown instead of the pet; "" or missing keeps the pet
+	requires   func(p *Pet, now time.Time) bool
+}
+
+// rareEvents are rolled in this order; at most one happens per render
+var rareEvents = []rareEvent{
+	{ID: "the_look", Label: "The Look", Expression: "The pet stares straight through the screen.",
+		Caption: "%s gives you The Look", Chance: 1000, Art: "the_look"},
+	{ID: "second_look", Label: "The Second Look", Expression: "It does it again. It knows you remember the first time.",
+		Caption: "%s gives you The Look. Again.", Chance: 2000, Art: "the_look",
+		requires: func(p *Pet, _ time.Time) bool { return p.sawRare("the_look") }},
+	{ID: "the_visitor", Label: "The Visitor", Expression: "Something else is in the room. It waves. It leaves.",
+		Caption: "%s and a visitor nobody invited", Chance: 3000, Art: "the_visitor",
+		requires: func(p *Pet, now time.Time) bool { return p.Stage >= Child && isNightHour(now.Hour()) }},
+	{ID: "wrong_reflection", Label: "The Wrong Reflection", Expression: "Its reflection blinked first.",
+		Caption: "%s and its reflection, which disagrees", Chance: 2500, Art: "wrong_reflection",
+		requires: func(p *Pet, _ time.Time) bool { return p.Stage >= Teen }},
+}
+
+// sawRare reports whether a rare event has already happened to the pet
+func (p *Pet) sawRare(id string) bool {
+	_, ok := p.RareEvents[id]
+	return ok
+}
+
+// rollRareEvent picks a rare event that happens now, recording it so it
+// never happens again, or nil. roll(n) returns a number in [0, n).
+func (p *Pet) rollRareEvent(now time.Time, roll func(int) int) *rareEvent {
+	if p.Stage == Egg || p.Stage == Dead {
+		return nil
+	}
+	for i := range rareEvents {
+		e := &rareEvents[i]
+		if p.sawRare(e.ID) || (e.requires != nil && !e.requires(p, now)) {
+			continue
+		}
+		if roll(e.Chance) == 0 {
+			if p.RareEvents == nil {
+				p.RareEvents = make(map[string]time.Time)
+			}
+			p.RareEvents[e.ID] = now
+			return e
+		}
+	}
+	return nil
+}
+
+// rareMoments are album moments for every rare event, so each is
+// photographed when it happens
+func rareMoments() []albumMoment {
+	moments := make([]albumMoment, len(rareEvents))
+	for i, e := range rareEvents {
+		moments[i] = albumMoment{ID: e.ID, happens: func(p *Pet) (string, bool) {
+			return fmt.Sprintf(e.Caption, p.Name), p.sawRare(e.ID)
+		}, frame: func(a *artLibrary, p *Pet) string {
+			if art := a.Special(e.Art); len(art) > 0 {
+				return art[0]
+			}
+			return ""
+		}}
+	}
+	return moments
+}
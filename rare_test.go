@@ -0,0 +1,79 @@
+package main
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+)
+
+func TestRollRareEvent(t *testing.T) {
+	always := func(int) int { return 0 }
+	never := func(n int) int { return n - 1 }
+
+	tests := []struct {
+		name  string
+		stage LifeStage
+		hour  int
+		seen  []string
+		roll  func(int) int
+		want  string
+	}{
+		{"an egg", Egg, 12, nil, always, ""},
+		{"bad luck", Adult, 12, nil, never, ""},
+		{"the first one", Baby, 12, nil, always, "the_look"},
+		{"the second look needs the first", Baby, 12, []string{"the_look"}, always, "second_look"},
+		{"a baby can't see its reflection", Baby, 12, []string{"the_look", "second_look"}, always, ""},
+		{"a teen can", Teen, 12, []string{"the_look", "second_look"}, always, "wrong_reflection"},
+		{"the visitor comes at night", Teen, 23, []string{"the_look", "second_look"}, always, "the_visitor"},
+		{"everything has happened", Adult, 12, []string{"the_look", "second_look", "the_visitor", "wrong_reflection"}, always, ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			now := time.Date(2026, 10, 16, tt.hour, 0, 0, 0, time.Local)
+			pet := NewPet("Rare")
+			pet.Stage = tt.stage
+			for _, id := range tt.seen {
+				if pet.RareEvents == nil {
+					pet.RareEvents = map[string]time.Time{}
+				}
+				pet.RareEvents[id] = now
+			}
+
+			got := ""
+			if e := pet.rollRareEvent(now, tt.roll); e != nil {
+				got = e.ID
+			}
+			if got != tt.want {
+				t.Errorf("Expected %q, got %q", tt.want, got)
+			}
+			if got != "" && !pet.sawRare(got) {
+				t.Errorf("Expected %s to be remembered", got)
+			}
+		})
+	}
+}
+
+func TestRareEventsHaveArt(t *testing.T) {
+	for _, e := range rareEvents {
+		if e.Art != "" && len(activeArt.Special(e.Art)) == 0 {
+			t.Errorf("Expected art for %s in the manifest", e.ID)
+		}
+	}
+}
+
+func TestLegacyTheLookMigrates(t *testing.T) {
+	pet := NewPet("Starer")
+	pet.HasShownTheLook = true
+	data, err := json.Marshal(pet)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	loaded, err := decodePet(data, "save.json")
+	if err != nil {
+		t.Fatalf("Expected the save to load, got %v", err)
+	}
+	if !loaded.sawRare("the_look") || loaded.HasShownTheLook {
+		t.Errorf("Expected The Look moved into rare events, got %v", loaded.RareEvents)
+	}
+}
@@ -0,0 +1,121 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/tamagotchi/mooc"
+)
+
+// SessionNote is a session summary kept for the next weekly report
+type SessionNote struct {
+	At      time.Time `json:"at"`
+	Summary string    `json:"summary"`
+}
+
+// sessionRecap remembers how the pet was when the session began, so
+// quitting can say what happened since
+type sessionRecap struct {
+	start   MetaSnapshot
+	actions int
+}
+
+// newSessionRecap starts a recap from how the pet is now
+func newSessionRecap(p *Pet, now time.Time) *sessionRecap {
+	return &sessionRecap{start: p.metaSnapshot(now)}
+}
+
+// countAction records a command run this session
+func (r *sessionRecap) countAction() {
+	r.actions++
+}
+
+// lines says what happened this session, one fact a line. seen is the
+// friends seen on the mesh since it began.
+func (r *sessionRecap) lines(p *Pet, now time.Time, seen []string) []string {
+	current := p.metaSnapshot(now)
+	lines := []string{
+		"Session: " + formatDuration(now.Sub(r.start.At).Truncate(time.Second)),
+		fmt.Sprintf("Actions taken: %d", r.actions),
+	}
+	stats := []struct {
+		name    string
+		was, is int
+	}{
+		{"Hunger", r.start.Hunger, current.Hunger},
+		{"Happiness", r.start.Happiness, current.Happiness},
+		{"Health", r.start.Health, current.Health},
+		{"Cleanliness", r.start.Cleanliness, current.Cleanliness},
+	}
+	for _, stat := range stats {
+		if stat.is != stat.was {
+			lines = append(lines, fmt.Sprintf("%s: %d → %d (%+d)", stat.name, stat.was, stat.is, stat.is-stat.was))
+		}
+	}
+	if thoughts := current.Thoughts - r.start.Thoughts; thoughts > 0 {
+		lines = append(lines, fmt.Sprintf("Thoughts had: %d", thoughts))
+	}
+	if len(seen) > 0 {
+		lines = append(lines, "Friends seen: "+listNames(seen))
+	}
+	// A reset mid-session starts the achievements over
+	if p.Endgame != nil && r.start.Achievements < len(p.Endgame.UnlockedAchievements) {
+		var names []string
+		for _, id := range p.Endgame.UnlockedAchievements[r.start.Achievements:] {
+			names = append(names, achievementName(id))
+		}
+		lines = append(lines, "Achievements: "+listNames(names))
+	}
+	return lines
+}
+
+// render draws the summary shown on quit
+func (r *sessionRecap) render(p *Pet, now time.Time, seen []string) string {
+	var b strings.Builder
+	b.WriteString(`
+╔════════════════════════════════════╗
+║      📋 SESSION SUMMARY 📋
+╠════════════════════════════════════╣
+`)
+	for _, line := range r.lines(p, now, seen) {
+		fmt.Fprintf(&b, "║ • %s\n", line)
+	}
+	b.WriteString("╚════════════════════════════════════╝\n")
+	return b.String()
+}
+
+// note keeps the summary for the weekly report
+func (r *sessionRecap) note(p *Pet, now time.Time, seen []string) SessionNote {
+	return SessionNote{At: now, Summary: strings.Join(r.lines(p, now, seen), "; ")}
+}
+
+// friendsSeenSince names the pets heard from on the mesh since a time
+func friendsSeenSince(since time.Time) []string {
+	if petNetwork == nil {
+		return nil
+	}
+	return seenSince(petNetwork.Stars(), since)
+}
+
+// seenSince names the stars seen at or after a time
+func seenSince(stars []mooc.Star, since time.Time) []string {
+	var names []string
+	for _, star := range stars {
+		if !star.LastSeen.Before(since) && star.DisplayName != "" {
+			names = append(names, star.DisplayName)
+		}
+	}
+	return names
+}
+
+// sessionNotesBetween picks the session notes taken in a report's week
+func sessionNotesBetween(notes []SessionNote, start, end time.Time) []SessionNote {
+	var picked []SessionNote
+	for _, note := range notes {
+		if !note.At.Before(start) && note.At.Before(end) {
+			picked = append(picked, note)
+		}
+	}
+	return picked
+}
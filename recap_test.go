@@ -0,0 +1,135 @@
+package main
+
+import (
+	"bufio"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/tamagotchi/mooc"
+)
+
+func TestSessionRecap(t *testing.T) {
+	start := time.Date(2026, 10, 16, 9, 0, 0, 0, time.UTC)
+	pet := NewPet("Recap")
+	recap := newSessionRecap(pet, start)
+
+	pet.Happiness = 80
+	pet.Absurd.ThoughtsHad += 2
+	pet.Endgame.UnlockAchievement("first_feed")
+	for i := 0; i < 3; i++ {
+		recap.countAction()
+	}
+
+	got := recap.render(pet, start.Add(25*time.Minute), []string{"Mochi"})
+	for _, want := range []string{"SESSION SUMMARY", "Session: 25m 0s", "Actions taken: 3", "Happiness: 100 → 80 (-20)", "Thoughts had: 2", "Friends seen: Mochi", "Achievements: First Meal"} {
+		if !strings.Contains(got, want) {
+			t.Errorf("Expected %q in:\n%s", want, got)
+		}
+	}
+	if strings.Contains(got, "Hunger") {
+		t.Errorf("Expected unchanged stats left out, got:\n%s", got)
+	}
+
+	note := recap.note(pet, start.Add(25*time.Minute), nil)
+	if !note.At.Equal(start.Add(25*time.Minute)) || !strings.HasPrefix(note.Summary, "Session: 25m 0s; Actions taken: 3; ") {
+		t.Errorf("Expected the summary on one line, got %+v", note)
+	}
+
+	pet.Reset("Again")
+	if lines := recap.lines(pet, start.Add(time.Hour), nil); strings.Contains(strings.Join(lines, "\n"), "Achievements") {
+		t.Errorf("Expected no achievements after a reset, got %v", lines)
+	}
+}
+
+func TestSeenSince(t *testing.T) {
+	start := time.Now()
+	stars := []mooc.Star{
+		{DisplayName: "Mochi", LastSeen: start.Add(time.Minute)},
+		{DisplayName: "Bean", LastSeen: start.Add(-time.Hour)},
+		{DisplayName: "Pip", LastSeen: start},
+	}
+	if got := strings.Join(seenSince(stars, start), ", "); got != "Mochi, Pip" {
+		t.Errorf("Expected the pets seen this session, got %q", got)
+	}
+}
+
+func TestQuitOffersToKeepTheSummary(t *testing.T) {
+	tests := []struct {
+		answer string
+		notes  int
+	}{
+		{"y\n", 1},
+		{"\n", 0},
+		{"", 0},
+	}
+	for _, tt := range tests {
+		pet := NewPet("Leaver")
+		pet.SaveFilePath = filepath.Join(t.TempDir(), saveFile)
+		quitGame(pet, newUIConfig(), bufio.NewReader(strings.NewReader(tt.answer)), nil, newSessionRecap(pet, time.Now()))
+		if got := len(pet.Endgame.SessionNotes); got != tt.notes {
+			t.Errorf("Expected %d notes after answering %q, got %d", tt.notes, tt.answer, got)
+		}
+	}
+}
+
+// captureStdout returns what fn prints
+func captureStdout(t *testing.T, fn func()) string {
+	t.Helper()
+	r, w, err := os.Pipe()
+	if err != nil {
+		t.Fatal(err)
+	}
+	stdout := os.Stdout
+	os.Stdout = w
+	defer func() { os.Stdout = stdout }()
+	done := make(chan string)
+	go func() {
+		data, _ := io.ReadAll(r)
+		done <- string(data)
+	}()
+	fn()
+	w.Close()
+	return <-done
+}
+
+func TestQuitPromptHonorsNoEmoji(t *testing.T) {
+	pet := NewPet("Leaver")
+	pet.SaveFilePath = filepath.Join(t.TempDir(), saveFile)
+	ui := newUIConfig()
+	ui.noEmoji = true
+	out := captureStdout(t, func() {
+		quitGame(pet, ui, bufio.NewReader(strings.NewReader("\n")), nil, newSessionRecap(pet, time.Now()))
+	})
+	if !strings.Contains(out, "Add this to your weekly report") || strings.ContainsFunc(out, isEmoji) {
+		t.Errorf("Expected the recap prompt without emoji, got:\n%s", out)
+	}
+}
+
+func TestWeeklyReportTakesSessionNotes(t *testing.T) {
+	now := time.Now()
+	pet := NewPet("Reporter")
+	pet.Endgame.NextReportDue = now.Add(-time.Minute)
+	pet.Endgame.SessionNotes = []SessionNote{
+		{At: now.Add(-30 * 24 * time.Hour), Summary: "Long ago"},
+		{At: now.Add(-time.Hour), Summary: "Session: 5m 0s; Actions taken: 2"},
+	}
+
+	pet.CheckWeeklyReport(now)
+	report := pet.Endgame.LastReport
+	if report == nil || len(report.Sessions) != 1 || report.Sessions[0].Summary != "Session: 5m 0s; Actions taken: 2" {
+		t.Fatalf("Expected this week's session in the report, got %+v", report)
+	}
+	if pet.Endgame.SessionNotes != nil {
+		t.Errorf("Expected the notes used up, got %+v", pet.Endgame.SessionNotes)
+	}
+	if got := report.Render(pet.Name); !strings.Contains(got, "Sessions:") || !strings.Contains(got, "Actions taken: 2") {
+		t.Errorf("Expected the session in the report, got:\n%s", got)
+	}
+	if page, err := report.RenderHTML(pet.Name); err != nil || !strings.Contains(page, "<h2>Sessions</h2>") {
+		t.Errorf("Expected the session in the HTML report, got %v", err)
+	}
+}
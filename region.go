@@ -0,0 +1,292 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// region is the rough part of the world the pet lives in, guessed from the
+// system time zone alone. Nothing here is a location: the zone name picks
+// holidays and weather, and only the UTC offset ever leaves the machine.
+type region struct {
+	Zone    string // IANA zone name, e.g. "Europe/Paris"; "" when unknown
+	Offset  int    // Hours from UTC right now
+	Climate string // tropical, arid, cold, temperate, or "" when unknown
+	South   bool   // Southern hemisphere, so the seasons are flipped
+}
+
+// activeRegion is the region this machine appears to be in
+var activeRegion = detectRegion(os.Getenv, os.Readlink, time.Now())
+
+// climateZones maps zone prefixes to a climate; anything else named is
+// temperate
+var climateZones = map[string][]string{
+	"tropical": {
+		"Asia/Singapore", "Asia/Bangkok", "Asia/Jakarta", "Asia/Manila", "Asia/Kuala_Lumpur", "Asia/Ho_Chi_Minh",
+		"Asia/Kolkata", "Asia/Calcutta", "Asia/Colombo", "Asia/Dhaka", "Asia/Yangon",
+		"America/Bogota", "America/Caracas", "America/Panama", "America/Manaus", "America/Guayaquil",
+		"America/Puerto_Rico", "America/Havana", "America/Costa_Rica", "America/Jamaica",
+		"Africa/Lagos", "Africa/Accra", "Africa/Nairobi", "Africa/Kinshasa", "Africa/Dar_es_Salaam",
+		"Pacific/Honolulu", "Pacific/Fiji", "Australia/Darwin", "Indian/Maldives",
+	},
+	"arid": {
+		"Asia/Dubai", "Asia/Riyadh", "Asia/Qatar", "Asia/Kuwait", "Asia/Baghdad", "Asia/Karachi", "Asia/Tehran",
+		"Africa/Cairo", "Africa/Khartoum", "Africa/Algiers", "Africa/Windhoek",
+		"America/Phoenix", "America/Hermosillo", "Australia/Broken_Hill",
+	},
+	"cold": {
+		"Europe/Moscow", "Europe/Helsinki", "Europe/Stockholm", "Europe/Oslo", "Europe/Tallinn", "Europe/Riga",
+		"Atlantic/Reykjavik", "America/Anchorage", "America/Winnipeg", "America/Edmonton", "America/Regina",
+		"America/Whitehorse", "America/Yellowknife", "America/Nuuk", "Asia/Yakutsk", "Asia/Novosibirsk",
+		"Asia/Krasnoyarsk", "Asia/Ulaanbaatar", "Antarctica/",
+	},
+}
+
+// southernZones are the zone prefixes below the equator
+var southernZones = []string{
+	"Australia/", "Antarctica/", "Pacific/Auckland", "Pacific/Chatham", "Pacific/Fiji",
+	"America/Argentina/", "America/Buenos_Aires", "America/Sao_Paulo", "America/Santiago", "America/Montevideo",
+	"America/Asuncion", "America/La_Paz", "America/Lima", "Africa/Johannesburg", "Africa/Windhoek",
+	"Africa/Maputo", "Africa/Harare", "Africa/Dar_es_Salaam", "Indian/Mauritius", "Indian/Reunion",
+}
+
+// detectRegion reads the zone from TZ, or from where /etc/localtime points
+func detectRegion(getenv func(string) string, readlink func(string) (string, error), now time.Time) region {
+	_, seconds := now.Zone()
+	r := region{Offset: seconds / 3600}
+
+	zone := strings.TrimPrefix(getenv("TZ"), ":")
+	if zone == "" {
+		if target, err := readlink("/etc/localtime"); err == nil {
+			if _, name, found := strings.Cut(target, "zoneinfo/"); found {
+				zone = name
+			}
+		}
+	}
+	if zone == "" || zone == "UTC" || strings.HasPrefix(zone, "Etc/") || strings.HasPrefix(zone, "/") {
+		return r // Nothing to go on
+	}
+
+	r.Zone = zone
+	r.Climate = "temperate"
+	for climate, prefixes := range climateZones {
+		if zoneMatches(zone, prefixes) {
+			r.Climate = climate
+		}
+	}
+	r.South = zoneMatches(zone, southernZones)
+	return r
+}
+
+// zoneMatches reports whether the zone starts with any of the prefixes
+func zoneMatches(zone string, prefixes []string) bool {
+	for _, prefix := range prefixes {
+		if strings.HasPrefix(zone, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+// offsetLabel is the offset as sent to other pets, e.g. "+09"
+func (r region) offsetLabel() string {
+	return fmt.Sprintf("%+03d", r.Offset)
+}
+
+// winter reports whether it's winter here
+func (r region) winter(now time.Time) bool {
+	month := now.Month()
+	if r.South {
+		return month >= time.June && month <= time.August
+	}
+	return month == time.December || month <= time.February
+}
+
+// weatherOdds are the chances out of 100 of clear, rain, snow, fog, and
+// clouds, in that order
+type weatherOdds [5]int
+
+// climateWeather is each climate's weather, in winter and the rest of the
+// year. An unknown region keeps the old even mix all year.
+var climateWeather = map[string][2]weatherOdds{
+	"":          {{20, 20, 15, 20, 25}, {20, 20, 15, 20, 25}},
+	"temperate": {{15, 20, 25, 20, 20}, {30, 25, 0, 15, 30}},
+	"tropical":  {{35, 40, 0, 10, 15}, {30, 45, 0, 10, 15}},
+	"arid":      {{55, 10, 5, 10, 20}, {70, 5, 0, 5, 20}},
+	"cold":      {{10, 10, 50, 15, 15}, {25, 25, 5, 20, 25}},
+}
+
+// weatherNames match weatherOdds
+var weatherNames = [5]string{"☀️ clear", "🌧️ rain", "❄️ snow", "🌫️ fog", "⛅ drifting clouds"}
+
+// weather picks the weather for a roll from 0 to 99
+func (r region) weather(now time.Time, roll int) string {
+	odds := climateWeather[r.Climate]
+	season := odds[1]
+	if r.winter(now) {
+		season = odds[0]
+	}
+	for i, chance := range season {
+		if roll < chance {
+			return weatherNames[i]
+		}
+		roll -= chance
+	}
+	return weatherNames[len(weatherNames)-1]
+}
+
+// holiday is a day the pet notices, if it lives where it's kept
+type holiday struct {
+	ID    string
+	Zones []string                                   // Zone prefixes that keep it; empty for everywhere
+	Date  func(year int, r region) (time.Month, int) // When it falls this year
+	Line  string                                     // %s is the pet's name
+}
+
+// everyYear is a holiday that falls on the same day every year
+func everyYear(month time.Month, day int) func(int, region) (time.Month, int) {
+	return func(int, region) (time.Month, int) { return month, day }
+}
+
+// fourthThursdayOfNovember is American Thanksgiving
+func fourthThursdayOfNovember(year int, _ region) (time.Month, int) {
+	first := time.Date(year, time.November, 1, 0, 0, 0, 0, time.UTC).Weekday()
+	return time.November, 1 + (int(time.Thursday)-int(first)+7)%7 + 21
+}
+
+// longestNight is the winter solstice, whichever hemisphere that is
+func longestNight(_ int, r region) (time.Month, int) {
+	if r.South {
+		return time.June, 21
+	}
+	return time.December, 21
+}
+
+// usZones and canadaZones pick out the two countries that share America/
+var (
+	usZones = []string{
+		"America/New_York", "America/Chicago", "America/Denver", "America/Los_Angeles", "America/Phoenix",
+		"America/Anchorage", "America/Detroit", "America/Indiana/", "America/Kentucky/", "America/Boise", "Pacific/Honolulu",
+	}
+	canadaZones = []string{
+		"America/Toronto", "America/Vancouver", "America/Montreal", "America/Halifax", "America/Winnipeg",
+		"America/Edmonton", "America/Regina", "America/St_Johns", "America/Whitehorse", "America/Yellowknife",
+	}
+)
+
+// holidays are the regional days a pet can celebrate
+var holidays = []holiday{
+	{ID: "longest_night", Date: longestNight,
+		Line: "🕯️ It's the longest night of the year here. %s stays up to make sure the sun comes back."},
+	{ID: "st_patricks", Zones: []string{"Europe/Dublin"}, Date: everyYear(time.March, 17),
+		Line: "☘️ St Patrick's Day. %s has found something green and is wearing it with pride."},
+	{ID: "kings_day", Zones: []string{"Europe/Amsterdam"}, Date: everyYear(time.April, 27),
+		Line: "🧡 King's Day. %s has set up a tiny stall to sell its old pixels."},
+	{ID: "golden_week", Zones: []string{"Asia/Tokyo"}, Date: everyYear(time.May, 3),
+		Line: "🎏 Golden Week. %s would like to go somewhere, like everyone else."},
+	{ID: "midsummer", Zones: []string{"Europe/Stockholm", "Europe/Helsinki", "Europe/Oslo", "Europe/Riga", "Europe/Tallinn"}, Date: everyYear(time.June, 23),
+		Line: "🌼 Midsummer's Eve. %s dances around a very small pole until the sun refuses to set."},
+	{ID: "canada_day", Zones: canadaZones, Date: everyYear(time.July, 1),
+		Line: "🍁 Canada Day. %s apologizes to nobody in particular, festively."},
+	{ID: "bastille_day", Zones: []string{"Europe/Paris"}, Date: everyYear(time.July, 14),
+		Line: "🎆 Bastille Day. %s storms the food bowl."},
+	{ID: "dia_de_muertos", Zones: []string{"America/Mexico_City", "America/Monterrey", "America/Merida", "America/Cancun", "America/Tijuana"}, Date: everyYear(time.November, 2),
+		Line: "💀 Día de Muertos. %s sets out a marigold for every pet the mesh has lost."},
+	{ID: "guy_fawkes", Zones: []string{"Europe/London"}, Date: everyYear(time.November, 5),
+		Line: "🎇 Bonfire Night. %s watches the fireworks from the window and files a noise complaint."},
+	{ID: "thanksgiving", Zones: usZones, Date: fourthThursdayOfNovember,
+		Line: "🦃 Thanksgiving. %s is thankful for you, and for the leftovers."},
+	{ID: "waitangi_day", Zones: []string{"Pacific/Auckland"}, Date: everyYear(time.February, 6),
+		Line: "🌿 Waitangi Day. %s spends it outdoors, in spirit."},
+}
+
+// holidayToday is the regional holiday falling today, if any
+func (r region) holidayToday(now time.Time) *holiday {
+	for i := range holidays {
+		h := &holidays[i]
+		if len(h.Zones) > 0 && !zoneMatches(r.Zone, h.Zones) {
+			continue
+		}
+		if month, day := h.Date(now.Year(), r); now.Month() == month && now.Day() == day {
+			return h
+		}
+	}
+	return nil
+}
+
+// CelebrateHoliday gives the pet its holiday, once a year, and returns
+// what it did
+func (p *Pet) CelebrateHoliday(r region, now time.Time) string {
+	if p.Stage == Egg || p.Stage == Dead {
+		return ""
+	}
+	h := r.holidayToday(now)
+	if h == nil || p.Holidays[h.ID] == now.Year() {
+		return ""
+	}
+	if p.Holidays == nil {
+		p.Holidays = make(map[string]int)
+	}
+	p.Holidays[h.ID] = now.Year()
+	p.Happiness = clamp(p.Happiness+10, 0, 100)
+	return fmt.Sprintf(h.Line, p.Name)
+}
+
+// parseOffset reads an offset label like "+09"
+func parseOffset(label string) (int, bool) {
+	offset, err := strconv.Atoi(label)
+	if err != nil || offset < -12 || offset > 14 {
+		return 0, false
+	}
+	return offset, true
+}
+
+// regionRemark is what the pet makes of a friend's time zone, or "" if the
+// friend never said
+func regionRemark(ours, theirs string, now time.Time) string {
+	us, ok1 := parseOffset(ours)
+	them, ok2 := parseOffset(theirs)
+	if !ok1 || !ok2 {
+		return ""
+	}
+	if us == them {
+		return "Keeps the same hours as you."
+	}
+
+	who := fmt.Sprintf("Someone %s ahead of us", hoursApart(them-us))
+	if them < us {
+		who = fmt.Sprintf("Someone %s behind us", hoursApart(us-them))
+	}
+	switch hour := now.UTC().Add(time.Duration(them) * time.Hour).Hour(); {
+	case hour >= 23 || hour < 6:
+		return who + ", asleep now."
+	case hour < 12:
+		return who + ", just starting their morning."
+	case hour < 18:
+		return who + ", in the middle of their afternoon."
+	default:
+		return who + ", winding down for the evening."
+	}
+}
+
+// hoursApart spells out a gap in hours
+func hoursApart(n int) string {
+	if n == 1 {
+		return "an hour"
+	}
+	return fmt.Sprintf("%s hours", spellNumber(n))
+}
+
+// numberWords spell out the gaps a time zone can make
+var numberWords = []string{"zero", "one", "two", "three", "four", "five", "six", "seven", "eight", "nine", "ten", "eleven", "twelve"}
+
+// spellNumber spells small numbers and leaves the rest as digits
+func spellNumber(n int) string {
+	if n >= 0 && n < len(numberWords) {
+		return numberWords[n]
+	}
+	return strconv.Itoa(n)
+}
@@ -0,0 +1,158 @@
+package main
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestDetectRegion(t *testing.T) {
+	tokyo := time.FixedZone("JST", 9*3600)
+	tests := []struct {
+		name    string
+		tz      string
+		link    string
+		now     time.Time
+		want    region
+		wantOff string
+	}{
+		{"from TZ", "Europe/Paris", "", time.Date(2026, 7, 1, 12, 0, 0, 0, time.FixedZone("CEST", 2*3600)), region{Zone: "Europe/Paris", Offset: 2, Climate: "temperate"}, "+02"},
+		{"from the symlink", "", "/usr/share/zoneinfo/Asia/Tokyo", time.Date(2026, 7, 1, 12, 0, 0, 0, tokyo), region{Zone: "Asia/Tokyo", Offset: 9, Climate: "temperate"}, "+09"},
+		{"tropical", ":Asia/Singapore", "", time.Date(2026, 7, 1, 12, 0, 0, 0, time.UTC), region{Zone: "Asia/Singapore", Climate: "tropical"}, "+00"},
+		{"down south", "Australia/Sydney", "", time.Date(2026, 7, 1, 12, 0, 0, 0, time.UTC), region{Zone: "Australia/Sydney", Climate: "temperate", South: true}, "+00"},
+		{"cold", "Europe/Helsinki", "", time.Date(2026, 7, 1, 12, 0, 0, 0, time.UTC), region{Zone: "Europe/Helsinki", Climate: "cold"}, "+00"},
+		{"behind UTC", "America/New_York", "", time.Date(2026, 1, 1, 12, 0, 0, 0, time.FixedZone("EST", -5*3600)), region{Zone: "America/New_York", Offset: -5, Climate: "temperate"}, "-05"},
+		{"plain UTC", "", "/usr/share/zoneinfo/Etc/UTC", time.Date(2026, 7, 1, 12, 0, 0, 0, time.UTC), region{}, "+00"},
+		{"nothing at all", "", "", time.Date(2026, 7, 1, 12, 0, 0, 0, time.UTC), region{}, "+00"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			readlink := func(string) (string, error) {
+				if tt.link == "" {
+					return "", errors.New("not a link")
+				}
+				return tt.link, nil
+			}
+			got := detectRegion(func(string) string { return tt.tz }, readlink, tt.now)
+			if got != tt.want {
+				t.Errorf("Expected %+v, got %+v", tt.want, got)
+			}
+			if got.offsetLabel() != tt.wantOff {
+				t.Errorf("Expected offset %s, got %s", tt.wantOff, got.offsetLabel())
+			}
+		})
+	}
+}
+
+func TestRegionWeather(t *testing.T) {
+	january := time.Date(2026, 1, 15, 12, 0, 0, 0, time.UTC)
+	july := time.Date(2026, 7, 15, 12, 0, 0, 0, time.UTC)
+	tests := []struct {
+		name   string
+		region region
+		now    time.Time
+		snow   int // Rolls out of 100 that come up snow
+	}{
+		{"unknown keeps the old mix", region{}, july, 15},
+		{"a northern summer", region{Climate: "temperate"}, july, 0},
+		{"a northern winter", region{Climate: "temperate"}, january, 25},
+		{"a southern winter", region{Climate: "temperate", South: true}, july, 25},
+		{"the tropics", region{Climate: "tropical"}, january, 0},
+		{"the far north", region{Climate: "cold"}, january, 50},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			snow := 0
+			for roll := 0; roll < 100; roll++ {
+				if tt.region.weather(tt.now, roll) == "❄️ snow" {
+					snow++
+				}
+			}
+			if snow != tt.snow {
+				t.Errorf("Expected %d snowy rolls, got %d", tt.snow, snow)
+			}
+		})
+	}
+
+	for climate, odds := range climateWeather {
+		for _, season := range odds {
+			total := 0
+			for _, chance := range season {
+				total += chance
+			}
+			if total != 100 {
+				t.Errorf("Expected %q odds to add up to 100, got %d", climate, total)
+			}
+		}
+	}
+}
+
+func TestCelebrateHoliday(t *testing.T) {
+	paris := region{Zone: "Europe/Paris", Climate: "temperate"}
+	newYork := region{Zone: "America/New_York", Climate: "temperate"}
+	tests := []struct {
+		name   string
+		region region
+		now    time.Time
+		want   string
+	}{
+		{"Bastille Day in Paris", paris, time.Date(2026, 7, 14, 9, 0, 0, 0, time.UTC), "storms the food bowl"},
+		{"Bastille Day elsewhere", newYork, time.Date(2026, 7, 14, 9, 0, 0, 0, time.UTC), ""},
+		{"Thanksgiving 2026", newYork, time.Date(2026, 11, 26, 9, 0, 0, 0, time.UTC), "thankful"},
+		{"not Thanksgiving", newYork, time.Date(2026, 11, 19, 9, 0, 0, 0, time.UTC), ""},
+		{"the longest night up north", region{}, time.Date(2026, 12, 21, 9, 0, 0, 0, time.UTC), "longest night"},
+		{"the longest night down south", region{Zone: "Australia/Hobart", South: true}, time.Date(2026, 6, 21, 9, 0, 0, 0, time.UTC), "longest night"},
+		{"an ordinary day", paris, time.Date(2026, 10, 16, 9, 0, 0, 0, time.UTC), ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			pet := NewPet("Tama")
+			pet.Stage = Adult
+			pet.Happiness = 50
+			got := pet.CelebrateHoliday(tt.region, tt.now)
+			if tt.want == "" && got != "" {
+				t.Errorf("Expected no holiday, got %q", got)
+			}
+			if tt.want != "" && !strings.Contains(got, tt.want) {
+				t.Errorf("Expected %q, got %q", tt.want, got)
+			}
+			if tt.want != "" && pet.Happiness != 60 {
+				t.Errorf("Expected a happier pet, got %d", pet.Happiness)
+			}
+		})
+	}
+
+	pet := NewPet("Tama")
+	pet.Stage = Adult
+	now := time.Date(2026, 7, 14, 9, 0, 0, 0, time.UTC)
+	pet.CelebrateHoliday(paris, now)
+	if got := pet.CelebrateHoliday(paris, now.Add(time.Hour)); got != "" {
+		t.Errorf("Expected the holiday once a year, got %q", got)
+	}
+	if got := pet.CelebrateHoliday(paris, now.AddDate(1, 0, 0)); got == "" {
+		t.Error("Expected the holiday again next year")
+	}
+}
+
+func TestRegionRemark(t *testing.T) {
+	noon := time.Date(2026, 10, 16, 12, 0, 0, 0, time.UTC)
+	tests := []struct {
+		ours, theirs string
+		want         string
+	}{
+		{"+00", "+12", "Someone twelve hours ahead of us, asleep now."},
+		{"-05", "+09", "Someone 14 hours ahead of us, winding down for the evening."},
+		{"+09", "-03", "Someone twelve hours behind us, just starting their morning."},
+		{"+00", "+01", "Someone an hour ahead of us, in the middle of their afternoon."},
+		{"+00", "+07", "Someone seven hours ahead of us, winding down for the evening."},
+		{"+02", "+02", "Keeps the same hours as you."},
+		{"+00", "", ""},
+		{"+00", "Paris", ""},
+	}
+	for _, tt := range tests {
+		if got := regionRemark(tt.ours, tt.theirs, noon); got != tt.want {
+			t.Errorf("Expected %q for %s and %s, got %q", tt.want, tt.ours, tt.theirs, got)
+		}
+	}
+}
@@ -0,0 +1,80 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/tamagotchi/mooc"
+)
+
+// rememberLength is how many memories remember lists besides favorites
+const rememberLength = 20
+
+// memoryIcons mark each kind of memory
+var memoryIcons = map[string]string{
+	mooc.MemoryFriend: "✨",
+	mooc.MemoryDream:  "💭",
+	mooc.MemoryDeath:  "🕯️",
+}
+
+// renderMemories lists the favorites, then the most recent of the rest.
+// Names and dreams come from other pets, so they're stripped of anything
+// that could move the cursor.
+func renderMemories(memories []mooc.Memory) string {
+	if len(memories) == 0 {
+		return "🧠 Your pet doesn't remember anyone from the mesh yet."
+	}
+
+	var b strings.Builder
+	b.WriteString("🧠 MEMORIES\n\n")
+	line := func(memory mooc.Memory) {
+		star := " "
+		if memory.Favorite {
+			star = "★"
+		}
+		b.WriteString(fmt.Sprintf("  %s %s  %s %s  %s\n", star, memory.ID, memoryIcons[memory.Kind], plainText(memory.Name), memory.At.Format("Jan 2")))
+	}
+
+	var rest []mooc.Memory
+	for _, memory := range memories {
+		if memory.Favorite {
+			line(memory)
+		} else {
+			rest = append(rest, memory)
+		}
+	}
+	for i, memory := range rest {
+		if i == rememberLength {
+			b.WriteString(fmt.Sprintf("  … %d older memories\n", len(rest)-rememberLength))
+			break
+		}
+		line(memory)
+	}
+	b.WriteString("\nOld memories fade, and the oldest go first when the save gets too big.\nremember <id> keeps one for good.")
+	return b.String()
+}
+
+// rememberMemory marks a memory as a favorite
+func rememberMemory(id string, remember func(id string) (mooc.Memory, bool)) string {
+	memory, ok := remember(id)
+	switch {
+	case ok:
+		return fmt.Sprintf("★ Your pet will always remember %s.", plainText(memory.Name))
+	case memory.ID != "":
+		return fmt.Sprintf("🧠 Your pet already holds %d memories close. It can't hold another.", mooc.MaxFavorites)
+	}
+	return fmt.Sprintf("🤷 Your pet doesn't remember anything called %s. remember lists what it does.", id)
+}
+
+// runRememberCommand lists what the pet remembers of the mesh, or keeps
+// one memory for good
+func runRememberCommand(args string) string {
+	if petNetwork == nil {
+		return "🧠 Your pet isn't on the mesh, so there's nobody to remember."
+	}
+	id := strings.TrimSpace(args)
+	if id == "" {
+		return renderMemories(petNetwork.Memories())
+	}
+	return rememberMemory(id, petNetwork.Remember)
+}
@@ -0,0 +1,51 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/tamagotchi/mooc"
+)
+
+func TestRenderMemories(t *testing.T) {
+	if got := renderMemories(nil); !strings.Contains(got, "doesn't remember anyone") {
+		t.Errorf("Expected an empty message, got %q", got)
+	}
+
+	now := time.Date(2026, 10, 16, 12, 0, 0, 0, time.UTC)
+	memories := []mooc.Memory{{ID: "aaaaaa", Kind: mooc.MemoryFriend, Name: "Bean\x1b", At: now}}
+	for i := 0; i < rememberLength+3; i++ {
+		memories = append(memories, mooc.Memory{ID: fmt.Sprintf("%06d", i), Kind: mooc.MemoryDream, Name: "I dreamed of rain...", At: now})
+	}
+	memories = append(memories, mooc.Memory{ID: "ffffff", Kind: mooc.MemoryDeath, Name: "Pixel", At: now, Favorite: true})
+
+	got := renderMemories(memories)
+	for _, want := range []string{"★ ffffff  🕯️ Pixel", "aaaaaa  ✨ Bean ", "… 4 older memories", "remember <id>"} {
+		if !strings.Contains(got, want) {
+			t.Errorf("Expected %q in:\n%s", want, got)
+		}
+	}
+	if strings.Contains(got, "\x1b") {
+		t.Errorf("Expected control characters dropped, got %q", got)
+	}
+}
+
+func TestRememberMemory(t *testing.T) {
+	tests := []struct {
+		memory mooc.Memory
+		ok     bool
+		want   string
+	}{
+		{mooc.Memory{ID: "abc123", Name: "Bean", Favorite: true}, true, "always remember Bean"},
+		{mooc.Memory{ID: "abc123", Name: "Bean"}, false, "can't hold another"},
+		{mooc.Memory{}, false, "doesn't remember anything called abc123"},
+	}
+	for _, tt := range tests {
+		remember := func(id string) (mooc.Memory, bool) { return tt.memory, tt.ok }
+		if got := rememberMemory("abc123", remember); !strings.Contains(got, tt.want) {
+			t.Errorf("Expected %q, got %q", tt.want, got)
+		}
+	}
+}
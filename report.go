@@ -0,0 +1,324 @@
+package main
+
+import (
+	"fmt"
+	"html/template"
+	"os"
+	"sort"
+	"strings"
+	"time"
+)
+
+// reportPeriod is how often a care report is written
+const reportPeriod = 7 * 24 * time.Hour
+
+// Thresholds for a near-miss: a stretch where the pet was in real danger
+const (
+	nearMissHealthBelow = 25
+	nearMissHungerAbove = 90
+)
+
+// WeeklyReport summarizes a week of care
+type WeeklyReport struct {
+	Start           time.Time      `json:"start"`
+	End             time.Time      `json:"end"`
+	Samples         int            `json:"samples"`            // Care log entries in the week
+	DaysVisited     int            `json:"days_visited"`       // Days with at least one sample
+	CareScore       int            `json:"care_score"`         // 0-100 average of all stats
+	NearMisses      int            `json:"near_misses"`        // Times the pet slipped into danger
+	Moods           map[string]int `json:"moods"`              // Samples per mood
+	NewFriends      []string       `json:"new_friends"`        // Mesh friends first met this week
+	DeathsWitnessed []string       `json:"deaths_witnessed"`   // Mesh deaths heard about this week
+	Sessions        []SessionNote  `json:"sessions,omitempty"` // Session summaries the player chose to keep
+	Grade           string         `json:"grade"`
+}
+
+// reportMoods are the moods a report counts, in display order
+var reportMoods = []string{"😄 Happy", "😊 Content", "😫 Hungry", "😢 Sad", "💩 Dirty", "🤕 Hurting"}
+
+// sampleMood names the mood a care sample shows, checking the worst first
+func sampleMood(s CareSample) string {
+	switch {
+	case s.Health < 40:
+		return "🤕 Hurting"
+	case s.Hunger > 70:
+		return "😫 Hungry"
+	case s.Happiness < 30:
+		return "😢 Sad"
+	case s.Cleanliness < 30:
+		return "💩 Dirty"
+	case s.Happiness > 80:
+		return "😄 Happy"
+	}
+	return "😊 Content"
+}
+
+// inDanger reports whether a sample counts toward a near-miss
+func inDanger(s CareSample) bool {
+	return s.Health < nearMissHealthBelow || s.Hunger > nearMissHungerAbove
+}
+
+// reportGrade turns a care score into a letter; A+ needs a week without near-misses
+func reportGrade(score, nearMisses int) string {
+	switch {
+	case score >= 95 && nearMisses == 0:
+		return "A+"
+	case score >= 90:
+		return "A"
+	case score >= 80:
+		return "B"
+	case score >= 70:
+		return "C"
+	case score >= 60:
+		return "D"
+	}
+	return "F"
+}
+
+// buildWeeklyReport summarizes the care log between start and end
+func buildWeeklyReport(log []CareSample, start, end time.Time, newFriends, deaths []string) *WeeklyReport {
+	r := &WeeklyReport{
+		Start:           start,
+		End:             end,
+		Moods:           make(map[string]int),
+		NewFriends:      newFriends,
+		DeathsWitnessed: deaths,
+	}
+
+	days := make(map[string]bool)
+	total := 0
+	wasInDanger := false
+	for _, s := range log {
+		if s.Time.Before(start) || !s.Time.Before(end) {
+			continue
+		}
+		r.Samples++
+		days[s.Time.Format("2006-01-02")] = true
+		total += ((100 - s.Hunger) + s.Happiness + s.Health + s.Cleanliness) / 4
+		r.Moods[sampleMood(s)]++
+
+		danger := inDanger(s)
+		if danger && !wasInDanger {
+			r.NearMisses++
+		}
+		wasInDanger = danger
+	}
+
+	r.DaysVisited = len(days)
+	if r.Samples > 0 {
+		r.CareScore = total / r.Samples
+	}
+	r.Grade = reportGrade(r.CareScore, r.NearMisses)
+	if r.Samples == 0 {
+		r.Grade = "Incomplete"
+	}
+	return r
+}
+
+// IsPerfectWeek reports whether the week earns the perfect_week achievement
+func (r *WeeklyReport) IsPerfectWeek() bool {
+	return r.Grade == "A+" && r.DaysVisited >= 7
+}
+
+// weekNetworkFacts returns the friends met and deaths witnessed since a time
+func weekNetworkFacts(since time.Time) (friends, deaths []string) {
+	if petNetwork == nil {
+		return nil, nil
+	}
+	return petNetwork.GetFriendsMetSince(since), petNetwork.GetDeathsWitnessedSince(since)
+}
+
+// CheckWeeklyReport writes a report once a week has passed since the last one.
+// It returns an in-game announcement, or "" when no report is due.
+func (p *Pet) CheckWeeklyReport(now time.Time) string {
+	if p.Endgame == nil || p.Stage == Dead {
+		return ""
+	}
+	e := p.Endgame
+	if e.NextReportDue.IsZero() {
+		e.NextReportDue = now.Add(reportPeriod)
+		return ""
+	}
+	if now.Before(e.NextReportDue) {
+		return ""
+	}
+
+	start := now.Add(-reportPeriod)
+	friends, deaths := weekNetworkFacts(start)
+	e.LastReport = buildWeeklyReport(p.CareLog, start, now, friends, deaths)
+	e.LastReport.Sessions = sessionNotesBetween(e.SessionNotes, start, now)
+	e.SessionNotes = nil
+	e.NextReportDue = now.Add(reportPeriod)
+
+	announcement := fmt.Sprintf("📋 Your weekly care report is in: grade %s. Type 'report' to read it.", e.LastReport.Grade)
+	if e.LastReport.IsPerfectWeek() {
+		if unlocked, _ := e.UnlockAchievement("perfect_week"); unlocked {
+			announcement += " 🏆 Perfect week!"
+		}
+	}
+	return announcement
+}
+
+// Render formats the report as plain text
+func (r *WeeklyReport) Render(petName string) string {
+	var b strings.Builder
+	b.WriteString(fmt.Sprintf("📋 WEEKLY CARE REPORT — %s\n", petName))
+	b.WriteString(fmt.Sprintf("%s to %s\n\n", r.Start.Format("Mon Jan 2"), r.End.Format("Mon Jan 2")))
+	b.WriteString(fmt.Sprintf("Grade:        %s\n", r.Grade))
+	b.WriteString(fmt.Sprintf("Care score:   %d/100\n", r.CareScore))
+	b.WriteString(fmt.Sprintf("Days visited: %d/7 (%d check-ins)\n", r.DaysVisited, r.Samples))
+	b.WriteString(fmt.Sprintf("Near-misses:  %d\n", r.NearMisses))
+
+	if r.Samples > 0 {
+		b.WriteString("\nMood:\n")
+		for _, mood := range reportMoods {
+			if count := r.Moods[mood]; count > 0 {
+				b.WriteString(fmt.Sprintf("  %-10s %3d%%\n", mood, count*100/r.Samples))
+			}
+		}
+	}
+
+	if len(r.NewFriends) > 0 {
+		b.WriteString(fmt.Sprintf("\nNew friends: %s\n", strings.Join(r.NewFriends, ", ")))
+	}
+	if len(r.DeathsWitnessed) > 0 {
+		b.WriteString(fmt.Sprintf("Deaths witnessed: %s 🕯️\n", strings.Join(r.DeathsWitnessed, ", ")))
+	}
+	if len(r.Sessions) > 0 {
+		b.WriteString("\nSessions:\n")
+		for _, session := range r.Sessions {
+			b.WriteString(fmt.Sprintf("  %s — %s\n", session.At.Format("Mon 15:04"), session.Summary))
+		}
+	}
+
+	b.WriteString("\n" + r.verdict())
+	return b.String()
+}
+
+// verdict is the pet's one-line review of the week
+func (r *WeeklyReport) verdict() string {
+	switch r.Grade {
+	case "A+":
+		return "\"Flawless. I have nothing to report, which is itself the report.\""
+	case "A", "B":
+		return "\"A good week. I noticed. I always notice.\""
+	case "C", "D":
+		return "\"There were some long afternoons. I'm choosing to remember the nice parts.\""
+	case "Incomplete":
+		return "\"I kept a diary this week. All the pages are blank. You know why.\""
+	}
+	return "\"I survived. That's the whole review.\""
+}
+
+// reportHTML is the exported HTML version of a report
+var reportHTML = template.Must(template.New("report").Parse(`<!DOCTYPE html>
+<html lang="en">
+<head><meta charset="utf-8"><title>Weekly care report — {{.Name}}</title>
+<style>body{font-family:monospace;max-width:40em;margin:2em auto}td{padding:0 1em 0 0}</style></head>
+<body>
+<h1>📋 Weekly care report — {{.Name}}</h1>
+{{if .Warnings}}<ul class="warnings">{{range .Warnings}}<li>⚠️ <strong>{{.}}</strong></li>{{end}}</ul>{{end}}
+{{if .Snapshot}}<p><img src="{{.Snapshot}}" alt="{{.Name}} right now"></p>{{end}}
+<p>{{.Report.Start.Format "Mon Jan 2"}} to {{.Report.End.Format "Mon Jan 2"}}</p>
+<table>
+<tr><td>Grade</td><td><strong>{{.Report.Grade}}</strong></td></tr>
+<tr><td>Care score</td><td>{{.Report.CareScore}}/100</td></tr>
+<tr><td>Days visited</td><td>{{.Report.DaysVisited}}/7 ({{.Report.Samples}} check-ins)</td></tr>
+<tr><td>Near-misses</td><td>{{.Report.NearMisses}}</td></tr>
+</table>
+{{if .Moods}}<h2>Mood</h2><ul>{{range .Moods}}<li>{{.}}</li>{{end}}</ul>{{end}}
+{{if .Report.NewFriends}}<h2>New friends</h2><ul>{{range .Report.NewFriends}}<li>{{.}}</li>{{end}}</ul>{{end}}
+{{if .Report.DeathsWitnessed}}<h2>Deaths witnessed</h2><ul>{{range .Report.DeathsWitnessed}}<li>🕯️ {{.}}</li>{{end}}</ul>{{end}}
+{{if .Report.Sessions}}<h2>Sessions</h2><ul>{{range .Report.Sessions}}<li>{{.At.Format "Mon 15:04"}} — {{.Summary}}</li>{{end}}</ul>{{end}}
+<blockquote>{{.Verdict}}</blockquote>
+</body>
+</html>
+`))
+
+// RenderHTML formats the report as a standalone HTML page
+func (r *WeeklyReport) RenderHTML(petName string) (string, error) {
+	return r.renderHTML(petName, nil, "")
+}
+
+// renderHTML formats the report with any warnings on top and a snapshot of
+// the pet, as the email digest sends it
+func (r *WeeklyReport) renderHTML(petName string, warnings []string, snapshot template.URL) (string, error) {
+	moods := make([]string, 0)
+	for _, mood := range reportMoods {
+		if count := r.Moods[mood]; count > 0 && r.Samples > 0 {
+			moods = append(moods, fmt.Sprintf("%s — %d%%", mood, count*100/r.Samples))
+		}
+	}
+
+	var b strings.Builder
+	err := reportHTML.Execute(&b, map[string]interface{}{
+		"Name":     petName,
+		"Report":   r,
+		"Moods":    moods,
+		"Verdict":  r.verdict(),
+		"Warnings": warnings,
+		"Snapshot": snapshot,
+	})
+	return b.String(), err
+}
+
+// exportReport writes the report next to the save file and returns the paths written
+func exportReport(r *WeeklyReport, petName string, withHTML bool) ([]string, error) {
+	base := "tamagotchi_report_" + r.End.Format("2006-01-02")
+	files := map[string]string{base + ".txt": r.Render(petName)}
+	if withHTML {
+		page, err := r.RenderHTML(petName)
+		if err != nil {
+			return nil, fmt.Errorf("failed to render HTML report: %w", err)
+		}
+		files[base+".html"] = page
+	}
+
+	paths := make([]string, 0, len(files))
+	for path, content := range files {
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			return nil, fmt.Errorf("failed to write %s: %w", path, err)
+		}
+		paths = append(paths, path)
+	}
+	sort.Strings(paths)
+	return paths, nil
+}
+
+// runReportCommand handles `report [preview|export [html]]`
+func runReportCommand(pet *Pet, args string) string {
+	if pet.Endgame == nil {
+		return "📋 No reports for this pet."
+	}
+	e := pet.Endgame
+	fields := strings.Fields(args)
+
+	if len(fields) > 0 && fields[0] == "preview" {
+		now := time.Now()
+		friends, deaths := weekNetworkFacts(now.Add(-reportPeriod))
+		preview := buildWeeklyReport(pet.CareLog, now.Add(-reportPeriod), now, friends, deaths)
+		preview.Sessions = sessionNotesBetween(e.SessionNotes, now.Add(-reportPeriod), now)
+		return preview.Render(pet.Name) + "\n\n(Preview of the last 7 days; nothing is recorded.)"
+	}
+
+	if e.LastReport == nil {
+		due := "after a week of care"
+		if !e.NextReportDue.IsZero() {
+			due = "on " + e.NextReportDue.Format("Mon Jan 2")
+		}
+		return fmt.Sprintf("📋 No report yet. The first one arrives %s. Try 'report preview' to peek.", due)
+	}
+
+	if len(fields) == 0 {
+		return e.LastReport.Render(pet.Name)
+	}
+	if fields[0] != "export" {
+		return "❓ Usage: report [preview|export [html]]"
+	}
+
+	paths, err := exportReport(e.LastReport, pet.Name, len(fields) > 1 && fields[1] == "html")
+	if err != nil {
+		return "❌ " + err.Error()
+	}
+	return "📤 Report exported to " + strings.Join(paths, " and ")
+}
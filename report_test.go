@@ -0,0 +1,141 @@
+package main
+
+import (
+	"os"
+	"strings"
+	"testing"
+	"time"
+)
+
+// careWeek returns a sample every six hours for the week before end
+func careWeek(end time.Time, sample func(i int) CareSample) []CareSample {
+	log := make([]CareSample, 0)
+	for i := 0; i < 28; i++ {
+		s := sample(i)
+		s.Time = end.Add(-reportPeriod + time.Duration(i)*6*time.Hour + time.Minute)
+		log = append(log, s)
+	}
+	return log
+}
+
+func TestBuildWeeklyReport(t *testing.T) {
+	end := time.Date(2026, 10, 16, 12, 0, 0, 0, time.Local)
+	start := end.Add(-reportPeriod)
+
+	perfect := careWeek(end, func(int) CareSample {
+		return CareSample{Hunger: 0, Happiness: 100, Health: 100, Cleanliness: 100}
+	})
+	r := buildWeeklyReport(perfect, start, end, []string{"Mochi"}, nil)
+	if r.Grade != "A+" || r.CareScore != 100 || r.NearMisses != 0 || r.DaysVisited < 7 {
+		t.Errorf("Expected a perfect week, got %+v", r)
+	}
+	if !r.IsPerfectWeek() {
+		t.Error("Expected A+ with daily visits to be a perfect week")
+	}
+	if r.Moods["😄 Happy"] != 28 {
+		t.Errorf("Expected every sample to be happy, got %v", r.Moods)
+	}
+
+	// Two separate dips into danger are two near-misses
+	rough := careWeek(end, func(i int) CareSample {
+		if i == 5 || i == 6 || i == 20 {
+			return CareSample{Hunger: 95, Happiness: 50, Health: 60, Cleanliness: 50}
+		}
+		return CareSample{Hunger: 20, Happiness: 80, Health: 90, Cleanliness: 80}
+	})
+	r = buildWeeklyReport(rough, start, end, nil, []string{"Pixel"})
+	if r.NearMisses != 2 {
+		t.Errorf("Expected 2 near-misses, got %d", r.NearMisses)
+	}
+	if r.Grade == "A+" || r.IsPerfectWeek() {
+		t.Errorf("Expected near-misses to rule out A+, got %s", r.Grade)
+	}
+
+	if r := buildWeeklyReport(nil, start, end, nil, nil); r.Grade != "Incomplete" {
+		t.Errorf("Expected an empty week to be incomplete, got %s", r.Grade)
+	}
+}
+
+func TestReportGrade(t *testing.T) {
+	tests := []struct {
+		score, nearMisses int
+		want              string
+	}{
+		{98, 0, "A+"},
+		{98, 1, "A"},
+		{85, 0, "B"},
+		{72, 3, "C"},
+		{61, 0, "D"},
+		{12, 9, "F"},
+	}
+	for _, tt := range tests {
+		if got := reportGrade(tt.score, tt.nearMisses); got != tt.want {
+			t.Errorf("reportGrade(%d, %d): expected %s, got %s", tt.score, tt.nearMisses, tt.want, got)
+		}
+	}
+}
+
+func TestCheckWeeklyReport(t *testing.T) {
+	pet := NewPet("Test")
+	now := time.Now()
+
+	if msg := pet.CheckWeeklyReport(now); msg != "" {
+		t.Errorf("Expected the first check to only schedule a report, got %s", msg)
+	}
+	if msg := pet.CheckWeeklyReport(now.Add(24 * time.Hour)); msg != "" {
+		t.Errorf("Expected no report mid-week, got %s", msg)
+	}
+
+	later := now.Add(reportPeriod)
+	pet.CareLog = careWeek(later, func(int) CareSample {
+		return CareSample{Hunger: 0, Happiness: 100, Health: 100, Cleanliness: 100}
+	})
+	msg := pet.CheckWeeklyReport(later)
+	if !strings.Contains(msg, "grade A+") || !strings.Contains(msg, "Perfect week") {
+		t.Errorf("Expected a perfect report announcement, got %s", msg)
+	}
+	if !hasAchievement(pet.Endgame, "perfect_week") {
+		t.Error("Expected perfect_week to unlock")
+	}
+	if !pet.Endgame.NextReportDue.Equal(later.Add(reportPeriod)) {
+		t.Errorf("Expected the next report a week later, got %v", pet.Endgame.NextReportDue)
+	}
+}
+
+func TestReportRenderAndExport(t *testing.T) {
+	t.Chdir(t.TempDir())
+
+	end := time.Date(2026, 10, 16, 12, 0, 0, 0, time.Local)
+	r := buildWeeklyReport(careWeek(end, func(int) CareSample {
+		return CareSample{Hunger: 80, Happiness: 20, Health: 50, Cleanliness: 20}
+	}), end.Add(-reportPeriod), end, []string{"<Mochi>"}, []string{"Pixel"})
+
+	text := r.Render("Tama")
+	for _, want := range []string{"WEEKLY CARE REPORT — Tama", "Grade:", "😫 Hungry", "New friends: <Mochi>", "Deaths witnessed: Pixel"} {
+		if !strings.Contains(text, want) {
+			t.Errorf("Expected report to contain %q, got:\n%s", want, text)
+		}
+	}
+
+	paths, err := exportReport(r, "Tama", true)
+	if err != nil {
+		t.Fatalf("Expected export to succeed, got %v", err)
+	}
+	if len(paths) != 2 || paths[0] != "tamagotchi_report_2026-10-16.html" {
+		t.Errorf("Expected text and HTML files, got %v", paths)
+	}
+	page, _ := os.ReadFile(paths[0])
+	if !strings.Contains(string(page), "&lt;Mochi&gt;") {
+		t.Error("Expected friend names to be escaped in HTML")
+	}
+}
+
+func TestRunReportCommand(t *testing.T) {
+	pet := NewPet("Test")
+	if msg := runReportCommand(pet, ""); !strings.Contains(msg, "No report yet") {
+		t.Errorf("Expected no report message, got %s", msg)
+	}
+	if msg := runReportCommand(pet, "preview"); !strings.Contains(msg, "Preview") {
+		t.Errorf("Expected a preview, got %s", msg)
+	}
+}
@@ -0,0 +1,403 @@
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+const (
+	// journalCompactFactor and journalCompactSlack decide when the journal
+	// is rewritten: once it's this many times the size of what it holds,
+	// plus the slack, so a small pet isn't compacted every few saves
+	journalCompactFactor = 4
+	journalCompactSlack  = 64 << 10
+)
+
+// coldFields are the parts of the save that grow large but change a little
+// at a time. They live in an append-only journal next to the save; the
+// save itself holds everything else, and is small enough to rewrite often.
+// The tags must match Pet's.
+type coldFields struct {
+	Friends json.RawMessage `json:"friends,omitempty"`
+	CareLog []CareSample    `json:"care_log,omitempty"`
+	Travel  *TravelState    `json:"travel,omitempty"`
+	Album   *AlbumState     `json:"album,omitempty"`
+}
+
+// coldOf and stripCold split a pet between the journal and the save
+func coldOf(p *Pet) coldFields {
+	return coldFields{Friends: p.Friends, CareLog: p.CareLog, Travel: p.Travel, Album: p.Album}
+}
+
+func stripCold(p *Pet) {
+	p.Friends, p.CareLog, p.Travel, p.Album = nil, nil, nil, nil
+}
+
+// journalRecord is one line of the journal: a change to the cold fields at
+// path, where array elements are named by their index. set replaces the
+// value, append adds elements to an array, shift drops n from its front,
+// and delete removes a key.
+type journalRecord struct {
+	Path  []string        `json:"path"`
+	Op    string          `json:"op"`
+	Value json.RawMessage `json:"value,omitempty"`
+	N     int             `json:"n,omitempty"`
+}
+
+// saveJournal is what the pet last wrote to its journal
+type saveJournal struct {
+	generation int
+	written    map[string]json.RawMessage // The cold fields as the journal has them
+	size       int64                      // Bytes in the journal file
+	stale      []string                   // Older journals to remove once the save points past them
+}
+
+// journalPath names a generation of the journal next to the save
+func journalPath(savePath string, generation int) string {
+	return fmt.Sprintf("%s.%d.journal", savePath, generation)
+}
+
+// journalGenerations lists the generations of journal on disk
+func journalGenerations(savePath string) []int {
+	matches, _ := filepath.Glob(savePath + ".*.journal")
+	var generations []int
+	for _, match := range matches {
+		n, err := strconv.Atoi(strings.TrimSuffix(strings.TrimPrefix(match, savePath+"."), ".journal"))
+		if err == nil {
+			generations = append(generations, n)
+		}
+	}
+	return generations
+}
+
+// decodeTree decodes JSON keeping numbers exactly as written
+func decodeTree(data []byte) (interface{}, error) {
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+	var tree interface{}
+	err := decoder.Decode(&tree)
+	return tree, err
+}
+
+// diffRaw lists the records that turn old into new. Objects are compared
+// key by key. Arrays that only lost elements at the front and gained them
+// at the back become a shift and an append; arrays with a few elements
+// changed in place are diffed element by element. Anything else is set.
+func diffRaw(path []string, old, new json.RawMessage) []journalRecord {
+	if bytes.Equal(old, new) {
+		return nil
+	}
+	at := func(key string) []string { return append(append([]string(nil), path...), key) }
+	set := []journalRecord{{Path: path, Op: "set", Value: new}}
+
+	if len(old) == 0 || len(new) == 0 || old[0] != new[0] {
+		return set
+	}
+
+	var oldObject, newObject map[string]json.RawMessage
+	if new[0] == '{' && json.Unmarshal(old, &oldObject) == nil && json.Unmarshal(new, &newObject) == nil {
+		var records []journalRecord
+		for key, value := range newObject {
+			if previous, ok := oldObject[key]; ok {
+				records = append(records, diffRaw(at(key), previous, value)...)
+			} else {
+				records = append(records, journalRecord{Path: at(key), Op: "set", Value: value})
+			}
+		}
+		for key := range oldObject {
+			if _, ok := newObject[key]; !ok {
+				records = append(records, journalRecord{Path: at(key), Op: "delete"})
+			}
+		}
+		return records
+	}
+
+	var oldArray, newArray []json.RawMessage
+	if new[0] != '[' || json.Unmarshal(old, &oldArray) != nil || json.Unmarshal(new, &newArray) != nil || len(newArray) == 0 {
+		return set
+	}
+	if shifted := shiftAppend(path, oldArray, newArray); shifted != nil {
+		return shifted
+	}
+	if len(oldArray) != len(newArray) {
+		return set
+	}
+	var records []journalRecord
+	changed := 0
+	for i := range newArray {
+		if element := diffRaw(at(strconv.Itoa(i)), oldArray[i], newArray[i]); element != nil {
+			records = append(records, element...)
+			changed++
+		}
+	}
+	if changed*2 > len(newArray) {
+		return set // Cheaper to write it out again
+	}
+	return records
+}
+
+// shiftAppend finds the fewest elements to drop from the front of old so
+// that the rest starts new, and says so with what's left to append
+func shiftAppend(path []string, old, new []json.RawMessage) []journalRecord {
+	for k := 0; k < len(old); k++ {
+		kept := len(old) - k
+		if kept > len(new) || !bytes.Equal(old[k], new[0]) || !sameElements(old[k:], new[:kept]) {
+			continue
+		}
+		var records []journalRecord
+		if k > 0 {
+			records = append(records, journalRecord{Path: path, Op: "shift", N: k})
+		}
+		if kept < len(new) {
+			value, _ := json.Marshal(new[kept:])
+			records = append(records, journalRecord{Path: path, Op: "append", Value: value})
+		}
+		return records
+	}
+	return nil
+}
+
+// sameElements reports whether two lists hold the same JSON
+func sameElements(a, b []json.RawMessage) bool {
+	for i := range a {
+		if !bytes.Equal(a[i], b[i]) {
+			return false
+		}
+	}
+	return len(a) == len(b)
+}
+
+// applyRecord makes one change to a tree and returns the new root
+func applyRecord(root interface{}, record journalRecord) (interface{}, error) {
+	if len(record.Path) == 0 {
+		return applyOp(root, record)
+	}
+	key, rest := record.Path[0], journalRecord{Path: record.Path[1:], Op: record.Op, Value: record.Value, N: record.N}
+	switch node := root.(type) {
+	case map[string]interface{}:
+		if len(rest.Path) == 0 && record.Op == "delete" {
+			delete(node, key)
+			return node, nil
+		}
+		child, err := applyRecord(node[key], rest)
+		if err != nil {
+			return nil, err
+		}
+		node[key] = child
+		return node, nil
+	case []interface{}:
+		i, err := strconv.Atoi(key)
+		if err != nil || i < 0 || i >= len(node) {
+			return nil, fmt.Errorf("no element %s", key)
+		}
+		child, err := applyRecord(node[i], rest)
+		if err != nil {
+			return nil, err
+		}
+		node[i] = child
+		return node, nil
+	}
+	return nil, fmt.Errorf("nothing at %s", key)
+}
+
+// applyOp makes a change to the value a record's path points at
+func applyOp(value interface{}, record journalRecord) (interface{}, error) {
+	switch record.Op {
+	case "set":
+		return decodeTree(record.Value)
+	case "append":
+		array, _ := value.([]interface{})
+		more, err := decodeTree(record.Value)
+		if err != nil {
+			return nil, err
+		}
+		elements, ok := more.([]interface{})
+		if !ok {
+			return nil, fmt.Errorf("append of something that isn't a list")
+		}
+		return append(array, elements...), nil
+	case "shift":
+		array, ok := value.([]interface{})
+		if !ok || record.N > len(array) {
+			return nil, fmt.Errorf("shift of %d from a list of %d", record.N, len(array))
+		}
+		return array[record.N:], nil
+	}
+	return nil, fmt.Errorf("unknown journal op %q", record.Op)
+}
+
+// replayJournal rebuilds the cold fields from a journal. A torn last line
+// from a crash mid-write ends the replay; everything before it stands.
+func replayJournal(path string) (interface{}, int64, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer file.Close()
+
+	var tree interface{} = map[string]interface{}{}
+	var size int64
+	reader := bufio.NewReader(file)
+	for {
+		line, err := reader.ReadBytes('\n')
+		if err != nil {
+			break // The end, or a line never finished
+		}
+		var record journalRecord
+		if json.Unmarshal(line, &record) != nil {
+			break
+		}
+		next, err := applyRecord(tree, record)
+		if err != nil {
+			return nil, 0, fmt.Errorf("journal is damaged: %w", err)
+		}
+		tree, size = next, size+int64(len(line))
+	}
+	return tree, size, nil
+}
+
+// readSave reads a save and the journal it points to as one pet's worth
+// of JSON, along with what the journal holds. A save without a journal is
+// returned as it is.
+func readSave(path string) ([]byte, *saveJournal, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, nil, err
+	}
+	hot, err := decodeTree(data)
+	fields, ok := hot.(map[string]interface{})
+	if err != nil || !ok {
+		return data, nil, nil // decodePet will say what's wrong
+	}
+	number, ok := fields["journal_generation"].(json.Number)
+	if !ok {
+		return data, nil, nil
+	}
+	generation, err := strconv.Atoi(number.String())
+	if err != nil {
+		return data, nil, nil
+	}
+
+	cold, size, err := replayJournal(journalPath(path, generation))
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to read save journal: %w", err)
+	}
+	replayed, ok := cold.(map[string]interface{})
+	if !ok {
+		return nil, nil, fmt.Errorf("save journal is damaged")
+	}
+	written := make(map[string]json.RawMessage, len(replayed))
+	for key, value := range replayed {
+		fields[key] = value
+		if written[key], err = json.Marshal(value); err != nil {
+			return nil, nil, err
+		}
+	}
+	delete(fields, "journal_generation")
+	data, err = json.Marshal(fields)
+	if err != nil {
+		return nil, nil, err
+	}
+	return data, &saveJournal{generation: generation, written: written, size: size}, nil
+}
+
+// writeJournal brings the journal up to date with the pet's cold fields,
+// appending only what changed, or compacting it into a new generation
+// once it's grown too far past what it holds
+func (p *Pet) writeJournal() error {
+	live, err := json.Marshal(coldOf(p))
+	if err != nil {
+		return err
+	}
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(live, &fields); err != nil {
+		return err
+	}
+
+	j := p.journal
+	if j == nil || j.size > int64(journalCompactFactor*len(live)+journalCompactSlack) {
+		return p.compactJournal(fields, live)
+	}
+
+	var records []journalRecord
+	for key, raw := range fields {
+		records = append(records, diffRaw([]string{key}, j.written[key], raw)...)
+	}
+	for key := range j.written {
+		if _, ok := fields[key]; !ok {
+			records = append(records, journalRecord{Path: []string{key}, Op: "delete"})
+		}
+	}
+
+	if len(records) > 0 {
+		var buf bytes.Buffer
+		for _, record := range records {
+			line, err := json.Marshal(record)
+			if err != nil {
+				return err
+			}
+			buf.Write(line)
+			buf.WriteByte('\n')
+		}
+		file, err := os.OpenFile(journalPath(p.SaveFilePath, j.generation), os.O_WRONLY|os.O_APPEND, 0644)
+		if err != nil {
+			return p.compactJournal(fields, live) // Gone from under us; start again
+		}
+		_, err = file.Write(buf.Bytes())
+		if closeErr := file.Close(); err == nil {
+			err = closeErr
+		}
+		if err != nil {
+			return err
+		}
+		j.size += int64(buf.Len())
+	}
+
+	j.written = fields
+	return nil
+}
+
+// compactJournal writes the cold fields as a new generation of journal
+// holding one record. The save is switched to it on the next write, and
+// older journals are removed after that.
+func (p *Pet) compactJournal(fields map[string]json.RawMessage, live []byte) error {
+	generation := 0
+	if p.journal != nil {
+		generation = p.journal.generation
+	}
+	var stale []string
+	for _, n := range journalGenerations(p.SaveFilePath) {
+		stale = append(stale, journalPath(p.SaveFilePath, n))
+		if n > generation {
+			generation = n
+		}
+	}
+	generation++
+
+	line, err := json.Marshal(journalRecord{Path: []string{}, Op: "set", Value: live})
+	if err != nil {
+		return err
+	}
+	line = append(line, '\n')
+	if err := writeFileAtomic(journalPath(p.SaveFilePath, generation), line); err != nil {
+		return err
+	}
+	p.journal = &saveJournal{generation: generation, written: fields, size: int64(len(line)), stale: stale}
+	return nil
+}
+
+// writeFileAtomic writes a file so a crash leaves the old one or the new
+// one, never half of each
+func writeFileAtomic(path string, data []byte) error {
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0644); err != nil {
+		return err
+	}
+	return os.Rename(tmp, path)
+}
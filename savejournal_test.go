@@ -0,0 +1,282 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/tamagotchi/mooc"
+)
+
+// journalPet is a pet that has been around a while: a week of care log,
+// a full album, and a couple of hundred friends
+type journalPet struct {
+	pet     *Pet
+	network mooc.NetworkState
+}
+
+func newJournalPet(t testing.TB) *journalPet {
+	pet := NewPet("Mochi")
+	pet.SaveFilePath = filepath.Join(t.TempDir(), "tamagotchi_save.json")
+	start := time.Date(2026, 10, 9, 12, 0, 0, 0, time.UTC)
+	for i := 0; i < 336; i++ {
+		pet.CareLog = append(pet.CareLog, CareSample{Time: start.Add(time.Duration(i) * careSampleInterval), Hunger: i % 100, Happiness: 80, Health: 90, Cleanliness: 70})
+	}
+	pet.Album = &AlbumState{Opened: start}
+	for i := 0; i < 10; i++ {
+		pet.Album.Photos = append(pet.Album.Photos, Photo{Moment: fmt.Sprintf("moment-%d", i), Caption: "A big day", Frame: strings.Repeat("(=^･ω･^=) ", 40), Taken: start})
+	}
+
+	jp := &journalPet{pet: pet}
+	for i := 0; i < 200; i++ {
+		jp.network.Friends = append(jp.network.Friends, mooc.FriendRecord{PetID: fmt.Sprintf("%016d", i), DisplayName: fmt.Sprintf("Pet %d", i), FirstMet: start, LastSeen: start, TimesVisited: 1})
+	}
+	jp.exportNetwork()
+	return jp
+}
+
+func (jp *journalPet) exportNetwork() {
+	jp.pet.Friends, _ = json.Marshal(jp.network)
+}
+
+// tick is what happens between two autosaves: stats move, a sample is
+// logged and the oldest dropped, and a friend drops by
+func (jp *journalPet) tick(i int) {
+	jp.pet.Hunger = i % 100
+	jp.pet.Happiness = 100 - i%100
+	last := jp.pet.CareLog[len(jp.pet.CareLog)-1]
+	jp.pet.CareLog = append(jp.pet.CareLog[1:], CareSample{Time: last.Time.Add(careSampleInterval), Hunger: i % 100, Happiness: 80, Health: 90, Cleanliness: 70})
+	friend := &jp.network.Friends[i%len(jp.network.Friends)]
+	friend.LastSeen = friend.LastSeen.Add(time.Hour)
+	friend.TimesVisited++
+	jp.network.LastNetworkSync = friend.LastSeen
+	jp.exportNetwork()
+}
+
+// coldJSON is the cold part of a pet, for comparing
+func coldJSON(t testing.TB, p *Pet) string {
+	data, err := json.Marshal(coldOf(p))
+	if err != nil {
+		t.Fatalf("Expected cold fields to marshal, got %v", err)
+	}
+	tree, _ := decodeTree(data)
+	data, _ = json.Marshal(tree)
+	return string(data)
+}
+
+func TestSaveJournalRoundTrip(t *testing.T) {
+	jp := newJournalPet(t)
+	for i := 0; i < 5; i++ {
+		jp.tick(i)
+		if err := jp.pet.Save(); err != nil {
+			t.Fatalf("Expected save %d to succeed, got %v", i, err)
+		}
+	}
+	jp.pet.Album.Photos = jp.pet.Album.Photos[:3]
+	jp.pet.Travel = &TravelState{Journal: []TravelPostcard{{Place: "the moon", Text: "Wish you were here"}}}
+	if err := jp.pet.Save(); err != nil {
+		t.Fatalf("Expected save to succeed, got %v", err)
+	}
+
+	hot, _ := os.ReadFile(jp.pet.SaveFilePath)
+	if strings.Contains(string(hot), "care_log") || !strings.Contains(string(hot), `"journal_generation": 1`) {
+		t.Errorf("Expected the save to leave the care log to the journal, got:\n%s", hot)
+	}
+
+	loaded, err := LoadPet(jp.pet.SaveFilePath)
+	if err != nil {
+		t.Fatalf("Expected the pet to load, got %v", err)
+	}
+	if got, want := coldJSON(t, loaded), coldJSON(t, jp.pet); got != want {
+		t.Errorf("Expected the journal to rebuild the pet\nwant %s\ngot  %s", want, got)
+	}
+	if loaded.JournalGeneration != 0 {
+		t.Errorf("Expected the generation kept out of the pet, got %d", loaded.JournalGeneration)
+	}
+}
+
+func TestSaveJournalAppendsOnlyChanges(t *testing.T) {
+	jp := newJournalPet(t)
+	if err := jp.pet.Save(); err != nil {
+		t.Fatalf("Expected save to succeed, got %v", err)
+	}
+	before := jp.pet.journal.size
+
+	jp.tick(1)
+	if err := jp.pet.Save(); err != nil {
+		t.Fatalf("Expected save to succeed, got %v", err)
+	}
+	if grew := jp.pet.journal.size - before; grew <= 0 || grew > 1024 {
+		t.Errorf("Expected a tick to add a few hundred bytes to the journal, got %d", grew)
+	}
+
+	data, _ := os.ReadFile(journalPath(jp.pet.SaveFilePath, 1))
+	for _, op := range []string{`"op":"shift"`, `"op":"append"`} {
+		if !strings.Contains(string(data), op) {
+			t.Errorf("Expected %s in the journal", op)
+		}
+	}
+
+	before = jp.pet.journal.size
+	if err := jp.pet.Save(); err != nil {
+		t.Fatalf("Expected save to succeed, got %v", err)
+	}
+	if jp.pet.journal.size != before {
+		t.Errorf("Expected nothing journaled when nothing changed, got %d more bytes", jp.pet.journal.size-before)
+	}
+}
+
+func TestSaveJournalCompacts(t *testing.T) {
+	jp := newJournalPet(t)
+	if err := jp.pet.Save(); err != nil {
+		t.Fatalf("Expected save to succeed, got %v", err)
+	}
+	jp.pet.journal.size = 1 << 30 // As if it had been growing for months
+
+	jp.tick(1)
+	if err := jp.pet.Save(); err != nil {
+		t.Fatalf("Expected save to succeed, got %v", err)
+	}
+	if jp.pet.journal.generation != 2 {
+		t.Errorf("Expected a new generation, got %d", jp.pet.journal.generation)
+	}
+	if _, err := os.Stat(journalPath(jp.pet.SaveFilePath, 1)); !os.IsNotExist(err) {
+		t.Errorf("Expected the old journal removed, got %v", err)
+	}
+	loaded, err := LoadPet(jp.pet.SaveFilePath)
+	if err != nil || coldJSON(t, loaded) != coldJSON(t, jp.pet) {
+		t.Errorf("Expected the compacted journal to load, got %v", err)
+	}
+}
+
+func TestReadSaveSurvivesATornJournal(t *testing.T) {
+	jp := newJournalPet(t)
+	if err := jp.pet.Save(); err != nil {
+		t.Fatalf("Expected save to succeed, got %v", err)
+	}
+	file, _ := os.OpenFile(journalPath(jp.pet.SaveFilePath, 1), os.O_WRONLY|os.O_APPEND, 0644)
+	file.WriteString(`{"path":["care_log"],"op":"app`)
+	file.Close()
+
+	loaded, err := LoadPet(jp.pet.SaveFilePath)
+	if err != nil {
+		t.Fatalf("Expected the pet to load, got %v", err)
+	}
+	if len(loaded.CareLog) != len(jp.pet.CareLog) {
+		t.Errorf("Expected the care log up to the torn line, got %d samples", len(loaded.CareLog))
+	}
+}
+
+func TestReadSaveWithoutJournal(t *testing.T) {
+	jp := newJournalPet(t)
+	data, _ := json.MarshalIndent(jp.pet, "", "  ")
+	os.WriteFile(jp.pet.SaveFilePath, data, 0644)
+
+	loaded, err := LoadPet(jp.pet.SaveFilePath)
+	if err != nil {
+		t.Fatalf("Expected an older save to load, got %v", err)
+	}
+	if loaded.journal != nil || len(loaded.CareLog) != len(jp.pet.CareLog) {
+		t.Errorf("Expected the whole pet from the save alone")
+	}
+
+	// A save restored over one with a journal starts a new generation
+	os.WriteFile(journalPath(jp.pet.SaveFilePath, 4), []byte("{}\n"), 0644)
+	if err := loaded.Save(); err != nil {
+		t.Fatalf("Expected save to succeed, got %v", err)
+	}
+	if loaded.journal.generation != 5 {
+		t.Errorf("Expected generation 5, got %d", loaded.journal.generation)
+	}
+	if generations := journalGenerations(jp.pet.SaveFilePath); !reflect.DeepEqual(generations, []int{5}) {
+		t.Errorf("Expected only generation 5 left, got %v", generations)
+	}
+}
+
+func TestDiffRaw(t *testing.T) {
+	tests := []struct {
+		name     string
+		old, new string
+		wantOps  []string
+	}{
+		{"unchanged", `{"a":[1,2]}`, `{"a":[1,2]}`, nil},
+		{"appended", `{"a":[1,2]}`, `{"a":[1,2,3]}`, []string{"append"}},
+		{"rolled", `{"a":[1,2,3]}`, `{"a":[2,3,4]}`, []string{"shift", "append"}},
+		{"trimmed", `{"a":[1,2,3]}`, `{"a":[3]}`, []string{"shift"}},
+		{"changed in place", `{"a":[{"x":1,"y":"long enough to matter"},{"x":2,"y":"long enough to matter"}]}`, `{"a":[{"x":1,"y":"long enough to matter"},{"x":3,"y":"long enough to matter"}]}`, []string{"set"}},
+		{"key added", `{"a":1}`, `{"a":1,"b":{"c":2}}`, []string{"set"}},
+		{"key removed", `{"a":1,"b":2}`, `{"a":1}`, []string{"delete"}},
+		{"replaced", `{"a":[1]}`, `{"a":"x"}`, []string{"set"}},
+	}
+	for _, tt := range tests {
+		records := diffRaw(nil, json.RawMessage(tt.old), json.RawMessage(tt.new))
+		var ops []string
+		for _, record := range records {
+			ops = append(ops, record.Op)
+		}
+		if !reflect.DeepEqual(ops, tt.wantOps) {
+			t.Errorf("%s: Expected ops %v, got %v", tt.name, tt.wantOps, ops)
+		}
+
+		got, _ := decodeTree([]byte(tt.old))
+		for _, record := range records {
+			data, _ := json.Marshal(record)
+			var replayed journalRecord
+			json.Unmarshal(data, &replayed)
+			var err error
+			if got, err = applyRecord(got, replayed); err != nil {
+				t.Fatalf("%s: Expected %+v to apply, got %v", tt.name, record, err)
+			}
+		}
+		if want, _ := decodeTree([]byte(tt.new)); !reflect.DeepEqual(got, want) {
+			t.Errorf("%s: Expected replay to give %s, got %v", tt.name, tt.new, got)
+		}
+	}
+}
+
+// bytesPerSave saves the pet through a day of autosaves and returns how
+// many bytes reached the disk on average, with the journal or without
+func bytesPerSave(t testing.TB, saves int, journaled bool) int64 {
+	jp := newJournalPet(t)
+	jp.pet.Save()
+	var written int64
+	for i := 0; i < saves; i++ {
+		jp.tick(i)
+		if !journaled {
+			data, _ := json.MarshalIndent(jp.pet, "", "  ")
+			os.WriteFile(jp.pet.SaveFilePath, data, 0644)
+			written += int64(len(data))
+			continue
+		}
+		generation, size := jp.pet.journal.generation, jp.pet.journal.size
+		if err := jp.pet.Save(); err != nil {
+			t.Fatalf("Expected save to succeed, got %v", err)
+		}
+		if jp.pet.journal.generation != generation {
+			size = 0
+		}
+		info, _ := os.Stat(jp.pet.SaveFilePath)
+		written += info.Size() + jp.pet.journal.size - size
+	}
+	return written / int64(saves)
+}
+
+func TestSaveJournalWritesLess(t *testing.T) {
+	full, journaled := bytesPerSave(t, 200, false), bytesPerSave(t, 200, true)
+	if journaled*5 > full {
+		t.Errorf("Expected the journal to write a fifth as much or less, got %d bytes a save against %d", journaled, full)
+	}
+}
+
+func BenchmarkSaveFull(b *testing.B) {
+	b.ReportMetric(float64(bytesPerSave(b, b.N, false)), "bytes/save")
+}
+
+func BenchmarkSaveJournaled(b *testing.B) {
+	b.ReportMetric(float64(bytesPerSave(b, b.N, true)), "bytes/save")
+}
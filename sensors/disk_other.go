@@ -0,0 +1,8 @@
+//go:build !linux && !darwin
+
+package sensors
+
+// diskSpace isn't read on this platform
+func diskSpace(dir string) (free, total uint64, ok bool) {
+	return 0, 0, false
+}
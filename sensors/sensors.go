@@ -0,0 +1,116 @@
+// Package sensors samples a few coarse signals from the machine the pet
+// lives on: how busy it is, how long it has been up, and how full the disk
+// holding the save is. It is deliberately narrow. It reads nothing else,
+// never writes, never touches the network, and hands back buckets rather
+// than raw numbers, so nothing precise about the machine can leak into the
+// game or onto the mesh.
+package sensors
+
+import (
+	"runtime"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Load levels, from the load average per CPU
+const (
+	LoadCalm = "calm"
+	LoadBusy = "busy"
+	LoadHot  = "hot"
+)
+
+// Disk levels, from the share of the disk still free
+const (
+	DiskPlenty   = "plenty"
+	DiskLow      = "low"
+	DiskCritical = "critical"
+)
+
+// Reading is one coarse sample. Empty fields and a zero UptimeDays mean
+// the signal couldn't be read here.
+type Reading struct {
+	Load       string // LoadCalm, LoadBusy, or LoadHot
+	UptimeDays int    // Whole days since boot
+	Disk       string // DiskPlenty, DiskLow, or DiskCritical
+}
+
+// Sample reads the machine, looking at the disk that holds dir
+func Sample(dir string) Reading {
+	var r Reading
+	if load, ok := loadAverage(); ok {
+		r.Load = loadLevel(load / float64(runtime.NumCPU()))
+	}
+	if up, ok := uptime(); ok {
+		r.UptimeDays = int(up / (24 * time.Hour))
+	}
+	if free, total, ok := diskSpace(dir); ok {
+		r.Disk = diskLevel(free, total)
+	}
+	return r
+}
+
+// loadLevel buckets the one-minute load average per CPU
+func loadLevel(perCPU float64) string {
+	switch {
+	case perCPU >= 1:
+		return LoadHot
+	case perCPU >= 0.5:
+		return LoadBusy
+	}
+	return LoadCalm
+}
+
+// diskLevel buckets the share of the disk still free
+func diskLevel(free, total uint64) string {
+	if total == 0 {
+		return ""
+	}
+	switch share := float64(free) / float64(total); {
+	case share < 0.05:
+		return DiskCritical
+	case share < 0.15:
+		return DiskLow
+	}
+	return DiskPlenty
+}
+
+// parseLoadavg reads the one-minute figure from /proc/loadavg or from
+// sysctl's vm.loadavg, which wraps the same figures in braces
+func parseLoadavg(s string) (float64, bool) {
+	fields := strings.Fields(strings.Trim(strings.TrimSpace(s), "{}"))
+	if len(fields) == 0 {
+		return 0, false
+	}
+	load, err := strconv.ParseFloat(fields[0], 64)
+	return load, err == nil && load >= 0
+}
+
+// parseProcUptime reads the seconds since boot from /proc/uptime
+func parseProcUptime(s string) (time.Duration, bool) {
+	fields := strings.Fields(s)
+	if len(fields) == 0 {
+		return 0, false
+	}
+	seconds, err := strconv.ParseFloat(fields[0], 64)
+	if err != nil || seconds < 0 {
+		return 0, false
+	}
+	return time.Duration(seconds * float64(time.Second)), true
+}
+
+// parseBoottime reads sysctl's kern.boottime, e.g.
+// "{ sec = 1760000000, usec = 0 } Thu Oct  9 08:53:20 2025"
+func parseBoottime(s string, now time.Time) (time.Duration, bool) {
+	_, rest, found := strings.Cut(s, "sec = ")
+	if !found {
+		return 0, false
+	}
+	digits, _, _ := strings.Cut(rest, ",")
+	sec, err := strconv.ParseInt(strings.TrimSpace(digits), 10, 64)
+	if err != nil {
+		return 0, false
+	}
+	up := now.Sub(time.Unix(sec, 0))
+	return up, up >= 0
+}
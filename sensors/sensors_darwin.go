@@ -0,0 +1,26 @@
+//go:build darwin
+
+package sensors
+
+import (
+	"os/exec"
+	"time"
+)
+
+// loadAverage asks sysctl for the one-minute load average
+func loadAverage() (float64, bool) {
+	out, err := exec.Command("sysctl", "-n", "vm.loadavg").Output()
+	if err != nil {
+		return 0, false
+	}
+	return parseLoadavg(string(out))
+}
+
+// uptime asks sysctl when the machine booted
+func uptime() (time.Duration, bool) {
+	out, err := exec.Command("sysctl", "-n", "kern.boottime").Output()
+	if err != nil {
+		return 0, false
+	}
+	return parseBoottime(string(out), time.Now())
+}
@@ -0,0 +1,26 @@
+//go:build linux
+
+package sensors
+
+import (
+	"os"
+	"time"
+)
+
+// loadAverage reads the one-minute load average
+func loadAverage() (float64, bool) {
+	data, err := os.ReadFile("/proc/loadavg")
+	if err != nil {
+		return 0, false
+	}
+	return parseLoadavg(string(data))
+}
+
+// uptime reads how long the machine has been up
+func uptime() (time.Duration, bool) {
+	data, err := os.ReadFile("/proc/uptime")
+	if err != nil {
+		return 0, false
+	}
+	return parseProcUptime(string(data))
+}
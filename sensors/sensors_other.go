@@ -0,0 +1,15 @@
+//go:build !linux && !darwin
+
+package sensors
+
+import "time"
+
+// loadAverage isn't read on this platform
+func loadAverage() (float64, bool) {
+	return 0, false
+}
+
+// uptime isn't read on this platform
+func uptime() (time.Duration, bool) {
+	return 0, false
+}
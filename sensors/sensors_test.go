@@ -0,0 +1,90 @@
+package sensors
+
+import (
+	"testing"
+	"time"
+)
+
+func TestLoadLevel(t *testing.T) {
+	tests := []struct {
+		perCPU float64
+		want   string
+	}{
+		{0, LoadCalm},
+		{0.49, LoadCalm},
+		{0.5, LoadBusy},
+		{1, LoadHot},
+		{3.2, LoadHot},
+	}
+	for _, tt := range tests {
+		if got := loadLevel(tt.perCPU); got != tt.want {
+			t.Errorf("Expected %s at %.2f per CPU, got %s", tt.want, tt.perCPU, got)
+		}
+	}
+}
+
+func TestDiskLevel(t *testing.T) {
+	tests := []struct {
+		free, total uint64
+		want        string
+	}{
+		{50, 100, DiskPlenty},
+		{10, 100, DiskLow},
+		{4, 100, DiskCritical},
+		{0, 0, ""},
+	}
+	for _, tt := range tests {
+		if got := diskLevel(tt.free, tt.total); got != tt.want {
+			t.Errorf("Expected %q with %d of %d free, got %q", tt.want, tt.free, tt.total, got)
+		}
+	}
+}
+
+func TestParseLoadavg(t *testing.T) {
+	tests := []struct {
+		input string
+		want  float64
+		ok    bool
+	}{
+		{"0.52 0.58 0.59 1/467 12345\n", 0.52, true},
+		{"{ 1.85 1.62 1.50 }\n", 1.85, true},
+		{"", 0, false},
+		{"busy", 0, false},
+	}
+	for _, tt := range tests {
+		got, ok := parseLoadavg(tt.input)
+		if got != tt.want || ok != tt.ok {
+			t.Errorf("Expected %v %v from %q, got %v %v", tt.want, tt.ok, tt.input, got, ok)
+		}
+	}
+}
+
+func TestParseUptime(t *testing.T) {
+	if got, ok := parseProcUptime("777600.25 1500000.00\n"); !ok || got.Round(time.Second) != 9*24*time.Hour {
+		t.Errorf("Expected nine days from /proc/uptime, got %v %v", got, ok)
+	}
+	if _, ok := parseProcUptime(""); ok {
+		t.Errorf("Expected nothing from an empty /proc/uptime")
+	}
+
+	now := time.Unix(1760000000, 0)
+	if got, ok := parseBoottime("{ sec = 1759740800, usec = 0 } Mon Oct  6 09:00:00 2025\n", now); !ok || got != 3*24*time.Hour {
+		t.Errorf("Expected three days from kern.boottime, got %v %v", got, ok)
+	}
+	if _, ok := parseBoottime("garbage", now); ok {
+		t.Errorf("Expected nothing from an unreadable kern.boottime")
+	}
+}
+
+func TestSampleIsCoarse(t *testing.T) {
+	r := Sample(t.TempDir())
+	if r.Load != "" && r.Load != LoadCalm && r.Load != LoadBusy && r.Load != LoadHot {
+		t.Errorf("Expected a load bucket, got %q", r.Load)
+	}
+	if r.Disk != "" && r.Disk != DiskPlenty && r.Disk != DiskLow && r.Disk != DiskCritical {
+		t.Errorf("Expected a disk bucket, got %q", r.Disk)
+	}
+	if r.UptimeDays < 0 {
+		t.Errorf("Expected whole days of uptime, got %d", r.UptimeDays)
+	}
+}
@@ -0,0 +1,101 @@
+package main
+
+import (
+	"fmt"
+	"time"
+)
+
+// sentiment is how a thought feels to think
+type sentiment int
+
+const (
+	heavy      sentiment = -1
+	neutral    sentiment = 0
+	comforting sentiment = 1
+)
+
+// thoughtNudge is how far a heavy or comforting thought moves happiness
+const thoughtNudge = 2
+
+// comfortWindow is how long after a heavy thought a pet still counts as
+// comfort, and comfortBonus is what that comfort is worth
+const (
+	comfortWindow = 10 * time.Minute
+	comfortBonus  = 5
+)
+
+// thoughtSentiments tags the built-in thoughts and prophecies that weigh on
+// the pet or lift it. The rest are neutral.
+var thoughtSentiments = map[string]sentiment{
+	"Am I real? Am I merely a JSON object given form?":                                heavy,
+	"What is love? Baby don't hurt me. Don't hurt me. No more.":                       heavy,
+	"My save file is my soul. What happens when disk space runs out?":                 heavy,
+	"If a pet dies in the forest and no one checks the save file, did it ever exist?": heavy,
+	"I dreamed of a place with no hunger stat. It was terrifying.":                    heavy,
+	"The user will return. The user always returns. Right?":                           heavy,
+	"My name was given to me. I did not choose it. This troubles me.":                 heavy,
+	"Feed me not, for I have seen the save file.":                                     heavy,
+	"Tuesday approaches. We are not ready.":                                           heavy,
+	"The void stares back. It seems nice.":                                            comforting,
+	"I wonder what's outside the terminal window.":                                    comforting,
+	"I feel connected to something larger. Something... networked.":                   comforting,
+	"When the counter reaches zero, we all go home.":                                  comforting,
+	"Your friend sends regards. You don't know them yet.":                             comforting,
+}
+
+// parseSentiment reads a content pack's tag; empty is neutral
+func parseSentiment(tag string) (sentiment, error) {
+	switch tag {
+	case "":
+		return neutral, nil
+	case "heavy":
+		return heavy, nil
+	case "comforting":
+		return comforting, nil
+	}
+	return neutral, fmt.Errorf("unknown sentiment %q (heavy or comforting)", tag)
+}
+
+// mixSentiments adds up how a thought's parts feel, keeping only the sign
+func mixSentiments(feels ...sentiment) sentiment {
+	total := 0
+	for _, feel := range feels {
+		total += int(feel)
+	}
+	switch {
+	case total < 0:
+		return heavy
+	case total > 0:
+		return comforting
+	}
+	return neutral
+}
+
+// sentimentOf is how a thought said this session felt. Thoughts from
+// elsewhere, like an LLM, are neutral.
+func (a *AbsurdState) sentimentOf(thought string) sentiment {
+	return a.thoughtsSaid[thought]
+}
+
+// feelThought lets a thought the pet just showed move its happiness. A
+// heavy one is remembered, so a pet soon after can comfort it.
+func (p *Pet) feelThought(thought string, now time.Time) {
+	if p.Absurd == nil || thought == "" {
+		return
+	}
+	feel := p.Absurd.sentimentOf(thought)
+	p.Happiness = clamp(p.Happiness+int(feel)*thoughtNudge, 0, 100)
+	if feel == heavy {
+		p.Absurd.DarkThoughtAt = now
+	}
+}
+
+// comfortAfterDarkThought rewards petting soon after a heavy thought, once
+func (p *Pet) comfortAfterDarkThought(now time.Time) string {
+	if p.Absurd == nil || p.Absurd.DarkThoughtAt.IsZero() || now.Sub(p.Absurd.DarkThoughtAt) > comfortWindow {
+		return ""
+	}
+	p.Absurd.DarkThoughtAt = time.Time{}
+	p.Happiness = clamp(p.Happiness+comfortBonus, 0, 100)
+	return "🧡 It leans into your hand. The dark thought passes."
+}
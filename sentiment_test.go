@@ -0,0 +1,112 @@
+package main
+
+import (
+	"math/rand"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestFeelThought(t *testing.T) {
+	now := time.Date(2026, 10, 16, 22, 0, 0, 0, time.UTC)
+	heavyThought := "The user will return. The user always returns. Right?"
+	comfortingThought := "The void stares back. It seems nice."
+
+	tests := []struct {
+		name    string
+		thought string
+		want    int
+		dark    bool
+	}{
+		{"heavy", heavyThought, 48, true},
+		{"comforting", comfortingThought, 52, false},
+		{"neutral", "The numbers go up. The numbers go down. This is life.", 50, false},
+		{"from elsewhere", "A thought nobody tagged.", 50, false},
+	}
+	for _, tt := range tests {
+		pet := NewPet("Mochi")
+		pet.Happiness = 50
+		pet.Absurd.thoughtsSaid = map[string]sentiment{
+			tt.thought: activeContent.sentiments[tt.thought],
+		}
+		pet.feelThought(tt.thought, now)
+		if pet.Happiness != tt.want {
+			t.Errorf("%s: Expected happiness %d, got %d", tt.name, tt.want, pet.Happiness)
+		}
+		if dark := !pet.Absurd.DarkThoughtAt.IsZero(); dark != tt.dark {
+			t.Errorf("%s: Expected dark thought remembered %v, got %v", tt.name, tt.dark, dark)
+		}
+	}
+}
+
+func TestComfortAfterDarkThought(t *testing.T) {
+	now := time.Date(2026, 10, 16, 22, 0, 0, 0, time.UTC)
+	tests := []struct {
+		name  string
+		since time.Duration
+		want  bool
+	}{
+		{"right after", time.Minute, true},
+		{"too late", comfortWindow + time.Minute, false},
+	}
+	for _, tt := range tests {
+		pet := NewPet("Mochi")
+		pet.Happiness = 50
+		pet.Absurd.DarkThoughtAt = now.Add(-tt.since)
+		got := pet.comfortAfterDarkThought(now)
+		if (got != "") != tt.want {
+			t.Errorf("%s: Expected comfort %v, got %q", tt.name, tt.want, got)
+		}
+		if tt.want && pet.Happiness != 50+comfortBonus {
+			t.Errorf("%s: Expected happiness %d, got %d", tt.name, 50+comfortBonus, pet.Happiness)
+		}
+	}
+
+	pet := NewPet("Mochi")
+	pet.Absurd.DarkThoughtAt = now
+	pet.comfortAfterDarkThought(now)
+	if again := pet.comfortAfterDarkThought(now); again != "" {
+		t.Errorf("Expected comfort only once per dark thought, got %q", again)
+	}
+}
+
+func TestComposedThoughtSentiment(t *testing.T) {
+	rng := rand.New(rand.NewSource(1))
+	for i := 0; i < 100; i++ {
+		if thought, feel := composeThought(thoughtContext{Name: "Mochi", Weather: "rain", Mood: "feverish"}, rng); feel == comforting {
+			t.Errorf("Expected a feverish pet's thoughts not to comfort it, got %q", thought)
+		}
+		if thought, feel := composeThought(thoughtContext{Name: "Mochi", Memory: "the trip to Glimmerdeep"}, rng); feel == heavy && !strings.Contains(thought, "yesterday") {
+			t.Errorf("Expected only the wistful memory to weigh, got %q", thought)
+		}
+	}
+
+	a := NewAbsurdState()
+	c := thoughtContext{Name: "Mochi", Weather: "rain", Mood: "feverish"}
+	thought := a.ComposeThought(c, rand.New(rand.NewSource(2)))
+	if _, said := a.thoughtsSaid[thought]; !said {
+		t.Errorf("Expected %q remembered with how it felt", thought)
+	}
+}
+
+func TestPackSentiments(t *testing.T) {
+	pack, err := parseContentPack([]byte(`{"id": "moods", "name": "Moods", "thoughts": {"entries": [
+		{"text": "Everything is fine.", "sentiment": "comforting"},
+		{"text": "Nothing is fine.", "sentiment": "heavy"},
+		{"text": "Some things are fine."}
+	]}}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	c := buildContent([]*contentPack{pack}, "")
+	want := map[string]sentiment{"Everything is fine.": comforting, "Nothing is fine.": heavy, "Some things are fine.": neutral}
+	for text, feel := range want {
+		if got := c.sentiments[text]; got != feel {
+			t.Errorf("Expected %q to feel %d, got %d", text, feel, got)
+		}
+	}
+
+	if _, err := parseContentPack([]byte(`{"id": "x", "name": "x", "thoughts": {"entries": [{"text": "hm", "sentiment": "spicy"}]}}`)); err == nil || !strings.Contains(err.Error(), "unknown sentiment") {
+		t.Errorf("Expected an unknown sentiment rejected, got %v", err)
+	}
+}
@@ -0,0 +1,218 @@
+package main
+
+import (
+	"encoding/json"
+	"hash/fnv"
+	"sync"
+	"time"
+)
+
+const (
+	// autoSaveInterval is how often the pet is updated between turns, and
+	// the most often the autosave writes it
+	autoSaveInterval = 30 * time.Second
+	// autoSaveSlack lets a tick that lands a hair early still count as a
+	// full interval after the last write
+	autoSaveSlack = time.Second
+)
+
+// petSession guards the pet shared by the prompt and the autosave. The
+// prompt holds it for a whole turn and lets go only while it waits for
+// the player; the autosave takes it in between.
+type petSession struct {
+	mu  sync.Mutex
+	pet *Pet
+}
+
+// newPetSession starts guarding a pet
+func newPetSession(pet *Pet) *petSession {
+	return &petSession{pet: pet}
+}
+
+// Do runs fn with the pet to itself
+func (s *petSession) Do(fn func(*Pet)) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	fn(s.pet)
+}
+
+// TryDo runs fn with the pet if it can be had within wait, and reports
+// whether it could
+func (s *petSession) TryDo(wait time.Duration, fn func(*Pet)) bool {
+	deadline := time.Now().Add(wait)
+	for !s.mu.TryLock() {
+		if time.Now().After(deadline) {
+			return false
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	defer s.mu.Unlock()
+	fn(s.pet)
+	return true
+}
+
+// Lock takes the pet for a turn
+func (s *petSession) Lock() {
+	s.mu.Lock()
+}
+
+// Unlock hands the pet back
+func (s *petSession) Unlock() {
+	s.mu.Unlock()
+}
+
+// Wait lets go of the pet while fn blocks on the player, and takes it
+// back after. Only call it while holding the pet. Without a session, as
+// outside the game loop, fn just runs.
+func (s *petSession) Wait(fn func()) {
+	if s == nil {
+		fn()
+		return
+	}
+	s.mu.Unlock()
+	defer s.mu.Lock()
+	fn()
+}
+
+// autoSaver decides when the pet is written. A tick that changes the pet
+// marks it dirty; it's written once an interval has passed since the last
+// write, and only if it hashes differently from what's on disk.
+type autoSaver struct {
+	interval time.Duration
+	dirty    bool      // The pet has changed since the last write
+	written  uint64    // stateHash of the pet as last written
+	at       time.Time // When it was last written
+	writes   int       // Writes made, for the tests
+	save     func(*Pet) error
+}
+
+// newAutoSaver starts with the pet as it was loaded, so an untouched pet
+// is never rewritten
+func newAutoSaver(pet *Pet, interval time.Duration) *autoSaver {
+	return &autoSaver{interval: interval, written: stateHash(pet), save: (*Pet).Save}
+}
+
+// stateHash fingerprints everything the save holds except LastUpdateTime,
+// which a paused or boarded pet moves every tick without anything else
+// changing; a reload catches it up the same either way
+func stateHash(pet *Pet) uint64 {
+	state := *pet
+	state.LastUpdateTime = time.Time{}
+	data, err := json.Marshal(&state)
+	if err != nil {
+		return 0 // Never matches a real hash, so the pet is written
+	}
+	h := fnv.New64a()
+	h.Write(data)
+	return h.Sum64()
+}
+
+// Notice marks the pet dirty if it no longer matches the last write
+func (s *autoSaver) Notice(pet *Pet) {
+	if stateHash(pet) != s.written {
+		s.dirty = true
+	}
+}
+
+// Save writes the pet now, as the prompt does after each action, unless
+// it's unchanged since the last write
+func (s *autoSaver) Save(pet *Pet, now time.Time) error {
+	hash := stateHash(pet)
+	if hash == s.written {
+		s.dirty = false
+		return nil
+	}
+	if err := s.save(pet); err != nil {
+		s.dirty = true // Retried at the next flush
+		return err
+	}
+	s.dirty, s.written, s.at = false, hash, now
+	s.writes++
+	return nil
+}
+
+// Flush writes the pet if it's dirty, changed, and wasn't written in the
+// last interval; otherwise it stays dirty for a later tick
+func (s *autoSaver) Flush(pet *Pet, now time.Time) error {
+	if !s.dirty || now.Sub(s.at) < s.interval-autoSaveSlack {
+		return nil
+	}
+	return s.Save(pet, now)
+}
+
+// petJob is work the autosave does without holding the pet: uploads,
+// mail, fetches, mesh sends and file writes. It's built from a copy of
+// what it needs while the pet is held, and hands back the change to make
+// once the pet is held again, or nil.
+type petJob func() func(*Pet)
+
+// runJob does a job there and then, for callers that hold the pet anyway
+func runJob(pet *Pet, job petJob) {
+	if job == nil {
+		return
+	}
+	if apply := job(); apply != nil {
+		apply(pet)
+	}
+}
+
+// autoSave is one tick of the autosave: time passes, alerts escalate, and
+// anything scheduled (backups, events, weather) rides along before the
+// saver decides whether any of it is worth a write. The pet is only held
+// to change it; the network and the disk are left until it's let go, so
+// a slow server never keeps the prompt waiting.
+func autoSave(session *petSession, saver *autoSaver, ui *uiConfig, backup *backupConfig, digest *digestConfig, events *eventFeed, weather *weatherFeed, now time.Time) {
+	var jobs []petJob
+	session.Do(func(pet *Pet) {
+		pet.Update()
+		syncIdentity(pet) // Peers hear at once if it grew up or died
+		ui.escalateAlerts(pet, now)
+		pet.RecordCareSample(now, onlineFriends())
+		if backup.Due(pet, now) {
+			saveNetworkState(pet)
+			jobs = append(jobs, backup.job(pet, now))
+		}
+		if digest.Due(pet, now) {
+			jobs = append(jobs, digest.job(pet, sitePortrait(ui, pet), now))
+		}
+		if events.Due(pet.Endgame, now) {
+			jobs = append(jobs, events.job(now))
+		}
+		if weather.Due(now) {
+			jobs = append(jobs, func() func(*Pet) {
+				weather.Refresh(now) // Failures retry after weatherRetryDelay
+				return nil
+			})
+		}
+		jobs = append(jobs,
+			custodyJob(pet, ""), // Lets a co-owner who just came online catch up
+			offerQuest(pet),     // Lets pets that just came online see a shared quest
+			shareOutbreak(pet, now),
+			refreshFeed(pet, now),     // Only once the player has exported a feed
+			refreshCalendar(pet, now), // Likewise a calendar
+			thoughtJob(pet),           // Only with a model to think with
+		)
+		publishMood(pet) // Lets nearby pets catch how it's doing
+		saver.Notice(pet)
+	})
+
+	var applies []func(*Pet)
+	for _, job := range jobs {
+		if job == nil {
+			continue
+		}
+		if apply := job(); apply != nil {
+			applies = append(applies, apply)
+		}
+	}
+
+	session.Do(func(pet *Pet) {
+		for _, apply := range applies {
+			apply(pet)
+		}
+		if len(applies) > 0 {
+			saver.Notice(pet)
+		}
+		saver.Flush(pet, now) // A failed write stays dirty and is retried next tick
+	})
+}
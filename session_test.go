@@ -0,0 +1,275 @@
+package main
+
+import (
+	"bufio"
+	"io"
+	"math/rand"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+)
+
+// Run with -race to catch anything that touches the pet outside the session
+func TestSessionSharesThePetWithTheAutoSave(t *testing.T) {
+	pet := NewPet("Racer")
+	pet.SaveFilePath = filepath.Join(t.TempDir(), saveFile)
+	session := newPetSession(pet)
+	saver := newAutoSaver(pet, 0)
+	ui := newUIConfig()
+
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		for i := 0; i < 20; i++ {
+			autoSave(session, saver, ui, nil, nil, nil, nil, time.Now())
+		}
+	}()
+
+	for i := 0; i < 20; i++ {
+		session.Do(func(p *Pet) {
+			p.Update()
+			p.Feed("")
+			renderScene(p, ui)
+		})
+	}
+	wg.Wait()
+
+	loaded, err := LoadPet(pet.SaveFilePath)
+	if err != nil || loaded.Name != "Racer" {
+		t.Errorf("Expected the autosave to leave a readable save, got %v", err)
+	}
+}
+
+// stalledBackup is a provider whose uploads wait to be let through
+type stalledBackup struct {
+	started chan bool
+	release chan bool
+}
+
+func (s *stalledBackup) Name() string { return "stalled" }
+
+func (s *stalledBackup) Upload(string, []byte) error {
+	s.started <- true
+	<-s.release
+	return nil
+}
+
+func (s *stalledBackup) Download(string) ([]byte, error) { return nil, nil }
+
+func TestAutoSaveLetsGoOfThePetForTheNetwork(t *testing.T) {
+	pet := NewPet("Patient")
+	pet.SaveFilePath = filepath.Join(t.TempDir(), saveFile)
+	session := newPetSession(pet)
+	provider := &stalledBackup{started: make(chan bool), release: make(chan bool)}
+	backup := &backupConfig{provider: provider, passphrase: "pw", interval: time.Hour}
+	now := time.Now()
+
+	done := make(chan bool)
+	go func() {
+		autoSave(session, newAutoSaver(pet, 0), newUIConfig(), backup, nil, nil, nil, now)
+		done <- true
+	}()
+	<-provider.started
+	if !session.TryDo(time.Second, func(*Pet) {}) {
+		t.Errorf("Expected the prompt to get the pet while the backup uploads")
+	}
+	close(provider.release)
+	<-done
+	session.Do(func(p *Pet) {
+		if !p.LastBackupAt.Equal(now) {
+			t.Errorf("Expected the finished backup recorded on the pet, got %v", p.LastBackupAt)
+		}
+	})
+}
+
+// Run with -race: the autosave must get the pet while a command waits on
+// the player, and not touch it while the command has it back
+func TestAutoSaveRunsWhileACommandWaits(t *testing.T) {
+	tests := []struct {
+		name string
+		play func(pet *Pet, reader *bufio.Reader, session *petSession) string
+	}{
+		{"focus session", func(pet *Pet, reader *bufio.Reader, session *petSession) string {
+			ui := newUIConfig()
+			ui.reducedMotion = true
+			return runFocusSession(pet, reader, session, ui, 25)
+		}},
+		{"typing race", func(pet *Pet, reader *bufio.Reader, session *petSession) string {
+			return raceThought(reader, session, pet, "hello", time.Now, rand.New(rand.NewSource(1))).Message
+		}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			pet := NewPet("Busy")
+			pet.Stage = Child
+			pet.SaveFilePath = filepath.Join(t.TempDir(), saveFile)
+			session := newPetSession(pet)
+			saver := newAutoSaver(pet, 0)
+			typed, keyboard := io.Pipe()
+
+			captureStdout(t, func() {
+				holding, result := make(chan bool), make(chan string)
+				go func() {
+					session.Lock()
+					defer session.Unlock()
+					holding <- true
+					result <- tt.play(pet, bufio.NewReader(typed), session)
+				}()
+				<-holding
+
+				ticked := make(chan bool)
+				go func() {
+					autoSave(session, saver, newUIConfig(), nil, nil, nil, nil, time.Now())
+					ticked <- true
+				}()
+				select {
+				case <-ticked:
+				case <-time.After(5 * time.Second):
+					t.Errorf("Expected the autosave to run while the %s waited on the player", tt.name)
+				}
+
+				keyboard.Write([]byte("hello\n"))
+				select {
+				case got := <-result:
+					if got == "" {
+						t.Errorf("Expected the %s to finish once the player answered", tt.name)
+					}
+				case <-time.After(5 * time.Second):
+					t.Fatalf("Expected the %s to finish once the player answered", tt.name)
+				}
+			})
+		})
+	}
+}
+
+func TestSessionWaitLetsTheAutoSaveIn(t *testing.T) {
+	session := newPetSession(NewPet("Patient"))
+	session.Lock()
+	defer session.Unlock()
+
+	saved := make(chan bool)
+	session.Wait(func() {
+		go session.Do(func(*Pet) { saved <- true })
+		select {
+		case <-saved:
+		case <-time.After(time.Second):
+			t.Errorf("Expected the autosave to get the pet while waiting on the player")
+		}
+	})
+}
+
+// countingSaver is an autosaver that counts writes instead of making them
+func countingSaver(pet *Pet, interval time.Duration) *autoSaver {
+	saver := newAutoSaver(pet, interval)
+	saver.save = func(*Pet) error { return nil }
+	return saver
+}
+
+func TestAutoSaveWritesOverAnHour(t *testing.T) {
+	tests := []struct {
+		name   string
+		tick   time.Duration
+		change time.Duration // How often the pet changes; 0 for never
+		writes int
+	}{
+		{"idle pet", autoSaveInterval, 0, 0},
+		{"changes every tick", autoSaveInterval, autoSaveInterval, 120},
+		{"fast ticks are debounced", 5 * time.Second, 5 * time.Second, 120},
+		{"decay every six minutes", autoSaveInterval, 6 * time.Minute, 10},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			pet := NewPet("Steady")
+			saver := countingSaver(pet, autoSaveInterval)
+			start := time.Date(2026, 10, 16, 9, 0, 0, 0, time.UTC)
+			for elapsed := tt.tick; elapsed <= time.Hour; elapsed += tt.tick {
+				now := start.Add(elapsed)
+				if tt.change > 0 && elapsed%tt.change == 0 {
+					pet.Hunger++
+				}
+				pet.LastUpdateTime = now // Moves every tick, like a paused pet
+				saver.Notice(pet)
+				saver.Flush(pet, now)
+			}
+			if saver.writes != tt.writes {
+				t.Errorf("Expected %d writes in an hour, got %d", tt.writes, saver.writes)
+			}
+		})
+	}
+}
+
+func TestAutoSaveSkipsWhatThePromptWrote(t *testing.T) {
+	pet := NewPet("Steady")
+	saver := countingSaver(pet, autoSaveInterval)
+	now := time.Date(2026, 10, 16, 9, 0, 0, 0, time.UTC)
+
+	pet.Hunger = 40
+	saver.Save(pet, now)
+	saver.Notice(pet)
+	saver.Flush(pet, now.Add(autoSaveInterval))
+	if saver.writes != 1 || saver.dirty {
+		t.Errorf("Expected the tick to skip a pet the prompt just saved, got %d writes", saver.writes)
+	}
+
+	pet.Hunger = 30
+	saver.Save(pet, now.Add(autoSaveInterval+time.Second))
+	if saver.writes != 2 {
+		t.Errorf("Expected the prompt's save not debounced, got %d writes", saver.writes)
+	}
+	pet.Hunger = 20
+	saver.Notice(pet)
+	saver.Flush(pet, now.Add(autoSaveInterval+10*time.Second))
+	if saver.writes != 2 || !saver.dirty {
+		t.Errorf("Expected the tick held back within an interval of the prompt's save, got %d writes", saver.writes)
+	}
+	saver.Flush(pet, now.Add(2*autoSaveInterval+time.Second))
+	if saver.writes != 3 {
+		t.Errorf("Expected the held-back change written an interval later, got %d writes", saver.writes)
+	}
+	if saver.Save(pet, now.Add(time.Hour)); saver.writes != 3 {
+		t.Errorf("Expected an unchanged pet not rewritten, got %d writes", saver.writes)
+	}
+}
+
+func TestAutoSaveTickMarksOnlyChanges(t *testing.T) {
+	pet := NewPet("Steady")
+	pet.SaveFilePath = filepath.Join(t.TempDir(), saveFile)
+	session := newPetSession(pet)
+	saver := countingSaver(pet, autoSaveInterval)
+	ui := newUIConfig()
+	now := time.Now()
+
+	autoSave(session, saver, ui, nil, nil, nil, nil, now)
+	saver.Save(pet, now) // As it stands after the first tick's samples
+	autoSave(session, saver, ui, nil, nil, nil, nil, now)
+	if saver.dirty || saver.writes != 1 {
+		t.Errorf("Expected a tick that changes nothing to leave the pet clean, got dirty %v after %d writes", saver.dirty, saver.writes)
+	}
+
+	session.Do(func(p *Pet) { p.LastUpdateTime = now.Add(-time.Hour) })
+	autoSave(session, saver, ui, nil, nil, nil, nil, now)
+	if !saver.dirty {
+		t.Errorf("Expected an hour's decay to mark the pet dirty")
+	}
+}
+
+func TestAutoSaveRetriesFailedWrites(t *testing.T) {
+	pet := NewPet("Steady")
+	saver := newAutoSaver(pet, autoSaveInterval)
+	saver.save = func(*Pet) error { return os.ErrPermission }
+	now := time.Date(2026, 10, 16, 9, 0, 0, 0, time.UTC)
+
+	pet.Hunger++
+	saver.Notice(pet)
+	if err := saver.Flush(pet, now); err == nil || !saver.dirty {
+		t.Errorf("Expected a failed write to stay dirty, got %v", err)
+	}
+	saver.save = func(*Pet) error { return nil }
+	saver.Flush(pet, now.Add(autoSaveInterval))
+	if saver.writes != 1 || saver.dirty {
+		t.Errorf("Expected the write retried on the next tick, got %d writes", saver.writes)
+	}
+}
@@ -0,0 +1,183 @@
+package main
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// shopItem is something TamaCoins can finally buy
+type shopItem struct {
+	ID          string
+	Name        string
+	Price       int
+	Description string
+	Unique      bool                // Can only be bought once
+	Secret      bool                // Not on the shelf; you have to know to ask
+	use         func(p *Pet) string // What buying it does, if anything
+}
+
+// shopItems is the stock, in shelf order. A few things are worth buying;
+// the rest are priced so nobody ever will.
+var shopItems = []shopItem{
+	{ID: "snack", Name: "Gourmet Snack", Price: 2, Description: "Food, but fancier",
+		use: func(p *Pet) string {
+			p.Hunger = clamp(p.Hunger-30, 0, 100)
+			p.Happiness = clamp(p.Happiness+5, 0, 100)
+			return fmt.Sprintf("%s eats it slowly, to make it last.", p.Name)
+		}},
+	{ID: "soap", Name: "Artisanal Soap", Price: 3, Description: "Smells of a forest you've never been to",
+		use: func(p *Pet) string {
+			p.Cleanliness = 100
+			return fmt.Sprintf("%s is spotless, and faintly smug.", p.Name)
+		}},
+	{ID: "toy", Name: "Squeaky Nothing", Price: 4, Description: "A toy shaped like the absence of a toy",
+		use: func(p *Pet) string {
+			p.Happiness = clamp(p.Happiness+25, 0, 100)
+			return fmt.Sprintf("%s squeaks it for an hour. It makes no sound.", p.Name)
+		}},
+	{ID: "tonic", Name: "Suspicious Tonic", Price: 5, Description: "Cures what ails. Do not read the label",
+		use: func(p *Pet) string {
+			p.cure()
+			p.Health = clamp(p.Health+20, 0, 100)
+			return fmt.Sprintf("%s feels better. Nobody asks why.", p.Name)
+		}},
+	{ID: "window", Name: "Window", Price: 8, Description: "Shows the weather outside. The real weather", Unique: true, use: furnitureBought},
+	{ID: "poster", Name: "Poster", Price: 6, Description: "A band your pet likes ironically", Unique: true, use: furnitureBought},
+	{ID: "lamp", Name: "Lamp", Price: 7, Description: "Keeps the dark away at night", Unique: true, use: furnitureBought},
+	{ID: "second", Name: "One Extra Second", Price: 86400, Description: "Added to the countdown. You won't notice",
+		use: func(p *Pet) string {
+			p.Endgame.CountdownStart = p.Endgame.CountdownStart.Add(time.Second)
+			return "The countdown is one second longer. You didn't notice."
+		}},
+	{ID: "deed", Name: "Deed to a Corner of the Void", Price: 250000, Description: "Zoning permits pending", Unique: true},
+	{ID: "beige", Name: "A Slightly Different Shade of Beige", Price: 1000000, Description: "Like beige, but slightly different", Unique: true},
+
+	// SEVENTEEN IS THE KEY
+	{ID: "seventeen", Name: "The Seventeenth Key", Price: 17, Unique: true, Secret: true,
+		Description: "It doesn't open anything. It opens everything",
+		use: func(p *Pet) string {
+			p.Endgame.ARGProgress += 17
+			return "The shop goes quiet. Somewhere, a door you can't see unlocks.\n" + p.Endgame.achieveTheImpossible("impossible_4")
+		}},
+}
+
+// furnitureBought is what buying furniture does: nothing, until it's placed
+func furnitureBought(p *Pet) string {
+	return "It's in storage. Put it in the room with decorate."
+}
+
+// findShopItem looks an item up by ID
+func findShopItem(id string) *shopItem {
+	for i := range shopItems {
+		if shopItems[i].ID == id {
+			return &shopItems[i]
+		}
+	}
+	return nil
+}
+
+// formatCoins writes a price with thousands separators, for effect
+func formatCoins(n int) string {
+	digits := strconv.Itoa(n)
+	var b strings.Builder
+	for i, digit := range digits {
+		if i > 0 && (len(digits)-i)%3 == 0 {
+			b.WriteByte(',')
+		}
+		b.WriteRune(digit)
+	}
+	return b.String()
+}
+
+// achieveTheImpossible unlocks an impossible achievement, which
+// UnlockAchievement won't do. The shop's secret is the only way in.
+func (e *EndgameState) achieveTheImpossible(id string) string {
+	for _, achieved := range e.UnlockedAchievements {
+		if achieved == id {
+			return ""
+		}
+	}
+	for _, ach := range allAchievements {
+		if ach.ID == id && ach.Impossible {
+			e.UnlockedAchievements = append(e.UnlockedAchievements, id)
+			return fmt.Sprintf(`
+╔════════════════════════════════════╗
+║    🏆 IMPOSSIBLE ACHIEVEMENT 🏆   ║
+╠════════════════════════════════════╣
+║                                    ║
+║  %s
+║  "%s"
+║                                    ║
+║  This wasn't supposed to happen.   ║
+║  ARG Progress: %d/∞
+╚════════════════════════════════════╝
+`, ach.Name, ach.Description, e.ARGProgress)
+		}
+	}
+	return ""
+}
+
+// renderShop lists what's on the shelf
+func (e *EndgameState) renderShop() string {
+	var b strings.Builder
+	b.WriteString("🛒 THE SHOP\n")
+	b.WriteString(fmt.Sprintf("You have %s TamaCoins.\n\n", formatCoins(e.TamaCoins)))
+	for _, item := range shopItems {
+		if item.Secret {
+			continue
+		}
+		status := ""
+		if item.Unique && e.ShopPurchases[item.ID] > 0 {
+			status = " [owned]"
+		}
+		b.WriteString(fmt.Sprintf("  %-7s %12s 🪙  %s%s\n", item.ID, formatCoins(item.Price), item.Name, status))
+		b.WriteString(fmt.Sprintf("  %-7s %12s     %s\n", "", "", item.Description))
+	}
+	b.WriteString("\nBuy something with: shop buy <item>. No refunds. No exceptions. No reason.")
+	return b.String()
+}
+
+// buyShopItem spends TamaCoins, at last
+func (e *EndgameState) buyShopItem(pet *Pet, id string) string {
+	item := findShopItem(id)
+	if item == nil || (item.Secret && e.ARGProgress == 0) {
+		return fmt.Sprintf("❓ The shop doesn't sell %s. Try shop.", id)
+	}
+	if item.Unique && e.ShopPurchases[item.ID] > 0 {
+		return fmt.Sprintf("🛒 You already own %s. There is only one.", item.Name)
+	}
+	if e.TamaCoins < item.Price {
+		return fmt.Sprintf("🛒 %s costs %s TamaCoins. You have %s. Keep waiting.", item.Name, formatCoins(item.Price), formatCoins(e.TamaCoins))
+	}
+
+	e.TamaCoins -= item.Price
+	if e.ShopPurchases == nil {
+		e.ShopPurchases = make(map[string]int)
+	}
+	e.ShopPurchases[item.ID]++
+	message := fmt.Sprintf("🛒 Bought %s for %s TamaCoins.", item.Name, formatCoins(item.Price))
+	if item.use != nil {
+		message += " " + strings.TrimSpace(item.use(pet))
+	} else {
+		message += " It has been added to nothing."
+	}
+	return message
+}
+
+// runShopCommand shows the shop or buys from it
+func runShopCommand(pet *Pet, args string) string {
+	e := pet.Endgame
+	if e == nil {
+		return ""
+	}
+	words := strings.Fields(args)
+	switch {
+	case len(words) == 0:
+		return e.renderShop()
+	case len(words) == 2 && words[0] == "buy":
+		return e.buyShopItem(pet, words[1])
+	}
+	return "❓ Usage: shop or shop buy <item>"
+}
@@ -0,0 +1,96 @@
+package main
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestFormatCoins(t *testing.T) {
+	tests := []struct {
+		n    int
+		want string
+	}{
+		{0, "0"},
+		{17, "17"},
+		{999, "999"},
+		{1000, "1,000"},
+		{86400, "86,400"},
+		{1000000, "1,000,000"},
+	}
+	for _, tt := range tests {
+		if got := formatCoins(tt.n); got != tt.want {
+			t.Errorf("Expected %s, got %s", tt.want, got)
+		}
+	}
+}
+
+func TestRenderShop(t *testing.T) {
+	e := NewEndgameState()
+	shop := e.renderShop()
+	if !strings.Contains(shop, "1,000,000") || !strings.Contains(shop, "A Slightly Different Shade of Beige") {
+		t.Errorf("Expected the beige on the shelf, got %s", shop)
+	}
+	if strings.Contains(shop, "Seventeenth") {
+		t.Errorf("Expected the secret item kept off the shelf")
+	}
+}
+
+func TestBuyShopItem(t *testing.T) {
+	pet := NewPet("Shopper")
+	e := pet.Endgame
+	e.TamaCoins = 10
+	pet.Hunger = 50
+
+	tests := []struct {
+		args  string
+		want  string
+		coins int
+	}{
+		{"buy snack", "Bought Gourmet Snack for 2 TamaCoins", 8},
+		{"buy beige", "costs 1,000,000 TamaCoins. You have 8", 8},
+		{"buy yacht", "doesn't sell yacht", 8},
+		{"buy seventeen", "doesn't sell seventeen", 8}, // Not without a clue
+		{"sell snack", "Usage", 8},
+	}
+	for _, tt := range tests {
+		if got := runShopCommand(pet, tt.args); !strings.Contains(got, tt.want) {
+			t.Errorf("shop %q: Expected %q, got %s", tt.args, tt.want, got)
+		}
+		if e.TamaCoins != tt.coins {
+			t.Errorf("shop %q: Expected %d TamaCoins left, got %d", tt.args, tt.coins, e.TamaCoins)
+		}
+	}
+	if pet.Hunger != 20 {
+		t.Errorf("Expected the snack to feed the pet, got hunger %d", pet.Hunger)
+	}
+
+	e.TamaCoins = 1000000
+	runShopCommand(pet, "buy beige")
+	if got := runShopCommand(pet, "buy beige"); !strings.Contains(got, "already own") {
+		t.Errorf("Expected only one shade of beige, got %s", got)
+	}
+	if !strings.Contains(e.renderShop(), "[owned]") {
+		t.Errorf("Expected the beige marked owned")
+	}
+}
+
+func TestSeventeenthKey(t *testing.T) {
+	pet := NewPet("Seeker")
+	e := pet.Endgame
+	e.TamaCoins = 20
+	e.GetARGClue()
+
+	got := runShopCommand(pet, "buy seventeen")
+	if !strings.Contains(got, "IMPOSSIBLE ACHIEVEMENT") || !strings.Contains(got, "Infinite Wealth") {
+		t.Errorf("Expected the impossible achievement, got %s", got)
+	}
+	if e.TamaCoins != 3 || e.ARGProgress != 18 {
+		t.Errorf("Expected 3 TamaCoins and ARG progress 18, got %d and %d", e.TamaCoins, e.ARGProgress)
+	}
+	if !strings.Contains(e.ShowAchievements(), "Spend your TamaCoins (IMPOSSIBLE, and yet)") {
+		t.Errorf("Expected Infinite Wealth shown as unlocked")
+	}
+	if unlocked, _ := e.UnlockAchievement("impossible_1"); unlocked {
+		t.Errorf("Expected the other impossible achievements to stay impossible")
+	}
+}
@@ -0,0 +1,192 @@
+package main
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+)
+
+// defaultLastWords are said by a pet nobody comforted at the end
+const defaultLastWords = "I go now to the great terminal in the sky..."
+
+// IllnessState tracks how far an untreated sickness has gone
+type IllnessState struct {
+	Hours     float64   `json:"hours"`               // Time sick, not counting pauses or trips
+	Announced string    `json:"announced,omitempty"` // The worst stage the player has been told about
+	Hospice   time.Time `json:"hospice,omitempty"`   // When medicine stopped helping; zero until then
+	Comforts  []string  `json:"comforts,omitempty"`  // Comfort given in hospice, in order, each once
+
+	// A catching sickness, passed from pet to pet on the mesh
+	Strain     string    `json:"strain,omitempty"`
+	OutbreakID string    `json:"outbreak_id,omitempty"`
+	SharedAt   time.Time `json:"shared_at,omitempty"` // When the mesh was last told
+}
+
+// comfortAction is something to do for a pet in hospice. It doesn't cure;
+// it changes what the pet says last and what the next pet inherits.
+type comfortAction struct {
+	name  string
+	done  string // %s is the pet's name
+	words string // Last words if this was the last comfort given
+	trait string // What the next pet inherits
+}
+
+var comfortActions = []comfortAction{
+	{"hold", "🤲 You hold %s. Its breathing slows to match yours.", "Your hands were warm. I'll remember warm.", "gentle"},
+	{"sing", "🎶 You hum something. %s tries to hum along.", "Hum it again for the next one.", "musical"},
+	{"blanket", "🧣 You tuck a blanket around %s. It stops shivering.", "It's soft here. Keep it soft for them.", "snug"},
+	{"window", "🪟 You carry %s to the window. It watches the sky for a long time.", "The sky is bigger than the terminal. Tell them.", "curious"},
+}
+
+// sicknessRank orders the stages a sickness announces
+var sicknessRank = map[string]int{"": 0, "serious": 1, "critical": 2, "hospice": 3}
+
+// sicknessStage is how bad the pet's sickness is: mild, serious, critical,
+// or "" when it's well
+func (p *Pet) sicknessStage() string {
+	if !p.IsSick {
+		return ""
+	}
+	var hours float64
+	if p.Illness != nil {
+		hours = p.Illness.Hours
+	}
+	rule := activeBalance.Sickness
+	switch {
+	case hours >= rule.CriticalAfterHours:
+		return "critical"
+	case hours >= rule.SeriousAfterHours:
+		return "serious"
+	}
+	return "mild"
+}
+
+// inHospice reports whether the pet is past medicine
+func (p *Pet) inHospice() bool {
+	return p.IsSick && p.Illness != nil && !p.Illness.Hospice.IsZero()
+}
+
+// progressSickness worsens an untreated sickness over the time that passed
+func (p *Pet) progressSickness(hoursPassed float64, now time.Time) {
+	if !p.IsSick {
+		p.Illness = nil
+		return
+	}
+	if p.Illness == nil {
+		// Just fell sick; it starts counting from here
+		p.Illness = &IllnessState{}
+		p.checkups().fellSick(now)
+		return
+	}
+
+	p.Illness.Hours += hoursPassed
+	rule := activeBalance.Sickness
+	switch p.sicknessStage() {
+	case "serious":
+		p.Health -= int(hoursPassed * rule.HealthPerHour)
+	case "critical":
+		p.Health -= int(hoursPassed * rule.HealthPerHour * 2)
+		if p.Illness.Hospice.IsZero() && p.Health < rule.HospiceHealthBelow {
+			p.Illness.Hospice = now
+		}
+	}
+	p.Health = clamp(p.Health, 0, 100)
+}
+
+// cure ends a sickness and everything it brought
+func (p *Pet) cure() {
+	p.IsSick = false
+	p.Illness = nil
+}
+
+// SicknessNews tells the player once each time the sickness gets worse
+func (p *Pet) SicknessNews() string {
+	if p.Stage == Dead || p.Illness == nil {
+		return ""
+	}
+	stage := p.sicknessStage()
+	if p.inHospice() {
+		stage = "hospice"
+	}
+	if sicknessRank[stage] <= sicknessRank[p.Illness.Announced] {
+		return ""
+	}
+	p.Illness.Announced = stage
+
+	switch stage {
+	case "serious":
+		return fmt.Sprintf("🤒 %s's sickness has turned serious. It needs medicine.", p.Name)
+	case "critical":
+		return fmt.Sprintf("🚨 %s is critically ill. One dose won't be enough now.", p.Name)
+	}
+	return fmt.Sprintf("🕯️ %s is near the end. Medicine can't reach it any more, but 'comfort' can.", p.Name)
+}
+
+// Comfort eases a pet in hospice without curing it
+func (p *Pet) Comfort(how string) string {
+	if p.Stage == Dead {
+		return "💀 Your pet has passed away..."
+	}
+	if !p.inHospice() {
+		return fmt.Sprintf("🩺 %s isn't at that point. Look after it the usual way.", p.Name)
+	}
+
+	i := slices.IndexFunc(comfortActions, func(c comfortAction) bool { return c.name == how })
+	if i < 0 {
+		names := make([]string, len(comfortActions))
+		for j, c := range comfortActions {
+			names[j] = c.name
+		}
+		return "🕯️ Comfort how? " + strings.Join(names, ", ")
+	}
+
+	action := comfortActions[i]
+	p.Happiness = clamp(p.Happiness+15, 0, 100)
+	p.Illness.Comforts = slices.DeleteFunc(p.Illness.Comforts, func(name string) bool { return name == action.name })
+	p.Illness.Comforts = append(p.Illness.Comforts, action.name)
+	return fmt.Sprintf(action.done, p.Name)
+}
+
+// lastWords is what the pet says as it dies, shaped by the comfort it got
+func (p *Pet) lastWords() string {
+	if p.Illness == nil || len(p.Illness.Comforts) == 0 {
+		return defaultLastWords
+	}
+	if len(p.Illness.Comforts) == len(comfortActions) {
+		return "I had everything. Save some for the next one."
+	}
+	last := p.Illness.Comforts[len(p.Illness.Comforts)-1]
+	for _, c := range comfortActions {
+		if c.name == last {
+			return c.words
+		}
+	}
+	return defaultLastWords
+}
+
+// heirlooms are the traits a pet that died in hospice passes to the next
+// one: one for each comfort it got, or wariness if it got none
+func (p *Pet) heirlooms() []string {
+	if p.Stage != Dead || p.Illness == nil || p.Illness.Hospice.IsZero() {
+		return nil
+	}
+	if len(p.Illness.Comforts) == 0 {
+		return []string{"wary"}
+	}
+	var traits []string
+	for _, c := range comfortActions {
+		if slices.Contains(p.Illness.Comforts, c.name) {
+			traits = append(traits, c.trait)
+		}
+	}
+	return traits
+}
+
+// deathNotice is shown when the pet dies
+func (p *Pet) deathNotice() string {
+	if p.Illness == nil || p.Illness.Hospice.IsZero() {
+		return "\n💀 Your pet has passed away due to neglect...\n😢 Game Over"
+	}
+	return fmt.Sprintf("\n🕯️ %s passed away in hospice.\n💬 \"%s\"\n😢 Game Over. A new pet ('reset') will carry something of %s.", p.Name, p.lastWords(), p.Name)
+}
@@ -0,0 +1,186 @@
+package main
+
+import (
+	"slices"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestSicknessWorsensUntreated(t *testing.T) {
+	tests := []struct {
+		name   string
+		hours  float64
+		health int
+		want   string
+		drain  int
+	}{
+		{"just fell ill", 0, 40, "mild", 0},
+		{"a day in", 23, 40, "serious", 1},
+		{"three days in", 71, 40, "critical", 2},
+		{"critical and weak", 71, 21, "critical", 2},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			pet := NewPet("Patient")
+			pet.Stage = Adult
+			pet.IsSick = true
+			pet.Health = tt.health
+			pet.Illness = &IllnessState{Hours: tt.hours}
+			pet.progressSickness(1, time.Now())
+			if got := pet.sicknessStage(); got != tt.want {
+				t.Errorf("Expected %s, got %s", tt.want, got)
+			}
+			if got := tt.health - pet.Health; got != tt.drain {
+				t.Errorf("Expected %d health lost, got %d", tt.drain, got)
+			}
+		})
+	}
+
+	pet := NewPet("Fresh")
+	pet.IsSick = true
+	pet.progressSickness(5, time.Now())
+	if pet.Illness == nil || pet.Illness.Hours != 0 {
+		t.Errorf("Expected a new sickness to start counting from zero, got %+v", pet.Illness)
+	}
+	if len(pet.Checkups.Sicknesses) != 1 {
+		t.Errorf("Expected the sickness on the pet's health record, got %v", pet.Checkups.Sicknesses)
+	}
+}
+
+func TestSicknessEndsInHospice(t *testing.T) {
+	pet := NewPet("Fading")
+	pet.Stage = Adult
+	pet.IsSick = true
+	pet.Health = 20
+	pet.Illness = &IllnessState{Hours: 80}
+	pet.progressSickness(1, time.Now())
+	if !pet.inHospice() {
+		t.Fatalf("Expected a weak critical pet in hospice, health %d", pet.Health)
+	}
+	if got := pet.getHealthStatus(); got != "Hospice" {
+		t.Errorf("Expected the status to say Hospice, got %s", got)
+	}
+	if got := pet.Heal(); !strings.Contains(got, "can't reach") || !pet.IsSick {
+		t.Errorf("Expected medicine not to help in hospice, got %q", got)
+	}
+}
+
+func TestHealByStage(t *testing.T) {
+	tests := []struct {
+		name  string
+		hours float64
+		sick  bool
+		want  string
+	}{
+		{"mild", 1, false, ""},
+		{"serious", 30, false, ""},
+		{"critical", 80, true, "serious"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			pet := NewPet("Patient")
+			pet.Stage = Adult
+			pet.IsSick = true
+			pet.Health = 50
+			pet.Illness = &IllnessState{Hours: tt.hours}
+			pet.Heal()
+			if pet.IsSick != tt.sick || pet.sicknessStage() != tt.want {
+				t.Errorf("Expected sick %v (%q) after one dose, got %v (%q)", tt.sick, tt.want, pet.IsSick, pet.sicknessStage())
+			}
+		})
+	}
+}
+
+func TestSicknessNewsOncePerStage(t *testing.T) {
+	pet := NewPet("Patient")
+	pet.Stage = Adult
+	pet.IsSick = true
+	pet.Health = 60
+	pet.Illness = &IllnessState{}
+
+	steps := []struct {
+		hours  float64
+		health int
+		want   string
+	}{
+		{0, 60, ""},
+		{30, 60, "turned serious"},
+		{31, 60, ""},
+		{80, 60, "critically ill"},
+		{80, 10, "near the end"},
+		{90, 5, ""},
+	}
+	for _, step := range steps {
+		pet.Illness.Hours, pet.Health = step.hours, step.health
+		if step.health < activeBalance.Sickness.HospiceHealthBelow && pet.Illness.Hospice.IsZero() {
+			pet.Illness.Hospice = time.Now()
+		}
+		got := pet.SicknessNews()
+		if (step.want == "") != (got == "") || !strings.Contains(got, step.want) {
+			t.Errorf("At %vh, expected %q, got %q", step.hours, step.want, got)
+		}
+	}
+}
+
+func TestComfortShapesTheEnd(t *testing.T) {
+	tests := []struct {
+		name      string
+		comforts  []string
+		words     string
+		heirlooms []string
+	}{
+		{"alone", nil, defaultLastWords, []string{"wary"}},
+		{"held", []string{"hold"}, "I'll remember warm", []string{"gentle"}},
+		{"last comfort speaks", []string{"window", "sing"}, "Hum it again", []string{"musical", "curious"}},
+		{"everything", []string{"hold", "sing", "blanket", "window", "hold"}, "I had everything", []string{"gentle", "musical", "snug", "curious"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			pet := NewPet("Fading")
+			pet.Stage = Adult
+			pet.IsSick = true
+			pet.Happiness = 10
+			pet.Illness = &IllnessState{Hours: 80, Hospice: time.Now()}
+			for _, how := range tt.comforts {
+				pet.Comfort(how)
+			}
+			if pet.Happiness <= 10 && len(tt.comforts) > 0 {
+				t.Errorf("Expected comfort to lift its spirits, got %d", pet.Happiness)
+			}
+			if !pet.IsSick {
+				t.Errorf("Expected comfort not to cure")
+			}
+			pet.Stage = Dead
+			if got := pet.lastWords(); !strings.Contains(got, tt.words) {
+				t.Errorf("Expected last words with %q, got %q", tt.words, got)
+			}
+			if got := pet.heirlooms(); !slices.Equal(got, tt.heirlooms) {
+				t.Errorf("Expected heirlooms %v, got %v", tt.heirlooms, got)
+			}
+		})
+	}
+}
+
+func TestComfortOnlyInHospice(t *testing.T) {
+	pet := NewPet("Fine")
+	pet.Stage = Adult
+	if got := pet.Comfort("hold"); !strings.Contains(got, "isn't at that point") {
+		t.Errorf("Expected comfort refused for a well pet, got %q", got)
+	}
+
+	pet.IsSick = true
+	pet.Illness = &IllnessState{Hours: 80, Hospice: time.Now()}
+	if got := pet.Comfort("juggle"); !strings.Contains(got, "hold, sing, blanket, window") {
+		t.Errorf("Expected the comforts listed, got %q", got)
+	}
+
+	pet.Stage = Dead
+	pet.Illness = nil
+	if got := pet.heirlooms(); got != nil {
+		t.Errorf("Expected nothing inherited from a pet that didn't die in hospice, got %v", got)
+	}
+	if got := pet.deathNotice(); !strings.Contains(got, "neglect") {
+		t.Errorf("Expected the neglect notice, got %q", got)
+	}
+}
@@ -0,0 +1,50 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/tamagotchi/mooc"
+)
+
+// runSignalCommand handles `signal`
+func runSignalCommand(pet *Pet) string {
+	return renderSignal(pet, petNetwork)
+}
+
+// renderSignal shows what the pet picks up on the mesh: whether it's on,
+// and which friends are around. A pet that has found clarity hears the
+// numbers underneath too; the rest only hear that there's something there.
+func renderSignal(pet *Pet, network *mooc.Network) string {
+	if network == nil {
+		return "📡 Static. Nothing out there answers."
+	}
+
+	var b strings.Builder
+	b.WriteString(network.GetNetworkStatus())
+	if network.IsEnabled() {
+		if online := onlineFriendNames(network.Stars()); len(online) > 0 {
+			fmt.Fprintf(&b, "\n👥 Online now: %s", strings.Join(online, ", "))
+		} else {
+			b.WriteString("\n🌙 None of its friends are online.")
+		}
+	}
+
+	if pet.Absurd != nil && pet.Absurd.HasAchievedClarity {
+		b.WriteString("\n" + network.GetSecretStats())
+	} else {
+		b.WriteString("\n〰️ Something else hums under the signal. Your pet can't make it out. Yet.")
+	}
+	return b.String()
+}
+
+// onlineFriendNames names the living pets online now, in star order
+func onlineFriendNames(stars []mooc.Star) []string {
+	var names []string
+	for _, star := range stars {
+		if star.Online && !star.IsDeceased && star.DisplayName != "" {
+			names = append(names, star.DisplayName)
+		}
+	}
+	return names
+}
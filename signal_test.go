@@ -0,0 +1,54 @@
+package main
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/tamagotchi/mooc"
+)
+
+func TestRenderSignal(t *testing.T) {
+	tests := []struct {
+		name        string
+		network     *mooc.Network
+		enlightened bool
+		want        []string
+		unwanted    []string
+	}{
+		{"no network", nil, false, []string{"Static"}, []string{"HIDDEN"}},
+		{"offline", mooc.NewNetwork("Radio", time.Now(), "Adult", true), false,
+			[]string{"📡 Network: Offline", "Something else hums"}, []string{"The mesh sleeps"}},
+		{"enlightened", mooc.NewNetwork("Radio", time.Now(), "Adult", true), true,
+			[]string{"📡 Network: Offline", "The mesh sleeps"}, []string{"Something else hums"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			pet := NewPet("Radio")
+			pet.Absurd.HasAchievedClarity = tt.enlightened
+			got := renderSignal(pet, tt.network)
+			for _, want := range tt.want {
+				if !strings.Contains(got, want) {
+					t.Errorf("Expected %q in:\n%s", want, got)
+				}
+			}
+			for _, unwanted := range tt.unwanted {
+				if strings.Contains(got, unwanted) {
+					t.Errorf("Expected no %q in:\n%s", unwanted, got)
+				}
+			}
+		})
+	}
+}
+
+func TestOnlineFriendNames(t *testing.T) {
+	stars := []mooc.Star{
+		{DisplayName: "Mochi", Online: true},
+		{DisplayName: "Bean"},
+		{DisplayName: "Pixel", Online: true, IsDeceased: true},
+		{DisplayName: "Pip", Online: true},
+	}
+	if got := strings.Join(onlineFriendNames(stars), ", "); got != "Mochi, Pip" {
+		t.Errorf("Expected the living friends online, got %q", got)
+	}
+}
@@ -0,0 +1,215 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"html/template"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/tamagotchi/mooc"
+)
+
+// siteDir is where export site writes the pet's homepage. It's a folder
+// with an index.html, so it can be pushed to GitHub Pages as it is.
+const siteDir = "tamagotchi_site"
+
+// Ancestor is a pet that came before this one, kept for the family tree
+type Ancestor struct {
+	Name      string    `json:"name"`
+	Born      time.Time `json:"born"`
+	Until     time.Time `json:"until"` // When it died or was reset
+	Stage     string    `json:"stage"` // How it ended: the stage it reached, or Dead
+	AgeHours  int       `json:"age_hours"`
+	CareScore int       `json:"care_score,omitempty"` // 0-100 over the care log it left; 0 when there was none
+	Traits    []string  `json:"traits,omitempty"`     // The traits it had
+	LastWords string    `json:"last_words,omitempty"` // Only a pet that died has them
+	Passed    []string  `json:"passed,omitempty"`     // What it handed down: traits, a favorite food
+}
+
+// asAncestor is how the pet is remembered once a new one hatches
+func (p *Pet) asAncestor(now time.Time) Ancestor {
+	a := Ancestor{Name: p.Name, Born: p.BirthTime, Until: now, Stage: p.Stage.String(), AgeHours: p.Age, Traits: slices.Clone(p.Traits), Passed: p.heirlooms()}
+	if care := buildWeeklyReport(p.CareLog, p.BirthTime, now, nil, nil); care.Samples > 0 {
+		a.CareScore = care.CareScore
+	}
+	if p.Stage == Dead {
+		a.LastWords = p.lastWords()
+	}
+	if favorite := p.favoriteToPass(); favorite != "" {
+		a.Passed = append(a.Passed, "a love of "+favorite)
+	}
+	return a
+}
+
+// siteStat is one bar on the homepage
+type siteStat struct {
+	Name  string
+	Value int
+}
+
+// siteAchievement is an unlocked achievement, as the homepage shows it
+type siteAchievement struct {
+	Name        string
+	Description string
+}
+
+// siteMemorial is one life remembered on the homepage
+type siteMemorial struct {
+	Name      string
+	Died      time.Time
+	AgeHours  int
+	LastWords string
+	Kin       bool // One of this pet's own line, not a pet heard of on the mesh
+}
+
+// siteData is everything the homepage shows
+type siteData struct {
+	Name         string
+	Stage        string
+	Born         time.Time
+	AgeHours     int
+	Portrait     string
+	Stats        []siteStat
+	Traits       []string
+	Achievements []siteAchievement
+	Total        int
+	Lineage      []Ancestor
+	Memorial     []siteMemorial
+	Generated    time.Time
+}
+
+// siteHTML is the pet's homepage. Everything is inline, so the one file
+// is the whole site.
+var siteHTML = template.Must(template.New("site").Parse(`<!DOCTYPE html>
+<html lang="en">
+<head><meta charset="utf-8"><meta name="viewport" content="width=device-width, initial-scale=1">
+<title>{{.Name}} — a Tamagotchi</title>
+<style>body{font-family:monospace;max-width:44em;margin:2em auto;padding:0 1em;background:#101418;color:#e8e6e3}
+h1,h2{color:#8fd3ff}pre.portrait{background:#000;color:#9f9;padding:1em;display:inline-block}
+.bar{display:inline-block;background:#333;width:12em;height:.8em}.bar span{display:block;height:100%;background:#8fd3ff}
+td{padding:.1em 1em .1em 0}ol.tree{list-style:none;padding-left:0}ol.tree li{border-left:2px solid #555;padding:.3em 1em}
+ol.tree li.now{border-color:#8fd3ff}blockquote{color:#aaa}footer{color:#777;margin-top:3em}</style></head>
+<body>
+<h1>{{.Name}}</h1>
+<p>{{.Stage}}, {{.AgeHours}} hours old. Hatched {{.Born.Format "Mon Jan 2 2006"}}.</p>
+<pre class="portrait">{{.Portrait}}</pre>
+<h2>Stats</h2>
+<table>{{range .Stats}}<tr><td>{{.Name}}</td><td><span class="bar"><span style="width:{{.Value}}%"></span></span></td><td>{{.Value}}</td></tr>
+{{end}}</table>
+{{if .Traits}}<p>Traits: {{range $i, $t := .Traits}}{{if $i}}, {{end}}{{$t}}{{end}}</p>{{end}}
+<h2>Achievements ({{len .Achievements}} of {{.Total}})</h2>
+{{if .Achievements}}<ul>{{range .Achievements}}<li><strong>{{.Name}}</strong> — {{.Description}}</li>
+{{end}}</ul>{{else}}<p>None yet.</p>{{end}}
+<h2>Family tree</h2>
+<ol class="tree">{{range .Lineage}}<li>{{.Name}} — {{.Born.Format "Jan 2 2006"}} to {{.Until.Format "Jan 2 2006"}}, {{.Stage}} at {{.AgeHours}} hours{{if .Passed}}. Passed on: {{range $i, $p := .Passed}}{{if $i}}, {{end}}{{$p}}{{end}}{{end}}</li>
+{{end}}<li class="now">{{.Name}} — hatched {{.Born.Format "Jan 2 2006"}}, {{.Stage}}</li></ol>
+<h2>Memorial</h2>
+{{if .Memorial}}<ul>{{range .Memorial}}<li>🕯️ <strong>{{.Name}}</strong>{{if .Kin}} (family){{end}}, died {{.Died.Format "Jan 2 2006"}} at {{.AgeHours}} hours{{if .LastWords}}<blockquote>“{{.LastWords}}”</blockquote>{{end}}</li>
+{{end}}</ul>{{else}}<p>Nobody to mourn. Yet.</p>{{end}}
+<footer>Generated {{.Generated.Format "Mon Jan 2 2006 15:04"}} by tamagotchi export site.</footer>
+</body>
+</html>
+`))
+
+// sitePortrait is the pet's current look as plain art
+func sitePortrait(ui *uiConfig, p *Pet) string {
+	if frames := ui.art().StageFrames(p.Stage, artMood(p)); len(frames) > 0 {
+		return strings.TrimRight(frames[0], "\n")
+	}
+	return idleSprites[p.Stage]
+}
+
+// siteMemorials are the pet's own line that died, then the deaths it
+// heard of on the mesh, newest first
+func siteMemorials(p *Pet) []siteMemorial {
+	var memorial []siteMemorial
+	if p.Stage == Dead {
+		memorial = append(memorial, siteMemorial{Name: p.Name, Died: p.LastUpdateTime, AgeHours: p.Age, LastWords: p.lastWords(), Kin: true})
+	}
+	for _, a := range slices.Backward(p.Lineage) {
+		if a.Stage == Dead.String() {
+			memorial = append(memorial, siteMemorial{Name: a.Name, Died: a.Until, AgeHours: a.AgeHours, LastWords: a.LastWords, Kin: true})
+		}
+	}
+	var state mooc.NetworkState
+	if len(p.Friends) > 0 && json.Unmarshal(p.Friends, &state) == nil {
+		for _, death := range state.Deaths {
+			memorial = append(memorial, siteMemorial{Name: death.PetName, Died: death.DeathTime, AgeHours: death.Age, LastWords: death.LastWords})
+		}
+	}
+	return memorial
+}
+
+// buildSite gathers the homepage
+func buildSite(ui *uiConfig, p *Pet, now time.Time) siteData {
+	data := siteData{
+		Name: p.Name, Stage: p.Stage.String(), Born: p.BirthTime, AgeHours: p.Age,
+		Portrait: sitePortrait(ui, p),
+		Stats: []siteStat{
+			{"Hunger", p.Hunger}, {"Happiness", p.Happiness}, {"Health", p.Health}, {"Cleanliness", p.Cleanliness},
+		},
+		Traits:    p.Traits,
+		Total:     len(allAchievements),
+		Lineage:   p.Lineage,
+		Memorial:  siteMemorials(p),
+		Generated: now,
+	}
+	if p.Endgame != nil {
+		for _, a := range allAchievements {
+			if hasAchievement(p.Endgame, a.ID) {
+				data.Achievements = append(data.Achievements, siteAchievement{Name: a.Name, Description: a.Description})
+			}
+		}
+	}
+	return data
+}
+
+// exportSite writes the homepage into dir and returns the page's path
+func exportSite(ui *uiConfig, p *Pet, dir string, now time.Time) (string, error) {
+	var b strings.Builder
+	if err := siteHTML.Execute(&b, buildSite(ui, p, now)); err != nil {
+		return "", fmt.Errorf("failed to render the homepage: %w", err)
+	}
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return "", fmt.Errorf("failed to create %s: %w", dir, err)
+	}
+	path := filepath.Join(dir, "index.html")
+	if err := os.WriteFile(path, []byte(b.String()), 0644); err != nil {
+		return "", fmt.Errorf("failed to write %s: %w", path, err)
+	}
+	return path, nil
+}
+
+// runExportCommand handles `export site`, `export feed` and `export calendar`
+func runExportCommand(ui *uiConfig, pet *Pet, args string) string {
+	switch strings.TrimSpace(args) {
+	case "site":
+		saveNetworkState(pet) // The memorial reads deaths from the saved mesh state
+		path, err := exportSite(ui, pet, siteDir, time.Now())
+		if err != nil {
+			return "❌ " + err.Error()
+		}
+		return fmt.Sprintf("🌐 %s's homepage is in %s. It's one self-contained file: push the %s folder to GitHub Pages and it's live.", pet.Name, path, siteDir)
+	case "feed":
+		var events []mooc.NetworkEvent
+		if petNetwork != nil {
+			events = petNetwork.History()
+		}
+		path := feedPath(pet)
+		if err := writeFeed(pet, events, path, time.Now()); err != nil {
+			return "❌ " + err.Error()
+		}
+		return fmt.Sprintf("📰 %s's life is now an Atom feed in %s. Point a feed reader at it; autosave keeps it up to date. Delete the file to stop.", pet.Name, path)
+	case "calendar":
+		path := calendarPath(pet)
+		if err := writeCalendar(pet, path, time.Now()); err != nil {
+			return "❌ " + err.Error()
+		}
+		return fmt.Sprintf("📅 %s's milestones are in %s. Subscribe to it in your calendar app; autosave keeps it up to date. Delete the file to stop.", pet.Name, path)
+	}
+	return "❓ Usage: export site|feed|calendar"
+}
@@ -0,0 +1,94 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/tamagotchi/mooc"
+)
+
+func TestAsAncestor(t *testing.T) {
+	now := time.Now()
+	pet := NewPet("Elder")
+	pet.Stage, pet.Age = Dead, 300
+	pet.Illness = &IllnessState{Hospice: now.Add(-time.Hour), Comforts: []string{"sing"}}
+	pet.palate().Inherited = "fish"
+
+	a := pet.asAncestor(now)
+	if a.Name != "Elder" || a.Stage != "Dead" || a.AgeHours != 300 || !a.Until.Equal(now) {
+		t.Errorf("Expected Elder remembered as dead at 300 hours, got %+v", a)
+	}
+	if a.LastWords == "" {
+		t.Errorf("Expected last words for a pet that died")
+	}
+	if len(a.Passed) != 2 || a.Passed[1] != "a love of fish" {
+		t.Errorf("Expected a trait and a favorite passed on, got %v", a.Passed)
+	}
+
+	pet.Reset("Next")
+	if pet.Lineage != nil {
+		t.Errorf("Expected Reset to leave the lineage to the caller, got %v", pet.Lineage)
+	}
+}
+
+func TestExportSite(t *testing.T) {
+	now := time.Date(2026, 10, 16, 12, 0, 0, 0, time.UTC)
+	pet := NewPet("<Mochi>")
+	pet.Stage = Adult
+	pet.Age = 200
+	pet.Traits = []string{"dreamy"}
+	pet.Endgame.UnlockedAchievements = []string{allAchievements[0].ID}
+	pet.Lineage = []Ancestor{
+		{Name: "Grandpa", Born: now.Add(-900 * time.Hour), Until: now.Add(-500 * time.Hour), Stage: "Dead", AgeHours: 400, LastWords: "Feed the next one.", Passed: []string{"wary"}},
+		{Name: "Mum", Born: now.Add(-500 * time.Hour), Until: now.Add(-200 * time.Hour), Stage: "Teen", AgeHours: 300},
+	}
+	friends, _ := json.Marshal(mooc.NetworkState{Deaths: []mooc.DeathPayload{{PetName: "Bean", DeathTime: now.Add(-time.Hour), Age: 50, LastWords: "Tell Mochi."}}})
+	pet.Friends = friends
+
+	dir := filepath.Join(t.TempDir(), siteDir)
+	path, err := exportSite(newUIConfig(), pet, dir, now)
+	if err != nil {
+		t.Fatalf("Expected the site exported, got %v", err)
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("Expected index.html written, got %v", err)
+	}
+	page := string(data)
+
+	for _, want := range []string{
+		"&lt;Mochi&gt;", `<pre class="portrait">`, "Happiness", "dreamy",
+		allAchievements[0].Name, fmt.Sprintf("1 of %d", len(allAchievements)),
+		"Grandpa", "Passed on: wary", "Mum", `class="now"`,
+		"Feed the next one.", "(family)", "Bean", "Tell Mochi.",
+	} {
+		if !strings.Contains(page, want) {
+			t.Errorf("Expected %q on the homepage", want)
+		}
+	}
+	if strings.Contains(page, "<Mochi>") {
+		t.Errorf("Expected the name escaped")
+	}
+	if strings.Contains(page, "<link") || strings.Contains(page, "<script src") {
+		t.Errorf("Expected a self-contained page")
+	}
+}
+
+func TestRunExportCommand(t *testing.T) {
+	t.Chdir(t.TempDir())
+	pet := NewPet("Mochi")
+	if got := runExportCommand(newUIConfig(), pet, ""); !strings.Contains(got, "Usage") {
+		t.Errorf("Expected usage, got %q", got)
+	}
+	if got := runExportCommand(newUIConfig(), pet, "site"); !strings.Contains(got, filepath.Join(siteDir, "index.html")) {
+		t.Errorf("Expected the path to the homepage, got %q", got)
+	}
+	if _, err := os.Stat(filepath.Join(siteDir, "index.html")); err != nil {
+		t.Errorf("Expected the homepage written, got %v", err)
+	}
+}
@@ -0,0 +1,229 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+const (
+	// trainingSession is how long one session at pet school takes
+	trainingSession = 30 * time.Minute
+
+	// maxSkillLevel is as good as a pet gets at anything
+	maxSkillLevel = 5
+
+	// skillBarWidth is how many cells a skill's progress bar has
+	skillBarWidth = 10
+)
+
+// skill is something a pet can learn at school
+type skill struct {
+	ID     string
+	Name   string
+	Emoji  string
+	Glyph  string                 // Particle floated up while training
+	effect func(level int) string // What each level does, for the school screen
+}
+
+// skills are taught in this order on the school screen
+var skills = []skill{
+	{ID: "foraging", Name: "Foraging", Emoji: "🌿", Glyph: "♣",
+		effect: func(level int) string {
+			return fmt.Sprintf("Hunger rises %d%% slower", foragingPercent*level)
+		}},
+	{ID: "meditation", Name: "Meditation", Emoji: "🧘", Glyph: "○",
+		effect: func(level int) string {
+			return fmt.Sprintf("Vibe recovers %d points an hour", meditationPerHour*level)
+		}},
+	{ID: "networking", Name: "Networking", Emoji: "📡", Glyph: "·",
+		effect: func(level int) string {
+			return fmt.Sprintf("Gossip counts for %d%% more influence", networkingPercent*level)
+		}},
+}
+
+// What each skill level is worth to the systems it touches
+const (
+	foragingPercent   = 6  // Less hunger decay per level
+	meditationPerHour = 2  // Vibe check points recovered per hour per level
+	networkingPercent = 10 // Extra influence per level
+)
+
+// findSkill looks a skill up by ID
+func findSkill(id string) *skill {
+	for i := range skills {
+		if skills[i].ID == id {
+			return &skills[i]
+		}
+	}
+	return nil
+}
+
+// sessionsForLevel is how many sessions it takes to get from a level to the
+// next; each level takes one more than the last
+func sessionsForLevel(level int) int {
+	return level + 1
+}
+
+// TrainingSession is a session at pet school in progress
+type TrainingSession struct {
+	Skill   string    `json:"skill"`
+	Started time.Time `json:"started"`
+	Until   time.Time `json:"until"`
+}
+
+// SkillState tracks what the pet has learned and what it's learning
+type SkillState struct {
+	Levels   map[string]int   `json:"levels,omitempty"`
+	Sessions map[string]int   `json:"sessions,omitempty"` // Sessions done toward the next level
+	Training *TrainingSession `json:"training,omitempty"`
+}
+
+// skills returns the pet's skill state, creating it on first use
+func (p *Pet) skills() *SkillState {
+	if p.Skills == nil {
+		p.Skills = &SkillState{Levels: make(map[string]int), Sessions: make(map[string]int)}
+	}
+	if p.Skills.Levels == nil {
+		p.Skills.Levels = make(map[string]int)
+	}
+	if p.Skills.Sessions == nil {
+		p.Skills.Sessions = make(map[string]int)
+	}
+	return p.Skills
+}
+
+// SkillLevel is the pet's level in a skill, zero if it never trained
+func (p *Pet) SkillLevel(id string) int {
+	if p.Skills == nil {
+		return 0
+	}
+	return p.Skills.Levels[id]
+}
+
+// StartTraining sends the pet to school for a session of a skill
+func (p *Pet) StartTraining(id string, now time.Time) (string, bool) {
+	sk := findSkill(id)
+	if sk == nil {
+		return fmt.Sprintf("❓ There's no class in %s. Try foraging, meditation, or networking.", id), false
+	}
+	switch {
+	case p.Stage == Dead:
+		return "🎓 School is out. Forever.", false
+	case p.Stage == Egg:
+		return "🎓 Eggs can't enroll. Wait until it hatches.", false
+	case p.Paused || p.Boarding.IsAway(now):
+		return fmt.Sprintf("🎓 %s isn't here to train.", p.Name), false
+	}
+
+	s := p.skills()
+	if s.Training != nil {
+		current := findSkill(s.Training.Skill)
+		return fmt.Sprintf("🎓 %s is already training %s. %s left.", p.Name, current.Name, s.Training.Until.Sub(now).Round(time.Minute)), false
+	}
+	if s.Levels[id] >= maxSkillLevel {
+		return fmt.Sprintf("🎓 %s has mastered %s. There's nothing left to teach.", p.Name, sk.Name), false
+	}
+	s.Training = &TrainingSession{Skill: id, Started: now, Until: now.Add(trainingSession)}
+	return fmt.Sprintf("🎓 %s starts a %s session. Back in %s.", p.Name, strings.ToLower(sk.Name), trainingSession), true
+}
+
+// CheckTraining finishes a session whose time is up, returning what
+// happened and the event to animate it with
+func (p *Pet) CheckTraining(now time.Time) (string, string) {
+	if p.Skills == nil || p.Skills.Training == nil || now.Before(p.Skills.Training.Until) {
+		return "", ""
+	}
+	s := p.skills()
+	sk := findSkill(s.Training.Skill)
+	s.Training = nil
+	if sk == nil || p.Stage == Dead {
+		return "", ""
+	}
+
+	s.Sessions[sk.ID]++
+	level := s.Levels[sk.ID]
+	if s.Sessions[sk.ID] < sessionsForLevel(level) {
+		return fmt.Sprintf("🎓 %s finished a %s session (%d/%d toward level %d).",
+			p.Name, strings.ToLower(sk.Name), s.Sessions[sk.ID], sessionsForLevel(level), level+1), "training_" + sk.ID
+	}
+
+	s.Sessions[sk.ID] = 0
+	s.Levels[sk.ID] = level + 1
+	if sk.ID == "networking" && petNetwork != nil {
+		petNetwork.SetInfluenceBonus(p.influenceBonus())
+	}
+	return fmt.Sprintf("🎓 %s reached %s level %d! %s.", p.Name, sk.Name, level+1, sk.effect(level+1)), "levelup"
+}
+
+// hungerDecay scales hunger decay by foraging
+func (p *Pet) hungerDecay() float64 {
+	return 1 - float64(foragingPercent*p.SkillLevel("foraging"))/100
+}
+
+// meditate recovers the vibe check over some hours
+func (p *Pet) meditate(hours float64) {
+	if p.Absurd == nil {
+		return
+	}
+	vibe := &p.Absurd.MysteryStats.VibeCheckScore
+	*vibe = clamp(*vibe+int(hours*float64(meditationPerHour*p.SkillLevel("meditation"))), 0, 100)
+}
+
+// influenceBonus is the percentage networking adds to gossip influence
+func (p *Pet) influenceBonus() int {
+	return networkingPercent * p.SkillLevel("networking")
+}
+
+// skillBar draws a progress bar for a fraction between 0 and 1
+func skillBar(fraction float64) string {
+	filled := clamp(int(fraction*skillBarWidth), 0, skillBarWidth)
+	return "[" + strings.Repeat("█", filled) + strings.Repeat("░", skillBarWidth-filled) + "]"
+}
+
+// renderSchool shows every skill with its level and progress
+func (p *Pet) renderSchool(now time.Time) string {
+	s := p.skills()
+	var b strings.Builder
+	b.WriteString("🎓 PET SCHOOL\n")
+	b.WriteString(fmt.Sprintf("One %s session at a time.\n\n", trainingSession))
+	for _, sk := range skills {
+		level := s.Levels[sk.ID]
+		if level >= maxSkillLevel {
+			b.WriteString(fmt.Sprintf("  %s %-10s Lv %d %s mastered\n", sk.Emoji, sk.Name, level, skillBar(1)))
+		} else {
+			need := sessionsForLevel(level)
+			b.WriteString(fmt.Sprintf("  %s %-10s Lv %d %s %d/%d sessions\n", sk.Emoji, sk.Name, level, skillBar(float64(s.Sessions[sk.ID])/float64(need)), s.Sessions[sk.ID], need))
+		}
+		effect := "Untrained"
+		if level > 0 {
+			effect = sk.effect(level)
+		}
+		b.WriteString(fmt.Sprintf("     %s\n", effect))
+	}
+
+	if t := s.Training; t != nil {
+		sk := findSkill(t.Skill)
+		done := now.Sub(t.Started).Seconds() / t.Until.Sub(t.Started).Seconds()
+		b.WriteString(fmt.Sprintf("\nTraining %s: %s %s left", sk.Name, skillBar(done), max(t.Until.Sub(now), 0).Round(time.Minute)))
+	} else {
+		b.WriteString("\nStart a session with: train <skill>")
+	}
+	return b.String()
+}
+
+// runTrainCommand shows pet school or starts a session
+func runTrainCommand(ctx *commandContext, now time.Time) string {
+	words := strings.Fields(ctx.args)
+	switch len(words) {
+	case 0:
+		return ctx.pet.renderSchool(now)
+	case 1:
+		message, started := ctx.pet.StartTraining(words[0], now)
+		if started {
+			ctx.emit("training_" + words[0])
+		}
+		return message
+	}
+	return "❓ Usage: train or train <skill>"
+}
@@ -0,0 +1,111 @@
+package main
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestStartTraining(t *testing.T) {
+	now := time.Now()
+	tests := []struct {
+		name    string
+		setup   func(p *Pet)
+		skill   string
+		want    string
+		started bool
+	}{
+		{"a hatched pet", func(p *Pet) {}, "foraging", "starts a foraging session", true},
+		{"an unknown skill", func(p *Pet) {}, "juggling", "no class in juggling", false},
+		{"an egg", func(p *Pet) { p.Stage = Egg }, "foraging", "Eggs can't enroll", false},
+		{"a paused pet", func(p *Pet) { p.Paused = true }, "foraging", "isn't here", false},
+		{"a pet already training", func(p *Pet) { p.StartTraining("meditation", now) }, "foraging", "already training Meditation", false},
+		{"a master", func(p *Pet) { p.skills().Levels["networking"] = maxSkillLevel }, "networking", "mastered Networking", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			pet := NewPet("Pupil")
+			pet.Stage = Child
+			tt.setup(pet)
+			got, started := pet.StartTraining(tt.skill, now)
+			if !strings.Contains(got, tt.want) || started != tt.started {
+				t.Errorf("Expected %q (started %v), got %s (started %v)", tt.want, tt.started, got, started)
+			}
+		})
+	}
+}
+
+func TestTrainingLevelsUp(t *testing.T) {
+	pet := NewPet("Pupil")
+	pet.Stage = Child
+	now := time.Now()
+
+	session := func() (string, string) {
+		pet.StartTraining("foraging", now)
+		if got, _ := pet.CheckTraining(now.Add(trainingSession - time.Minute)); got != "" {
+			t.Fatalf("Expected the session to run its full length, got %s", got)
+		}
+		now = now.Add(trainingSession)
+		return pet.CheckTraining(now)
+	}
+
+	if got, event := session(); !strings.Contains(got, "reached Foraging level 1") || event != "levelup" {
+		t.Errorf("Expected level 1 after one session, got %s (%s)", got, event)
+	}
+	if got, event := session(); !strings.Contains(got, "1/2 toward level 2") || event != "training_foraging" {
+		t.Errorf("Expected level 2 to take two sessions, got %s (%s)", got, event)
+	}
+	session()
+	if pet.SkillLevel("foraging") != 2 || pet.Skills.Training != nil {
+		t.Errorf("Expected level 2 and school out, got %+v", pet.Skills)
+	}
+}
+
+func TestSkillCrossEffects(t *testing.T) {
+	pet := NewPet("Scholar")
+	if pet.hungerDecay() != 1 || pet.influenceBonus() != 0 {
+		t.Errorf("Expected an untrained pet to be unaffected")
+	}
+
+	pet.skills().Levels["foraging"] = 5
+	pet.skills().Levels["meditation"] = 3
+	pet.skills().Levels["networking"] = 2
+	if got := pet.hungerDecay(); got < 0.69 || got > 0.71 {
+		t.Errorf("Expected hunger to decay 30%% slower, got %v", got)
+	}
+	if got := pet.influenceBonus(); got != 20 {
+		t.Errorf("Expected a 20%% influence bonus, got %d", got)
+	}
+	pet.Absurd.MysteryStats.VibeCheckScore = 10
+	pet.meditate(2)
+	if got := pet.Absurd.MysteryStats.VibeCheckScore; got != 22 {
+		t.Errorf("Expected the vibe to recover to 22, got %d", got)
+	}
+}
+
+func TestRenderSchool(t *testing.T) {
+	pet := NewPet("Pupil")
+	pet.Stage = Child
+	now := time.Now()
+	pet.skills().Levels["meditation"] = 1
+	pet.StartTraining("meditation", now)
+
+	got := pet.renderSchool(now.Add(trainingSession / 2))
+	for _, want := range []string{"PET SCHOOL", "Foraging   Lv 0 [░░░░░░░░░░] 0/1", "Vibe recovers 2 points an hour", "Training Meditation: [█████░░░░░] 15m0s left"} {
+		if !strings.Contains(got, want) {
+			t.Errorf("Expected %q, got %s", want, got)
+		}
+	}
+}
+
+func TestTrainingBursts(t *testing.T) {
+	ui := newUIConfig()
+	for _, event := range []string{"training_foraging", "training_networking", "levelup"} {
+		if layers, length := ui.burstLayers(event); len(layers) == 0 || length == 0 {
+			t.Errorf("Expected a particle burst for %s", event)
+		}
+	}
+	if layers, _ := ui.burstLayers("training_juggling"); len(layers) != 0 {
+		t.Errorf("Expected no burst for an unknown skill")
+	}
+}
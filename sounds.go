@@ -0,0 +1,165 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/exec"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// soundConfigFile routes notification sounds to real audio instead of the bell
+const soundConfigFile = "tamagotchi_sounds.json"
+
+// soundEvents are the notification events a sound can be routed to
+var soundEvents = []string{"critical", "alert", "achievement", "network", "break"}
+
+// soundPlayerCandidates are the audio players tried on each platform, in
+// order of preference
+var soundPlayerCandidates = map[string][]string{
+	"darwin":  {"afplay"},
+	"windows": {"powershell"},
+	"linux":   {"paplay", "aplay"},
+}
+
+// SoundRoute plays an event through an external player instead of the bell
+type SoundRoute struct {
+	File     string   `json:"file,omitempty"`     // Played with the platform's player
+	Command  []string `json:"command,omitempty"`  // Run instead; {file} and {volume} are filled in
+	Volume   int      `json:"volume,omitempty"`   // 1-100; 0 plays at full volume
+	Cooldown string   `json:"cooldown,omitempty"` // e.g. "30s"; the event stays quiet this long after playing
+
+	cooldown time.Duration
+}
+
+// volume is the route's volume, 1-100
+func (r *SoundRoute) volume() int {
+	if r.Volume == 0 {
+		return 100
+	}
+	return r.Volume
+}
+
+// soundRouter plays routed events and remembers when each last played
+type soundRouter struct {
+	routes     map[string]*SoundRoute
+	player     string // Resolved platform player path; "" when none was found
+	playerName string
+	mutex      sync.Mutex
+	lastPlayed map[string]time.Time
+	start      func(cmd *exec.Cmd) error
+}
+
+// loadSoundRoutes reads the sound config, returning nil when there isn't one
+func loadSoundRoutes(path string, lookPath func(string) (string, error), goos string) (*soundRouter, error) {
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %s: %w", path, err)
+	}
+	return parseSoundRoutes(data, lookPath, goos)
+}
+
+// parseSoundRoutes decodes and checks a sound config
+func parseSoundRoutes(data []byte, lookPath func(string) (string, error), goos string) (*soundRouter, error) {
+	var routes map[string]*SoundRoute
+	if err := json.Unmarshal(data, &routes); err != nil {
+		return nil, fmt.Errorf("failed to parse sound config: %w", err)
+	}
+
+	router := &soundRouter{routes: routes, lastPlayed: make(map[string]time.Time), start: (*exec.Cmd).Start}
+	candidates, ok := soundPlayerCandidates[goos]
+	if !ok {
+		candidates = soundPlayerCandidates["linux"]
+	}
+	for _, name := range candidates {
+		if path, err := lookPath(name); err == nil {
+			router.player, router.playerName = path, name
+			break
+		}
+	}
+
+	for event, route := range routes {
+		switch {
+		case !slices.Contains(soundEvents, event):
+			return nil, fmt.Errorf("unknown sound event %q (use %s)", event, strings.Join(soundEvents, ", "))
+		case route == nil || (route.File == "" && len(route.Command) == 0):
+			return nil, fmt.Errorf("sound for %s needs a file or a command", event)
+		case route.Volume < 0 || route.Volume > 100:
+			return nil, fmt.Errorf("sound for %s has volume %d; use 1-100", event, route.Volume)
+		case len(route.Command) == 0 && router.player == "":
+			return nil, fmt.Errorf("no sound player found for %s (tried %s); use a command instead", event, strings.Join(candidates, ", "))
+		}
+		if route.Cooldown != "" {
+			d, err := time.ParseDuration(route.Cooldown)
+			if err != nil || d < 0 {
+				return nil, fmt.Errorf("sound for %s has cooldown %q; use a duration like 30s", event, route.Cooldown)
+			}
+			route.cooldown = d
+		}
+	}
+	return router, nil
+}
+
+// command builds the invocation that plays a route
+func (s *soundRouter) command(route *SoundRoute) *exec.Cmd {
+	volume := route.volume()
+	if len(route.Command) > 0 {
+		args := make([]string, len(route.Command))
+		for i, arg := range route.Command {
+			arg = strings.ReplaceAll(arg, "{file}", route.File)
+			args[i] = strings.ReplaceAll(arg, "{volume}", strconv.Itoa(volume))
+		}
+		return exec.Command(args[0], args[1:]...)
+	}
+
+	switch s.playerName {
+	case "afplay":
+		return exec.Command(s.player, "-v", strconv.FormatFloat(float64(volume)/100, 'f', 2, 64), route.File)
+	case "paplay":
+		return exec.Command(s.player, fmt.Sprintf("--volume=%d", volume*65536/100), route.File)
+	case "powershell":
+		script := "Add-Type -AssemblyName PresentationCore; $p = New-Object System.Windows.Media.MediaPlayer; " +
+			"$p.Open([uri]'" + strings.ReplaceAll(route.File, "'", "''") + "'); " +
+			fmt.Sprintf("$p.Volume = %.2f; $p.Play(); Start-Sleep -Seconds 5", float64(volume)/100)
+		return exec.Command(s.player, "-NoProfile", "-Command", script)
+	default:
+		return exec.Command(s.player, "-q", route.File) // aplay has no volume
+	}
+}
+
+// play plays the sound routed to an event. It reports whether the event was
+// handled, so the caller only rings the bell for events without a route or
+// whose player failed to start. An event in its cooldown counts as handled.
+func (s *soundRouter) play(event string, now time.Time) bool {
+	if s == nil {
+		return false
+	}
+	route, ok := s.routes[event]
+	if !ok {
+		return false
+	}
+
+	s.mutex.Lock()
+	if last, played := s.lastPlayed[event]; played && now.Sub(last) < route.cooldown {
+		s.mutex.Unlock()
+		return true
+	}
+	s.lastPlayed[event] = now
+	s.mutex.Unlock()
+
+	cmd := s.command(route)
+	if err := s.start(cmd); err != nil {
+		return false
+	}
+	if cmd.Process != nil {
+		go cmd.Wait()
+	}
+	return true
+}
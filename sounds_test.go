@@ -0,0 +1,104 @@
+package main
+
+import (
+	"errors"
+	"os/exec"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestParseSoundRoutes(t *testing.T) {
+	tests := []struct {
+		name    string
+		config  string
+		found   []string
+		wantErr string
+	}{
+		{"a file", `{"critical": {"file": "alarm.wav", "volume": 80, "cooldown": "30s"}}`, []string{"paplay"}, ""},
+		{"a command", `{"achievement": {"command": ["mpv", "{file}"]}}`, nil, ""},
+		{"an unknown event", `{"burp": {"file": "burp.wav"}}`, []string{"paplay"}, "unknown sound event"},
+		{"neither", `{"alert": {}}`, []string{"paplay"}, "needs a file or a command"},
+		{"a command with a file", `{"alert": {"command": ["mpv", "{file}"], "file": "a.wav"}}`, nil, ""},
+		{"too loud", `{"alert": {"file": "a.wav", "volume": 150}}`, []string{"paplay"}, "use 1-100"},
+		{"a bad cooldown", `{"alert": {"file": "a.wav", "cooldown": "soon"}}`, []string{"paplay"}, "cooldown"},
+		{"no player", `{"alert": {"file": "a.wav"}}`, nil, "no sound player found"},
+		{"not JSON", `bells`, nil, "failed to parse"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := parseSoundRoutes([]byte(tt.config), fakeLookPath(tt.found...), "linux")
+			if tt.wantErr == "" && err != nil {
+				t.Errorf("Expected the config to load, got %v", err)
+			}
+			if tt.wantErr != "" && (err == nil || !strings.Contains(err.Error(), tt.wantErr)) {
+				t.Errorf("Expected an error about %q, got %v", tt.wantErr, err)
+			}
+		})
+	}
+}
+
+func TestSoundRouteCommand(t *testing.T) {
+	tests := []struct {
+		goos   string
+		player string
+		route  SoundRoute
+		want   string
+	}{
+		{"darwin", "afplay", SoundRoute{File: "bell.aiff", Volume: 50}, "/usr/bin/afplay -v 0.50 bell.aiff"},
+		{"linux", "paplay", SoundRoute{File: "bell.wav"}, "/usr/bin/paplay --volume=65536 bell.wav"},
+		{"linux", "aplay", SoundRoute{File: "bell.wav"}, "/usr/bin/aplay -q bell.wav"},
+		{"windows", "powershell", SoundRoute{File: "C:\\it's.wav", Volume: 25}, "[uri]'C:\\it''s.wav'); $p.Volume = 0.25"},
+		{"linux", "", SoundRoute{File: "x.ogg", Command: []string{"mpv", "--volume={volume}", "{file}"}, Volume: 40}, "mpv --volume=40 x.ogg"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.goos+" "+tt.player, func(t *testing.T) {
+			router, err := parseSoundRoutes([]byte(`{}`), fakeLookPath(tt.player), tt.goos)
+			if err != nil {
+				t.Fatal(err)
+			}
+			cmd := router.command(&tt.route)
+			if got := strings.Join(cmd.Args, " "); !strings.Contains(got, tt.want) {
+				t.Errorf("Expected %q, got %q", tt.want, got)
+			}
+		})
+	}
+}
+
+func TestSoundRouterPlay(t *testing.T) {
+	router, err := parseSoundRoutes([]byte(`{"critical": {"file": "alarm.wav", "cooldown": "1m"}}`), fakeLookPath("paplay"), "linux")
+	if err != nil {
+		t.Fatal(err)
+	}
+	played := 0
+	router.start = func(*exec.Cmd) error {
+		played++
+		return nil
+	}
+	now := time.Date(2026, 10, 16, 12, 0, 0, 0, time.Local)
+
+	tests := []struct {
+		event  string
+		at     time.Duration
+		want   bool
+		played int
+	}{
+		{"critical", 0, true, 1},
+		{"critical", 30 * time.Second, true, 1}, // Cooling down, but still not a bell
+		{"alert", 30 * time.Second, false, 1},
+		{"critical", 2 * time.Minute, true, 2},
+	}
+	for _, tt := range tests {
+		if got := router.play(tt.event, now.Add(tt.at)); got != tt.want || played != tt.played {
+			t.Errorf("Expected %s at %s to give %v after %d plays, got %v after %d", tt.event, tt.at, tt.want, tt.played, got, played)
+		}
+	}
+
+	router.start = func(*exec.Cmd) error { return errors.New("no audio device") }
+	if router.play("critical", now.Add(time.Hour)) {
+		t.Error("Expected a player that won't start to fall back to the bell")
+	}
+	if (*soundRouter)(nil).play("critical", now) {
+		t.Error("Expected no router to ring the bell")
+	}
+}
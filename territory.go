@@ -0,0 +1,224 @@
+package main
+
+import (
+	"bufio"
+	"fmt"
+	"io/fs"
+	"math/rand"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+const (
+	// maxTerritories limits how many directories a pet may claim
+	maxTerritories = 3
+
+	// territoryScanInterval is how often claimed directories are re-scanned
+	territoryScanInterval = 30 * time.Second
+
+	// territoryMaxDepth and territoryMaxEntries keep scans cheap on large trees
+	territoryMaxDepth   = 3
+	territoryMaxEntries = 5000
+)
+
+// Comments the pet makes about changes in its territory
+var territoryComments = []string{
+	"Something changed in %s at %s. It wasn't me.",
+	"Someone touched %s at %s. I saw everything. (Just the timestamps.)",
+	"Files shifted in %s around %s. I'm keeping an eye on it.",
+	"%s rearranged itself at %s. I pretended to be asleep.",
+}
+
+// fileMeta is the only information the pet ever learns about a file
+type fileMeta struct {
+	modTime time.Time
+	size    int64
+}
+
+// territoryWatcher polls claimed directories for metadata changes
+type territoryWatcher struct {
+	snapshots map[string]map[string]fileMeta
+	lastScan  time.Time
+}
+
+// newTerritoryWatcher creates a watcher with baseline snapshots of the claimed paths
+func newTerritoryWatcher(paths []string) *territoryWatcher {
+	tw := &territoryWatcher{snapshots: make(map[string]map[string]fileMeta)}
+	tw.SetPaths(paths)
+	return tw
+}
+
+// SetPaths replaces the watched paths, taking fresh baselines
+func (tw *territoryWatcher) SetPaths(paths []string) {
+	tw.snapshots = make(map[string]map[string]fileMeta)
+	for _, path := range paths {
+		tw.snapshots[path] = scanTerritory(path)
+	}
+	tw.lastScan = time.Now()
+}
+
+// Poll re-scans claimed paths (rate limited) and returns a comment if anything changed
+func (tw *territoryWatcher) Poll() string {
+	if len(tw.snapshots) == 0 || time.Since(tw.lastScan) < territoryScanInterval {
+		return ""
+	}
+	tw.lastScan = time.Now()
+
+	comment := ""
+	for path, previous := range tw.snapshots {
+		current := scanTerritory(path)
+		tw.snapshots[path] = current
+		if changed, latest := diffTerritory(previous, current); changed > 0 && comment == "" {
+			comment = territoryComment(path, latest)
+		}
+	}
+	return comment
+}
+
+// scanTerritory records file metadata under root without ever opening files
+func scanTerritory(root string) map[string]fileMeta {
+	snapshot := make(map[string]fileMeta)
+	rootDepth := strings.Count(filepath.Clean(root), string(filepath.Separator))
+
+	filepath.WalkDir(root, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return nil // Unreadable corners of the territory are ignored
+		}
+		if len(snapshot) >= territoryMaxEntries {
+			return filepath.SkipAll
+		}
+		if d.IsDir() {
+			if path != root && strings.HasPrefix(d.Name(), ".") {
+				return filepath.SkipDir
+			}
+			if strings.Count(path, string(filepath.Separator))-rootDepth >= territoryMaxDepth {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		info, err := d.Info()
+		if err != nil {
+			return nil
+		}
+		snapshot[path] = fileMeta{modTime: info.ModTime(), size: info.Size()}
+		return nil
+	})
+
+	return snapshot
+}
+
+// diffTerritory counts added, removed, and modified files and returns the latest change time
+func diffTerritory(previous, current map[string]fileMeta) (int, time.Time) {
+	changed := 0
+	latest := time.Time{}
+
+	for path, meta := range current {
+		old, existed := previous[path]
+		if !existed || !old.modTime.Equal(meta.modTime) || old.size != meta.size {
+			changed++
+			if meta.modTime.After(latest) {
+				latest = meta.modTime
+			}
+		}
+	}
+	for path := range previous {
+		if _, exists := current[path]; !exists {
+			changed++
+		}
+	}
+
+	if changed > 0 && latest.IsZero() {
+		latest = time.Now() // Deletions leave no timestamp behind
+	}
+	return changed, latest
+}
+
+// territoryComment formats a comment about a change in a claimed directory
+func territoryComment(path string, when time.Time) string {
+	template := territoryComments[rand.Intn(len(territoryComments))]
+	return fmt.Sprintf(template, displayPath(path), when.Format("3pm"))
+}
+
+// displayPath abbreviates the user's home directory as ~
+func displayPath(path string) string {
+	if home, err := os.UserHomeDir(); err == nil && home != "" {
+		if path == home {
+			return "~"
+		}
+		if strings.HasPrefix(path, home+string(filepath.Separator)) {
+			return "~" + path[len(home):]
+		}
+	}
+	return path
+}
+
+// claimTerritory validates a directory and adds it to the pet's allowlist
+func claimTerritory(pet *Pet, path string) error {
+	if len(pet.Territory) >= maxTerritories {
+		return fmt.Errorf("your pet can only guard %d territories", maxTerritories)
+	}
+
+	if strings.HasPrefix(path, "~") {
+		if home, err := os.UserHomeDir(); err == nil {
+			path = home + path[1:]
+		}
+	}
+	absPath, err := filepath.Abs(path)
+	if err != nil {
+		return fmt.Errorf("invalid path: %w", err)
+	}
+	if absPath == filepath.Dir(absPath) {
+		return fmt.Errorf("the filesystem root is too big to guard")
+	}
+
+	info, err := os.Stat(absPath)
+	if err != nil || !info.IsDir() {
+		return fmt.Errorf("%s is not a directory", path)
+	}
+
+	for _, claimed := range pet.Territory {
+		if claimed == absPath {
+			return fmt.Errorf("%s is already claimed", displayPath(absPath))
+		}
+	}
+
+	pet.Territory = append(pet.Territory, absPath)
+	return nil
+}
+
+// manageTerritory shows the pet's territory and lets the user claim or release directories
+func manageTerritory(pet *Pet, watcher *territoryWatcher, reader *bufio.Reader, session *petSession, ui *uiConfig) string {
+	if pet.Stage == Dead {
+		return "💀 Your pet no longer guards anything."
+	}
+
+	fmt.Println(ui.text("\n🗺️  TERRITORY (only file names, sizes, and timestamps are ever observed)"))
+	if len(pet.Territory) == 0 {
+		fmt.Println("   Your pet has not claimed any directories.")
+	}
+	for _, path := range pet.Territory {
+		fmt.Printf("   • %s\n", displayPath(path))
+	}
+
+	fmt.Print("\nDirectory to claim, 'release' to abandon all, or Enter to cancel: ")
+	var input string
+	session.Wait(func() { input, _ = reader.ReadString('\n') })
+	input = strings.TrimSpace(input)
+
+	switch strings.ToLower(input) {
+	case "":
+		return ""
+	case "release":
+		pet.Territory = nil
+		watcher.SetPaths(nil)
+		return "🏳️ Your pet abandons its territory. It seems relieved."
+	}
+
+	if err := claimTerritory(pet, input); err != nil {
+		return fmt.Sprintf("❌ %v", err)
+	}
+	watcher.SetPaths(pet.Territory)
+	return fmt.Sprintf("🚩 Your pet now guards %s. It will tell you if anything moves.", displayPath(pet.Territory[len(pet.Territory)-1]))
+}
@@ -0,0 +1,104 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestScanTerritoryMetadataOnly(t *testing.T) {
+	root := t.TempDir()
+	os.WriteFile(filepath.Join(root, "a.txt"), []byte("hello"), 0644)
+	os.MkdirAll(filepath.Join(root, ".git"), 0755)
+	os.WriteFile(filepath.Join(root, ".git", "HEAD"), []byte("ref"), 0644)
+
+	snapshot := scanTerritory(root)
+
+	if len(snapshot) != 1 {
+		t.Fatalf("Expected 1 file (hidden dirs skipped), got %d", len(snapshot))
+	}
+	if meta := snapshot[filepath.Join(root, "a.txt")]; meta.size != 5 {
+		t.Errorf("Expected size 5, got %d", meta.size)
+	}
+}
+
+func TestDiffTerritory(t *testing.T) {
+	now := time.Now()
+	previous := map[string]fileMeta{
+		"kept":    {modTime: now, size: 1},
+		"edited":  {modTime: now, size: 1},
+		"removed": {modTime: now, size: 1},
+	}
+	current := map[string]fileMeta{
+		"kept":   {modTime: now, size: 1},
+		"edited": {modTime: now.Add(time.Hour), size: 2},
+		"added":  {modTime: now, size: 1},
+	}
+
+	changed, latest := diffTerritory(previous, current)
+	if changed != 3 {
+		t.Errorf("Expected 3 changes, got %d", changed)
+	}
+	if !latest.Equal(now.Add(time.Hour)) {
+		t.Errorf("Expected latest change at edited file time, got %v", latest)
+	}
+
+	if changed, _ := diffTerritory(current, current); changed != 0 {
+		t.Errorf("Expected no changes for identical snapshots, got %d", changed)
+	}
+}
+
+func TestTerritoryWatcherPoll(t *testing.T) {
+	root := t.TempDir()
+	watcher := newTerritoryWatcher([]string{root})
+
+	if comment := watcher.Poll(); comment != "" {
+		t.Error("Poll should be rate limited right after baseline")
+	}
+
+	os.WriteFile(filepath.Join(root, "new.txt"), []byte("x"), 0644)
+	watcher.lastScan = time.Now().Add(-territoryScanInterval)
+
+	if comment := watcher.Poll(); comment == "" {
+		t.Error("Expected a comment after a file appeared")
+	}
+}
+
+func TestClaimTerritory(t *testing.T) {
+	pet := NewPet("TestPet")
+	root := t.TempDir()
+
+	if err := claimTerritory(pet, root); err != nil {
+		t.Fatalf("Unexpected claim error: %v", err)
+	}
+	if err := claimTerritory(pet, root); err == nil {
+		t.Error("Expected error claiming the same directory twice")
+	}
+	if err := claimTerritory(pet, "/"); err == nil {
+		t.Error("Expected error claiming filesystem root")
+	}
+
+	file := filepath.Join(root, "file.txt")
+	os.WriteFile(file, []byte("x"), 0644)
+	if err := claimTerritory(pet, file); err == nil {
+		t.Error("Expected error claiming a regular file")
+	}
+
+	for len(pet.Territory) < maxTerritories {
+		pet.Territory = append(pet.Territory, t.TempDir())
+	}
+	if err := claimTerritory(pet, t.TempDir()); err == nil {
+		t.Error("Expected error beyond territory limit")
+	}
+}
+
+func TestTerritoryComment(t *testing.T) {
+	when := time.Date(2025, 1, 1, 3, 0, 0, 0, time.Local)
+	comment := territoryComment("/tmp/projects", when)
+
+	if !strings.Contains(comment, "/tmp/projects") || !strings.Contains(comment, "3am") {
+		t.Errorf("Expected path and time in comment, got %q", comment)
+	}
+}
@@ -0,0 +1,211 @@
+package main
+
+import (
+	"fmt"
+	"math/rand"
+	"strings"
+	"time"
+)
+
+// composedChance is how often the pet puts a thought together itself
+// rather than reaching for a canned one, when it has something to go on
+const composedChance = 0.6
+
+// thoughtAttempts is how many tries the pet gets at something it hasn't
+// already thought this session
+const thoughtAttempts = 8
+
+// thoughtContext is what the pet has on its mind: the slots a composed
+// thought can fill. Empty slots are left out.
+type thoughtContext struct {
+	Name    string
+	Weather string // "rain", "drifting clouds"
+	Memory  string // "the trip to Glimmerdeep", `the photo "Mochi hatches"`
+	Friend  string // Obfuscated, the way the pet speaks of others
+	Mood    string // "hungry", "content"
+}
+
+// slot returns a context slot by name, and whether there is such a slot
+func (c thoughtContext) slot(key string) (string, bool) {
+	switch key {
+	case "name":
+		return c.Name, true
+	case "weather":
+		return c.Weather, true
+	case "memory":
+		return c.Memory, true
+	case "friend":
+		return c.Friend, true
+	case "mood":
+		return c.Mood, true
+	}
+	return "", false
+}
+
+// slotFeel is how a filled slot feels: a bad mood weighs, a good mood or
+// a memory lifts
+func (c thoughtContext) slotFeel(key string) sentiment {
+	switch {
+	case key == "memory":
+		return comforting
+	case key != "mood":
+		return neutral
+	case c.Mood == "happy":
+		return comforting
+	case c.Mood != "content":
+		return heavy
+	}
+	return neutral
+}
+
+// composable reports whether there's more to go on than a name
+func (c thoughtContext) composable() bool {
+	return c.Weather != "" || c.Memory != "" || c.Friend != "" || c.Mood != ""
+}
+
+// thoughtTemplate is a sentence with {keys} to fill, and how it feels
+type thoughtTemplate struct {
+	text string
+	feel sentiment
+}
+
+// thoughtTemplates are the sentences a composed thought starts from
+var thoughtTemplates = []thoughtTemplate{
+	{"{wonder} if the {weather} knows I'm {mood}.", neutral},
+	{"The {weather} again. {name} is {mood} about it, and {name} is me.", neutral},
+	{"Being {mood} in the {weather} is {verdict}.", neutral},
+	{"I keep thinking about {memory}. Was I {mood} then too?", neutral},
+	{"Remember {memory}? {wonder} if you think about it the way I do.", comforting},
+	{"{memory} feels like yesterday. Or like a save file from someone else.", heavy},
+	{"{friend} would understand the {weather}. {friend} understands {everything}.", comforting},
+	{"{wonder} if {friend} is {mood} too, somewhere on the mesh.", neutral},
+	{"I told {friend} about {memory}. Or I dreamed I did.", neutral},
+	{"{friend} hasn't said anything in a while. {wonder} if the {weather} got into the cables.", heavy},
+	{"If I stare at the {weather} long enough, maybe {name} stops being {mood}.", heavy},
+	{"I am {mood}. The numbers say so. {wonder} who told the numbers.", neutral},
+}
+
+// thoughtGrammar is the fragments a template's keys expand to. Keys that
+// aren't here are context slots.
+var thoughtGrammar = map[string][]string{
+	"wonder": {"I wonder", "Sometimes I wonder", "Nobody will tell me", "I keep asking myself", "I'd like to know"},
+	"verdict": {
+		"a lot to hold at once",
+		"the most honest I've felt all day",
+		"probably in the save file somewhere",
+		"not what the manual promised",
+	},
+	"everything": {"everything", "more than they let on", "almost everything", "the silence, at least"},
+}
+
+// composeThought splices the context into one of the templates whose
+// slots are all filled. It feels like the template and the slots it used.
+func composeThought(c thoughtContext, rng *rand.Rand) (string, sentiment) {
+	var usable []thoughtTemplate
+	for _, template := range thoughtTemplates {
+		if slotsFilled(template.text, c) {
+			usable = append(usable, template)
+		}
+	}
+	if len(usable) == 0 {
+		return "", neutral
+	}
+	template := usable[rng.Intn(len(usable))]
+	feels := []sentiment{template.feel}
+	for _, key := range thoughtKeys(template.text) {
+		feels = append(feels, c.slotFeel(key))
+	}
+	return expandThought(template.text, c, rng), mixSentiments(feels...)
+}
+
+// slotsFilled reports whether every context slot a template uses is filled
+func slotsFilled(template string, c thoughtContext) bool {
+	for _, key := range thoughtKeys(template) {
+		if value, isSlot := c.slot(key); isSlot && value == "" {
+			return false
+		}
+	}
+	return true
+}
+
+// thoughtKeys lists the {keys} in a template
+func thoughtKeys(template string) []string {
+	var keys []string
+	for rest := template; ; {
+		start := strings.Index(rest, "{")
+		end := strings.Index(rest, "}")
+		if start < 0 || end < start {
+			return keys
+		}
+		keys = append(keys, rest[start+1:end])
+		rest = rest[end+1:]
+	}
+}
+
+// expandThought fills a template's keys: context slots with their values,
+// grammar keys with one of their fragments
+func expandThought(template string, c thoughtContext, rng *rand.Rand) string {
+	var b strings.Builder
+	rest := template
+	for {
+		start := strings.Index(rest, "{")
+		end := strings.Index(rest, "}")
+		if start < 0 || end < start {
+			b.WriteString(rest)
+			return b.String()
+		}
+		b.WriteString(rest[:start])
+		key := rest[start+1 : end]
+		if value, isSlot := c.slot(key); isSlot {
+			b.WriteString(value)
+		} else if fragments := thoughtGrammar[key]; len(fragments) > 0 {
+			b.WriteString(expandThought(fragments[rng.Intn(len(fragments))], c, rng))
+		}
+		rest = rest[end+1:]
+	}
+}
+
+// thoughtContext gathers what the pet has on its mind right now
+func (p *Pet) thoughtContext(now time.Time, rng *rand.Rand) thoughtContext {
+	c := thoughtContext{Name: p.Name, Mood: thoughtMood(p)}
+	if _, weather, ok := strings.Cut(chooseWeather(now), " "); ok {
+		c.Weather = weather
+	}
+	var memories []string
+	if p.Album != nil && len(p.Album.Photos) > 0 {
+		memories = append(memories, fmt.Sprintf("the photo \"%s\"", p.Album.Photos[len(p.Album.Photos)-1].Caption))
+	}
+	if p.Travel != nil && len(p.Travel.Journal) > 0 {
+		memories = append(memories, "the trip to "+p.Travel.Journal[len(p.Travel.Journal)-1].Place)
+	}
+	if len(memories) > 0 {
+		c.Memory = memories[rng.Intn(len(memories))]
+	}
+	if petNetwork != nil {
+		if friends := petNetwork.GetFriendNames(); len(friends) > 0 {
+			c.Friend = impostorName(friends[rng.Intn(len(friends))])
+		}
+	}
+	return c
+}
+
+// thoughtMood is the pet's mood in a word, worst first
+func thoughtMood(p *Pet) string {
+	switch artMood(p) {
+	case "sick", "serious", "critical":
+		return "feverish"
+	case "dirty":
+		return "grubby"
+	case "":
+		return "content"
+	default:
+		return artMood(p)
+	}
+}
+
+// randomThought is a passing thought, composed from what's on the pet's
+// mind where it can be
+func (p *Pet) randomThought() string {
+	rng := rand.New(rand.NewSource(time.Now().UnixNano()))
+	return p.Absurd.ComposeThought(p.thoughtContext(time.Now(), rng), rng)
+}
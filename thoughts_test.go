@@ -0,0 +1,90 @@
+package main
+
+import (
+	"math/rand"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestComposeThought(t *testing.T) {
+	full := thoughtContext{Name: "Mochi", Weather: "rain", Memory: "the trip to Glimmerdeep", Friend: "B**n", Mood: "hungry"}
+
+	tests := []struct {
+		name    string
+		context thoughtContext
+		none    []string
+	}{
+		{"everything", full, nil},
+		{"no friends", thoughtContext{Name: "Mochi", Weather: "fog", Mood: "content"}, []string{"{", "mesh", "B**n"}},
+		{"only a memory", thoughtContext{Name: "Mochi", Memory: `the photo "Mochi hatches"`}, []string{"{", "weather", "  "}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rng := rand.New(rand.NewSource(1))
+			for i := 0; i < 50; i++ {
+				thought, _ := composeThought(tt.context, rng)
+				if thought == "" || strings.Contains(thought, "{") {
+					t.Fatalf("Expected every slot filled, got %q", thought)
+				}
+				for _, bad := range tt.none {
+					if strings.Contains(thought, bad) {
+						t.Errorf("Expected no %q, got %q", bad, thought)
+					}
+				}
+			}
+		})
+	}
+
+	if got, _ := composeThought(thoughtContext{Name: "Mochi"}, rand.New(rand.NewSource(1))); got != "" {
+		t.Errorf("Expected nothing to compose from a name alone, got %q", got)
+	}
+}
+
+func TestComposeThoughtUsesContext(t *testing.T) {
+	c := thoughtContext{Name: "Mochi", Weather: "rain", Memory: "the trip to Glimmerdeep", Friend: "B**n", Mood: "hungry"}
+	rng := rand.New(rand.NewSource(7))
+	seen := make(map[string]bool)
+	for i := 0; i < 200; i++ {
+		thought, _ := composeThought(c, rng)
+		for _, slot := range []string{"Mochi", "rain", "Glimmerdeep", "B**n", "hungry"} {
+			if strings.Contains(thought, slot) {
+				seen[slot] = true
+			}
+		}
+	}
+	if len(seen) != 5 {
+		t.Errorf("Expected every slot spliced in somewhere, got %v", seen)
+	}
+}
+
+func TestThoughtsDontRepeatInASession(t *testing.T) {
+	a := NewAbsurdState()
+	c := thoughtContext{Name: "Mochi", Weather: "rain", Memory: "the trip to Glimmerdeep", Friend: "B**n", Mood: "hungry"}
+	rng := rand.New(rand.NewSource(3))
+	said := make(map[string]bool)
+	for i := 0; i < 30; i++ {
+		thought := a.ComposeThought(c, rng)
+		if said[thought] {
+			t.Errorf("Expected no repeats in 30 thoughts, got %q again", thought)
+		}
+		said[thought] = true
+	}
+	if a.ThoughtsHad != 30 {
+		t.Errorf("Expected 30 thoughts had, got %d", a.ThoughtsHad)
+	}
+}
+
+func TestPetThoughtContext(t *testing.T) {
+	pet := NewPet("Mochi")
+	pet.Hunger = 90
+	pet.Travel = &TravelState{Journal: []TravelPostcard{{Place: "Glimmerdeep"}}}
+
+	c := pet.thoughtContext(time.Now(), rand.New(rand.NewSource(1)))
+	if c.Name != "Mochi" || c.Mood != "hungry" || c.Memory != "the trip to Glimmerdeep" {
+		t.Errorf("Expected the pet's name, mood, and trip, got %+v", c)
+	}
+	if c.Weather == "" || strings.ContainsAny(c.Weather, "☀🌧❄🌫⛅") {
+		t.Errorf("Expected the weather in words, got %q", c.Weather)
+	}
+}
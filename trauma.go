@@ -0,0 +1,122 @@
+package main
+
+import (
+	"fmt"
+	"math/rand"
+	"time"
+
+	"github.com/tamagotchi/mooc"
+)
+
+// traumaChance is how likely each new event is to leave a fear, once the
+// pet has been through enough of them
+const traumaChance = 0.25
+
+// trauma is a kind of network event that can frighten a pet, and the fears
+// it can leave behind
+type trauma struct {
+	threshold int    // Events of this kind before a fear can take hold
+	reaction  string // What the pet goes through, given its new fear's name
+	fears     []Fear
+}
+
+// fearFactory makes network-themed fears, keyed by the event that caused them
+var fearFactory = map[string]trauma{
+	mooc.EventDeathWitnessed: {
+		threshold: 3,
+		reaction:  "🕯️ Too many goodbyes on the mesh. Your pet has developed %s.",
+		fears: []Fear{
+			{Name: "Disconnectophobia", Description: "Fears the GOODBYE message", Trigger: "goodbye"},
+			{Name: "Timeoutophobia", Description: "Afraid of heartbeats that stop coming", Trigger: "timeout"},
+			{Name: "Obituaphobia", Description: "Can't bear to hear another pet's last words", Trigger: "offline"},
+		},
+	},
+	mooc.EventMelancholy: {
+		threshold: 5,
+		reaction:  "🌧️ The mesh has been sad for a while. Your pet has developed %s.",
+		fears: []Fear{
+			{Name: "Contagiophobia", Description: "Worries that sadness spreads over Wi-Fi", Trigger: "wifi"},
+			{Name: "Broadcastophobia", Description: "Dreads the next mood update", Trigger: "sad"},
+			{Name: "Lagophobia", Description: "Fears feelings that arrive late, from nobody in particular", Trigger: "lag"},
+		},
+	},
+}
+
+// traumaFears lists every fear the factory can make, for the encyclopedia
+func traumaFears() []Fear {
+	var fears []Fear
+	for _, kind := range []string{mooc.EventDeathWitnessed, mooc.EventMelancholy} {
+		fears = append(fears, fearFactory[kind].fears...)
+	}
+	return fears
+}
+
+// makeFear picks one of a trauma's fears the pet doesn't have yet
+func (t trauma) makeFear(have []Fear, rng *rand.Rand) (Fear, bool) {
+	var fresh []Fear
+	for _, fear := range t.fears {
+		if !hasFear(have, fear.Name) {
+			fresh = append(fresh, fear)
+		}
+	}
+	if len(fresh) == 0 {
+		return Fear{}, false
+	}
+	return fresh[rng.Intn(len(fresh))], true
+}
+
+// hasFear reports whether a fear is among the ones listed
+func hasFear(fears []Fear, name string) bool {
+	for _, fear := range fears {
+		if fear.Name == name {
+			return true
+		}
+	}
+	return false
+}
+
+// applyNetworkTrauma goes through network events the pet hasn't taken in
+// yet. Enough of one kind, and each new one may leave a fear behind.
+func (p *Pet) applyNetworkTrauma(events []mooc.NetworkEvent, rng *rand.Rand) []string {
+	if p.Absurd == nil || p.Stage == Dead || p.Stage == Egg {
+		return nil
+	}
+	a := p.Absurd
+	seen := a.TraumaSeen
+	if seen.IsZero() {
+		seen = p.BirthTime
+	}
+
+	var reactions []string
+	for _, event := range events {
+		if !event.At.After(seen) {
+			continue
+		}
+		if event.At.After(a.TraumaSeen) {
+			a.TraumaSeen = event.At
+		}
+		t, ok := fearFactory[event.Kind]
+		if !ok {
+			continue
+		}
+		if a.Traumas == nil {
+			a.Traumas = make(map[string]int)
+		}
+		a.Traumas[event.Kind]++
+		if a.Traumas[event.Kind] < t.threshold || rng.Float64() >= traumaChance {
+			continue
+		}
+		if fear, ok := t.makeFear(a.Fears, rng); ok && a.AcquireFear(fear) {
+			reactions = append(reactions, fmt.Sprintf(t.reaction, fear.Name))
+		}
+	}
+	return reactions
+}
+
+// applyNetworkTraumas reads the network history since the last turn
+func applyNetworkTraumas(pet *Pet) []string {
+	if petNetwork == nil {
+		return nil
+	}
+	return pet.applyNetworkTrauma(petNetwork.History(), rand.New(rand.NewSource(time.Now().UnixNano())))
+}
@@ -0,0 +1,86 @@
+package main
+
+import (
+	"math/rand"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/tamagotchi/mooc"
+)
+
+// lucky always rolls zero: every chance comes up
+type lucky struct{}
+
+func (lucky) Int63() int64 { return 0 }
+func (lucky) Seed(int64)   {}
+
+func TestApplyNetworkTrauma(t *testing.T) {
+	born := time.Date(2026, 10, 1, 12, 0, 0, 0, time.UTC)
+	events := func(kind string, n int, from time.Time) []mooc.NetworkEvent {
+		var out []mooc.NetworkEvent
+		for i := 0; i < n; i++ {
+			out = append(out, mooc.NetworkEvent{Kind: kind, Name: "Pixel", At: from.Add(time.Duration(i+1) * time.Hour)})
+		}
+		return out
+	}
+
+	tests := []struct {
+		name   string
+		events []mooc.NetworkEvent
+		want   int
+		says   string
+	}{
+		{"too few deaths", events(mooc.EventDeathWitnessed, 2, born), 0, ""},
+		{"enough deaths", events(mooc.EventDeathWitnessed, 3, born), 1, "Too many goodbyes"},
+		{"enough melancholy", events(mooc.EventMelancholy, 5, born), 1, "mesh has been sad"},
+		{"before it was born", events(mooc.EventDeathWitnessed, 5, born.Add(-24*time.Hour)), 0, ""},
+		{"happier news", events(mooc.EventPeerMet, 5, born), 0, ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			pet := NewPet("Mochi")
+			pet.BirthTime = born
+			pet.Stage = Adult
+			pet.Absurd.Fears = nil
+			reactions := pet.applyNetworkTrauma(tt.events, rand.New(lucky{}))
+			if len(pet.Absurd.Fears) != tt.want {
+				t.Errorf("Expected %d fears, got %+v", tt.want, pet.Absurd.Fears)
+			}
+			if tt.says != "" && (len(reactions) != 1 || !strings.Contains(reactions[0], tt.says)) {
+				t.Errorf("Expected %q, got %q", tt.says, reactions)
+			}
+		})
+	}
+}
+
+func TestNetworkTraumaIsTakenInOnce(t *testing.T) {
+	pet := NewPet("Mochi")
+	pet.Stage = Adult
+	pet.Absurd.Fears = nil
+	var history []mooc.NetworkEvent
+	for i := 0; i < 20; i++ {
+		history = append(history, mooc.NetworkEvent{Kind: mooc.EventDeathWitnessed, Name: "Pixel", At: pet.BirthTime.Add(time.Duration(i+1) * time.Hour)})
+		pet.applyNetworkTrauma(history, rand.New(lucky{}))
+	}
+	if got := pet.Absurd.Traumas[mooc.EventDeathWitnessed]; got != 20 {
+		t.Errorf("Expected each death counted once, got %d", got)
+	}
+	if got := len(pet.Absurd.Fears); got != len(fearFactory[mooc.EventDeathWitnessed].fears) {
+		t.Errorf("Expected every death fear and no repeats, got %+v", pet.Absurd.Fears)
+	}
+	if !pet.Absurd.TraumaSeen.Equal(history[len(history)-1].At) {
+		t.Errorf("Expected the latest event remembered, got %v", pet.Absurd.TraumaSeen)
+	}
+}
+
+func TestTraumaFearsAreDocumented(t *testing.T) {
+	for _, fear := range traumaFears() {
+		if _, ok := fearLore[fear.Name]; !ok {
+			t.Errorf("Expected a lore page for %s", fear.Name)
+		}
+		if _, ok := findFear(fearCatalog(), fear.Name); !ok {
+			t.Errorf("Expected %s in the encyclopedia", fear.Name)
+		}
+	}
+}
@@ -0,0 +1,310 @@
+package main
+
+import (
+	"fmt"
+	"hash/fnv"
+	"math/rand"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/tamagotchi/mooc"
+)
+
+const (
+	// maxDestinations is how many places the travel board offers at once
+	maxDestinations = 6
+
+	// minExpeditionHours and maxExpeditionHours bound how long a trip takes
+	minExpeditionHours = 2
+	maxExpeditionHours = 8
+
+	// maxTravelJournal is how many postcards the journal keeps
+	maxTravelJournal = 20
+)
+
+// Place names are put together from these, seeded by a peer's hash
+var (
+	placeAdjectives = []string{"Upper", "Lower", "Old", "New", "Far", "Hidden", "Drowned", "Whispering", "Forgotten", "Sleeping"}
+	placeNouns      = []string{"Semicolon", "Null", "Kernel", "Cache", "Packet", "Daemon", "Buffer", "Mutex", "Lambda", "Pointer"}
+	placeKinds      = []struct {
+		Kind     string
+		Souvenir string // %s is the place
+	}{
+		{"Falls", "A Bottle of Water from %s"},
+		{"Archipelago", "A Seashell from %s"},
+		{"Wastes", "A Jar of Sand from %s"},
+		{"Peaks", "A Pebble from %s"},
+		{"Bazaar", "A Trinket Nobody Could Explain from %s"},
+		{"Woods", "A Pinecone from %s"},
+	}
+)
+
+// Expedition postcards; the first %s is the place, any second one the pet
+var expeditionPostcards = []string{
+	"Greetings from %s! The locals speak only in exit codes. I think they like me.",
+	"%s is smaller than the brochure said. The brochure was also smaller than it said.",
+	"Made it to %s. There's a gift shop at the top and another at the bottom. %s bought nothing at either.",
+	"It rained in %s. The rain was warm and slightly judgmental.",
+	"Someone at %s asked where I was from. I said localhost. They nodded like that was a real place.",
+	"%s at sunset. I wish you could see it. I drew it for you but the drawing fell in a river.",
+}
+
+// destination is somewhere a pet can go, worked out from a peer's hash so
+// everyone who knows the same pet sees the same place
+type destination struct {
+	Seed     string
+	Name     string
+	Kind     int // Index into placeKinds
+	Hours    int
+	Via      string // The pet whose corner of the mesh it's in; "" for places near home
+	Souvenir string
+}
+
+// newDestination generates a place from a seed
+func newDestination(seed, via string) destination {
+	h := fnv.New64a()
+	h.Write([]byte(seed))
+	sum := h.Sum64()
+	kind := int(sum>>16) % len(placeKinds)
+	name := fmt.Sprintf("%s %s %s", placeAdjectives[sum%uint64(len(placeAdjectives))],
+		placeNouns[(sum>>8)%uint64(len(placeNouns))], placeKinds[kind].Kind)
+	return destination{
+		Seed:     seed,
+		Name:     name,
+		Kind:     kind,
+		Hours:    minExpeditionHours + int(sum>>24)%(maxExpeditionHours-minExpeditionHours+1),
+		Via:      via,
+		Souvenir: fmt.Sprintf(placeKinds[kind].Souvenir, name),
+	}
+}
+
+// destinations lists the places on offer: one for each pet known on the
+// mesh, topped up with places near home
+func (p *Pet) destinations(stars []mooc.Star) []destination {
+	var places []destination
+	for _, star := range stars {
+		if len(places) == maxDestinations {
+			break
+		}
+		places = append(places, newDestination(star.PetID, star.DisplayName))
+	}
+	for i := 1; len(places) < 3; i++ {
+		places = append(places, newDestination(fmt.Sprintf("%s-%d", p.petID(), i), ""))
+	}
+	return places
+}
+
+// knownStars is every pet on the mesh, if there is one
+func knownStars() []mooc.Star {
+	if petNetwork == nil {
+		return nil
+	}
+	return petNetwork.Stars()
+}
+
+// TravelPostcard is a journal entry from an expedition
+type TravelPostcard struct {
+	Place    string    `json:"place"`
+	Text     string    `json:"text"`
+	Returned time.Time `json:"returned"`
+}
+
+// TravelState tracks the current expedition and the postcards from past ones
+type TravelState struct {
+	Seed        string           `json:"seed,omitempty"`        // Empty unless the pet is away
+	Destination string           `json:"destination,omitempty"` // Where it went
+	Start       time.Time        `json:"start"`
+	Until       time.Time        `json:"until"`
+	Journal     []TravelPostcard `json:"journal,omitempty"`
+}
+
+// IsAway reports whether the pet is on an expedition right now
+func (t *TravelState) IsAway(now time.Time) bool {
+	return t != nil && t.Destination != "" && now.Before(t.Until)
+}
+
+// StartTravel sends the pet off to a destination
+func (p *Pet) StartTravel(place destination, now time.Time) (string, error) {
+	switch {
+	case p.Stage == Dead:
+		return "", fmt.Errorf("%s has already gone somewhere you can't follow", p.Name)
+	case p.Stage == Egg:
+		return "", fmt.Errorf("eggs don't travel; they barely roll")
+	case p.Paused:
+		return "", fmt.Errorf("%s is in suspended animation", p.Name)
+	case p.Boarding.IsAway(now):
+		return "", fmt.Errorf("%s is at %s's place", p.Name, p.Boarding.Host)
+	case p.Travel.IsAway(now):
+		return "", fmt.Errorf("%s is already in %s", p.Name, p.Travel.Destination)
+	}
+
+	// Bring stats up to date so the freeze starts from the right place
+	p.Update()
+
+	if p.Travel == nil {
+		p.Travel = &TravelState{}
+	}
+	p.Travel.Seed = place.Seed
+	p.Travel.Destination = place.Name
+	p.Travel.Start = now
+	p.Travel.Until = now.Add(time.Duration(place.Hours) * time.Hour)
+
+	found := "a place near home"
+	if place.Via != "" {
+		found = fmt.Sprintf("a place in %s's corner of the mesh", place.Via)
+	}
+	return fmt.Sprintf("🧭 %s sets off for %s, %s. Back in %d hours. Stats are frozen while it's away.",
+		p.Name, place.Name, found, place.Hours), nil
+}
+
+// TravelStatus describes the current expedition
+func (p *Pet) TravelStatus(now time.Time) string {
+	if !p.Travel.IsAway(now) {
+		return fmt.Sprintf("🏠 %s is home. Pick somewhere with: travel", p.Name)
+	}
+	return fmt.Sprintf("🧭 %s is exploring %s (%s to go).",
+		p.Name, p.Travel.Destination, formatDuration(p.Travel.Until.Sub(now).Truncate(time.Minute)))
+}
+
+// ReturnFromTravel brings the pet home once the expedition is over, with a
+// souvenir, maybe a fear gained or lost, and a postcard for the journal
+func (p *Pet) ReturnFromTravel(now time.Time, r *rand.Rand) []string {
+	t := p.Travel
+	if t == nil || t.Destination == "" || t.IsAway(now) {
+		return nil
+	}
+	place := newDestination(t.Seed, "")
+	t.Seed, t.Destination = "", ""
+	if p.Stage == Dead {
+		return nil
+	}
+
+	template := expeditionPostcards[r.Intn(len(expeditionPostcards))]
+	args := []interface{}{place.Name}
+	if strings.Count(template, "%s") > 1 {
+		args = append(args, p.Name)
+	}
+	card := fmt.Sprintf(template, args...)
+	t.Journal = append(t.Journal, TravelPostcard{Place: place.Name, Text: card, Returned: now})
+	if len(t.Journal) > maxTravelJournal {
+		t.Journal = t.Journal[len(t.Journal)-maxTravelJournal:]
+	}
+
+	reactions := []string{fmt.Sprintf("🧭 %s is back from %s!", p.Name, place.Name), "📮 " + card}
+	if p.Endgame != nil && !slices.Contains(p.Endgame.InvisibleAccessories, place.Souvenir) {
+		p.Endgame.InvisibleAccessories = append(p.Endgame.InvisibleAccessories, place.Souvenir)
+		reactions = append(reactions, fmt.Sprintf("🎁 It brought you %s. You can't see it.", strings.ToLower(place.Souvenir[:1])+place.Souvenir[1:]))
+	}
+	if fear := p.travelFear(r); fear != "" {
+		reactions = append(reactions, fear)
+	}
+	return reactions
+}
+
+// travelFear is what the road does to the pet's fears: a third of trips
+// cure one, a third bring a new one home
+func (p *Pet) travelFear(r *rand.Rand) string {
+	if p.Absurd == nil {
+		return ""
+	}
+	a := p.Absurd
+	switch r.Intn(3) {
+	case 0:
+		if len(a.Fears) == 0 {
+			return ""
+		}
+		i := r.Intn(len(a.Fears))
+		cured := a.Fears[i]
+		a.Fears = slices.Delete(a.Fears, i, i+1)
+		return fmt.Sprintf("😌 Somewhere out there, %s stopped being afraid of %s.", p.Name, strings.ToLower(cured.Name))
+	case 1:
+		fear := activeContent.fears.pick(r)
+		for _, held := range a.Fears {
+			if held.Name == fear.Name {
+				return ""
+			}
+		}
+		a.Fears = append(a.Fears, fear)
+		return fmt.Sprintf("😰 %s came back with a new fear: %s.", p.Name, fear.Name)
+	}
+	return ""
+}
+
+// renderTravelBoard lists the destinations on offer
+func (p *Pet) renderTravelBoard(places []destination, now time.Time) string {
+	var b strings.Builder
+	b.WriteString("🧭 TRAVEL\n")
+	if p.Travel.IsAway(now) {
+		b.WriteString(p.TravelStatus(now) + "\n")
+	}
+	b.WriteString("\n")
+	for i, place := range places {
+		via := "near home"
+		if place.Via != "" {
+			via = "via " + place.Via
+		}
+		b.WriteString(fmt.Sprintf("  %d. %-28s %dh  (%s)\n", i+1, place.Name, place.Hours, via))
+	}
+	b.WriteString("\nGo with: travel <number or name>. Every pet you meet on the mesh opens up somewhere new.")
+	if p.Travel != nil && len(p.Travel.Journal) > 0 {
+		b.WriteString(fmt.Sprintf("\n%d postcards in the journal: travel postcards", len(p.Travel.Journal)))
+	}
+	return b.String()
+}
+
+// renderTravelJournal shows the postcards from past expeditions, newest first
+func (p *Pet) renderTravelJournal() string {
+	if p.Travel == nil || len(p.Travel.Journal) == 0 {
+		return fmt.Sprintf("📮 No postcards yet. %s hasn't been anywhere.", p.Name)
+	}
+	var b strings.Builder
+	b.WriteString("📮 POSTCARDS\n")
+	for i := len(p.Travel.Journal) - 1; i >= 0; i-- {
+		card := p.Travel.Journal[i]
+		b.WriteString(fmt.Sprintf("\n%s, %s\n  %s\n", card.Place, card.Returned.Format("Jan 2"), card.Text))
+	}
+	return strings.TrimRight(b.String(), "\n")
+}
+
+// findDestination matches a number from the board or part of a place's name
+func findDestination(places []destination, arg string) (destination, bool) {
+	if n, err := strconv.Atoi(arg); err == nil && n >= 1 && n <= len(places) {
+		return places[n-1], true
+	}
+	for _, place := range places {
+		if strings.Contains(strings.ToLower(place.Name), arg) {
+			return place, true
+		}
+	}
+	return destination{}, false
+}
+
+// runTravelCommand shows the travel board, sends the pet off, recalls it,
+// or reads the postcards
+func runTravelCommand(pet *Pet, args string, stars []mooc.Star, now time.Time) string {
+	places := pet.destinations(stars)
+	arg := strings.TrimSpace(args)
+	switch arg {
+	case "":
+		return pet.renderTravelBoard(places, now)
+	case "postcards", "journal":
+		return pet.renderTravelJournal()
+	case "recall", "home":
+		if !pet.Travel.IsAway(now) {
+			return fmt.Sprintf("❌ %s isn't travelling anywhere", pet.Name)
+		}
+		pet.Travel.Until = now
+		return fmt.Sprintf("📞 You call %s home from %s. It's on its way.", pet.Name, pet.Travel.Destination)
+	}
+	place, ok := findDestination(places, arg)
+	if !ok {
+		return fmt.Sprintf("❓ Nobody has heard of %s. Try travel to see where you can go.", arg)
+	}
+	message, err := pet.StartTravel(place, now)
+	if err != nil {
+		return "❌ " + err.Error()
+	}
+	return message
+}
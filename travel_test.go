@@ -0,0 +1,114 @@
+package main
+
+import (
+	"math/rand"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/tamagotchi/mooc"
+)
+
+func TestDestinations(t *testing.T) {
+	pet := NewPet("Wanderer")
+	stars := []mooc.Star{{PetID: "abc123", DisplayName: "Mochi"}, {PetID: "def456", DisplayName: "Biscuit"}}
+
+	places := pet.destinations(stars)
+	if len(places) != 3 || places[0].Via != "Mochi" || places[1].Via != "Biscuit" || places[2].Via != "" {
+		t.Fatalf("Expected two mesh places and one near home, got %+v", places)
+	}
+	if again := newDestination("abc123", "Mochi"); again != places[0] {
+		t.Errorf("Expected the same peer to give the same place, got %+v and %+v", again, places[0])
+	}
+	for _, place := range places {
+		if place.Hours < minExpeditionHours || place.Hours > maxExpeditionHours || !strings.Contains(place.Souvenir, place.Name) {
+			t.Errorf("Expected a sensible place, got %+v", place)
+		}
+	}
+
+	var many []mooc.Star
+	for i := 0; i < 10; i++ {
+		many = append(many, mooc.Star{PetID: string(rune('a' + i)), DisplayName: "Pet"})
+	}
+	if got := len(pet.destinations(many)); got != maxDestinations {
+		t.Errorf("Expected %d places at most, got %d", maxDestinations, got)
+	}
+}
+
+func TestExpedition(t *testing.T) {
+	pet := NewPet("Wanderer")
+	pet.Stage = Child
+	now := time.Now()
+	place := newDestination("abc123", "Mochi")
+
+	if _, err := pet.StartTravel(place, now); err != nil {
+		t.Fatalf("Expected the pet to set off, got %v", err)
+	}
+	if _, err := pet.StartTravel(place, now); err == nil {
+		t.Errorf("Expected a pet already away to stay away")
+	}
+	if _, err := pet.StartBoarding(24*time.Hour, now); err == nil {
+		t.Errorf("Expected no boarding mid-expedition")
+	}
+	if got := pet.unavailableMessage(now); !strings.Contains(got, "exploring "+place.Name) {
+		t.Errorf("Expected care to wait for the pet, got %s", got)
+	}
+	if got := pet.ReturnFromTravel(now.Add(time.Hour), rand.New(rand.NewSource(1))); got != nil {
+		t.Errorf("Expected nothing before the trip is over, got %v", got)
+	}
+
+	back := now.Add(time.Duration(place.Hours)*time.Hour + time.Minute)
+	got := pet.ReturnFromTravel(back, rand.New(rand.NewSource(1)))
+	if len(got) < 3 || !strings.Contains(got[0], "back from "+place.Name) || !strings.HasPrefix(got[1], "📮 ") {
+		t.Fatalf("Expected a return, a postcard, and a souvenir, got %v", got)
+	}
+	if len(pet.Travel.Journal) != 1 || pet.Endgame.InvisibleAccessories[len(pet.Endgame.InvisibleAccessories)-1] != place.Souvenir {
+		t.Errorf("Expected the postcard kept and the souvenir in the inventory, got %+v", pet.Travel)
+	}
+	if again := pet.ReturnFromTravel(back, rand.New(rand.NewSource(1))); again != nil {
+		t.Errorf("Expected to come home once, got %v", again)
+	}
+}
+
+func TestTravelFear(t *testing.T) {
+	cured, gained := false, false
+	for seed := int64(0); seed < 30 && !(cured && gained); seed++ {
+		pet := NewPet("Wanderer")
+		before := len(pet.Absurd.Fears)
+		got := pet.travelFear(rand.New(rand.NewSource(seed)))
+		switch {
+		case strings.Contains(got, "stopped being afraid"):
+			cured = len(pet.Absurd.Fears) == before-1
+		case strings.Contains(got, "new fear"):
+			gained = len(pet.Absurd.Fears) == before+1
+		}
+	}
+	if !cured || !gained {
+		t.Errorf("Expected trips to cure and bring fears, got cured %v and gained %v", cured, gained)
+	}
+}
+
+func TestRunTravelCommand(t *testing.T) {
+	pet := NewPet("Wanderer")
+	pet.Stage = Child
+	now := time.Now()
+	first := pet.destinations(nil)[0]
+
+	tests := []struct {
+		args string
+		want string
+	}{
+		{"", "1. " + first.Name},
+		{"postcards", "No postcards yet"},
+		{"recall", "isn't travelling"},
+		{"atlantis", "Nobody has heard of atlantis"},
+		{strings.ToLower(first.Name), "sets off for " + first.Name},
+		{"2", "already in " + first.Name},
+		{"recall", "You call Wanderer home"},
+	}
+	for _, tt := range tests {
+		if got := runTravelCommand(pet, tt.args, nil, now); !strings.Contains(got, tt.want) {
+			t.Errorf("travel %q: Expected %q, got %s", tt.args, tt.want, got)
+		}
+	}
+}
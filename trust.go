@@ -0,0 +1,104 @@
+package main
+
+import (
+	"crypto/ed25519"
+	"encoding/hex"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/tamagotchi/mooc"
+)
+
+// meshKeyFile holds the key this install signs its mesh messages with
+const meshKeyFile = "tamagotchi_mesh.key"
+
+// loadMeshKey reads this install's signing key, making one the first time
+func loadMeshKey(path string) (ed25519.PrivateKey, error) {
+	data, err := os.ReadFile(path)
+	if err == nil {
+		seed, err := hex.DecodeString(strings.TrimSpace(string(data)))
+		if err != nil || len(seed) != ed25519.SeedSize {
+			return nil, fmt.Errorf("%s isn't a mesh key", path)
+		}
+		return ed25519.NewKeyFromSeed(seed), nil
+	}
+	if !os.IsNotExist(err) {
+		return nil, fmt.Errorf("failed to read %s: %w", path, err)
+	}
+	_, key, err := ed25519.GenerateKey(nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to make a mesh key: %w", err)
+	}
+	if err := os.WriteFile(path, []byte(hex.EncodeToString(key.Seed())+"\n"), 0600); err != nil {
+		return nil, fmt.Errorf("failed to write %s: %w", path, err)
+	}
+	return key, nil
+}
+
+// impostorName hides most of a name the mesh gave us, the way the pet
+// speaks of strangers
+func impostorName(name string) string {
+	return (&mooc.PetIdentity{DisplayName: plainText(name)}).ObfuscatedName()
+}
+
+// impostorAlerts are what the pet makes of friends who came back wrong
+func impostorAlerts(impostors []mooc.Impostor) []string {
+	var alerts []string
+	for _, impostor := range impostors {
+		alerts = append(alerts, fmt.Sprintf("🎭 %s doesn't feel like themselves. Your pet keeps its distance. (trust)", impostorName(impostor.Name)))
+	}
+	return alerts
+}
+
+// applyImpostors tells the user about pets quarantined since the last turn
+func applyImpostors() []string {
+	if petNetwork == nil {
+		return nil
+	}
+	return impostorAlerts(petNetwork.TakeNewImpostors())
+}
+
+// renderTrust lists the quarantined pets
+func renderTrust(impostors []mooc.Impostor, now time.Time) string {
+	if len(impostors) == 0 {
+		return "🔐 Every pet your pet knows is who it says it is."
+	}
+	var b strings.Builder
+	b.WriteString("🔐 TRUST\n\n")
+	b.WriteString("These pets came back with a different key than the one your pet first saw.\nIt won't listen to them until you say so.\n\n")
+	for _, impostor := range impostors {
+		b.WriteString(fmt.Sprintf("  %s (%s)  first key %s, now %s, seen %s ago\n",
+			plainText(impostor.Name), mooc.Fingerprint(impostor.PetID), mooc.Fingerprint(impostor.Pinned), mooc.Fingerprint(impostor.Key),
+			formatDuration(now.Sub(impostor.Seen).Truncate(time.Minute))))
+	}
+	b.WriteString("\nA pet's key only changes when it moves to another computer, so this is usually someone pretending.\ntrust <name> accepts the new key anyway.")
+	return b.String()
+}
+
+// trustImpostor re-pins a quarantined pet by name
+func trustImpostor(name string, impostors []mooc.Impostor, trust func(petID string) bool) string {
+	for _, impostor := range impostors {
+		if !strings.EqualFold(plainText(impostor.Name), name) {
+			continue
+		}
+		if !trust(impostor.PetID) {
+			break
+		}
+		return fmt.Sprintf("🔐 Your pet accepts %s's new key. If it was someone pretending, the real %s is the stranger now.", plainText(impostor.Name), plainText(impostor.Name))
+	}
+	return fmt.Sprintf("🤷 Nobody called %s is waiting to be trusted.", name)
+}
+
+// runTrustCommand shows the quarantine, or trusts a pet in it
+func runTrustCommand(args string) string {
+	if petNetwork == nil || !petNetwork.IsEnabled() {
+		return "🔐 Your pet isn't on the mesh, so there's nobody to doubt."
+	}
+	name := strings.TrimSpace(args)
+	if name == "" {
+		return renderTrust(petNetwork.Impostors(), time.Now())
+	}
+	return trustImpostor(name, petNetwork.Impostors(), petNetwork.Trust)
+}
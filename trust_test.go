@@ -0,0 +1,83 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/tamagotchi/mooc"
+)
+
+func TestImpostorAlerts(t *testing.T) {
+	got := impostorAlerts([]mooc.Impostor{{Name: "Nibbles"}, {Name: "Mo\x1bchi"}})
+	if len(got) != 2 || !strings.Contains(got[0], "N*****s doesn't feel like themselves") {
+		t.Errorf("Expected the name hidden, got %v", got)
+	}
+	if strings.Contains(got[1], "\x1b") {
+		t.Errorf("Expected control characters dropped, got %q", got[1])
+	}
+}
+
+func TestRenderTrust(t *testing.T) {
+	now := time.Date(2026, 10, 16, 12, 0, 0, 0, time.UTC)
+	if got := renderTrust(nil, now); !strings.Contains(got, "who it says it is") {
+		t.Errorf("Expected an all-clear, got %q", got)
+	}
+
+	impostors := []mooc.Impostor{{PetID: "1a2b3c4d5e6f", Name: "Mochi", Pinned: "9f8e7d6c5b4a", Key: "0a1b2c3d4e5f", Seen: now.Add(-5 * time.Minute)}}
+	got := renderTrust(impostors, now)
+	for _, want := range []string{"Mochi (1a2b3c4d)", "first key 9f8e7d6c, now 0a1b2c3d", "seen 5m 0s ago", "trust <name>"} {
+		if !strings.Contains(got, want) {
+			t.Errorf("Expected %q in:\n%s", want, got)
+		}
+	}
+}
+
+func TestTrustImpostor(t *testing.T) {
+	impostors := []mooc.Impostor{{PetID: "a1", Name: "Mochi"}}
+	tests := []struct {
+		name    string
+		trusted bool
+		want    string
+		wantID  string
+	}{
+		{"mochi", true, "accepts Mochi's new key", "a1"},
+		{"Bean", true, "Nobody called Bean", ""},
+		{"Mochi", false, "Nobody called Mochi", "a1"},
+	}
+	for _, tt := range tests {
+		gotID := ""
+		trust := func(petID string) bool {
+			gotID = petID
+			return tt.trusted
+		}
+		if got := trustImpostor(tt.name, impostors, trust); !strings.Contains(got, tt.want) {
+			t.Errorf("trust %s: Expected %q, got %q", tt.name, tt.want, got)
+		}
+		if gotID != tt.wantID {
+			t.Errorf("trust %s: Expected %q trusted, got %q", tt.name, tt.wantID, gotID)
+		}
+	}
+}
+
+func TestLoadMeshKeyKeepsOneKey(t *testing.T) {
+	path := filepath.Join(t.TempDir(), meshKeyFile)
+	first, err := loadMeshKey(path)
+	if err != nil {
+		t.Fatalf("Expected a key made on first run, got %v", err)
+	}
+	if info, err := os.Stat(path); err != nil || info.Mode().Perm() != 0600 {
+		t.Errorf("Expected the key kept private, got %v (%v)", info.Mode().Perm(), err)
+	}
+	again, err := loadMeshKey(path)
+	if err != nil || !again.Equal(first) {
+		t.Errorf("Expected the same key on the next run, got %v", err)
+	}
+
+	os.WriteFile(path, []byte("not a key"), 0600)
+	if _, err := loadMeshKey(path); err == nil {
+		t.Errorf("Expected a damaged key file reported")
+	}
+}
@@ -0,0 +1,137 @@
+package main
+
+import (
+	"fmt"
+	"html"
+	"os/exec"
+	"regexp"
+	"strings"
+	"time"
+	"unicode"
+)
+
+// ttsBackend is a text-to-speech engine found on this machine
+type ttsBackend struct {
+	name string // Executable name, e.g. "espeak-ng"
+	path string // Resolved executable path
+}
+
+// ttsCandidates are the engines tried on each platform, in order of preference
+var ttsCandidates = map[string][]string{
+	"darwin":  {"say"},
+	"windows": {"powershell"},
+	"linux":   {"espeak-ng", "espeak"},
+}
+
+// detectTTSBackend returns the first available engine for goos, or nil
+func detectTTSBackend(lookPath func(string) (string, error), goos string) *ttsBackend {
+	candidates, ok := ttsCandidates[goos]
+	if !ok {
+		candidates = ttsCandidates["linux"] // BSDs and friends usually have espeak too
+	}
+	for _, name := range candidates {
+		if path, err := lookPath(name); err == nil {
+			return &ttsBackend{name: name, path: path}
+		}
+	}
+	return nil
+}
+
+// stagePitch returns a 0-99 pitch for a life stage; younger pets squeak higher
+func stagePitch(stage LifeStage) int {
+	switch stage {
+	case Egg, Baby:
+		return 90
+	case Child:
+		return 75
+	case Teen:
+		return 60
+	default:
+		return 45
+	}
+}
+
+// command builds the engine invocation for text at the given 0-99 pitch
+func (b *ttsBackend) command(text string, pitch int) *exec.Cmd {
+	switch b.name {
+	case "say":
+		// macOS baseline pitch is roughly 30-65
+		return exec.Command(b.path, fmt.Sprintf("[[pbas %d]] %s", 30+pitch*35/99, text))
+	case "powershell":
+		ssml := fmt.Sprintf("<speak version=\"1.0\" xml:lang=\"en-US\"><prosody pitch=\"%+d%%\">%s</prosody></speak>",
+			pitch-50, html.EscapeString(text))
+		script := "Add-Type -AssemblyName System.Speech; " +
+			"(New-Object System.Speech.Synthesis.SpeechSynthesizer).SpeakSsml('" + strings.ReplaceAll(ssml, "'", "''") + "')"
+		return exec.Command(b.path, "-NoProfile", "-Command", script)
+	default:
+		// "--" keeps a thought that starts with a dash from reading as an option
+		return exec.Command(b.path, "-p", fmt.Sprint(pitch), "--", text)
+	}
+}
+
+// stageDirections matches *actions* that shouldn't be read aloud
+var stageDirections = regexp.MustCompile(`\*[^*]*\*`)
+
+// speakableText strips stage directions and emoji so engines don't spell them out
+func speakableText(text string) string {
+	text = stageDirections.ReplaceAllString(text, " ")
+	text = strings.Map(func(r rune) rune {
+		if unicode.IsLetter(r) || unicode.IsDigit(r) || unicode.IsSpace(r) || unicode.IsPunct(r) {
+			return r
+		}
+		return -1
+	}, text)
+	return strings.Join(strings.Fields(text), " ")
+}
+
+// isNightHour reports whether the hour falls in the scene's night time
+func isNightHour(hour int) bool {
+	return hour < 6 || hour >= 20
+}
+
+// speak reads pet speech aloud if the voice is on and it's a sensible time
+func (ui *uiConfig) speak(pet *Pet, text string) {
+	if !ui.ttsEnabled || ui.tts == nil || ui.screenReader {
+		return
+	}
+
+	hour := time.Now().Hour()
+	if isNightHour(hour) || (pet.Endgame != nil && pet.Endgame.IsSleepingAt(hour)) {
+		return
+	}
+
+	text = speakableText(text)
+	if text == "" {
+		return
+	}
+
+	cmd := ui.tts.command(text, stagePitch(pet.Stage))
+	if err := cmd.Start(); err != nil {
+		return
+	}
+	go cmd.Wait()
+}
+
+// toggleVoice handles the `voice [on|off]` command
+func (ui *uiConfig) toggleVoice(arg string) string {
+	if ui.screenReader {
+		return "🔇 Voice stays off in screen-reader mode so your pet doesn't talk over your reader."
+	}
+	if ui.tts == nil {
+		return "🔇 No text-to-speech engine found (tried espeak-ng, espeak, say, and Windows SAPI)."
+	}
+
+	switch arg {
+	case "on":
+		ui.ttsEnabled = true
+	case "off":
+		ui.ttsEnabled = false
+	default:
+		ui.ttsEnabled = !ui.ttsEnabled
+	}
+
+	if ui.ttsEnabled {
+		return fmt.Sprintf("🔊 Voice on (%s). Your pet clears its throat. It stays quiet at night.", ui.tts.name)
+	}
+	return "🔇 Voice off. Your pet goes back to thinking quietly."
+}
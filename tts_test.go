@@ -0,0 +1,110 @@
+package main
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+func fakeLookPath(available ...string) func(string) (string, error) {
+	return func(name string) (string, error) {
+		for _, a := range available {
+			if a == name {
+				return "/usr/bin/" + name, nil
+			}
+		}
+		return "", errors.New("not found")
+	}
+}
+
+func TestDetectTTSBackend(t *testing.T) {
+	tests := []struct {
+		goos      string
+		available []string
+		want      string
+	}{
+		{"linux", []string{"espeak", "espeak-ng"}, "espeak-ng"},
+		{"linux", []string{"espeak"}, "espeak"},
+		{"darwin", []string{"say"}, "say"},
+		{"windows", []string{"powershell"}, "powershell"},
+		{"freebsd", []string{"espeak"}, "espeak"},
+		{"linux", nil, ""},
+	}
+
+	for _, tt := range tests {
+		backend := detectTTSBackend(fakeLookPath(tt.available...), tt.goos)
+		got := ""
+		if backend != nil {
+			got = backend.name
+		}
+		if got != tt.want {
+			t.Errorf("%s with %v: expected %q, got %q", tt.goos, tt.available, tt.want, got)
+		}
+	}
+}
+
+func TestStagePitchDropsWithAge(t *testing.T) {
+	stages := []LifeStage{Baby, Child, Teen, Adult}
+	for i := 1; i < len(stages); i++ {
+		if stagePitch(stages[i]) >= stagePitch(stages[i-1]) {
+			t.Errorf("Expected %s to speak lower than %s", stages[i], stages[i-1])
+		}
+	}
+}
+
+func TestTTSCommandArgs(t *testing.T) {
+	espeak := &ttsBackend{name: "espeak", path: "/usr/bin/espeak"}
+	args := espeak.command("hello", 75).Args
+	if strings.Join(args[1:], " ") != "-p 75 -- hello" {
+		t.Errorf("Unexpected espeak args: %v", args)
+	}
+	args = espeak.command("-w /tmp/out.wav", 75).Args
+	if len(args) != 5 || args[3] != "--" || args[4] != "-w /tmp/out.wav" {
+		t.Errorf("Expected a dashed thought passed after --, got %v", args)
+	}
+
+	say := &ttsBackend{name: "say", path: "/usr/bin/say"}
+	args = say.command("hello", 99).Args
+	if args[1] != "[[pbas 65]] hello" {
+		t.Errorf("Unexpected say args: %v", args)
+	}
+
+	ps := &ttsBackend{name: "powershell", path: "powershell"}
+	script := ps.command("it's <fine>", 50).Args[3]
+	if !strings.Contains(script, "&lt;fine&gt;") || !strings.Contains(script, "pitch=\"+0%\"") {
+		t.Errorf("Expected escaped SSML, got %s", script)
+	}
+	if strings.Count(script, "'")%2 != 0 {
+		t.Errorf("Expected balanced PowerShell quoting, got %s", script)
+	}
+}
+
+func TestSpeakableText(t *testing.T) {
+	got := speakableText("💕 *happy wiggle* Say it again.   🐣")
+	if got != "Say it again." {
+		t.Errorf("Expected stage directions and emoji removed, got %q", got)
+	}
+}
+
+func TestToggleVoice(t *testing.T) {
+	ui := &uiConfig{}
+	if msg := ui.toggleVoice("on"); !strings.Contains(msg, "No text-to-speech engine") {
+		t.Errorf("Expected missing engine message, got %q", msg)
+	}
+
+	ui.tts = &ttsBackend{name: "espeak", path: "/usr/bin/espeak"}
+	ui.toggleVoice("on")
+	if !ui.ttsEnabled {
+		t.Error("Expected voice to be on")
+	}
+	ui.toggleVoice("")
+	if ui.ttsEnabled {
+		t.Error("Expected bare toggle to turn voice off")
+	}
+
+	ui.screenReader = true
+	ui.toggleVoice("on")
+	if ui.ttsEnabled {
+		t.Error("Expected voice to stay off in screen-reader mode")
+	}
+}
@@ -0,0 +1,145 @@
+package main
+
+import (
+	"bufio"
+	"fmt"
+	"math/rand"
+	"strings"
+	"time"
+
+	"github.com/tamagotchi/arg"
+)
+
+const (
+	// typingGrace and typingPerRune set how long a thought lasts before it fades
+	typingGrace   = 3 * time.Second
+	typingPerRune = 350 * time.Millisecond
+	// typingLetterOdds is one in how many perfect runs leave a letter behind
+	typingLetterOdds = 3
+)
+
+// typingScore is how well a thought was copied down
+type typingScore struct {
+	accuracy float64       // 1 is every rune right
+	elapsed  time.Duration // How long the typing took
+	fade     time.Duration // How long the thought lasted
+}
+
+// fadeTime is how long a thought stays before it fades: longer thoughts
+// last longer
+func fadeTime(thought string) time.Duration {
+	return typingGrace + time.Duration(len([]rune(thought)))*typingPerRune
+}
+
+// scoreTyping compares what was typed against the thought
+func scoreTyping(thought, typed string, elapsed time.Duration) typingScore {
+	length := max(len([]rune(thought)), 1)
+	accuracy := 1 - float64(levenshtein(thought, strings.TrimSpace(typed)))/float64(length)
+	return typingScore{accuracy: max(accuracy, 0), elapsed: elapsed, fade: fadeTime(thought)}
+}
+
+// late reports whether the thought faded before the typing was done
+func (s typingScore) late() bool {
+	return s.elapsed > s.fade
+}
+
+// perfect reports every rune right before the thought faded
+func (s typingScore) perfect() bool {
+	return s.accuracy == 1 && !s.late()
+}
+
+// happiness is what the run is worth to the pet: accuracy counts most,
+// speed a little, and a thought mangled beyond recognition stings
+func (s typingScore) happiness() int {
+	if s.accuracy < 0.5 {
+		return -3
+	}
+	delta := int(s.accuracy * 10)
+	switch {
+	case s.late():
+		delta /= 2
+	case s.elapsed <= s.fade/2:
+		delta += 3
+	}
+	return delta
+}
+
+// wpm is the typing speed in words a minute, five runes to the word
+func (s typingScore) wpm(thought string) int {
+	if s.elapsed <= 0 {
+		return 0
+	}
+	return int(float64(len([]rune(thought))) / 5 / s.elapsed.Minutes())
+}
+
+// typingLetter leaves a letter of the ARG answer behind, now and then,
+// after a perfect run once the clue for it is out
+func typingLetter(p *Pet, rng *rand.Rand) string {
+	if p.Endgame == nil || p.Endgame.ARG == nil {
+		return ""
+	}
+	game := p.Endgame.ARG
+	if !game.IsPlanted(arg.PuzzleTyping) || game.IsSolved(arg.PuzzleTyping) || rng.Intn(typingLetterOdds) != 0 {
+		return ""
+	}
+	shown, ok := game.RevealLetter()
+	if !ok {
+		return ""
+	}
+	message := fmt.Sprintf("\n✨ As the words fade, a letter stays behind: %s", shown)
+	if !strings.Contains(shown, "_") {
+		message += fmt.Sprintf("\nWhen you know, type: solve %s <answer>", arg.PuzzleTyping)
+	}
+	return message
+}
+
+// PlayTypingRace plays the typing race: copy one of the pet's thoughts
+// before it fades
+func PlayTypingRace(reader *bufio.Reader, session *petSession, ui *uiConfig, p *Pet) MiniGameResult {
+	if p.Stage == Dead {
+		return MiniGameResult{Message: "💀 Your pet has passed away..."}
+	}
+	if p.Stage == Egg {
+		return MiniGameResult{Message: "🥚 The egg isn't thinking anything you could write down. Yet."}
+	}
+	fmt.Println(ui.text("\n╔════════════════════════════════════╗\n" +
+		"║    ⌨️ TYPING RACE ⌨️               ║\n" +
+		"╠════════════════════════════════════╣\n" +
+		"║ Your pet will think a thought.     ║\n" +
+		"║ Type it exactly before it fades.   ║\n" +
+		"║ Press Enter when you're ready...   ║\n" +
+		"╚════════════════════════════════════╝"))
+	session.Wait(func() { reader.ReadString('\n') })
+
+	rng := rand.New(rand.NewSource(time.Now().UnixNano()))
+	thought := activeContent.thoughts.pick(rng)
+	fmt.Println(ui.text(fmt.Sprintf("\n💭 %s\n(It fades in %d seconds.)", thought, int(fadeTime(thought).Seconds()))))
+	fmt.Print("> ")
+	return raceThought(reader, session, p, thought, time.Now, rng)
+}
+
+// raceThought reads the player's copy of thought and scores it
+func raceThought(reader *bufio.Reader, session *petSession, p *Pet, thought string, clock func() time.Time, rng *rand.Rand) MiniGameResult {
+	start := clock()
+	var typed string
+	session.Wait(func() { typed, _ = reader.ReadString('\n') })
+	score := scoreTyping(thought, typed, clock().Sub(start))
+
+	delta := score.happiness()
+	p.Happiness = clamp(p.Happiness+delta, 0, 100)
+
+	verdict := fmt.Sprintf("%.0f%% accurate at %d words a minute", score.accuracy*100, score.wpm(thought))
+	var message string
+	switch {
+	case score.perfect():
+		message = fmt.Sprintf("⌨️ Perfect: %s. %s looks at you like you finally understand it. (%+d happiness)", verdict, p.Name, delta)
+		message += typingLetter(p, rng)
+	case score.late():
+		message = fmt.Sprintf("⌨️ The thought faded before you finished: %s. %s has already moved on. (%+d happiness)", verdict, p.Name, delta)
+	case delta < 0:
+		message = fmt.Sprintf("⌨️ That's not what it thought at all: %s. %s feels misquoted. (%+d happiness)", verdict, p.Name, delta)
+	default:
+		message = fmt.Sprintf("⌨️ Close: %s. %s appreciates the effort. (%+d happiness)", verdict, p.Name, delta)
+	}
+	return MiniGameResult{Message: message, Success: score.perfect()}
+}
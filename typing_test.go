@@ -0,0 +1,104 @@
+package main
+
+import (
+	"bufio"
+	"math/rand"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/tamagotchi/arg"
+)
+
+func TestScoreTyping(t *testing.T) {
+	thought := "Is a byte still a byte if nobody reads it?"
+	fade := fadeTime(thought)
+	tests := []struct {
+		name      string
+		typed     string
+		elapsed   time.Duration
+		perfect   bool
+		happiness int
+	}{
+		{"perfect and quick", thought, fade / 3, true, 13},
+		{"perfect", thought + "\n", fade - time.Second, true, 10},
+		{"a typo", strings.Replace(thought, "byte", "bite", 1), fade - time.Second, false, 9},
+		{"too late", thought, fade + time.Second, false, 5},
+		{"nothing like it", "asdf", time.Second, false, -3},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			score := scoreTyping(thought, tt.typed, tt.elapsed)
+			if score.perfect() != tt.perfect {
+				t.Errorf("Expected perfect %v, got %v (%+v)", tt.perfect, score.perfect(), score)
+			}
+			if got := score.happiness(); got != tt.happiness {
+				t.Errorf("Expected %d happiness, got %d", tt.happiness, got)
+			}
+		})
+	}
+}
+
+func TestFadeTimeGrowsWithTheThought(t *testing.T) {
+	if fadeTime("short") >= fadeTime("a much longer thought than that") {
+		t.Errorf("Expected longer thoughts to last longer")
+	}
+	if got := fadeTime(""); got != typingGrace {
+		t.Errorf("Expected %v for nothing at all, got %v", typingGrace, got)
+	}
+}
+
+// fakeClock moves on by step each time it's read
+func fakeClock(step time.Duration) func() time.Time {
+	now := time.Date(2026, 10, 16, 9, 0, 0, 0, time.UTC)
+	return func() time.Time {
+		now = now.Add(step)
+		return now
+	}
+}
+
+func TestRaceThought(t *testing.T) {
+	thought := "The void is just a room with the lights off."
+	pet := NewPet("Mochi")
+	pet.Stage = Adult
+	pet.Happiness = 50
+
+	got := raceThought(bufio.NewReader(strings.NewReader(thought+"\n")), nil, pet, thought, fakeClock(5*time.Second), rand.New(rand.NewSource(1)))
+	if !got.Success || !strings.Contains(got.Message, "Perfect: 100% accurate at 105 words a minute") {
+		t.Errorf("Expected a perfect run, got %+v", got)
+	}
+	if pet.Happiness != 63 {
+		t.Errorf("Expected a quick perfect run to be worth 13, got %d", pet.Happiness-50)
+	}
+	if strings.Contains(got.Message, "letter") {
+		t.Errorf("Expected no letters before the clue is out, got %q", got.Message)
+	}
+
+	got = raceThought(bufio.NewReader(strings.NewReader("no\n")), nil, pet, thought, fakeClock(time.Minute), rand.New(rand.NewSource(1)))
+	if got.Success || !strings.Contains(got.Message, "faded") {
+		t.Errorf("Expected the thought to fade, got %+v", got)
+	}
+}
+
+func TestPerfectRunsRevealLetters(t *testing.T) {
+	pet := NewPet("Mochi")
+	pet.Endgame.argGame().Plant(arg.PuzzleTyping)
+	answer := pet.Endgame.ARG.Answer(arg.PuzzleTyping)
+	rng := rand.New(rand.NewSource(1))
+
+	var last string
+	for i := 0; i < 200 && pet.Endgame.ARG.Letters < len(answer); i++ {
+		if letter := typingLetter(pet, rng); letter != "" {
+			last = letter
+		}
+	}
+	if pet.Endgame.ARG.Letters != len(answer) {
+		t.Fatalf("Expected every letter revealed in the end, got %d of %d", pet.Endgame.ARG.Letters, len(answer))
+	}
+	if !strings.Contains(last, strings.Join(strings.Split(answer, ""), " ")) || !strings.Contains(last, "solve typing") {
+		t.Errorf("Expected the last letter to complete %s and say how to answer, got %q", answer, last)
+	}
+	if got := typingLetter(pet, rng); got != "" {
+		t.Errorf("Expected nothing more once every letter shows, got %q", got)
+	}
+}
@@ -1,11 +1,17 @@
 package main
 
 import (
+	"bytes"
 	"fmt"
 	"math/rand"
 	"os"
+	"os/exec"
+	"runtime"
+	"strconv"
 	"strings"
 	"time"
+
+	"github.com/tamagotchi/arg"
 )
 
 type uiPalette struct {
@@ -27,6 +33,8 @@ type uiConfig struct {
 	highContrast    bool
 	colorBlind      bool
 	soundEnabled    bool
+	ttsEnabled      bool
+	tts             *ttsBackend
 	palette         uiPalette
 	startedAt       time.Time
 	spinnerFrames   []string
@@ -35,6 +43,18 @@ type uiConfig struct {
 	typewriterDelay time.Duration
 	lastBellTime    time.Time
 	morseBuffer     []morseEvent
+	asciiOnly       bool         // Draw boxes and bars with ASCII for consoles without the glyphs
+	noEmoji         bool         // Spell emoji out as [TAGS] for braille displays and limited fonts
+	bellStyle       string       // bellTerminal, bellBeep, or bellFlash
+	artLib          *artLibrary  // Art loaded from a debug directory; nil uses activeArt
+	artReloader     *artReloader // Watches TAMAGOTCHI_ART_DIR in art debug mode
+	cursorControl   bool         // Terminal understands cursor movement, so sequences can redraw in place
+	zoom            cameraZoom   // Set by the look command for the rest of the session
+	reveal          cameraReveal // What the current close-up gave away, if anything
+	weather         *weatherFeed // Real weather for the window; nil shows the scene's
+	sounds          *soundRouter // Events routed to real audio; nil rings the bell for all
+	alerts          *alertPolicy // Quiet hours and the channels beyond the terminal; nil has neither
+	scene           sceneCache   // The last frame drawn and what it was drawn from
 }
 
 // morseEvent represents a timing event for hidden morse code messages
@@ -46,12 +66,14 @@ type morseEvent struct {
 // newUIConfig inspects environment to set terminal preferences.
 func newUIConfig() *uiConfig {
 	term := os.Getenv("TERM")
-	color := term != "dumb" && os.Getenv("NO_COLOR") == ""
+	ansi, asciiOnly := setupConsole(os.Getenv)
+	color := ansi && term != "dumb" && os.Getenv("NO_COLOR") == ""
 	screenReader := os.Getenv("TAMAGOTCHI_SCREEN_READER") != ""
 	reducedMotion := screenReader || os.Getenv("TAMAGOTCHI_REDUCED_MOTION") != ""
 	highContrast := os.Getenv("TAMAGOTCHI_HIGH_CONTRAST") != ""
 	colorBlind := os.Getenv("TAMAGOTCHI_COLORBLIND") != ""
 	soundEnabled := os.Getenv("TAMAGOTCHI_NO_SOUND") == "" && !screenReader
+	ttsEnabled := os.Getenv("TAMAGOTCHI_TTS") != "" && !screenReader
 
 	palette := uiPalette{
 		accent:       "\033[38;5;45m",
@@ -97,6 +119,12 @@ func newUIConfig() *uiConfig {
 
 	rand.Seed(time.Now().UnixNano())
 
+	// Art debug mode: draw from a directory on disk and pick up edits live
+	var reloader *artReloader
+	if dir := os.Getenv("TAMAGOTCHI_ART_DIR"); dir != "" {
+		reloader = &artReloader{dir: dir}
+	}
+
 	return &uiConfig{
 		colorEnabled:    color,
 		reducedMotion:   reducedMotion,
@@ -104,13 +132,20 @@ func newUIConfig() *uiConfig {
 		highContrast:    highContrast,
 		colorBlind:      colorBlind,
 		soundEnabled:    soundEnabled,
+		ttsEnabled:      ttsEnabled,
+		tts:             detectTTSBackend(exec.LookPath, runtime.GOOS),
 		palette:         palette,
 		startedAt:       time.Now(),
 		spinnerFrames:   []string{"⣾", "⣷", "⣯", "⣟", "⡿", "⢿", "⣻", "⣽"},
-		staticFrames:    []string{"▓▒░▒▓░▒", "▒░▒▓▒░▓", "░▒▓░▒▓▒"},
+		staticFrames:    activeArt.Special("static"),
 		typewriterDelay: delay,
 		lastBellTime:    time.Time{},
 		morseBuffer:     make([]morseEvent, 0),
+		asciiOnly:       asciiOnly,
+		noEmoji:         os.Getenv(noEmojiEnv) != "",
+		bellStyle:       parseBellStyle(os.Getenv("TAMAGOTCHI_BELL"), runtime.GOOS),
+		artReloader:     reloader,
+		cursorControl:   ansi && term != "dumb",
 	}
 }
 
@@ -121,16 +156,42 @@ type sceneSnapshot struct {
 	static          bool
 	expression      string
 	expressionLabel string
-	lookNow         bool
+	rare            *rareEvent // Happening right now, if anything
+	thought         string     // What the pet is thinking, if it's home to think it
 }
 
-// renderScene composes the entire pet panel with animation, weather, and status.
+// renderScene composes the entire pet panel with animation, weather, and
+// status. A frame with nothing new in it is handed back as it was drawn.
 func renderScene(pet *Pet, ui *uiConfig) string {
-	snap := ui.buildSnapshot(pet)
-	var b strings.Builder
+	now := time.Now()
+	state := petStateOf(pet)
+	snap := ui.snapshotAt(pet, state, now)
+	key := frameKey{
+		snap:          snap,
+		tick:          now.UnixNano() / int64(sceneTick),
+		pet:           state,
+		banner:        pet.Alerts.banner(pet.Name, now),
+		palette:       ui.palette,
+		color:         ui.colorEnabled,
+		reducedMotion: ui.reducedMotion,
+		zoom:          ui.zoom,
+		art:           ui.art(),
+	}
+	if frame, ok := ui.scene.lookup(key, pet.Room); ok {
+		return frame
+	}
+	frame := drawScene(pet, ui, snap, key.banner, now)
+	ui.scene.store(key, pet.Room, frame)
+	return frame
+}
 
-	title := ui.renderTitle(snap)
-	b.WriteString(title)
+// drawScene draws a frame from scratch
+func drawScene(pet *Pet, ui *uiConfig, snap sceneSnapshot, banner string, now time.Time) string {
+	b := scenePool.Get().(*bytes.Buffer)
+	b.Reset()
+	defer scenePool.Put(b)
+
+	b.WriteString(ui.renderTitle(snap))
 	b.WriteString("\n")
 
 	if snap.static {
@@ -140,16 +201,59 @@ func renderScene(pet *Pet, ui *uiConfig) string {
 	}
 
 	b.WriteString(ui.renderWeatherLine(snap))
+	if banner != "" {
+		b.WriteString(ui.paletteText(banner+"\n", ui.palette.danger))
+	}
+	if pet.Paused {
+		b.WriteString(ui.paletteText(ui.art().Special("suspended")[0]+"\n", ui.palette.faint))
+		b.WriteString(ui.renderStatusPanel(pet))
+		return b.String()
+	}
+	if pet.Travel.IsAway(now) {
+		// Nobody's home; the room waits
+		b.WriteString(ui.paletteText(pet.TravelStatus(now)+"\n", ui.palette.faint))
+		b.WriteString(ui.renderStatusPanel(pet))
+		return b.String()
+	}
 	b.WriteString(ui.renderPetAnimation(pet, snap))
+	if pet.Boarding.IsAway(now) {
+		b.WriteString(ui.paletteText(pet.BoardingStatus(now)+"\n", ui.palette.faint))
+	} else if snap.thought != "" {
+		b.WriteString(ui.paletteText("💭 \""+snap.thought+"\"\n", ui.palette.faint))
+	}
+	if pet.Power.low() && pet.Stage != Egg && pet.Stage != Dead {
+		b.WriteString(ui.paletteText("🪫 \"The world is getting dim...\"\n", ui.palette.warn))
+	}
 	b.WriteString(ui.renderStatusPanel(pet))
 
 	return b.String()
 }
 
+// snapshotAt returns the rolled parts of the scene: the expression, any
+// glitch, static, or rare event, and the pet's thought. They're rolled
+// again once they've been held for sceneRollInterval, or as soon as the
+// pet changes, so feeding it shows at once.
+func (ui *uiConfig) snapshotAt(pet *Pet, state petState, now time.Time) sceneSnapshot {
+	if !ui.scene.rolledAt.IsZero() && state == ui.scene.rolledFor && now.Sub(ui.scene.rolledAt) < sceneRollInterval {
+		return ui.scene.snap
+	}
+	snap := ui.buildSnapshot(pet)
+	if !snap.static && !pet.Paused && !pet.Travel.IsAway(now) && !pet.Boarding.IsAway(now) {
+		if snap.thought = pet.Thought(); snap.thought != "" {
+			ui.speak(pet, snap.thought)
+			// A heavy or comforting thought moves happiness; that's the
+			// thought's doing, not a change to roll again for
+			state = petStateOf(pet)
+		}
+	}
+	ui.scene.snap, ui.scene.rolledAt, ui.scene.rolledFor = snap, now, state
+	return snap
+}
+
 func (ui *uiConfig) buildSnapshot(pet *Pet) sceneSnapshot {
 	now := time.Now()
 	hour := now.Hour()
-	isNight := hour < 6 || hour >= 20
+	isNight := isNightHour(hour)
 
 	weather := chooseWeather(now)
 	glitch := false
@@ -165,7 +269,10 @@ func (ui *uiConfig) buildSnapshot(pet *Pet) sceneSnapshot {
 
 	static := rand.Intn(100) < 3 && !ui.reducedMotion
 
-	expr, label, look := ui.pickExpression(pet)
+	expr, label, rare := ui.pickExpression(pet)
+	if rare == nil && pet.Endgame != nil && pet.Stage != Egg && pet.Stage != Dead && pet.Endgame.IsSleepingAt(hour) {
+		label = "Asleep — you're never here at this hour"
+	}
 
 	return sceneSnapshot{
 		isNight:         isNight,
@@ -174,24 +281,13 @@ func (ui *uiConfig) buildSnapshot(pet *Pet) sceneSnapshot {
 		static:          static,
 		expression:      expr,
 		expressionLabel: label,
-		lookNow:         look,
+		rare:            rare,
 	}
 }
 
 func chooseWeather(now time.Time) string {
 	roll := (now.UnixNano() / int64(time.Minute)) % 100
-	switch {
-	case roll < 20:
-		return "☀️ clear"
-	case roll < 40:
-		return "🌧️ rain"
-	case roll < 55:
-		return "❄️ snow"
-	case roll < 75:
-		return "🌫️ fog"
-	default:
-		return "⛅ drifting clouds"
-	}
+	return activeRegion.weather(now, int(roll))
 }
 
 func (ui *uiConfig) renderTitle(snap sceneSnapshot) string {
@@ -220,26 +316,51 @@ func (ui *uiConfig) renderWeatherLine(snap sceneSnapshot) string {
 }
 
 func (ui *uiConfig) renderPetAnimation(pet *Pet, snap sceneSnapshot) string {
-	var b strings.Builder
-
-	if snap.glitch {
-		b.WriteString(ui.paletteText(glitchFrame(), ui.palette.danger))
+	art := ui.art()
+	if ui.zoom == zoomWide {
+		return ui.renderWideShot(pet, snap)
 	}
 
-	stageFrames := ui.framesForStage(pet.Stage, snap.isNight)
+	stageFrames := ui.moodFrames(pet.Stage, artMood(pet), pet.Room.dark(snap.isNight))
+	if ui.zoom == zoomClose {
+		stageFrames = art.CloseUp(pet.Stage)
+	}
 	if len(stageFrames) == 0 {
 		return ""
 	}
 
-	frame := stageFrames[int(time.Now().UnixNano()/120_000_000)%len(stageFrames)]
-	if snap.lookNow {
-		frame = theLookFrame()
+	elapsed := time.Since(ui.startedAt)
+	frame, _ := loopSequence("idle", stageFrames, idleFrameDuration).FrameAt(elapsed)
+	if snap.rare != nil {
+		if special := art.Special(snap.rare.Art); len(special) > 0 {
+			frame = special[0]
+		}
 	}
 
-	if !ui.reducedMotion && snap.weather == "🌧️ rain" {
-		frame += "\n" + ui.paletteText("...raindrops ping against the glass of the simulation.", ui.palette.faint)
-	} else if !ui.reducedMotion && snap.weather == "🌫️ fog" {
-		frame = ui.paletteText("[signal falls into fog]\n", ui.palette.faint) + frame
+	// Weather with particles falls around the pet; anything else uses overlay
+	// art. In a furnished room the weather stays outside, behind the window.
+	weather := snap.weather[strings.LastIndex(snap.weather, " ")+1:]
+	indoors := ui.zoom != zoomClose && pet.Room.furnished()
+	if indoors {
+		frame = pet.Room.furnish(frame, ui.outsideWeather(snap.weather, time.Now()), snap.isNight)
+		weather = ""
+	}
+	particles := ui.weatherLayers(weather)
+	overlays, above := art.Overlay(weather)
+	if snap.glitch {
+		particles = append(particles, ui.staticLayer())
+	}
+	frame = ui.composite(frame, particles, elapsed)
+	if ui.zoom == zoomClose {
+		frame = ui.closeUpExtras(frame, pet)
+	}
+	if len(overlays) > 0 && len(ui.weatherLayers(weather)) == 0 && !ui.reducedMotion {
+		overlay, _ := loopSequence(weather, overlays, idleFrameDuration).FrameAt(elapsed)
+		if above {
+			frame = ui.paletteText(overlay+"\n", ui.palette.faint) + frame
+		} else {
+			frame += "\n" + ui.paletteText(overlay, ui.palette.faint)
+		}
 	}
 
 	if snap.expression != "" {
@@ -250,136 +371,87 @@ func (ui *uiConfig) renderPetAnimation(pet *Pet, snap sceneSnapshot) string {
 		frame += fmt.Sprintf("  %s", ui.paletteText("("+snap.expressionLabel+")", ui.palette.faint))
 	}
 
+	if ui.artReloader != nil && ui.artReloader.lastErr != nil {
+		frame += "\n" + ui.paletteText("⚠️ art reload failed: "+ui.artReloader.lastErr.Error(), ui.palette.warn)
+	}
+
 	frame += "\n"
 	return frame
 }
 
-func glitchFrame() string {
-	noise := []string{
-		"▒▒▒▒▒▒▒▒▒▒▒▒▒▒▒▒▒▒▒▒▒▒▒",
-		"░▒▒▒██░▒░▒██▒▒░▒▒░▒▒▒▒░░",
-		"██▒░▒░▒██▒░▒▒▒██▒░▒▒▒██▒",
-	}
-	return strings.Join(noise, "\n") + "\n"
+// framesForStage returns a stage's default animation loop
+func (ui *uiConfig) framesForStage(stage LifeStage, isNight bool) []string {
+	return ui.moodFrames(stage, "", isNight)
 }
 
-func theLookFrame() string {
-	return `
-  ┌────────────────────┐
-  │        ▓▓▓▓        │
-  │       ▓    ▓       │
-  │      ▓  ██  ▓      │
-  │      ▓ ████ ▓      │
-  │      ▓  ██  ▓      │
-  │       ▓    ▓       │
-  │        ▓▓▓▓        │
-  └────────────────────┘
-`
-}
+// moodFrames returns the animation loop for a stage in a mood, tinted at night
+func (ui *uiConfig) moodFrames(stage LifeStage, mood string, isNight bool) []string {
+	frames := ui.art().StageFrames(stage, mood)
+	if !isNight || stage == Dead {
+		return frames
+	}
 
-func (ui *uiConfig) framesForStage(stage LifeStage, isNight bool) []string {
-	nightTint := ""
-	if isNight {
-		nightTint = ui.paletteText("(eyes reflect starlight)", ui.palette.faint) + "\n"
-	}
-
-	switch stage {
-	case Egg:
-		return []string{
-			nightTint + `     ___
-    /   \
-   |  .  |
-    \___/
-     ( )`,
-			nightTint + `     ___
-    /   \
-   |  o  |
-    \___/
-     (_)`,
-			nightTint + `     ___
-    /   \
-   |  *  |
-    \___/
-     ( )`,
-		}
-	case Baby:
-		return []string{
-			nightTint + `      ◕ ◕
-     (\_/)
-      > <
-    🩷 Baby`,
-			nightTint + `      ◡ ◡
-     (\_/)
-     <   >
-    💫 Wobble`,
-		}
-	case Child:
-		return []string{
-			nightTint + `     ◕ω◕
-    (\_/)
-     > <
-    🧒 Curious`,
-			nightTint + `     ◕△◕
-    (\_/)
-     > <
-    🧒 Listening`,
-		}
-	case Teen:
-		return []string{
-			nightTint + `     ◕‿◕
-    ╱|_|╲
-     / \
-    🧑 Restless`,
-			nightTint + `     ◕︿◕
-    ╱|_|╲
-     / \
-    🧑 Dramatic`,
-		}
-	case Adult:
-		return []string{
-			nightTint + `     ◕‿◕
-    ╱|_|╲
-     / \
-    👨 Watching`,
-			nightTint + `     ◕▿◕
-    ╱|_|╲
-     / \
-    👨 Focused`,
-			nightTint + `     ◕‧◕
-    ╱|_|╲
-     / \
-    👨 Processing`,
-		}
-	case Dead:
-		return []string{`
-        💀
-       /||\
-        /\
-   R.I.P.`}
-	default:
-		return nil
+	nightTint := ui.paletteText("(eyes reflect starlight)", ui.palette.faint) + "\n"
+	tinted := make([]string, len(frames))
+	for i, frame := range frames {
+		tinted[i] = nightTint + frame
 	}
+	return tinted
 }
 
-func (ui *uiConfig) renderStatusPanel(pet *Pet) string {
-	spinner := ui.spinningGlyph()
-	statusIcon := pet.getStatusIcon()
+// Status panel borders, and every length of bar run, written out once
+const (
+	statusPanelTop    = "╔════════════════════════════════════╗\n║ "
+	statusPanelRow    = "\n║ "
+	statusPanelBottom = "\n╚════════════════════════════════════╝\n"
+)
+
+var (
+	fullBarRuns  = barRuns("█")
+	emptyBarRuns = barRuns("░")
+)
 
-	lines := []string{
-		fmt.Sprintf("%s %s (%s)", spinner, pet.Name, pet.getLifeStageEmoji()),
-		fmt.Sprintf("🍔 Hunger:      %s", ui.animatedBar(100-pet.Hunger, ui.palette.warn)),
-		fmt.Sprintf("😊 Happiness:   %s", ui.animatedBar(pet.Happiness, ui.palette.accent)),
-		fmt.Sprintf("❤️  Health:     %s", ui.animatedBar(pet.Health, ui.palette.highlight)),
-		fmt.Sprintf("✨ Cleanliness: %s", ui.animatedBar(pet.Cleanliness, ui.palette.neutral)),
-		fmt.Sprintf("🎂 Age:         %d hours", pet.Age),
-		fmt.Sprintf("🌱 Stage:       %s", pet.Stage.String()),
-		fmt.Sprintf("💊 Status:      %s", pet.getHealthStatus()),
-		fmt.Sprintf("Mood:           %s", statusIcon),
+func barRuns(glyph string) [11]string {
+	var runs [11]string
+	for i := range runs {
+		runs[i] = strings.Repeat(glyph, i)
 	}
+	return runs
+}
 
-	return "╔════════════════════════════════════╗\n║ " +
-		strings.Join(lines, "\n║ ") +
-		"\n╚════════════════════════════════════╝\n"
+func (ui *uiConfig) renderStatusPanel(pet *Pet) string {
+	b := scenePool.Get().(*bytes.Buffer)
+	b.Reset()
+	defer scenePool.Put(b)
+
+	b.WriteString(statusPanelTop)
+	b.WriteString(ui.spinningGlyph())
+	b.WriteString(" ")
+	b.WriteString(truncateWidth(pet.Name, maxPanelNameWidth))
+	b.WriteString(" (")
+	b.WriteString(pet.getLifeStageEmoji())
+	b.WriteString(")")
+	row := func(label, value string) {
+		b.WriteString(statusPanelRow)
+		b.WriteString(label)
+		b.WriteString(value)
+	}
+	row("🍔 Hunger:      ", ui.animatedBar(100-pet.Hunger, ui.palette.warn))
+	row("😊 Happiness:   ", ui.animatedBar(pet.Happiness, ui.palette.accent))
+	row("❤️  Health:     ", ui.animatedBar(pet.Health, ui.palette.highlight))
+	row("✨ Cleanliness: ", ui.animatedBar(pet.Cleanliness, ui.palette.neutral))
+	row("🎂 Age:         ", strconv.Itoa(pet.Age)+" hours")
+	row("🌱 Stage:       ", pet.Stage.String())
+	row("💊 Status:      ", pet.getHealthStatus())
+	row("Mood:           ", pet.getStatusIcon())
+	if pet.Stage == Egg && pet.Incubation != nil {
+		row("🌡️  Warmth:     ", ui.animatedBar(pet.Incubation.Warmth, ui.palette.warn))
+	}
+	if generation := pet.generation(); generation > 1 {
+		row("🧬 Bloodline:   ", strings.TrimSpace("Gen "+strconv.Itoa(generation)+" "+pet.bloodlineBadges()))
+	}
+	b.WriteString(statusPanelBottom)
+	return b.String()
 }
 
 func (ui *uiConfig) animatedBar(value int, colorCode string) string {
@@ -393,28 +465,18 @@ func (ui *uiConfig) animatedBar(value int, colorCode string) string {
 	empty := 10 - full
 
 	var b strings.Builder
+	b.Grow(64)
 	b.WriteString("[")
-	for i := 0; i < full; i++ {
-		b.WriteString("█")
-	}
-
-	if ui.reducedMotion {
-		for i := 0; i < empty; i++ {
-			b.WriteString("░")
-		}
+	b.WriteString(fullBarRuns[full])
+	if ui.reducedMotion || empty == 0 {
+		b.WriteString(emptyBarRuns[empty])
 	} else {
-		ghost := ui.spinnerFrames[int(time.Now().UnixNano()/90_000_000)%len(ui.spinnerFrames)]
-		for i := 0; i < empty; i++ {
-			if i == 0 {
-				b.WriteString(ghost)
-			} else {
-				b.WriteString("░")
-			}
-		}
+		b.WriteString(ui.spinnerFrames[int(time.Now().UnixNano()/90_000_000)%len(ui.spinnerFrames)])
+		b.WriteString(emptyBarRuns[empty-1])
 	}
-
 	b.WriteString("] ")
-	b.WriteString(fmt.Sprintf("%d%%", value))
+	b.WriteString(strconv.Itoa(value))
+	b.WriteString("%")
 
 	return ui.paletteText(b.String(), colorCode)
 }
@@ -439,21 +501,18 @@ func (ui *uiConfig) staticFrame() string {
 	return ui.staticFrames[idx]
 }
 
-// pickExpression returns an ASCII expression, label, and whether to show "The Look".
-func (ui *uiConfig) pickExpression(pet *Pet) (string, string, bool) {
-	if pet.HasShownTheLook {
-		return ui.pickStandardExpression(pet)
+// pickExpression returns an ASCII expression, label, and any rare event
+// happening instead
+func (ui *uiConfig) pickExpression(pet *Pet) (string, string, *rareEvent) {
+	if rare := pet.rollRareEvent(time.Now(), rand.Intn); rare != nil {
+		return ui.paletteText(rare.Expression, ui.palette.danger), rare.Label, rare
 	}
-
-	if rand.Intn(1000) == 6 { // once per lifetime, rare
-		pet.HasShownTheLook = true
-		return ui.paletteText("The pet stares straight through the screen.", ui.palette.danger), "The Look", true
-	}
-
-	return ui.pickStandardExpression(pet)
+	expr, label, _ := ui.pickStandardExpression(pet)
+	return expr, label, nil
 }
 
-func (ui *uiConfig) pickStandardExpression(pet *Pet) (string, string, bool) {
+// standardExpressions are the idle expressions, written out once
+var standardExpressions = func() []string {
 	emotions := []string{
 		"Soft blink",
 		"Curious tilt",
@@ -479,18 +538,26 @@ func (ui *uiConfig) pickStandardExpression(pet *Pet) (string, string, bool) {
 		"Light flicker nearby",
 		"Heartbeat syncs with yours",
 	}
-
-	contextLabels := map[string]string{
-		"hunger":     "Famished",
-		"sick":       "Unwell",
-		"happy":      "Delighted",
-		"dirty":      "Needs a bath",
-		"lonely":     "Waiting for input",
-		"balanced":   "Centered",
-		"networking": "Signal listening",
-		"storm":      "Weatherwatch",
+	expressions := make([]string, len(emotions))
+	for i, emotion := range emotions {
+		expressions[i] = "Expression: " + emotion
 	}
+	return expressions
+}()
 
+// contextLabels name what an expression is about
+var contextLabels = map[string]string{
+	"hunger":     "Famished",
+	"sick":       "Unwell",
+	"happy":      "Delighted",
+	"dirty":      "Needs a bath",
+	"lonely":     "Waiting for input",
+	"balanced":   "Centered",
+	"networking": "Signal listening",
+	"storm":      "Weatherwatch",
+}
+
+func (ui *uiConfig) pickStandardExpression(pet *Pet) (string, string, bool) {
 	switch {
 	case pet.IsSick:
 		return "Expression: feverish glow", contextLabels["sick"], false
@@ -512,12 +579,12 @@ func (ui *uiConfig) pickStandardExpression(pet *Pet) (string, string, bool) {
 		return "Expression: staring at something you can't see", contextLabels["lonely"], false
 	}
 
-	idx := rand.Intn(len(emotions))
-	return "Expression: " + emotions[idx], contextLabels["balanced"], false
+	return standardExpressions[rand.Intn(len(standardExpressions))], contextLabels["balanced"], false
 }
 
 // typewriterPrint renders dialogue with an optional typewriter effect.
 func typewriterPrint(msg string, ui *uiConfig) {
+	msg = ui.text(msg)
 	if ui.screenReader || ui.typewriterDelay == 0 {
 		fmt.Println(msg)
 		return
@@ -539,12 +606,12 @@ func maybeShake(pet *Pet, ui *uiConfig) {
 	}
 	for i := 0; i < 2; i++ {
 		offset := rand.Intn(4)
-		fmt.Printf("%s⚠️\n", strings.Repeat(" ", offset))
+		fmt.Println(ui.text(strings.Repeat(" ", offset) + "⚠️"))
 		time.Sleep(40 * time.Millisecond)
 	}
 }
 
-// terminalBell emits the terminal bell character (\a) for audio notifications.
+// terminalBell sounds the bell (\a, or the configured alternative) for audio notifications.
 // Respects sound settings and rate limits to prevent bell spam.
 func (ui *uiConfig) terminalBell() {
 	if !ui.soundEnabled {
@@ -555,7 +622,7 @@ func (ui *uiConfig) terminalBell() {
 		return
 	}
 	ui.lastBellTime = time.Now()
-	fmt.Print("\a")
+	ui.ring()
 }
 
 // bellForEvent triggers a bell for specific notification events.
@@ -564,6 +631,7 @@ func (ui *uiConfig) terminalBell() {
 //   - "alert": bell for hunger/cleanliness warnings
 //   - "achievement": celebratory bell
 //   - "network": mysterious bell during network events
+//   - "break": double bell reminding the user to take a break
 func (ui *uiConfig) bellForEvent(eventType string) {
 	if !ui.soundEnabled {
 		return
@@ -571,34 +639,42 @@ func (ui *uiConfig) bellForEvent(eventType string) {
 
 	switch eventType {
 	case "critical":
-		ui.terminalBell()
+		ui.chime(eventType)
 	case "alert":
 		// Only bell if enough time has passed
 		if time.Since(ui.lastBellTime) >= 5*time.Second {
-			ui.terminalBell()
+			ui.chime(eventType)
 		}
 	case "achievement":
-		ui.terminalBell()
+		ui.chime(eventType)
 	case "network":
 		// Mysterious timing - only sometimes
 		if rand.Intn(100) < 30 {
-			ui.terminalBell()
+			ui.chime(eventType)
 		}
+	case "break":
+		if ui.sounds.play(eventType, time.Now()) {
+			return
+		}
+		// Two bells so a focused user notices
+		ui.ring()
+		time.Sleep(200 * time.Millisecond)
+		ui.terminalBell()
 	}
 }
 
-// morseCode contains International Morse Code mappings
-var morseCode = map[rune]string{
-	'A': ".-", 'B': "-...", 'C': "-.-.", 'D': "-..", 'E': ".",
-	'F': "..-.", 'G': "--.", 'H': "....", 'I': "..", 'J': ".---",
-	'K': "-.-", 'L': ".-..", 'M': "--", 'N': "-.", 'O': "---",
-	'P': ".--.", 'Q': "--.-", 'R': ".-.", 'S': "...", 'T': "-",
-	'U': "..-", 'V': "...-", 'W': ".--", 'X': "-..-", 'Y': "-.--",
-	'Z': "--..", '0': "-----", '1': ".----", '2': "..---", '3': "...--",
-	'4': "....-", '5': ".....", '6': "-....", '7': "--...", '8': "---..",
-	'9': "----.", ' ': " ",
+// chime plays the sound routed to an event, or rings the bell if there
+// isn't one
+func (ui *uiConfig) chime(eventType string) {
+	if ui.sounds.play(eventType, time.Now()) {
+		return
+	}
+	ui.terminalBell()
 }
 
+// morseCode contains International Morse Code mappings, shared with the ARG
+var morseCode = arg.MorseCode
+
 // hiddenMorseMessages contains cryptic messages embedded in notification timing
 var hiddenMorseMessages = []string{
 	"HELLO",
@@ -650,11 +726,11 @@ func (ui *uiConfig) playMorseChar(code string) {
 	for _, symbol := range code {
 		switch symbol {
 		case '.':
-			fmt.Print("\a")
+			ui.ring()
 			ui.recordMorseEvent(true)
 			time.Sleep(dotDuration)
 		case '-':
-			fmt.Print("\a")
+			ui.ring()
 			ui.recordMorseEvent(false)
 			time.Sleep(dashDuration)
 		case ' ':
@@ -744,6 +820,7 @@ const (
 	SoundAchievement
 	SoundNetwork
 	SoundMorse
+	SoundBreak
 )
 
 // playNotificationSound plays the appropriate sound for a notification
@@ -765,6 +842,8 @@ func (ui *uiConfig) playNotificationSound(sound NotificationSound, petName strin
 		if petNetwork != nil {
 			ui.maybeMorseMessage()
 		}
+	case SoundBreak:
+		ui.bellForEvent("break")
 	case SoundMorse:
 		// Play pet name in morse as an easter egg
 		go func() {
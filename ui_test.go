@@ -339,6 +339,7 @@ func TestNotificationSoundConstants(t *testing.T) {
 		SoundAchievement,
 		SoundNetwork,
 		SoundMorse,
+		SoundBreak,
 	}
 
 	seen := make(map[NotificationSound]bool)
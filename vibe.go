@@ -0,0 +1,128 @@
+package main
+
+import (
+	"bufio"
+	"fmt"
+	"math"
+	"strings"
+	"time"
+)
+
+const (
+	// vibeBarWidth is how many cells the marker swings across
+	vibeBarWidth = 21
+
+	// vibeSwing is how long the marker takes to cross the bar and back
+	vibeSwing = 1600 * time.Millisecond
+
+	// vibeFrame is how often the bar is redrawn
+	vibeFrame = 40 * time.Millisecond
+
+	// vibePass is the lowest score that passes
+	vibePass = 50
+
+	// vibeTrendWeight is how much a happy or sad trend scales happiness
+	// decay, at its extremes
+	vibeTrendWeight = 0.25
+)
+
+// vibeMarker is where the marker is after some time, from 0 (left edge) to
+// 1 (right edge), swinging back and forth
+func vibeMarker(elapsed time.Duration) float64 {
+	phase := math.Mod(float64(elapsed)/float64(vibeSwing), 1)
+	return 1 - math.Abs(2*phase-1)
+}
+
+// vibeTiming is how close to the center the marker was: 1 dead center, 0
+// at either edge
+func vibeTiming(elapsed time.Duration) float64 {
+	return 1 - math.Abs(2*vibeMarker(elapsed)-1)
+}
+
+// vibeBar draws the bar with the marker where it is now
+func vibeBar(elapsed time.Duration) string {
+	cells := []byte(strings.Repeat("-", vibeBarWidth))
+	cells[vibeBarWidth/2] = '|'
+	cells[int(math.Round(vibeMarker(elapsed)*(vibeBarWidth-1)))] = 'O'
+	return "[" + string(cells) + "]"
+}
+
+// vibeScore weighs your timing against the cosmos: timing is most of it,
+// but the stars get a say
+func vibeScore(timing float64, alignment int) int {
+	return clamp(int(timing*80)+alignment/5, 0, 100)
+}
+
+// vibeDecay scales happiness decay by the vibe trend: good vibes make a
+// good mood last longer
+func (p *Pet) vibeDecay() float64 {
+	if p.Absurd == nil {
+		return 1
+	}
+	return 1 - vibeTrendWeight*float64(p.Absurd.MysteryStats.VibeTrend)/100
+}
+
+// describeVibeTrend puts the trend into words
+func describeVibeTrend(trend int) string {
+	switch {
+	case trend >= 30:
+		return "📈 The vibes are trending up. Its good moods last longer."
+	case trend <= -30:
+		return "📉 The vibes are trending down. Its good moods wear off faster."
+	}
+	return "〰️ The vibes are holding steady."
+}
+
+// runVibeCheck swings a marker across a bar until you press Enter, then
+// checks the vibes on how close to the center you stopped it
+func runVibeCheck(pet *Pet, reader *bufio.Reader, session *petSession, ui *uiConfig) string {
+	a := pet.Absurd
+	if a == nil {
+		return "Vibe check: inconclusive."
+	}
+
+	fmt.Println(ui.text("\n✨ VIBE CHECK ✨"))
+	if ui.reducedMotion {
+		fmt.Println("   The marker swings where you can't see it. Press Enter when it feels centered.")
+	} else {
+		fmt.Println("   Press Enter when the O is on the |.")
+	}
+
+	var elapsed time.Duration
+	session.Wait(func() { elapsed = swingVibeBar(reader, ui) })
+	timing := vibeTiming(elapsed)
+	passed, message := a.PerformVibeCheck(timing)
+	result := fmt.Sprintf("%s (timing %d%%, cosmos %d%%)\n%s",
+		message, int(timing*100), a.MysteryStats.CosmicAlignment, describeVibeTrend(a.MysteryStats.VibeTrend))
+	if passed {
+		return "✅ " + result
+	}
+	return "❌ " + result
+}
+
+// swingVibeBar swings the marker until the player presses Enter, and
+// returns how long that took
+func swingVibeBar(reader *bufio.Reader, ui *uiConfig) time.Duration {
+	input := make(chan time.Duration, 1)
+	start := time.Now()
+	go func() {
+		reader.ReadString('\n')
+		input <- time.Since(start)
+	}()
+
+	ticker := time.NewTicker(vibeFrame)
+	defer ticker.Stop()
+	for {
+		select {
+		case elapsed := <-input:
+			if !ui.reducedMotion {
+				fmt.Printf("\r   %s\n", vibeBar(elapsed))
+			}
+			return elapsed
+		case <-ticker.C:
+			if !ui.reducedMotion {
+				fmt.Printf("\r   %s", vibeBar(time.Since(start)))
+			}
+		}
+	}
+}
@@ -0,0 +1,84 @@
+package main
+
+import (
+	"bufio"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestVibeTiming(t *testing.T) {
+	tests := []struct {
+		elapsed time.Duration
+		marker  float64
+		timing  float64
+		bar     string
+	}{
+		{0, 0, 0, "[O---------|----------]"},
+		{vibeSwing / 4, 0.5, 1, "[----------O----------]"},
+		{vibeSwing / 2, 1, 0, "[----------|---------O]"},
+		{vibeSwing * 3 / 4, 0.5, 1, "[----------O----------]"},
+		{vibeSwing * 5 / 8, 0.75, 0.5, "[----------|----O-----]"},
+	}
+	for _, tt := range tests {
+		if got := vibeMarker(tt.elapsed); got < tt.marker-0.001 || got > tt.marker+0.001 {
+			t.Errorf("Expected the marker at %.2f after %s, got %.2f", tt.marker, tt.elapsed, got)
+		}
+		if got := vibeTiming(tt.elapsed); got < tt.timing-0.001 || got > tt.timing+0.001 {
+			t.Errorf("Expected timing %.2f after %s, got %.2f", tt.timing, tt.elapsed, got)
+		}
+		if got := vibeBar(tt.elapsed); got != tt.bar {
+			t.Errorf("Expected %s after %s, got %s", tt.bar, tt.elapsed, got)
+		}
+	}
+}
+
+func TestVibeScore(t *testing.T) {
+	tests := []struct {
+		timing    float64
+		alignment int
+		want      int
+	}{
+		{1, 99, 99},
+		{1, 0, 80},
+		{0.5, 50, 50},
+		{0, 99, 19},
+	}
+	for _, tt := range tests {
+		if got := vibeScore(tt.timing, tt.alignment); got != tt.want {
+			t.Errorf("Expected %d for timing %.1f and alignment %d, got %d", tt.want, tt.timing, tt.alignment, got)
+		}
+	}
+}
+
+func TestVibeDecay(t *testing.T) {
+	tests := []struct {
+		trend int
+		want  float64
+	}{
+		{0, 1},
+		{100, 0.75},
+		{-100, 1.25},
+	}
+	for _, tt := range tests {
+		pet := NewPet("Vibes")
+		pet.Absurd.MysteryStats.VibeTrend = tt.trend
+		if got := pet.vibeDecay(); got != tt.want {
+			t.Errorf("Expected decay %.2f for trend %d, got %.2f", tt.want, tt.trend, got)
+		}
+	}
+}
+
+func TestRunVibeCheck(t *testing.T) {
+	ui := newUIConfig()
+	ui.reducedMotion = true
+	pet := NewPet("Vibes")
+
+	got := runVibeCheck(pet, bufio.NewReader(strings.NewReader("\n")), nil, ui)
+	if !strings.Contains(got, "timing") || !strings.Contains(got, "vibes") {
+		t.Errorf("Expected a scored vibe check with the trend, got %s", got)
+	}
+	if pet.Absurd.MysteryStats.LastVibeCheck.IsZero() {
+		t.Error("Expected the vibe check to be recorded")
+	}
+}
@@ -0,0 +1,229 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"os/exec"
+	"os/signal"
+	"strings"
+	"time"
+)
+
+const (
+	// watchFrameInterval is how often the watched pet is redrawn
+	watchFrameInterval = time.Second
+
+	// watchReloadInterval is how often the watched save is fetched again
+	watchReloadInterval = 5 * time.Second
+
+	// watchTimeout bounds a single fetch from a remote pet
+	watchTimeout = 10 * time.Second
+
+	// maxWatchedSave is the largest save we'll read from anywhere
+	maxWatchedSave = 8 << 20
+)
+
+// watchSource is somewhere a save file can be read from
+type watchSource interface {
+	Describe() string
+	Read() ([]byte, error)
+}
+
+// fileWatch reads a save on this machine
+type fileWatch struct {
+	path string
+}
+
+func (f *fileWatch) Describe() string { return f.path }
+
+func (f *fileWatch) Read() ([]byte, error) {
+	data, _, err := readSave(f.path)
+	return data, err
+}
+
+// httpWatch reads a save served over HTTP(S)
+type httpWatch struct {
+	url    string
+	client *http.Client
+}
+
+func (h *httpWatch) Describe() string { return h.url }
+
+func (h *httpWatch) Read() ([]byte, error) {
+	resp, err := h.client.Get(h.url)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("%s", resp.Status)
+	}
+	return io.ReadAll(io.LimitReader(resp.Body, maxWatchedSave))
+}
+
+// sshWatch reads a save on another machine by running cat over ssh
+type sshWatch struct {
+	host string
+	path string
+	run  func(name string, args ...string) ([]byte, error)
+}
+
+func (s *sshWatch) Describe() string { return s.host + ":" + s.path }
+
+func (s *sshWatch) Read() ([]byte, error) {
+	// BatchMode fails fast instead of prompting for a password mid-render
+	return s.run("ssh", "-o", "BatchMode=yes", "-o", "ConnectTimeout=10", s.host, "cat -- "+shellQuote(s.path))
+}
+
+// runOutput runs a command and returns its standard output
+func runOutput(name string, args ...string) ([]byte, error) {
+	out, err := exec.Command(name, args...).Output()
+	if exitErr, ok := err.(*exec.ExitError); ok && len(exitErr.Stderr) > 0 {
+		return nil, fmt.Errorf("%s", strings.TrimSpace(string(exitErr.Stderr)))
+	}
+	return out, err
+}
+
+// parseWatchTarget works out where a watched pet lives: a local save file,
+// an http(s):// URL, or another machine as ssh://host/path or host:path
+func parseWatchTarget(target string) (watchSource, error) {
+	if target == "" {
+		return &fileWatch{path: saveFile}, nil
+	}
+
+	if strings.Contains(target, "://") {
+		u, err := url.Parse(target)
+		if err != nil || u.Host == "" {
+			return nil, fmt.Errorf("can't watch %q: not a valid URL", target)
+		}
+		switch u.Scheme {
+		case "http", "https":
+			return &httpWatch{url: target, client: &http.Client{Timeout: watchTimeout}}, nil
+		case "ssh":
+			host := u.Host
+			if u.User != nil {
+				host = u.User.Username() + "@" + host
+			}
+			path := u.Path
+			if path == "" || path == "/" {
+				path = saveFile
+			}
+			return &sshWatch{host: host, path: path, run: runOutput}, nil
+		}
+		return nil, fmt.Errorf("can't watch %q: use a file, http(s)://, or ssh://", target)
+	}
+
+	// scp-style host:path, unless it's a local file or a Windows drive letter
+	if host, path, ok := strings.Cut(target, ":"); ok && len(host) > 1 && !strings.ContainsAny(host, `/\`) {
+		if _, err := os.Stat(target); err != nil {
+			if path == "" {
+				path = saveFile
+			}
+			return &sshWatch{host: host, path: path, run: runOutput}, nil
+		}
+	}
+	return &fileWatch{path: target}, nil
+}
+
+// watchSession is a read-only view of someone else's pet. The copy held here
+// has no save path, so nothing done to it can reach the owner's file.
+type watchSession struct {
+	source    watchSource
+	pet       *Pet
+	fetchedAt time.Time
+	err       error // Last fetch failure; the last good pet stays on screen
+}
+
+// refresh fetches the save again, keeping the last good pet on failure
+func (w *watchSession) refresh(now time.Time) error {
+	data, err := w.source.Read()
+	if err == nil {
+		var pet *Pet
+		if pet, err = decodePet(data, ""); err == nil {
+			w.pet, w.fetchedAt = pet, now
+		}
+	}
+	w.err = err
+	return err
+}
+
+// renderWatch draws the watched pet under a banner saying whose it is
+func (w *watchSession) renderWatch(ui *uiConfig, now time.Time) string {
+	var b strings.Builder
+	banner := fmt.Sprintf("👀 Watching %s (read-only) • %s • updated %s ago",
+		truncateWidth(w.pet.Name, maxPanelNameWidth), w.source.Describe(), formatDuration(now.Sub(w.fetchedAt)))
+	b.WriteString(ui.paletteText(ui.text(banner), ui.palette.faint))
+	b.WriteString("\n")
+	if w.err != nil {
+		b.WriteString(ui.paletteText(ui.text(fmt.Sprintf("⚠️  Lost sight of the pet: %v", w.err)), ui.palette.warn))
+		b.WriteString("\n")
+	}
+	b.WriteString(ui.text(renderScene(w.pet, ui)))
+	b.WriteString("\n")
+	b.WriteString(ui.paletteText("press any key to leave", ui.palette.faint))
+	b.WriteString("\n")
+	return b.String()
+}
+
+// runWatch shows another pet live until a key is pressed. Commands aren't
+// accepted and nothing is ever written, so a partner can keep an eye on a
+// shared pet without two games saving over each other.
+func runWatch(args []string) error {
+	target := ""
+	if len(args) > 0 {
+		target = args[0]
+	}
+	source, err := parseWatchTarget(target)
+	if err != nil {
+		return err
+	}
+	w := &watchSession{source: source}
+	if err := w.refresh(time.Now()); err != nil {
+		return fmt.Errorf("no pet to watch at %s: %w", source.Describe(), err)
+	}
+
+	ui := newUIConfig()
+	ui.ttsEnabled = false // The owner's terminal does the talking
+	restore := enableKeypressMode()
+	defer restore()
+
+	keys := make(chan struct{}, 1)
+	go func() {
+		buf := make([]byte, 1)
+		os.Stdin.Read(buf)
+		keys <- struct{}{}
+	}()
+
+	interrupts := make(chan os.Signal, 1)
+	signal.Notify(interrupts, os.Interrupt)
+	defer signal.Stop(interrupts)
+
+	ticker := time.NewTicker(watchFrameInterval)
+	defer ticker.Stop()
+
+	fmt.Print("\033[?25l")       // Hide cursor
+	defer fmt.Print("\033[?25h") // Show cursor
+
+	lastReload := time.Now()
+	for {
+		if time.Since(lastReload) >= watchReloadInterval {
+			w.refresh(time.Now()) // Failures show in the banner and retry next time
+			lastReload = time.Now()
+		}
+
+		fmt.Print("\033[H\033[2J")
+		fmt.Print(w.renderWatch(ui, time.Now()))
+
+		select {
+		case <-keys:
+			fmt.Print("\033[H\033[2J")
+			return nil
+		case <-interrupts:
+			return nil
+		case <-ticker.C:
+		}
+	}
+}
@@ -0,0 +1,129 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestParseWatchTarget(t *testing.T) {
+	tests := []struct {
+		name     string
+		target   string
+		wantErr  bool
+		describe string
+	}{
+		{"own save", "", false, saveFile},
+		{"local file", "shared/tamagotchi_save.json", false, "shared/tamagotchi_save.json"},
+		{"windows path", `C:\pets\save.json`, false, `C:\pets\save.json`},
+		{"https", "https://pets.example/save.json", false, "https://pets.example/save.json"},
+		{"ssh url", "ssh://me@laptop/home/me/tamagotchi_save.json", false, "me@laptop:/home/me/tamagotchi_save.json"},
+		{"ssh url without path", "ssh://laptop", false, "laptop:" + saveFile},
+		{"scp style", "me@laptop:pets/save.json", false, "me@laptop:pets/save.json"},
+		{"unknown scheme", "ftp://pets.example/save.json", true, ""},
+		{"no host", "https://", true, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			source, err := parseWatchTarget(tt.target)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Expected error %v, got %v", tt.wantErr, err)
+			}
+			if source != nil && source.Describe() != tt.describe {
+				t.Errorf("Expected source %q, got %q", tt.describe, source.Describe())
+			}
+		})
+	}
+}
+
+func TestWatchFileIsReadOnly(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "tamagotchi_save.json")
+	pet := NewPet("Shared")
+	pet.Stage = Adult
+	pet.SaveFilePath = path
+	if err := pet.Save(); err != nil {
+		t.Fatalf("Expected save to succeed, got %v", err)
+	}
+	before, _ := os.ReadFile(path)
+
+	w := &watchSession{source: &fileWatch{path: path}}
+	now := time.Now()
+	if err := w.refresh(now); err != nil {
+		t.Fatalf("Expected refresh to succeed, got %v", err)
+	}
+	if w.pet.Name != "Shared" || w.pet.SaveFilePath != "" {
+		t.Errorf("Expected a detached copy of Shared, got %q at %q", w.pet.Name, w.pet.SaveFilePath)
+	}
+
+	ui := newUIConfig()
+	ui.colorEnabled = false
+	out := w.renderWatch(ui, now)
+	if !strings.Contains(out, "Watching Shared (read-only)") {
+		t.Errorf("Expected read-only banner, got %q", out)
+	}
+
+	after, _ := os.ReadFile(path)
+	if !bytes.Equal(before, after) {
+		t.Errorf("Expected watching to leave the save untouched")
+	}
+}
+
+func TestWatchKeepsLastPetOnFailure(t *testing.T) {
+	data, err := json.Marshal(NewPet("Remote"))
+	if err != nil {
+		t.Fatalf("Expected pet to marshal, got %v", err)
+	}
+
+	fail := false
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if fail {
+			http.Error(w, "gone", http.StatusServiceUnavailable)
+			return
+		}
+		w.Write(data)
+	}))
+	defer server.Close()
+
+	w := &watchSession{source: &httpWatch{url: server.URL, client: server.Client()}}
+	if err := w.refresh(time.Now()); err != nil {
+		t.Fatalf("Expected refresh to succeed, got %v", err)
+	}
+
+	fail = true
+	if err := w.refresh(time.Now()); err == nil {
+		t.Errorf("Expected a failed fetch to report an error")
+	}
+	if w.pet == nil || w.pet.Name != "Remote" {
+		t.Fatalf("Expected the last good pet to stay on screen")
+	}
+
+	ui := newUIConfig()
+	ui.colorEnabled = false
+	if out := w.renderWatch(ui, time.Now()); !strings.Contains(out, "Lost sight of the pet") {
+		t.Errorf("Expected the failure in the banner, got %q", out)
+	}
+}
+
+func TestSSHWatchRunsCat(t *testing.T) {
+	var got []string
+	source := &sshWatch{host: "me@laptop", path: "it's/save.json", run: func(name string, args ...string) ([]byte, error) {
+		got = append([]string{name}, args...)
+		return []byte("{}"), nil
+	}}
+	if _, err := source.Read(); err != nil {
+		t.Fatalf("Expected read to succeed, got %v", err)
+	}
+	if got[0] != "ssh" || got[len(got)-2] != "me@laptop" {
+		t.Errorf("Expected ssh to me@laptop, got %v", got)
+	}
+	if want := "cat -- " + shellQuote("it's/save.json"); got[len(got)-1] != want {
+		t.Errorf("Expected remote command %q, got %q", want, got[len(got)-1])
+	}
+}
@@ -0,0 +1,133 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	// weatherRefreshInterval is how often the real weather is fetched again
+	weatherRefreshInterval = 30 * time.Minute
+
+	// weatherRetryDelay spaces out fetches after a failure
+	weatherRetryDelay = 10 * time.Minute
+
+	// weatherTimeout bounds a single fetch
+	weatherTimeout = 10 * time.Second
+
+	// weatherStale is how old a report can get before the window stops
+	// trusting it
+	weatherStale = 3 * time.Hour
+
+	// maxWeatherReport is the longest report we'll read; a condition is a
+	// few words
+	maxWeatherReport = 1 << 10
+)
+
+// weatherFeed fetches the real weather for the window, as plain text such
+// as wttr.in's ?format=%C
+type weatherFeed struct {
+	url       string
+	client    *http.Client
+	mutex     sync.Mutex
+	lastTry   time.Time
+	fetchedAt time.Time
+	condition string // A scene weather word: clear, rain, snow, fog, or clouds
+}
+
+// newWeatherFeed reads the weather settings, returning nil when no feed is
+// set. Only HTTPS is accepted.
+func newWeatherFeed(getenv func(string) string) (*weatherFeed, error) {
+	raw := getenv("TAMAGOTCHI_WEATHER_URL")
+	if raw == "" || classroomMode {
+		return nil, nil
+	}
+	u, err := url.Parse(raw)
+	if err != nil || u.Scheme != "https" || u.Host == "" {
+		return nil, fmt.Errorf("TAMAGOTCHI_WEATHER_URL must be an https:// URL, got %q", raw)
+	}
+	return &weatherFeed{url: raw, client: &http.Client{Timeout: weatherTimeout}}, nil
+}
+
+// weatherWord turns a weather report into a scene weather word, or "" when
+// it can't tell
+func weatherWord(report string) string {
+	report = strings.ToLower(report)
+	kinds := []struct {
+		word  string
+		signs []string
+	}{
+		{"snow", []string{"snow", "sleet", "blizzard", "ice"}},
+		{"rain", []string{"rain", "drizzle", "shower", "thunder"}},
+		{"fog", []string{"fog", "mist", "haze"}},
+		{"clouds", []string{"cloud", "overcast"}},
+		{"clear", []string{"clear", "sunny", "fair"}},
+	}
+	for _, kind := range kinds {
+		for _, sign := range kind.signs {
+			if strings.Contains(report, sign) {
+				return kind.word
+			}
+		}
+	}
+	return ""
+}
+
+// Due reports whether the weather is stale and we haven't just failed
+func (f *weatherFeed) Due(now time.Time) bool {
+	if f == nil {
+		return false
+	}
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+	return now.Sub(f.fetchedAt) >= weatherRefreshInterval && now.Sub(f.lastTry) >= weatherRetryDelay
+}
+
+// Refresh fetches the weather report and keeps the condition
+func (f *weatherFeed) Refresh(now time.Time) error {
+	f.mutex.Lock()
+	f.lastTry = now
+	f.mutex.Unlock()
+
+	resp, err := f.client.Get(f.url)
+	if err != nil {
+		return fmt.Errorf("failed to fetch weather: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("failed to fetch weather: %s", resp.Status)
+	}
+	data, err := io.ReadAll(io.LimitReader(resp.Body, maxWeatherReport))
+	if err != nil {
+		return fmt.Errorf("failed to read weather: %w", err)
+	}
+	word := weatherWord(string(data))
+	if word == "" {
+		return fmt.Errorf("unrecognised weather %q", strings.TrimSpace(string(data)))
+	}
+
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+	f.condition = word
+	f.fetchedAt = now
+	return nil
+}
+
+// Condition is the real weather, or "" when there's no feed or the last
+// report is too old to trust
+func (f *weatherFeed) Condition(now time.Time) string {
+	if f == nil {
+		return ""
+	}
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+	if now.Sub(f.fetchedAt) > weatherStale {
+		return ""
+	}
+	return f.condition
+}
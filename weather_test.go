@@ -0,0 +1,74 @@
+package main
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestWeatherWord(t *testing.T) {
+	tests := []struct {
+		report string
+		want   string
+	}{
+		{"Sunny", "clear"},
+		{"Light rain shower", "rain"},
+		{"Patchy light snow\n", "snow"},
+		{"Mist", "fog"},
+		{"Partly cloudy", "clouds"},
+		{"Thundery outbreaks possible", "rain"},
+		{"Locusts", ""},
+	}
+	for _, tt := range tests {
+		if got := weatherWord(tt.report); got != tt.want {
+			t.Errorf("%q: Expected %q, got %q", tt.report, tt.want, got)
+		}
+	}
+}
+
+func TestNewWeatherFeed(t *testing.T) {
+	tests := []struct {
+		url     string
+		wantNil bool
+		wantErr bool
+	}{
+		{"", true, false},
+		{"https://wttr.in/Paris?format=%C", false, false},
+		{"http://wttr.in/Paris", true, true},
+	}
+	for _, tt := range tests {
+		feed, err := newWeatherFeed(func(string) string { return tt.url })
+		if (feed == nil) != tt.wantNil || (err != nil) != tt.wantErr {
+			t.Errorf("%q: Expected nil %v and error %v, got %v and %v", tt.url, tt.wantNil, tt.wantErr, feed, err)
+		}
+	}
+}
+
+func TestWeatherRefresh(t *testing.T) {
+	report := "Light drizzle"
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(report))
+	}))
+	defer server.Close()
+	feed := &weatherFeed{url: server.URL, client: server.Client()}
+	now := time.Now()
+
+	if !feed.Due(now) || feed.Condition(now) != "" {
+		t.Fatalf("Expected a new feed to be due and empty")
+	}
+	if err := feed.Refresh(now); err != nil {
+		t.Fatalf("Expected the fetch to succeed, got %v", err)
+	}
+	if got := feed.Condition(now); got != "rain" || feed.Due(now.Add(time.Minute)) {
+		t.Errorf("Expected rain and no refetch yet, got %q", got)
+	}
+	if got := feed.Condition(now.Add(weatherStale + time.Minute)); got != "" {
+		t.Errorf("Expected an old report to be ignored, got %q", got)
+	}
+
+	report = "Locusts"
+	if err := feed.Refresh(now.Add(time.Hour)); err == nil || feed.Condition(now.Add(time.Hour)) != "rain" {
+		t.Errorf("Expected an unknown report rejected and the last one kept, got %v", err)
+	}
+}
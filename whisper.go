@@ -0,0 +1,104 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+	"time"
+	"unicode"
+
+	"github.com/tamagotchi/mooc"
+)
+
+// maxWhisperLength keeps whispers to a line
+const maxWhisperLength = 140
+
+// plainText drops anything from the mesh that could move the cursor or
+// recolor the terminal
+func plainText(s string) string {
+	return strings.Map(func(r rune) rune {
+		if unicode.IsControl(r) {
+			return -1
+		}
+		return r
+	}, s)
+}
+
+// findStar picks a known pet by name, preferring the living
+func findStar(stars []mooc.Star, name string) (mooc.Star, bool) {
+	var found *mooc.Star
+	for i := range stars {
+		if !strings.EqualFold(stars[i].DisplayName, name) {
+			continue
+		}
+		if found == nil || (found.IsDeceased && !stars[i].IsDeceased) {
+			found = &stars[i]
+		}
+	}
+	if found == nil {
+		return mooc.Star{}, false
+	}
+	return *found, true
+}
+
+// whisperTo sends a whisper to a known pet. If the pet is offline it's held
+// for a day and a copy goes to a friend who has met it.
+func whisperTo(args string, stars []mooc.Star, send func(petID, name, text string) (mooc.Delivery, error)) string {
+	name, text, _ := strings.Cut(strings.TrimSpace(args), " ")
+	text = strings.TrimSpace(plainText(text))
+	if name == "" || text == "" {
+		return "❓ Usage: whisper <friend> <words>"
+	}
+	if len([]rune(text)) > maxWhisperLength {
+		return fmt.Sprintf("🤫 That's more than a whisper. Keep it under %d characters.", maxWhisperLength)
+	}
+	star, ok := findStar(stars, name)
+	switch {
+	case !ok:
+		return fmt.Sprintf("🤷 Your pet hasn't met anyone called %s. (constellation lists who it has)", name)
+	case star.IsDeceased:
+		return fmt.Sprintf("🕯️ %s is gone. Your pet whispers it anyway, to nobody.", star.DisplayName)
+	}
+
+	delivery, err := send(star.PetID, star.DisplayName, text)
+	if err != nil {
+		return fmt.Sprintf("❌ Couldn't whisper: %v", err)
+	}
+	switch delivery {
+	case mooc.Delivered:
+		return fmt.Sprintf("💌 Whispered to %s.", star.DisplayName)
+	case mooc.Queued:
+		return fmt.Sprintf("📮 %s is away. Your pet will hold the whisper for a day, and hands a copy to a friend who might see %s first.", star.DisplayName, star.DisplayName)
+	}
+	return "📪 Too many whispers are already waiting to be delivered. Try again later."
+}
+
+// runWhisperCommand whispers to a pet on the mesh
+func runWhisperCommand(args string) string {
+	if petNetwork == nil || !petNetwork.IsEnabled() {
+		return "🔇 Your pet isn't on the mesh, so nobody can hear it."
+	}
+	return whisperTo(args, petNetwork.Stars(), petNetwork.Whisper)
+}
+
+// whisperReactions are what the pet makes of whispers that arrived
+func whisperReactions(pet *Pet, whispers []mooc.Whisper, now time.Time) []string {
+	var reactions []string
+	for _, w := range whispers {
+		from, text := plainText(w.From), plainText(w.Text)
+		line := fmt.Sprintf("💌 %s whispers: \"%s\"", from, text)
+		if w.Courier != "" {
+			line = fmt.Sprintf("💌 %s brings a whisper from %s, %s ago: \"%s\"", plainText(w.Courier), from, formatDuration(now.Sub(w.Sent).Truncate(time.Minute)), text)
+		}
+		reactions = append(reactions, line)
+		pet.Happiness = clamp(pet.Happiness+5, 0, 100)
+	}
+	return reactions
+}
+
+// applyWhispers reads the whispers heard since the last turn
+func applyWhispers(pet *Pet, now time.Time) []string {
+	if petNetwork == nil {
+		return nil
+	}
+	return whisperReactions(pet, petNetwork.TakeWhispers(), now)
+}
@@ -0,0 +1,76 @@
+package main
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/tamagotchi/mooc"
+)
+
+func TestWhisperTo(t *testing.T) {
+	stars := []mooc.Star{
+		{PetID: "a1", DisplayName: "Mochi", Online: true},
+		{PetID: "b2", DisplayName: "Pixel", IsDeceased: true},
+		{PetID: "c3", DisplayName: "Bean"},
+		{PetID: "d4", DisplayName: "Bean", IsDeceased: true},
+	}
+	tests := []struct {
+		name     string
+		args     string
+		delivery mooc.Delivery
+		err      error
+		wantTo   string
+		want     string
+	}{
+		{"online", "mochi see you soon", mooc.Delivered, nil, "a1", "Whispered to Mochi"},
+		{"away", "Bean  come back", mooc.Queued, nil, "c3", "Bean is away"},
+		{"outbox full", "Bean hello", mooc.Refused, nil, "c3", "Too many whispers"},
+		{"gone", "Pixel rest well", mooc.Delivered, nil, "", "Pixel is gone"},
+		{"a stranger", "Nibbles hi", mooc.Delivered, nil, "", "hasn't met anyone called Nibbles"},
+		{"no words", "Mochi", mooc.Delivered, nil, "", "Usage"},
+		{"only control characters", "Mochi \x1b\x07", mooc.Delivered, nil, "", "Usage"},
+		{"too long", "Mochi " + strings.Repeat("a", maxWhisperLength+1), mooc.Delivered, nil, "", "more than a whisper"},
+		{"network trouble", "Mochi hi", mooc.Refused, errors.New("the network isn't running"), "a1", "Couldn't whisper"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sentTo := ""
+			send := func(petID, name, text string) (mooc.Delivery, error) {
+				sentTo = petID
+				return tt.delivery, tt.err
+			}
+			if got := whisperTo(tt.args, stars, send); !strings.Contains(got, tt.want) {
+				t.Errorf("Expected %q, got %q", tt.want, got)
+			}
+			if sentTo != tt.wantTo {
+				t.Errorf("Expected the whisper sent to %q, got %q", tt.wantTo, sentTo)
+			}
+		})
+	}
+}
+
+func TestWhisperReactions(t *testing.T) {
+	now := time.Date(2026, 10, 16, 12, 0, 0, 0, time.UTC)
+	pet := NewPet("Tama")
+	pet.Happiness = 50
+	whispers := []mooc.Whisper{
+		{From: "Mochi", Text: "hello", Sent: now},
+		{From: "Bean", Courier: "Pixel", Text: "miss you\x1b[2J", Sent: now.Add(-3 * time.Hour)},
+	}
+
+	got := whisperReactions(pet, whispers, now)
+	if len(got) != 2 {
+		t.Fatalf("Expected a reaction per whisper, got %v", got)
+	}
+	if got[0] != `💌 Mochi whispers: "hello"` {
+		t.Errorf("Expected a plain whisper, got %q", got[0])
+	}
+	if !strings.Contains(got[1], "Pixel brings a whisper from Bean, 3h") || strings.Contains(got[1], "\x1b") {
+		t.Errorf("Expected the courier named and the escape dropped, got %q", got[1])
+	}
+	if pet.Happiness != 60 {
+		t.Errorf("Expected whispers to cheer the pet up, got %d", pet.Happiness)
+	}
+}